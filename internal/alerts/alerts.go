@@ -0,0 +1,207 @@
+// Package alerts turns the live container stats into alerts: rules such as
+// "CPU above 80% for a minute" are checked on every stats sample, and the
+// containers breaking one are reported once when the alert starts.
+package alerts
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EnvVar holds the alert rules, e.g. "cpu>80:1m,mem>90,db=mem>75"
+const EnvVar = "TINYD_ALERTS"
+
+// NotifyEnvVar enables desktop notifications for alerts when set to "1"
+const NotifyEnvVar = "TINYD_ALERT_NOTIFY"
+
+// Metric is the resource a rule watches
+type Metric int
+
+const (
+	CPU    Metric = iota // CPU usage in percent of one core
+	Memory               // Memory usage in percent of the container's limit
+)
+
+// String returns the metric's display name
+func (m Metric) String() string {
+	if m == Memory {
+		return "MEM"
+	}
+	return "CPU"
+}
+
+// Rule fires when a metric stays above Threshold for at least For. A rule
+// with a Container name applies to that container only and replaces the
+// global rule of the same metric for it.
+type Rule struct {
+	Container string
+	Metric    Metric
+	Threshold float64
+	For       time.Duration
+}
+
+// ParseRules reads a comma-separated list of rules of the form
+// [container=]metric>threshold[:duration], where metric is cpu or mem, the
+// threshold is a percentage and the duration (e.g. 30s, 1m) defaults to 0,
+// alerting on the first sample above the threshold.
+func ParseRules(s string) ([]Rule, error) {
+	var rules []Rule
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		var rule Rule
+		spec := field
+		if name, rest, ok := strings.Cut(field, "="); ok {
+			rule.Container = strings.TrimSpace(name)
+			spec = rest
+		}
+		metric, rest, ok := strings.Cut(spec, ">")
+		if !ok {
+			return nil, fmt.Errorf("invalid alert rule %q: expected metric>threshold", field)
+		}
+		switch strings.ToLower(strings.TrimSpace(metric)) {
+		case "cpu":
+			rule.Metric = CPU
+		case "mem", "memory":
+			rule.Metric = Memory
+		default:
+			return nil, fmt.Errorf("invalid alert rule %q: unknown metric %q", field, metric)
+		}
+
+		threshold, duration, hasDuration := strings.Cut(rest, ":")
+		value, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(threshold), "%"), 64)
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("invalid alert rule %q: bad threshold %q", field, threshold)
+		}
+		rule.Threshold = value
+		if hasDuration {
+			if rule.For, err = time.ParseDuration(strings.TrimSpace(duration)); err != nil || rule.For < 0 {
+				return nil, fmt.Errorf("invalid alert rule %q: bad duration %q", field, duration)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Sample is the usage of one container at one point in time
+type Sample struct {
+	Name       string
+	CPUPercent float64
+	MemPercent float64 // 0 when the memory limit is unknown
+}
+
+// Alert is a rule a container has started breaking
+type Alert struct {
+	ContainerID string
+	Container   string
+	Rule        Rule
+	Value       float64
+}
+
+// String describes the alert, e.g. "web: CPU 93.2% > 80% for 1m0s"
+func (a Alert) String() string {
+	s := fmt.Sprintf("%s: %s %.1f%% > %g%%", a.Container, a.Rule.Metric, a.Value, a.Rule.Threshold)
+	if a.Rule.For > 0 {
+		s += " for " + a.Rule.For.String()
+	}
+	return s
+}
+
+// key identifies one metric of one container
+type key struct {
+	id     string
+	metric Metric
+}
+
+// Monitor tracks how long each container has been above its thresholds
+type Monitor struct {
+	rules  []Rule
+	above  map[key]time.Time // When the metric went above the threshold
+	firing map[key]bool
+}
+
+// NewMonitor creates a monitor for the given rules
+func NewMonitor(rules []Rule) *Monitor {
+	return &Monitor{
+		rules:  rules,
+		above:  make(map[key]time.Time),
+		firing: make(map[key]bool),
+	}
+}
+
+// Enabled reports whether any rule is configured
+func (m *Monitor) Enabled() bool {
+	return len(m.rules) > 0
+}
+
+// Update checks the samples taken at now against the rules and returns the
+// alerts that started with them. Containers without a sample are forgotten,
+// so they alert afresh once sampled again.
+func (m *Monitor) Update(now time.Time, samples map[string]Sample) []Alert {
+	var started []Alert
+	for id, sample := range samples {
+		for _, rule := range m.rulesFor(sample.Name) {
+			k := key{id, rule.Metric}
+			value := sample.CPUPercent
+			if rule.Metric == Memory {
+				value = sample.MemPercent
+			}
+
+			if value <= rule.Threshold {
+				delete(m.above, k)
+				delete(m.firing, k)
+				continue
+			}
+			since, ok := m.above[k]
+			if !ok {
+				since = now
+				m.above[k] = now
+			}
+			if !m.firing[k] && now.Sub(since) >= rule.For {
+				m.firing[k] = true
+				started = append(started, Alert{ContainerID: id, Container: sample.Name, Rule: rule, Value: value})
+			}
+		}
+	}
+
+	for k := range m.above {
+		if _, ok := samples[k.id]; !ok {
+			delete(m.above, k)
+			delete(m.firing, k)
+		}
+	}
+	return started
+}
+
+// Firing reports whether a container is breaking any of its rules
+func (m *Monitor) Firing(containerID string) bool {
+	return m.firing[key{containerID, CPU}] || m.firing[key{containerID, Memory}]
+}
+
+// rulesFor returns the rules that apply to a container: its own rules, plus
+// the global rules of metrics it has no rule for
+func (m *Monitor) rulesFor(name string) []Rule {
+	var own, global []Rule
+	covered := make(map[Metric]bool)
+	for _, rule := range m.rules {
+		switch rule.Container {
+		case name:
+			own = append(own, rule)
+			covered[rule.Metric] = true
+		case "":
+			global = append(global, rule)
+		}
+	}
+	for _, rule := range global {
+		if !covered[rule.Metric] {
+			own = append(own, rule)
+		}
+	}
+	return own
+}
@@ -0,0 +1,66 @@
+package alerts
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules("cpu>80:1m, mem>90%,db=memory>75:30s")
+	if err != nil {
+		t.Fatalf("ParseRules() error = %v", err)
+	}
+	want := []Rule{
+		{Metric: CPU, Threshold: 80, For: time.Minute},
+		{Metric: Memory, Threshold: 90},
+		{Container: "db", Metric: Memory, Threshold: 75, For: 30 * time.Second},
+	}
+	if len(rules) != len(want) {
+		t.Fatalf("ParseRules() = %+v, want %+v", rules, want)
+	}
+	for i := range want {
+		if rules[i] != want[i] {
+			t.Errorf("rule %d = %+v, want %+v", i, rules[i], want[i])
+		}
+	}
+
+	for _, bad := range []string{"cpu", "disk>50", "cpu>high", "cpu>80:soon", "mem>-5"} {
+		if _, err := ParseRules(bad); err == nil {
+			t.Errorf("ParseRules(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestMonitorUpdate(t *testing.T) {
+	m := NewMonitor([]Rule{
+		{Metric: CPU, Threshold: 80, For: time.Minute},
+		{Container: "db", Metric: CPU, Threshold: 50},
+	})
+	start := time.Now()
+
+	samples := map[string]Sample{
+		"web": {Name: "web", CPUPercent: 95},
+		"db":  {Name: "db", CPUPercent: 60},
+	}
+	alerts := m.Update(start, samples)
+	if len(alerts) != 1 || alerts[0].ContainerID != "db" {
+		t.Fatalf("first update alerts = %+v, want only db (its own rule has no duration)", alerts)
+	}
+	if m.Firing("web") {
+		t.Error("web firing before its rule duration elapsed")
+	}
+
+	alerts = m.Update(start.Add(time.Minute), samples)
+	if len(alerts) != 1 || alerts[0].ContainerID != "web" {
+		t.Fatalf("after a minute alerts = %+v, want only web (db already firing)", alerts)
+	}
+	if !m.Firing("web") || !m.Firing("db") {
+		t.Error("want both containers firing")
+	}
+
+	// Dropping below the threshold clears the alert; a missing sample too
+	alerts = m.Update(start.Add(2*time.Minute), map[string]Sample{"web": {Name: "web", CPUPercent: 10}})
+	if len(alerts) != 0 || m.Firing("web") || m.Firing("db") {
+		t.Errorf("after recovery alerts = %+v, firing web=%v db=%v, want none", alerts, m.Firing("web"), m.Firing("db"))
+	}
+}
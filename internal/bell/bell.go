@@ -0,0 +1,91 @@
+// Package bell rings the terminal bell or flashes the screen on events worth
+// looking up for, such as a failed action, so that they aren't missed while
+// the terminal sits in another window.
+package bell
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EnvVar holds the events to signal, e.g. "error,exit=flash,alert"
+const EnvVar = "TINYD_BELL"
+
+// Event is something worth signalling
+type Event int
+
+const (
+	Error Event = iota // An action failed
+	Exit               // A container whose logs are followed stopped
+	Alert              // A usage alert started (TINYD_ALERTS)
+)
+
+// eventNames maps the names used in EnvVar to events
+var eventNames = map[string]Event{
+	"error": Error,
+	"exit":  Exit,
+	"alert": Alert,
+}
+
+// Signal is how an event is signalled
+type Signal int
+
+const (
+	None  Signal = iota
+	Ring         // The terminal bell, which some terminals show visually
+	Flash        // Reverse video for a moment
+)
+
+// Sequences that switch the screen to reverse video and back
+const (
+	FlashOn  = "\x1b[?5h"
+	FlashOff = "\x1b[?5l"
+)
+
+// Config maps events to their signal; events not in it are not signalled
+type Config map[Event]Signal
+
+// Parse reads a comma-separated list of events of the form event[=signal],
+// where event is error, exit, alert or all, and signal is bell (the
+// default) or flash. Later entries win, so "all=flash,error" rings for
+// errors and flashes for the rest.
+func Parse(s string) (Config, error) {
+	config := make(Config)
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		name, mode, _ := strings.Cut(field, "=")
+		signal := Ring
+		switch strings.ToLower(strings.TrimSpace(mode)) {
+		case "", "bell":
+		case "flash":
+			signal = Flash
+		case "off", "none":
+			signal = None
+		default:
+			return nil, fmt.Errorf("invalid bell setting %q: unknown signal %q", field, mode)
+		}
+
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "all" {
+			for _, event := range eventNames {
+				config[event] = signal
+			}
+			continue
+		}
+		event, ok := eventNames[name]
+		if !ok {
+			return nil, fmt.Errorf("invalid bell setting %q: unknown event %q", field, name)
+		}
+		config[event] = signal
+	}
+	return config, nil
+}
+
+// Signal returns how an event is signalled
+func (c Config) Signal(event Event) Signal {
+	return c[event]
+}
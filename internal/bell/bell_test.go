@@ -0,0 +1,32 @@
+package bell
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	config, err := Parse("error, exit=flash")
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if config.Signal(Error) != Ring || config.Signal(Exit) != Flash || config.Signal(Alert) != None {
+		t.Errorf("Parse(error, exit=flash) = %v", config)
+	}
+
+	// Later entries win
+	config, err = Parse("all=flash,error=bell,alert=off")
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if config.Signal(Error) != Ring || config.Signal(Exit) != Flash || config.Signal(Alert) != None {
+		t.Errorf("Parse(all=flash,error=bell,alert=off) = %v", config)
+	}
+
+	if config, err := Parse(""); err != nil || len(config) != 0 {
+		t.Errorf("Parse(\"\") = %v, %v; want empty", config, err)
+	}
+
+	for _, bad := range []string{"errors", "error=beep"} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q) should fail", bad)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// Package cache keeps the last lists fetched from a daemon on disk, so that
+// tinyd can still show them, marked as cached, while the daemon is
+// unreachable instead of only an error.
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"tinyd/internal/types"
+)
+
+// rewriteAfter is how old the file may get before unchanged lists are
+// written again, to keep its timestamp close to the last successful fetch
+const rewriteAfter = time.Minute
+
+// Snapshot is what a daemon last listed
+type Snapshot struct {
+	Host       string
+	Saved      time.Time // Last successful fetch
+	Containers []types.Container
+	Images     []types.Image
+	Volumes    []types.Volume
+	Networks   []types.Network
+}
+
+// Store keeps the snapshot of one daemon, in memory and in a file of the
+// user cache directory. It is safe for concurrent use, as fetches run in
+// their own goroutines.
+type Store struct {
+	path string // "" when there is no cache directory
+
+	mu         sync.Mutex
+	snap       Snapshot
+	written    []byte    // Lists last written, to skip unchanged writes
+	fileSaved  time.Time // Saved time in the file
+	hasEntries bool
+}
+
+// Open returns the store of the daemon at host, with what was kept from a
+// previous run. A missing or unreadable cache just starts empty.
+func Open(host string) *Store {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return &Store{snap: Snapshot{Host: host}}
+	}
+	sum := sha256.Sum256([]byte(host))
+	return openFile(filepath.Join(dir, "tinyd", "lists-"+hex.EncodeToString(sum[:6])+".json"), host)
+}
+
+// openFile returns the store kept in path for host
+func openFile(path, host string) *Store {
+	s := &Store{path: path, snap: Snapshot{Host: host}}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return s
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil || snap.Host != host {
+		return s
+	}
+	s.snap = snap
+	s.fileSaved = snap.Saved
+	s.hasEntries = true
+	return s
+}
+
+// Snapshot returns the last lists, ok is false when none were ever saved
+func (s *Store) Snapshot() (snap Snapshot, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.snap, s.hasEntries
+}
+
+// SaveContainers records a successful fetch of the containers
+func (s *Store) SaveContainers(containers []types.Container) {
+	s.save(func(snap *Snapshot) { snap.Containers = containers })
+}
+
+// SaveImages records a successful fetch of the images
+func (s *Store) SaveImages(images []types.Image) {
+	s.save(func(snap *Snapshot) { snap.Images = images })
+}
+
+// SaveVolumes records a successful fetch of the volumes
+func (s *Store) SaveVolumes(volumes []types.Volume) {
+	s.save(func(snap *Snapshot) { snap.Volumes = volumes })
+}
+
+// SaveNetworks records a successful fetch of the networks
+func (s *Store) SaveNetworks(networks []types.Network) {
+	s.save(func(snap *Snapshot) { snap.Networks = networks })
+}
+
+// save updates the snapshot and writes it when the lists changed or the
+// file is getting old. The cache is best effort: write errors are dropped
+// and only cost the next run its cached lists.
+func (s *Store) save(set func(*Snapshot)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	set(&s.snap)
+	s.snap.Saved = time.Now()
+	s.hasEntries = true
+	if s.path == "" {
+		return
+	}
+
+	lists := s.snap
+	lists.Saved = time.Time{}
+	data, err := json.Marshal(lists)
+	if err != nil || (bytes.Equal(data, s.written) && s.snap.Saved.Sub(s.fileSaved) < rewriteAfter) {
+		return
+	}
+	file, err := json.Marshal(s.snap)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return
+	}
+	// Write then rename, so a crash mid-write keeps the previous cache
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, file, 0o600); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return
+	}
+	s.written = data
+	s.fileSaved = s.snap.Saved
+}
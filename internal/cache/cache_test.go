@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"tinyd/internal/types"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tinyd", "lists.json")
+	host := "unix:///var/run/docker.sock"
+
+	s := openFile(path, host)
+	if _, ok := s.Snapshot(); ok {
+		t.Fatal("Snapshot() of a new store should be empty")
+	}
+
+	s.SaveContainers([]types.Container{{ID: "abc", Name: "web", Status: "RUNNING"}})
+	s.SaveNetworks([]types.Network{{ID: "n1", Name: "backend"}})
+
+	// A later run reads what was kept
+	snap, ok := openFile(path, host).Snapshot()
+	if !ok {
+		t.Fatal("Snapshot() after reopening found nothing")
+	}
+	if len(snap.Containers) != 1 || snap.Containers[0].Name != "web" || len(snap.Networks) != 1 {
+		t.Errorf("Snapshot() = %+v, want the saved lists", snap)
+	}
+	if snap.Saved.IsZero() {
+		t.Error("Snapshot() has no saved time")
+	}
+
+	// Another daemon's cache is not used
+	if _, ok := openFile(path, "ssh://me@box").Snapshot(); ok {
+		t.Error("Snapshot() of another host should be empty")
+	}
+
+	// A corrupt file starts empty
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := openFile(path, host).Snapshot(); ok {
+		t.Error("Snapshot() of a corrupt cache should be empty")
+	}
+}
@@ -0,0 +1,72 @@
+// Package churn tells which items of a list appeared or disappeared since
+// tinyd started, e.g. the containers and images CI or an orchestrator
+// created and removed during a session.
+package churn
+
+import "time"
+
+// maxRemoved bounds the removed items kept, the oldest going first
+const maxRemoved = 100
+
+// Removed is an item that disappeared from the list
+type Removed[T any] struct {
+	Item T
+	At   time.Time // When it was first missing
+}
+
+// Tracker compares each list to the first one it was given. Items are
+// told apart by key, e.g. a container ID.
+type Tracker[T any] struct {
+	key     func(T) string
+	initial map[string]bool // nil until the first list
+	current map[string]T
+	removed []Removed[T] // Newest first
+}
+
+// New returns a tracker of items told apart by key
+func New[T any](key func(T) string) *Tracker[T] {
+	return &Tracker[T]{key: key}
+}
+
+// Update records the current list. Items missing since the previous list
+// are added to Removed, and drop out of it when they come back.
+func (t *Tracker[T]) Update(items []T, now time.Time) {
+	current := make(map[string]T, len(items))
+	for _, item := range items {
+		current[t.key(item)] = item
+	}
+
+	if t.initial == nil {
+		t.initial = make(map[string]bool, len(current))
+		for k := range current {
+			t.initial[k] = true
+		}
+		t.current = current
+		return
+	}
+
+	var removed []Removed[T]
+	for k, item := range t.current {
+		if _, ok := current[k]; !ok {
+			removed = append(removed, Removed[T]{Item: item, At: now})
+		}
+	}
+	for _, r := range t.removed {
+		if _, back := current[t.key(r.Item)]; !back {
+			removed = append(removed, r)
+		}
+	}
+	t.removed = removed[:min(len(removed), maxRemoved)]
+	t.current = current
+}
+
+// IsNew reports whether the item wasn't in the first list
+func (t *Tracker[T]) IsNew(item T) bool {
+	return t.initial != nil && !t.initial[t.key(item)]
+}
+
+// Removed returns the items that disappeared since the first list, most
+// recently removed first
+func (t *Tracker[T]) Removed() []Removed[T] {
+	return t.removed
+}
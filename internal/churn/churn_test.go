@@ -0,0 +1,36 @@
+package churn
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTracker(t *testing.T) {
+	tr := New(func(s string) string { return s })
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	tr.Update([]string{"web", "db"}, start)
+	if tr.IsNew("web") || len(tr.Removed()) != 0 {
+		t.Fatalf("first list: IsNew(web) = %v, Removed() = %v", tr.IsNew("web"), tr.Removed())
+	}
+
+	// A CI job starts and stops a container, and the database goes away
+	tr.Update([]string{"web", "db", "ci-1"}, start.Add(time.Minute))
+	if !tr.IsNew("ci-1") || tr.IsNew("db") {
+		t.Errorf("IsNew(ci-1) = %v, IsNew(db) = %v; want true, false", tr.IsNew("ci-1"), tr.IsNew("db"))
+	}
+	tr.Update([]string{"web"}, start.Add(2*time.Minute))
+	removed := tr.Removed()
+	if len(removed) != 2 || !removed[0].At.Equal(start.Add(2*time.Minute)) {
+		t.Fatalf("Removed() = %v, want ci-1 and db at 10:02", removed)
+	}
+
+	// Items that come back are no longer removed, and keep their newness
+	tr.Update([]string{"web", "db"}, start.Add(3*time.Minute))
+	if removed := tr.Removed(); len(removed) != 1 || removed[0].Item != "ci-1" {
+		t.Errorf("Removed() after db came back = %v, want ci-1", removed)
+	}
+	if tr.IsNew("db") {
+		t.Error("IsNew(db) = true for an item of the first list")
+	}
+}
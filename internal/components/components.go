@@ -6,11 +6,12 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"tinyd/internal/theme"
 )
 
 // Color palette
 var (
-	bgColor = lipgloss.Color("#0a0a0a")
+	bgColor = theme.Color("#0a0a0a")
 )
 
 // HeaderComponent renders the top header bar
@@ -51,11 +52,13 @@ type TabsComponent struct {
 	tabs      []TabItem
 	activeTab int
 	width     int
+	notice    string // Optional right-aligned note on the label row
 }
 
 type TabItem struct {
 	Name     string
 	Shortcut string
+	Badge    string // Optional count shown after the name (e.g. "12/4▶")
 }
 
 func NewTabsComponent(tabs []TabItem, activeTab int) TabsComponent {
@@ -84,13 +87,41 @@ func (t TabsComponent) SetActiveTab(index int) TabsComponent {
 	return t
 }
 
+// SetBadges updates the count badge of each tab, in tab order
+func (t TabsComponent) SetBadges(badges []string) TabsComponent {
+	tabs := make([]TabItem, len(t.tabs))
+	copy(tabs, t.tabs)
+	for i := range tabs {
+		if i < len(badges) {
+			tabs[i].Badge = badges[i]
+		}
+	}
+	t.tabs = tabs
+	return t
+}
+
+// SetNotice sets a short note shown at the right end of the tab labels
+// (e.g. an available update); empty hides it
+func (t TabsComponent) SetNotice(notice string) TabsComponent {
+	t.notice = notice
+	return t
+}
+
+// tabText returns the padded label of a tab, including its badge
+func tabText(tab TabItem) string {
+	if tab.Badge != "" {
+		return fmt.Sprintf(" %s %s ", tab.Name, tab.Badge)
+	}
+	return fmt.Sprintf(" %s ", tab.Name)
+}
+
 func (t TabsComponent) View() string {
 	var b strings.Builder
 
-	borderColor := lipgloss.Color("#999999")
-	activeBorderColor := lipgloss.Color("#FFFFFF") // Brighter border for active tab
-	activeColor := lipgloss.Color("#FFFFFF")
-	inactiveColor := lipgloss.Color("#666666")
+	borderColor := theme.Color("#999999")
+	activeBorderColor := theme.Color("#FFFFFF") // Brighter border for active tab
+	activeColor := theme.Color("#FFFFFF")
+	inactiveColor := theme.Color("#666666")
 
 	borderStyle := lipgloss.NewStyle().
 		Foreground(borderColor).
@@ -103,8 +134,7 @@ func (t TabsComponent) View() string {
 	// Top row with rounded corners
 	b.WriteString(" ")
 	for i, tab := range t.tabs {
-		tabText := fmt.Sprintf(" %s ", tab.Name)
-		tabWidth := len(tabText)
+		tabWidth := lipgloss.Width(tabText(tab))
 
 		// Top border with rounded corners (use bright border for active tab)
 		style := borderStyle
@@ -120,8 +150,6 @@ func (t TabsComponent) View() string {
 	// Middle row with tab labels
 	b.WriteString(" ")
 	for i, tab := range t.tabs {
-		tabText := fmt.Sprintf(" %s ", tab.Name)
-
 		// Border style (use bright border for active tab)
 		bStyle := borderStyle
 		if i == t.activeTab {
@@ -141,18 +169,37 @@ func (t TabsComponent) View() string {
 				Background(bgColor).
 				Bold(true)
 		}
-		b.WriteString(textStyle.Render(tabText))
+		b.WriteString(textStyle.Render(" " + tab.Name + " "))
+		if tab.Badge != "" {
+			badgeStyle := lipgloss.NewStyle().
+				Foreground(inactiveColor).
+				Background(bgColor)
+			b.WriteString(badgeStyle.Render(tab.Badge + " "))
+		}
 
 		// Right border
 		b.WriteString(bStyle.Render("│"))
 	}
+	if t.notice != "" {
+		// Right-align the notice when it fits next to the tabs
+		used := 1
+		for _, tab := range t.tabs {
+			used += lipgloss.Width(tabText(tab)) + 2
+		}
+		if gap := t.width - used - lipgloss.Width(t.notice); gap >= 2 {
+			noticeStyle := lipgloss.NewStyle().
+				Foreground(theme.Color("#00FFFF")).
+				Background(bgColor)
+			b.WriteString(strings.Repeat(" ", gap))
+			b.WriteString(noticeStyle.Render(t.notice))
+		}
+	}
 	b.WriteString("\n")
 
 	// Bottom row with connecting line
 	b.WriteString(borderStyle.Render("─"))
 	for i, tab := range t.tabs {
-		tabText := fmt.Sprintf(" %s ", tab.Name)
-		tabWidth := len(tabText)
+		tabWidth := lipgloss.Width(tabText(tab))
 
 		if i == t.activeTab {
 			// Active tab: no bottom border (open to content), use bright border
@@ -170,7 +217,7 @@ func (t TabsComponent) View() string {
 	// Calculate remaining width for the horizontal line
 	totalTabWidth := 1 // Initial left padding
 	for _, tab := range t.tabs {
-		totalTabWidth += len(fmt.Sprintf(" %s ", tab.Name)) + 2 // +2 for borders
+		totalTabWidth += lipgloss.Width(tabText(tab)) + 2 // +2 for borders
 	}
 	remaining := t.width - totalTabWidth
 	if remaining > 0 {
@@ -222,11 +269,15 @@ func (s StatusLineComponent) View() string {
 
 // TableComponent renders a table with headers and rows
 type TableComponent struct {
-	headers []TableHeader
-	rows    []TableRow
-	start   int
-	end     int
-	width   int
+	headers      []TableHeader
+	rows         []TableRow
+	start        int
+	end          int
+	width        int
+	emptyMessage string
+	rowSpacing   int
+	sortColumn   int // Header marked with the sort direction, -1 for none
+	sortDesc     bool
 }
 
 type TableHeader struct {
@@ -239,13 +290,15 @@ type TableRow struct {
 	Cells      []string
 	IsSelected bool
 	Style      lipgloss.Style
+	Subline    string // Optional dim line rendered under the row, aligned with the second column
 }
 
 func NewTableComponent(headers []TableHeader) TableComponent {
 	return TableComponent{
-		headers: headers,
-		rows:    []TableRow{},
-		width:   80,
+		headers:    headers,
+		rows:       []TableRow{},
+		width:      80,
+		sortColumn: -1,
 	}
 }
 
@@ -267,6 +320,28 @@ func (t TableComponent) SetRows(rows []TableRow) TableComponent {
 	return t
 }
 
+// SetEmptyMessage sets what is shown instead of rows when there are none,
+// e.g. the key that creates the first item
+func (t TableComponent) SetEmptyMessage(message string) TableComponent {
+	t.emptyMessage = message
+	return t
+}
+
+// WithRowSpacing sets how many blank lines separate rows, for a roomier
+// layout that is easier to read and click
+func (t TableComponent) WithRowSpacing(lines int) TableComponent {
+	t.rowSpacing = lines
+	return t
+}
+
+// WithSort marks the header of the column the rows are sorted by with ▲
+// when ascending or ▼ when descending; -1 marks none
+func (t TableComponent) WithSort(column int, descending bool) TableComponent {
+	t.sortColumn = column
+	t.sortDesc = descending
+	return t
+}
+
 func (t TableComponent) SetVisibleRange(start, end int) TableComponent {
 	t.start = start
 	t.end = end
@@ -277,29 +352,33 @@ func (t TableComponent) View() string {
 	var b strings.Builder
 
 	headerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#CCCCCC")).
-		Background(lipgloss.Color("#0a0a0a")).
+		Foreground(theme.Color("#CCCCCC")).
+		Background(theme.Color("#0a0a0a")).
 		Bold(true)
 
 	normalCellStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#999999")).
-		Background(lipgloss.Color("#0a0a0a"))
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
 
 	selectedCellStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Background(lipgloss.Color("#0a0a0a"))
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a"))
 
 	lineStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#999999")).
-		Background(lipgloss.Color("#0a0a0a"))
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
 
 	// Table headers
 	for j, header := range t.headers {
+		label := header.Label
+		if j == t.sortColumn {
+			label = sortLabel(label, header.Width, t.sortDesc)
+		}
 		var headerText string
 		if header.AlignRight {
-			headerText = padLeft(header.Label, header.Width)
+			headerText = padLeft(label, header.Width)
 		} else {
-			headerText = padRight(header.Label, header.Width)
+			headerText = padRight(label, header.Width)
 		}
 		b.WriteString(headerStyle.Render(headerText))
 		if j < len(t.headers)-1 {
@@ -320,14 +399,20 @@ func (t TableComponent) View() string {
 	// Table rows
 	if len(t.rows) == 0 {
 		emptyMsg := " No items found"
+		if t.emptyMessage != "" {
+			emptyMsg = " " + t.emptyMessage
+		}
 		emptyStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#444444")).
-			Background(lipgloss.Color("#0a0a0a"))
+			Foreground(theme.Color("#444444")).
+			Background(theme.Color("#0a0a0a"))
 		b.WriteString(emptyStyle.Render(emptyMsg))
 		b.WriteString("\n")
 	} else {
 		for i := t.start; i < t.end && i < len(t.rows); i++ {
 			row := t.rows[i]
+			if i > t.start {
+				b.WriteString(strings.Repeat("\n", t.rowSpacing))
+			}
 
 			// Check if this is a special full-width row (like delete confirmation)
 			// Detect by checking if all cells after index 0 are empty
@@ -351,7 +436,12 @@ func (t TableComponent) View() string {
 						if j == 0 && (strings.Contains(cell, "●") || strings.Contains(cell, "○")) {
 							b.WriteString(cell)
 							if t.headers[j].Width > 1 {
-								b.WriteString(normalCellStyle.Render(strings.Repeat(" ", t.headers[j].Width-1)))
+								pad := strings.Repeat(" ", t.headers[j].Width-1)
+								// Without colors the selected row is marked instead of highlighted
+								if row.IsSelected && theme.Monochrome() {
+									pad = ">" + pad[1:]
+								}
+								b.WriteString(normalCellStyle.Render(pad))
 							}
 						} else {
 							// Apply alignment based on header
@@ -362,11 +452,15 @@ func (t TableComponent) View() string {
 								cellText = padRight(cell, t.headers[j].Width)
 							}
 
+							cellStyle := normalCellStyle
 							if row.IsSelected {
-								b.WriteString(selectedCellStyle.Render(cellText))
-							} else {
-								b.WriteString(normalCellStyle.Render(cellText))
+								cellStyle = selectedCellStyle
+							}
+							// A row style's foreground (e.g. red for alerts) overrides the text color
+							if _, unset := row.Style.GetForeground().(lipgloss.NoColor); !unset {
+								cellStyle = cellStyle.Foreground(row.Style.GetForeground())
 							}
+							b.WriteString(cellStyle.Render(cellText))
 						}
 						if j < len(t.headers)-1 {
 							b.WriteString(normalCellStyle.Render("  "))
@@ -375,6 +469,16 @@ func (t TableComponent) View() string {
 				}
 				b.WriteString("\n")
 			}
+
+			if row.Subline != "" {
+				sublineStyle := lipgloss.NewStyle().
+					Foreground(theme.Color("#555555")).
+					Background(theme.Color("#0a0a0a"))
+				indent := t.headers[0].Width + 2
+				b.WriteString(normalCellStyle.Render(strings.Repeat(" ", indent)))
+				b.WriteString(sublineStyle.Render(row.Subline))
+				b.WriteString("\n")
+			}
 		}
 	}
 
@@ -421,16 +525,16 @@ func (a ActionBarComponent) View() string {
 	var b strings.Builder
 
 	lineStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#999999")).
-		Background(lipgloss.Color("#0a0a0a"))
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
 
 	statusStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#00FFFF")).
-		Background(lipgloss.Color("#0a0a0a"))
+		Foreground(theme.Color("#00FFFF")).
+		Background(theme.Color("#0a0a0a"))
 
 	errorStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FF0000")).
-		Background(lipgloss.Color("#0a0a0a"))
+		Foreground(theme.Color("#FF0000")).
+		Background(theme.Color("#0a0a0a"))
 
 	// Top line
 	b.WriteString(lineStyle.Render(strings.Repeat("─", a.width)))
@@ -509,25 +613,25 @@ func (d DetailViewComponent) View() string {
 	var b strings.Builder
 
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Background(lipgloss.Color("#0a0a0a")).
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
 		Bold(true)
 
 	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#999999")).
-		Background(lipgloss.Color("#0a0a0a"))
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
 
 	lineStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#999999")).
-		Background(lipgloss.Color("#0a0a0a"))
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
 
 	contentStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#999999")).
-		Background(lipgloss.Color("#0a0a0a"))
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
 
 	loadingStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#444444")).
-		Background(lipgloss.Color("#0a0a0a"))
+		Foreground(theme.Color("#444444")).
+		Background(theme.Color("#0a0a0a"))
 
 	// Header
 	headerText := d.title
@@ -588,18 +692,48 @@ func stripAnsiCodes(str string) string {
 	return result
 }
 
+// sortLabel appends the sort direction to a header label, shortening the
+// label so that the arrow still fits the column
+func sortLabel(label string, width int, descending bool) string {
+	arrow := "▲"
+	if descending {
+		arrow = "▼"
+	}
+	if lipgloss.Width(label)+1 > width {
+		label = truncateWidth(label, width-1)
+	}
+	return label + arrow
+}
+
 // padRight pads a string to the right with spaces
 func padRight(s string, width int) string {
-	if len(s) >= width {
-		return s[:width]
+	w := lipgloss.Width(s)
+	if w >= width {
+		return truncateWidth(s, width)
 	}
-	return s + strings.Repeat(" ", width-len(s))
+	return s + strings.Repeat(" ", width-w)
 }
 
 // padLeft pads a string to the left with spaces
 func padLeft(s string, width int) string {
-	if len(s) >= width {
-		return s[:width]
+	w := lipgloss.Width(s)
+	if w >= width {
+		return truncateWidth(s, width)
 	}
-	return strings.Repeat(" ", width-len(s)) + s
+	return strings.Repeat(" ", width-w) + s
+}
+
+// truncateWidth cuts s to at most width terminal cells without splitting runes
+func truncateWidth(s string, width int) string {
+	var b strings.Builder
+	used := 0
+	for _, r := range s {
+		w := lipgloss.Width(string(r))
+		if used+w > width {
+			break
+		}
+		b.WriteRune(r)
+		used += w
+	}
+	return b.String()
 }
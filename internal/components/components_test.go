@@ -0,0 +1,22 @@
+package components
+
+import "testing"
+
+func TestSortLabel(t *testing.T) {
+	tests := []struct {
+		label      string
+		width      int
+		descending bool
+		want       string
+	}{
+		{"NAME", 20, false, "NAME▲"},
+		{"CPU", 8, true, "CPU▼"},
+		{"CREATED", 8, true, "CREATED▼"},
+		{"MOUNT POINT", 6, false, "MOUNT▲"}, // Shortened for the arrow
+	}
+	for _, tt := range tests {
+		if got := sortLabel(tt.label, tt.width, tt.descending); got != tt.want {
+			t.Errorf("sortLabel(%q, %d, %v) = %q, want %q", tt.label, tt.width, tt.descending, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package components
+
+import "strings"
+
+// Smallest terminal the UI is laid out for. Below it the UI only shows a
+// notice, and `tinyd doctor` warns about the terminal.
+const (
+	MinWidth  = 80
+	MinHeight = 24
+)
+
+// TableHeaderLines is how many lines a table takes above its rows: the
+// column labels and the divider under them
+const TableHeaderLines = 2
+
+// LineCount returns how many terminal lines a rendered block takes. A
+// trailing newline ends the last line rather than starting a new one.
+func LineCount(s string) int {
+	if s == "" {
+		return 0
+	}
+	return strings.Count(strings.TrimSuffix(s, "\n"), "\n") + 1
+}
+
+// ViewportHeight returns how many table rows fit in a terminal of the given
+// height next to a table header and the rendered chrome around the table
+// (tabs, action bar, ...), but never fewer than minRows. Measuring the chrome
+// instead of assuming its size keeps the table filling the terminal exactly
+// when a component grows or shrinks.
+func ViewportHeight(height, minRows int, chrome ...string) int {
+	rows := height - TableHeaderLines
+	for _, c := range chrome {
+		rows -= LineCount(c)
+	}
+	return max(rows, minRows)
+}
+
+// WidenColumn widens headers[col] by up to extra cells, taking them from the
+// fill columns (indexes into headers) one at a time from the widest, none
+// going below minFill. It returns how many cells the column gained, which
+// is less than extra when the fill columns run out.
+func WidenColumn(headers []TableHeader, col, extra int, fill []int, minFill int) int {
+	added := 0
+	for added < extra {
+		donor := -1
+		for _, i := range fill {
+			if i != col && headers[i].Width > minFill && (donor < 0 || headers[i].Width > headers[donor].Width) {
+				donor = i
+			}
+		}
+		if donor < 0 {
+			break
+		}
+		headers[donor].Width--
+		headers[col].Width++
+		added++
+	}
+	return added
+}
+
+// ColumnAt returns the index of the header under terminal column x of a
+// table rendered from the left edge, or -1 for the gaps between columns
+// and past the last one
+func ColumnAt(headers []TableHeader, x int) int {
+	start := 0
+	for i, header := range headers {
+		if x >= start && x < start+header.Width {
+			return i
+		}
+		start += header.Width + 2
+	}
+	return -1
+}
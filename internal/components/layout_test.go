@@ -0,0 +1,77 @@
+package components
+
+import "testing"
+
+func TestLineCount(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{"", 0},
+		{"one", 1},
+		{"one\n", 1},
+		{"one\ntwo", 2},
+		{"\nindicator", 2},
+		{"a\nb\nc\n", 3},
+	}
+	for _, tt := range tests {
+		if got := LineCount(tt.input); got != tt.want {
+			t.Errorf("LineCount(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestViewportHeight(t *testing.T) {
+	tabs := "top\nlabels\nbottom\n"
+	actionBar := "rule\nactions\n"
+	if got := ViewportHeight(24, 3, tabs, "", actionBar); got != 24-3-2-2 {
+		t.Errorf("ViewportHeight(24) = %d, want %d", got, 24-3-2-2)
+	}
+	if got := ViewportHeight(8, 3, tabs, actionBar); got != 3 {
+		t.Errorf("ViewportHeight(8) = %d, want the minimum 3", got)
+	}
+}
+
+func TestWidenColumn(t *testing.T) {
+	headers := []TableHeader{{Width: 2}, {Width: 30}, {Width: 20}, {Width: 15}}
+
+	// The widest fill column gives first
+	if got := WidenColumn(headers, 3, 12, []int{1, 2}, 10); got != 12 {
+		t.Errorf("WidenColumn() = %d, want 12", got)
+	}
+	if headers[1].Width != 19 || headers[2].Width != 19 || headers[3].Width != 27 {
+		t.Errorf("widths after WidenColumn() = %v", headers)
+	}
+
+	// A fill column takes from the others only, down to the minimum
+	if got := WidenColumn(headers, 1, 50, []int{1, 2}, 10); got != 9 {
+		t.Errorf("WidenColumn(fill) = %d, want 9", got)
+	}
+	if headers[1].Width != 28 || headers[2].Width != 10 {
+		t.Errorf("widths after WidenColumn(fill) = %v", headers)
+	}
+}
+
+func TestColumnAt(t *testing.T) {
+	headers := []TableHeader{{Width: 2}, {Width: 10}, {Width: 8}}
+
+	tests := []struct {
+		x    int
+		want int
+	}{
+		{0, 0},
+		{1, 0},
+		{2, -1}, // Gap
+		{4, 1},
+		{13, 1},
+		{15, -1},
+		{16, 2},
+		{23, 2},
+		{24, -1}, // Past the last column
+	}
+	for _, tt := range tests {
+		if got := ColumnAt(headers, tt.x); got != tt.want {
+			t.Errorf("ColumnAt(%d) = %d, want %d", tt.x, got, tt.want)
+		}
+	}
+}
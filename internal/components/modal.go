@@ -0,0 +1,180 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"tinyd/internal/theme"
+)
+
+// ModalKey is what a key press means to a modal. Every modal answers keys
+// the same way: Esc always cancels, Enter always confirms, and Tab or the
+// arrow keys move the focus.
+type ModalKey int
+
+const (
+	ModalKeyNone    ModalKey = iota // A key the modal handles itself, e.g. text input
+	ModalKeyCancel                  // Esc
+	ModalKeyConfirm                 // Enter
+	ModalKeyNext                    // Tab or down
+	ModalKeyPrev                    // Shift+Tab or up
+)
+
+// ClassifyModalKey maps a key to its modal meaning. Left and right are left
+// to the modal, as text fields and choices use them differently.
+func ClassifyModalKey(key string) ModalKey {
+	switch key {
+	case "esc":
+		return ModalKeyCancel
+	case "enter":
+		return ModalKeyConfirm
+	case "tab", "down":
+		return ModalKeyNext
+	case "shift+tab", "up":
+		return ModalKeyPrev
+	}
+	return ModalKeyNone
+}
+
+// ModalResult is the outcome of a key press in a modal
+type ModalResult int
+
+const (
+	ModalOpen      ModalResult = iota // Still waiting for an answer
+	ModalConfirmed                    // Closed with the focused choice
+	ModalCancelled                    // Closed without acting
+)
+
+// ConfirmModal is a yes/no question. While active it takes every key, so
+// nothing behind it reacts until it is answered.
+type ConfirmModal struct {
+	prompt string
+	yes    bool // Whether YES has the focus
+	active bool
+}
+
+// NewConfirmModal opens a question with the focus on YES or NO
+func NewConfirmModal(prompt string, focusYes bool) ConfirmModal {
+	return ConfirmModal{prompt: prompt, yes: focusYes, active: true}
+}
+
+// Active reports whether the question is waiting for an answer
+func (c ConfirmModal) Active() bool {
+	return c.active
+}
+
+// HandleKey answers a key press. Left/right, h/l and Tab move between YES and
+// NO, y and n answer directly, Enter confirms the focused choice and Esc
+// cancels. Confirming NO counts as cancelling.
+func (c ConfirmModal) HandleKey(key string) (ConfirmModal, ModalResult) {
+	switch key {
+	case "left", "h":
+		c.yes = true
+		return c, ModalOpen
+	case "right", "l":
+		c.yes = false
+		return c, ModalOpen
+	case "y", "Y":
+		c.active = false
+		return c, ModalConfirmed
+	case "n", "N":
+		c.active = false
+		return c, ModalCancelled
+	}
+
+	switch ClassifyModalKey(key) {
+	case ModalKeyNext, ModalKeyPrev:
+		c.yes = !c.yes
+	case ModalKeyCancel:
+		c.active = false
+		return c, ModalCancelled
+	case ModalKeyConfirm:
+		c.active = false
+		if c.yes {
+			return c, ModalConfirmed
+		}
+		return c, ModalCancelled
+	}
+	return c, ModalOpen
+}
+
+// View renders the question followed by the YES and NO buttons
+func (c ConfirmModal) View() string {
+	// Question in white
+	promptStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(bgColor).
+		Bold(true)
+
+	// Active YES button: black text on green background
+	yesActiveStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#000000")).
+		Background(theme.Color("#00FF00"))
+
+	// Active NO button: black text on red background
+	noActiveStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#000000")).
+		Background(theme.Color("#FF0000"))
+
+	// Inactive button: gray text, no background
+	inactiveStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#666666")).
+		Background(bgColor)
+
+	var b strings.Builder
+	b.WriteString(promptStyle.Render(c.prompt))
+	if theme.Monochrome() {
+		// Without colors the focused button is bracketed instead
+		if c.yes {
+			b.WriteString(promptStyle.Render("[YES]  NO "))
+		} else {
+			b.WriteString(promptStyle.Render(" YES  [NO]"))
+		}
+		return b.String()
+	}
+	if c.yes {
+		b.WriteString(yesActiveStyle.Render(" YES "))
+		b.WriteString(inactiveStyle.Render(" NO "))
+	} else {
+		b.WriteString(inactiveStyle.Render(" YES "))
+		b.WriteString(noActiveStyle.Render(" NO "))
+	}
+	return b.String()
+}
+
+// TruncateMiddle shortens s to at most width terminal cells by replacing its
+// middle with "…", so that two long names sharing a prefix stay apart. The
+// tag or digest after the last ':' or '@' is kept whole when it leaves room
+// for a few characters of the prefix. Runes are never split.
+func TruncateMiddle(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	if width < 1 {
+		return ""
+	}
+	avail := width - 1 // The ellipsis
+	tail := avail / 2
+	if i := strings.LastIndexAny(s, ":@"); i > 0 {
+		if w := lipgloss.Width(s[i:]); w > tail && w <= avail-4 {
+			tail = w
+		}
+	}
+	return truncateWidth(s, avail-tail) + "…" + lastWidth(s, tail)
+}
+
+// lastWidth returns the end of s that fits in width terminal cells without
+// splitting runes
+func lastWidth(s string, width int) string {
+	runes := []rune(s)
+	used, start := 0, len(runes)
+	for start > 0 {
+		w := lipgloss.Width(string(runes[start-1]))
+		if used+w > width {
+			break
+		}
+		used += w
+		start--
+	}
+	return string(runes[start:])
+}
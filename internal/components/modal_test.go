@@ -0,0 +1,58 @@
+package components
+
+import "testing"
+
+func TestConfirmModal(t *testing.T) {
+	tests := []struct {
+		name     string
+		focusYes bool
+		keys     []string
+		want     ModalResult
+	}{
+		{"enter confirms focused yes", true, []string{"enter"}, ModalConfirmed},
+		{"enter on no cancels", false, []string{"enter"}, ModalCancelled},
+		{"esc always cancels", true, []string{"esc"}, ModalCancelled},
+		{"left focuses yes", false, []string{"left", "enter"}, ModalConfirmed},
+		{"tab toggles focus", false, []string{"tab", "enter"}, ModalConfirmed},
+		{"y answers directly", false, []string{"y"}, ModalConfirmed},
+		{"other keys are swallowed", true, []string{"q", "H"}, ModalOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modal := NewConfirmModal("Delete web? ", tt.focusYes)
+			result := ModalOpen
+			for _, key := range tt.keys {
+				modal, result = modal.HandleKey(key)
+			}
+			if result != tt.want {
+				t.Errorf("result = %v, want %v", result, tt.want)
+			}
+			if modal.Active() != (tt.want == ModalOpen) {
+				t.Errorf("Active() = %v after %v", modal.Active(), result)
+			}
+		})
+	}
+}
+
+func TestTruncateMiddle(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"web", 10, "web"},
+		{"shop-backend-worker-1", 11, "shop-…ker-1"},
+		{"registry.example.com/team/api:1.24.3-alpine", 24, "registry.…:1.24.3-alpine"},
+		{"ghcr.io/org/app:a-tag-longer-than-the-room", 16, "ghcr.io/…he-room"}, // Tag too long to keep
+		{"données-très-longues-été", 10, "donné…-été"},
+		{"日本語のコンテナ名", 9, "日本…ナ名"},
+		{"日本語のコンテナ名", 8, "日本…名"}, // Wide runes aren't split
+	}
+	for _, tt := range tests {
+		got := TruncateMiddle(tt.s, tt.width)
+		if got != tt.want {
+			t.Errorf("TruncateMiddle(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package components
+
+import (
+	"math"
+	"strings"
+)
+
+// sparkBlocks fill a cell by eighths, from empty to full
+var sparkBlocks = []rune(" ▁▂▃▄▅▆▇█")
+
+// Sparkline graphs the last width values as columns of block characters,
+// height lines tall, top line first. Columns are scaled against ceiling, or
+// against the largest value when ceiling is 0. With fewer values than width
+// the graph is right-aligned, so the newest value is always in the last
+// column, and any value above zero shows at least as a sliver.
+func Sparkline(values []float64, width, height int, ceiling float64) []string {
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+	values = values[max(len(values)-width, 0):]
+	top := ceiling
+	if top <= 0 {
+		for _, v := range values {
+			top = max(top, v)
+		}
+	}
+
+	// Eighths of a cell filled in each column, counted from the bottom
+	levels := make([]int, len(values))
+	for i, v := range values {
+		if top <= 0 || v <= 0 {
+			continue
+		}
+		level := int(math.Round(min(v/top, 1) * float64(height*8)))
+		levels[i] = max(level, 1)
+	}
+
+	lines := make([]string, height)
+	pad := strings.Repeat(" ", width-len(values))
+	for row := range height {
+		var b strings.Builder
+		b.WriteString(pad)
+		floor := (height - 1 - row) * 8
+		for _, level := range levels {
+			b.WriteRune(sparkBlocks[min(max(level-floor, 0), 8)])
+		}
+		lines[row] = b.String()
+	}
+	return lines
+}
@@ -0,0 +1,32 @@
+package components
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []float64
+		width   int
+		height  int
+		ceiling float64
+		want    []string
+	}{
+		{"scaled to the largest", []float64{0, 1, 2, 4, 8}, 5, 1, 0, []string{" ▁▂▄█"}},
+		{"scaled to the ceiling", []float64{50, 100, 200}, 3, 1, 100, []string{"▄██"}},
+		{"right-aligned", []float64{8}, 3, 1, 0, []string{"  █"}},
+		{"newest kept", []float64{8, 0, 8}, 2, 1, 0, []string{" █"}},
+		{"slivers", []float64{1, 1000}, 2, 1, 0, []string{"▁█"}},
+		{"two lines", []float64{1, 2, 4}, 3, 2, 4, []string{"  █", "▄██"}},
+		{"all zero", []float64{0, 0}, 2, 1, 0, []string{"  "}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sparkline(tt.values, tt.width, tt.height, tt.ceiling); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Sparkline(%v, %d, %d, %v) = %q, want %q", tt.values, tt.width, tt.height, tt.ceiling, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+// Package crash writes a report when tinyd panics: the stack trace, the
+// build, the terminal size and the last messages the UI processed, so that
+// issues can include more than the panic value.
+package crash
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"tinyd/internal/version"
+)
+
+// recentMessages is how many processed messages a report lists
+const recentMessages = 20
+
+// Reporter wraps the UI model and writes a crash report when its Update,
+// View or one of its commands panics. The panic is passed on afterwards, so
+// Bubble Tea still restores the terminal from the altscreen.
+type Reporter struct {
+	model tea.Model
+	dir   string // Where reports are written
+
+	mu     sync.Mutex
+	recent []string // Last messages processed, oldest first
+	width  int
+	height int
+	path   string // Report written, "" until a crash
+}
+
+// Wrap returns a reporter around the UI model, writing reports to the
+// temp directory
+func Wrap(model tea.Model) *Reporter {
+	return &Reporter{model: model, dir: os.TempDir()}
+}
+
+// Path returns the report written for a crash, "" when there was none
+func (r *Reporter) Path() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.path
+}
+
+// Init initializes the wrapped model
+func (r *Reporter) Init() tea.Cmd {
+	defer r.catch()
+	return r.wrapCmd(r.model.Init())
+}
+
+// Update records the message and passes it on to the wrapped model
+func (r *Reporter) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	r.remember(msg)
+	defer r.catch()
+	model, cmd := r.model.Update(msg)
+	r.model = model
+	return r, r.wrapCmd(cmd)
+}
+
+// View renders the wrapped model
+func (r *Reporter) View() string {
+	defer r.catch()
+	return r.model.View()
+}
+
+// Recover writes a report for a panic recovered outside the UI, e.g. by
+// main's top-level recover, and returns its path
+func (r *Reporter) Recover(value any) string {
+	r.record(value, debug.Stack())
+	return r.Path()
+}
+
+// catch writes a report for a panic in progress and panics again. It must
+// be deferred directly.
+func (r *Reporter) catch() {
+	if value := recover(); value != nil {
+		r.record(value, debug.Stack())
+		panic(value)
+	}
+}
+
+// wrapCmd makes a command, and the commands of a batch it returns, report
+// panics too. Bubble Tea runs commands in their own goroutines.
+func (r *Reporter) wrapCmd(cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		defer r.catch()
+		msg := cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			wrapped := make(tea.BatchMsg, len(batch))
+			for i, c := range batch {
+				wrapped[i] = r.wrapCmd(c)
+			}
+			return wrapped
+		}
+		return msg
+	}
+}
+
+// remember keeps a short description of a message: its type, plus the key of
+// key presses and the size of resizes. Message contents may hold secrets
+// (e.g. environment variables), so nothing else is kept.
+func (r *Reporter) remember(msg tea.Msg) {
+	desc := fmt.Sprintf("%T", msg)
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		desc += " " + msg.String()
+	case tea.WindowSizeMsg:
+		desc += fmt.Sprintf(" %dx%d", msg.Width, msg.Height)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		r.width, r.height = size.Width, size.Height
+	}
+	r.recent = append(r.recent, time.Now().Format("15:04:05.000")+" "+desc)
+	if len(r.recent) > recentMessages {
+		r.recent = r.recent[1:]
+	}
+}
+
+// record writes the report of the first panic; later panics, e.g. the same
+// one passed on through other wrappers, are ignored
+func (r *Reporter) record(value any, stack []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.path != "" {
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n", version.Info())
+	fmt.Fprintf(&b, "Time: %s\n", time.Now().Format(time.RFC3339))
+	fmt.Fprintf(&b, "Terminal: %dx%d, TERM=%s\n\n", r.width, r.height, os.Getenv("TERM"))
+	fmt.Fprintf(&b, "panic: %v\n\n", value)
+	fmt.Fprintf(&b, "Last messages:\n")
+	for _, desc := range r.recent {
+		fmt.Fprintf(&b, "  %s\n", desc)
+	}
+	fmt.Fprintf(&b, "\n%s", stack)
+
+	path := filepath.Join(r.dir, fmt.Sprintf("tinyd-crash-%s.txt", time.Now().Format("20060102-150405")))
+	if err := os.WriteFile(path, []byte(b.String()), 0o600); err != nil {
+		return
+	}
+	r.path = path
+}
@@ -0,0 +1,74 @@
+package crash
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// panicky panics on the key "x"
+type panicky struct{}
+
+func (panicky) Init() tea.Cmd { return nil }
+
+func (p panicky) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && key.String() == "x" {
+		panic("boom")
+	}
+	return p, nil
+}
+
+func (panicky) View() string { return "" }
+
+func TestReporterWritesReport(t *testing.T) {
+	r := Wrap(panicky{})
+	r.dir = t.TempDir()
+
+	r.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	r.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Update() did not pass the panic on")
+			}
+		}()
+		r.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	}()
+
+	if r.Path() == "" {
+		t.Fatal("Path() is empty after a panic")
+	}
+	report, err := os.ReadFile(r.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"panic: boom", "Terminal: 120x40", "tea.KeyMsg j", "tea.KeyMsg x", "crash_test.go"} {
+		if !strings.Contains(string(report), want) {
+			t.Errorf("report is missing %q:\n%s", want, report)
+		}
+	}
+}
+
+func TestReporterWrapsBatchedCommands(t *testing.T) {
+	r := Wrap(panicky{})
+	r.dir = t.TempDir()
+
+	cmd := r.wrapCmd(tea.Batch(func() tea.Msg { return nil }, func() tea.Msg { panic("in a command") }))
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok {
+		t.Fatal("batch not passed through")
+	}
+
+	func() {
+		defer func() { recover() }()
+		for _, c := range batch {
+			c()
+		}
+	}()
+	if r.Path() == "" {
+		t.Error("panic in a batched command not reported")
+	}
+}
@@ -0,0 +1,55 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/moby/moby/api/types/network"
+)
+
+// ContainerAddress returns the IP address of a running container that the
+// daemon's host can reach, preferring the default bridge network
+func (c *Client) ContainerAddress(ctx context.Context, containerID string) (string, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutQuick)
+		defer cancel()
+	}
+
+	inspect, err := c.inspectContainer(ctx, containerID)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect container: %w", err)
+	}
+	if inspect.Container.State == nil || !inspect.Container.State.Running {
+		return "", errors.New("the container is not running")
+	}
+	if inspect.Container.NetworkSettings == nil {
+		return "", errors.New("the container has no network")
+	}
+	ip := networkAddress(inspect.Container.NetworkSettings.Networks)
+	if ip == "" {
+		return "", errors.New("the container has no IP address: it runs with host or no networking")
+	}
+	return ip, nil
+}
+
+// networkAddress picks the IPv4 address of the bridge network, or else of
+// the first network by name that has one
+func networkAddress(networks map[string]*network.EndpointSettings) string {
+	if settings := networks["bridge"]; settings != nil && settings.IPAddress.IsValid() {
+		return settings.IPAddress.String()
+	}
+	names := make([]string, 0, len(networks))
+	for name := range networks {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if settings := networks[name]; settings != nil && settings.IPAddress.IsValid() {
+			return settings.IPAddress.String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,39 @@
+package docker
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/moby/moby/api/types/network"
+)
+
+func TestNetworkAddress(t *testing.T) {
+	endpoint := func(ip string) *network.EndpointSettings {
+		settings := &network.EndpointSettings{}
+		if ip != "" {
+			settings.IPAddress = netip.MustParseAddr(ip)
+		}
+		return settings
+	}
+
+	networks := map[string]*network.EndpointSettings{
+		"shop_default": endpoint("172.20.0.4"),
+		"bridge":       endpoint("172.17.0.3"),
+	}
+	if got := networkAddress(networks); got != "172.17.0.3" {
+		t.Errorf("networkAddress() = %q, want the bridge address", got)
+	}
+
+	networks = map[string]*network.EndpointSettings{
+		"web_front": endpoint("172.21.0.2"),
+		"none":      endpoint(""),
+		"web_back":  endpoint("172.22.0.2"),
+	}
+	if got := networkAddress(networks); got != "172.22.0.2" {
+		t.Errorf("networkAddress() = %q, want the first network by name", got)
+	}
+
+	if got := networkAddress(map[string]*network.EndpointSettings{"host": endpoint("")}); got != "" {
+		t.Errorf("networkAddress() with host networking = %q", got)
+	}
+}
@@ -0,0 +1,84 @@
+package docker
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/moby/moby/api/types/container"
+	"tinyd/internal/types"
+)
+
+// defaultNetworks are the networks any container may be on without being
+// related to the others there; sharing them is not counted
+var defaultNetworks = []string{"bridge", "host", "none"}
+
+// containerVolumes lists the named volumes and bind-mounted host paths of a
+// container, sorted
+func containerVolumes(mounts []container.MountPoint) []string {
+	var volumes []string
+	for _, m := range mounts {
+		switch {
+		case m.Type == "volume" && m.Name != "":
+			volumes = append(volumes, m.Name)
+		case m.Type == "bind" && m.Source != "":
+			volumes = append(volumes, m.Source)
+		}
+	}
+	sort.Strings(volumes)
+	return slices.Compact(volumes)
+}
+
+// containerNetworks lists the networks a container is attached to, sorted
+func containerNetworks(settings *container.NetworkSettingsSummary) []string {
+	if settings == nil {
+		return nil
+	}
+	networks := make([]string, 0, len(settings.Networks))
+	for name := range settings.Networks {
+		networks = append(networks, name)
+	}
+	sort.Strings(networks)
+	return networks
+}
+
+// RelatedContainers lists the containers sharing a volume, a host path or a
+// network other than the defaults with the container id, i.e. the ones a
+// stop or removal may affect. Those sharing the most come first.
+func RelatedContainers(containers []types.Container, id string) []types.Relation {
+	idx := slices.IndexFunc(containers, func(c types.Container) bool { return c.ID == id })
+	if idx < 0 {
+		return nil
+	}
+	target := containers[idx]
+
+	var related []types.Relation
+	for _, c := range containers {
+		if c.ID == id {
+			continue
+		}
+		rel := types.Relation{Container: c}
+		for _, v := range c.Volumes {
+			if slices.Contains(target.Volumes, v) {
+				rel.Volumes = append(rel.Volumes, v)
+			}
+		}
+		for _, n := range c.Networks {
+			if slices.Contains(target.Networks, n) && !slices.Contains(defaultNetworks, n) {
+				rel.Networks = append(rel.Networks, n)
+			}
+		}
+		if len(rel.Volumes) > 0 || len(rel.Networks) > 0 {
+			related = append(related, rel)
+		}
+	}
+
+	sort.SliceStable(related, func(i, j int) bool {
+		si := len(related[i].Volumes) + len(related[i].Networks)
+		sj := len(related[j].Volumes) + len(related[j].Networks)
+		if si != sj {
+			return si > sj
+		}
+		return related[i].Container.Name < related[j].Container.Name
+	})
+	return related
+}
@@ -0,0 +1,59 @@
+package docker
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/network"
+	"tinyd/internal/types"
+)
+
+func TestContainerVolumes(t *testing.T) {
+	mounts := []container.MountPoint{
+		{Type: "volume", Name: "pgdata", Destination: "/var/lib/postgresql/data"},
+		{Type: "bind", Source: "/srv/config", Destination: "/config"},
+		{Type: "tmpfs", Destination: "/tmp"},
+		{Type: "volume", Name: "pgdata", Destination: "/backup"},
+	}
+	want := []string{"/srv/config", "pgdata"}
+	if got := containerVolumes(mounts); !reflect.DeepEqual(got, want) {
+		t.Errorf("containerVolumes() = %v, want %v", got, want)
+	}
+
+	settings := &container.NetworkSettingsSummary{Networks: map[string]*network.EndpointSettings{"web": {}, "bridge": {}}}
+	if got := containerNetworks(settings); !reflect.DeepEqual(got, []string{"bridge", "web"}) {
+		t.Errorf("containerNetworks() = %v", got)
+	}
+	if got := containerNetworks(nil); got != nil {
+		t.Errorf("containerNetworks(nil) = %v, want nil", got)
+	}
+}
+
+func TestRelatedContainers(t *testing.T) {
+	containers := []types.Container{
+		{ID: "db", Name: "db", Volumes: []string{"pgdata"}, Networks: []string{"backend", "bridge"}},
+		{ID: "api", Name: "api", Networks: []string{"backend", "frontend"}},
+		{ID: "backup", Name: "backup", Volumes: []string{"pgdata"}, Networks: []string{"backend"}},
+		{ID: "other", Name: "other", Networks: []string{"bridge"}},
+	}
+
+	related := RelatedContainers(containers, "db")
+	if len(related) != 2 {
+		t.Fatalf("RelatedContainers() = %v, want backup and api", related)
+	}
+	if related[0].Container.ID != "backup" || !reflect.DeepEqual(related[0].Volumes, []string{"pgdata"}) ||
+		!reflect.DeepEqual(related[0].Networks, []string{"backend"}) {
+		t.Errorf("first relation = %+v, want backup sharing pgdata and backend", related[0])
+	}
+	if related[1].Container.ID != "api" || related[1].Volumes != nil {
+		t.Errorf("second relation = %+v, want api sharing backend", related[1])
+	}
+
+	if related := RelatedContainers(containers, "other"); len(related) != 0 {
+		t.Errorf("RelatedContainers() on the default bridge = %v, want none", related)
+	}
+	if related := RelatedContainers(containers, "missing"); related != nil {
+		t.Errorf("RelatedContainers() of an unknown container = %v", related)
+	}
+}
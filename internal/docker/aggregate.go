@@ -0,0 +1,92 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"tinyd/internal/types"
+)
+
+// AggregatedTail returns the last tail lines of each container merged into
+// one stream in the order Docker received them, like `docker compose logs`.
+// Source is the container's index in containerIDs. A container whose logs
+// can't be read is skipped; the error names it.
+func (c *Client) AggregatedTail(ctx context.Context, containerIDs []string, tail string) ([]types.AggregatedLogLine, error) {
+	logs := make([]string, len(containerIDs))
+	var errs []error
+	for i, id := range containerIDs {
+		text, _, err := c.GetContainerLogStreams(ctx, id, LogsOptions{Tail: tail, Timestamps: true})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", id, err))
+			continue
+		}
+		logs[i] = text
+	}
+	return mergeLogsByTime(logs), errors.Join(errs...)
+}
+
+// mergeLogsByTime interleaves timestamped logs by time and strips the
+// timestamps. A line without one (a wrapped partial line) stays after the
+// line before it.
+func mergeLogsByTime(logs []string) []types.AggregatedLogLine {
+	type stamped struct {
+		at   time.Time
+		line types.AggregatedLogLine
+	}
+
+	var all []stamped
+	for source, text := range logs {
+		if text == "" {
+			continue
+		}
+		var last time.Time
+		for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+			if stamp, rest, ok := strings.Cut(line, " "); ok {
+				if at, err := time.Parse(time.RFC3339Nano, stamp); err == nil {
+					last, line = at, rest
+				}
+			}
+			all = append(all, stamped{at: last, line: types.AggregatedLogLine{Source: source, Line: line}})
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].at.Before(all[j].at) })
+
+	merged := make([]types.AggregatedLogLine, len(all))
+	for i, s := range all {
+		merged[i] = s.line
+	}
+	return merged
+}
+
+// FollowAggregatedLogs follows the logs of several containers at once (see
+// FollowLogs), emitting their new lines one at a time as they arrive. It
+// returns when every follow has ended, with the errors of those that
+// failed; a container that stops doesn't end the others.
+func (c *Client) FollowAggregatedLogs(ctx context.Context, containerIDs []string, emit func(types.AggregatedLogLine)) error {
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs = make([]error, len(containerIDs))
+	)
+	for i, id := range containerIDs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := c.FollowLogs(ctx, id, false, func(line string) {
+				mu.Lock()
+				defer mu.Unlock()
+				emit(types.AggregatedLogLine{Source: i, Line: line})
+			})
+			if err != nil && ctx.Err() == nil {
+				errs[i] = fmt.Errorf("%s: %w", id, err)
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
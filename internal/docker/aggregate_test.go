@@ -0,0 +1,26 @@
+package docker
+
+import (
+	"reflect"
+	"testing"
+
+	"tinyd/internal/types"
+)
+
+func TestMergeLogsByTime(t *testing.T) {
+	logs := []string{
+		"2024-05-01T10:00:00.000000001Z web starting\n2024-05-01T10:00:02Z web ready\n",
+		"",
+		"2024-05-01T10:00:01Z db starting\ncontinued\n2024-05-01T10:00:03Z db ready",
+	}
+	want := []types.AggregatedLogLine{
+		{Source: 0, Line: "web starting"},
+		{Source: 2, Line: "db starting"},
+		{Source: 2, Line: "continued"},
+		{Source: 0, Line: "web ready"},
+		{Source: 2, Line: "db ready"},
+	}
+	if got := mergeLogsByTime(logs); !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeLogsByTime() = %v, want %v", got, want)
+	}
+}
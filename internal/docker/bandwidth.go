@@ -0,0 +1,18 @@
+package docker
+
+// LowBandwidthEnvVar forces the low-bandwidth mode on ("1") or off ("0")
+const LowBandwidthEnvVar = "TINYD_LOW_BANDWIDTH"
+
+// LowBandwidth reports whether to use the low-bandwidth mode for a daemon
+// endpoint (see Endpoint): as set in LowBandwidthEnvVar, otherwise for
+// ssh:// endpoints, whose every request crosses a remote link
+func LowBandwidth(endpoint, setting string) bool {
+	switch setting {
+	case "1":
+		return true
+	case "0":
+		return false
+	}
+	_, _, ok := SSHDestination(endpoint)
+	return ok
+}
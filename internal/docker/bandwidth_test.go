@@ -0,0 +1,26 @@
+package docker
+
+import "testing"
+
+func TestLowBandwidth(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		setting  string
+		want     bool
+	}{
+		{"", "", false},
+		{"unix:///var/run/docker.sock", "", false},
+		{"tcp://10.0.0.5:2376", "", false},
+		{"ssh://deploy@build-box", "", true},
+		{"ssh://deploy@build-box:2222", "", true},
+		{"ssh://deploy@build-box", "0", false},
+		{"", "1", true},
+		{"tcp://10.0.0.5:2376", "1", true},
+		{"ssh://deploy@build-box", "yes", true}, // Unknown settings keep the default
+	}
+	for _, tt := range tests {
+		if got := LowBandwidth(tt.endpoint, tt.setting); got != tt.want {
+			t.Errorf("LowBandwidth(%q, %q) = %v, want %v", tt.endpoint, tt.setting, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package docker
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+)
+
+// CaptureImage is the helper image that runs tcpdump next to a container
+const CaptureImage = "nicolaka/netshoot:latest"
+
+// captureFile is where the helper writes the capture
+const captureFile = "/tmp/capture.pcap"
+
+// timeoutExitCode is the exit code of timeout(1) when the command ran out of time
+const timeoutExitCode = 124
+
+// CapturePackets records the traffic of a container for the given duration
+// and writes it as a pcap file to dest. tcpdump runs in a helper container
+// that shares the target's network namespace, so the target needs no tools
+// of its own. A port of 0 captures all traffic.
+func (c *Client) CapturePackets(ctx context.Context, containerID string, port int, duration time.Duration, dest string) error {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(duration + TimeoutLong)
+		defer cancel()
+	}
+
+	exists, err := c.ImageExists(ctx, CaptureImage)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if err := c.PullImage(ctx, CaptureImage); err != nil {
+			return err
+		}
+	}
+
+	helper, err := c.cli.ContainerCreate(ctx, client.ContainerCreateOptions{
+		Config: &container.Config{
+			Image: CaptureImage,
+			Cmd:   captureCommand(port, duration),
+			User:  "root",
+		},
+		HostConfig: &container.HostConfig{
+			NetworkMode: container.NetworkMode("container:" + containerID),
+			CapAdd:      []string{"NET_ADMIN", "NET_RAW"},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create capture helper: %w", err)
+	}
+	defer func() {
+		// Clean up even when ctx has expired
+		cleanupCtx, cancel := c.WithCustomTimeout(TimeoutQuick)
+		defer cancel()
+		_, _ = c.cli.ContainerRemove(cleanupCtx, helper.ID, client.ContainerRemoveOptions{Force: true})
+	}()
+
+	if _, err := c.cli.ContainerStart(ctx, helper.ID, client.ContainerStartOptions{}); err != nil {
+		return fmt.Errorf("failed to start capture helper: %w", err)
+	}
+
+	wait := c.cli.ContainerWait(ctx, helper.ID, client.ContainerWaitOptions{Condition: container.WaitConditionNotRunning})
+	select {
+	case err := <-wait.Error:
+		return fmt.Errorf("failed waiting for capture: %w", err)
+	case result := <-wait.Result:
+		if result.StatusCode != 0 && result.StatusCode != timeoutExitCode {
+			logs, _ := c.GetContainerLogs(ctx, helper.ID, LogsOptions{Tail: "5"})
+			return fmt.Errorf("tcpdump exited with code %d: %s", result.StatusCode, demuxLogs([]byte(logs)))
+		}
+	}
+
+	copied, err := c.cli.CopyFromContainer(ctx, helper.ID, client.CopyFromContainerOptions{SourcePath: captureFile})
+	if err != nil {
+		return fmt.Errorf("failed to copy capture: %w", err)
+	}
+	defer copied.Content.Close()
+
+	return extractSingleFile(copied.Content, dest)
+}
+
+// captureCommand builds the helper command: tcpdump on all interfaces,
+// stopped by timeout after duration, optionally filtered to one port
+func captureCommand(port int, duration time.Duration) []string {
+	cmd := []string{"timeout", strconv.Itoa(int(duration.Seconds())), "tcpdump", "-i", "any", "-U", "-w", captureFile}
+	if port > 0 {
+		cmd = append(cmd, "port", strconv.Itoa(port))
+	}
+	return cmd
+}
+
+// extractSingleFile writes the first regular file of a tar stream to dest
+func extractSingleFile(r io.Reader, dest string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return fmt.Errorf("capture archive is empty")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read capture archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		f, err := os.Create(dest)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", dest, err)
+		}
+		if _, err := io.Copy(f, tr); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to write %s: %w", dest, err)
+		}
+		return f.Close()
+	}
+}
@@ -0,0 +1,49 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCaptureCommand(t *testing.T) {
+	got := captureCommand(8080, 10*time.Second)
+	want := []string{"timeout", "10", "tcpdump", "-i", "any", "-U", "-w", captureFile, "port", "8080"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("captureCommand(8080) = %v, want %v", got, want)
+	}
+
+	// Port 0 captures everything
+	if got := captureCommand(0, 5*time.Second); len(got) != 8 {
+		t.Errorf("captureCommand(0) = %v, want no port filter", got)
+	}
+}
+
+func TestExtractSingleFile(t *testing.T) {
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	content := []byte("pcap data")
+	if err := tw.WriteHeader(&tar.Header{Name: "capture.pcap", Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	tw.Close()
+
+	dest := filepath.Join(t.TempDir(), "out.pcap")
+	if err := extractSingleFile(&archive, dest); err != nil {
+		t.Fatalf("extractSingleFile() error = %v", err)
+	}
+	got, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Errorf("extracted %q, want %q", got, content)
+	}
+}
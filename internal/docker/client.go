@@ -4,6 +4,7 @@ package docker
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/moby/moby/client"
@@ -13,6 +14,18 @@ import (
 type Client struct {
 	cli            *client.Client
 	defaultTimeout time.Duration
+
+	// Inspected settings behind the risk badges, by full container ID
+	risksMu sync.Mutex
+	risks   map[string]hostRisks
+
+	// Inspected restart counts, by full container ID
+	restartsMu sync.Mutex
+	restarts   map[string]restartCount
+
+	// Leave out what costs transfer but is seldom looked at, for slow
+	// links to remote daemons (see LowBandwidth)
+	lowBandwidth bool
 }
 
 // NewClient creates a new Docker client wrapper with sensible defaults
@@ -31,6 +44,14 @@ func NewClient() (*Client, error) {
 	}, nil
 }
 
+// Wrap returns a Client around an existing Docker client
+func Wrap(cli *client.Client) *Client {
+	return &Client{
+		cli:            cli,
+		defaultTimeout: 10 * time.Second,
+	}
+}
+
 // Close closes the underlying Docker client
 func (c *Client) Close() error {
 	if c.cli != nil {
@@ -54,6 +75,12 @@ func (c *Client) SetDefaultTimeout(timeout time.Duration) {
 	c.defaultTimeout = timeout
 }
 
+// SetLowBandwidth turns the low-bandwidth mode on or off: image lists
+// leave out intermediate layers
+func (c *Client) SetLowBandwidth(on bool) {
+	c.lowBandwidth = on
+}
+
 // Underlying returns the raw Docker client for advanced operations
 func (c *Client) Underlying() *client.Client {
 	return c.cli
@@ -0,0 +1,131 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/moby/moby/api/types/network"
+	"github.com/moby/moby/client"
+	"tinyd/internal/types"
+)
+
+// Labels set by docker compose on the containers it creates
+const (
+	composeProjectLabel = "com.docker.compose.project"
+	composeServiceLabel = "com.docker.compose.service"
+	composeNumberLabel  = "com.docker.compose.container-number"
+)
+
+// replicaSuffix matches the replica number at the end of a compose container
+// name: "-2" (compose v2) or "_2" (compose v1)
+var replicaSuffix = regexp.MustCompile(`([-_])(\d+)$`)
+
+// GroupReplicas folds the containers of each compose service with more than
+// one replica into a single entry. The first replica in list order is kept
+// as the row and the others go into its Replicas, so sorting by status keeps
+// a running replica in front.
+func GroupReplicas(containers []types.Container) []types.Container {
+	grouped := make([]types.Container, 0, len(containers))
+	rows := make(map[string]int) // project/service -> index in grouped
+
+	for _, c := range containers {
+		if c.ComposeService == "" {
+			grouped = append(grouped, c)
+			continue
+		}
+
+		key := c.ComposeProject + "/" + c.ComposeService
+		if i, ok := rows[key]; ok {
+			grouped[i].Replicas = append(grouped[i].Replicas, c)
+			continue
+		}
+		rows[key] = len(grouped)
+		grouped = append(grouped, c)
+	}
+
+	return grouped
+}
+
+// ServiceReplicas returns every replica behind a (possibly grouped) container row
+func ServiceReplicas(c types.Container) []types.Container {
+	replicas := append([]types.Container{c}, c.Replicas...)
+	replicas[0].Replicas = nil
+	return replicas
+}
+
+// replicaName derives the name of replica number from the name of another
+// replica of the same service, e.g. "shop-web-1" -> "shop-web-3"
+func replicaName(name string, number int) string {
+	if replicaSuffix.MatchString(name) {
+		return replicaSuffix.ReplaceAllString(name, "${1}"+strconv.Itoa(number))
+	}
+	return name + "-" + strconv.Itoa(number)
+}
+
+// AddReplica creates and starts replica number of a compose service, copying
+// the configuration of the template replica. Host ports are left for the
+// daemon to pick, since the template's fixed ports are already taken.
+// It returns the ID of the new container.
+func (c *Client) AddReplica(ctx context.Context, templateID string, number int) (string, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutMedium)
+		defer cancel()
+	}
+
+	inspect, err := c.inspectContainer(ctx, templateID)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect container: %w", err)
+	}
+	template := inspect.Container
+	if template.Config == nil || template.HostConfig == nil {
+		return "", fmt.Errorf("container %s has no configuration", templateID)
+	}
+
+	config := *template.Config
+	hostConfig := *template.HostConfig
+
+	// A hostname defaulted from the template's ID would be shared otherwise
+	if strings.HasPrefix(template.ID, config.Hostname) {
+		config.Hostname = ""
+	}
+
+	config.Labels = maps.Clone(config.Labels)
+	if config.Labels == nil {
+		config.Labels = make(map[string]string)
+	}
+	config.Labels[composeNumberLabel] = strconv.Itoa(number)
+
+	hostConfig.PortBindings = maps.Clone(hostConfig.PortBindings)
+	for port, bindings := range hostConfig.PortBindings {
+		ephemeral := make([]network.PortBinding, len(bindings))
+		for i, binding := range bindings {
+			ephemeral[i] = network.PortBinding{HostIP: binding.HostIP}
+		}
+		hostConfig.PortBindings[port] = ephemeral
+	}
+
+	name := replicaName(strings.TrimPrefix(template.Name, "/"), number)
+	created, err := c.cli.ContainerCreate(ctx, client.ContainerCreateOptions{
+		Config:           &config,
+		HostConfig:       &hostConfig,
+		NetworkingConfig: userNetworking(template.NetworkSettings, false),
+		Name:             name,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create replica: %w", err)
+	}
+
+	// A replica that can't start is removed rather than left behind
+	if _, err := c.cli.ContainerStart(ctx, created.ID, client.ContainerStartOptions{}); err != nil {
+		_, rmErr := c.cli.ContainerRemove(ctx, created.ID, client.ContainerRemoveOptions{Force: true})
+		return "", errors.Join(fmt.Errorf("failed to start replica %s: %w", name, err), rmErr)
+	}
+
+	return created.ID[:12], nil
+}
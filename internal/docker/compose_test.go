@@ -0,0 +1,53 @@
+package docker
+
+import (
+	"testing"
+
+	"tinyd/internal/types"
+)
+
+func TestGroupReplicas(t *testing.T) {
+	containers := []types.Container{
+		{ID: "1", Name: "shop-web-1", ComposeProject: "shop", ComposeService: "web", ComposeNumber: 1},
+		{ID: "2", Name: "standalone"},
+		{ID: "3", Name: "shop-db-1", ComposeProject: "shop", ComposeService: "db", ComposeNumber: 1},
+		{ID: "4", Name: "shop-web-2", ComposeProject: "shop", ComposeService: "web", ComposeNumber: 2},
+		{ID: "5", Name: "blog-web-1", ComposeProject: "blog", ComposeService: "web", ComposeNumber: 1},
+	}
+
+	grouped := GroupReplicas(containers)
+	if len(grouped) != 4 {
+		t.Fatalf("GroupReplicas() returned %d rows, want 4", len(grouped))
+	}
+	if grouped[0].ID != "1" || len(grouped[0].Replicas) != 1 || grouped[0].Replicas[0].ID != "4" {
+		t.Errorf("shop/web row = %+v, want 1 with replica 4", grouped[0])
+	}
+	for _, row := range grouped[1:] {
+		if len(row.Replicas) != 0 {
+			t.Errorf("row %s has replicas %v, want none", row.Name, row.Replicas)
+		}
+	}
+
+	replicas := ServiceReplicas(grouped[0])
+	if len(replicas) != 2 || replicas[0].ID != "1" || replicas[1].ID != "4" || replicas[0].Replicas != nil {
+		t.Errorf("ServiceReplicas() = %+v", replicas)
+	}
+}
+
+func TestReplicaName(t *testing.T) {
+	tests := []struct {
+		name   string
+		number int
+		want   string
+	}{
+		{"shop-web-1", 3, "shop-web-3"},
+		{"shop_web_1", 2, "shop_web_2"},
+		{"web", 2, "web-2"},
+	}
+
+	for _, tt := range tests {
+		if got := replicaName(tt.name, tt.number); got != tt.want {
+			t.Errorf("replicaName(%q, %d) = %q, want %q", tt.name, tt.number, got, tt.want)
+		}
+	}
+}
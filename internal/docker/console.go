@@ -0,0 +1,211 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/moby/moby/client"
+	"github.com/muesli/cancelreader"
+)
+
+// Shells are the interactive shells looked for in a container, in order of
+// preference
+var Shells = []string{"/bin/bash", "/bin/ash", "/bin/sh"}
+
+// resizePollInterval is how often an interactive exec checks the terminal
+// size. Polling works the same on every platform, unlike SIGWINCH.
+const resizePollInterval = 250 * time.Millisecond
+
+// DetectShell returns the first of Shells present in a container. Paths are
+// checked through the archive API, so the container needs no tools of its
+// own and no docker CLI is involved.
+func (c *Client) DetectShell(ctx context.Context, containerID string) (string, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutQuick)
+		defer cancel()
+	}
+
+	for _, shell := range Shells {
+		_, err := c.cli.ContainerStatPath(ctx, containerID, client.ContainerStatPathOptions{Path: shell})
+		if err == nil {
+			return shell, nil
+		}
+		if !cerrdefs.IsNotFound(err) {
+			return "", fmt.Errorf("failed to look for a shell: %w", err)
+		}
+	}
+	return "", fmt.Errorf("no shell found in container (tried %v)", Shells)
+}
+
+// ExecSession runs an interactive command in a container with a TTY, wired to
+// the local terminal. It implements Bubble Tea's ExecCommand, so tea.Exec can
+// hand it the terminal the same way as an external process.
+type ExecSession struct {
+	client      *Client
+	containerID string
+	cmd         []string
+	user        string
+	workdir     string
+	env         []string
+	stdin       io.Reader
+	stdout      io.Writer
+	stderr      io.Writer
+}
+
+// NewExecSession prepares an interactive exec of cmd in a running container
+func (c *Client) NewExecSession(containerID string, cmd []string) *ExecSession {
+	return &ExecSession{
+		client:      c,
+		containerID: containerID,
+		cmd:         cmd,
+		stdin:       os.Stdin,
+		stdout:      os.Stdout,
+		stderr:      os.Stderr,
+	}
+}
+
+// SetUser sets the user the command runs as, like `docker exec --user`
+func (s *ExecSession) SetUser(user string) { s.user = user }
+
+// SetWorkdir sets the directory the command starts in, like `docker exec
+// --workdir`
+func (s *ExecSession) SetWorkdir(dir string) { s.workdir = dir }
+
+// SetEnv adds KEY=value environment variables to the command's
+func (s *ExecSession) SetEnv(env []string) { s.env = env }
+
+// SetStdin sets the terminal input
+func (s *ExecSession) SetStdin(r io.Reader) { s.stdin = r }
+
+// SetStdout sets the terminal output
+func (s *ExecSession) SetStdout(w io.Writer) { s.stdout = w }
+
+// SetStderr sets where errors go; with a TTY the command's stderr is merged
+// into stdout, as in a local terminal
+func (s *ExecSession) SetStderr(w io.Writer) { s.stderr = w }
+
+// Run executes the command and relays input and output until it exits. The
+// local terminal is put in raw mode meanwhile, and size changes are passed on
+// to the container's TTY. A command that can't be started (exit code 126 or
+// 127) is reported as an error; other exit codes are the command's own.
+func (s *ExecSession) Run() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	inFd, inTerminal := terminalFd(s.stdin)
+	outFd, outTerminal := terminalFd(s.stdout)
+
+	var size client.ConsoleSize
+	if outTerminal {
+		if width, height, err := term.GetSize(outFd); err == nil {
+			size = client.ConsoleSize{Height: uint(height), Width: uint(width)}
+		}
+	}
+
+	exec, err := s.client.cli.ExecCreate(ctx, s.containerID, client.ExecCreateOptions{
+		Cmd:          s.cmd,
+		User:         s.user,
+		WorkingDir:   s.workdir,
+		Env:          s.env,
+		TTY:          true,
+		ConsoleSize:  size,
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create exec: %w", err)
+	}
+
+	attach, err := s.client.cli.ExecAttach(ctx, exec.ID, client.ExecAttachOptions{TTY: true, ConsoleSize: size})
+	if err != nil {
+		return fmt.Errorf("failed to attach to exec: %w", err)
+	}
+	defer attach.Close()
+
+	if inTerminal {
+		state, err := term.MakeRaw(inFd)
+		if err != nil {
+			return fmt.Errorf("failed to set terminal to raw mode: %w", err)
+		}
+		defer term.Restore(inFd, state)
+	}
+
+	if outTerminal {
+		go s.relayResizes(ctx, exec.ID, outFd, size)
+	}
+
+	// Input is read through a cancelable reader, so the copy stops when the
+	// command exits instead of swallowing the next key meant for tinyd
+	input, err := cancelreader.NewReader(s.stdin)
+	if err != nil {
+		return fmt.Errorf("failed to read terminal input: %w", err)
+	}
+	defer input.Close()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(attach.Conn, input)
+		_ = attach.CloseWrite()
+	}()
+
+	_, copyErr := io.Copy(s.stdout, attach.Reader)
+	input.Cancel()
+	wg.Wait()
+	if copyErr != nil && !errors.Is(copyErr, io.EOF) {
+		return fmt.Errorf("failed to read exec output: %w", copyErr)
+	}
+
+	inspect, err := s.client.cli.ExecInspect(context.Background(), exec.ID, client.ExecInspectOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to inspect exec: %w", err)
+	}
+	if inspect.ExitCode == 126 || inspect.ExitCode == 127 {
+		return fmt.Errorf("%s could not be started (exit code %d)", s.cmd[0], inspect.ExitCode)
+	}
+	return nil
+}
+
+// relayResizes resizes the exec's TTY whenever the local terminal changes
+// size, until ctx is cancelled
+func (s *ExecSession) relayResizes(ctx context.Context, execID string, fd uintptr, last client.ConsoleSize) {
+	ticker := time.NewTicker(resizePollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			width, height, err := term.GetSize(fd)
+			if err != nil {
+				continue
+			}
+			size := client.ConsoleSize{Height: uint(height), Width: uint(width)}
+			if size == last {
+				continue
+			}
+			last = size
+			_, _ = s.client.cli.ExecResize(ctx, execID, client.ExecResizeOptions{Height: size.Height, Width: size.Width})
+		}
+	}
+}
+
+// terminalFd returns the file descriptor behind a reader or writer and
+// whether it is a terminal
+func terminalFd(v any) (uintptr, bool) {
+	f, ok := v.(interface{ Fd() uintptr })
+	if !ok {
+		return 0, false
+	}
+	return f.Fd(), term.IsTerminal(f.Fd())
+}
@@ -0,0 +1,23 @@
+package docker
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestTerminalFd(t *testing.T) {
+	if _, ok := terminalFd(&bytes.Buffer{}); ok {
+		t.Error("terminalFd(buffer) reported a terminal")
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	if fd, ok := terminalFd(r); ok || fd != r.Fd() {
+		t.Errorf("terminalFd(pipe) = %d, %v, want %d, false", fd, ok, r.Fd())
+	}
+}
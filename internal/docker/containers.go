@@ -6,10 +6,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
-	"github.com/docker/go-units"
 	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/client"
 	"tinyd/internal/types"
@@ -24,7 +25,7 @@ func (c *Client) FetchContainers(ctx context.Context) ([]types.Container, error)
 	}
 
 	// List all containers (including stopped ones)
-	result, err := c.cli.ContainerList(ctx, client.ContainerListOptions{All: true})
+	result, err := c.listContainers(ctx, client.ContainerListOptions{All: true})
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			return nil, fmt.Errorf("operation timed out after %s", TimeoutQuick)
@@ -35,20 +36,27 @@ func (c *Client) FetchContainers(ctx context.Context) ([]types.Container, error)
 	containers := make([]types.Container, 0, len(result.Items))
 
 	for _, dockerContainer := range result.Items {
-		container := c.parseContainer(ctx, dockerContainer)
+		container := parseContainer(dockerContainer)
 		containers = append(containers, container)
 	}
+	c.fillRisks(ctx, result.Items, containers)
+	c.fillRestarts(ctx, result.Items, containers)
 
-	// Sort containers by status priority: RUNNING > PAUSED > ERROR > STOPPED
+	SortContainers(containers)
+
+	return containers, nil
+}
+
+// SortContainers orders containers by status priority: RUNNING > PAUSED > ERROR > STOPPED
+func SortContainers(containers []types.Container) {
 	sort.SliceStable(containers, func(i, j int) bool {
 		return getStatusPriority(containers[i].Status) < getStatusPriority(containers[j].Status)
 	})
-
-	return containers, nil
 }
 
-// parseContainer converts a Docker API container to our display type
-func (c *Client) parseContainer(ctx context.Context, dockerContainer container.Summary) types.Container {
+// parseContainer converts a Docker API container to our display type.
+// CPU and memory are left as "--"; live values come from the StatsStreamer.
+func parseContainer(dockerContainer container.Summary) types.Container {
 	// Format container name (remove leading /)
 	name := "unknown"
 	if len(dockerContainer.Names) > 0 {
@@ -56,7 +64,15 @@ func (c *Client) parseContainer(ctx context.Context, dockerContainer container.S
 	}
 
 	// Parse status
-	status := parseContainerStatus(string(dockerContainer.State), dockerContainer.Status)
+	exitCode := -1
+	uptime := ""
+	switch dockerContainer.State {
+	case container.StateExited, container.StateDead:
+		exitCode = exitCodeFromStatus(dockerContainer.Status)
+	case container.StateRunning, container.StatePaused:
+		uptime = uptimeFromStatus(dockerContainer.Status)
+	}
+	status := parseContainerStatus(string(dockerContainer.State), exitCode)
 
 	// Format image (shorten if too long)
 	img := formatImageName(dockerContainer.Image)
@@ -64,77 +80,39 @@ func (c *Client) parseContainer(ctx context.Context, dockerContainer container.S
 	// Format ports
 	ports := formatPorts(dockerContainer.Ports)
 
-	// Get stats for running containers
-	cpu := "--"
-	mem := "--"
-	if string(dockerContainer.State) == "running" {
-		cpu, mem, _ = c.fetchContainerStats(ctx, dockerContainer.ID)
-	}
-
 	// Format container ID
 	containerID := dockerContainer.ID
 	if len(containerID) > 12 {
 		containerID = containerID[:12]
 	}
 
+	number, _ := strconv.Atoi(dockerContainer.Labels[composeNumberLabel])
+
 	return types.Container{
 		ID:     containerID,
 		Name:   name,
 		Status: status,
-		CPU:    cpu,
-		Mem:    mem,
+		CPU:    "--",
+		Mem:    "--",
 		Image:  img,
 		Ports:  ports,
-	}
-}
 
-// fetchContainerStats retrieves CPU and memory stats for a container
-func (c *Client) fetchContainerStats(ctx context.Context, containerID string) (cpu string, mem string, err error) {
-	statsResp, err := c.cli.ContainerStats(ctx, containerID, client.ContainerStatsOptions{Stream: false})
-	if err != nil {
-		return "--", "--", err
-	}
-	defer statsResp.Body.Close()
+		ImageRef: dockerContainer.Image,
 
-	var statsJSON struct {
-		CPUStats struct {
-			CPUUsage struct {
-				TotalUsage  uint64   `json:"total_usage"`
-				PercpuUsage []uint64 `json:"percpu_usage"`
-			} `json:"cpu_usage"`
-			SystemUsage uint64 `json:"system_cpu_usage"`
-		} `json:"cpu_stats"`
-		PreCPUStats struct {
-			CPUUsage struct {
-				TotalUsage uint64 `json:"total_usage"`
-			} `json:"cpu_usage"`
-			SystemUsage uint64 `json:"system_cpu_usage"`
-		} `json:"precpu_stats"`
-		MemoryStats struct {
-			Usage uint64 `json:"usage"`
-		} `json:"memory_stats"`
-	}
+		ComposeProject: dockerContainer.Labels[composeProjectLabel],
+		ComposeService: dockerContainer.Labels[composeServiceLabel],
+		ComposeNumber:  number,
 
-	if err := json.NewDecoder(statsResp.Body).Decode(&statsJSON); err != nil {
-		return "--", "--", err
-	}
+		Volumes:  containerVolumes(dockerContainer.Mounts),
+		Networks: containerNetworks(dockerContainer.NetworkSettings),
 
-	// Calculate CPU percentage
-	cpu = "--"
-	cpuDelta := float64(statsJSON.CPUStats.CPUUsage.TotalUsage) - float64(statsJSON.PreCPUStats.CPUUsage.TotalUsage)
-	systemDelta := float64(statsJSON.CPUStats.SystemUsage) - float64(statsJSON.PreCPUStats.SystemUsage)
-	if systemDelta > 0.0 && cpuDelta > 0.0 && len(statsJSON.CPUStats.CPUUsage.PercpuUsage) > 0 {
-		cpuPercent := (cpuDelta / systemDelta) * float64(len(statsJSON.CPUStats.CPUUsage.PercpuUsage)) * 100.0
-		cpu = fmt.Sprintf("%.1f", cpuPercent)
-	}
+		Health: containerHealth(dockerContainer),
 
-	// Format memory
-	mem = "--"
-	if statsJSON.MemoryStats.Usage > 0 {
-		mem = units.BytesSize(float64(statsJSON.MemoryStats.Usage))
-	}
+		Uptime:   uptime,
+		ExitCode: exitCode,
 
-	return cpu, mem, nil
+		Labels: dockerContainer.Labels,
+	}
 }
 
 // StartContainer starts a container
@@ -175,6 +153,56 @@ func (c *Client) StopContainer(ctx context.Context, containerID string) error {
 	return nil
 }
 
+// PauseContainer suspends the processes of a running container
+func (c *Client) PauseContainer(ctx context.Context, containerID string) error {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutMedium)
+		defer cancel()
+	}
+
+	if _, err := c.cli.ContainerPause(ctx, containerID, client.ContainerPauseOptions{}); err != nil {
+		return fmt.Errorf("failed to pause container: %w", err)
+	}
+	return nil
+}
+
+// UnpauseContainer resumes the processes of a paused container
+func (c *Client) UnpauseContainer(ctx context.Context, containerID string) error {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutMedium)
+		defer cancel()
+	}
+
+	if _, err := c.cli.ContainerUnpause(ctx, containerID, client.ContainerUnpauseOptions{}); err != nil {
+		return fmt.Errorf("failed to unpause container: %w", err)
+	}
+	return nil
+}
+
+// CommitContainer saves a container's filesystem changes as a new image
+// tagged ref, e.g. "myapp:debug", with an optional comment and author like
+// `docker commit -m -a`. It returns the new image's short ID.
+func (c *Client) CommitContainer(ctx context.Context, containerID, ref, comment, author string) (string, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutLong)
+		defer cancel()
+	}
+
+	result, err := c.cli.ContainerCommit(ctx, containerID, client.ContainerCommitOptions{
+		Reference: ref,
+		Comment:   comment,
+		Author:    author,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to commit container: %w", err)
+	}
+	id := strings.TrimPrefix(result.ID, "sha256:")
+	return id[:min(len(id), 12)], nil
+}
+
 // RestartContainer restarts a container
 func (c *Client) RestartContainer(ctx context.Context, containerID string) error {
 	if ctx == nil {
@@ -215,8 +243,68 @@ func (c *Client) DeleteContainer(ctx context.Context, containerID string, force
 	return nil
 }
 
-// GetContainerLogs retrieves container logs
-func (c *Client) GetContainerLogs(ctx context.Context, containerID string, tailLines string) (string, error) {
+// SignalContainer sends a signal (e.g. SIGHUP) to the container's main process
+func (c *Client) SignalContainer(ctx context.Context, containerID string, signal string) error {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutQuick)
+		defer cancel()
+	}
+
+	_, err := c.cli.ContainerKill(ctx, containerID, client.ContainerKillOptions{Signal: signal})
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("signal operation timed out after %s", TimeoutQuick)
+		}
+		return fmt.Errorf("failed to send %s: %w", signal, err)
+	}
+	return nil
+}
+
+// GetBindMounts returns the host bind mounts of a container
+func (c *Client) GetBindMounts(ctx context.Context, containerID string) ([]types.BindMount, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutQuick)
+		defer cancel()
+	}
+
+	inspectResult, err := c.inspectContainer(ctx, containerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect: %w", err)
+	}
+
+	var mounts []types.BindMount
+	for _, mount := range inspectResult.Container.Mounts {
+		if mount.Type != "bind" {
+			continue
+		}
+		mounts = append(mounts, types.BindMount{
+			Source:      mount.Source,
+			Destination: mount.Destination,
+		})
+	}
+
+	return mounts, nil
+}
+
+// LocalHost reports whether a daemon host is reached through a local socket,
+// so that the bind mount sources of its containers are paths of this machine
+func LocalHost(host string) bool {
+	return strings.HasPrefix(host, "unix://") || strings.HasPrefix(host, "npipe://")
+}
+
+// GetContainerLogs retrieves container logs, limited to a tail and/or a time
+// window, with stdout and stderr interleaved as written
+func (c *Client) GetContainerLogs(ctx context.Context, containerID string, opts LogsOptions) (string, error) {
+	logs, _, err := c.GetContainerLogStreams(ctx, containerID, opts)
+	return logs, err
+}
+
+// GetContainerLogStreams is GetContainerLogs that also reports, for each
+// line of the logs, whether it was written to stderr. Containers with a TTY
+// have a single stream, so none of their lines are flagged.
+func (c *Client) GetContainerLogStreams(ctx context.Context, containerID string, opts LogsOptions) (string, []bool, error) {
 	if ctx == nil {
 		var cancel context.CancelFunc
 		ctx, cancel = c.WithCustomTimeout(TimeoutQuick)
@@ -226,21 +314,25 @@ func (c *Client) GetContainerLogs(ctx context.Context, containerID string, tailL
 	options := client.ContainerLogsOptions{
 		ShowStdout: true,
 		ShowStderr: true,
-		Tail:       tailLines,
+		Tail:       opts.Tail,
+		Since:      apiTimestamp(opts.Since),
+		Until:      apiTimestamp(opts.Until),
+		Timestamps: opts.Timestamps,
 	}
 
 	logs, err := c.cli.ContainerLogs(ctx, containerID, options)
 	if err != nil {
-		return "", fmt.Errorf("failed to get logs: %w", err)
+		return "", nil, fmt.Errorf("failed to get logs: %w", err)
 	}
 	defer logs.Close()
 
 	logBytes, err := io.ReadAll(logs)
 	if err != nil {
-		return "", fmt.Errorf("failed to read logs: %w", err)
+		return "", nil, fmt.Errorf("failed to read logs: %w", err)
 	}
 
-	return string(logBytes), nil
+	text, stderr := demuxLogStreams(logBytes)
+	return text, stderr, nil
 }
 
 // InspectContainer retrieves detailed container information as JSON
@@ -251,7 +343,7 @@ func (c *Client) InspectContainer(ctx context.Context, containerID string) (stri
 		defer cancel()
 	}
 
-	inspectResult, err := c.cli.ContainerInspect(ctx, containerID, client.ContainerInspectOptions{})
+	inspectResult, err := c.inspectContainer(ctx, containerID)
 	if err != nil {
 		return "", fmt.Errorf("failed to inspect: %w", err)
 	}
@@ -267,7 +359,10 @@ func (c *Client) InspectContainer(ctx context.Context, containerID string) (stri
 
 // Helper functions
 
-func parseContainerStatus(state, status string) string {
+// parseContainerStatus maps a container state to the status shown. Exited
+// and dead containers are ERROR when their exit code is non-zero; -1 is an
+// unknown exit code.
+func parseContainerStatus(state string, exitCode int) string {
 	s := "STOPPED"
 	if string(state) == "running" {
 		s = "RUNNING"
@@ -275,20 +370,130 @@ func parseContainerStatus(state, status string) string {
 		s = "PAUSED"
 	} else if string(state) == "restarting" {
 		s = "RESTARTING"
-	} else if string(state) == "dead" || string(state) == "exited" {
-		// Check exit code for errors
-		if status != "" && strings.Contains(strings.ToLower(status), "error") {
-			s = "ERROR"
-		} else if status != "" && (strings.Contains(strings.ToLower(status), "exit") || strings.Contains(status, "(")) {
-			// Check for non-zero exit codes in format "Exited (1)" or "Exited (42)"
-			if strings.Contains(status, "Exited (") && !strings.Contains(status, "Exited (0)") {
-				s = "ERROR"
-			}
-		}
+	} else if (string(state) == "dead" || string(state) == "exited") && exitCode > 0 {
+		s = "ERROR"
 	}
 	return s
 }
 
+// exitCodeFromStatus reads the exit code of an exited container from the
+// status the daemon lists, e.g. "Exited (137) 5 minutes ago", or returns -1
+func exitCodeFromStatus(status string) int {
+	rest, ok := strings.CutPrefix(status, "Exited (")
+	if !ok {
+		return -1
+	}
+	code, _, ok := strings.Cut(rest, ")")
+	if !ok {
+		return -1
+	}
+	n, err := strconv.Atoi(code)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// uptimeUnits shortens the units of the daemon's humanized durations
+var uptimeUnits = map[string]string{
+	"second": "s", "seconds": "s",
+	"minute": "m", "minutes": "m",
+	"hour": "h", "hours": "h",
+	"day": "d", "days": "d",
+	"week": "w", "weeks": "w",
+	"month": "mo", "months": "mo",
+	"year": "y", "years": "y",
+}
+
+// uptimeFromStatus shortens the uptime of a running container from the
+// status the daemon lists, e.g. "Up 5 minutes (healthy)" to "5m" and "Up
+// About an hour" to "1h". Statuses it can't read are returned without the
+// "Up " prefix.
+func uptimeFromStatus(status string) string {
+	uptime, ok := strings.CutPrefix(status, "Up ")
+	if !ok {
+		return ""
+	}
+	if i := strings.Index(uptime, " ("); i >= 0 {
+		uptime = uptime[:i] // (healthy), (Paused)
+	}
+
+	switch uptime {
+	case "Less than a second":
+		return "<1s"
+	case "About a minute":
+		return "1m"
+	case "About an hour":
+		return "1h"
+	}
+	n, unit, ok := strings.Cut(uptime, " ")
+	if short, known := uptimeUnits[unit]; ok && known {
+		if _, err := strconv.Atoi(n); err == nil {
+			return n + short
+		}
+	}
+	return uptime
+}
+
+// ImageRepository returns the repository of an image reference, without its
+// tag or digest; image IDs are returned as they are
+func ImageRepository(ref string) string {
+	if strings.HasPrefix(ref, "sha256:") {
+		return ref
+	}
+	ref, _, _ = strings.Cut(ref, "@")
+	repo, _ := splitRepoTag(ref)
+	return repo
+}
+
+// ImagesInUse lists the image repositories the containers were created
+// from, the most used first
+func ImagesInUse(containers []types.Container) []types.ImageUse {
+	byRepo := make(map[string]*types.ImageUse)
+	var uses []*types.ImageUse
+	for _, c := range containers {
+		repo := ImageRepository(c.ImageRef)
+		use, ok := byRepo[repo]
+		if !ok {
+			use = &types.ImageUse{Repository: repo}
+			byRepo[repo] = use
+			uses = append(uses, use)
+		}
+		use.Containers++
+		if repo == c.ImageRef {
+			continue // No tag, or an image ID
+		}
+		ref, _, _ := strings.Cut(c.ImageRef, "@")
+		if _, tag := splitRepoTag(ref); tag != "" && !slices.Contains(use.Tags, tag) {
+			use.Tags = append(use.Tags, tag)
+		}
+	}
+
+	result := make([]types.ImageUse, len(uses))
+	for i, use := range uses {
+		sort.Strings(use.Tags)
+		result[i] = *use
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Containers != result[j].Containers {
+			return result[i].Containers > result[j].Containers
+		}
+		return result[i].Repository < result[j].Repository
+	})
+	return result
+}
+
+// ContainersOfImage keeps the containers created from any tag of repository
+func ContainersOfImage(containers []types.Container, repository string) []types.Container {
+	var filtered []types.Container
+	for _, c := range containers {
+		if ImageRepository(c.ImageRef) == repository {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
 func formatImageName(img string) string {
 	if len(img) > 17 {
 		parts := strings.Split(img, ":")
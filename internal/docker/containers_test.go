@@ -1,9 +1,11 @@
 package docker
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/moby/moby/api/types/container"
+	"tinyd/internal/types"
 )
 
 func TestGetStatusPriority(t *testing.T) {
@@ -33,29 +35,72 @@ func TestParseContainerStatus(t *testing.T) {
 	tests := []struct {
 		name     string
 		state    string
-		status   string
+		exitCode int
 		expected string
 	}{
-		{"running container", "running", "", "RUNNING"},
-		{"paused container", "paused", "", "PAUSED"},
-		{"stopped container", "exited", "", "STOPPED"},
-		{"error container", "exited", "Error: exit code 1", "ERROR"},
-		{"restarting container", "restarting", "", "STOPPED"},
-		{"dead container", "dead", "", "STOPPED"},
-		{"unknown state", "unknown", "", "STOPPED"},
+		{"running container", "running", -1, "RUNNING"},
+		{"paused container", "paused", -1, "PAUSED"},
+		{"stopped container", "exited", 0, "STOPPED"},
+		{"error container", "exited", 1, "ERROR"},
+		{"killed container", "exited", 137, "ERROR"},
+		{"unknown exit code", "exited", -1, "STOPPED"},
+		{"restarting container", "restarting", -1, "RESTARTING"},
+		{"dead container", "dead", -1, "STOPPED"},
+		{"created container", "created", -1, "STOPPED"},
+		{"unknown state", "unknown", -1, "STOPPED"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := parseContainerStatus(tt.state, tt.status)
+			result := parseContainerStatus(tt.state, tt.exitCode)
 			if result != tt.expected {
-				t.Errorf("parseContainerStatus(%q, %q) = %q, want %q",
-					tt.state, tt.status, result, tt.expected)
+				t.Errorf("parseContainerStatus(%q, %d) = %q, want %q",
+					tt.state, tt.exitCode, result, tt.expected)
 			}
 		})
 	}
 }
 
+func TestExitCodeFromStatus(t *testing.T) {
+	tests := []struct {
+		status string
+		want   int
+	}{
+		{"Exited (0) 5 minutes ago", 0},
+		{"Exited (137) About an hour ago", 137},
+		{"Exited (255) 2 days ago", 255},
+		{"Up 5 minutes", -1},
+		{"Created", -1},
+		{"", -1},
+	}
+	for _, tt := range tests {
+		if got := exitCodeFromStatus(tt.status); got != tt.want {
+			t.Errorf("exitCodeFromStatus(%q) = %d, want %d", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestUptimeFromStatus(t *testing.T) {
+	tests := []struct {
+		status string
+		want   string
+	}{
+		{"Up 5 minutes", "5m"},
+		{"Up 3 days (healthy)", "3d"},
+		{"Up About an hour", "1h"},
+		{"Up Less than a second", "<1s"},
+		{"Up 2 weeks (Paused)", "2w"},
+		{"Up 4 months", "4mo"},
+		{"Up forever", "forever"},
+		{"Exited (0) 5 minutes ago", ""},
+	}
+	for _, tt := range tests {
+		if got := uptimeFromStatus(tt.status); got != tt.want {
+			t.Errorf("uptimeFromStatus(%q) = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+}
+
 func TestFormatImageName(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -154,3 +199,44 @@ func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) &&
 		(s[:len(substr)] == substr || contains(s[1:], substr)))
 }
+
+func TestImagesInUse(t *testing.T) {
+	containers := []types.Container{
+		{Name: "db1", ImageRef: "postgres:15"},
+		{Name: "web", ImageRef: "nginx"},
+		{Name: "db2", ImageRef: "postgres:16"},
+		{Name: "db3", ImageRef: "postgres:15"},
+		{Name: "app", ImageRef: "registry:5000/team/app@sha256:0123"},
+		{Name: "tmp", ImageRef: "sha256:4567"},
+	}
+
+	got := ImagesInUse(containers)
+	want := []types.ImageUse{
+		{Repository: "postgres", Tags: []string{"15", "16"}, Containers: 3},
+		{Repository: "nginx", Containers: 1},
+		{Repository: "registry:5000/team/app", Containers: 1},
+		{Repository: "sha256:4567", Containers: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ImagesInUse() = %+v, want %+v", got, want)
+	}
+
+	filtered := ContainersOfImage(containers, "postgres")
+	if len(filtered) != 3 || filtered[0].Name != "db1" || filtered[2].Name != "db3" {
+		t.Errorf("ContainersOfImage(postgres) = %+v", filtered)
+	}
+}
+
+func TestLocalHost(t *testing.T) {
+	tests := map[string]bool{
+		"unix:///var/run/docker.sock":    true,
+		"npipe:////./pipe/docker_engine": true,
+		"tcp://10.0.0.5:2376":            false,
+		"ssh://deploy@build-box":         false,
+	}
+	for host, want := range tests {
+		if got := LocalHost(host); got != want {
+			t.Errorf("LocalHost(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
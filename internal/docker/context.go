@@ -0,0 +1,101 @@
+package docker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// Endpoint returns the daemon endpoint the docker CLI would use: DOCKER_HOST
+// if set, otherwise the host of the active docker context. It returns an
+// empty string for the default local socket.
+func Endpoint() string {
+	if host := os.Getenv("DOCKER_HOST"); host != "" {
+		return host
+	}
+
+	configDir := configDir()
+	if configDir == "" {
+		return ""
+	}
+
+	contextName := os.Getenv("DOCKER_CONTEXT")
+	if contextName == "" {
+		var config struct {
+			CurrentContext string `json:"currentContext"`
+		}
+		data, err := os.ReadFile(filepath.Join(configDir, "config.json"))
+		if err != nil || json.Unmarshal(data, &config) != nil {
+			return ""
+		}
+		contextName = config.CurrentContext
+	}
+	if contextName == "" || contextName == "default" {
+		return ""
+	}
+
+	// Context metadata lives in a directory named after the digest of its name
+	digest := sha256.Sum256([]byte(contextName))
+	metaPath := filepath.Join(configDir, "contexts", "meta", hex.EncodeToString(digest[:]), "meta.json")
+
+	var meta struct {
+		Endpoints map[string]struct {
+			Host string `json:"Host"`
+		} `json:"Endpoints"`
+	}
+	data, err := os.ReadFile(metaPath)
+	if err != nil || json.Unmarshal(data, &meta) != nil {
+		return ""
+	}
+	return meta.Endpoints["docker"].Host
+}
+
+// configDir returns the directory of the docker CLI's config.json,
+// DOCKER_CONFIG or ~/.docker. It returns "" when there is no home directory.
+func configDir() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".docker")
+}
+
+// CLIFlags returns the global docker CLI flags that reach the daemon at host
+// the way tinyd does, with the TLS settings of DOCKER_CERT_PATH and
+// DOCKER_TLS_VERIFY, for commands run where tinyd's environment isn't
+// inherited, such as a new macOS Terminal window
+func CLIFlags(host string) []string {
+	flags := []string{"-H", host}
+	if os.Getenv("DOCKER_TLS_VERIFY") != "" {
+		flags = append(flags, "--tlsverify")
+	}
+	if dir := os.Getenv("DOCKER_CERT_PATH"); dir != "" {
+		flags = append(flags,
+			"--tlscacert", filepath.Join(dir, "ca.pem"),
+			"--tlscert", filepath.Join(dir, "cert.pem"),
+			"--tlskey", filepath.Join(dir, "key.pem"),
+		)
+	}
+	return flags
+}
+
+// SSHDestination extracts the ssh destination ("user@host") and port from an
+// ssh:// endpoint. ok is false for any other kind of endpoint.
+func SSHDestination(endpoint string) (destination string, port string, ok bool) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme != "ssh" || u.Hostname() == "" {
+		return "", "", false
+	}
+
+	destination = u.Hostname()
+	if u.User != nil && u.User.Username() != "" {
+		destination = u.User.Username() + "@" + destination
+	}
+	return destination, u.Port(), true
+}
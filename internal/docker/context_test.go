@@ -0,0 +1,78 @@
+package docker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCLIFlags(t *testing.T) {
+	t.Setenv("DOCKER_TLS_VERIFY", "")
+	t.Setenv("DOCKER_CERT_PATH", "")
+	if got, want := CLIFlags("unix:///var/run/docker.sock"), []string{"-H", "unix:///var/run/docker.sock"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("CLIFlags() = %q, want %q", got, want)
+	}
+
+	t.Setenv("DOCKER_TLS_VERIFY", "1")
+	t.Setenv("DOCKER_CERT_PATH", "/certs")
+	want := []string{"-H", "tcp://10.0.0.5:2376", "--tlsverify",
+		"--tlscacert", filepath.Join("/certs", "ca.pem"),
+		"--tlscert", filepath.Join("/certs", "cert.pem"),
+		"--tlskey", filepath.Join("/certs", "key.pem")}
+	if got := CLIFlags("tcp://10.0.0.5:2376"); !reflect.DeepEqual(got, want) {
+		t.Errorf("CLIFlags() with TLS = %q, want %q", got, want)
+	}
+}
+
+func TestSSHDestination(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		dest     string
+		port     string
+		ok       bool
+	}{
+		{"ssh://deploy@build-01:2222", "deploy@build-01", "2222", true},
+		{"ssh://build-01", "build-01", "", true},
+		{"unix:///var/run/docker.sock", "", "", false},
+		{"tcp://10.0.0.5:2376", "", "", false},
+		{"", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.endpoint, func(t *testing.T) {
+			dest, port, ok := SSHDestination(tt.endpoint)
+			if dest != tt.dest || port != tt.port || ok != tt.ok {
+				t.Errorf("SSHDestination(%q) = (%q, %q, %t), want (%q, %q, %t)",
+					tt.endpoint, dest, port, ok, tt.dest, tt.port, tt.ok)
+			}
+		})
+	}
+}
+
+func TestEndpointFromContext(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("DOCKER_HOST", "")
+	t.Setenv("DOCKER_CONTEXT", "")
+	t.Setenv("DOCKER_CONFIG", configDir)
+
+	os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"currentContext":"remote"}`), 0o644)
+
+	digest := sha256.Sum256([]byte("remote"))
+	metaDir := filepath.Join(configDir, "contexts", "meta", hex.EncodeToString(digest[:]))
+	os.MkdirAll(metaDir, 0o755)
+	os.WriteFile(filepath.Join(metaDir, "meta.json"),
+		[]byte(`{"Name":"remote","Endpoints":{"docker":{"Host":"ssh://me@box"}}}`), 0o644)
+
+	if got := Endpoint(); got != "ssh://me@box" {
+		t.Errorf("Endpoint() = %q, want %q", got, "ssh://me@box")
+	}
+
+	// DOCKER_HOST takes precedence over the context
+	t.Setenv("DOCKER_HOST", "tcp://localhost:2375")
+	if got := Endpoint(); got != "tcp://localhost:2375" {
+		t.Errorf("Endpoint() = %q, want %q", got, "tcp://localhost:2375")
+	}
+}
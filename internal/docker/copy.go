@@ -0,0 +1,224 @@
+package docker
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/moby/moby/client"
+)
+
+// CopyFromContainer copies a file or directory of a container to the host,
+// as `docker cp container:src dest` does: into dest when it is an existing
+// directory, else to dest itself. progress is called about once a second
+// with the amount copied.
+func (c *Client) CopyFromContainer(ctx context.Context, containerID, src, dest string, progress func(string)) error {
+	if ctx == nil {
+		// Copies take as long as the data needs, so there is no deadline
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(context.Background())
+		defer cancel()
+	}
+
+	copied, err := c.cli.CopyFromContainer(ctx, containerID, client.CopyFromContainerOptions{SourcePath: src})
+	if err != nil {
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+	defer copied.Content.Close()
+
+	// The archive holds src under its base name; it keeps that name inside
+	// an existing directory and is renamed to dest otherwise
+	root := dest
+	if info, err := os.Stat(dest); err == nil && info.IsDir() {
+		root = filepath.Join(dest, copied.Stat.Name)
+	}
+
+	var estimate int64
+	if !copied.Stat.Mode.IsDir() {
+		estimate = copied.Stat.Size
+	}
+	var written atomic.Int64
+	defer reportProgress(&written, estimate, progress)()
+
+	return extractTar(io.TeeReader(copied.Content, countWriter{&written}), copied.Stat.Name, root)
+}
+
+// CopyToContainer copies a file or directory of the host into a container,
+// as `docker cp src container:dest` does: into dest when it is an existing
+// directory, else to dest itself. progress is called about once a second
+// with the amount copied.
+func (c *Client) CopyToContainer(ctx context.Context, containerID, src, dest string, progress func(string)) error {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(context.Background())
+		defer cancel()
+	}
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", src, err)
+	}
+
+	dir, name := path.Dir(dest), path.Base(dest)
+	if stat, err := c.cli.ContainerStatPath(ctx, containerID, client.ContainerStatPathOptions{Path: dest}); err == nil && stat.Stat.Mode.IsDir() {
+		dir, name = dest, filepath.Base(src)
+	}
+
+	var estimate int64
+	if !info.IsDir() {
+		estimate = info.Size()
+	}
+	var written atomic.Int64
+	defer reportProgress(&written, estimate, progress)()
+
+	// Stream the archive as it is written
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(writeTar(countingWriter{writer, &written}, src, name))
+	}()
+	defer reader.Close()
+
+	if _, err := c.cli.CopyToContainer(ctx, containerID, client.CopyToContainerOptions{DestinationPath: dir, Content: reader}); err != nil {
+		return fmt.Errorf("failed to copy to %s: %w", dest, err)
+	}
+	return nil
+}
+
+// reportProgress calls progress about once a second with the amount
+// written against estimate, until the returned stop is called
+func reportProgress(written *atomic.Int64, estimate int64, progress func(string)) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				progress(copyProgress(written.Load(), estimate))
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// countingWriter passes writes on to w, adding up the bytes written
+type countingWriter struct {
+	w io.Writer
+	n *atomic.Int64
+}
+
+func (w countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n.Add(int64(n))
+	return n, err
+}
+
+// extractTar writes a tar stream whose entries sit under name to root,
+// e.g. "logs/app.log" to root/app.log. Entries outside name are skipped.
+func extractTar(r io.Reader, name, root string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		// Cleaning resolves "..", so what stays under name stays under root
+		rel, ok := strings.CutPrefix(path.Clean(hdr.Name), name)
+		if !ok || (rel != "" && !strings.HasPrefix(rel, "/")) {
+			continue
+		}
+		target := filepath.Join(root, filepath.FromSlash(rel))
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, fs.FileMode(hdr.Mode)&fs.ModePerm|0o700); err != nil {
+				return fmt.Errorf("failed to create %s: %w", target, err)
+			}
+		case tar.TypeReg:
+			if err := writeFile(target, tr, fs.FileMode(hdr.Mode)&fs.ModePerm); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			_ = os.Remove(target)
+			if err := os.Symlink(hdr.Linkname, target); err != nil {
+				return fmt.Errorf("failed to create %s: %w", target, err)
+			}
+		}
+	}
+}
+
+// writeFile writes r to a new file at path, creating its directory
+func writeFile(path string, r io.Reader, mode fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// writeTar archives the file or directory src under name
+func writeTar(w io.Writer, src, name string) error {
+	tw := tar.NewWriter(w)
+	err := filepath.WalkDir(src, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		link := ""
+		if info.Mode()&fs.ModeSymlink != 0 {
+			if link, err = os.Readlink(file); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, file)
+		if err != nil {
+			return err
+		}
+		hdr.Name = path.Join(name, filepath.ToSlash(rel))
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to archive %s: %w", src, err)
+	}
+	return tw.Close()
+}
@@ -0,0 +1,60 @@
+package docker
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTarRoundTrip(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "logs")
+	if err := os.MkdirAll(filepath.Join(src, "old"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "app.log"), []byte("started"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "old", "app.1.log"), []byte("stopped"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Archived under a new name, as when copying to a path that doesn't exist
+	var archive bytes.Buffer
+	if err := writeTar(&archive, src, "backup"); err != nil {
+		t.Fatalf("writeTar() error: %v", err)
+	}
+	dest := filepath.Join(t.TempDir(), "restored")
+	if err := extractTar(&archive, "backup", dest); err != nil {
+		t.Fatalf("extractTar() error: %v", err)
+	}
+
+	for file, want := range map[string]string{"app.log": "started", "old/app.1.log": "stopped"} {
+		got, err := os.ReadFile(filepath.Join(dest, file))
+		if err != nil || string(got) != want {
+			t.Errorf("%s = %q, %v; want %q", file, got, err, want)
+		}
+	}
+	if info, err := os.Stat(filepath.Join(dest, "old", "app.1.log")); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("mode of old/app.1.log = %v, %v; want 0600", info.Mode().Perm(), err)
+	}
+}
+
+func TestExtractTarSkipsEscapes(t *testing.T) {
+	var archive bytes.Buffer
+	src := filepath.Join(t.TempDir(), "f")
+	if err := os.WriteFile(src, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeTar(&archive, src, "data/../../escaped"); err != nil {
+		t.Fatal(err)
+	}
+
+	root := filepath.Join(t.TempDir(), "root")
+	if err := extractTar(&archive, "data", root); err != nil {
+		t.Fatalf("extractTar() error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(root), "escaped")); err == nil {
+		t.Error("extractTar() wrote outside of root")
+	}
+}
@@ -0,0 +1,48 @@
+package docker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// CredentialHelpers returns the credential helpers config.json names, the
+// default credsStore and any per-registry credHelpers, without duplicates.
+// Each is a docker-credential-<name> binary the docker CLI runs to get
+// registry logins. A missing config.json has none.
+func CredentialHelpers() ([]string, error) {
+	dir := configDir()
+	if dir == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var config struct {
+		CredsStore  string            `json:"credsStore"`
+		CredHelpers map[string]string `json:"credHelpers"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", filepath.Join(dir, "config.json"), err)
+	}
+
+	var helpers []string
+	if config.CredsStore != "" {
+		helpers = append(helpers, config.CredsStore)
+	}
+	for _, helper := range config.CredHelpers {
+		if helper != "" && !slices.Contains(helpers, helper) {
+			helpers = append(helpers, helper)
+		}
+	}
+	slices.Sort(helpers[min(len(helpers), 1):])
+	return helpers, nil
+}
@@ -0,0 +1,33 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestCredentialHelpers(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", configDir)
+
+	if helpers, err := CredentialHelpers(); err != nil || helpers != nil {
+		t.Errorf("CredentialHelpers() without config.json = %v, %v", helpers, err)
+	}
+
+	config := `{"credsStore": "desktop", "credHelpers": {"gcr.io": "gcloud", "eu.gcr.io": "gcloud", "123.dkr.ecr.eu-west-1.amazonaws.com": "ecr-login", "quay.io": "desktop"}}`
+	if err := os.WriteFile(filepath.Join(configDir, "config.json"), []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	helpers, err := CredentialHelpers()
+	if want := []string{"desktop", "ecr-login", "gcloud"}; err != nil || !slices.Equal(helpers, want) {
+		t.Errorf("CredentialHelpers() = %v, %v, want %v", helpers, err, want)
+	}
+
+	if err := os.WriteFile(filepath.Join(configDir, "config.json"), []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CredentialHelpers(); err == nil {
+		t.Error("CredentialHelpers() with an invalid config.json should fail")
+	}
+}
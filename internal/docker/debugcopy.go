@@ -0,0 +1,108 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+)
+
+// DebugCopyLabel marks throwaway debug copies with the name of the container
+// they reproduce
+const DebugCopyLabel = "tinyd.debug-copy-of"
+
+// StartDebugCopy creates and starts a throwaway container that reproduces a
+// stopped or crashed container's environment (image, mounts, environment,
+// user, working directory and networks) with its entrypoint replaced by an
+// idle shell to exec into. Published ports, the restart policy and the
+// healthcheck are dropped so the copy can't clash with or mimic the original.
+// It returns the copy's ID and the shell found in the container.
+func (c *Client) StartDebugCopy(ctx context.Context, containerID string) (string, string, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutMedium)
+		defer cancel()
+	}
+
+	inspect, err := c.inspectContainer(ctx, containerID)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to inspect container: %w", err)
+	}
+	original := inspect.Container
+	if original.Config == nil || original.HostConfig == nil {
+		return "", "", fmt.Errorf("container %s has no configuration", containerID)
+	}
+	name := strings.TrimPrefix(original.Name, "/")
+
+	// The original's filesystem has the same shells as the copy will
+	shell, err := c.DetectShell(ctx, original.ID)
+	if err != nil {
+		return "", "", err
+	}
+
+	config := *original.Config
+	hostConfig := *original.HostConfig
+	debugConfig(&config, &hostConfig, shell, name)
+	if strings.HasPrefix(original.ID, config.Hostname) {
+		config.Hostname = ""
+	}
+
+	created, err := c.cli.ContainerCreate(ctx, client.ContainerCreateOptions{
+		Config:           &config,
+		HostConfig:       &hostConfig,
+		NetworkingConfig: userNetworking(original.NetworkSettings, false),
+		Name:             fmt.Sprintf("%s-tinyd-debug-%d", name, time.Now().Unix()),
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create debug copy: %w", err)
+	}
+
+	if _, err := c.cli.ContainerStart(ctx, created.ID, client.ContainerStartOptions{}); err != nil {
+		c.RemoveDebugCopy(created.ID)
+		return "", "", fmt.Errorf("failed to start debug copy: %w", err)
+	}
+	return created.ID, shell, nil
+}
+
+// debugConfig turns a copy of a container's configuration into that of a
+// debug copy. The shell is started with an open stdin and a TTY, which keeps
+// it waiting for input, so the copy stays up without any other tool.
+func debugConfig(config *container.Config, hostConfig *container.HostConfig, shell, name string) {
+	config.Entrypoint = []string{shell}
+	config.Cmd = nil
+	config.Tty = true
+	config.OpenStdin = true
+	config.Healthcheck = &container.HealthConfig{Test: []string{"NONE"}}
+	config.ExposedPorts = nil
+	config.Labels = maps.Clone(config.Labels)
+	if config.Labels == nil {
+		config.Labels = make(map[string]string)
+	}
+	config.Labels[DebugCopyLabel] = name
+	// Compose would otherwise count the copy as a replica of the service
+	for key := range config.Labels {
+		if strings.HasPrefix(key, "com.docker.compose.") {
+			delete(config.Labels, key)
+		}
+	}
+
+	hostConfig.PortBindings = nil
+	hostConfig.PublishAllPorts = false
+	hostConfig.RestartPolicy = container.RestartPolicy{Name: container.RestartPolicyDisabled}
+	hostConfig.AutoRemove = false
+}
+
+// RemoveDebugCopy removes a debug copy, even when the caller's context has
+// expired
+func (c *Client) RemoveDebugCopy(containerID string) error {
+	ctx, cancel := c.WithCustomTimeout(TimeoutMedium)
+	defer cancel()
+	if _, err := c.cli.ContainerRemove(ctx, containerID, client.ContainerRemoveOptions{Force: true}); err != nil {
+		return fmt.Errorf("failed to remove debug copy: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/network"
+)
+
+func TestDebugConfig(t *testing.T) {
+	labels := map[string]string{"app": "web", "com.docker.compose.service": "web"}
+	config := &container.Config{
+		Entrypoint: []string{"/docker-entrypoint.sh"},
+		Cmd:        []string{"nginx"},
+		Env:        []string{"A=1"},
+		Labels:     labels,
+	}
+	hostConfig := &container.HostConfig{
+		Binds:         []string{"/srv:/srv"},
+		PortBindings:  network.PortMap{},
+		RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyAlways},
+	}
+
+	debugConfig(config, hostConfig, "/bin/sh", "web-1")
+
+	if len(config.Entrypoint) != 1 || config.Entrypoint[0] != "/bin/sh" || config.Cmd != nil {
+		t.Errorf("entrypoint = %v, cmd = %v, want the shell alone", config.Entrypoint, config.Cmd)
+	}
+	if !config.Tty || !config.OpenStdin {
+		t.Error("shell needs a TTY and open stdin to stay up")
+	}
+	if config.Labels[DebugCopyLabel] != "web-1" || config.Labels["app"] != "web" {
+		t.Errorf("labels = %v, want debug label and app kept", config.Labels)
+	}
+	if _, ok := config.Labels["com.docker.compose.service"]; ok {
+		t.Error("compose labels kept on the copy")
+	}
+	if _, ok := labels[DebugCopyLabel]; ok {
+		t.Error("original labels were modified")
+	}
+	if hostConfig.PortBindings != nil || hostConfig.RestartPolicy.Name != container.RestartPolicyDisabled {
+		t.Errorf("ports = %v, restart = %v, want none", hostConfig.PortBindings, hostConfig.RestartPolicy.Name)
+	}
+	if len(config.Env) != 1 || len(hostConfig.Binds) != 1 {
+		t.Error("environment and mounts must be kept")
+	}
+}
@@ -0,0 +1,119 @@
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Diagnosis explains a failed connection to the daemon
+type Diagnosis struct {
+	Host     string   // Endpoint tried, e.g. "unix:///var/run/docker.sock"
+	Rootless bool     // Whether the endpoint is a rootless daemon's socket
+	Fixes    []string // Suggestions, most likely first
+}
+
+// socketAccess is what is known about the current user's access to a socket
+type socketAccess struct {
+	path         string
+	group        string // Group owning the socket, "" if unknown
+	held         bool   // This process has the group
+	assigned     bool   // The user is in the group per the group database
+	rootlessPath string // Socket of the user's rootless daemon, "" if none
+}
+
+// DiagnoseConnection looks into a failed connection to the daemon at host
+// (as reported by DaemonHost). Only permission errors on a unix socket are
+// diagnosed: the user may be missing the group that owns the socket, be in a
+// login session that predates joining it, or have a rootless daemon of their
+// own to use instead. ok is false for other errors.
+func DiagnoseConnection(err error, host string) (diagnosis Diagnosis, ok bool) {
+	if err == nil || !isPermissionError(err) {
+		return Diagnosis{}, false
+	}
+
+	diagnosis.Host = host
+	path, isSocket := strings.CutPrefix(host, "unix://")
+	if !isSocket {
+		diagnosis.Fixes = []string{"Check the permissions of " + host}
+		return diagnosis, true
+	}
+
+	access := socketAccess{path: path, rootlessPath: rootlessSocket(os.Getenv, path)}
+	access.group, access.held, access.assigned = socketGroup(path)
+	diagnosis.Rootless = isRootlessSocket(os.Getenv, path)
+	diagnosis.Fixes = accessFixes(access)
+	return diagnosis, true
+}
+
+// isPermissionError reports whether err is a refused socket access. The
+// client doesn't always keep the underlying error, so the text is checked too.
+func isPermissionError(err error) bool {
+	return errors.Is(err, os.ErrPermission) || strings.Contains(strings.ToLower(err.Error()), "permission denied")
+}
+
+// accessFixes suggests how to get access to a socket
+func accessFixes(a socketAccess) []string {
+	var fixes []string
+	switch {
+	case a.group == "":
+	case a.group == "root":
+		fixes = append(fixes, fmt.Sprintf("%s is only accessible to root: run tinyd with sudo, or set up rootless Docker with dockerd-rootless-setuptool.sh install", a.path))
+	case a.held:
+		fixes = append(fixes, fmt.Sprintf("%s is not writable by the %q group: sudo chmod 660 %s", a.path, a.group, a.path))
+	case a.assigned:
+		fixes = append(fixes, fmt.Sprintf("You joined the %q group after logging in: log out and back in, or run newgrp %s", a.group, a.group))
+	default:
+		fixes = append(fixes, fmt.Sprintf("Your user is not in the %q group that owns %s: sudo usermod -aG %s $USER, then log out and back in", a.group, a.path, a.group))
+	}
+
+	if a.rootlessPath != "" {
+		fixes = append(fixes, fmt.Sprintf("A rootless daemon is listening on %s: export DOCKER_HOST=unix://%s", a.rootlessPath, a.rootlessPath))
+	}
+	if len(fixes) == 0 {
+		fixes = append(fixes, "Check the owner and mode of "+a.path)
+	}
+	return fixes
+}
+
+// rootlessSockets returns where the current user's rootless daemon listens
+func rootlessSockets(getenv func(string) string) []string {
+	var paths []string
+	if dir := getenv("XDG_RUNTIME_DIR"); dir != "" {
+		paths = append(paths, filepath.Join(dir, "docker.sock"))
+	}
+	if uid := os.Getuid(); uid > 0 {
+		paths = append(paths, filepath.Join("/run/user", strconv.Itoa(uid), "docker.sock"))
+	}
+	return paths
+}
+
+// isRootlessSocket reports whether path is a rootless daemon's socket
+func isRootlessSocket(getenv func(string) string, path string) bool {
+	if strings.HasPrefix(path, "/run/user/") {
+		return true
+	}
+	for _, rootless := range rootlessSockets(getenv) {
+		if path == rootless {
+			return true
+		}
+	}
+	return false
+}
+
+// rootlessSocket returns the current user's rootless daemon socket when it
+// exists and is not the socket already tried
+func rootlessSocket(getenv func(string) string, tried string) string {
+	for _, path := range rootlessSockets(getenv) {
+		if path == tried {
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
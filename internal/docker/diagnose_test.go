@@ -0,0 +1,73 @@
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDiagnoseConnectionIgnoresOtherErrors(t *testing.T) {
+	err := errors.New("Cannot connect to the Docker daemon at unix:///var/run/docker.sock. Is the docker daemon running?")
+	if _, ok := DiagnoseConnection(err, "unix:///var/run/docker.sock"); ok {
+		t.Error("DiagnoseConnection() diagnosed a daemon that is not running")
+	}
+
+	denied := fmt.Errorf("failed to list containers: %w", os.ErrPermission)
+	diagnosis, ok := DiagnoseConnection(denied, "tcp://10.0.0.5:2375")
+	if !ok || len(diagnosis.Fixes) != 1 {
+		t.Errorf("DiagnoseConnection() over TCP = %+v, %v", diagnosis, ok)
+	}
+}
+
+func TestAccessFixes(t *testing.T) {
+	tests := []struct {
+		name   string
+		access socketAccess
+		want   []string // Substrings of the fixes, in order
+	}{
+		{"not in group", socketAccess{path: "/var/run/docker.sock", group: "docker"}, []string{"usermod -aG docker"}},
+		{"joined after login", socketAccess{path: "/var/run/docker.sock", group: "docker", assigned: true}, []string{"newgrp docker"}},
+		{"group not writable", socketAccess{path: "/var/run/docker.sock", group: "docker", held: true, assigned: true}, []string{"chmod 660"}},
+		{"root only", socketAccess{path: "/var/run/docker.sock", group: "root"}, []string{"sudo"}},
+		{
+			"rootless available",
+			socketAccess{path: "/var/run/docker.sock", group: "docker", rootlessPath: "/run/user/1000/docker.sock"},
+			[]string{"usermod", "DOCKER_HOST=unix:///run/user/1000/docker.sock"},
+		},
+		{"unknown", socketAccess{path: "/var/run/docker.sock"}, []string{"owner and mode"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixes := accessFixes(tt.access)
+			if len(fixes) != len(tt.want) {
+				t.Fatalf("accessFixes() = %q, want %d fixes", fixes, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(fixes[i], want) {
+					t.Errorf("fix %d = %q, want it to mention %q", i, fixes[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestIsRootlessSocket(t *testing.T) {
+	getenv := func(key string) string {
+		if key == "XDG_RUNTIME_DIR" {
+			return "/tmp/runtime-dev"
+		}
+		return ""
+	}
+	if !isRootlessSocket(getenv, "/tmp/runtime-dev/docker.sock") {
+		t.Error("socket in XDG_RUNTIME_DIR not detected as rootless")
+	}
+	if !isRootlessSocket(getenv, "/run/user/1000/docker.sock") {
+		t.Error("socket in /run/user not detected as rootless")
+	}
+	if isRootlessSocket(getenv, "/var/run/docker.sock") {
+		t.Error("system socket detected as rootless")
+	}
+}
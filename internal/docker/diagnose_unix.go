@@ -0,0 +1,42 @@
+//go:build !windows
+
+package docker
+
+import (
+	"os"
+	"os/user"
+	"slices"
+	"strconv"
+	"syscall"
+)
+
+// socketGroup returns the name of the group owning a socket, whether this
+// process has that group, and whether the user is in it according to the
+// group database, which includes groups joined after the session started
+func socketGroup(path string) (name string, held, assigned bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", false, false
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return "", false, false
+	}
+
+	gid := int(stat.Gid)
+	name = strconv.Itoa(gid)
+	if group, err := user.LookupGroupId(name); err == nil {
+		name = group.Name
+	}
+	if gid == 0 {
+		name = "root"
+	}
+
+	groups, _ := os.Getgroups()
+	held = os.Getegid() == gid || slices.Contains(groups, gid)
+	if current, err := user.Current(); err == nil {
+		ids, _ := current.GroupIds()
+		assigned = slices.Contains(ids, strconv.Itoa(gid))
+	}
+	return name, held, assigned
+}
@@ -0,0 +1,6 @@
+package docker
+
+// socketGroup is unknown on Windows, where the daemon listens on a named pipe
+func socketGroup(path string) (name string, held, assigned bool) {
+	return "", false, false
+}
@@ -0,0 +1,114 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/moby/moby/client"
+	"tinyd/internal/types"
+)
+
+// pinTagPrefix starts the local tags that keep pinned images from becoming
+// dangling (and pruned) once their tag moves to a newer build
+const pinTagPrefix = "pin-"
+
+// PinImageDigest pins the tag of an image to the digest it was pulled with:
+// the image stays runnable as repo@sha256:... and gets a "pin-<digest>" tag,
+// since Docker can't tag an image with a digest reference. The registry is
+// asked for the tag's current digest, to tell whether it has moved since.
+func (c *Client) PinImageDigest(ctx context.Context, imageID string) (types.DigestPin, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutMedium)
+		defer cancel()
+	}
+
+	inspect, err := c.inspectImage(ctx, imageID)
+	if err != nil {
+		return types.DigestPin{}, fmt.Errorf("failed to inspect image: %w", err)
+	}
+	if len(inspect.RepoTags) == 0 {
+		return types.DigestPin{}, fmt.Errorf("image has no tag to pin")
+	}
+	tagRef := inspect.RepoTags[0]
+	repo, _ := splitRepoTag(tagRef)
+
+	digest := repoDigest(repo, inspect.RepoDigests)
+	if digest == "" {
+		return types.DigestPin{}, fmt.Errorf("%s has no registry digest: it was built locally, push it first", tagRef)
+	}
+
+	pin := types.DigestPin{Ref: repo + "@" + digest, PinTag: pinTag(repo, digest)}
+	if _, err := c.cli.ImageTag(ctx, client.ImageTagOptions{Source: imageID, Target: pin.PinTag}); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return types.DigestPin{}, fmt.Errorf("tag operation timed out after %s", TimeoutMedium)
+		}
+		return types.DigestPin{}, fmt.Errorf("failed to tag image: %w", err)
+	}
+
+	// Best effort: offline hosts and private registries may not answer
+	if remote, err := c.cli.DistributionInspect(ctx, tagRef, client.DistributionInspectOptions{}); err == nil {
+		pin.Remote = string(remote.Descriptor.Digest)
+	}
+	return pin, nil
+}
+
+// splitRepoTag splits "registry:5000/app:1.0" into its repository and tag;
+// the tag is empty when the reference has none
+func splitRepoTag(ref string) (string, string) {
+	i := strings.LastIndex(ref, ":")
+	if i < 0 || strings.Contains(ref[i:], "/") {
+		return ref, ""
+	}
+	return ref[:i], ref[i+1:]
+}
+
+// repoDigest returns the digest of repo among the "repo@sha256:..." digests
+// of an image. Names are compared without Docker Hub's implied prefixes, as
+// daemons differ in which form they report.
+func repoDigest(repo string, repoDigests []string) string {
+	for _, ref := range repoDigests {
+		name, digest, ok := strings.Cut(ref, "@")
+		if ok && familiarName(name) == familiarName(repo) {
+			return digest
+		}
+	}
+	return ""
+}
+
+// familiarName strips Docker Hub's registry and library prefixes, so that
+// "docker.io/library/nginx" and "nginx" compare equal
+func familiarName(name string) string {
+	name = strings.TrimPrefix(name, "docker.io/")
+	return strings.TrimPrefix(name, "library/")
+}
+
+// pinTag returns the local tag that keeps an image pinned at digest
+func pinTag(repo, digest string) string {
+	hex := strings.TrimPrefix(digest, "sha256:")
+	if len(hex) > 12 {
+		hex = hex[:12]
+	}
+	return repo + ":" + pinTagPrefix + hex
+}
+
+// imageDigest returns the first registry digest of an image, empty when it
+// has none
+func imageDigest(repoDigests []string) string {
+	for _, ref := range repoDigests {
+		if _, digest, ok := strings.Cut(ref, "@"); ok {
+			return digest
+		}
+	}
+	return ""
+}
+
+// ShortDigest shortens a digest for display, "sha256:0123456789ab"
+func ShortDigest(digest string) string {
+	if len(digest) > len("sha256:")+12 {
+		return digest[:len("sha256:")+12]
+	}
+	return digest
+}
@@ -0,0 +1,45 @@
+package docker
+
+import "testing"
+
+func TestSplitRepoTag(t *testing.T) {
+	tests := []struct {
+		ref, repo, tag string
+	}{
+		{"nginx:1.25", "nginx", "1.25"},
+		{"registry:5000/team/app:v2", "registry:5000/team/app", "v2"},
+		{"registry:5000/team/app", "registry:5000/team/app", ""},
+		{"alpine", "alpine", ""},
+	}
+	for _, tt := range tests {
+		if repo, tag := splitRepoTag(tt.ref); repo != tt.repo || tag != tt.tag {
+			t.Errorf("splitRepoTag(%q) = %q, %q, want %q, %q", tt.ref, repo, tag, tt.repo, tt.tag)
+		}
+	}
+}
+
+func TestRepoDigest(t *testing.T) {
+	digests := []string{
+		"ghcr.io/team/app@sha256:1111",
+		"docker.io/library/nginx@sha256:2222",
+	}
+	if got := repoDigest("nginx", digests); got != "sha256:2222" {
+		t.Errorf("repoDigest(nginx) = %q, want sha256:2222", got)
+	}
+	if got := repoDigest("ghcr.io/team/app", digests); got != "sha256:1111" {
+		t.Errorf("repoDigest(ghcr.io/team/app) = %q, want sha256:1111", got)
+	}
+	if got := repoDigest("redis", digests); got != "" {
+		t.Errorf("repoDigest(redis) = %q, want none", got)
+	}
+}
+
+func TestPinTag(t *testing.T) {
+	got := pinTag("nginx", "sha256:0123456789abcdef0123")
+	if got != "nginx:pin-0123456789ab" {
+		t.Errorf("pinTag() = %q", got)
+	}
+	if got := ShortDigest("sha256:0123456789abcdef0123"); got != "sha256:0123456789ab" {
+		t.Errorf("ShortDigest() = %q", got)
+	}
+}
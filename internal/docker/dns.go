@@ -0,0 +1,157 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"tinyd/internal/types"
+)
+
+// resolveScript prints the container's nameservers, then the lookup of $1
+// under a "## <tool>" marker. getent follows nsswitch like the application
+// would; images without it (e.g. busybox) usually ship nslookup.
+const resolveScript = `grep '^nameserver' /etc/resolv.conf 2>/dev/null
+if command -v getent >/dev/null 2>&1; then echo "## getent"; getent hosts "$1"
+elif command -v nslookup >/dev/null 2>&1; then echo "## nslookup"; nslookup "$1"
+else echo "## none"; fi`
+
+// connectScript tries a TCP connection to $1 port $2 with nc, or bash's
+// /dev/tcp; it exits with 127 when the container has neither
+const connectScript = `if command -v nc >/dev/null 2>&1; then nc -z -w 3 "$1" "$2"
+elif command -v bash >/dev/null 2>&1; then bash -c 'exec 3<>"/dev/tcp/$0/$1"' "$1" "$2"
+else exit 127; fi`
+
+// CheckDNS resolves host from inside a running container and, when port is
+// not 0 and the name resolves, tries a TCP connection to it. Times exclude
+// the cost of an exec, measured with an empty one first.
+func (c *Client) CheckDNS(ctx context.Context, containerID, host string, port int) (types.DNSCheck, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutMedium)
+		defer cancel()
+	}
+
+	start := time.Now()
+	if _, _, err := c.ExecOutput(ctx, containerID, []string{"true"}); err != nil {
+		return types.DNSCheck{}, err
+	}
+	overhead := time.Since(start)
+
+	start = time.Now()
+	out, code, err := c.ExecOutput(ctx, containerID, []string{"sh", "-c", resolveScript, "sh", host})
+	if err != nil {
+		return types.DNSCheck{}, err
+	}
+	check, err := parseResolveOutput(out, code)
+	if err != nil {
+		return types.DNSCheck{}, err
+	}
+	check.Host = host
+	check.Resolve = max(time.Since(start)-overhead, 0)
+	if port == 0 || len(check.Addresses) == 0 {
+		return check, nil
+	}
+
+	check.Port = port
+	start = time.Now()
+	out, code, err = c.ExecOutput(ctx, containerID, []string{"sh", "-c", connectScript, "sh", host, strconv.Itoa(port)})
+	if err != nil {
+		return types.DNSCheck{}, err
+	}
+	check.Connect = max(time.Since(start)-overhead, 0)
+	switch code {
+	case 0:
+		check.Connected = true
+	case 127:
+		check.ConnectErr = "no nc or bash in the container to test the port"
+	default:
+		check.ConnectErr = "connection failed"
+		if msg := strings.TrimSpace(out); msg != "" {
+			check.ConnectErr = lastLine(msg)
+		}
+	}
+	return check, nil
+}
+
+// parseResolveOutput reads the output of resolveScript. A name that doesn't
+// resolve is not an error, only a lookup that could not run is.
+func parseResolveOutput(out string, code int) (types.DNSCheck, error) {
+	var check types.DNSCheck
+	answer := false // nslookup lists its server before the answer
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			continue
+		case check.Tool == "" && fields[0] == "nameserver" && len(fields) > 1:
+			check.Nameservers = append(check.Nameservers, fields[1])
+		case fields[0] == "##" && len(fields) > 1:
+			check.Tool = fields[1]
+		case check.Tool == "getent":
+			check.Addresses = appendAddress(check.Addresses, fields[0])
+		case check.Tool == "nslookup" && strings.HasPrefix(fields[0], "Name:"):
+			answer = true
+		case check.Tool == "nslookup" && answer && strings.HasPrefix(fields[0], "Address"):
+			// "Address: 10.0.0.2" or, in older busybox, "Address 1: 10.0.0.2 name"
+			_, rest, _ := strings.Cut(line, ":")
+			if addr := strings.Fields(rest); len(addr) > 0 {
+				check.Addresses = appendAddress(check.Addresses, addr[0])
+			}
+		}
+	}
+
+	switch check.Tool {
+	case "":
+		return check, fmt.Errorf("lookup failed (exit code %d): %s", code, strings.TrimSpace(out))
+	case "none":
+		return check, fmt.Errorf("neither getent nor nslookup is available in the container")
+	}
+	return check, nil
+}
+
+// appendAddress adds addr when it is an IP address not listed yet
+func appendAddress(addrs []string, addr string) []string {
+	if net.ParseIP(addr) == nil {
+		return addrs
+	}
+	for _, a := range addrs {
+		if a == addr {
+			return addrs
+		}
+	}
+	return append(addrs, addr)
+}
+
+// lastLine returns the last line of s
+func lastLine(s string) string {
+	if i := strings.LastIndex(s, "\n"); i >= 0 {
+		return s[i+1:]
+	}
+	return s
+}
+
+// ParseHostPort reads a DNS check target, "db" or "db:5432"; IPv6
+// addresses with a port are written in brackets ("[fd00::1]:80")
+func ParseHostPort(s string) (string, int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", 0, fmt.Errorf("enter a hostname, optionally with :port")
+	}
+	if strings.Count(s, ":") != 1 && !strings.HasPrefix(s, "[") {
+		// A name, or an IPv6 address without a port
+		return s, 0, nil
+	}
+	host, portText, err := net.SplitHostPort(s)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid target %q: %w", s, err)
+	}
+	port, err := strconv.Atoi(portText)
+	if err != nil || port < 1 || port > 65535 {
+		return "", 0, fmt.Errorf("invalid port %q", portText)
+	}
+	return host, port, nil
+}
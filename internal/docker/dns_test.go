@@ -0,0 +1,84 @@
+package docker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseResolveOutput(t *testing.T) {
+	tests := []struct {
+		name        string
+		out         string
+		tool        string
+		nameservers []string
+		addresses   []string
+	}{
+		{
+			name:        "getent",
+			out:         "nameserver 127.0.0.11\n## getent\n172.18.0.3      db\n172.18.0.4      db\n",
+			tool:        "getent",
+			nameservers: []string{"127.0.0.11"},
+			addresses:   []string{"172.18.0.3", "172.18.0.4"},
+		},
+		{
+			name:        "nslookup",
+			out:         "nameserver 127.0.0.11\n## nslookup\nServer:\t\t127.0.0.11\nAddress:\t127.0.0.11:53\n\nNon-authoritative answer:\nName:\tdb\nAddress: 172.18.0.3\n",
+			tool:        "nslookup",
+			nameservers: []string{"127.0.0.11"},
+			addresses:   []string{"172.18.0.3"},
+		},
+		{
+			name:      "old busybox nslookup",
+			out:       "## nslookup\nServer:    127.0.0.11\nAddress 1: 127.0.0.11\n\nName:      db\nAddress 1: 172.18.0.3 db.app_default\n",
+			tool:      "nslookup",
+			addresses: []string{"172.18.0.3"},
+		},
+		{
+			name: "not found",
+			out:  "nameserver 8.8.8.8\n## getent\n",
+			tool: "getent", nameservers: []string{"8.8.8.8"},
+		},
+	}
+	for _, tt := range tests {
+		check, err := parseResolveOutput(tt.out, 0)
+		if err != nil {
+			t.Errorf("%s: parseResolveOutput() error = %v", tt.name, err)
+			continue
+		}
+		if check.Tool != tt.tool || !reflect.DeepEqual(check.Nameservers, tt.nameservers) || !reflect.DeepEqual(check.Addresses, tt.addresses) {
+			t.Errorf("%s: parseResolveOutput() = %+v", tt.name, check)
+		}
+	}
+
+	if _, err := parseResolveOutput("## none\n", 0); err == nil {
+		t.Error("expected an error without lookup tools")
+	}
+	if _, err := parseResolveOutput("sh: not found\n", 127); err == nil {
+		t.Error("expected an error when the script did not run")
+	}
+}
+
+func TestParseHostPort(t *testing.T) {
+	tests := []struct {
+		input string
+		host  string
+		port  int
+	}{
+		{"db", "db", 0},
+		{" db:5432 ", "db", 5432},
+		{"fd00::1", "fd00::1", 0},
+		{"[fd00::1]:80", "fd00::1", 80},
+	}
+	for _, tt := range tests {
+		host, port, err := ParseHostPort(tt.input)
+		if err != nil || host != tt.host || port != tt.port {
+			t.Errorf("ParseHostPort(%q) = %q, %d, %v", tt.input, host, port, err)
+		}
+	}
+
+	for _, input := range []string{"", "db:http", "db:70000"} {
+		if _, _, err := ParseHostPort(input); err == nil {
+			t.Errorf("ParseHostPort(%q) succeeded, want an error", input)
+		}
+	}
+}
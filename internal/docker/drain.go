@@ -0,0 +1,125 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moby/moby/api/types/network"
+)
+
+// drainPoll is how often DrainAndStop counts the open connections
+const drainPoll = time.Second
+
+// tcpSocketsCmd prints the container's TCP sockets. /proc is there even in
+// images without ss or netstat; tcp6 is missing when IPv6 is disabled.
+var tcpSocketsCmd = []string{"sh", "-c", "cat /proc/net/tcp /proc/net/tcp6 2>/dev/null"}
+
+// DrainAndStop stops a container once no client is connected to it, a
+// poor man's graceful drain for a container behind a load balancer that
+// was told to stop sending it traffic. Connections are counted to the
+// container's published TCP ports or, without any, to every port it listens
+// on. After maxWait it stops the container anyway. progress is called with
+// the count while waiting; open is the count left when it was stopped.
+func (c *Client) DrainAndStop(ctx context.Context, containerID string, maxWait time.Duration, progress func(string)) (open int, err error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), maxWait+TimeoutMedium)
+		defer cancel()
+	}
+
+	inspect, err := c.inspectContainer(ctx, containerID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to inspect container: %w", err)
+	}
+	ports := make(map[int]bool)
+	if settings := inspect.Container.NetworkSettings; settings != nil {
+		for port, bindings := range settings.Ports {
+			if len(bindings) > 0 && port.Proto() == network.TCP {
+				ports[int(port.Num())] = true
+			}
+		}
+	}
+
+	deadline := time.Now().Add(maxWait)
+	for {
+		open, err = c.establishedConns(ctx, containerID, ports)
+		if err != nil {
+			return 0, err
+		}
+		left := time.Until(deadline)
+		if open == 0 || left <= 0 {
+			break
+		}
+		progress(fmt.Sprintf("%d connection(s) open, stopping in %s at the latest", open, left.Round(time.Second)))
+
+		select {
+		case <-ctx.Done():
+			return open, ctx.Err()
+		case <-time.After(min(drainPoll, left)):
+		}
+	}
+
+	progress("stopping")
+	return open, c.StopContainer(ctx, containerID)
+}
+
+// establishedConns counts the clients connected to ports of the container,
+// or to any port it listens on when ports is empty
+func (c *Client) establishedConns(ctx context.Context, containerID string, ports map[int]bool) (int, error) {
+	execCtx, cancel := context.WithTimeout(ctx, TimeoutQuick)
+	defer cancel()
+
+	out, _, err := c.ExecOutput(execCtx, containerID, tcpSocketsCmd)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count connections: %w", err)
+	}
+	if !strings.Contains(out, "local_address") {
+		return 0, fmt.Errorf("failed to count connections: no TCP socket table in the container: %s", strings.TrimSpace(out))
+	}
+	return countEstablished(out, ports), nil
+}
+
+// countEstablished counts the established connections of /proc/net/tcp
+// output whose local port is one of ports, or any listening port when ports
+// is empty. Connections the container opened itself to those ports count
+// too, which is rare enough.
+func countEstablished(out string, ports map[int]bool) int {
+	type socket struct {
+		port  int
+		state string
+	}
+	var sockets []socket
+	listening := make(map[int]bool)
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[0] == "sl" {
+			continue
+		}
+		_, hexPort, ok := strings.Cut(fields[1], ":")
+		if !ok {
+			continue
+		}
+		port, err := strconv.ParseInt(hexPort, 16, 32)
+		if err != nil {
+			continue
+		}
+		sockets = append(sockets, socket{int(port), fields[3]})
+		if fields[3] == "0A" { // LISTEN
+			listening[int(port)] = true
+		}
+	}
+
+	if len(ports) == 0 {
+		ports = listening
+	}
+	count := 0
+	for _, s := range sockets {
+		if s.state == "01" && ports[s.port] { // ESTABLISHED
+			count++
+		}
+	}
+	return count
+}
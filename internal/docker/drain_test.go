@@ -0,0 +1,25 @@
+package docker
+
+import "testing"
+
+func TestCountEstablished(t *testing.T) {
+	// nginx on 80 (0x50) with two clients, one of them over IPv6, and a
+	// connection of its own to a database on 5432 (0x1538)
+	out := `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:0050 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1001 1 0 100 0 0 10 0
+   1: 020011AC:0050 010011AC:C350 01 00000000:00000000 00:00000000 00000000     0        0 1002 1 0 20 4 30 10 -1
+   2: 020011AC:D431 030011AC:1538 01 00000000:00000000 00:00000000 00000000     0        0 1003 1 0 20 4 30 10 -1
+   3: 020011AC:0050 010011AC:C351 06 00000000:00000000 00:00000000 00000000     0        0 0 3 0 0 0 0 0 0
+  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 0000000000000000FFFF0000020011AC:0050 0000000000000000FFFF0000010011AC:C352 01 00000000:00000000 00:00000000 00000000     0        0 1004 1 0 20 4 30 10 -1
+`
+	if got := countEstablished(out, nil); got != 2 {
+		t.Errorf("countEstablished(listening ports) = %d, want 2", got)
+	}
+	if got := countEstablished(out, map[int]bool{80: true}); got != 2 {
+		t.Errorf("countEstablished(published 80) = %d, want 2", got)
+	}
+	if got := countEstablished(out, map[int]bool{8080: true}); got != 0 {
+		t.Errorf("countEstablished(published 8080) = %d, want 0", got)
+	}
+}
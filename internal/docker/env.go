@@ -0,0 +1,34 @@
+package docker
+
+import (
+	"strings"
+
+	"tinyd/internal/types"
+)
+
+// secretKeyParts are the parts of variable names whose values are masked,
+// e.g. POSTGRES_PASSWORD, GITHUB_TOKEN or AWS_SECRET_ACCESS_KEY
+var secretKeyParts = []string{"PASSWORD", "PASSWD", "PASS", "TOKEN", "KEY", "SECRET", "CREDENTIAL", "AUTH"}
+
+// SecretEnvKey reports whether a variable looks like it holds a secret,
+// judging by its name
+func SecretEnvKey(key string) bool {
+	key = strings.ToUpper(key)
+	for _, part := range secretKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseEnv splits KEY=VALUE entries of a container's environment into
+// variables, in their order. An entry without "=" has an empty value.
+func ParseEnv(env []string) []types.EnvVar {
+	vars := make([]types.EnvVar, 0, len(env))
+	for _, entry := range env {
+		key, value, _ := strings.Cut(entry, "=")
+		vars = append(vars, types.EnvVar{Key: key, Value: value})
+	}
+	return vars
+}
@@ -0,0 +1,49 @@
+package docker
+
+import (
+	"testing"
+
+	"tinyd/internal/types"
+)
+
+func TestSecretEnvKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"POSTGRES_PASSWORD", true},
+		{"MYSQL_ROOT_PASSWD", true},
+		{"GITHUB_TOKEN", true},
+		{"AWS_SECRET_ACCESS_KEY", true},
+		{"api_key", true},
+		{"GOOGLE_APPLICATION_CREDENTIALS", true},
+		{"BASIC_AUTH", true},
+		{"PATH", false},
+		{"HOME", false},
+		{"POSTGRES_DB", false},
+		{"LANG", false},
+	}
+	for _, tt := range tests {
+		if got := SecretEnvKey(tt.key); got != tt.want {
+			t.Errorf("SecretEnvKey(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestParseEnv(t *testing.T) {
+	got := ParseEnv([]string{"PATH=/usr/bin:/bin", "DSN=postgres://u:p@db/app?sslmode=disable", "EMPTY=", "BARE"})
+	want := []types.EnvVar{
+		{Key: "PATH", Value: "/usr/bin:/bin"},
+		{Key: "DSN", Value: "postgres://u:p@db/app?sslmode=disable"},
+		{Key: "EMPTY", Value: ""},
+		{Key: "BARE", Value: ""},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ParseEnv() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ParseEnv()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moby/moby/api/types/events"
+	"github.com/moby/moby/client"
+
+	"tinyd/internal/types"
+)
+
+// eventFilterKeys are the filters `docker events --filter` accepts
+var eventFilterKeys = map[string]bool{
+	"config": true, "container": true, "daemon": true, "event": true,
+	"image": true, "label": true, "network": true, "node": true,
+	"plugin": true, "scope": true, "secret": true, "service": true,
+	"type": true, "volume": true,
+}
+
+// ParseEventFilter reads space-separated key=value filters as `docker
+// events --filter` takes them, e.g. "type=image event=pull" or
+// "label=com.docker.compose.project=shop". A key given twice matches
+// either value.
+func ParseEventFilter(s string) (client.Filters, error) {
+	filters := make(client.Filters)
+	for _, term := range strings.Fields(s) {
+		key, value, ok := strings.Cut(term, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid filter %q: expected key=value", term)
+		}
+		if !eventFilterKeys[key] {
+			return nil, fmt.Errorf("invalid filter %q: unknown key %q", term, key)
+		}
+		filters.Add(key, value)
+	}
+	return filters, nil
+}
+
+// ParseSince reads the time to replay events from: a duration back from now
+// ("10m", "2h"), a time of day ("14:30", the last one that passed), a date
+// and time ("2024-05-01 14:30") or RFC 3339. Empty means now.
+func ParseSince(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return now.Add(-d), nil
+	}
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			since := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), t.Second(), 0, now.Location())
+			if since.After(now) {
+				since = since.AddDate(0, 0, -1)
+			}
+			return since, nil
+		}
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02 15:04:05", "2006-01-02", time.RFC3339} {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: use e.g. 10m, 14:30 or 2024-05-01 14:30", s)
+}
+
+// FollowEvents streams the daemon's events matching filter (see
+// ParseEventFilter) to emit, first replaying those since the given time
+// unless it is zero, until ctx is cancelled or the stream fails.
+func (c *Client) FollowEvents(ctx context.Context, filter string, since time.Time, emit func(types.DockerEvent)) error {
+	filters, err := ParseEventFilter(filter)
+	if err != nil {
+		return err
+	}
+	options := client.EventsListOptions{Filters: filters}
+	if !since.IsZero() {
+		options.Since = strconv.FormatInt(since.Unix(), 10)
+	}
+
+	result := c.cli.Events(ctx, options)
+	for {
+		select {
+		case msg := <-result.Messages:
+			emit(toEvent(msg))
+		case err := <-result.Err:
+			if err == nil || errors.Is(err, io.EOF) || ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to read events: %w", err)
+		}
+	}
+}
+
+// toEvent converts an event for display. Attributes with a dot in their
+// key are container labels (com.docker.compose.project, ...), too many to
+// show.
+func toEvent(msg events.Message) types.DockerEvent {
+	event := types.DockerEvent{
+		Time:   time.Unix(0, msg.TimeNano),
+		Type:   string(msg.Type),
+		Action: string(msg.Action),
+		Actor:  msg.Actor.Attributes["name"],
+	}
+	if msg.TimeNano == 0 {
+		event.Time = time.Unix(msg.Time, 0)
+	}
+	if event.Actor == "" {
+		id := strings.TrimPrefix(msg.Actor.ID, "sha256:")
+		event.Actor = id[:min(len(id), 12)]
+	}
+
+	var attrs []string
+	for key, value := range msg.Actor.Attributes {
+		if key != "name" && !strings.Contains(key, ".") {
+			attrs = append(attrs, key+"="+value)
+		}
+	}
+	sort.Strings(attrs)
+	event.Attrs = strings.Join(attrs, " ")
+	return event
+}
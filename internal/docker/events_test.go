@@ -0,0 +1,75 @@
+package docker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moby/moby/api/types/events"
+)
+
+func TestParseEventFilter(t *testing.T) {
+	filters, err := ParseEventFilter("type=container  event=start event=die label=com.docker.compose.project=shop")
+	if err != nil {
+		t.Fatalf("ParseEventFilter() error: %v", err)
+	}
+	if !filters["event"]["start"] || !filters["event"]["die"] || !filters["type"]["container"] ||
+		!filters["label"]["com.docker.compose.project=shop"] || len(filters) != 3 {
+		t.Errorf("ParseEventFilter() = %v", filters)
+	}
+
+	if filters, err := ParseEventFilter(""); err != nil || len(filters) != 0 {
+		t.Errorf("ParseEventFilter(\"\") = %v, %v; want empty", filters, err)
+	}
+	for _, bad := range []string{"type", "type=", "colour=red"} {
+		if _, err := ParseEventFilter(bad); err == nil {
+			t.Errorf("ParseEventFilter(%q) should fail", bad)
+		}
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{"", time.Time{}},
+		{"10m", now.Add(-10 * time.Minute)},
+		{"09:30", time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)},
+		{"14:30:15", time.Date(2024, 4, 30, 14, 30, 15, 0, time.UTC)}, // Not yet today
+		{"2024-04-20 08:00", time.Date(2024, 4, 20, 8, 0, 0, 0, time.UTC)},
+		{"2024-04-20T08:00:00Z", time.Date(2024, 4, 20, 8, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseSince(tt.input, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("ParseSince(%q) = %v, %v; want %v", tt.input, got, err, tt.want)
+		}
+	}
+	if _, err := ParseSince("yesterday", now); err == nil {
+		t.Error("ParseSince(yesterday) should fail")
+	}
+}
+
+func TestToEvent(t *testing.T) {
+	event := toEvent(events.Message{
+		Type:   events.ContainerEventType,
+		Action: events.ActionDie,
+		Actor: events.Actor{ID: "0123456789abcdef", Attributes: map[string]string{
+			"name":                       "shop-web-1",
+			"image":                      "nginx",
+			"exitCode":                   "137",
+			"com.docker.compose.project": "shop",
+		}},
+		TimeNano: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC).UnixNano(),
+	})
+	if event.Type != "container" || event.Action != "die" || event.Actor != "shop-web-1" || event.Attrs != "exitCode=137 image=nginx" {
+		t.Errorf("toEvent() = %+v", event)
+	}
+
+	// Without a name the short ID stands for the object
+	event = toEvent(events.Message{Type: events.ImageEventType, Actor: events.Actor{ID: "sha256:0123456789abcdef"}})
+	if event.Actor != "0123456789ab" {
+		t.Errorf("toEvent().Actor = %q, want the short ID", event.Actor)
+	}
+}
@@ -0,0 +1,145 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moby/moby/api/pkg/stdcopy"
+	"github.com/moby/moby/client"
+	"tinyd/internal/types"
+)
+
+// ExecOutput runs a command in a running container without a TTY and
+// returns its combined stdout and stderr and its exit code
+func (c *Client) ExecOutput(ctx context.Context, containerID string, cmd []string) (string, int, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutMedium)
+		defer cancel()
+	}
+
+	exec, err := c.cli.ExecCreate(ctx, containerID, client.ExecCreateOptions{
+		Cmd:          cmd,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create exec: %w", err)
+	}
+
+	attach, err := c.cli.ExecAttach(ctx, exec.ID, client.ExecAttachOptions{})
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to attach to exec: %w", err)
+	}
+	defer attach.Close()
+
+	var out bytes.Buffer
+	if _, err := stdcopy.StdCopy(&out, &out, attach.Reader); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return "", 0, fmt.Errorf("exec timed out")
+		}
+		return "", 0, fmt.Errorf("failed to read exec output: %w", err)
+	}
+
+	inspect, err := c.cli.ExecInspect(ctx, exec.ID, client.ExecInspectOptions{})
+	if err != nil {
+		return out.String(), 0, fmt.Errorf("failed to inspect exec: %w", err)
+	}
+	return out.String(), inspect.ExitCode, nil
+}
+
+// clockScript prints the container's epoch seconds, its zone abbreviation
+// and offset, and $TZ, one per line; busybox date supports these formats
+const clockScript = `date +%s; date "+%Z %z"; echo "$TZ"`
+
+// ContainerClock compares a container's clock and timezone to the host's
+func (c *Client) ContainerClock(ctx context.Context, containerID string) (types.ClockInfo, error) {
+	before := time.Now()
+	out, code, err := c.ExecOutput(ctx, containerID, []string{"sh", "-c", clockScript})
+	after := time.Now()
+	if err != nil {
+		return types.ClockInfo{}, err
+	}
+	if code != 0 {
+		return types.ClockInfo{}, fmt.Errorf("date failed (exit code %d): %s", code, strings.TrimSpace(out))
+	}
+	return parseClockOutput(out, before, after)
+}
+
+// parseClockOutput reads the output of clockScript. The container clock is
+// compared to the midpoint of the exec, so drift is accurate to about a
+// second plus half the exec round trip.
+func parseClockOutput(out string, before, after time.Time) (types.ClockInfo, error) {
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	if len(lines) < 2 {
+		return types.ClockInfo{}, fmt.Errorf("unexpected date output: %q", out)
+	}
+
+	epoch, err := strconv.ParseInt(strings.TrimSpace(lines[0]), 10, 64)
+	if err != nil {
+		return types.ClockInfo{}, fmt.Errorf("unexpected date output: %q", lines[0])
+	}
+
+	hostTime := before.Add(after.Sub(before) / 2)
+	info := types.ClockInfo{
+		Drift: time.Unix(epoch, 0).Sub(hostTime.Truncate(time.Second)),
+	}
+	info.Zone, info.Offset, _ = strings.Cut(strings.TrimSpace(lines[1]), " ")
+	if len(lines) > 2 {
+		info.TZ = strings.TrimSpace(lines[2])
+	}
+	return info, nil
+}
+
+// SplitCommand splits a command line into arguments at spaces, as a shell
+// would for simple commands: single and double quotes group words, and a
+// backslash escapes the next character outside single quotes. Pipes,
+// variables and globs are not interpreted.
+func SplitCommand(s string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	inArg := false
+	var quote rune
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			arg.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inArg = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, errors.New("trailing backslash")
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,60 @@
+package docker
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseClockOutput(t *testing.T) {
+	before := time.Unix(1700000000, 0)
+	after := before.Add(200 * time.Millisecond)
+
+	info, err := parseClockOutput("1700000003\nCET +0100\nEurope/Madrid\n", before, after)
+	if err != nil {
+		t.Fatalf("parseClockOutput() error = %v", err)
+	}
+	if info.Drift != 3*time.Second {
+		t.Errorf("Drift = %v, want 3s", info.Drift)
+	}
+	if info.Zone != "CET" || info.Offset != "+0100" || info.TZ != "Europe/Madrid" {
+		t.Errorf("zone = %q %q TZ=%q", info.Zone, info.Offset, info.TZ)
+	}
+
+	// $TZ unset prints an empty last line
+	info, err = parseClockOutput("1699999990\nUTC +0000\n\n", before, after)
+	if err != nil {
+		t.Fatalf("parseClockOutput() error = %v", err)
+	}
+	if info.Drift != -10*time.Second || info.TZ != "" {
+		t.Errorf("info = %+v, want -10s drift and no TZ", info)
+	}
+
+	if _, err := parseClockOutput("date: not found\n", before, after); err == nil {
+		t.Error("parseClockOutput(garbage) expected error")
+	}
+}
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"", nil},
+		{"  psql  -U postgres ", []string{"psql", "-U", "postgres"}},
+		{`sh -c 'echo $HOME | wc -c'`, []string{"sh", "-c", "echo $HOME | wc -c"}},
+		{`GREETING="hello world" a\ b ""`, []string{"GREETING=hello world", "a b", ""}},
+		{`'it\'`, []string{`it\`}},
+	}
+	for _, tt := range tests {
+		got, err := SplitCommand(tt.input)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitCommand(%q) = %q, %v; want %q", tt.input, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{`echo "hi`, `echo \`} {
+		if _, err := SplitCommand(bad); err == nil {
+			t.Errorf("SplitCommand(%q) should fail", bad)
+		}
+	}
+}
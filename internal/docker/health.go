@@ -0,0 +1,87 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/moby/moby/api/types/container"
+
+	"tinyd/internal/types"
+)
+
+// containerHealth returns the healthcheck status of a listed container, or
+// "" without a healthcheck. Daemons older than API 1.52 only report it in
+// the status text, e.g. "Up 5 minutes (healthy)".
+func containerHealth(summary container.Summary) string {
+	if summary.Health != nil && summary.Health.Status != "" {
+		if summary.Health.Status == container.NoHealthcheck {
+			return ""
+		}
+		return string(summary.Health.Status)
+	}
+	switch {
+	case strings.Contains(summary.Status, "(unhealthy)"):
+		return string(container.Unhealthy)
+	case strings.Contains(summary.Status, "(healthy)"):
+		return string(container.Healthy)
+	case strings.Contains(summary.Status, "(health: starting)"):
+		return string(container.Starting)
+	}
+	return ""
+}
+
+// ContainerHealth returns a container's healthcheck with the results of its
+// last probes, which the daemon keeps five of
+func (c *Client) ContainerHealth(ctx context.Context, containerID string) (types.HealthLog, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutQuick)
+		defer cancel()
+	}
+
+	inspect, err := c.inspectContainer(ctx, containerID)
+	if err != nil {
+		return types.HealthLog{}, fmt.Errorf("failed to inspect container: %w", err)
+	}
+	var log types.HealthLog
+	if config := inspect.Container.Config; config != nil && config.Healthcheck != nil {
+		log.Test = healthTest(config.Healthcheck.Test)
+		log.Interval = config.Healthcheck.Interval
+	}
+	state := inspect.Container.State
+	if state == nil || state.Health == nil {
+		return log, nil
+	}
+	log.Status = string(state.Health.Status)
+	log.FailingStreak = state.Health.FailingStreak
+	for i := len(state.Health.Log) - 1; i >= 0; i-- {
+		result := state.Health.Log[i]
+		if result == nil {
+			continue
+		}
+		log.Probes = append(log.Probes, types.HealthProbe{
+			Start:    result.Start,
+			Duration: result.End.Sub(result.Start),
+			ExitCode: result.ExitCode,
+			Output:   strings.TrimSpace(result.Output),
+		})
+	}
+	return log, nil
+}
+
+// healthTest formats a healthcheck's test as the command it runs:
+// ["CMD-SHELL", "curl -f localhost"] as the shell line, ["CMD", ...] as
+// the arguments, and ["NONE"] as disabled
+func healthTest(test []string) string {
+	if len(test) == 0 {
+		return ""
+	}
+	switch test[0] {
+	case "NONE":
+		return "disabled"
+	case "CMD-SHELL", "CMD":
+		return strings.Join(test[1:], " ")
+	}
+	return strings.Join(test, " ")
+}
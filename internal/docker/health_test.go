@@ -0,0 +1,44 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/moby/moby/api/types/container"
+)
+
+func TestContainerHealth(t *testing.T) {
+	tests := []struct {
+		summary container.Summary
+		want    string
+	}{
+		{container.Summary{Health: &container.HealthSummary{Status: container.Unhealthy}, Status: "Up 5 minutes (healthy)"}, "unhealthy"},
+		{container.Summary{Health: &container.HealthSummary{Status: container.NoHealthcheck}}, ""},
+		// Older daemons only put it in the status text
+		{container.Summary{Status: "Up 5 minutes (healthy)"}, "healthy"},
+		{container.Summary{Status: "Up 2 hours (unhealthy)"}, "unhealthy"},
+		{container.Summary{Status: "Up 3 seconds (health: starting)"}, "starting"},
+		{container.Summary{Status: "Up 5 minutes"}, ""},
+	}
+	for _, tt := range tests {
+		if got := containerHealth(tt.summary); got != tt.want {
+			t.Errorf("containerHealth(%+v) = %q, want %q", tt.summary, got, tt.want)
+		}
+	}
+}
+
+func TestHealthTest(t *testing.T) {
+	tests := []struct {
+		test []string
+		want string
+	}{
+		{[]string{"CMD-SHELL", "curl -f http://localhost/ || exit 1"}, "curl -f http://localhost/ || exit 1"},
+		{[]string{"CMD", "pg_isready", "-U", "postgres"}, "pg_isready -U postgres"},
+		{[]string{"NONE"}, "disabled"},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := healthTest(tt.test); got != tt.want {
+			t.Errorf("healthTest(%q) = %q, want %q", tt.test, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"tinyd/internal/types"
+)
+
+// HostLoad reads the load average and memory use of the daemon's host from
+// /proc. That is only possible when the daemon runs on this machine: over a
+// unix socket on Linux, outside Docker Desktop's VM, and seeing as many CPUs
+// as we do (a socket forwarded from another host fails that). ok is false
+// otherwise.
+func (c *Client) HostLoad(info types.HostInfo) (load types.HostLoad, ok bool) {
+	if runtime.GOOS != "linux" || info.Desktop || info.NCPU != runtime.NumCPU() ||
+		!strings.HasPrefix(c.cli.DaemonHost(), "unix://") {
+		return types.HostLoad{}, false
+	}
+
+	loadavg, err := os.ReadFile("/proc/loadavg")
+	if err != nil {
+		return types.HostLoad{}, false
+	}
+	meminfo, err := os.ReadFile("/proc/meminfo")
+	if err != nil {
+		return types.HostLoad{}, false
+	}
+	load.Load1, load.Load5, load.Load15, err = parseLoadavg(string(loadavg))
+	if err != nil {
+		return types.HostLoad{}, false
+	}
+	total, available, err := parseMeminfo(string(meminfo))
+	if err != nil {
+		return types.HostLoad{}, false
+	}
+	load.MemUsed = total - available
+	return load, true
+}
+
+// parseLoadavg reads the 1, 5 and 15 minute load averages of /proc/loadavg
+func parseLoadavg(s string) (load1, load5, load15 float64, err error) {
+	fields := strings.Fields(s)
+	if len(fields) < 3 {
+		return 0, 0, 0, fmt.Errorf("invalid loadavg %q", s)
+	}
+	var loads [3]float64
+	for i := range loads {
+		if loads[i], err = strconv.ParseFloat(fields[i], 64); err != nil {
+			return 0, 0, 0, fmt.Errorf("invalid loadavg %q", s)
+		}
+	}
+	return loads[0], loads[1], loads[2], nil
+}
+
+// parseMeminfo reads the total and available memory of /proc/meminfo, in
+// bytes. Kernels before 3.14 don't report MemAvailable; free memory and the
+// page cache stand in for it.
+func parseMeminfo(s string) (total, available int64, err error) {
+	values := make(map[string]int64)
+	for _, line := range strings.Split(s, "\n") {
+		key, rest, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		if kb, err := strconv.ParseInt(fields[0], 10, 64); err == nil {
+			values[key] = kb * 1024
+		}
+	}
+
+	total = values["MemTotal"]
+	if total == 0 {
+		return 0, 0, errors.New("invalid meminfo: no MemTotal")
+	}
+	available, ok := values["MemAvailable"]
+	if !ok {
+		available = values["MemFree"] + values["Buffers"] + values["Cached"]
+	}
+	return total, min(available, total), nil
+}
@@ -0,0 +1,32 @@
+package docker
+
+import "testing"
+
+func TestParseLoadavg(t *testing.T) {
+	load1, load5, load15, err := parseLoadavg("1.52 0.98 0.40 2/1234 56789\n")
+	if err != nil || load1 != 1.52 || load5 != 0.98 || load15 != 0.40 {
+		t.Errorf("parseLoadavg() = %v %v %v, %v", load1, load5, load15, err)
+	}
+	for _, bad := range []string{"", "1.0 2.0", "a b c"} {
+		if _, _, _, err := parseLoadavg(bad); err == nil {
+			t.Errorf("parseLoadavg(%q) should fail", bad)
+		}
+	}
+}
+
+func TestParseMeminfo(t *testing.T) {
+	total, available, err := parseMeminfo("MemTotal:       16384000 kB\nMemFree:         1024000 kB\nMemAvailable:    8192000 kB\n")
+	if err != nil || total != 16384000*1024 || available != 8192000*1024 {
+		t.Errorf("parseMeminfo() = %d, %d, %v", total, available, err)
+	}
+
+	// Without MemAvailable free memory and the page cache count
+	total, available, err = parseMeminfo("MemTotal: 1000 kB\nMemFree: 100 kB\nBuffers: 50 kB\nCached: 250 kB\n")
+	if err != nil || total != 1000*1024 || available != 400*1024 {
+		t.Errorf("parseMeminfo() without MemAvailable = %d, %d, %v", total, available, err)
+	}
+
+	if _, _, err := parseMeminfo("MemFree: 100 kB\n"); err == nil {
+		t.Error("parseMeminfo() without MemTotal should fail")
+	}
+}
@@ -5,14 +5,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"sort"
 	"strings"
 	"time"
 
+	cerrdefs "github.com/containerd/errdefs"
 	"github.com/docker/go-units"
 	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/api/types/image"
+	"github.com/moby/moby/api/types/jsonstream"
 	"github.com/moby/moby/client"
 	"tinyd/internal/types"
 )
@@ -25,7 +26,21 @@ func (c *Client) FetchImages(ctx context.Context) ([]types.Image, error) {
 		defer cancel()
 	}
 
-	result, err := c.cli.ImageList(ctx, client.ImageListOptions{All: true})
+	// The image list leaves container counts out, so find used images from
+	// the containers, stopped ones included
+	containersResult, err := c.listContainers(ctx, client.ContainerListOptions{All: true})
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("operation timed out after %s", TimeoutQuick)
+		}
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	usedImages := make(map[string]bool)
+	for _, container := range containersResult.Items {
+		usedImages[container.ImageID] = true
+	}
+
+	result, err := c.listImages(ctx, client.ImageListOptions{All: !c.lowBandwidth})
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			return nil, fmt.Errorf("operation timed out after %s", TimeoutQuick)
@@ -37,6 +52,7 @@ func (c *Client) FetchImages(ctx context.Context) ([]types.Image, error) {
 
 	for _, img := range result.Items {
 		image := parseImage(img)
+		image.InUse = image.InUse || usedImages[img.ID]
 		images = append(images, image)
 	}
 
@@ -69,32 +85,204 @@ func (c *Client) DeleteImage(ctx context.Context, imageID string, force bool) er
 	return nil
 }
 
+// IsImageUsedByStoppedContainers reports whether an image deletion was
+// refused because stopped containers were still created from the image
+func IsImageUsedByStoppedContainers(err error) bool {
+	return cerrdefs.IsConflict(err) && strings.Contains(err.Error(), "stopped container")
+}
+
+// StoppedContainersUsingImage returns the stopped containers created from an
+// image, which keep it from being deleted without force
+func (c *Client) StoppedContainersUsingImage(ctx context.Context, imageID string) ([]types.Container, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutQuick)
+		defer cancel()
+	}
+
+	result, err := c.listContainers(ctx, client.ContainerListOptions{All: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	return stoppedContainersOf(result.Items, imageID), nil
+}
+
+// stoppedContainersOf picks the containers that are not running out of
+// items and were created from the image with the given (possibly short) ID
+func stoppedContainersOf(items []container.Summary, imageID string) []types.Container {
+	imageID = strings.TrimPrefix(imageID, "sha256:")
+	if imageID == "" {
+		return nil
+	}
+	var stopped []types.Container
+	for _, item := range items {
+		if !strings.HasPrefix(strings.TrimPrefix(item.ImageID, "sha256:"), imageID) {
+			continue
+		}
+		switch item.State {
+		case container.StateRunning, container.StatePaused, container.StateRestarting, container.StateRemoving:
+			continue
+		}
+		stopped = append(stopped, parseContainer(item))
+	}
+	return stopped
+}
+
+// LocalRef returns the repository and tag a pull of ref stores the image
+// under, in the form the images list shows them: Docker Hub names lose
+// their implied prefixes and a missing tag is latest. ok is false for a
+// digest reference, which doesn't move a tag.
+func LocalRef(ref string) (repo, tag string, ok bool) {
+	if strings.Contains(ref, "@") {
+		return "", "", false
+	}
+	repo, tag = splitRepoTag(ref)
+	if tag == "" {
+		tag = "latest"
+	}
+	return familiarName(repo), tag, true
+}
+
+// TagImage adds the reference target to the image source, e.g. to keep
+// the current image of a tag reachable before a pull moves the tag
+func (c *Client) TagImage(ctx context.Context, source, target string) error {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutQuick)
+		defer cancel()
+	}
+
+	if _, err := c.cli.ImageTag(ctx, client.ImageTagOptions{Source: source, Target: target}); err != nil {
+		return fmt.Errorf("failed to tag image: %w", err)
+	}
+	return nil
+}
+
 // PullImage pulls an image from a registry
 func (c *Client) PullImage(ctx context.Context, imageName string) error {
+	return c.PullImageWithProgress(ctx, imageName, nil)
+}
+
+// PullImageWithProgress pulls an image from a registry, calling progress
+// with a short summary (e.g. "downloading 42%, 2/5 layers done") whenever
+// it changes. progress may be nil.
+func (c *Client) PullImageWithProgress(ctx context.Context, imageName string, progress func(string)) error {
 	if ctx == nil {
 		var cancel context.CancelFunc
 		ctx, cancel = c.WithCustomTimeout(TimeoutLong)
 		defer cancel()
 	}
 
-	reader, err := c.cli.ImagePull(ctx, imageName, client.ImagePullOptions{})
+	resp, err := c.cli.ImagePull(ctx, imageName, client.ImagePullOptions{})
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			return fmt.Errorf("pull operation timed out after %s", TimeoutLong)
 		}
 		return fmt.Errorf("failed to pull image: %w", err)
 	}
-	defer reader.Close()
 
-	// Read the pull response to completion (required for the pull to actually happen)
-	_, err = io.ReadAll(reader)
-	if err != nil {
-		return fmt.Errorf("failed to read pull response: %w", err)
+	// The stream must be read to completion for the pull to actually happen
+	tracker := newPullTracker()
+	last := ""
+	for msg, err := range resp.JSONMessages(ctx) {
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				return fmt.Errorf("pull operation timed out after %s", TimeoutLong)
+			}
+			return fmt.Errorf("failed to read pull response: %w", err)
+		}
+		if msg.Error != nil {
+			return fmt.Errorf("failed to pull image: %s", msg.Error.Message)
+		}
+		tracker.update(msg)
+		if summary := tracker.summary(); progress != nil && summary != last {
+			progress(summary)
+			last = summary
+		}
 	}
 
 	return nil
 }
 
+// ImageExists reports whether an image reference is present locally
+func (c *Client) ImageExists(ctx context.Context, imageRef string) (bool, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutQuick)
+		defer cancel()
+	}
+
+	if _, err := c.inspectImage(ctx, imageRef); err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to inspect image: %w", err)
+	}
+	return true, nil
+}
+
+// pullTracker aggregates the per-layer messages of a pull stream
+type pullTracker struct {
+	layers  []string // Layer IDs in the order they were announced
+	current map[string]int64
+	total   map[string]int64
+	done    map[string]bool
+}
+
+func newPullTracker() *pullTracker {
+	return &pullTracker{
+		current: make(map[string]int64),
+		total:   make(map[string]int64),
+		done:    make(map[string]bool),
+	}
+}
+
+// update records a pull stream message
+func (t *pullTracker) update(msg jsonstream.Message) {
+	// Messages without an ID are overall status lines ("Pulling from ...")
+	if msg.ID == "" || strings.HasPrefix(msg.Status, "Pulling from") {
+		return
+	}
+	if _, seen := t.current[msg.ID]; !seen {
+		t.layers = append(t.layers, msg.ID)
+		t.current[msg.ID] = 0
+	}
+
+	switch msg.Status {
+	case "Downloading":
+		if msg.Progress != nil {
+			t.current[msg.ID] = msg.Progress.Current
+			t.total[msg.ID] = msg.Progress.Total
+		}
+	case "Download complete", "Verifying Checksum":
+		t.current[msg.ID] = t.total[msg.ID]
+	case "Pull complete", "Already exists":
+		t.current[msg.ID] = t.total[msg.ID]
+		t.done[msg.ID] = true
+	}
+}
+
+// summary describes the pull so far, "" before any layer is known
+func (t *pullTracker) summary() string {
+	if len(t.layers) == 0 {
+		return ""
+	}
+
+	var current, total int64
+	for _, id := range t.layers {
+		current += t.current[id]
+		total += t.total[id]
+	}
+
+	if len(t.done) == len(t.layers) {
+		return fmt.Sprintf("%d/%d layers done", len(t.done), len(t.layers))
+	}
+	if total == 0 {
+		return fmt.Sprintf("waiting, %d/%d layers done", len(t.done), len(t.layers))
+	}
+	return fmt.Sprintf("downloading %d%%, %d/%d layers done", current*100/total, len(t.done), len(t.layers))
+}
+
 // InspectImage retrieves detailed image information as JSON
 func (c *Client) InspectImage(ctx context.Context, imageID string) (string, error) {
 	if ctx == nil {
@@ -103,7 +291,7 @@ func (c *Client) InspectImage(ctx context.Context, imageID string) (string, erro
 		defer cancel()
 	}
 
-	inspectResult, err := c.cli.ImageInspect(ctx, imageID)
+	inspectResult, err := c.inspectImage(ctx, imageID)
 	if err != nil {
 		return "", fmt.Errorf("failed to inspect image: %w", err)
 	}
@@ -117,20 +305,19 @@ func (c *Client) InspectImage(ctx context.Context, imageID string) (string, erro
 	return string(jsonBytes), nil
 }
 
-// RunContainer creates and starts a container from an image
-func (c *Client) RunContainer(ctx context.Context, img *types.Image, containerName string, ports []types.PortMapping, volumes []types.VolumeMapping, envVars []types.EnvVar) (string, error) {
+// RunContainer creates and starts a container from an image reference
+// (repository:tag or image ID)
+func (c *Client) RunContainer(ctx context.Context, imageRef string, containerName string, hostname string, ports []types.PortMapping, volumes []types.VolumeMapping, envVars []types.EnvVar, labels []types.Label) (string, error) {
 	if ctx == nil {
 		var cancel context.CancelFunc
 		ctx, cancel = c.WithCustomTimeout(TimeoutMedium)
 		defer cancel()
 	}
 
-	// Build image reference
-	imageRef := img.Repository + ":" + img.Tag
-
 	// Build container config
 	config := &container.Config{
-		Image: imageRef,
+		Image:    imageRef,
+		Hostname: hostname,
 	}
 
 	// Add environment variables
@@ -142,6 +329,14 @@ func (c *Client) RunContainer(ctx context.Context, img *types.Image, containerNa
 		config.Env = env
 	}
 
+	// Add labels, e.g. for service discovery
+	if len(labels) > 0 {
+		config.Labels = make(map[string]string, len(labels))
+		for _, l := range labels {
+			config.Labels[l.Key] = l.Value
+		}
+	}
+
 	// Build host config for ports and volumes
 	hostConfig := &container.HostConfig{}
 
@@ -149,11 +344,7 @@ func (c *Client) RunContainer(ctx context.Context, img *types.Image, containerNa
 	if len(volumes) > 0 {
 		mounts := make([]string, len(volumes))
 		for i, v := range volumes {
-			if v.IsNamed {
-				mounts[i] = v.VolumeName + ":" + v.Container
-			} else {
-				mounts[i] = v.Host + ":" + v.Container
-			}
+			mounts[i] = volumeBind(v)
 		}
 		hostConfig.Binds = mounts
 	}
@@ -177,8 +368,61 @@ func (c *Client) RunContainer(ctx context.Context, img *types.Image, containerNa
 	return resp.ID[:12], nil
 }
 
+// ImageOf returns the index of the image a container was created from,
+// given the container's image reference: a "repo:tag", a bare repository
+// (meaning latest), or an image ID. When the tag was moved to a newer image
+// since, another tag of the repository is the best match. It returns -1
+// when no image matches.
+func ImageOf(images []types.Image, ref string) int {
+	if id, ok := strings.CutPrefix(ref, "sha256:"); ok {
+		for i, img := range images {
+			if strings.HasPrefix(id, img.ID) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	ref, _, _ = strings.Cut(ref, "@")
+	repo, tag := splitRepoTag(ref)
+	if tag == "" {
+		ref += ":latest"
+	}
+	fallback := -1
+	for i, img := range images {
+		if img.RepoTag == ref {
+			return i
+		}
+		if fallback < 0 && img.RepoTag != "" && ImageRepository(img.RepoTag) == repo {
+			fallback = i
+		}
+	}
+	return fallback
+}
+
 // Helper functions
 
+// volumeBind formats a volume mapping as a bind, "source:target" followed by
+// its options, e.g. "./src:/app:ro,cached"
+func volumeBind(v types.VolumeMapping) string {
+	bind := v.Host + ":" + v.Container
+	if v.IsNamed {
+		bind = v.VolumeName + ":" + v.Container
+	}
+
+	var options []string
+	if v.ReadOnly {
+		options = append(options, "ro")
+	}
+	if v.Consistency != "" {
+		options = append(options, v.Consistency)
+	}
+	if len(options) > 0 {
+		bind += ":" + strings.Join(options, ",")
+	}
+	return bind
+}
+
 func parseImage(img image.Summary) types.Image {
 	// Get repository and tag
 	repo := "<none>"
@@ -226,9 +470,66 @@ func parseImage(img image.Summary) types.Image {
 		Tag:        tag,
 		Size:       size,
 		Created:    createdStr,
+		CreatedAt:  created,
 		InUse:      inUse,
 		Dangling:   dangling,
+		Digest:     imageDigest(img.RepoDigests),
+		SizeBytes:  img.Size,
+		RepoTag:    firstRepoTag(img.RepoTags),
+
+		Source:       img.Labels[ociSourceLabel],
+		Revision:     img.Labels[ociRevisionLabel],
+		BuildCreated: img.Labels[ociCreatedLabel],
+
+		Labels: img.Labels,
+	}
+}
+
+// ImageLastTagTimes returns when each image was last tagged, which is when
+// it was last pulled, built or tagged locally. Images the daemon has no tag
+// time for are left out.
+func (c *Client) ImageLastTagTimes(ctx context.Context, imageIDs []string) (map[string]time.Time, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutMedium)
+		defer cancel()
+	}
+
+	times := make(map[string]time.Time, len(imageIDs))
+	for _, id := range imageIDs {
+		inspect, err := c.inspectImage(ctx, id)
+		if err != nil {
+			if cerrdefs.IsNotFound(err) {
+				continue // Removed since the last list
+			}
+			return times, fmt.Errorf("failed to inspect image: %w", err)
+		}
+		if inspect.Metadata.LastTagTime.IsZero() {
+			continue
+		}
+		times[id] = inspect.Metadata.LastTagTime
+	}
+	return times, nil
+}
+
+// StaleImages returns the images no container uses that were neither
+// created nor last tagged within olderThan of now. The tag time counts as
+// the image's last use, so an old image pulled yesterday isn't stale.
+func StaleImages(images []types.Image, lastTagged map[string]time.Time, olderThan time.Duration, now time.Time) []types.Image {
+	var stale []types.Image
+	for _, img := range images {
+		if img.InUse {
+			continue
+		}
+		lastUsed := img.CreatedAt
+		if tagged := lastTagged[img.ID]; tagged.After(lastUsed) {
+			lastUsed = tagged
+		}
+		if now.Sub(lastUsed) >= olderThan {
+			stale = append(stale, img)
+		}
 	}
+	return stale
 }
 
 func getImagePriority(img types.Image) int {
@@ -0,0 +1,162 @@
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/jsonstream"
+	"tinyd/internal/types"
+)
+
+func TestPullTracker(t *testing.T) {
+	tracker := newPullTracker()
+	if got := tracker.summary(); got != "" {
+		t.Errorf("summary() before any layer = %q, want empty", got)
+	}
+
+	tracker.update(jsonstream.Message{ID: "latest", Status: "Pulling from library/nginx"})
+	tracker.update(jsonstream.Message{ID: "a", Status: "Pulling fs layer"})
+	tracker.update(jsonstream.Message{ID: "b", Status: "Already exists"})
+	if got := tracker.summary(); got != "waiting, 1/2 layers done" {
+		t.Errorf("summary() = %q", got)
+	}
+
+	tracker.update(jsonstream.Message{ID: "a", Status: "Downloading", Progress: &jsonstream.Progress{Current: 25, Total: 100}})
+	if got := tracker.summary(); got != "downloading 25%, 1/2 layers done" {
+		t.Errorf("summary() = %q", got)
+	}
+
+	tracker.update(jsonstream.Message{ID: "a", Status: "Download complete"})
+	tracker.update(jsonstream.Message{ID: "a", Status: "Pull complete"})
+	if got := tracker.summary(); got != "2/2 layers done" {
+		t.Errorf("summary() = %q", got)
+	}
+}
+
+func TestStaleImages(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+
+	images := []types.Image{
+		{ID: "used", CreatedAt: daysAgo(400), InUse: true},
+		{ID: "old", CreatedAt: daysAgo(120)},
+		{ID: "recent", CreatedAt: daysAgo(10)},
+		{ID: "repulled", CreatedAt: daysAgo(300)},
+		{ID: "boundary", CreatedAt: daysAgo(30)},
+	}
+	lastTagged := map[string]time.Time{"repulled": daysAgo(2)}
+
+	got := StaleImages(images, lastTagged, 30*24*time.Hour, now)
+	var ids []string
+	for _, img := range got {
+		ids = append(ids, img.ID)
+	}
+	if want := []string{"old", "boundary"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("StaleImages() = %v, want %v", ids, want)
+	}
+}
+
+func TestStoppedContainersOf(t *testing.T) {
+	items := []container.Summary{
+		{ID: "aaa", Names: []string{"/old-web"}, ImageID: "sha256:0123456789abcdef", State: container.StateExited},
+		{ID: "bbb", Names: []string{"/web"}, ImageID: "sha256:0123456789abcdef", State: container.StateRunning},
+		{ID: "ccc", Names: []string{"/fresh"}, ImageID: "sha256:0123456789abcdef", State: container.StateCreated},
+		{ID: "ddd", Names: []string{"/db"}, ImageID: "sha256:fedcba9876543210", State: container.StateExited},
+	}
+
+	var names []string
+	for _, c := range stoppedContainersOf(items, "0123456789ab") {
+		names = append(names, c.Name)
+	}
+	if want := []string{"old-web", "fresh"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("stoppedContainersOf() = %v, want %v", names, want)
+	}
+	if got := stoppedContainersOf(items, ""); got != nil {
+		t.Errorf("stoppedContainersOf() with no image ID = %v, want none", got)
+	}
+}
+
+func TestIsImageUsedByStoppedContainers(t *testing.T) {
+	stopped := fmt.Errorf("failed to delete image: %w", cerrdefs.ErrConflict.WithMessage(
+		"conflict: unable to delete 0123456789ab (must be forced) - image is being used by stopped container aaa"))
+	running := fmt.Errorf("failed to delete image: %w", cerrdefs.ErrConflict.WithMessage(
+		"conflict: unable to delete 0123456789ab (cannot be forced) - image is being used by running container bbb"))
+
+	if !IsImageUsedByStoppedContainers(stopped) {
+		t.Error("conflict with a stopped container not recognized")
+	}
+	if IsImageUsedByStoppedContainers(running) {
+		t.Error("conflict with a running container reported as stopped")
+	}
+	if IsImageUsedByStoppedContainers(errors.New("image is being used by stopped container aaa")) {
+		t.Error("non-conflict error reported as stopped")
+	}
+}
+
+func TestVolumeBind(t *testing.T) {
+	tests := []struct {
+		volume types.VolumeMapping
+		want   string
+	}{
+		{types.VolumeMapping{Host: "/src", Container: "/app"}, "/src:/app"},
+		{types.VolumeMapping{Host: "/src", Container: "/app", ReadOnly: true}, "/src:/app:ro"},
+		{types.VolumeMapping{Host: "/src", Container: "/app", Consistency: "delegated"}, "/src:/app:delegated"},
+		{types.VolumeMapping{IsNamed: true, VolumeName: "data", Container: "/data", ReadOnly: true, Consistency: "cached"}, "data:/data:ro,cached"},
+	}
+	for _, tt := range tests {
+		if got := volumeBind(tt.volume); got != tt.want {
+			t.Errorf("volumeBind(%+v) = %q, want %q", tt.volume, got, tt.want)
+		}
+	}
+}
+
+func TestImageOf(t *testing.T) {
+	images := []types.Image{
+		{ID: "111111111111", RepoTag: "nginx:1.25"},
+		{ID: "222222222222", RepoTag: "nginx:latest"},
+		{ID: "333333333333", RepoTag: "ghcr.io/org/api:v2"},
+		{ID: "444444444444"},
+	}
+	tests := []struct {
+		ref  string
+		want int
+	}{
+		{"nginx", 1},
+		{"nginx:1.25", 0},
+		{"nginx:1.24", 0}, // Tag moved or removed: another tag of the repository
+		{"ghcr.io/org/api:v2@sha256:abc", 2},
+		{"sha256:444444444444aaaa", 3},
+		{"redis", -1},
+		{"sha256:999999999999", -1},
+	}
+	for _, tt := range tests {
+		if got := ImageOf(images, tt.ref); got != tt.want {
+			t.Errorf("ImageOf(%q) = %d, want %d", tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestLocalRef(t *testing.T) {
+	tests := []struct {
+		ref       string
+		repo, tag string
+		ok        bool
+	}{
+		{"nginx", "nginx", "latest", true},
+		{"docker.io/library/redis:7", "redis", "7", true},
+		{"registry:5000/team/app:1.2", "registry:5000/team/app", "1.2", true},
+		{"registry:5000/team/app", "registry:5000/team/app", "latest", true},
+		{"nginx@sha256:abc", "", "", false},
+	}
+	for _, tt := range tests {
+		repo, tag, ok := LocalRef(tt.ref)
+		if repo != tt.repo || tag != tt.tag || ok != tt.ok {
+			t.Errorf("LocalRef(%q) = (%q, %q, %t), want (%q, %q, %t)", tt.ref, repo, tag, ok, tt.repo, tt.tag, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync/atomic"
+
+	"tinyd/internal/types"
+)
+
+// firstRepoTag returns the first real "repo:tag" of an image, "" when it
+// is untagged
+func firstRepoTag(repoTags []string) string {
+	for _, tag := range repoTags {
+		if tag != "<none>:<none>" {
+			return tag
+		}
+	}
+	return ""
+}
+
+// SaveRefs returns what to save for the images: their tag, so `docker load`
+// restores it, or their ID when untagged. Duplicates are dropped.
+func SaveRefs(images []types.Image) []string {
+	var refs []string
+	for _, img := range images {
+		ref := img.RepoTag
+		if ref == "" {
+			ref = img.ID
+		}
+		if !slices.Contains(refs, ref) {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// SaveEstimate returns the size of the images added up. Layers shared by
+// several images are stored once in the archive, so it is an upper bound.
+func SaveEstimate(images []types.Image) int64 {
+	var total int64
+	for _, img := range images {
+		total += img.SizeBytes
+	}
+	return total
+}
+
+// SaveImages writes the images refs into one tar archive at path, as
+// `docker save` does, for `docker load` on another machine. progress is
+// called about once a second with the amount written against estimate.
+// A partial archive is removed when saving fails.
+func (c *Client) SaveImages(ctx context.Context, refs []string, path string, estimate int64, progress func(string)) (err error) {
+	if ctx == nil {
+		// Saves take as long as the data needs, so there is no deadline
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(context.Background())
+		defer cancel()
+	}
+
+	reader, err := c.cli.ImageSave(ctx, refs)
+	if err != nil {
+		return fmt.Errorf("failed to save images: %w", err)
+	}
+	defer reader.Close()
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to write %s: %w", path, closeErr)
+		}
+		if err != nil {
+			os.Remove(path)
+		}
+	}()
+
+	var written atomic.Int64
+	defer reportProgress(&written, estimate, progress)()
+
+	if _, err := io.Copy(file, io.TeeReader(reader, countWriter{&written})); err != nil {
+		return fmt.Errorf("failed to save images: %w", err)
+	}
+	return nil
+}
+
+// countWriter adds up the bytes written through it
+type countWriter struct {
+	n *atomic.Int64
+}
+
+func (w countWriter) Write(p []byte) (int, error) {
+	w.n.Add(int64(len(p)))
+	return len(p), nil
+}
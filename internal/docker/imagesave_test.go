@@ -0,0 +1,30 @@
+package docker
+
+import (
+	"reflect"
+	"testing"
+
+	"tinyd/internal/types"
+)
+
+func TestSaveRefs(t *testing.T) {
+	images := []types.Image{
+		{ID: "aaaaaaaaaaaa", RepoTag: "nginx:1.25", SizeBytes: 100},
+		{ID: "bbbbbbbbbbbb", SizeBytes: 50},
+		{ID: "aaaaaaaaaaaa", RepoTag: "nginx:1.25", SizeBytes: 100},
+	}
+	want := []string{"nginx:1.25", "bbbbbbbbbbbb"}
+	if got := SaveRefs(images); !reflect.DeepEqual(got, want) {
+		t.Errorf("SaveRefs() = %v, want %v", got, want)
+	}
+	if got := SaveEstimate(images[:2]); got != 150 {
+		t.Errorf("SaveEstimate() = %d, want 150", got)
+	}
+
+	if got := firstRepoTag([]string{"<none>:<none>", "app:dev"}); got != "app:dev" {
+		t.Errorf("firstRepoTag() = %q, want app:dev", got)
+	}
+	if got := firstRepoTag(nil); got != "" {
+		t.Errorf("firstRepoTag(nil) = %q, want empty", got)
+	}
+}
@@ -0,0 +1,99 @@
+package docker
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// LabelMatch is one term of a label filter: a label key that resources
+// must have, with Value when Exact, or must not have when Negate
+type LabelMatch struct {
+	Key    string
+	Value  string
+	Exact  bool
+	Negate bool
+}
+
+// ParseLabelFilter reads space-separated label terms as `docker ps
+// --filter` takes them: label=key for resources with that label,
+// label=key=value for that value of it, and label!=key or
+// label!=key=value for resources without. Empty matches every resource.
+func ParseLabelFilter(s string) ([]LabelMatch, error) {
+	var filter []LabelMatch
+	for _, term := range strings.Fields(s) {
+		kind, label, ok := strings.Cut(term, "=")
+		if !ok || label == "" || (kind != "label" && kind != "label!") {
+			return nil, fmt.Errorf("invalid filter %q: expected label=key[=value] or label!=key[=value]", term)
+		}
+		key, value, exact := strings.Cut(label, "=")
+		if key == "" {
+			return nil, fmt.Errorf("invalid filter %q: missing label key", term)
+		}
+		filter = append(filter, LabelMatch{Key: key, Value: value, Exact: exact, Negate: kind == "label!"})
+	}
+	return filter, nil
+}
+
+// MatchLabels reports whether labels satisfy every term of filter
+func MatchLabels(labels map[string]string, filter []LabelMatch) bool {
+	for _, term := range filter {
+		value, ok := labels[term.Key]
+		matched := ok && (!term.Exact || value == term.Value)
+		if matched == term.Negate {
+			return false
+		}
+	}
+	return true
+}
+
+// FormatLabels returns the labels as key=value lines, sorted by key
+func FormatLabels(labels map[string]string) []string {
+	lines := make([]string, 0, len(labels))
+	for _, key := range slices.Sorted(maps.Keys(labels)) {
+		lines = append(lines, key+"="+labels[key])
+	}
+	return lines
+}
+
+// composeLabelPrefix starts the labels Compose uses to tell which project
+// and service a container belongs to
+const composeLabelPrefix = "com.docker.compose."
+
+// LabelChanges describes what replacing labels with edited would do, one
+// line per label sorted by key: "+ KEY" (added), "~ KEY" (changed) or
+// "- KEY" (removed). Unchanged labels are omitted.
+func LabelChanges(labels, edited map[string]string) []string {
+	var lines []string
+	for _, key := range slices.Sorted(maps.Keys(edited)) {
+		value, exists := labels[key]
+		switch {
+		case !exists:
+			lines = append(lines, "+ "+key)
+		case value != edited[key]:
+			lines = append(lines, "~ "+key)
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(labels)) {
+		if _, kept := edited[key]; !kept {
+			lines = append(lines, "- "+key)
+		}
+	}
+	slices.SortFunc(lines, func(a, b string) int { return strings.Compare(a[2:], b[2:]) })
+	return lines
+}
+
+// TouchesComposeLabels reports whether replacing labels with edited changes
+// or removes a Compose label, which detaches the container from its project
+func TouchesComposeLabels(labels, edited map[string]string) bool {
+	for key, value := range labels {
+		if !strings.HasPrefix(key, composeLabelPrefix) {
+			continue
+		}
+		if kept, ok := edited[key]; !ok || kept != value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package docker
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseLabelFilter(t *testing.T) {
+	filter, err := ParseLabelFilter("label=team=core label=env  label!=com.example.skip=true label!=tmp")
+	if err != nil {
+		t.Fatalf("ParseLabelFilter() error: %v", err)
+	}
+	want := []LabelMatch{
+		{Key: "team", Value: "core", Exact: true},
+		{Key: "env"},
+		{Key: "com.example.skip", Value: "true", Exact: true, Negate: true},
+		{Key: "tmp", Negate: true},
+	}
+	if !slices.Equal(filter, want) {
+		t.Errorf("ParseLabelFilter() = %+v, want %+v", filter, want)
+	}
+
+	if filter, err := ParseLabelFilter("  "); err != nil || len(filter) != 0 {
+		t.Errorf("ParseLabelFilter(blank) = %v, %v, want no terms", filter, err)
+	}
+
+	for _, bad := range []string{"team=core", "label", "label=", "label==core", "name=web"} {
+		if _, err := ParseLabelFilter(bad); err == nil {
+			t.Errorf("ParseLabelFilter(%q) should fail", bad)
+		}
+	}
+}
+
+func TestMatchLabels(t *testing.T) {
+	labels := map[string]string{"team": "core", "env": "", "tier": "db"}
+
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		{"", true},
+		{"label=team", true},
+		{"label=team=core", true},
+		{"label=team=web", false},
+		{"label=env=", true}, // Set to an empty value
+		{"label=owner", false},
+		{"label!=owner", true},
+		{"label!=team", false},
+		{"label!=team=web", true},
+		{"label=team=core label=tier=db", true},
+		{"label=team=core label=tier=cache", false},
+	}
+	for _, tt := range tests {
+		filter, err := ParseLabelFilter(tt.filter)
+		if err != nil {
+			t.Fatalf("ParseLabelFilter(%q) error: %v", tt.filter, err)
+		}
+		if got := MatchLabels(labels, filter); got != tt.want {
+			t.Errorf("MatchLabels(%q) = %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestFormatLabels(t *testing.T) {
+	got := FormatLabels(map[string]string{"team": "core", "app": "web", "env": ""})
+	want := []string{"app=web", "env=", "team=core"}
+	if !slices.Equal(got, want) {
+		t.Errorf("FormatLabels() = %v, want %v", got, want)
+	}
+}
+
+func TestLabelChanges(t *testing.T) {
+	labels := map[string]string{"team": "core", "tier": "db", "owner": "ops"}
+	edited := map[string]string{"team": "core", "tier": "cache", "app": "web"}
+	got := LabelChanges(labels, edited)
+	want := []string{"+ app", "- owner", "~ tier"}
+	if !slices.Equal(got, want) {
+		t.Errorf("LabelChanges() = %v, want %v", got, want)
+	}
+	if got := LabelChanges(labels, labels); got != nil {
+		t.Errorf("LabelChanges() of unedited labels = %v, want none", got)
+	}
+}
+
+func TestTouchesComposeLabels(t *testing.T) {
+	labels := map[string]string{"com.docker.compose.project": "shop", "team": "core"}
+	tests := []struct {
+		name   string
+		edited map[string]string
+		want   bool
+	}{
+		{"other label changed", map[string]string{"com.docker.compose.project": "shop", "team": "web"}, false},
+		{"compose label changed", map[string]string{"com.docker.compose.project": "other", "team": "core"}, true},
+		{"compose label removed", map[string]string{"team": "core"}, true},
+	}
+	for _, tt := range tests {
+		if got := TouchesComposeLabels(labels, tt.edited); got != tt.want {
+			t.Errorf("%s: TouchesComposeLabels() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
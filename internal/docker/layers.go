@@ -0,0 +1,189 @@
+package docker
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"tinyd/internal/types"
+)
+
+// whiteoutPrefix marks files deleted by a layer (see the OCI image spec)
+const whiteoutPrefix = ".wh."
+
+// ImageLayers reads the image archive (as produced by docker save) and lists
+// the files each layer adds, modifies or deletes, oldest layer first
+func (c *Client) ImageLayers(ctx context.Context, imageID string) ([]types.ImageLayer, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutLong)
+		defer cancel()
+	}
+
+	history, err := c.cli.ImageHistory(ctx, imageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get image history: %w", err)
+	}
+
+	archive, err := c.cli.ImageSave(ctx, []string{imageID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save image: %w", err)
+	}
+	defer archive.Close()
+
+	layers, err := readImageArchive(archive)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("reading layers timed out after %s", TimeoutLong)
+		}
+		return nil, err
+	}
+
+	// History is newest first and includes steps that created no layer
+	var steps []string
+	for i := len(history.Items) - 1; i >= 0; i-- {
+		if history.Items[i].Size > 0 {
+			steps = append(steps, history.Items[i].CreatedBy)
+		}
+	}
+	if len(steps) == len(layers) {
+		for i := range layers {
+			layers[i].CreatedBy = steps[i]
+		}
+	}
+
+	return layers, nil
+}
+
+// readImageArchive lists the layer contents of an image archive. The manifest
+// may come after the layer blobs, so every blob that parses as a tar is
+// listed and the manifest only decides the order.
+func readImageArchive(r io.Reader) ([]types.ImageLayer, error) {
+	var manifest []struct {
+		Layers []string `json:"Layers"`
+	}
+	contents := make(map[string][]tarEntry)
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read image archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		if hdr.Name == "manifest.json" {
+			if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+				return nil, fmt.Errorf("failed to parse image manifest: %w", err)
+			}
+			continue
+		}
+
+		// Config and index blobs are JSON, not tars; skip anything that won't list
+		if entries, err := listLayerTar(tr); err == nil {
+			contents[hdr.Name] = entries
+		}
+	}
+
+	if len(manifest) == 0 {
+		return nil, fmt.Errorf("image archive has no manifest")
+	}
+
+	seen := make(map[string]bool)
+	layers := make([]types.ImageLayer, 0, len(manifest[0].Layers))
+	for _, name := range manifest[0].Layers {
+		entries, ok := contents[name]
+		if !ok {
+			return nil, fmt.Errorf("layer %s missing from image archive", name)
+		}
+
+		layer := types.ImageLayer{Digest: layerDigest(name)}
+		for _, entry := range entries {
+			dir, base := path.Split(entry.path)
+			switch {
+			case base == whiteoutPrefix+whiteoutPrefix+".opq":
+				// Opaque directory marker, nothing to show by itself
+				continue
+			case strings.HasPrefix(base, whiteoutPrefix):
+				deleted := dir + strings.TrimPrefix(base, whiteoutPrefix)
+				layer.Files = append(layer.Files, types.LayerFile{Path: deleted, Change: "D"})
+				delete(seen, deleted)
+				continue
+			}
+
+			change := "A"
+			if seen[entry.path] {
+				change = "M"
+			}
+			seen[entry.path] = true
+			layer.Size += entry.size
+			layer.Files = append(layer.Files, types.LayerFile{Path: entry.path, Size: entry.size, Change: change})
+		}
+
+		sort.Slice(layer.Files, func(i, j int) bool {
+			return layer.Files[i].Path < layer.Files[j].Path
+		})
+		layers = append(layers, layer)
+	}
+
+	return layers, nil
+}
+
+type tarEntry struct {
+	path string
+	size int64
+}
+
+// listLayerTar lists the non-directory entries of a (possibly gzipped) layer
+func listLayerTar(r io.Reader) ([]tarEntry, error) {
+	br := bufio.NewReader(r)
+	var layer io.Reader = br
+	if magic, err := br.Peek(2); err == nil && bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		layer = gz
+	}
+
+	var entries []tarEntry
+	tr := tar.NewReader(layer)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag == tar.TypeDir {
+			continue
+		}
+		name := "/" + strings.TrimPrefix(path.Clean(hdr.Name), "./")
+		entries = append(entries, tarEntry{path: name, size: hdr.Size})
+	}
+}
+
+// layerDigest derives a layer identifier from its path in the archive,
+// "blobs/sha256/<digest>" (OCI) or "<id>/layer.tar" (legacy)
+func layerDigest(name string) string {
+	if strings.HasSuffix(name, "/layer.tar") {
+		return path.Base(path.Dir(name))
+	}
+	return path.Base(name)
+}
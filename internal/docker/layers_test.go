@@ -0,0 +1,80 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"testing"
+)
+
+// buildTar writes the given name → content entries as a tar archive
+func buildTar(t *testing.T, files [][2]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, f := range files {
+		hdr := &tar.Header{Name: f[0], Mode: 0o644, Size: int64(len(f[1])), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(f[1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadImageArchive(t *testing.T) {
+	base := buildTar(t, [][2]string{
+		{"etc/config", "v1"},
+		{"usr/bin/app", "binary"},
+	})
+	update := buildTar(t, [][2]string{
+		{"etc/config", "v2!"},
+		{"etc/.wh.old", ""},
+		{"srv/new.txt", "hello"},
+	})
+
+	// Manifest deliberately comes last, as docker save writes it
+	archive := buildTar(t, [][2]string{
+		{"blobs/sha256/aaa", string(base)},
+		{"blobs/sha256/cfg", `{"architecture":"amd64"}`},
+		{"blobs/sha256/bbb", string(update)},
+		{"manifest.json", `[{"Config":"blobs/sha256/cfg","Layers":["blobs/sha256/aaa","blobs/sha256/bbb"]}]`},
+	})
+
+	layers, err := readImageArchive(bytes.NewReader(archive))
+	if err != nil {
+		t.Fatalf("readImageArchive() error = %v", err)
+	}
+	if len(layers) != 2 {
+		t.Fatalf("got %d layers, want 2", len(layers))
+	}
+
+	if layers[0].Digest != "aaa" || layers[0].Size != 8 || len(layers[0].Files) != 2 {
+		t.Errorf("layer 0 = %+v", layers[0])
+	}
+
+	want := map[string]string{
+		"/etc/config":  "M",
+		"/etc/old":     "D",
+		"/srv/new.txt": "A",
+	}
+	for _, f := range layers[1].Files {
+		if want[f.Path] != f.Change {
+			t.Errorf("layer 1 file %s change = %q, want %q", f.Path, f.Change, want[f.Path])
+		}
+	}
+	if len(layers[1].Files) != len(want) {
+		t.Errorf("layer 1 has %d files, want %d", len(layers[1].Files), len(want))
+	}
+}
+
+func TestReadImageArchiveNoManifest(t *testing.T) {
+	archive := buildTar(t, [][2]string{{"blobs/sha256/cfg", "{}"}})
+	if _, err := readImageArchive(bytes.NewReader(archive)); err == nil {
+		t.Error("expected error for archive without manifest")
+	}
+}
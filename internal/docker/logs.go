@@ -0,0 +1,316 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/moby/moby/api/pkg/stdcopy"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+)
+
+// LogsOptions selects which log lines GetContainerLogs returns
+type LogsOptions struct {
+	Tail       string    // Number of lines from the end, or "all"
+	Since      time.Time // Zero means from the beginning
+	Until      time.Time // Zero means up to now
+	Timestamps bool      // Prefix each line with the time Docker received it
+}
+
+// Layouts accepted by ParseLogTime for absolute times
+var logTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ParseLogTime parses a user-entered log time bound relative to now. It
+// accepts a duration ago ("30m", "2h"), a time of day ("14:00", "14:00:30"),
+// "yesterday 14:00", or a date ("2024-05-01 14:00", RFC 3339). An empty
+// string returns the zero time.
+func ParseLogTime(input string, now time.Time) (time.Time, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return time.Time{}, nil
+	}
+
+	if d, err := time.ParseDuration(input); err == nil && d > 0 {
+		return now.Add(-d), nil
+	}
+
+	day := now
+	if rest, ok := strings.CutPrefix(input, "yesterday"); ok {
+		day = now.AddDate(0, 0, -1)
+		input = strings.TrimSpace(rest)
+		if input == "" {
+			input = "00:00"
+		}
+	}
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if t, err := time.ParseInLocation(layout, input, now.Location()); err == nil {
+			return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), t.Second(), 0, now.Location()), nil
+		}
+	}
+
+	for _, layout := range logTimeLayouts {
+		if t, err := time.ParseInLocation(layout, input, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognized time %q", input)
+}
+
+// apiTimestamp formats a time bound for the logs API, "" when unset
+func apiTimestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return strconv.FormatInt(t.Unix(), 10)
+}
+
+// LastLogLine returns the most recent non-empty log line of a container
+func (c *Client) LastLogLine(ctx context.Context, containerID string) (string, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutQuick)
+		defer cancel()
+	}
+
+	logs, err := c.cli.ContainerLogs(ctx, containerID, client.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Tail:       "5",
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get logs: %w", err)
+	}
+	defer logs.Close()
+
+	raw, err := io.ReadAll(logs)
+	if err != nil {
+		return "", fmt.Errorf("failed to read logs: %w", err)
+	}
+
+	lines := strings.Split(demuxLogs(raw), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.Map(func(r rune) rune {
+			if unicode.IsPrint(r) {
+				return r
+			}
+			return -1
+		}, lines[i])
+		if line = strings.TrimSpace(line); line != "" {
+			return line, nil
+		}
+	}
+	return "", nil
+}
+
+// demuxLogs strips the stream headers from the logs of a container without a
+// TTY; TTY logs are returned unchanged
+func demuxLogs(raw []byte) string {
+	text, _ := demuxLogStreams(raw)
+	return text
+}
+
+// demuxLogStreams strips the stream headers like demuxLogs, keeping stdout
+// and stderr in the order they were written, and flags each line that
+// starts in a stderr frame. Raw bytes that aren't a valid frame sequence
+// are returned unchanged, without flags.
+func demuxLogStreams(raw []byte) (string, []bool) {
+	var out bytes.Buffer
+	var stderr []bool
+	lineStart := true
+	for rest := raw; len(rest) > 0; {
+		// Frames start with a stream byte (0-2), three zero bytes and the
+		// big-endian payload size
+		if len(rest) < 8 || rest[0] > 2 || rest[1] != 0 || rest[2] != 0 || rest[3] != 0 {
+			return string(raw), nil
+		}
+		size := int(binary.BigEndian.Uint32(rest[4:8]))
+		if size > len(rest)-8 {
+			return string(raw), nil
+		}
+		stream, frame := rest[0], rest[8:8+size]
+		rest = rest[8+size:]
+
+		for len(frame) > 0 {
+			if lineStart {
+				stderr = append(stderr, stdcopy.StdType(stream) == stdcopy.Stderr)
+			}
+			i := bytes.IndexByte(frame, '\n')
+			if i < 0 {
+				out.Write(frame)
+				lineStart = false
+				break
+			}
+			out.Write(frame[:i+1])
+			frame = frame[i+1:]
+			lineStart = true
+		}
+	}
+	return out.String(), stderr
+}
+
+// LogRestartMarker is the line FollowLogs emits when it re-attaches to a
+// container that restarted or was replaced
+const LogRestartMarker = "── container restarted ──"
+
+// restartPollInterval is how often FollowLogs checks whether a stopped
+// container is running again
+const restartPollInterval = time.Second
+
+// followTarget is the container instance FollowLogs is attached to
+type followTarget struct {
+	id        string
+	tty       bool
+	startedAt string
+}
+
+// FollowLogs streams the log lines a container writes from now on to emit,
+// until ctx is cancelled, prefixed with Docker's timestamps if asked. When
+// the container stops, it waits for it to run again, or for a new container
+// of the same name to run (as a compose recreate leaves behind), and
+// re-attaches after emitting LogRestartMarker.
+func (c *Client) FollowLogs(ctx context.Context, containerID string, timestamps bool, emit func(string)) error {
+	inspect, err := c.inspectContainer(ctx, containerID)
+	if err != nil {
+		return fmt.Errorf("failed to inspect container: %w", err)
+	}
+	name := strings.TrimPrefix(inspect.Container.Name, "/")
+	target := newFollowTarget(inspect.Container)
+
+	opts := client.ContainerLogsOptions{ShowStdout: true, ShowStderr: true, Follow: true, Tail: "0", Timestamps: timestamps}
+	for {
+		if err := c.streamLogs(ctx, target, opts, emit); err != nil {
+			return err
+		}
+		stoppedAt := time.Now()
+
+		next, err := c.waitForRestart(ctx, target, name)
+		if err != nil {
+			return err
+		}
+		if next.startedAt != target.startedAt || next.id != target.id {
+			emit(LogRestartMarker)
+		}
+
+		// A replacement shows its whole log; the same container only what
+		// came after the stop, since its log keeps the earlier lines
+		opts.Tail = "all"
+		opts.Since = ""
+		if next.id == target.id {
+			opts.Since = preciseTimestamp(stoppedAt)
+		}
+		target = next
+	}
+}
+
+// streamLogs emits the log lines of one container instance until its stream
+// ends. It returns nil when the container stopped and ctx.Err() on cancel.
+func (c *Client) streamLogs(ctx context.Context, target followTarget, opts client.ContainerLogsOptions, emit func(string)) error {
+	logs, err := c.cli.ContainerLogs(ctx, target.id, opts)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("failed to follow logs: %w", err)
+	}
+	defer logs.Close()
+
+	var r io.Reader = logs
+	if !target.tty {
+		// Demultiplex stdout and stderr frames into one line stream
+		pr, pw := io.Pipe()
+		go func() {
+			_, err := stdcopy.StdCopy(pw, pw, logs)
+			pw.CloseWithError(err)
+		}()
+		defer pr.Close()
+		r = pr
+	}
+
+	err = emitLines(r, emit)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// emitLines sends each line read from r to emit, without the line ending
+func emitLines(r io.Reader, emit func(string)) error {
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if line != "" {
+			emit(strings.TrimRight(line, "\r\n"))
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read logs: %w", err)
+		}
+	}
+}
+
+// waitForRestart polls until the followed container, or a new container of
+// the same name, is running
+func (c *Client) waitForRestart(ctx context.Context, target followTarget, name string) (followTarget, error) {
+	ticker := time.NewTicker(restartPollInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, ref := range []string{target.id, name} {
+			inspect, err := c.inspectContainer(ctx, ref)
+			if err != nil {
+				if cerrdefs.IsNotFound(err) {
+					continue
+				}
+				if ctx.Err() != nil {
+					return target, ctx.Err()
+				}
+				return target, fmt.Errorf("failed to inspect container: %w", err)
+			}
+			if inspect.Container.State != nil && inspect.Container.State.Running {
+				return newFollowTarget(inspect.Container), nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return target, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// newFollowTarget describes the instance of an inspected container
+func newFollowTarget(inspect container.InspectResponse) followTarget {
+	target := followTarget{id: inspect.ID}
+	if inspect.Config != nil {
+		target.tty = inspect.Config.Tty
+	}
+	if inspect.State != nil {
+		target.startedAt = inspect.State.StartedAt
+	}
+	return target
+}
+
+// preciseTimestamp formats a time for the logs API with sub-second
+// precision, so lines from the same second aren't repeated
+func preciseTimestamp(t time.Time) string {
+	return fmt.Sprintf("%d.%09d", t.Unix(), t.Nanosecond())
+}
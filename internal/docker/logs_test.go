@@ -0,0 +1,112 @@
+package docker
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDemuxLogs(t *testing.T) {
+	// Two frames: stdout "hello\n" and stderr "oops\n"
+	raw := []byte{1, 0, 0, 0, 0, 0, 0, 6}
+	raw = append(raw, "hello\n"...)
+	raw = append(raw, 2, 0, 0, 0, 0, 0, 0, 5)
+	raw = append(raw, "oops\n"...)
+
+	if got := demuxLogs(raw); got != "hello\noops\n" {
+		t.Errorf("demuxLogs(multiplexed) = %q", got)
+	}
+
+	// TTY output has no frame headers
+	if got := demuxLogs([]byte("plain output\n")); got != "plain output\n" {
+		t.Errorf("demuxLogs(tty) = %q", got)
+	}
+}
+
+func TestDemuxLogStreams(t *testing.T) {
+	frame := func(stream byte, payload string) []byte {
+		return append([]byte{stream, 0, 0, 0, 0, 0, 0, byte(len(payload))}, payload...)
+	}
+	// A line split across two stdout frames, then stderr lines
+	var raw []byte
+	raw = append(raw, frame(1, "starting ")...)
+	raw = append(raw, frame(1, "up\n")...)
+	raw = append(raw, frame(2, "warn: a\nwarn: b\n")...)
+	raw = append(raw, frame(1, "ready")...)
+
+	text, stderr := demuxLogStreams(raw)
+	if text != "starting up\nwarn: a\nwarn: b\nready" {
+		t.Errorf("demuxLogStreams() text = %q", text)
+	}
+	if want := []bool{false, true, true, false}; !reflect.DeepEqual(stderr, want) {
+		t.Errorf("demuxLogStreams() stderr = %v, want %v", stderr, want)
+	}
+
+	// A truncated frame is not a frame sequence
+	truncated := frame(1, "hello\n")[:10]
+	if text, stderr := demuxLogStreams(truncated); text != string(truncated) || stderr != nil {
+		t.Errorf("demuxLogStreams(truncated) = %q, %v", text, stderr)
+	}
+}
+
+func TestParseLogTime(t *testing.T) {
+	now := time.Date(2024, 5, 2, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{"", time.Time{}},
+		{"30m", now.Add(-30 * time.Minute)},
+		{"14:00", time.Date(2024, 5, 2, 14, 0, 0, 0, time.UTC)},
+		{"14:10:30", time.Date(2024, 5, 2, 14, 10, 30, 0, time.UTC)},
+		{"yesterday 14:00", time.Date(2024, 5, 1, 14, 0, 0, 0, time.UTC)},
+		{"yesterday", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{"2024-04-30 08:15", time.Date(2024, 4, 30, 8, 15, 0, 0, time.UTC)},
+		{"2024-04-30", time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC)},
+		{"2024-04-30T08:15:00Z", time.Date(2024, 4, 30, 8, 15, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseLogTime(tt.input, now)
+			if err != nil {
+				t.Fatalf("ParseLogTime(%q) error = %v", tt.input, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseLogTime(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLogTimeInvalid(t *testing.T) {
+	for _, input := range []string{"noon", "25:00", "-5m"} {
+		if _, err := ParseLogTime(input, time.Now()); err == nil {
+			t.Errorf("ParseLogTime(%q) expected error", input)
+		}
+	}
+}
+
+func TestEmitLines(t *testing.T) {
+	var lines []string
+	err := emitLines(strings.NewReader("first\r\nsecond\n\nunterminated"), func(line string) {
+		lines = append(lines, line)
+	})
+	if err != nil {
+		t.Fatalf("emitLines() error = %v", err)
+	}
+
+	want := []string{"first", "second", "", "unterminated"}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("emitLines() = %q, want %q", lines, want)
+	}
+}
+
+func TestPreciseTimestamp(t *testing.T) {
+	ts := time.Unix(1714640000, 5000)
+	if got := preciseTimestamp(ts); got != "1714640000.000005000" {
+		t.Errorf("preciseTimestamp() = %q", got)
+	}
+}
@@ -0,0 +1,111 @@
+package docker
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"tinyd/internal/types"
+)
+
+// LogSearchTail bounds how many recent log lines of each container a log
+// search reads
+const LogSearchTail = 500
+
+// logSearchMatches is how many matching lines a search keeps per container
+const logSearchMatches = 5
+
+// logSearchWorkers is how many containers' logs are fetched at once
+const logSearchWorkers = 4
+
+// SearchLogs looks for query, case-insensitively, in the recent logs of
+// each container. Results are ordered by match count, most first; containers
+// without matches are left out unless their logs couldn't be read.
+func (c *Client) SearchLogs(ctx context.Context, containers []types.Container, query string) []types.LogSearchResult {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutMedium)
+		defer cancel()
+	}
+
+	results := make([]types.LogSearchResult, len(containers))
+	sem := make(chan struct{}, logSearchWorkers)
+	var wg sync.WaitGroup
+	for i, ctr := range containers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			result := types.LogSearchResult{ContainerID: ctr.ID, ContainerName: ctr.Name}
+			logs, err := c.GetContainerLogs(ctx, ctr.ID, LogsOptions{Tail: strconv.Itoa(LogSearchTail)})
+			if err != nil {
+				result.Err = err
+			} else {
+				result.Count, result.Matches = matchLogLines(demuxLogs([]byte(logs)), query, logSearchMatches)
+			}
+			results[i] = result
+		}()
+	}
+	wg.Wait()
+
+	found := results[:0]
+	for _, result := range results {
+		if result.Count > 0 || result.Err != nil {
+			found = append(found, result)
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].Count != found[j].Count {
+			return found[i].Count > found[j].Count
+		}
+		return found[i].ContainerName < found[j].ContainerName
+	})
+	return found
+}
+
+// matchLogLines counts the lines of logs containing query, ignoring case,
+// and returns up to limit of the most recent ones, oldest first
+func matchLogLines(logs, query string, limit int) (int, []string) {
+	query = strings.ToLower(query)
+	count := 0
+	var matches []string
+	for _, line := range strings.Split(logs, "\n") {
+		if !strings.Contains(strings.ToLower(line), query) {
+			continue
+		}
+		count++
+		matches = append(matches, strings.TrimRight(line, "\r"))
+		if len(matches) > limit {
+			matches = matches[1:]
+		}
+	}
+	return count, matches
+}
+
+// CompileLogQuery turns a search in the logs view into a case-insensitive
+// pattern: the query as literal text, or as a regular expression in regex
+// mode
+func CompileLogQuery(query string, regex bool) (*regexp.Regexp, error) {
+	if !regex {
+		query = regexp.QuoteMeta(query)
+	} else if _, err := regexp.Compile(query); err != nil {
+		return nil, err
+	}
+	return regexp.Compile("(?i)" + query)
+}
+
+// MatchingLines returns the indexes of the lines re matches
+func MatchingLines(lines []string, re *regexp.Regexp) []int {
+	var matches []int
+	for i, line := range lines {
+		if re.MatchString(line) {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
@@ -0,0 +1,52 @@
+package docker
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMatchLogLines(t *testing.T) {
+	logs := "ok\nERROR one\r\nfine\nerror two\nError three\n"
+
+	count, matches := matchLogLines(logs, "error", 2)
+	if count != 3 {
+		t.Errorf("count = %d, want 3", count)
+	}
+	if want := []string{"error two", "Error three"}; !slices.Equal(matches, want) {
+		t.Errorf("matches = %q, want the last two %q", matches, want)
+	}
+
+	if count, matches := matchLogLines(logs, "missing", 2); count != 0 || matches != nil {
+		t.Errorf("no match: got %d, %q", count, matches)
+	}
+}
+
+func TestCompileLogQuery(t *testing.T) {
+	lines := []string{"GET /api/users 200", "POST /api/users 500", "get /health 200", "timeout after 5s"}
+
+	literal, err := CompileLogQuery("get /", false)
+	if err != nil {
+		t.Fatalf("CompileLogQuery(literal) error: %v", err)
+	}
+	if got := MatchingLines(lines, literal); !slices.Equal(got, []int{0, 2}) {
+		t.Errorf("literal matches = %v, want [0 2]", got)
+	}
+
+	// Regex characters are literal outside regex mode
+	dot, _ := CompileLogQuery("5.0", false)
+	if got := MatchingLines(lines, dot); got != nil {
+		t.Errorf("literal \"5.0\" matches = %v, want none", got)
+	}
+
+	status, err := CompileLogQuery(` 5\d\d$`, true)
+	if err != nil {
+		t.Fatalf("CompileLogQuery(regex) error: %v", err)
+	}
+	if got := MatchingLines(lines, status); !slices.Equal(got, []int{1}) {
+		t.Errorf("regex matches = %v, want [1]", got)
+	}
+
+	if _, err := CompileLogQuery("(unclosed", true); err == nil {
+		t.Error("CompileLogQuery(invalid regex) succeeded, want an error")
+	}
+}
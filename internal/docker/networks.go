@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/moby/moby/api/types/network"
@@ -22,7 +23,7 @@ func (c *Client) FetchNetworks(ctx context.Context) ([]types.Network, error) {
 	}
 
 	// Get all containers to determine which networks are in use
-	containersResult, err := c.cli.ContainerList(ctx, client.ContainerListOptions{All: true})
+	containersResult, err := c.listContainers(ctx, client.ContainerListOptions{All: true})
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			return nil, fmt.Errorf("operation timed out after %s", TimeoutQuick)
@@ -40,7 +41,7 @@ func (c *Client) FetchNetworks(ctx context.Context) ([]types.Network, error) {
 		}
 	}
 
-	result, err := c.cli.NetworkList(ctx, client.NetworkListOptions{})
+	result, err := c.listNetworks(ctx, client.NetworkListOptions{})
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			return nil, fmt.Errorf("operation timed out after %s", TimeoutQuick)
@@ -93,7 +94,7 @@ func (c *Client) InspectNetwork(ctx context.Context, networkID string) (string,
 		defer cancel()
 	}
 
-	inspectResult, err := c.cli.NetworkInspect(ctx, networkID, client.NetworkInspectOptions{})
+	inspectResult, err := c.inspectNetwork(ctx, networkID)
 	if err != nil {
 		return "", fmt.Errorf("failed to inspect network: %w", err)
 	}
@@ -101,6 +102,73 @@ func (c *Client) InspectNetwork(ctx context.Context, networkID string) (string,
 	return formatNetworkInspect(inspectResult), nil
 }
 
+// Driver options understood by the built-in network drivers
+const (
+	optionMTU       = "com.docker.network.driver.mtu"
+	optionParent    = "parent"
+	optionEncrypted = "encrypted"
+)
+
+// CreateNetwork creates a network with the driver options of spec and
+// returns its ID
+func (c *Client) CreateNetwork(ctx context.Context, spec types.NetworkSpec) (string, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutMedium)
+		defer cancel()
+	}
+
+	options, err := networkCreateOptions(spec)
+	if err != nil {
+		return "", err
+	}
+	result, err := c.cli.NetworkCreate(ctx, spec.Name, options)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return "", fmt.Errorf("create operation timed out after %s", TimeoutMedium)
+		}
+		return "", fmt.Errorf("failed to create network: %w", err)
+	}
+	return result.ID, nil
+}
+
+// networkCreateOptions checks spec and turns it into create options. Parent
+// interfaces only apply to macvlan and ipvlan, encryption only to overlay.
+func networkCreateOptions(spec types.NetworkSpec) (client.NetworkCreateOptions, error) {
+	options := client.NetworkCreateOptions{Driver: spec.Driver, Options: map[string]string{}}
+	if spec.Name == "" {
+		return options, fmt.Errorf("network name is required")
+	}
+	if options.Driver == "" {
+		options.Driver = "bridge"
+	}
+
+	if spec.MTU != 0 {
+		// 68 is the smallest MTU IPv4 allows
+		if spec.MTU < 68 || spec.MTU > 65535 {
+			return options, fmt.Errorf("MTU must be between 68 and 65535")
+		}
+		options.Options[optionMTU] = strconv.Itoa(spec.MTU)
+	}
+	if spec.Parent != "" {
+		if options.Driver != "macvlan" && options.Driver != "ipvlan" {
+			return options, fmt.Errorf("a parent interface needs the macvlan or ipvlan driver")
+		}
+		options.Options[optionParent] = spec.Parent
+	}
+	if spec.Encrypted {
+		if options.Driver != "overlay" {
+			return options, fmt.Errorf("encryption needs the overlay driver")
+		}
+		options.Options[optionEncrypted] = "true"
+	}
+	if options.Driver == "overlay" {
+		// Containers started outside swarm services can only join attachable overlays
+		options.Attachable = true
+	}
+	return options, nil
+}
+
 // Helper functions
 
 func parseNetwork(net network.Summary) types.Network {
@@ -144,6 +212,7 @@ func parseNetwork(net network.Summary) types.Network {
 		IPv4:   ipv4,
 		IPv6:   ipv6,
 		InUse:  inUse,
+		Labels: net.Labels,
 	}
 }
 
@@ -162,8 +231,54 @@ func formatNetworkInspect(net client.NetworkInspectResult) string {
 	}
 
 	var b strings.Builder
-	b.WriteString("=== NETWORK DETAILS ===\n\n")
+	b.WriteString("=== DRIVER OPTIONS ===\n")
+	for _, line := range driverOptionLines(net.Network.Driver, net.Network.Options) {
+		b.WriteString(line + "\n")
+	}
+	b.WriteString("\n=== NETWORK DETAILS ===\n\n")
 	b.WriteString(string(jsonData))
 
 	return b.String()
 }
+
+// driverOptionLines summarizes the driver options of a network: the MTU
+// always, as MTU mismatches cause connections that hang instead of failing,
+// the parent interface of macvlan/ipvlan, overlay encryption, and any other
+// options as set
+func driverOptionLines(driver string, options map[string]string) []string {
+	lines := []string{"Driver: " + driver}
+
+	mtu := "daemon default (usually 1500)"
+	if value, ok := options[optionMTU]; ok {
+		mtu = value
+	}
+	lines = append(lines, "MTU: "+mtu)
+
+	switch driver {
+	case "macvlan", "ipvlan":
+		parent := options[optionParent]
+		if parent == "" {
+			parent = "none (internal network)"
+		}
+		lines = append(lines, "Parent interface: "+parent)
+	case "overlay":
+		encrypted := "no"
+		if _, ok := options[optionEncrypted]; ok {
+			encrypted = "yes"
+		}
+		lines = append(lines, "Encrypted: "+encrypted)
+	}
+
+	var other []string
+	for key, value := range options {
+		if key != optionMTU && key != optionParent && key != optionEncrypted {
+			other = append(other, fmt.Sprintf("  %s=%s", key, value))
+		}
+	}
+	if len(other) > 0 {
+		sort.Strings(other)
+		lines = append(lines, "Other options:")
+		lines = append(lines, other...)
+	}
+	return lines
+}
@@ -0,0 +1,59 @@
+package docker
+
+import (
+	"reflect"
+	"testing"
+
+	"tinyd/internal/types"
+)
+
+func TestNetworkCreateOptions(t *testing.T) {
+	options, err := networkCreateOptions(types.NetworkSpec{Name: "lan", Driver: "macvlan", MTU: 1450, Parent: "eth0"})
+	if err != nil {
+		t.Fatalf("networkCreateOptions() error = %v", err)
+	}
+	want := map[string]string{optionMTU: "1450", optionParent: "eth0"}
+	if options.Driver != "macvlan" || !reflect.DeepEqual(options.Options, want) {
+		t.Errorf("networkCreateOptions() = %s %v, want macvlan %v", options.Driver, options.Options, want)
+	}
+
+	options, err = networkCreateOptions(types.NetworkSpec{Name: "mesh", Driver: "overlay", Encrypted: true})
+	if err != nil || options.Options[optionEncrypted] != "true" || !options.Attachable {
+		t.Errorf("overlay options = %+v, %v", options, err)
+	}
+
+	if options, _ := networkCreateOptions(types.NetworkSpec{Name: "app"}); options.Driver != "bridge" {
+		t.Errorf("default driver = %q, want bridge", options.Driver)
+	}
+
+	invalid := []types.NetworkSpec{
+		{Driver: "bridge"},
+		{Name: "app", MTU: 40},
+		{Name: "app", Parent: "eth0"},
+		{Name: "app", Driver: "bridge", Encrypted: true},
+	}
+	for _, spec := range invalid {
+		if _, err := networkCreateOptions(spec); err == nil {
+			t.Errorf("networkCreateOptions(%+v) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestDriverOptionLines(t *testing.T) {
+	got := driverOptionLines("bridge", map[string]string{"com.docker.network.bridge.name": "br0"})
+	want := []string{
+		"Driver: bridge",
+		"MTU: daemon default (usually 1500)",
+		"Other options:",
+		"  com.docker.network.bridge.name=br0",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("driverOptionLines(bridge) = %q, want %q", got, want)
+	}
+
+	got = driverOptionLines("overlay", map[string]string{optionMTU: "1400", optionEncrypted: ""})
+	want = []string{"Driver: overlay", "MTU: 1400", "Encrypted: yes"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("driverOptionLines(overlay) = %q, want %q", got, want)
+	}
+}
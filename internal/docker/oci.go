@@ -0,0 +1,33 @@
+package docker
+
+import "strings"
+
+// Standard OCI annotations that CI pipelines set on the images they build
+const (
+	ociSourceLabel   = "org.opencontainers.image.source"
+	ociRevisionLabel = "org.opencontainers.image.revision"
+	ociCreatedLabel  = "org.opencontainers.image.created"
+)
+
+// ShortSource trims a source repository URL for display:
+// "https://github.com/org/repo.git" and "git@github.com:org/repo" both
+// become "github.com/org/repo"
+func ShortSource(source string) string {
+	source = strings.TrimSpace(source)
+	if rest, ok := strings.CutPrefix(source, "git@"); ok {
+		source = strings.Replace(rest, ":", "/", 1)
+	}
+	if _, rest, ok := strings.Cut(source, "://"); ok {
+		source = rest
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(source, "/"), ".git")
+}
+
+// ShortRevision abbreviates a git commit SHA to 7 characters like git does;
+// other revisions (tags, branch names) are returned unchanged
+func ShortRevision(revision string) string {
+	if len(revision) < 12 || strings.Trim(strings.ToLower(revision), "0123456789abcdef") != "" {
+		return revision
+	}
+	return revision[:7]
+}
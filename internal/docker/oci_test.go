@@ -0,0 +1,31 @@
+package docker
+
+import "testing"
+
+func TestShortSource(t *testing.T) {
+	tests := map[string]string{
+		"https://github.com/org/repo.git": "github.com/org/repo",
+		"https://gitlab.com/group/repo/":  "gitlab.com/group/repo",
+		"git@github.com:org/repo.git":     "github.com/org/repo",
+		"github.com/org/repo":             "github.com/org/repo",
+		"":                                "",
+	}
+	for input, want := range tests {
+		if got := ShortSource(input); got != want {
+			t.Errorf("ShortSource(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestShortRevision(t *testing.T) {
+	tests := map[string]string{
+		"3f2a9c1d8e7b6a5f4e3d2c1b0a9f8e7d6c5b4a3f": "3f2a9c1",
+		"v1.4.2": "v1.4.2",
+		"main":   "main",
+	}
+	for input, want := range tests {
+		if got := ShortRevision(input); got != want {
+			t.Errorf("ShortRevision(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cerrdefs "github.com/containerd/errdefs"
+)
+
+// ImagePlatforms returns the platform of each image, e.g. "linux/arm64" or
+// "linux/arm/v7". Images removed since the last list are left out.
+func (c *Client) ImagePlatforms(ctx context.Context, imageIDs []string) (map[string]string, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutMedium)
+		defer cancel()
+	}
+
+	platforms := make(map[string]string, len(imageIDs))
+	for _, id := range imageIDs {
+		inspect, err := c.inspectImage(ctx, id)
+		if err != nil {
+			if cerrdefs.IsNotFound(err) {
+				continue
+			}
+			return platforms, fmt.Errorf("failed to inspect image: %w", err)
+		}
+		platforms[id] = Platform(inspect.Os, inspect.Architecture, inspect.Variant)
+	}
+	return platforms, nil
+}
+
+// Platform joins an OS, architecture and variant as in "linux/arm/v7".
+// Architectures are normalized to the names images use, so that the
+// daemon's "x86_64" reads "amd64". It returns "" without an architecture.
+func Platform(os, arch, variant string) string {
+	if arch == "" {
+		return ""
+	}
+	if os == "" {
+		os = "linux"
+	}
+	platform := strings.ToLower(os) + "/" + normalizeArch(arch)
+	if variant != "" && !strings.Contains(platform, "/v") {
+		platform += "/" + variant
+	}
+	return platform
+}
+
+// normalizeArch maps uname machine names to Go architecture names
+func normalizeArch(arch string) string {
+	switch strings.ToLower(arch) {
+	case "x86_64", "x86-64", "amd64":
+		return "amd64"
+	case "aarch64", "arm64":
+		return "arm64"
+	case "armv7l", "armhf":
+		return "arm/v7"
+	case "armv6l", "armel":
+		return "arm/v6"
+	case "i386", "i686", "386":
+		return "386"
+	}
+	return strings.ToLower(arch)
+}
+
+// PlatformMismatch reports whether an image of one platform can't run
+// natively on a daemon of another, by OS and architecture. Variants are not
+// compared, as an arm/v6 image runs on arm/v7. Unknown platforms never
+// mismatch.
+func PlatformMismatch(image, daemon string) bool {
+	if image == "" || daemon == "" {
+		return false
+	}
+	return osArch(image) != osArch(daemon)
+}
+
+// osArch drops the variant of a platform
+func osArch(platform string) string {
+	parts := strings.SplitN(platform, "/", 3)
+	return strings.Join(parts[:min(len(parts), 2)], "/")
+}
+
+// ShortPlatform drops the OS of Linux platforms, which is nearly all of
+// them, e.g. "arm64" or "windows/amd64"
+func ShortPlatform(platform string) string {
+	return strings.TrimPrefix(platform, "linux/")
+}
@@ -0,0 +1,44 @@
+package docker
+
+import "testing"
+
+func TestPlatform(t *testing.T) {
+	tests := []struct {
+		os, arch, variant string
+		want              string
+	}{
+		{"linux", "amd64", "", "linux/amd64"},
+		{"linux", "x86_64", "", "linux/amd64"},
+		{"linux", "aarch64", "", "linux/arm64"},
+		{"linux", "arm", "v7", "linux/arm/v7"},
+		{"linux", "armv7l", "", "linux/arm/v7"},
+		{"", "arm64", "v8", "linux/arm64/v8"},
+		{"windows", "amd64", "", "windows/amd64"},
+		{"linux", "", "", ""},
+	}
+	for _, tt := range tests {
+		if got := Platform(tt.os, tt.arch, tt.variant); got != tt.want {
+			t.Errorf("Platform(%q, %q, %q) = %q, want %q", tt.os, tt.arch, tt.variant, got, tt.want)
+		}
+	}
+}
+
+func TestPlatformMismatch(t *testing.T) {
+	tests := []struct {
+		image, daemon string
+		want          bool
+	}{
+		{"linux/amd64", "linux/amd64", false},
+		{"linux/arm64/v8", "linux/arm64", false},
+		{"linux/arm/v6", "linux/arm/v7", false},
+		{"linux/arm64", "linux/amd64", true},
+		{"windows/amd64", "linux/amd64", true},
+		{"", "linux/amd64", false},
+		{"linux/arm64", "", false},
+	}
+	for _, tt := range tests {
+		if got := PlatformMismatch(tt.image, tt.daemon); got != tt.want {
+			t.Errorf("PlatformMismatch(%q, %q) = %v, want %v", tt.image, tt.daemon, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moby/moby/client"
+
+	"tinyd/internal/types"
+)
+
+// ParsePruneFilter reads space-separated key=value filters as `docker
+// container prune --filter` takes them: until=24h for containers created
+// more than 24 hours ago (or before a date such as 2024-05-01), and
+// label=key[=value] or label!=key[=value]. A bare duration such as "24h" is
+// short for until=24h. Empty prunes every stopped container.
+func ParsePruneFilter(s string) (client.Filters, error) {
+	filters := make(client.Filters)
+	for _, term := range strings.Fields(s) {
+		if _, err := time.ParseDuration(term); err == nil {
+			term = "until=" + term
+		}
+		key, value, ok := strings.Cut(term, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid filter %q: expected key=value", term)
+		}
+		switch key {
+		case "label", "label!":
+		case "until":
+			if !validUntil(value) {
+				return nil, fmt.Errorf("invalid filter %q: expected a duration such as 24h or a date such as 2024-05-01", term)
+			}
+		default:
+			return nil, fmt.Errorf("invalid filter %q: use until, label or label!", term)
+		}
+		filters.Add(key, value)
+	}
+	return filters, nil
+}
+
+// validUntil reports whether the daemon takes s as an until filter: a
+// duration, a Unix timestamp or a date and time
+func validUntil(s string) bool {
+	if d, err := time.ParseDuration(s); err == nil {
+		return d > 0
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return true
+	}
+	for _, layout := range []string{"2006-01-02", "2006-01-02T15:04:05", time.RFC3339} {
+		if _, err := time.Parse(layout, s); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// PruneContainers removes the stopped containers matching filter (see
+// ParsePruneFilter), like `docker container prune`
+func (c *Client) PruneContainers(ctx context.Context, filter string) (types.PruneReport, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutLong)
+		defer cancel()
+	}
+
+	filters, err := ParsePruneFilter(filter)
+	if err != nil {
+		return types.PruneReport{}, err
+	}
+	result, err := c.cli.ContainerPrune(ctx, client.ContainerPruneOptions{Filters: filters})
+	if err != nil {
+		return types.PruneReport{}, fmt.Errorf("failed to prune containers: %w", err)
+	}
+	return types.PruneReport{
+		Deleted:        len(result.Report.ContainersDeleted),
+		SpaceReclaimed: result.Report.SpaceReclaimed,
+	}, nil
+}
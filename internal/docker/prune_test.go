@@ -0,0 +1,24 @@
+package docker
+
+import "testing"
+
+func TestParsePruneFilter(t *testing.T) {
+	filters, err := ParsePruneFilter("24h  label=env=dev label!=keep")
+	if err != nil {
+		t.Fatalf("ParsePruneFilter() error: %v", err)
+	}
+	if !filters["until"]["24h"] || !filters["label"]["env=dev"] || !filters["label!"]["keep"] || len(filters) != 3 {
+		t.Errorf("ParsePruneFilter() = %v", filters)
+	}
+
+	for _, good := range []string{"", "until=2024-05-01", "until=1714521600", "until=2024-05-01T10:00:00Z"} {
+		if _, err := ParsePruneFilter(good); err != nil {
+			t.Errorf("ParsePruneFilter(%q) error: %v", good, err)
+		}
+	}
+	for _, bad := range []string{"until", "until=", "until=yesterday", "-1h", "status=exited"} {
+		if _, err := ParsePruneFilter(bad); err == nil {
+			t.Errorf("ParsePruneFilter(%q) should fail", bad)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/network"
+	"github.com/moby/moby/client"
+	"tinyd/internal/types"
+)
+
+// RecreateContainer replaces a container with a new one built from its
+// current configuration after mutate has been applied. The old container is
+// renamed aside until the new one is running, and restored if any step fails.
+// It returns the ID of the new container.
+func (c *Client) RecreateContainer(ctx context.Context, containerID string, mutate func(*container.Config, *container.HostConfig)) (string, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutLong)
+		defer cancel()
+	}
+
+	inspect, err := c.inspectContainer(ctx, containerID)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect container: %w", err)
+	}
+	old := inspect.Container
+	if old.Config == nil || old.HostConfig == nil {
+		return "", fmt.Errorf("container %s has no configuration", containerID)
+	}
+
+	name := strings.TrimPrefix(old.Name, "/")
+	wasRunning := old.State != nil && old.State.Running
+	config := *old.Config
+	hostConfig := *old.HostConfig
+
+	// A hostname defaulted from the old ID would otherwise stick around
+	if strings.HasPrefix(old.ID, config.Hostname) {
+		config.Hostname = ""
+	}
+	mutate(&config, &hostConfig)
+
+	networking := userNetworking(old.NetworkSettings, true)
+
+	if wasRunning {
+		if _, err := c.cli.ContainerStop(ctx, old.ID, client.ContainerStopOptions{}); err != nil {
+			return "", fmt.Errorf("failed to stop container: %w", err)
+		}
+	}
+
+	backupName := name + "-tinyd-old"
+	if _, err := c.cli.ContainerRename(ctx, old.ID, client.ContainerRenameOptions{NewName: backupName}); err != nil {
+		return "", errors.Join(fmt.Errorf("failed to rename container: %w", err), c.restore(ctx, old.ID, "", wasRunning))
+	}
+
+	created, err := c.cli.ContainerCreate(ctx, client.ContainerCreateOptions{
+		Config:           &config,
+		HostConfig:       &hostConfig,
+		NetworkingConfig: networking,
+		Name:             name,
+	})
+	if err != nil {
+		return "", errors.Join(fmt.Errorf("failed to create container: %w", err), c.restore(ctx, old.ID, name, wasRunning))
+	}
+
+	if wasRunning {
+		if _, err := c.cli.ContainerStart(ctx, created.ID, client.ContainerStartOptions{}); err != nil {
+			_, rmErr := c.cli.ContainerRemove(ctx, created.ID, client.ContainerRemoveOptions{Force: true})
+			return "", errors.Join(fmt.Errorf("failed to start new container: %w", err), rmErr, c.restore(ctx, old.ID, name, wasRunning))
+		}
+	}
+
+	if _, err := c.cli.ContainerRemove(ctx, old.ID, client.ContainerRemoveOptions{}); err != nil {
+		return created.ID, fmt.Errorf("recreated %s, but failed to remove %s: %w", name, backupName, err)
+	}
+
+	return created.ID, nil
+}
+
+// userNetworking keeps only the user-specified part of each network endpoint
+// of a container, for creating another container on the same networks.
+// Static addresses are dropped unless keepAddresses is set.
+func userNetworking(settings *container.NetworkSettings, keepAddresses bool) *network.NetworkingConfig {
+	if settings == nil || len(settings.Networks) == 0 {
+		return nil
+	}
+
+	networking := &network.NetworkingConfig{EndpointsConfig: make(map[string]*network.EndpointSettings)}
+	for netName, endpoint := range settings.Networks {
+		user := &network.EndpointSettings{
+			Links:      endpoint.Links,
+			Aliases:    endpoint.Aliases,
+			DriverOpts: endpoint.DriverOpts,
+			GwPriority: endpoint.GwPriority,
+		}
+		if keepAddresses {
+			user.IPAMConfig = endpoint.IPAMConfig
+		}
+		networking.EndpointsConfig[netName] = user
+	}
+	return networking
+}
+
+// restore puts the original container back after a failed recreate
+func (c *Client) restore(ctx context.Context, containerID, name string, start bool) error {
+	if name != "" {
+		if _, err := c.cli.ContainerRename(ctx, containerID, client.ContainerRenameOptions{NewName: name}); err != nil {
+			return fmt.Errorf("failed to restore original name: %w", err)
+		}
+	}
+	if start {
+		if _, err := c.cli.ContainerStart(ctx, containerID, client.ContainerStartOptions{}); err != nil {
+			return fmt.Errorf("failed to restart original container: %w", err)
+		}
+	}
+	return nil
+}
+
+// ContainerEnv returns the environment of a container as KEY=VALUE entries
+func (c *Client) ContainerEnv(ctx context.Context, containerID string) ([]string, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutQuick)
+		defer cancel()
+	}
+
+	inspect, err := c.inspectContainer(ctx, containerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
+	}
+	if inspect.Container.Config == nil {
+		return nil, nil
+	}
+	return inspect.Container.Config.Env, nil
+}
+
+// RecreateWithEnv recreates a container with the given variables added or
+// replaced in its environment
+func (c *Client) RecreateWithEnv(ctx context.Context, containerID string, changes []types.EnvVar) (string, error) {
+	return c.RecreateContainer(ctx, containerID, func(config *container.Config, _ *container.HostConfig) {
+		config.Env = MergeEnv(config.Env, changes)
+	})
+}
+
+// RecreateWithLabels recreates a container with labels in place of its
+// own. Labels set by the image come back on the new container even when
+// left out, since Docker adds them at create.
+func (c *Client) RecreateWithLabels(ctx context.Context, containerID string, labels map[string]string) (string, error) {
+	return c.RecreateContainer(ctx, containerID, func(config *container.Config, _ *container.HostConfig) {
+		config.Labels = labels
+	})
+}
+
+// MergeEnv returns env with each change replacing the variable of the same
+// key, or appended when the key is new
+func MergeEnv(env []string, changes []types.EnvVar) []string {
+	merged := append([]string(nil), env...)
+	for _, change := range changes {
+		entry := change.Key + "=" + change.Value
+		replaced := false
+		for i, existing := range merged {
+			if key, _, _ := strings.Cut(existing, "="); key == change.Key {
+				merged[i] = entry
+				replaced = true
+			}
+		}
+		if !replaced {
+			merged = append(merged, entry)
+		}
+	}
+	return merged
+}
+
+// EnvChanges describes what applying changes to env would do, one line per
+// variable: "+ KEY" (added) or "~ KEY" (changed). Values are left out since
+// they are often secrets. Unchanged variables are omitted.
+func EnvChanges(env []string, changes []types.EnvVar) []string {
+	current := make(map[string]string, len(env))
+	for _, entry := range env {
+		key, value, _ := strings.Cut(entry, "=")
+		current[key] = value
+	}
+
+	var lines []string
+	for _, change := range changes {
+		value, exists := current[change.Key]
+		switch {
+		case !exists:
+			lines = append(lines, "+ "+change.Key)
+		case value != change.Value:
+			lines = append(lines, "~ "+change.Key)
+		}
+	}
+	return lines
+}
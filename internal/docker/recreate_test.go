@@ -0,0 +1,42 @@
+package docker
+
+import (
+	"reflect"
+	"testing"
+
+	"tinyd/internal/types"
+)
+
+func TestMergeEnv(t *testing.T) {
+	env := []string{"PATH=/usr/bin", "API_KEY=old", "DEBUG=0"}
+	changes := []types.EnvVar{
+		{Key: "API_KEY", Value: "new"},
+		{Key: "REGION", Value: "eu"},
+	}
+
+	got := MergeEnv(env, changes)
+	want := []string{"PATH=/usr/bin", "API_KEY=new", "DEBUG=0", "REGION=eu"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeEnv() = %v, want %v", got, want)
+	}
+
+	// The input slice must not be modified
+	if env[1] != "API_KEY=old" {
+		t.Errorf("MergeEnv modified its input: %v", env)
+	}
+}
+
+func TestEnvChanges(t *testing.T) {
+	env := []string{"API_KEY=old", "DEBUG=0"}
+	changes := []types.EnvVar{
+		{Key: "API_KEY", Value: "new"},
+		{Key: "DEBUG", Value: "0"},
+		{Key: "REGION", Value: "eu"},
+	}
+
+	got := EnvChanges(env, changes)
+	want := []string{"~ API_KEY", "+ REGION"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EnvChanges() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,55 @@
+package docker
+
+import (
+	"context"
+	"strings"
+
+	"github.com/moby/moby/api/types/container"
+	"tinyd/internal/types"
+)
+
+// restartCount is the restart count of a container as of the listed state
+// it was inspected in
+type restartCount struct {
+	state string
+	count int
+}
+
+// fillRestarts sets the restart counts of the listed containers, which only
+// an inspect reports. The count can only change when the daemon restarts
+// the container, which goes through the restarting state, so a container is
+// inspected again only once its listed state changed.
+func (c *Client) fillRestarts(ctx context.Context, summaries []container.Summary, containers []types.Container) {
+	c.restartsMu.Lock()
+	defer c.restartsMu.Unlock()
+
+	seen := make(map[string]restartCount, len(summaries))
+	for i, summary := range summaries {
+		state := restartState(summary)
+		restarts, ok := c.restarts[summary.ID]
+		if !ok || restarts.state != state {
+			result, err := c.inspectContainer(ctx, summary.ID)
+			if err != nil {
+				continue // Retried on the next list
+			}
+			restarts = restartCount{state: state, count: result.Container.RestartCount}
+		}
+		seen[summary.ID] = restarts
+		containers[i].RestartCount = restarts.count
+	}
+	// Forget removed containers
+	c.restarts = seen
+}
+
+// restartState is the listed state of a container and, once it exited,
+// its exit code, e.g. "exited Exited (1)" for "Exited (1) 2 minutes ago"
+func restartState(summary container.Summary) string {
+	state := string(summary.State)
+	for _, prefix := range []string{"Exited (", "Restarting ("} {
+		if strings.HasPrefix(summary.Status, prefix) {
+			status, _, _ := strings.Cut(summary.Status, ")")
+			return state + " " + status + ")"
+		}
+	}
+	return state
+}
@@ -0,0 +1,26 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/moby/moby/api/types/container"
+)
+
+func TestRestartState(t *testing.T) {
+	tests := []struct {
+		state  container.ContainerState
+		status string
+		want   string
+	}{
+		{container.StateRunning, "Up 5 minutes (healthy)", "running"},
+		{container.StateExited, "Exited (1) 2 minutes ago", "exited Exited (1)"},
+		{container.StateRestarting, "Restarting (137) 3 seconds ago", "restarting Restarting (137)"},
+		{container.StateCreated, "Created", "created"},
+	}
+	for _, tt := range tests {
+		got := restartState(container.Summary{State: tt.state, Status: tt.status})
+		if got != tt.want {
+			t.Errorf("restartState(%q, %q) = %q, want %q", tt.state, tt.status, got, tt.want)
+		}
+	}
+}
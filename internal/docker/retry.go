@@ -0,0 +1,131 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"syscall"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/moby/moby/client"
+)
+
+// backoff is a retry policy: how many retries follow the first try, and
+// the delay before the first retry, doubling up to max
+type backoff struct {
+	retries int
+	base    time.Duration
+	max     time.Duration
+}
+
+// readRetry is the policy of the read calls behind the lists and inspects,
+// which a busy daemon sometimes fails for a moment: a dropped connection
+// or a 500 under load. Together the retries take about a second at most.
+var readRetry = backoff{retries: 3, base: 100 * time.Millisecond, max: 500 * time.Millisecond}
+
+// delay returns the jittered wait before a retry (0 for the first), so
+// that lists refreshed together don't retry in lockstep
+func (b backoff) delay(retry int) time.Duration {
+	d := min(b.base<<retry, b.max)
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// isTransient reports whether err is worth retrying: the connection to the
+// daemon dropped, or it answered with a server error or overload. Refused
+// connections and permission errors are not; the daemon isn't coming back
+// within a retry.
+func isTransient(err error) bool {
+	return errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) ||
+		cerrdefs.IsInternal(err) ||
+		cerrdefs.IsUnavailable(err) ||
+		cerrdefs.IsResourceExhausted(err)
+}
+
+// retry calls call until it succeeds, fails with an error that isn't
+// transient, or the retries of b run out, and returns the last result. It
+// gives up early when ctx is done.
+func retry[T any](ctx context.Context, b backoff, call func() (T, error)) (T, error) {
+	result, err := call()
+	for i := 0; i < b.retries && err != nil && isTransient(err); i++ {
+		timer := time.NewTimer(b.delay(i))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, err
+		case <-timer.C:
+		}
+		result, err = call()
+	}
+	return result, err
+}
+
+// listContainers is ContainerList, retried on transient errors
+func (c *Client) listContainers(ctx context.Context, opts client.ContainerListOptions) (client.ContainerListResult, error) {
+	return retry(ctx, readRetry, func() (client.ContainerListResult, error) {
+		return c.cli.ContainerList(ctx, opts)
+	})
+}
+
+// inspectContainer is ContainerInspect, retried on transient errors
+func (c *Client) inspectContainer(ctx context.Context, containerID string) (client.ContainerInspectResult, error) {
+	return retry(ctx, readRetry, func() (client.ContainerInspectResult, error) {
+		return c.cli.ContainerInspect(ctx, containerID, client.ContainerInspectOptions{})
+	})
+}
+
+// listImages is ImageList, retried on transient errors
+func (c *Client) listImages(ctx context.Context, opts client.ImageListOptions) (client.ImageListResult, error) {
+	return retry(ctx, readRetry, func() (client.ImageListResult, error) {
+		return c.cli.ImageList(ctx, opts)
+	})
+}
+
+// inspectImage is ImageInspect, retried on transient errors
+func (c *Client) inspectImage(ctx context.Context, imageID string) (client.ImageInspectResult, error) {
+	return retry(ctx, readRetry, func() (client.ImageInspectResult, error) {
+		return c.cli.ImageInspect(ctx, imageID)
+	})
+}
+
+// listVolumes is VolumeList, retried on transient errors
+func (c *Client) listVolumes(ctx context.Context, opts client.VolumeListOptions) (client.VolumeListResult, error) {
+	return retry(ctx, readRetry, func() (client.VolumeListResult, error) {
+		return c.cli.VolumeList(ctx, opts)
+	})
+}
+
+// inspectVolume is VolumeInspect, retried on transient errors
+func (c *Client) inspectVolume(ctx context.Context, volumeName string) (client.VolumeInspectResult, error) {
+	return retry(ctx, readRetry, func() (client.VolumeInspectResult, error) {
+		return c.cli.VolumeInspect(ctx, volumeName, client.VolumeInspectOptions{})
+	})
+}
+
+// listNetworks is NetworkList, retried on transient errors
+func (c *Client) listNetworks(ctx context.Context, opts client.NetworkListOptions) (client.NetworkListResult, error) {
+	return retry(ctx, readRetry, func() (client.NetworkListResult, error) {
+		return c.cli.NetworkList(ctx, opts)
+	})
+}
+
+// inspectNetwork is NetworkInspect, retried on transient errors
+func (c *Client) inspectNetwork(ctx context.Context, networkID string) (client.NetworkInspectResult, error) {
+	return retry(ctx, readRetry, func() (client.NetworkInspectResult, error) {
+		return c.cli.NetworkInspect(ctx, networkID, client.NetworkInspectOptions{})
+	})
+}
+
+// systemInfo is Info, retried on transient errors
+func (c *Client) systemInfo(ctx context.Context) (client.SystemInfoResult, error) {
+	return retry(ctx, readRetry, func() (client.SystemInfoResult, error) {
+		return c.cli.Info(ctx, client.InfoOptions{})
+	})
+}
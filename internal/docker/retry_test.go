@@ -0,0 +1,85 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"syscall"
+	"testing"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+)
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{io.EOF, true},
+		{fmt.Errorf("read: %w", syscall.ECONNRESET), true},
+		{cerrdefs.ErrInternal, true},
+		{cerrdefs.ErrUnavailable, true},
+		{cerrdefs.ErrNotFound, false},
+		{syscall.ECONNREFUSED, false},
+		{context.DeadlineExceeded, false},
+	}
+	for _, tt := range tests {
+		if got := isTransient(tt.err); got != tt.want {
+			t.Errorf("isTransient(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	b := backoff{retries: 5, base: 100 * time.Millisecond, max: 300 * time.Millisecond}
+	for retry, full := range []time.Duration{100, 200, 300, 300} {
+		full *= time.Millisecond
+		for range 20 {
+			if d := b.delay(retry); d < full/2 || d > full {
+				t.Fatalf("delay(%d) = %v, want within [%v, %v]", retry, d, full/2, full)
+			}
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	fast := backoff{retries: 3, base: time.Millisecond, max: time.Millisecond}
+	calls := 0
+	failTwice := func() (string, error) {
+		calls++
+		if calls <= 2 {
+			return "", io.ErrUnexpectedEOF
+		}
+		return "ok", nil
+	}
+	if got, err := retry(context.Background(), fast, failTwice); err != nil || got != "ok" || calls != 3 {
+		t.Errorf("retry(transient twice) = %q, %v after %d calls", got, err, calls)
+	}
+
+	calls = 0
+	notFound := func() (string, error) {
+		calls++
+		return "", cerrdefs.ErrNotFound
+	}
+	if _, err := retry(context.Background(), fast, notFound); !errors.Is(err, cerrdefs.ErrNotFound) || calls != 1 {
+		t.Errorf("retry(not found) = %v after %d calls, want no retry", err, calls)
+	}
+
+	calls = 0
+	persistent := func() (string, error) {
+		calls++
+		return "", cerrdefs.ErrInternal
+	}
+	if _, err := retry(context.Background(), fast, persistent); !errors.Is(err, cerrdefs.ErrInternal) || calls != 4 {
+		t.Errorf("retry(persistent) = %v after %d calls, want 4", err, calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls = 0
+	if _, err := retry(ctx, backoff{retries: 3, base: time.Hour, max: time.Hour}, persistent); err == nil || calls != 1 {
+		t.Errorf("retry(cancelled) = %v after %d calls, want 1", err, calls)
+	}
+}
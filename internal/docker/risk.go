@@ -0,0 +1,134 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/moby/moby/api/types/container"
+	"tinyd/internal/types"
+)
+
+// Risk badges shown on container rows, most serious first
+const (
+	RiskPrivileged   = "PRIV"    // Privileged mode: all devices and capabilities
+	RiskDockerSocket = "SOCK"    // Docker socket mounted: control over the daemon
+	RiskHostPID      = "HOSTPID" // Shares the host's process namespace
+	RiskHostNetwork  = "HOSTNET" // Shares the host's network stack
+)
+
+// dockerSockets are where the daemon socket is usually mounted from
+var dockerSockets = []string{"/var/run/docker.sock", "/run/docker.sock"}
+
+// hostRisks are the settings behind the badges that the container list
+// doesn't include, so they need an inspect
+type hostRisks struct {
+	privileged bool
+	hostPID    bool
+}
+
+// containerRisks returns the risk badges of a container
+func containerRisks(host hostRisks, networkMode string, mounts []container.MountPoint) []string {
+	var risks []string
+	if host.privileged {
+		risks = append(risks, RiskPrivileged)
+	}
+	if slices.ContainsFunc(mounts, func(m container.MountPoint) bool { return isDockerSocket(m.Source) }) {
+		risks = append(risks, RiskDockerSocket)
+	}
+	if host.hostPID {
+		risks = append(risks, RiskHostPID)
+	}
+	if container.NetworkMode(networkMode).IsHost() {
+		risks = append(risks, RiskHostNetwork)
+	}
+	return risks
+}
+
+// isDockerSocket reports whether a host path is the daemon socket
+func isDockerSocket(path string) bool {
+	return slices.Contains(dockerSockets, path)
+}
+
+// fillRisks sets the risk badges of the listed containers. Privileged and
+// PID modes can't change after creation, so each container is inspected
+// once; one that fails to inspect is retried on the next list.
+func (c *Client) fillRisks(ctx context.Context, summaries []container.Summary, containers []types.Container) {
+	c.risksMu.Lock()
+	defer c.risksMu.Unlock()
+	if c.risks == nil {
+		c.risks = make(map[string]hostRisks)
+	}
+
+	seen := make(map[string]hostRisks, len(summaries))
+	for i, summary := range summaries {
+		host, ok := c.risks[summary.ID]
+		if !ok {
+			result, err := c.inspectContainer(ctx, summary.ID)
+			if err == nil && result.Container.HostConfig != nil {
+				host = hostRisks{
+					privileged: result.Container.HostConfig.Privileged,
+					hostPID:    result.Container.HostConfig.PidMode.IsHost(),
+				}
+				ok = true
+			}
+		}
+		if ok {
+			seen[summary.ID] = host
+		}
+		containers[i].Risks = containerRisks(host, summary.HostConfig.NetworkMode, summary.Mounts)
+	}
+	// Forget removed containers
+	c.risks = seen
+}
+
+// SecuritySummary lists the settings of a container worth a look in a
+// security review, from its inspect JSON: privileged mode, host namespaces,
+// a mounted Docker socket, added capabilities, relaxed security options and
+// running as root. It returns nil when the JSON can't be read.
+func SecuritySummary(inspectJSON string) []string {
+	var inspect container.InspectResponse
+	if err := json.Unmarshal([]byte(inspectJSON), &inspect); err != nil || inspect.HostConfig == nil {
+		return nil
+	}
+	host := inspect.HostConfig
+
+	var findings []string
+	if host.Privileged {
+		findings = append(findings, "Privileged: all host devices and capabilities")
+	}
+	for _, m := range inspect.Mounts {
+		if isDockerSocket(m.Source) {
+			access := "read-only"
+			if m.RW {
+				access = "read-write"
+			}
+			findings = append(findings, fmt.Sprintf("Docker socket mounted at %s (%s): full control over the daemon", m.Destination, access))
+		}
+	}
+	if host.PidMode.IsHost() {
+		findings = append(findings, "Host PID namespace: sees and can signal host processes")
+	}
+	if host.NetworkMode.IsHost() {
+		findings = append(findings, "Host network: shares the host's interfaces and ports")
+	}
+	if host.IpcMode.IsHost() {
+		findings = append(findings, "Host IPC namespace")
+	}
+	if len(host.CapAdd) > 0 {
+		findings = append(findings, "Added capabilities: "+strings.Join(host.CapAdd, ", "))
+	}
+	for _, opt := range host.SecurityOpt {
+		if strings.HasSuffix(opt, "unconfined") {
+			findings = append(findings, "Security option: "+opt)
+		}
+	}
+	if inspect.Config != nil {
+		if user := inspect.Config.User; user == "" || user == "0" || user == "root" || strings.HasPrefix(user, "0:") || strings.HasPrefix(user, "root:") {
+			findings = append(findings, "Runs as root")
+		}
+	}
+	return findings
+}
@@ -0,0 +1,52 @@
+package docker
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/moby/moby/api/types/container"
+)
+
+func TestContainerRisks(t *testing.T) {
+	socket := []container.MountPoint{{Type: "bind", Source: "/var/run/docker.sock", Destination: "/var/run/docker.sock"}}
+	tests := []struct {
+		host        hostRisks
+		networkMode string
+		mounts      []container.MountPoint
+		want        []string
+	}{
+		{hostRisks{}, "bridge", nil, nil},
+		{hostRisks{privileged: true, hostPID: true}, "host", socket, []string{RiskPrivileged, RiskDockerSocket, RiskHostPID, RiskHostNetwork}},
+		{hostRisks{}, "default", []container.MountPoint{{Type: "bind", Source: "/srv/docker.sock"}}, nil},
+	}
+	for _, tt := range tests {
+		if got := containerRisks(tt.host, tt.networkMode, tt.mounts); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("containerRisks(%+v, %q) = %v, want %v", tt.host, tt.networkMode, got, tt.want)
+		}
+	}
+}
+
+func TestSecuritySummary(t *testing.T) {
+	inspect := `{
+		"HostConfig": {"Privileged": true, "NetworkMode": "host", "CapAdd": ["NET_ADMIN"], "SecurityOpt": ["seccomp=unconfined", "label=disable"]},
+		"Mounts": [{"Type": "bind", "Source": "/run/docker.sock", "Destination": "/docker.sock", "RW": false}],
+		"Config": {"User": "app"}
+	}`
+	want := []string{
+		"Privileged: all host devices and capabilities",
+		"Docker socket mounted at /docker.sock (read-only): full control over the daemon",
+		"Host network: shares the host's interfaces and ports",
+		"Added capabilities: NET_ADMIN",
+		"Security option: seccomp=unconfined",
+	}
+	if got := SecuritySummary(inspect); !reflect.DeepEqual(got, want) {
+		t.Errorf("SecuritySummary() = %q, want %q", got, want)
+	}
+
+	if got := SecuritySummary(`{"HostConfig": {}, "Config": {"User": "root:root"}}`); !reflect.DeepEqual(got, []string{"Runs as root"}) {
+		t.Errorf("SecuritySummary(root) = %q", got)
+	}
+	if got := SecuritySummary("not json"); got != nil {
+		t.Errorf("SecuritySummary(invalid) = %q, want nil", got)
+	}
+}
@@ -0,0 +1,137 @@
+package docker
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/moby/moby/client"
+	"tinyd/internal/types"
+)
+
+var (
+	// "Bind for 0.0.0.0:8080 failed: port is already allocated" when another
+	// container publishes the port, "listen tcp4 0.0.0.0:8080: bind: address
+	// already in use" when a host process listens on it
+	portAllocatedPattern = regexp.MustCompile(`Bind for \S+:(\d+) failed: port is already allocated`)
+	portInUsePattern     = regexp.MustCompile(`listen \w+ \S+:(\d+): bind: address already in use`)
+
+	missingSourcePattern = regexp.MustCompile(`bind source path does not exist: (\S+)`)
+	notSharedPattern     = regexp.MustCompile(`(?i)the path (\S+) is not shared from the host`)
+	mountSourcePattern   = regexp.MustCompile(`error while creating mount source path '([^']+)': (.+)`)
+
+	notInPathPattern      = regexp.MustCompile(`exec: "([^"]+)": executable file not found in \$PATH`)
+	noSuchFilePattern     = regexp.MustCompile(`exec: "([^"]+)": stat \S+: no such file or directory`)
+	notExecutablePattern  = regexp.MustCompile(`exec: "([^"]+)": permission denied`)
+	networkMissingPattern = regexp.MustCompile(`network (\S+) not found`)
+)
+
+// inspectFix points at the inspect view, where mounts and the command are
+const inspectFix = "Press I on the container to review its configuration"
+
+// ClassifyStartError recognizes the common reasons the daemon refuses to
+// start a container in its error message: a host port already taken, a
+// missing or unshared bind mount source, a bad entrypoint or a removed
+// network. ok is false for other errors.
+func ClassifyStartError(msg string) (failure types.StartFailure, ok bool) {
+	if m := portAllocatedPattern.FindStringSubmatch(msg); m != nil {
+		return types.StartFailure{
+			Cause: "Port " + m[1] + " is already allocated by another container",
+			Port:  m[1],
+			Fixes: []string{"Stop the container publishing port " + m[1] + ", or recreate this one with another host port"},
+		}, true
+	}
+	if m := portInUsePattern.FindStringSubmatch(msg); m != nil {
+		return types.StartFailure{
+			Cause: "Port " + m[1] + " is in use by a process on the host",
+			Port:  m[1],
+			Fixes: []string{
+				"Find the process with: lsof -i :" + m[1] + " (or ss -ltnp 'sport = :" + m[1] + "')",
+				"Stop it, or recreate the container with another host port",
+			},
+		}, true
+	}
+
+	if m := missingSourcePattern.FindStringSubmatch(msg); m != nil {
+		return types.StartFailure{
+			Cause: "Bind mount source " + m[1] + " does not exist",
+			Fixes: []string{"Create it: mkdir -p " + m[1], inspectFix + " and fix the path"},
+		}, true
+	}
+	if m := notSharedPattern.FindStringSubmatch(msg); m != nil {
+		return types.StartFailure{
+			Cause: "Bind mount source " + m[1] + " is not shared with Docker Desktop",
+			Fixes: []string{"Add it in Docker Desktop: Settings → Resources → File sharing", inspectFix + " and mount a shared path instead"},
+		}, true
+	}
+	if m := mountSourcePattern.FindStringSubmatch(msg); m != nil {
+		return types.StartFailure{
+			Cause: "Could not create bind mount source " + m[1] + ": " + m[2],
+			Fixes: []string{"Create it yourself with the right owner: mkdir -p " + m[1], inspectFix + " and fix the path"},
+		}, true
+	}
+
+	if m := notInPathPattern.FindStringSubmatch(msg); m != nil {
+		return types.StartFailure{
+			Cause: "Entrypoint " + m[1] + " is not in the image's PATH",
+			Fixes: []string{"Use the full path of the executable, or install it in the image", inspectFix + " (Cmd and Entrypoint)"},
+		}, true
+	}
+	if m := noSuchFilePattern.FindStringSubmatch(msg); m != nil {
+		return types.StartFailure{
+			Cause: "Entrypoint " + m[1] + " does not exist in the image",
+			Fixes: []string{"Check the path, or whether a mount hides it", inspectFix + " (Cmd and Entrypoint)"},
+		}, true
+	}
+	if m := notExecutablePattern.FindStringSubmatch(msg); m != nil {
+		return types.StartFailure{
+			Cause: "Entrypoint " + m[1] + " is not executable",
+			Fixes: []string{"Make it executable in the image: RUN chmod +x " + m[1]},
+		}, true
+	}
+	if strings.Contains(msg, "exec format error") {
+		return types.StartFailure{
+			Cause: "The entrypoint was built for another CPU architecture",
+			Fixes: []string{"Pull or build the image for this platform, e.g. with --platform"},
+		}, true
+	}
+
+	if m := networkMissingPattern.FindStringSubmatch(msg); m != nil {
+		return types.StartFailure{
+			Cause: "Network " + m[1] + " no longer exists",
+			Fixes: []string{"Create it again (N on the networks tab), or recreate the container on another network"},
+		}, true
+	}
+	return types.StartFailure{}, false
+}
+
+// DiagnoseStart explains a failed start of containerID. For a port already
+// allocated, the running container publishing it is looked up so the UI
+// can point at it. ok is false for errors ClassifyStartError doesn't know.
+func (c *Client) DiagnoseStart(ctx context.Context, err error, containerID string) (types.StartFailure, bool) {
+	failure, ok := ClassifyStartError(err.Error())
+	if !ok || failure.Port == "" {
+		return failure, ok
+	}
+
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutQuick)
+		defer cancel()
+	}
+	filters := make(client.Filters).Add("publish", failure.Port).Add("status", "running")
+	result, listErr := c.listContainers(ctx, client.ContainerListOptions{Filters: filters})
+	if listErr != nil {
+		return failure, true // The diagnosis stands without the holder
+	}
+	for _, summary := range result.Items {
+		holder := parseContainer(summary)
+		if strings.HasPrefix(containerID, holder.ID) || strings.HasPrefix(holder.ID, containerID) {
+			continue
+		}
+		failure.Holder, failure.HolderID = holder.Name, holder.ID
+		failure.Fixes[0] = "Stop " + holder.Name + ", which publishes port " + failure.Port + ", or recreate this container with another host port"
+		break
+	}
+	return failure, true
+}
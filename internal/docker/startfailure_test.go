@@ -0,0 +1,67 @@
+package docker
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClassifyStartError(t *testing.T) {
+	tests := []struct {
+		msg   string
+		cause string
+		port  string
+	}{
+		{
+			"failed to start container: Error response from daemon: driver failed programming external connectivity on endpoint web (3f2a): Bind for 0.0.0.0:8080 failed: port is already allocated",
+			"Port 8080 is already allocated by another container", "8080",
+		},
+		{
+			"failed to start container: Error response from daemon: Error starting userland proxy: listen tcp4 0.0.0.0:5432: bind: address already in use",
+			"Port 5432 is in use by a process on the host", "5432",
+		},
+		{
+			`failed to start container: Error response from daemon: invalid mount config for type "bind": bind source path does not exist: /srv/data`,
+			"Bind mount source /srv/data does not exist", "",
+		},
+		{
+			"Error response from daemon: Mounts denied: \nThe path /opt/app is not shared from the host and is not known to Docker.",
+			"Bind mount source /opt/app is not shared with Docker Desktop", "",
+		},
+		{
+			`Error response from daemon: failed to create task for container: failed to create shim task: OCI runtime create failed: runc create failed: unable to start container process: exec: "serve": executable file not found in $PATH: unknown`,
+			"Entrypoint serve is not in the image's PATH", "",
+		},
+		{
+			`OCI runtime create failed: runc create failed: unable to start container process: exec: "/app/run.sh": stat /app/run.sh: no such file or directory: unknown`,
+			"Entrypoint /app/run.sh does not exist in the image", "",
+		},
+		{
+			`unable to start container process: exec: "/entrypoint.sh": permission denied: unknown`,
+			"Entrypoint /entrypoint.sh is not executable", "",
+		},
+		{
+			"Error response from daemon: network backend not found",
+			"Network backend no longer exists", "",
+		},
+	}
+	for _, tt := range tests {
+		failure, ok := ClassifyStartError(tt.msg)
+		if !ok {
+			t.Errorf("ClassifyStartError(%q) not recognized", tt.msg)
+			continue
+		}
+		if failure.Cause != tt.cause || failure.Port != tt.port {
+			t.Errorf("ClassifyStartError(%q) = %q port %q, want %q port %q", tt.msg, failure.Cause, failure.Port, tt.cause, tt.port)
+		}
+		if len(failure.Fixes) == 0 {
+			t.Errorf("ClassifyStartError(%q) suggests no fix", tt.msg)
+		}
+	}
+
+	if _, ok := ClassifyStartError("Error response from daemon: container is marked for removal"); ok {
+		t.Error("ClassifyStartError() recognized an unknown error")
+	}
+	if failure, _ := ClassifyStartError("Bind for [::]:443 failed: port is already allocated"); !strings.Contains(failure.Cause, "443") {
+		t.Errorf("ClassifyStartError() on IPv6 = %q, want port 443", failure.Cause)
+	}
+}
@@ -0,0 +1,246 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/moby/moby/client"
+	"tinyd/internal/types"
+)
+
+// statsJSON is the subset of the stats API response used by tinyd
+type statsJSON struct {
+	CPUStats struct {
+		CPUUsage struct {
+			TotalUsage  uint64   `json:"total_usage"`
+			PercpuUsage []uint64 `json:"percpu_usage"`
+		} `json:"cpu_usage"`
+		SystemUsage uint64 `json:"system_cpu_usage"`
+		OnlineCPUs  uint32 `json:"online_cpus"`
+	} `json:"cpu_stats"`
+	PreCPUStats struct {
+		CPUUsage struct {
+			TotalUsage uint64 `json:"total_usage"`
+		} `json:"cpu_usage"`
+		SystemUsage uint64 `json:"system_cpu_usage"`
+	} `json:"precpu_stats"`
+	MemoryStats struct {
+		Usage uint64 `json:"usage"`
+		Limit uint64 `json:"limit"`
+	} `json:"memory_stats"`
+	Read     time.Time `json:"read"`
+	Networks map[string]struct {
+		RxBytes uint64 `json:"rx_bytes"`
+		TxBytes uint64 `json:"tx_bytes"`
+	} `json:"networks"`
+	BlkioStats struct {
+		IoServiceBytesRecursive []struct {
+			Op    string `json:"op"`
+			Value uint64 `json:"value"`
+		} `json:"io_service_bytes_recursive"`
+	} `json:"blkio_stats"`
+}
+
+// calculateStats turns a raw stats sample into display values
+func calculateStats(s statsJSON) types.ContainerStats {
+	stats := types.ContainerStats{CPU: "--", Mem: "--", NetIO: "--", BlockIO: "--"}
+
+	// Calculate CPU percentage (needs a previous sample to diff against)
+	cpus := float64(s.CPUStats.OnlineCPUs)
+	if cpus == 0 {
+		cpus = float64(len(s.CPUStats.CPUUsage.PercpuUsage))
+	}
+	cpuDelta := float64(s.CPUStats.CPUUsage.TotalUsage) - float64(s.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(s.CPUStats.SystemUsage) - float64(s.PreCPUStats.SystemUsage)
+	if s.PreCPUStats.SystemUsage > 0 && systemDelta > 0.0 && cpuDelta >= 0.0 && cpus > 0 {
+		cpuPercent := (cpuDelta / systemDelta) * cpus * 100.0
+		stats.CPU = fmt.Sprintf("%.1f", cpuPercent)
+		stats.CPUPercent = cpuPercent
+	}
+
+	// Format memory
+	if s.MemoryStats.Usage > 0 {
+		stats.Mem = units.BytesSize(float64(s.MemoryStats.Usage))
+		stats.MemBytes = s.MemoryStats.Usage
+		stats.MemLimit = s.MemoryStats.Limit
+	}
+
+	// Network and block I/O totals since the container started, summed over
+	// its interfaces and devices. cgroup v1 reports ops as "Read"/"Write",
+	// v2 as "read"/"write".
+	for _, n := range s.Networks {
+		stats.NetRx += n.RxBytes
+		stats.NetTx += n.TxBytes
+	}
+	for _, io := range s.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(io.Op) {
+		case "read":
+			stats.BlockRead += io.Value
+		case "write":
+			stats.BlockWrite += io.Value
+		}
+	}
+
+	return stats
+}
+
+// withRates fills in the network and block I/O rates of a sample from the
+// totals of the previous one, taken elapsed earlier. A total that went
+// down, as it does when the container restarts, counts as no traffic.
+func withRates(stats, prev types.ContainerStats, elapsed time.Duration) types.ContainerStats {
+	if elapsed <= 0 {
+		return stats
+	}
+	rate := func(cur, prev uint64) float64 {
+		if cur < prev {
+			return 0
+		}
+		return float64(cur-prev) / elapsed.Seconds()
+	}
+	stats.NetRxRate = rate(stats.NetRx, prev.NetRx)
+	stats.NetTxRate = rate(stats.NetTx, prev.NetTx)
+	stats.BlockReadRate = rate(stats.BlockRead, prev.BlockRead)
+	stats.BlockWriteRate = rate(stats.BlockWrite, prev.BlockWrite)
+	stats.NetIO = FormatRate(stats.NetRxRate) + "/" + FormatRate(stats.NetTxRate)
+	stats.BlockIO = FormatRate(stats.BlockReadRate) + "/" + FormatRate(stats.BlockWriteRate)
+	return stats
+}
+
+// FormatRate formats bytes per second in a few characters for the table
+// columns, e.g. "0", "512B", "1.2k", "34M"
+func FormatRate(bytes float64) string {
+	if bytes < 1 {
+		return "0"
+	}
+	for _, unit := range []string{"B", "k", "M", "G"} {
+		switch {
+		case unit == "B" && bytes < 999.5:
+			return fmt.Sprintf("%.0fB", bytes)
+		case bytes < 9.95:
+			return fmt.Sprintf("%.1f%s", bytes, unit)
+		case bytes < 999.5:
+			return fmt.Sprintf("%.0f%s", bytes, unit)
+		}
+		bytes /= 1000
+	}
+	return fmt.Sprintf("%.0fT", bytes)
+}
+
+// StatsStreamer keeps one streaming stats connection per container and
+// remembers the latest sample of each, so stats can be refreshed on their own
+// interval independently of the container list
+type StatsStreamer struct {
+	cli     *client.Client
+	mu      sync.Mutex
+	streams map[string]*statsStream
+	latest  map[string]types.ContainerStats
+}
+
+type statsStream struct {
+	cancel context.CancelFunc
+}
+
+// NewStatsStreamer creates a streamer bound to this client
+func (c *Client) NewStatsStreamer() *StatsStreamer {
+	return &StatsStreamer{
+		cli:     c.cli,
+		streams: make(map[string]*statsStream),
+		latest:  make(map[string]types.ContainerStats),
+	}
+}
+
+// Sync makes the set of open streams match containerIDs: new containers get a
+// stream, containers no longer listed have theirs cancelled
+func (s *StatsStreamer) Sync(containerIDs []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wanted := make(map[string]bool, len(containerIDs))
+	for _, id := range containerIDs {
+		wanted[id] = true
+		if _, ok := s.streams[id]; !ok {
+			ctx, cancel := context.WithCancel(context.Background())
+			stream := &statsStream{cancel: cancel}
+			s.streams[id] = stream
+			go s.run(ctx, id, stream)
+		}
+	}
+
+	for id, stream := range s.streams {
+		if !wanted[id] {
+			stream.cancel()
+			delete(s.streams, id)
+			delete(s.latest, id)
+		}
+	}
+}
+
+// Snapshot returns the latest sample of every streamed container
+func (s *StatsStreamer) Snapshot() map[string]types.ContainerStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := make(map[string]types.ContainerStats, len(s.latest))
+	for id, stats := range s.latest {
+		snapshot[id] = stats
+	}
+	return snapshot
+}
+
+// Latest returns the latest sample of a streamed container
+func (s *StatsStreamer) Latest(containerID string) (types.ContainerStats, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats, ok := s.latest[containerID]
+	return stats, ok
+}
+
+// Close cancels all open streams
+func (s *StatsStreamer) Close() {
+	s.Sync(nil)
+}
+
+// run decodes samples from a single stream until it ends or is cancelled
+func (s *StatsStreamer) run(ctx context.Context, containerID string, stream *statsStream) {
+	defer func() {
+		// Forget the stream so the next Sync can reopen it (e.g. after a restart)
+		s.mu.Lock()
+		if s.streams[containerID] == stream {
+			delete(s.streams, containerID)
+		}
+		s.mu.Unlock()
+	}()
+
+	resp, err := s.cli.ContainerStats(ctx, containerID, client.ContainerStatsOptions{Stream: true})
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	decoder := json.NewDecoder(resp.Body)
+	var prev types.ContainerStats
+	var prevRead time.Time
+	for {
+		var sample statsJSON
+		if err := decoder.Decode(&sample); err != nil {
+			return
+		}
+
+		stats := calculateStats(sample)
+		if !prevRead.IsZero() {
+			stats = withRates(stats, prev, sample.Read.Sub(prevRead))
+		}
+		prev, prevRead = stats, sample.Read
+		s.mu.Lock()
+		if s.streams[containerID] == stream {
+			s.latest[containerID] = stats
+		}
+		s.mu.Unlock()
+	}
+}
@@ -0,0 +1,115 @@
+package docker
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"tinyd/internal/types"
+)
+
+func TestCalculateStats(t *testing.T) {
+	var s statsJSON
+	s.CPUStats.CPUUsage.TotalUsage = 300
+	s.CPUStats.SystemUsage = 2000
+	s.CPUStats.OnlineCPUs = 2
+	s.PreCPUStats.CPUUsage.TotalUsage = 100
+	s.PreCPUStats.SystemUsage = 1000
+	s.MemoryStats.Usage = 1024 * 1024
+
+	stats := calculateStats(s)
+	if stats.CPU != "40.0" {
+		t.Errorf("CPU = %q, want %q", stats.CPU, "40.0")
+	}
+	if stats.Mem != "1MiB" {
+		t.Errorf("Mem = %q, want %q", stats.Mem, "1MiB")
+	}
+}
+
+func TestCalculateStatsFirstSample(t *testing.T) {
+	// The first streamed sample has no previous CPU reading
+	var s statsJSON
+	s.CPUStats.CPUUsage.TotalUsage = 300
+	s.CPUStats.SystemUsage = 2000
+	s.CPUStats.OnlineCPUs = 2
+
+	stats := calculateStats(s)
+	if stats.CPU != "--" {
+		t.Errorf("CPU = %q, want %q", stats.CPU, "--")
+	}
+	if stats.Mem != "--" {
+		t.Errorf("Mem = %q, want %q", stats.Mem, "--")
+	}
+}
+
+func TestCalculateStatsPercpuFallback(t *testing.T) {
+	// cgroup v1 daemons may omit online_cpus
+	var s statsJSON
+	s.CPUStats.CPUUsage.TotalUsage = 200
+	s.CPUStats.CPUUsage.PercpuUsage = []uint64{100, 100, 0, 0}
+	s.CPUStats.SystemUsage = 1100
+	s.PreCPUStats.CPUUsage.TotalUsage = 100
+	s.PreCPUStats.SystemUsage = 100
+
+	stats := calculateStats(s)
+	if stats.CPU != "40.0" {
+		t.Errorf("CPU = %q, want %q", stats.CPU, "40.0")
+	}
+}
+
+func TestCalculateStatsIO(t *testing.T) {
+	var s statsJSON
+	if err := json.Unmarshal([]byte(`{
+		"networks": {"eth0": {"rx_bytes": 100, "tx_bytes": 10}, "eth1": {"rx_bytes": 50, "tx_bytes": 5}},
+		"blkio_stats": {"io_service_bytes_recursive": [
+			{"op": "Read", "value": 4096}, {"op": "Write", "value": 1024},
+			{"op": "read", "value": 4096}, {"op": "Total", "value": 9216}
+		]}
+	}`), &s); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := calculateStats(s)
+	if stats.NetRx != 150 || stats.NetTx != 15 {
+		t.Errorf("NetRx, NetTx = %d, %d, want 150, 15", stats.NetRx, stats.NetTx)
+	}
+	if stats.BlockRead != 8192 || stats.BlockWrite != 1024 {
+		t.Errorf("BlockRead, BlockWrite = %d, %d, want 8192, 1024", stats.BlockRead, stats.BlockWrite)
+	}
+}
+
+func TestWithRates(t *testing.T) {
+	prev := types.ContainerStats{NetRx: 1000, NetTx: 500, BlockRead: 0, BlockWrite: 4096}
+	cur := types.ContainerStats{NetRx: 5000, NetTx: 500, BlockRead: 2_000_000, BlockWrite: 0} // Written total reset by a restart
+
+	stats := withRates(cur, prev, 2*time.Second)
+	if stats.NetRxRate != 2000 || stats.NetTxRate != 0 || stats.BlockReadRate != 1_000_000 || stats.BlockWriteRate != 0 {
+		t.Errorf("rates = %v, %v, %v, %v, want 2000, 0, 1000000, 0", stats.NetRxRate, stats.NetTxRate, stats.BlockReadRate, stats.BlockWriteRate)
+	}
+	if stats.NetIO != "2.0k/0" || stats.BlockIO != "1.0M/0" {
+		t.Errorf("NetIO, BlockIO = %q, %q, want %q, %q", stats.NetIO, stats.BlockIO, "2.0k/0", "1.0M/0")
+	}
+}
+
+func TestFormatRate(t *testing.T) {
+	tests := []struct {
+		bytes float64
+		want  string
+	}{
+		{0, "0"},
+		{0.4, "0"},
+		{512, "512B"},
+		{999.7, "1.0k"},
+		{1234, "1.2k"},
+		{34_000, "34k"},
+		{999_400, "999k"},
+		{2_500_000, "2.5M"},
+		{120_000_000_000, "120G"},
+		{5e15, "5000T"},
+	}
+	for _, tt := range tests {
+		if got := FormatRate(tt.bytes); got != tt.want {
+			t.Errorf("FormatRate(%v) = %q, want %q", tt.bytes, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/docker/go-units"
+	"tinyd/internal/types"
+)
+
+// desktopPressure is the share of the Docker Desktop VM's CPU or memory in
+// use above which DesktopWarning warns
+const desktopPressure = 0.9
+
+// HostInfo reports the resources available to the daemon
+func (c *Client) HostInfo(ctx context.Context) (types.HostInfo, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutQuick)
+		defer cancel()
+	}
+
+	result, err := c.systemInfo(ctx)
+	if err != nil {
+		return types.HostInfo{}, fmt.Errorf("failed to get daemon info: %w", err)
+	}
+
+	return types.HostInfo{
+		Desktop:  strings.Contains(result.Info.OperatingSystem, "Docker Desktop"),
+		Rootless: slices.Contains(result.Info.SecurityOptions, "name=rootless"),
+		NCPU:     result.Info.NCPU,
+		MemTotal: result.Info.MemTotal,
+		Platform: Platform(result.Info.OSType, result.Info.Architecture, ""),
+	}, nil
+}
+
+// DesktopWarning explains why new containers may fail when running
+// containers use nearly all of the Docker Desktop VM's CPU or memory. cpu is
+// the summed CPU percentage (100 per core) and mem the summed memory usage.
+// It returns "" when not on Docker Desktop or usage is below the threshold.
+func DesktopWarning(info types.HostInfo, cpu float64, mem uint64) string {
+	if !info.Desktop {
+		return ""
+	}
+
+	if info.MemTotal > 0 && float64(mem) >= desktopPressure*float64(info.MemTotal) {
+		return fmt.Sprintf("Docker Desktop VM memory %d%% used (%s of %s): new containers may fail or be OOM-killed. Raise the limit in Settings > Resources.",
+			int(float64(mem)*100/float64(info.MemTotal)), units.BytesSize(float64(mem)), units.BytesSize(float64(info.MemTotal)))
+	}
+	if info.NCPU > 0 && cpu >= desktopPressure*float64(info.NCPU)*100 {
+		return fmt.Sprintf("Docker Desktop VM CPUs %d%% busy (%d CPUs): containers will be slow to start. Raise the limit in Settings > Resources.",
+			int(cpu/float64(info.NCPU)), info.NCPU)
+	}
+	return ""
+}
@@ -0,0 +1,37 @@
+package docker
+
+import (
+	"strings"
+	"testing"
+
+	"tinyd/internal/types"
+)
+
+func TestDesktopWarning(t *testing.T) {
+	desktop := types.HostInfo{Desktop: true, NCPU: 4, MemTotal: 8 << 30}
+
+	tests := []struct {
+		name string
+		info types.HostInfo
+		cpu  float64
+		mem  uint64
+		want string // Substring of the warning, "" for none
+	}{
+		{"not desktop", types.HostInfo{NCPU: 4, MemTotal: 8 << 30}, 400, 8 << 30, ""},
+		{"plenty left", desktop, 100, 2 << 30, ""},
+		{"memory", desktop, 100, 7680 << 20, "memory 93% used"},
+		{"cpu", desktop, 380, 1 << 30, "CPUs 95% busy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DesktopWarning(tt.info, tt.cpu, tt.mem)
+			if tt.want == "" && got != "" {
+				t.Errorf("DesktopWarning() = %q, want none", got)
+			}
+			if tt.want != "" && !strings.Contains(got, tt.want) {
+				t.Errorf("DesktopWarning() = %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"tinyd/internal/types"
+)
+
+// PlanTagCleanup sorts the tagged images of a repository into the keep
+// newest ones, by creation time, and the rest to delete. Images containers
+// were created from are kept whatever their age, as deleting them fails.
+func PlanTagCleanup(images []types.Image, repository string, keep int) types.TagCleanup {
+	var tagged []types.Image
+	for _, img := range images {
+		if img.RepoTag != "" && ImageRepository(img.RepoTag) == repository {
+			tagged = append(tagged, img)
+		}
+	}
+	sort.SliceStable(tagged, func(i, j int) bool {
+		if !tagged[i].CreatedAt.Equal(tagged[j].CreatedAt) {
+			return tagged[i].CreatedAt.After(tagged[j].CreatedAt)
+		}
+		return tagged[i].Tag > tagged[j].Tag
+	})
+
+	plan := types.TagCleanup{Repository: repository}
+	for i, img := range tagged {
+		switch {
+		case i < keep:
+			plan.Keep = append(plan.Keep, img)
+		case img.InUse:
+			plan.InUse = append(plan.InUse, img)
+		default:
+			plan.Delete = append(plan.Delete, img)
+		}
+	}
+	return plan
+}
+
+// DeleteTags removes the tags of a cleanup plan one by one; an image goes
+// away with its last tag. Failures don't stop the others and are reported
+// together at the end. progress is called before each tag.
+func (c *Client) DeleteTags(ctx context.Context, images []types.Image, progress func(string)) error {
+	var errs []error
+	for i, img := range images {
+		if ctx != nil && ctx.Err() != nil {
+			return ctx.Err()
+		}
+		progress(fmt.Sprintf("%d/%d %s", i+1, len(images), img.RepoTag))
+		if err := c.DeleteImage(ctx, img.RepoTag, false); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", img.RepoTag, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%d of %d tags not deleted: %w", len(errs), len(images), errors.Join(errs...))
+	}
+	return nil
+}
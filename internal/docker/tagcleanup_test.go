@@ -0,0 +1,43 @@
+package docker
+
+import (
+	"testing"
+	"time"
+
+	"tinyd/internal/types"
+)
+
+func TestPlanTagCleanup(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 0, 0, 0, 0, time.UTC) }
+	images := []types.Image{
+		{ID: "a", RepoTag: "registry.local:5000/app:1.0", Tag: "1.0", CreatedAt: day(1)},
+		{ID: "b", RepoTag: "registry.local:5000/app:1.1", Tag: "1.1", CreatedAt: day(2), InUse: true},
+		{ID: "c", RepoTag: "registry.local:5000/app:1.2", Tag: "1.2", CreatedAt: day(3)},
+		{ID: "d", RepoTag: "registry.local:5000/app:1.3", Tag: "1.3", CreatedAt: day(4)},
+		{ID: "e", RepoTag: "registry.local:5000/app:1.4", Tag: "1.4", CreatedAt: day(5)},
+		{ID: "f", RepoTag: "registry.local:5000/other:1.0", Tag: "1.0", CreatedAt: day(6)},
+		{ID: "g", Tag: "<none>", CreatedAt: day(7)},
+	}
+
+	plan := PlanTagCleanup(images, "registry.local:5000/app", 2)
+	ids := func(imgs []types.Image) string {
+		var s string
+		for _, img := range imgs {
+			s += img.ID
+		}
+		return s
+	}
+	if got := ids(plan.Keep); got != "ed" {
+		t.Errorf("Keep = %q, want the two newest (ed)", got)
+	}
+	if got := ids(plan.Delete); got != "ca" {
+		t.Errorf("Delete = %q, want ca", got)
+	}
+	if got := ids(plan.InUse); got != "b" {
+		t.Errorf("InUse = %q, want b", got)
+	}
+
+	if plan := PlanTagCleanup(images, "registry.local:5000/app", 10); len(plan.Delete) != 0 || len(plan.Keep) != 5 {
+		t.Errorf("PlanTagCleanup() keeping 10 = %+v, want nothing deleted", plan)
+	}
+}
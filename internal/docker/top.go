@@ -0,0 +1,70 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/moby/moby/client"
+	"tinyd/internal/types"
+)
+
+// topArgs are the ps arguments of ContainerProcesses; the daemon runs ps
+// on the host and keeps the container's processes
+var topArgs = []string{"-eo", "pid,user,pcpu,pmem,args"}
+
+// ContainerProcesses lists the processes running in a container, busiest
+// first. A ps that doesn't take topArgs (e.g. BusyBox on some hosts) gets
+// Docker's default arguments instead, which have no memory column.
+func (c *Client) ContainerProcesses(ctx context.Context, containerID string) ([]types.Process, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutQuick)
+		defer cancel()
+	}
+
+	result, err := c.cli.ContainerTop(ctx, containerID, client.ContainerTopOptions{Arguments: topArgs})
+	if err != nil {
+		result, err = c.cli.ContainerTop(ctx, containerID, client.ContainerTopOptions{})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+	return parseProcesses(result.Titles, result.Processes), nil
+}
+
+// parseProcesses reads ps output by its column titles, which depend on the
+// ps arguments, and sorts the processes by CPU, highest first
+func parseProcesses(titles []string, rows [][]string) []types.Process {
+	columns := make(map[string]int, len(titles))
+	for i, title := range titles {
+		columns[title] = i
+	}
+	field := func(row []string, names ...string) string {
+		for _, name := range names {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return row[i]
+			}
+		}
+		return ""
+	}
+
+	processes := make([]types.Process, 0, len(rows))
+	for _, row := range rows {
+		processes = append(processes, types.Process{
+			PID:     field(row, "PID"),
+			User:    field(row, "USER", "UID"),
+			CPU:     field(row, "%CPU", "C"),
+			Mem:     field(row, "%MEM"),
+			Command: field(row, "COMMAND", "CMD", "ARGS"),
+		})
+	}
+
+	cpu := func(p types.Process) float64 {
+		v, _ := strconv.ParseFloat(p.CPU, 64)
+		return v
+	}
+	sort.SliceStable(processes, func(i, j int) bool { return cpu(processes[i]) > cpu(processes[j]) })
+	return processes
+}
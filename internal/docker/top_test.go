@@ -0,0 +1,32 @@
+package docker
+
+import (
+	"reflect"
+	"testing"
+
+	"tinyd/internal/types"
+)
+
+func TestParseProcesses(t *testing.T) {
+	// Output of topArgs
+	titles := []string{"PID", "USER", "%CPU", "%MEM", "COMMAND"}
+	rows := [][]string{
+		{"101", "root", "0.1", "0.5", "nginx: master process"},
+		{"130", "nginx", "12.5", "1.2", "nginx: worker process"},
+	}
+	want := []types.Process{
+		{PID: "130", User: "nginx", CPU: "12.5", Mem: "1.2", Command: "nginx: worker process"},
+		{PID: "101", User: "root", CPU: "0.1", Mem: "0.5", Command: "nginx: master process"},
+	}
+	if got := parseProcesses(titles, rows); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseProcesses(custom) = %v, want %v", got, want)
+	}
+
+	// Docker's default "ps -ef" output
+	titles = []string{"UID", "PID", "PPID", "C", "STIME", "TTY", "TIME", "CMD"}
+	rows = [][]string{{"999", "4242", "4200", "3", "10:00", "?", "00:00:01", "redis-server *:6379"}}
+	want = []types.Process{{PID: "4242", User: "999", CPU: "3", Command: "redis-server *:6379"}}
+	if got := parseProcesses(titles, rows); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseProcesses(default) = %v, want %v", got, want)
+	}
+}
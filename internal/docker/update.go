@@ -0,0 +1,236 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/docker/go-units"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+
+	"tinyd/internal/types"
+)
+
+// Defaults the daemon applies when a container sets no CPU weight or period
+const (
+	defaultCPUShares = 1024
+	defaultCPUPeriod = 100000
+)
+
+// ContainerLimits returns the resource limits and restart policy of a
+// container as `docker update` takes them
+func (c *Client) ContainerLimits(ctx context.Context, containerID string) (types.ResourceLimits, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutQuick)
+		defer cancel()
+	}
+
+	inspect, err := c.inspectContainer(ctx, containerID)
+	if err != nil {
+		return types.ResourceLimits{}, fmt.Errorf("failed to inspect container: %w", err)
+	}
+	if inspect.Container.HostConfig == nil {
+		return types.ResourceLimits{}, errors.New("failed to inspect container: no host config")
+	}
+	return formatLimits(inspect.Container.HostConfig), nil
+}
+
+// ContainerAllocation returns the CPU and memory limits and the memory
+// reservation of a container
+func (c *Client) ContainerAllocation(ctx context.Context, containerID string) (types.ResourceAllocation, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutQuick)
+		defer cancel()
+	}
+
+	inspect, err := c.inspectContainer(ctx, containerID)
+	if err != nil {
+		return types.ResourceAllocation{}, fmt.Errorf("failed to inspect container: %w", err)
+	}
+	if inspect.Container.HostConfig == nil {
+		return types.ResourceAllocation{}, errors.New("failed to inspect container: no host config")
+	}
+	return allocation(inspect.Container.HostConfig), nil
+}
+
+// allocation reads the CPU limit, set either with --cpus or as a quota,
+// and the memory limit and reservation of hc
+func allocation(hc *container.HostConfig) types.ResourceAllocation {
+	alloc := types.ResourceAllocation{
+		MemoryLimit:       hc.Memory,
+		MemoryReservation: hc.MemoryReservation,
+	}
+	switch {
+	case hc.NanoCPUs > 0:
+		alloc.CPUs = float64(hc.NanoCPUs) / 1e9
+	case hc.CPUQuota > 0:
+		alloc.CPUs = float64(hc.CPUQuota) / float64(cpuPeriod(hc))
+	}
+	return alloc
+}
+
+// UpdateContainerLimits changes the resource limits and restart policy of a
+// running container in place, like `docker update`. Only the settings that
+// differ from the container's current ones are sent. It returns the warnings
+// of the daemon, e.g. when the kernel doesn't support a limit.
+func (c *Client) UpdateContainerLimits(ctx context.Context, containerID string, limits types.ResourceLimits) ([]string, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutMedium)
+		defer cancel()
+	}
+
+	inspect, err := c.inspectContainer(ctx, containerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
+	}
+	if inspect.Container.HostConfig == nil {
+		return nil, errors.New("failed to inspect container: no host config")
+	}
+	options, err := updateOptions(inspect.Container.HostConfig, limits)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.cli.ContainerUpdate(ctx, containerID, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update container: %w", err)
+	}
+	return result.Warnings, nil
+}
+
+// formatLimits shows the settings of a host config the way the update
+// modal takes them
+func formatLimits(hc *container.HostConfig) types.ResourceLimits {
+	limits := types.ResourceLimits{
+		CPUShares: strconv.FormatInt(defaultCPUShares, 10),
+		Restart:   string(container.RestartPolicyDisabled),
+	}
+	if hc.CPUShares > 0 {
+		limits.CPUShares = strconv.FormatInt(hc.CPUShares, 10)
+	}
+	switch {
+	case hc.NanoCPUs > 0:
+		limits.CPUs = strconv.FormatFloat(float64(hc.NanoCPUs)/1e9, 'f', -1, 64)
+	case hc.CPUQuota > 0:
+		limits.CPUs = strconv.FormatFloat(float64(hc.CPUQuota)/float64(cpuPeriod(hc)), 'f', -1, 64)
+	}
+	if hc.Memory > 0 {
+		limits.Memory = units.BytesSize(float64(hc.Memory))
+	}
+	if hc.RestartPolicy.Name != "" {
+		limits.Restart = string(hc.RestartPolicy.Name)
+		if hc.RestartPolicy.MaximumRetryCount > 0 {
+			limits.Restart += ":" + strconv.Itoa(hc.RestartPolicy.MaximumRetryCount)
+		}
+	}
+	return limits
+}
+
+// updateOptions turns the settings changed from those of hc into an
+// update. A CPU limit is kept in the form the container was created with,
+// as the daemon refuses to mix --cpus and --cpu-quota; a new one is set as
+// a quota, which unlike --cpus can be removed again. When the memory limit
+// changes the swap allowance on top of it is kept.
+func updateOptions(hc *container.HostConfig, limits types.ResourceLimits) (client.ContainerUpdateOptions, error) {
+	current := formatLimits(hc)
+	resources := container.Resources{}
+	var options client.ContainerUpdateOptions
+
+	if shares := strings.TrimSpace(limits.CPUShares); shares != current.CPUShares {
+		resources.CPUShares = defaultCPUShares
+		if shares != "" {
+			n, err := strconv.ParseInt(shares, 10, 64)
+			if err != nil || n < 2 {
+				return options, fmt.Errorf("invalid CPU shares %q: expected a weight of at least 2", shares)
+			}
+			resources.CPUShares = n
+		}
+		options.Resources = &resources
+	}
+
+	if cpus := strings.TrimSpace(limits.CPUs); cpus != current.CPUs {
+		switch {
+		case cpus == "" && hc.NanoCPUs > 0:
+			return options, errors.New("the CPU limit of a container created with --cpus can only be changed, not removed")
+		case cpus == "":
+			resources.CPUQuota = -1
+		default:
+			n, err := strconv.ParseFloat(cpus, 64)
+			if err != nil || n <= 0 || math.IsInf(n, 0) {
+				return options, fmt.Errorf("invalid CPUs %q: expected a number such as 1.5", cpus)
+			}
+			if hc.NanoCPUs > 0 {
+				resources.NanoCPUs = int64(math.Round(n * 1e9))
+			} else {
+				resources.CPUQuota = int64(math.Round(n * float64(cpuPeriod(hc))))
+			}
+		}
+		options.Resources = &resources
+	}
+
+	if memory := strings.TrimSpace(limits.Memory); memory != current.Memory {
+		if memory == "" {
+			return options, errors.New("a memory limit can only be changed, not removed, without recreating the container")
+		}
+		n, err := units.RAMInBytes(memory)
+		if err != nil || n < 6*1024*1024 {
+			return options, fmt.Errorf("invalid memory %q: expected a size of at least 6m such as 512m or 2g", memory)
+		}
+		resources.Memory = n
+		if hc.MemorySwap > 0 {
+			resources.MemorySwap = n + max(hc.MemorySwap-hc.Memory, 0)
+		}
+		options.Resources = &resources
+	}
+
+	if restart := strings.TrimSpace(limits.Restart); restart != current.Restart {
+		policy, err := parseRestartPolicy(restart)
+		if err != nil {
+			return options, err
+		}
+		options.RestartPolicy = &policy
+	}
+
+	if options.Resources == nil && options.RestartPolicy == nil {
+		return options, errors.New("nothing changed")
+	}
+	return options, nil
+}
+
+// parseRestartPolicy reads a restart policy as --restart takes it: no,
+// always, unless-stopped or on-failure with an optional retry count, e.g.
+// on-failure:3. Empty means no.
+func parseRestartPolicy(s string) (container.RestartPolicy, error) {
+	name, count, hasCount := strings.Cut(s, ":")
+	policy := container.RestartPolicy{Name: container.RestartPolicyMode(name)}
+	if name == "" {
+		policy.Name = container.RestartPolicyDisabled
+	}
+	if hasCount {
+		n, err := strconv.Atoi(count)
+		if err != nil {
+			return policy, fmt.Errorf("invalid restart policy %q: the retry count must be a number", s)
+		}
+		policy.MaximumRetryCount = n
+	}
+	if err := container.ValidateRestartPolicy(policy); err != nil {
+		return policy, err
+	}
+	return policy, nil
+}
+
+// cpuPeriod returns the CFS period CPU quotas of the container are
+// relative to
+func cpuPeriod(hc *container.HostConfig) int64 {
+	if hc.CPUPeriod > 0 {
+		return hc.CPUPeriod
+	}
+	return defaultCPUPeriod
+}
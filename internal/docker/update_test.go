@@ -0,0 +1,107 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/moby/moby/api/types/container"
+
+	"tinyd/internal/types"
+)
+
+func TestFormatLimits(t *testing.T) {
+	got := formatLimits(&container.HostConfig{
+		Resources:     container.Resources{Memory: 512 * 1024 * 1024, CPUQuota: 150000},
+		RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyOnFailure, MaximumRetryCount: 3},
+	})
+	want := types.ResourceLimits{CPUShares: "1024", CPUs: "1.5", Memory: "512MiB", Restart: "on-failure:3"}
+	if got != want {
+		t.Errorf("formatLimits() = %+v, want %+v", got, want)
+	}
+
+	got = formatLimits(&container.HostConfig{Resources: container.Resources{CPUShares: 512, NanoCPUs: 2e9}})
+	want = types.ResourceLimits{CPUShares: "512", CPUs: "2", Restart: "no"}
+	if got != want {
+		t.Errorf("formatLimits() = %+v, want %+v", got, want)
+	}
+}
+
+func TestAllocation(t *testing.T) {
+	got := allocation(&container.HostConfig{Resources: container.Resources{
+		Memory: 512 * 1024 * 1024, MemoryReservation: 256 * 1024 * 1024, CPUQuota: 50000, CPUPeriod: 100000,
+	}})
+	want := types.ResourceAllocation{CPUs: 0.5, MemoryLimit: 512 * 1024 * 1024, MemoryReservation: 256 * 1024 * 1024}
+	if got != want {
+		t.Errorf("allocation() = %+v, want %+v", got, want)
+	}
+
+	got = allocation(&container.HostConfig{Resources: container.Resources{NanoCPUs: 2e9}})
+	if got != (types.ResourceAllocation{CPUs: 2}) {
+		t.Errorf("allocation(--cpus) = %+v, want 2 CPUs", got)
+	}
+	if got := allocation(&container.HostConfig{}); got != (types.ResourceAllocation{}) {
+		t.Errorf("allocation(unset) = %+v, want zero", got)
+	}
+}
+
+func TestUpdateOptions(t *testing.T) {
+	hc := &container.HostConfig{
+		Resources: container.Resources{Memory: 512 * 1024 * 1024, MemorySwap: 1024 * 1024 * 1024},
+	}
+	limits := formatLimits(hc)
+
+	// Only what changed is sent, and the swap allowance follows the memory
+	limits.Memory = "1g"
+	options, err := updateOptions(hc, limits)
+	if err != nil {
+		t.Fatalf("updateOptions() error: %v", err)
+	}
+	r := options.Resources
+	if r == nil || r.Memory != 1024*1024*1024 || r.MemorySwap != 1536*1024*1024 || r.CPUShares != 0 || r.CPUQuota != 0 || options.RestartPolicy != nil {
+		t.Errorf("updateOptions() = %+v, %+v", r, options.RestartPolicy)
+	}
+
+	// A new CPU limit is a quota, and a removed one is lifted
+	limits = formatLimits(hc)
+	limits.CPUs = "0.5"
+	limits.Restart = "unless-stopped"
+	options, err = updateOptions(hc, limits)
+	if err != nil || options.Resources.CPUQuota != 50000 || options.Resources.NanoCPUs != 0 ||
+		options.RestartPolicy.Name != container.RestartPolicyUnlessStopped {
+		t.Errorf("updateOptions() = %+v, %+v, %v", options.Resources, options.RestartPolicy, err)
+	}
+	quota := &container.HostConfig{Resources: container.Resources{CPUQuota: 50000}}
+	limits = formatLimits(quota)
+	limits.CPUs = ""
+	if options, err := updateOptions(quota, limits); err != nil || options.Resources.CPUQuota != -1 {
+		t.Errorf("updateOptions() removing the quota = %+v, %v", options.Resources, err)
+	}
+
+	// A --cpus limit stays one
+	nano := &container.HostConfig{Resources: container.Resources{NanoCPUs: 1e9}}
+	limits = formatLimits(nano)
+	limits.CPUs = "2"
+	if options, err := updateOptions(nano, limits); err != nil || options.Resources.NanoCPUs != 2e9 || options.Resources.CPUQuota != 0 {
+		t.Errorf("updateOptions() on --cpus = %+v, %v", options.Resources, err)
+	}
+
+	for name, edit := range map[string]func(*types.ResourceLimits){
+		"nothing changed":        func(*types.ResourceLimits) {},
+		"bad shares":             func(l *types.ResourceLimits) { l.CPUShares = "lots" },
+		"negative CPUs":          func(l *types.ResourceLimits) { l.CPUs = "-1" },
+		"removed memory":         func(l *types.ResourceLimits) { l.Memory = "" },
+		"tiny memory":            func(l *types.ResourceLimits) { l.Memory = "1m" },
+		"unknown restart policy": func(l *types.ResourceLimits) { l.Restart = "sometimes" },
+		"retries with always":    func(l *types.ResourceLimits) { l.Restart = "always:3" },
+	} {
+		limits := formatLimits(hc)
+		edit(&limits)
+		if _, err := updateOptions(hc, limits); err == nil {
+			t.Errorf("updateOptions() with %s should fail", name)
+		}
+	}
+	limits = formatLimits(nano)
+	limits.CPUs = ""
+	if _, err := updateOptions(nano, limits); err == nil {
+		t.Error("updateOptions() removing a --cpus limit should fail")
+	}
+}
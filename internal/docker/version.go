@@ -0,0 +1,53 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/moby/moby/client"
+	"github.com/moby/moby/client/pkg/versions"
+)
+
+// API versions tinyd's Docker client can speak
+const (
+	MinAPIVersion = client.MinAPIVersion
+	MaxAPIVersion = client.MaxAPIVersion
+)
+
+// DaemonVersion is what the daemon reports about itself
+type DaemonVersion struct {
+	Version       string // Engine version, e.g. "28.0.1"
+	APIVersion    string // Highest API version the daemon supports
+	MinAPIVersion string // Lowest API version the daemon supports
+	Platform      string // Product, e.g. "Docker Engine - Community"
+}
+
+// DaemonVersion asks the daemon for its engine and API versions. It is the
+// first request made, so its error is also how an unreachable daemon shows.
+func (c *Client) DaemonVersion(ctx context.Context) (DaemonVersion, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutQuick)
+		defer cancel()
+	}
+
+	result, err := c.cli.ServerVersion(ctx, client.ServerVersionOptions{})
+	if err != nil {
+		return DaemonVersion{}, fmt.Errorf("failed to get daemon version: %w", err)
+	}
+	return DaemonVersion{
+		Version:       result.Version,
+		APIVersion:    result.APIVersion,
+		MinAPIVersion: result.MinAPIVersion,
+		Platform:      result.Platform.Name,
+	}, nil
+}
+
+// APISupported reports whether the client and a daemon supporting API
+// versions min to max have a version in common
+func APISupported(min, max string) bool {
+	if max == "" {
+		return false
+	}
+	return !versions.LessThan(max, MinAPIVersion) && (min == "" || !versions.GreaterThan(min, MaxAPIVersion))
+}
@@ -0,0 +1,21 @@
+package docker
+
+import "testing"
+
+func TestAPISupported(t *testing.T) {
+	tests := []struct {
+		min, max string
+		want     bool
+	}{
+		{"1.24", "1.51", true},
+		{"1.12", "1.43", false}, // Daemon older than the client supports
+		{"1.54", "1.60", false}, // Daemon newer than the client supports
+		{"", "1.47", true},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		if got := APISupported(tt.min, tt.max); got != tt.want {
+			t.Errorf("APISupported(%q, %q) = %t, want %t", tt.min, tt.max, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/go-units"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/mount"
+	"github.com/moby/moby/client"
+	"tinyd/internal/types"
+)
+
+// VolumeHelperImage is the image of the helper container that copies volumes
+const VolumeHelperImage = "alpine:latest"
+
+// estimateScript prints the size of /from in KiB, its number of entries and
+// the number of entries already in /to (0 when /to isn't mounted)
+const estimateScript = `du -sk /from | cut -f1; find /from -mindepth 1 | wc -l; if [ -d /to ]; then find /to -mindepth 1 | wc -l; else echo 0; fi`
+
+// EstimateVolumeCopy works out what copying source into target would do
+// without changing anything: the size of the data to copy and whether the
+// target exists and already holds files. A missing target is not created.
+func (c *Client) EstimateVolumeCopy(ctx context.Context, source, target string) (types.VolumeCopyPlan, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.WithCustomTimeout(TimeoutLong)
+		defer cancel()
+	}
+
+	plan := types.VolumeCopyPlan{Source: source, Target: target}
+	if source == target {
+		return plan, fmt.Errorf("source and target are the same volume")
+	}
+
+	exists, err := c.volumeExists(ctx, source)
+	if err != nil {
+		return plan, err
+	}
+	if !exists {
+		return plan, fmt.Errorf("volume %s not found", source)
+	}
+	if plan.TargetExists, err = c.volumeExists(ctx, target); err != nil {
+		return plan, err
+	}
+
+	mountTarget := ""
+	if plan.TargetExists {
+		mountTarget = target
+	}
+	helperID, err := c.startVolumeHelper(ctx, source, mountTarget)
+	if err != nil {
+		return plan, err
+	}
+	defer c.removeVolumeHelper(helperID)
+
+	out, code, err := c.ExecOutput(ctx, helperID, []string{"sh", "-c", estimateScript})
+	if err != nil {
+		return plan, err
+	}
+	if code != 0 {
+		return plan, fmt.Errorf("failed to measure %s (exit code %d): %s", source, code, strings.TrimSpace(out))
+	}
+	if err := parseCopyEstimate(out, &plan); err != nil {
+		return plan, err
+	}
+	return plan, nil
+}
+
+// parseCopyEstimate reads the output of estimateScript into plan
+func parseCopyEstimate(out string, plan *types.VolumeCopyPlan) error {
+	fields := strings.Fields(out)
+	if len(fields) != 3 {
+		return fmt.Errorf("unexpected estimate output: %q", out)
+	}
+	var values [3]int64
+	for i, field := range fields {
+		value, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return fmt.Errorf("unexpected estimate output: %q", out)
+		}
+		values[i] = value
+	}
+	plan.Size = values[0] * 1024
+	plan.Files = int(values[1])
+	plan.TargetFiles = int(values[2])
+	return nil
+}
+
+// CopyVolume copies the contents of source into target, creating target
+// when it doesn't exist. Files already in target are kept unless source has
+// a file of the same path. progress is called about once a second with the
+// amount copied so far against total, the size from EstimateVolumeCopy.
+func (c *Client) CopyVolume(ctx context.Context, source, target string, total int64, progress func(string)) error {
+	if ctx == nil {
+		// Copies take as long as the data needs, so there is no deadline
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(context.Background())
+		defer cancel()
+	}
+
+	exists, err := c.volumeExists(ctx, target)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if _, err := c.cli.VolumeCreate(ctx, client.VolumeCreateOptions{Name: target}); err != nil {
+			return fmt.Errorf("failed to create volume: %w", err)
+		}
+	}
+
+	helperID, err := c.startVolumeHelper(ctx, source, target)
+	if err != nil {
+		return err
+	}
+	defer c.removeVolumeHelper(helperID)
+
+	// Measure what target already holds so progress counts only new data
+	baseline, err := c.volumeHelperUsage(ctx, helperID)
+	if err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if used, err := c.volumeHelperUsage(ctx, helperID); err == nil {
+					progress(copyProgress(used-baseline, total))
+				}
+			}
+		}
+	}()
+
+	out, code, err := c.ExecOutput(ctx, helperID, []string{"cp", "-a", "/from/.", "/to/"})
+	if err != nil {
+		return fmt.Errorf("failed to copy volume: %w", err)
+	}
+	if code != 0 {
+		return fmt.Errorf("copy failed (exit code %d): %s", code, strings.TrimSpace(out))
+	}
+	return nil
+}
+
+// copyProgress describes how much of a copy is done, e.g. "12MB of 48MB (25%)"
+func copyProgress(copied, total int64) string {
+	copied = max(copied, 0)
+	if total <= 0 {
+		return units.HumanSize(float64(copied)) + " copied"
+	}
+	percent := min(copied*100/total, 100)
+	return fmt.Sprintf("%s of %s (%d%%)", units.HumanSize(float64(copied)), units.HumanSize(float64(total)), percent)
+}
+
+// volumeExists reports whether a volume of that name exists
+func (c *Client) volumeExists(ctx context.Context, name string) (bool, error) {
+	if _, err := c.inspectVolume(ctx, name); err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to inspect volume: %w", err)
+	}
+	return true, nil
+}
+
+// startVolumeHelper starts an idle helper container with source mounted
+// read-only at /from and, unless empty, target at /to
+func (c *Client) startVolumeHelper(ctx context.Context, source, target string) (string, error) {
+	exists, err := c.ImageExists(ctx, VolumeHelperImage)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		if err := c.PullImage(ctx, VolumeHelperImage); err != nil {
+			return "", err
+		}
+	}
+
+	mounts := []mount.Mount{{Type: mount.TypeVolume, Source: source, Target: "/from", ReadOnly: true}}
+	if target != "" {
+		mounts = append(mounts, mount.Mount{Type: mount.TypeVolume, Source: target, Target: "/to"})
+	}
+
+	helper, err := c.cli.ContainerCreate(ctx, client.ContainerCreateOptions{
+		Config: &container.Config{
+			Image: VolumeHelperImage,
+			Cmd:   []string{"tail", "-f", "/dev/null"},
+		},
+		HostConfig: &container.HostConfig{Mounts: mounts},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create volume helper: %w", err)
+	}
+
+	if _, err := c.cli.ContainerStart(ctx, helper.ID, client.ContainerStartOptions{}); err != nil {
+		c.removeVolumeHelper(helper.ID)
+		return "", fmt.Errorf("failed to start volume helper: %w", err)
+	}
+	return helper.ID, nil
+}
+
+// volumeHelperUsage returns the bytes used in the helper's /to
+func (c *Client) volumeHelperUsage(ctx context.Context, helperID string) (int64, error) {
+	out, code, err := c.ExecOutput(ctx, helperID, []string{"sh", "-c", "du -sk /to | cut -f1"})
+	if err != nil {
+		return 0, err
+	}
+	kib, convErr := strconv.ParseInt(strings.TrimSpace(out), 10, 64)
+	if code != 0 || convErr != nil {
+		return 0, fmt.Errorf("failed to measure target volume: %s", strings.TrimSpace(out))
+	}
+	return kib * 1024, nil
+}
+
+// removeVolumeHelper removes a helper container, even when ctx has expired
+func (c *Client) removeVolumeHelper(helperID string) {
+	ctx, cancel := c.WithCustomTimeout(TimeoutQuick)
+	defer cancel()
+	_, _ = c.cli.ContainerRemove(ctx, helperID, client.ContainerRemoveOptions{Force: true})
+}
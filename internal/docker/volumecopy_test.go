@@ -0,0 +1,38 @@
+package docker
+
+import (
+	"testing"
+
+	"tinyd/internal/types"
+)
+
+func TestParseCopyEstimate(t *testing.T) {
+	var plan types.VolumeCopyPlan
+	if err := parseCopyEstimate("2048\n37\n0\n", &plan); err != nil {
+		t.Fatalf("parseCopyEstimate() error = %v", err)
+	}
+	if plan.Size != 2048*1024 || plan.Files != 37 || plan.TargetFiles != 0 {
+		t.Errorf("parseCopyEstimate() = %+v", plan)
+	}
+
+	if err := parseCopyEstimate("du: /from: Permission denied\n", &plan); err == nil {
+		t.Error("parseCopyEstimate() accepted malformed output")
+	}
+}
+
+func TestCopyProgress(t *testing.T) {
+	tests := []struct {
+		copied, total int64
+		want          string
+	}{
+		{12e6, 48e6, "12MB of 48MB (25%)"},
+		{-4096, 48e6, "0B of 48MB (0%)"},    // Target shrank below its baseline
+		{60e6, 48e6, "60MB of 48MB (100%)"}, // Block rounding can overshoot
+		{5e6, 0, "5MB copied"},
+	}
+	for _, tt := range tests {
+		if got := copyProgress(tt.copied, tt.total); got != tt.want {
+			t.Errorf("copyProgress(%d, %d) = %q, want %q", tt.copied, tt.total, got, tt.want)
+		}
+	}
+}
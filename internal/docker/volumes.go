@@ -23,7 +23,7 @@ func (c *Client) FetchVolumes(ctx context.Context) ([]types.Volume, error) {
 	}
 
 	// First, get all containers to determine which volumes are in use
-	containersResult, err := c.cli.ContainerList(ctx, client.ContainerListOptions{All: true})
+	containersResult, err := c.listContainers(ctx, client.ContainerListOptions{All: true})
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			return nil, fmt.Errorf("operation timed out after %s", TimeoutQuick)
@@ -52,7 +52,7 @@ func (c *Client) FetchVolumes(ctx context.Context) ([]types.Volume, error) {
 		}
 	}
 
-	result, err := c.cli.VolumeList(ctx, client.VolumeListOptions{})
+	result, err := c.listVolumes(ctx, client.VolumeListOptions{})
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			return nil, fmt.Errorf("operation timed out after %s", TimeoutQuick)
@@ -101,13 +101,13 @@ func (c *Client) InspectVolume(ctx context.Context, volumeName string) (string,
 		defer cancel()
 	}
 
-	inspectResult, err := c.cli.VolumeInspect(ctx, volumeName, client.VolumeInspectOptions{})
+	inspectResult, err := c.inspectVolume(ctx, volumeName)
 	if err != nil {
 		return "", fmt.Errorf("failed to inspect volume: %w", err)
 	}
 
 	// Get containers using this volume
-	containersResult, err := c.cli.ContainerList(ctx, client.ContainerListOptions{All: true})
+	containersResult, err := c.listContainers(ctx, client.ContainerListOptions{All: true})
 	if err != nil {
 		return "", fmt.Errorf("failed to list containers: %w", err)
 	}
@@ -136,11 +136,6 @@ func (c *Client) InspectVolume(ctx context.Context, volumeName string) (string,
 // Helper functions
 
 func parseVolume(vol volume.Volume, volumeToContainers map[string][]string) types.Volume {
-	name := vol.Name
-	if len(name) > 25 {
-		name = name[:22] + "..."
-	}
-
 	mountpoint := vol.Mountpoint
 	if len(mountpoint) > 30 {
 		mountpoint = "..." + mountpoint[len(mountpoint)-27:]
@@ -164,13 +159,14 @@ func parseVolume(vol volume.Volume, volumeToContainers map[string][]string) type
 	}
 
 	return types.Volume{
-		Name:       name,
+		Name:       vol.Name,
 		Driver:     vol.Driver,
 		Mountpoint: mountpoint,
 		Scope:      vol.Scope,
 		Created:    created,
 		InUse:      inUse,
 		Containers: containers,
+		Labels:     vol.Labels,
 	}
 }
 
@@ -0,0 +1,97 @@
+package docker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"tinyd/internal/types"
+)
+
+// packageCaches are directories package managers fill during a build, which
+// are only needed to install and can be cleaned in the same step
+var packageCaches = []string{
+	"/var/lib/apt/lists",
+	"/var/cache/apt",
+	"/var/cache/apk",
+	"/var/cache/yum",
+	"/var/cache/dnf",
+	"/root/.cache/pip",
+	"/root/.npm",
+	"/usr/local/share/.cache/yarn",
+	"/root/.cache/go-build",
+}
+
+// AnalyzeWaste looks for space the layers spend on files the final image
+// doesn't have: files overwritten by a later layer, files deleted by a later
+// layer (their bytes stay in the earlier one) and package-manager caches
+// left behind. It is a subset of what dive reports, from the same listing
+// the layer browser uses.
+func AnalyzeWaste(layers []types.ImageLayer) types.ImageWaste {
+	live := make(map[string]int64) // Path to size, in the image so far
+	var report types.ImageWaste
+
+	for i, layer := range layers {
+		report.Total += layer.Size
+		for _, f := range layer.Files {
+			if f.Change == "D" {
+				// A whiteout deletes a file or a whole directory
+				item := types.WasteItem{Path: f.Path, Reason: fmt.Sprintf("deleted in layer %d", i+1)}
+				for p, size := range live {
+					if p == f.Path || strings.HasPrefix(p, f.Path+"/") {
+						item.Size += size
+						item.Files++
+						delete(live, p)
+					}
+				}
+				if item.Size > 0 {
+					report.Items = append(report.Items, item)
+				}
+				continue
+			}
+			if prev := live[f.Path]; prev > 0 {
+				report.Items = append(report.Items, types.WasteItem{
+					Path:   f.Path,
+					Size:   prev,
+					Files:  1,
+					Reason: fmt.Sprintf("overwritten in layer %d", i+1),
+				})
+			}
+			live[f.Path] = f.Size
+		}
+	}
+
+	caches := make(map[string]*types.WasteItem)
+	for p, size := range live {
+		for _, dir := range packageCaches {
+			if size > 0 && strings.HasPrefix(p, dir+"/") {
+				item, ok := caches[dir]
+				if !ok {
+					item = &types.WasteItem{Path: dir, Reason: "package cache"}
+					caches[dir] = item
+				}
+				item.Size += size
+				item.Files++
+				break
+			}
+		}
+	}
+	for _, item := range caches {
+		report.Items = append(report.Items, *item)
+	}
+
+	sort.Slice(report.Items, func(i, j int) bool {
+		if report.Items[i].Size != report.Items[j].Size {
+			return report.Items[i].Size > report.Items[j].Size
+		}
+		return report.Items[i].Path < report.Items[j].Path
+	})
+	for _, item := range report.Items {
+		report.Wasted += item.Size
+	}
+	report.Efficiency = 1
+	if report.Total > 0 {
+		report.Efficiency = float64(report.Total-report.Wasted) / float64(report.Total)
+	}
+	return report
+}
@@ -0,0 +1,54 @@
+package docker
+
+import (
+	"testing"
+
+	"tinyd/internal/types"
+)
+
+func TestAnalyzeWaste(t *testing.T) {
+	layers := []types.ImageLayer{
+		{Size: 1000, Files: []types.LayerFile{
+			{Path: "/etc/config", Size: 100, Change: "A"},
+			{Path: "/tmp/build/a.o", Size: 300, Change: "A"},
+			{Path: "/tmp/build/b.o", Size: 200, Change: "A"},
+			{Path: "/usr/bin/app", Size: 400, Change: "A"},
+		}},
+		{Size: 250, Files: []types.LayerFile{
+			{Path: "/var/lib/apt/lists/main", Size: 150, Change: "A"},
+			{Path: "/var/lib/apt/lists/lock", Size: 0, Change: "A"},
+			{Path: "/etc/config", Size: 100, Change: "M"},
+		}},
+		{Files: []types.LayerFile{
+			{Path: "/tmp/build", Change: "D"},
+		}},
+	}
+
+	report := AnalyzeWaste(layers)
+	if report.Total != 1250 || report.Wasted != 750 {
+		t.Fatalf("AnalyzeWaste() total %d wasted %d, want 1250 and 750", report.Total, report.Wasted)
+	}
+	if report.Efficiency != 0.4 {
+		t.Errorf("Efficiency = %v, want 0.4", report.Efficiency)
+	}
+
+	want := []types.WasteItem{
+		{Path: "/tmp/build", Size: 500, Files: 2, Reason: "deleted in layer 3"},
+		{Path: "/var/lib/apt/lists", Size: 150, Files: 1, Reason: "package cache"},
+		{Path: "/etc/config", Size: 100, Files: 1, Reason: "overwritten in layer 2"},
+	}
+	if len(report.Items) != len(want) {
+		t.Fatalf("Items = %+v, want %+v", report.Items, want)
+	}
+	for i := range want {
+		if report.Items[i] != want[i] {
+			t.Errorf("Items[%d] = %+v, want %+v", i, report.Items[i], want[i])
+		}
+	}
+}
+
+func TestAnalyzeWasteEmpty(t *testing.T) {
+	if report := AnalyzeWaste(nil); report.Efficiency != 1 || len(report.Items) != 0 {
+		t.Errorf("AnalyzeWaste(nil) = %+v", report)
+	}
+}
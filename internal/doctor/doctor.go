@@ -0,0 +1,319 @@
+// Package doctor checks the setup tinyd depends on: the daemon endpoint and
+// its permissions, the API version, registry credential helpers, the docker
+// CLI and the terminal. `tinyd doctor` prints the outcome of each check with
+// how to fix what failed.
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/muesli/termenv"
+
+	"tinyd/internal/components"
+	"tinyd/internal/docker"
+	"tinyd/internal/theme"
+)
+
+// timeout bounds the daemon checks, so an unreachable tcp:// or ssh://
+// endpoint doesn't hang the report
+const timeout = 5 * time.Second
+
+// Status is the outcome of a check
+type Status int
+
+const (
+	Pass Status = iota
+	Warn        // tinyd works, but some feature won't
+	Fail        // tinyd won't work until fixed
+)
+
+// Check is the outcome of one check
+type Check struct {
+	Name   string
+	Status Status
+	Detail string   // What was found
+	Fixes  []string // How to fix a warning or failure, most likely first
+}
+
+// Env is what the checks look at besides the daemon
+type Env struct {
+	Getenv   func(string) string
+	LookPath func(string) (string, error)
+	Terminal bool // Stdout is a terminal
+	Width    int  // Terminal size, 0 when unknown
+	Height   int
+	Colors   termenv.Profile
+}
+
+// Run checks the setup of the current process and writes the report to w.
+// It returns the exit code: 1 when a check failed, 0 otherwise.
+func Run(w io.Writer) int {
+	env := Env{
+		Getenv:   os.Getenv,
+		LookPath: exec.LookPath,
+		Terminal: term.IsTerminal(os.Stdout.Fd()),
+		Colors:   theme.Terminal(),
+	}
+	if env.Terminal {
+		env.Width, env.Height, _ = term.GetSize(os.Stdout.Fd())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if Report(w, Checks(ctx, env)) {
+		return 1
+	}
+	return 0
+}
+
+// Checks runs every check. The daemon checks are skipped when no client
+// can be made, and the API check when the daemon is unreachable.
+func Checks(ctx context.Context, env Env) []Check {
+	var checks []Check
+
+	cli, err := docker.NewClient()
+	if err != nil {
+		checks = append(checks, Check{
+			Name:   "Endpoint",
+			Status: Fail,
+			Detail: err.Error(),
+			Fixes:  []string{"Set DOCKER_HOST to an endpoint such as unix:///var/run/docker.sock or tcp://host:2376, or unset it"},
+		})
+	} else {
+		defer cli.Close()
+		host := cli.Underlying().DaemonHost()
+		checks = append(checks, checkEndpoint(host, docker.Endpoint(), env.Getenv))
+
+		version, err := cli.DaemonVersion(ctx)
+		checks = append(checks, checkDaemon(host, version, err))
+		if err == nil {
+			checks = append(checks, checkAPI(version))
+		}
+	}
+
+	helpers, err := docker.CredentialHelpers()
+	checks = append(checks,
+		checkCredentials(helpers, err, env.LookPath),
+		checkCLI(env.LookPath),
+		checkTerminal(env),
+		checkColors(env),
+	)
+	return checks
+}
+
+// checkEndpoint looks at the endpoint tinyd connects to, host, and the
+// one the docker CLI uses, cliHost ("" for the default socket)
+func checkEndpoint(host, cliHost string, getenv func(string) string) Check {
+	check := Check{Name: "Endpoint", Detail: host}
+
+	u, err := url.Parse(host)
+	if err != nil {
+		check.Status = Fail
+		check.Detail = fmt.Sprintf("invalid endpoint %s: %v", host, err)
+		return check
+	}
+
+	switch u.Scheme {
+	case "ssh":
+		check.Status = Fail
+		check.Detail = host + " is an ssh endpoint, which tinyd can't connect to directly"
+		check.Fixes = []string{fmt.Sprintf("Forward the remote socket and point tinyd at it: ssh -nNT -L /tmp/docker.sock:/var/run/docker.sock %s, then export DOCKER_HOST=unix:///tmp/docker.sock", strings.TrimPrefix(host, "ssh://"))}
+		return check
+	case "unix":
+		if _, err := os.Stat(u.Path); err != nil {
+			check.Status = Fail
+			check.Detail = "no socket at " + u.Path
+			check.Fixes = []string{
+				"Start the daemon, e.g. sudo systemctl start docker, or open Docker Desktop",
+				"If the daemon listens elsewhere, set DOCKER_HOST, e.g. unix://$XDG_RUNTIME_DIR/docker.sock for rootless Docker",
+			}
+			return check
+		}
+	case "tcp", "http":
+		if getenv("DOCKER_TLS_VERIFY") == "" && getenv("DOCKER_CERT_PATH") == "" {
+			check.Status = Warn
+			check.Detail = host + " is not protected by TLS: anyone who can reach it controls the daemon"
+			check.Fixes = []string{"Serve the API with --tlsverify and set DOCKER_TLS_VERIFY=1 and DOCKER_CERT_PATH, or forward the socket over ssh"}
+		}
+	}
+
+	// tinyd reads DOCKER_HOST but not docker contexts
+	if cliHost != "" && cliHost != host && check.Status == Pass {
+		check.Status = Warn
+		check.Detail = fmt.Sprintf("%s, but the active docker context uses %s", host, cliHost)
+		check.Fixes = []string{"Point tinyd at the context's daemon: export DOCKER_HOST=" + cliHost}
+	}
+	return check
+}
+
+// checkDaemon reports whether the daemon answered the version request
+func checkDaemon(host string, version docker.DaemonVersion, err error) Check {
+	check := Check{Name: "Daemon"}
+	if err == nil {
+		check.Detail = "Docker " + version.Version
+		if version.Platform != "" {
+			check.Detail += " (" + version.Platform + ")"
+		}
+		return check
+	}
+
+	check.Status = Fail
+	check.Detail = err.Error()
+	if diagnosis, ok := docker.DiagnoseConnection(err, host); ok {
+		check.Detail = "permission denied on " + host
+		check.Fixes = diagnosis.Fixes
+		return check
+	}
+	check.Fixes = []string{"Check that the daemon is running: sudo systemctl status docker, or open Docker Desktop"}
+	return check
+}
+
+// checkAPI reports whether tinyd's client and the daemon share an API
+// version
+func checkAPI(version docker.DaemonVersion) Check {
+	check := Check{
+		Name:   "API version",
+		Detail: fmt.Sprintf("daemon %s, tinyd %s to %s", apiRange(version), docker.MinAPIVersion, docker.MaxAPIVersion),
+	}
+	if !docker.APISupported(version.MinAPIVersion, version.APIVersion) {
+		check.Status = Fail
+		check.Fixes = []string{fmt.Sprintf("tinyd needs Docker 25.0 (API %s) or later: upgrade the daemon", docker.MinAPIVersion)}
+	}
+	return check
+}
+
+// apiRange shows the API versions a daemon supports
+func apiRange(version docker.DaemonVersion) string {
+	if version.MinAPIVersion == "" || version.MinAPIVersion == version.APIVersion {
+		return version.APIVersion
+	}
+	return version.MinAPIVersion + " to " + version.APIVersion
+}
+
+// checkCredentials reports whether the credential helpers config.json
+// names are installed. Without them pulls of private images fail.
+func checkCredentials(helpers []string, err error, lookPath func(string) (string, error)) Check {
+	check := Check{Name: "Credentials"}
+	if err != nil {
+		check.Status = Warn
+		check.Detail = err.Error()
+		check.Fixes = []string{"Fix or remove the docker config.json; registry logins are read from it"}
+		return check
+	}
+	if len(helpers) == 0 {
+		check.Detail = "no credential helpers configured"
+		return check
+	}
+
+	var missing []string
+	for _, helper := range helpers {
+		if _, err := lookPath("docker-credential-" + helper); err != nil {
+			missing = append(missing, "docker-credential-"+helper)
+		}
+	}
+	if len(missing) == 0 {
+		check.Detail = strings.Join(helpers, ", ")
+		return check
+	}
+	check.Status = Warn
+	check.Detail = strings.Join(missing, ", ") + " not found in PATH: pulls from registries needing a login will fail"
+	check.Fixes = []string{"Install the helper, or remove it from credsStore/credHelpers in the docker config.json and run docker login again"}
+	return check
+}
+
+// checkCLI reports whether the docker CLI is installed. tinyd talks to the
+// API itself but runs the CLI for consoles opened in another terminal.
+func checkCLI(lookPath func(string) (string, error)) Check {
+	path, err := lookPath("docker")
+	if err != nil {
+		return Check{
+			Name:   "Docker CLI",
+			Status: Warn,
+			Detail: "docker not found in PATH: consoles can't open in a separate terminal (TINYD_TERMINAL)",
+			Fixes:  []string{"Install the docker CLI, e.g. the docker-ce-cli package, or add it to PATH"},
+		}
+	}
+	return Check{Name: "Docker CLI", Detail: path}
+}
+
+// checkTerminal reports whether the UI can be drawn
+func checkTerminal(env Env) Check {
+	check := Check{Name: "Terminal"}
+	switch {
+	case !env.Terminal:
+		check.Status = Warn
+		check.Detail = "stdout is not a terminal"
+		check.Fixes = []string{"Run tinyd from an interactive terminal, not through a pipe or redirect"}
+	case env.Getenv("TERM") == "dumb":
+		check.Status = Fail
+		check.Detail = "TERM=dumb can't move the cursor or draw the full-screen UI"
+		check.Fixes = []string{"Set TERM to your terminal's type, e.g. export TERM=xterm-256color"}
+	case env.Width > 0 && (env.Width < components.MinWidth || env.Height < components.MinHeight):
+		check.Status = Warn
+		check.Detail = fmt.Sprintf("%dx%d, smaller than the %dx%d the UI needs", env.Width, env.Height, components.MinWidth, components.MinHeight)
+		check.Fixes = []string{"Enlarge the window or reduce the font size"}
+	default:
+		check.Detail = env.Getenv("TERM")
+		if env.Width > 0 {
+			check.Detail = strings.TrimSpace(fmt.Sprintf("%s %dx%d", check.Detail, env.Width, env.Height))
+		}
+	}
+	return check
+}
+
+// checkColors reports the color depth the UI is drawn with
+func checkColors(env Env) Check {
+	check := Check{Name: "Colors", Detail: env.Colors.Name()}
+	switch {
+	case env.Getenv(theme.NoColorEnvVar) != "":
+		check.Detail = "off (NO_COLOR is set)"
+	case env.Colors == termenv.Ascii:
+		check.Status = Warn
+		check.Detail = "none: states shown by color get text markers instead"
+		check.Fixes = []string{"Set COLORTERM=truecolor if the terminal supports it, or force a depth with TINYD_COLORS=256"}
+	case env.Colors == termenv.ANSI:
+		check.Status = Warn
+		check.Detail = "16 colors: the palette is approximated"
+		check.Fixes = []string{"Set COLORTERM=truecolor if the terminal supports it, or force a depth with TINYD_COLORS=256"}
+	}
+	return check
+}
+
+// Report writes one line per check with the fixes of those that didn't
+// pass beneath it, then a summary. It reports whether any check failed.
+func Report(w io.Writer, checks []Check) (failed bool) {
+	width := 0
+	for _, check := range checks {
+		width = max(width, len(check.Name))
+	}
+
+	var counts [3]int
+	for _, check := range checks {
+		counts[check.Status]++
+		fmt.Fprintf(w, "%s %-*s  %s\n", marker(check.Status), width, check.Name, check.Detail)
+		for _, fix := range check.Fixes {
+			fmt.Fprintf(w, "  %*s  → %s\n", width, "", fix)
+		}
+	}
+	fmt.Fprintf(w, "\n%d passed, %d warned, %d failed\n", counts[Pass], counts[Warn], counts[Fail])
+	return counts[Fail] > 0
+}
+
+// marker shows a status in a way that reads without colors
+func marker(status Status) string {
+	switch status {
+	case Warn:
+		return "[warn]"
+	case Fail:
+		return "[FAIL]"
+	}
+	return "[ ok ]"
+}
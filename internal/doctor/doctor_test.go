@@ -0,0 +1,164 @@
+package doctor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/muesli/termenv"
+
+	"tinyd/internal/docker"
+)
+
+func env(vars map[string]string) func(string) string {
+	return func(key string) string { return vars[key] }
+}
+
+func TestCheckEndpoint(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	if err := os.WriteFile(socket, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	none := env(nil)
+
+	tests := []struct {
+		name    string
+		host    string
+		cliHost string
+		getenv  func(string) string
+		want    Status
+	}{
+		{"socket", "unix://" + socket, "", none, Pass},
+		{"missing socket", "unix:///nonexistent/docker.sock", "", none, Fail},
+		{"plain tcp", "tcp://10.0.0.5:2375", "", none, Warn},
+		{"tcp with TLS", "tcp://10.0.0.5:2376", "", env(map[string]string{"DOCKER_TLS_VERIFY": "1"}), Pass},
+		{"ssh", "ssh://deploy@build-01", "", none, Fail},
+		{"other context", "unix://" + socket, "tcp://10.0.0.5:2376", none, Warn},
+		{"same context", "unix://" + socket, "unix://" + socket, none, Pass},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := checkEndpoint(tt.host, tt.cliHost, tt.getenv)
+			if check.Status != tt.want || (check.Status != Pass && len(check.Fixes) == 0) {
+				t.Errorf("checkEndpoint(%q, %q) = %+v, want status %d with fixes", tt.host, tt.cliHost, check, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckDaemon(t *testing.T) {
+	check := checkDaemon("unix:///var/run/docker.sock", docker.DaemonVersion{Version: "28.0.1", Platform: "Docker Engine - Community"}, nil)
+	if check.Status != Pass || check.Detail != "Docker 28.0.1 (Docker Engine - Community)" {
+		t.Errorf("checkDaemon() = %+v", check)
+	}
+
+	check = checkDaemon("tcp://10.0.0.5:2375", docker.DaemonVersion{}, errors.New("connection refused"))
+	if check.Status != Fail || len(check.Fixes) == 0 {
+		t.Errorf("checkDaemon() unreachable = %+v", check)
+	}
+
+	check = checkDaemon("tcp://10.0.0.5:2375", docker.DaemonVersion{}, errors.New("dial: permission denied"))
+	if check.Status != Fail || !strings.Contains(check.Detail, "permission denied") || len(check.Fixes) == 0 {
+		t.Errorf("checkDaemon() refused = %+v", check)
+	}
+}
+
+func TestCheckAPI(t *testing.T) {
+	if check := checkAPI(docker.DaemonVersion{APIVersion: "1.48", MinAPIVersion: "1.24"}); check.Status != Pass ||
+		check.Detail != "daemon 1.24 to 1.48, tinyd "+docker.MinAPIVersion+" to "+docker.MaxAPIVersion {
+		t.Errorf("checkAPI() = %+v", check)
+	}
+	if check := checkAPI(docker.DaemonVersion{APIVersion: "1.41", MinAPIVersion: "1.12"}); check.Status != Fail || len(check.Fixes) == 0 {
+		t.Errorf("checkAPI() on an old daemon = %+v", check)
+	}
+}
+
+func TestCheckCredentials(t *testing.T) {
+	lookPath := func(name string) (string, error) {
+		if name == "docker-credential-desktop" {
+			return "/usr/local/bin/" + name, nil
+		}
+		return "", errors.New("not found")
+	}
+
+	if check := checkCredentials(nil, nil, lookPath); check.Status != Pass {
+		t.Errorf("checkCredentials() without helpers = %+v", check)
+	}
+	if check := checkCredentials([]string{"desktop"}, nil, lookPath); check.Status != Pass || check.Detail != "desktop" {
+		t.Errorf("checkCredentials() = %+v", check)
+	}
+	check := checkCredentials([]string{"desktop", "ecr-login"}, nil, lookPath)
+	if check.Status != Warn || !strings.HasPrefix(check.Detail, "docker-credential-ecr-login not found") {
+		t.Errorf("checkCredentials() with a missing helper = %+v", check)
+	}
+	if check := checkCredentials(nil, errors.New("invalid config.json"), lookPath); check.Status != Warn {
+		t.Errorf("checkCredentials() with a broken config = %+v", check)
+	}
+}
+
+func TestCheckTerminal(t *testing.T) {
+	tests := []struct {
+		name string
+		env  Env
+		want Status
+	}{
+		{"ok", Env{Terminal: true, Width: 120, Height: 40, Getenv: env(map[string]string{"TERM": "xterm-256color"})}, Pass},
+		{"piped", Env{Getenv: env(nil)}, Warn},
+		{"dumb", Env{Terminal: true, Getenv: env(map[string]string{"TERM": "dumb"})}, Fail},
+		{"small", Env{Terminal: true, Width: 70, Height: 40, Getenv: env(nil)}, Warn},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if check := checkTerminal(tt.env); check.Status != tt.want {
+				t.Errorf("checkTerminal() = %+v, want status %d", check, tt.want)
+			}
+		})
+	}
+
+	if check := checkColors(Env{Colors: termenv.TrueColor, Getenv: env(nil)}); check.Status != Pass {
+		t.Errorf("checkColors() truecolor = %+v", check)
+	}
+	if check := checkColors(Env{Colors: termenv.ANSI, Getenv: env(nil)}); check.Status != Warn {
+		t.Errorf("checkColors() 16 colors = %+v", check)
+	}
+	if check := checkColors(Env{Colors: termenv.Ascii, Getenv: env(map[string]string{"NO_COLOR": "1"})}); check.Status != Pass {
+		t.Errorf("checkColors() NO_COLOR = %+v", check)
+	}
+}
+
+func TestReport(t *testing.T) {
+	var b strings.Builder
+	failed := Report(&b, []Check{
+		{Name: "Daemon", Detail: "Docker 28.0.1"},
+		{Name: "Docker CLI", Status: Warn, Detail: "docker not found in PATH", Fixes: []string{"Install the docker CLI"}},
+	})
+	want := "[ ok ] Daemon      Docker 28.0.1\n" +
+		"[warn] Docker CLI  docker not found in PATH\n" +
+		"              → Install the docker CLI\n" +
+		"\n1 passed, 1 warned, 0 failed\n"
+	if failed || b.String() != want {
+		t.Errorf("Report() = %t\n%s\nwant\n%s", failed, b.String(), want)
+	}
+
+	if !Report(&b, []Check{{Name: "Daemon", Status: Fail}}) {
+		t.Error("Report() with a failed check should report it")
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DOCKER_HOST", "unix://"+filepath.Join(dir, "docker.sock"))
+	t.Setenv("DOCKER_CONFIG", dir)
+
+	var b strings.Builder
+	if code := Run(&b); code != 1 {
+		t.Errorf("Run() = %d without a daemon, want 1", code)
+	}
+	for _, name := range []string{"Endpoint", "Daemon", "Terminal", "failed"} {
+		if !strings.Contains(b.String(), name) {
+			t.Errorf("Run() report lacks %q:\n%s", name, b.String())
+		}
+	}
+}
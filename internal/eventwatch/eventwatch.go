@@ -0,0 +1,40 @@
+// Package eventwatch keeps named filters of the events view, e.g. "shop"
+// for the events of one compose project or "pulls" for image pulls, so a
+// recurring question to the daemon's event stream is one key away. Watches
+// are saved to a file of the user config directory and survive restarts.
+package eventwatch
+
+import (
+	"strings"
+
+	"tinyd/internal/store"
+)
+
+// Watch is a named events filter, in `docker events --filter` terms such as
+// "type=image event=pull"
+type Watch struct {
+	Name   string
+	Filter string
+}
+
+// Store holds the saved watches, sorted by name
+type Store struct {
+	*store.Named[Watch]
+}
+
+// Open returns the watches saved in the user config directory. A missing or
+// unreadable file starts empty.
+func Open() *Store {
+	return openFile(store.Path("event-watches.json"))
+}
+
+// openFile returns the watches saved in path
+func openFile(path string) *Store {
+	return &Store{store.OpenNamed(path, "watch", func(w Watch) string { return w.Name })}
+}
+
+// Save adds a watch, replacing the one of the same name (ignoring case)
+func (s *Store) Save(w Watch) error {
+	w.Name = strings.TrimSpace(w.Name)
+	return s.Named.Save(w)
+}
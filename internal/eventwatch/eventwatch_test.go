@@ -0,0 +1,22 @@
+package eventwatch
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tinyd", "event-watches.json")
+
+	s := openFile(path)
+	if err := s.Save(Watch{Name: " pulls ", Filter: "type=image event=pull"}); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	// The name is saved trimmed, so it is found as typed in the picker
+	if list := openFile(path).List(); len(list) != 1 || list[0] != (Watch{Name: "pulls", Filter: "type=image event=pull"}) {
+		t.Errorf("List() after reopening = %+v", list)
+	}
+	if err := s.Delete("Pulls"); err != nil {
+		t.Errorf("Delete() error: %v", err)
+	}
+}
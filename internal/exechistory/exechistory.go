@@ -0,0 +1,114 @@
+// Package exechistory keeps the commands run in containers, per container
+// name, so that a check like "cat /etc/resolv.conf" is typed once and
+// recalled with the arrow keys afterwards, as in a shell, along with the
+// options of the last interactive exec. The history is saved per daemon to
+// a file of the user config directory.
+package exechistory
+
+import (
+	"strings"
+
+	"tinyd/internal/store"
+)
+
+// MaxCommands is how many commands are kept per container
+const MaxCommands = 100
+
+// Options are the settings of an interactive exec, as typed
+type Options struct {
+	Command string
+	User    string `json:",omitempty"`
+	Workdir string `json:",omitempty"`
+	Env     string `json:",omitempty"` // KEY=value pairs separated by spaces
+}
+
+// Store holds the commands run in the containers of one daemon, oldest
+// first, and the options of their last interactive exec, keyed by container
+// name so that they outlive recreating it
+type Store struct {
+	path string // "" when there is no config directory
+	data storeData
+}
+
+// storeData is the content of the history file
+type storeData struct {
+	Commands    map[string][]string
+	Interactive map[string]Options
+}
+
+// Open returns the history of the daemon at host. A missing or unreadable
+// file starts empty.
+func Open(host string) *Store {
+	return openFile(store.DaemonPath("exec-history", host))
+}
+
+// openFile returns the history saved in path
+func openFile(path string) *Store {
+	s := &Store{path: path}
+	if store.Load(path, &s.data) != nil {
+		s.data = storeData{}
+	}
+	if s.data.Commands == nil {
+		s.data.Commands = make(map[string][]string)
+	}
+	if s.data.Interactive == nil {
+		s.data.Interactive = make(map[string]Options)
+	}
+	return s
+}
+
+// Commands returns the commands run in the container, oldest first
+func (s *Store) Commands(container string) []string {
+	return append([]string(nil), s.data.Commands[container]...)
+}
+
+// Last returns the command last run in the container, or ""
+func (s *Store) Last(container string) string {
+	commands := s.data.Commands[container]
+	if len(commands) == 0 {
+		return ""
+	}
+	return commands[len(commands)-1]
+}
+
+// Add records a command run in the container. Running a command again
+// moves it to the end rather than repeating it, and the oldest commands
+// past MaxCommands are dropped.
+func (s *Store) Add(container, command string) error {
+	command = strings.TrimSpace(command)
+	if command == "" {
+		return nil
+	}
+	commands := s.data.Commands[container][:0:0]
+	for _, c := range s.data.Commands[container] {
+		if c != command {
+			commands = append(commands, c)
+		}
+	}
+	commands = append(commands, command)
+	if len(commands) > MaxCommands {
+		commands = commands[len(commands)-MaxCommands:]
+	}
+	s.data.Commands[container] = commands
+	return s.write()
+}
+
+// LastOptions returns the options of the last interactive exec in the
+// container
+func (s *Store) LastOptions(container string) (Options, bool) {
+	o, ok := s.data.Interactive[container]
+	return o, ok
+}
+
+// SetLastOptions records the options of an interactive exec in the
+// container
+func (s *Store) SetLastOptions(container string, o Options) error {
+	s.data.Interactive[container] = o
+	return s.write()
+}
+
+// write saves the history to the file, which only the user can read as
+// commands and exec environments may hold secrets
+func (s *Store) write() error {
+	return store.Save(s.path, s.data)
+}
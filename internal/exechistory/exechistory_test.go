@@ -0,0 +1,73 @@
+package exechistory
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tinyd", "exec-history.json")
+
+	s := openFile(path)
+	if s.Last("web") != "" || len(s.Commands("web")) != 0 {
+		t.Fatal("a new store should be empty")
+	}
+
+	for _, command := range []string{"ls /", " env ", "", "cat /etc/hosts", "ls /"} {
+		if err := s.Add("web", command); err != nil {
+			t.Fatalf("Add(%q) error: %v", command, err)
+		}
+	}
+	if err := s.Add("db", "psql -c 'select 1'"); err != nil {
+		t.Fatalf("Add() error: %v", err)
+	}
+
+	// A later run reads what was saved; a repeated command moves to the end
+	reopened := openFile(path)
+	if want := []string{"env", "cat /etc/hosts", "ls /"}; !reflect.DeepEqual(reopened.Commands("web"), want) {
+		t.Errorf("Commands(web) = %q, want %q", reopened.Commands("web"), want)
+	}
+	if got := reopened.Last("db"); got != "psql -c 'select 1'" {
+		t.Errorf("Last(db) = %q", got)
+	}
+
+	// Interactive options are kept apart from the commands
+	if _, ok := reopened.LastOptions("web"); ok {
+		t.Error("LastOptions() before any interactive exec should be missing")
+	}
+	options := Options{Command: "psql", User: "postgres", Env: "PGDATABASE=shop"}
+	if err := s.SetLastOptions("db", options); err != nil {
+		t.Fatalf("SetLastOptions() error: %v", err)
+	}
+	if got, ok := openFile(path).LastOptions("db"); !ok || got != options {
+		t.Errorf("LastOptions(db) after reopening = %+v, %v", got, ok)
+	}
+	if info, err := os.Stat(path); err != nil {
+		t.Fatal(err)
+	} else if runtime.GOOS != "windows" && info.Mode().Perm() != 0o600 {
+		t.Errorf("history file mode = %v, want 0600 for the environment values", info.Mode().Perm())
+	}
+
+	// The oldest commands are dropped past MaxCommands
+	for i := range MaxCommands {
+		if err := s.Add("web", fmt.Sprintf("echo %d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commands := s.Commands("web")
+	if len(commands) != MaxCommands || commands[0] != "echo 0" {
+		t.Errorf("Commands(web) past MaxCommands: %d, first %q", len(commands), commands[0])
+	}
+
+	// A corrupt file starts empty
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if commands := openFile(path).Commands("web"); len(commands) != 0 {
+		t.Errorf("Commands() of a corrupt file = %q", commands)
+	}
+}
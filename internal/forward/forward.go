@@ -0,0 +1,197 @@
+// Package forward proxies TCP connections from a localhost port to a port
+// of a container, for services that publish no port on the host. Forwards
+// live in memory, as long as tinyd runs.
+package forward
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// dialTimeout bounds connecting to the container, both when a forward
+// starts and for each connection
+const dialTimeout = 3 * time.Second
+
+// Forward is a localhost port proxied to a container
+type Forward struct {
+	ID        int
+	Container string
+	Local     string // Listening address, e.g. "127.0.0.1:8080"
+	Target    string // Container address, e.g. "172.17.0.3:80"
+	Started   time.Time
+	Open      int // Connections open now
+	Total     int // Connections since the start
+}
+
+// String describes the forward, e.g. "127.0.0.1:8080 → web:80"
+func (f Forward) String() string {
+	_, port, _ := net.SplitHostPort(f.Target)
+	return fmt.Sprintf("%s → %s:%s", f.Local, f.Container, port)
+}
+
+// forward is a running forward with its listener and open connections
+type forward struct {
+	Forward
+	listener net.Listener
+	conns    map[net.Conn]bool
+	stopped  chan struct{} // Closed by Stop, under the manager's lock
+}
+
+// Manager runs the forwards. The zero value has none.
+type Manager struct {
+	mu       sync.Mutex
+	forwards []*forward
+	nextID   int
+}
+
+// ParseSpec reads a forward as `docker run -p` writes ports: "8080:80"
+// listens on localhost port 8080 for container port 80, and a bare "80"
+// listens on a free port picked by the system, reported as 0
+func ParseSpec(s string) (localPort, containerPort int, err error) {
+	local, remote, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if !ok {
+		local, remote = "0", local
+	}
+	if localPort, err = strconv.Atoi(local); err != nil || localPort < 0 || localPort > 65535 {
+		return 0, 0, fmt.Errorf("invalid local port %q: expected e.g. 8080:80 or 80", local)
+	}
+	if containerPort, err = strconv.Atoi(remote); err != nil || containerPort < 1 || containerPort > 65535 {
+		return 0, 0, fmt.Errorf("invalid container port %q: expected e.g. 8080:80 or 80", remote)
+	}
+	return localPort, containerPort, nil
+}
+
+// Start listens on localPort of 127.0.0.1 (a free port when 0) and proxies
+// each connection to target, the container's address. The container is
+// dialed first, so an unreachable address (e.g. a remote daemon's or Docker
+// Desktop's private network) or a port nothing listens on fails here.
+func (m *Manager) Start(container string, localPort int, target string) (Forward, error) {
+	probe, err := net.DialTimeout("tcp", target, dialTimeout)
+	if err != nil {
+		return Forward{}, fmt.Errorf("can't reach %s at %s from this host: %w", container, target, err)
+	}
+	probe.Close()
+
+	listener, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(localPort)))
+	if err != nil {
+		return Forward{}, fmt.Errorf("failed to listen on port %d: %w", localPort, err)
+	}
+
+	m.mu.Lock()
+	m.nextID++
+	f := &forward{
+		Forward: Forward{
+			ID:        m.nextID,
+			Container: container,
+			Local:     listener.Addr().String(),
+			Target:    target,
+			Started:   time.Now(),
+		},
+		listener: listener,
+		conns:    make(map[net.Conn]bool),
+		stopped:  make(chan struct{}),
+	}
+	m.forwards = append(m.forwards, f)
+	started := f.Forward
+	m.mu.Unlock()
+
+	go m.serve(f)
+	return started, nil
+}
+
+// serve accepts connections until the listener is closed
+func (m *Manager) serve(f *forward) {
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		go m.proxy(f, conn)
+	}
+}
+
+// proxy copies a connection to the container and back until either side
+// closes it
+func (m *Manager) proxy(f *forward, conn net.Conn) {
+	upstream, err := net.DialTimeout("tcp", f.Target, dialTimeout)
+	if err != nil {
+		conn.Close()
+		return
+	}
+
+	// Stop may have run while dialing, after closing the connections it knew
+	m.mu.Lock()
+	select {
+	case <-f.stopped:
+		m.mu.Unlock()
+		conn.Close()
+		upstream.Close()
+		return
+	default:
+	}
+	f.conns[conn] = true
+	f.conns[upstream] = true
+	f.Open++
+	f.Total++
+	m.mu.Unlock()
+
+	done := make(chan struct{}, 2)
+	pipe := func(dst, src net.Conn) {
+		_, _ = io.Copy(dst, src)
+		// Pass the end of one direction on, so half-closed protocols finish
+		if tcp, ok := dst.(*net.TCPConn); ok {
+			_ = tcp.CloseWrite()
+		}
+		done <- struct{}{}
+	}
+	go pipe(upstream, conn)
+	go pipe(conn, upstream)
+	<-done
+	<-done
+	conn.Close()
+	upstream.Close()
+
+	m.mu.Lock()
+	delete(f.conns, conn)
+	delete(f.conns, upstream)
+	f.Open--
+	m.mu.Unlock()
+}
+
+// Stop closes a forward and its open connections
+func (m *Manager) Stop(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, f := range m.forwards {
+		if f.ID != id {
+			continue
+		}
+		close(f.stopped)
+		f.listener.Close()
+		for conn := range f.conns {
+			conn.Close()
+		}
+		m.forwards = append(m.forwards[:i], m.forwards[i+1:]...)
+		return nil
+	}
+	return errors.New("no such forward")
+}
+
+// List returns the forwards, oldest first
+func (m *Manager) List() []Forward {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	list := make([]Forward, len(m.forwards))
+	for i, f := range m.forwards {
+		list[i] = f.Forward
+	}
+	return list
+}
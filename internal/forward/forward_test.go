@@ -0,0 +1,140 @@
+package forward
+
+import (
+	"bufio"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestParseSpec(t *testing.T) {
+	tests := []struct {
+		spec          string
+		local, remote int
+		ok            bool
+	}{
+		{"8080:80", 8080, 80, true},
+		{" 80 ", 0, 80, true},
+		{"0:5432", 0, 5432, true},
+		{"http", 0, 0, false},
+		{"8080:", 0, 0, false},
+		{"70000:80", 0, 0, false},
+		{"8080:0", 0, 0, false},
+	}
+	for _, tt := range tests {
+		local, remote, err := ParseSpec(tt.spec)
+		if local != tt.local || remote != tt.remote || (err == nil) != tt.ok {
+			t.Errorf("ParseSpec(%q) = (%d, %d, %v), want (%d, %d, ok %t)", tt.spec, local, remote, err, tt.local, tt.remote, tt.ok)
+		}
+	}
+}
+
+// echoServer answers each line with the same line
+func echoServer(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					conn.Write([]byte(scanner.Text() + "\n"))
+				}
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestManager(t *testing.T) {
+	var m Manager
+	target := echoServer(t)
+
+	f, err := m.Start("web", 0, target)
+	if err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	if f.ID != 1 || f.Container != "web" || f.Target != target {
+		t.Errorf("Start() = %+v", f)
+	}
+
+	conn, err := net.Dial("tcp", f.Local)
+	if err != nil {
+		t.Fatalf("dialing the forward: %v", err)
+	}
+	defer conn.Close()
+	conn.Write([]byte("ping\n"))
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil || reply != "ping\n" {
+		t.Errorf("reply through the forward = %q, %v", reply, err)
+	}
+	if list := m.List(); len(list) != 1 || list[0].Open != 1 || list[0].Total != 1 {
+		t.Errorf("List() = %+v, want one forward with one connection", list)
+	}
+
+	if err := m.Stop(f.ID); err != nil {
+		t.Fatalf("Stop() error: %v", err)
+	}
+	if list := m.List(); len(list) != 0 {
+		t.Errorf("List() after Stop() = %+v", list)
+	}
+	if _, err := net.DialTimeout("tcp", f.Local, time.Second); err == nil {
+		t.Error("the forward still listens after Stop()")
+	}
+	if err := m.Stop(f.ID); err == nil {
+		t.Error("Stop() of a stopped forward should fail")
+	}
+
+	// Nothing listens on the target
+	closed, _ := net.Listen("tcp", "127.0.0.1:0")
+	addr := closed.Addr().String()
+	closed.Close()
+	if _, err := m.Start("db", 0, addr); err == nil {
+		t.Error("Start() to an unreachable target should fail")
+	}
+}
+
+func TestStopWhileDialing(t *testing.T) {
+	var m Manager
+	f, err := m.Start("web", 0, echoServer(t))
+	if err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	m.mu.Lock()
+	running := m.forwards[0]
+	m.mu.Unlock()
+
+	// A connection whose dial to the container finishes after Stop
+	client, server := net.Pipe()
+	defer client.Close()
+	if err := m.Stop(f.ID); err != nil {
+		t.Fatalf("Stop() error: %v", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		m.proxy(running, server)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("a connection dialed after Stop() is still proxied")
+	}
+
+	if running.Total != 0 {
+		t.Errorf("a connection was registered after Stop(): %d total", running.Total)
+	}
+	if _, err := client.Write([]byte("x")); err == nil {
+		t.Error("the connection stays open after Stop()")
+	}
+}
@@ -0,0 +1,69 @@
+// Package history keeps the last status, action and error messages of a
+// session, so that one which flashed by in the action bar can be read again.
+package history
+
+import (
+	"strings"
+	"time"
+)
+
+// Size is how many messages a log keeps
+const Size = 100
+
+// Entry is a message and when it was shown
+type Entry struct {
+	Time  time.Time
+	Text  string
+	Error bool
+}
+
+// Log is a ring buffer of the last Size messages
+type Log struct {
+	entries []Entry
+	next    int // Slot the next message goes into once the buffer is full
+}
+
+// Add records a message shown at the given time. Errors are recognized by
+// their "ERROR" prefix. A message repeating the newest one is not recorded
+// again.
+func (l *Log) Add(at time.Time, text string) {
+	if text == "" {
+		return
+	}
+	if len(l.entries) > 0 && l.newest().Text == text {
+		return
+	}
+
+	entry := Entry{Time: at, Text: text, Error: strings.HasPrefix(text, "ERROR")}
+	if len(l.entries) < Size {
+		l.entries = append(l.entries, entry)
+		return
+	}
+	l.entries[l.next] = entry
+	l.next = (l.next + 1) % Size
+}
+
+// Len returns the number of messages kept
+func (l *Log) Len() int {
+	return len(l.entries)
+}
+
+// Entries returns the messages kept, newest first
+func (l *Log) Entries() []Entry {
+	entries := make([]Entry, 0, len(l.entries))
+	for i := len(l.entries) - 1; i >= 0; i-- {
+		entries = append(entries, l.entries[(l.next+i)%len(l.entries)])
+	}
+	return entries
+}
+
+// Clear drops every message
+func (l *Log) Clear() {
+	l.entries = nil
+	l.next = 0
+}
+
+// newest returns the last message added; the log must not be empty
+func (l *Log) newest() Entry {
+	return l.entries[(l.next+len(l.entries)-1)%len(l.entries)]
+}
@@ -0,0 +1,51 @@
+package history
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestLog(t *testing.T) {
+	var l Log
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	l.Add(start, "Container started")
+	l.Add(start, "Container started")
+	l.Add(start, "")
+	l.Add(start.Add(time.Second), "ERROR: failed to stop container")
+
+	entries := l.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("Entries() = %+v, want 2 entries", entries)
+	}
+	if !entries[0].Error || entries[0].Text != "ERROR: failed to stop container" || entries[1].Error {
+		t.Errorf("Entries() = %+v, want the error first", entries)
+	}
+
+	l.Clear()
+	if l.Len() != 0 {
+		t.Errorf("Len() after Clear() = %d", l.Len())
+	}
+}
+
+func TestLogWrapsAround(t *testing.T) {
+	var l Log
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i := range Size + 5 {
+		l.Add(start.Add(time.Duration(i)*time.Second), fmt.Sprintf("message %d", i))
+	}
+
+	entries := l.Entries()
+	if len(entries) != Size {
+		t.Fatalf("Len() = %d, want %d", len(entries), Size)
+	}
+	if entries[0].Text != fmt.Sprintf("message %d", Size+4) || entries[Size-1].Text != "message 5" {
+		t.Errorf("Entries() run from %q to %q", entries[0].Text, entries[Size-1].Text)
+	}
+
+	// A repeat of the newest message is still detected after wrapping
+	l.Add(start, fmt.Sprintf("message %d", Size+4))
+	if l.Entries()[0].Time.Equal(start) {
+		t.Error("repeated newest message was recorded again")
+	}
+}
@@ -0,0 +1,137 @@
+// Package hooks runs shell commands on the host before and after tinyd
+// starts or stops a container, e.g. to post to a chat webhook or flush a
+// cache. Hooks are read from hooks.json in the user config directory:
+//
+//	[
+//	  {"event": "post-stop", "command": "notify-send \"$TINYD_CONTAINER_NAME stopped\""},
+//	  {"container": "db", "event": "pre-stop", "command": "./backup.sh"}
+//	]
+//
+// A hook without a container applies to all of them; a container may be a
+// glob such as "web-*". A failing pre hook cancels the action.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"time"
+
+	"tinyd/internal/store"
+)
+
+// Timeout bounds a hook, so a hanging command doesn't hold up the action
+const Timeout = 30 * time.Second
+
+// Event is when a hook runs
+type Event string
+
+const (
+	PreStart  Event = "pre-start"
+	PostStart Event = "post-start"
+	PreStop   Event = "pre-stop"
+	PostStop  Event = "post-stop"
+)
+
+// Hook is a command run on the host around an action
+type Hook struct {
+	Container string `json:"container,omitempty"` // Name or glob, "" for every container
+	Event     Event  `json:"event"`
+	Command   string `json:"command"` // Run with sh -c
+}
+
+// Config holds the configured hooks in file order
+type Config struct {
+	Path  string // File the hooks were read from
+	hooks []Hook
+}
+
+// Load reads the hooks saved in the user config directory. A missing file
+// has none; an invalid one is an error, so a hook isn't silently skipped.
+func Load() (Config, error) {
+	file := store.Path("hooks.json")
+	if file == "" {
+		return Config{}, nil
+	}
+	return loadFile(file)
+}
+
+// loadFile reads the hooks saved in path
+func loadFile(file string) (Config, error) {
+	config := Config{Path: file}
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, err
+	}
+	if err := json.Unmarshal(data, &config.hooks); err != nil {
+		return config, fmt.Errorf("%s: %w", file, err)
+	}
+
+	for i, hook := range config.hooks {
+		switch hook.Event {
+		case PreStart, PostStart, PreStop, PostStop:
+		default:
+			return config, fmt.Errorf("%s: hook %d: unknown event %q, use pre-start, post-start, pre-stop or post-stop", file, i+1, hook.Event)
+		}
+		if strings.TrimSpace(hook.Command) == "" {
+			return config, fmt.Errorf("%s: hook %d: no command", file, i+1)
+		}
+		if _, err := path.Match(hook.Container, ""); err != nil {
+			return config, fmt.Errorf("%s: hook %d: invalid container pattern %q", file, i+1, hook.Container)
+		}
+	}
+	return config, nil
+}
+
+// Len returns the number of hooks configured
+func (c Config) Len() int {
+	return len(c.hooks)
+}
+
+// For returns the hooks to run on event for the named container, in file
+// order
+func (c Config) For(event Event, container string) []Hook {
+	var hooks []Hook
+	for _, hook := range c.hooks {
+		if hook.Event != event {
+			continue
+		}
+		if ok, _ := path.Match(hook.Container, container); hook.Container == "" || ok {
+			hooks = append(hooks, hook)
+		}
+	}
+	return hooks
+}
+
+// Run runs a hook with sh -c for a container, whose ID and name are passed
+// in TINYD_CONTAINER_ID and TINYD_CONTAINER_NAME, and the event in
+// TINYD_HOOK_EVENT. It returns the trimmed combined output of the command.
+func Run(ctx context.Context, hook Hook, containerID, containerName string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", hook.Command)
+	cmd.Env = append(os.Environ(),
+		"TINYD_CONTAINER_ID="+containerID,
+		"TINYD_CONTAINER_NAME="+containerName,
+		"TINYD_HOOK_EVENT="+string(hook.Event),
+	)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timed out after %s", Timeout)
+	}
+	return strings.TrimSpace(out.String()), err
+}
@@ -0,0 +1,65 @@
+package hooks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeHooks(t *testing.T, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "hooks.json")
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestLoadFile(t *testing.T) {
+	config, err := loadFile(filepath.Join(t.TempDir(), "hooks.json"))
+	if err != nil || config.Len() != 0 {
+		t.Errorf("loadFile() of a missing file = %d hooks, %v", config.Len(), err)
+	}
+
+	config, err = loadFile(writeHooks(t, `[
+		{"event": "post-stop", "command": "echo all"},
+		{"container": "web-*", "event": "post-stop", "command": "echo web"},
+		{"container": "db", "event": "pre-start", "command": "echo db"}
+	]`))
+	if err != nil || config.Len() != 3 {
+		t.Fatalf("loadFile() = %d hooks, %v", config.Len(), err)
+	}
+	if hooks := config.For(PostStop, "web-1"); len(hooks) != 2 || hooks[0].Command != "echo all" || hooks[1].Command != "echo web" {
+		t.Errorf("For(post-stop, web-1) = %+v", hooks)
+	}
+	if hooks := config.For(PostStop, "db"); len(hooks) != 1 {
+		t.Errorf("For(post-stop, db) = %+v", hooks)
+	}
+	if hooks := config.For(PreStart, "web-1"); len(hooks) != 0 {
+		t.Errorf("For(pre-start, web-1) = %+v", hooks)
+	}
+
+	for name, content := range map[string]string{
+		"invalid json":    `{`,
+		"unknown event":   `[{"event": "on-stop", "command": "true"}]`,
+		"no command":      `[{"event": "pre-stop", "command": " "}]`,
+		"invalid pattern": `[{"container": "web-[", "event": "pre-stop", "command": "true"}]`,
+	} {
+		if _, err := loadFile(writeHooks(t, content)); err == nil {
+			t.Errorf("loadFile() with %s should fail", name)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	hook := Hook{Event: PreStop, Command: `echo "$TINYD_HOOK_EVENT $TINYD_CONTAINER_NAME $TINYD_CONTAINER_ID"; echo oops >&2`}
+	out, err := Run(context.Background(), hook, "abc123", "web")
+	if err != nil || out != "pre-stop web abc123\noops" {
+		t.Errorf("Run() = %q, %v", out, err)
+	}
+
+	if _, err := Run(context.Background(), Hook{Event: PostStop, Command: "exit 3"}, "abc123", "web"); err == nil {
+		t.Error("Run() of a failing command should fail")
+	}
+}
@@ -0,0 +1,103 @@
+// Package notes keeps free-text notes on containers and images, e.g. "do
+// not delete, belongs to the demo", so that they are seen by whoever works
+// on a shared host with tinyd. Notes are local to this machine: they are
+// saved per daemon to a file of the user config directory.
+package notes
+
+import (
+	"strings"
+	"time"
+
+	"tinyd/internal/store"
+)
+
+// Kinds of objects a note is attached to
+const (
+	Container = "container"
+	Image     = "image"
+)
+
+// Note is the text attached to a container or image. It is found by the ID,
+// or by the name once the ID is gone, so that a note outlives recreating the
+// container or pulling the tag again.
+type Note struct {
+	Kind    string
+	ID      string
+	Name    string // Container name or image repository:tag
+	Text    string
+	Updated time.Time
+}
+
+// Store holds the notes of one daemon
+type Store struct {
+	path  string // "" when there is no config directory
+	notes []Note
+}
+
+// Open returns the notes on the objects of the daemon at host. A missing
+// or unreadable file starts empty.
+func Open(host string) *Store {
+	return openFile(store.DaemonPath("notes", host))
+}
+
+// openFile returns the notes saved in path
+func openFile(path string) *Store {
+	s := &Store{path: path}
+	if store.Load(path, &s.notes) != nil {
+		s.notes = nil
+	}
+	return s
+}
+
+// Get returns the note on the object of that kind, ID and name
+func (s *Store) Get(kind, id, name string) (Note, bool) {
+	if i := s.index(kind, id, name); i >= 0 {
+		return s.notes[i], true
+	}
+	return Note{}, false
+}
+
+// Has reports whether the object has a note
+func (s *Store) Has(kind, id, name string) bool {
+	return s.index(kind, id, name) >= 0
+}
+
+// Set attaches text to the object, replacing its note; an empty text removes
+// the note
+func (s *Store) Set(kind, id, name, text string) error {
+	text = strings.TrimSpace(text)
+	i := s.index(kind, id, name)
+	switch {
+	case text == "" && i < 0:
+		return nil
+	case text == "":
+		s.notes = append(s.notes[:i], s.notes[i+1:]...)
+	case i < 0:
+		s.notes = append(s.notes, Note{Kind: kind, ID: id, Name: name, Text: text, Updated: time.Now()})
+	default:
+		s.notes[i] = Note{Kind: kind, ID: id, Name: name, Text: text, Updated: time.Now()}
+	}
+	return s.write()
+}
+
+// index returns the position of the note matching the ID, else the name, or -1
+func (s *Store) index(kind, id, name string) int {
+	byName := -1
+	for i, n := range s.notes {
+		if n.Kind != kind {
+			continue
+		}
+		if id != "" && n.ID == id {
+			return i
+		}
+		if byName < 0 && name != "" && n.Name == name {
+			byName = i
+		}
+	}
+	return byName
+}
+
+// write saves the notes to the file
+func (s *Store) write() error {
+	return store.Save(s.path, s.notes)
+}
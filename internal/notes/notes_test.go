@@ -0,0 +1,46 @@
+package notes
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tinyd", "notes.json")
+
+	s := openFile(path)
+	if s.Has(Container, "abc123", "web") {
+		t.Fatal("Has() on a new store should be false")
+	}
+	if err := s.Set(Container, "abc123", "web", "  belongs to the demo "); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+
+	// A later run finds it by ID, and by name once the container is recreated
+	s = openFile(path)
+	if n, ok := s.Get(Container, "abc123", "web"); !ok || n.Text != "belongs to the demo" {
+		t.Errorf("Get() by ID = %+v, %v", n, ok)
+	}
+	if n, ok := s.Get(Container, "def456", "web"); !ok || n.Text != "belongs to the demo" {
+		t.Errorf("Get() by name = %+v, %v", n, ok)
+	}
+	if s.Has(Image, "abc123", "web") {
+		t.Error("Has() should not match another kind")
+	}
+
+	// Setting it from the recreated container moves the note to the new ID
+	if err := s.Set(Container, "def456", "web", "keep"); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+	if n, _ := s.Get(Container, "def456", ""); n.Text != "keep" || len(s.notes) != 1 {
+		t.Errorf("Get() after update = %+v, %d notes", n, len(s.notes))
+	}
+
+	// An empty text removes the note
+	if err := s.Set(Container, "def456", "web", " "); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+	if openFile(path).Has(Container, "def456", "web") {
+		t.Error("Has() after removal should be false")
+	}
+}
@@ -0,0 +1,205 @@
+// Package provision compares what containers are given, their CPU and
+// memory limits and memory reservation, with the peak usage seen in the
+// stats while tinyd runs, to point out containers that are badly over- or
+// under-provisioned.
+package provision
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/go-units"
+
+	"tinyd/internal/types"
+)
+
+// Peak is the highest usage seen of a container
+type Peak struct {
+	CPUPercent float64 // In percent of one CPU
+	MemBytes   uint64
+	Samples    int
+}
+
+// Tracker keeps the peak usage of each container seen in the stats
+type Tracker struct {
+	peaks map[string]Peak
+}
+
+// NewTracker returns a tracker that has seen no stats yet
+func NewTracker() *Tracker {
+	return &Tracker{peaks: make(map[string]Peak)}
+}
+
+// Observe takes a stats sample of the streamed containers, by ID
+func (t *Tracker) Observe(stats map[string]types.ContainerStats) {
+	for id, s := range stats {
+		if s.CPU == "--" && s.Mem == "--" {
+			continue // No sample yet
+		}
+		peak := t.peaks[id]
+		peak.CPUPercent = max(peak.CPUPercent, s.CPUPercent)
+		peak.MemBytes = max(peak.MemBytes, s.MemBytes)
+		peak.Samples++
+		t.peaks[id] = peak
+	}
+}
+
+// Peak returns the peak usage seen of a container
+func (t *Tracker) Peak(id string) Peak {
+	return t.peaks[id]
+}
+
+// Verdict sums up how well a container is provisioned, worst first
+type Verdict int
+
+const (
+	Under     Verdict = iota // Usage reaches a limit or exceeds the reservation
+	Over                     // Usage stays far below the limits or reservation
+	Unlimited                // Neither a CPU nor a memory limit
+	OK
+	NoData // Too few stats samples to tell
+)
+
+// String returns the verdict as shown in the report
+func (v Verdict) String() string {
+	switch v {
+	case Under:
+		return "under-provisioned"
+	case Over:
+		return "over-provisioned"
+	case Unlimited:
+		return "unlimited"
+	case OK:
+		return "ok"
+	}
+	return "no data"
+}
+
+// Thresholds of the assessment: usage from underShare of a limit up is
+// close to it, below overShare it could do with much less. minSamples
+// samples are needed before a container is judged.
+const (
+	underShare = 0.9
+	overShare  = 0.2
+	minSamples = 5
+)
+
+// Row is one container of the report
+type Row struct {
+	Name       string
+	Allocation types.ResourceAllocation
+	Peak       Peak
+	Verdict    Verdict
+	Findings   []string // Why, e.g. "memory peaked at 95% of its 512MiB limit"
+}
+
+// Assess compares a container's allocation with its peak usage
+func Assess(name string, alloc types.ResourceAllocation, peak Peak) Row {
+	row := Row{Name: name, Allocation: alloc, Peak: peak, Verdict: OK}
+	if peak.Samples < minSamples {
+		row.Verdict = NoData
+		row.Findings = []string{fmt.Sprintf("%d stats samples, %d needed; stats are streamed for running containers on screen", peak.Samples, minSamples)}
+		return row
+	}
+
+	var under, over bool
+	if alloc.CPUs > 0 {
+		share := peak.CPUPercent / 100 / alloc.CPUs
+		finding := fmt.Sprintf("CPU peaked at %.0f%% of its %s CPU limit", share*100, strconv.FormatFloat(alloc.CPUs, 'f', -1, 64))
+		switch {
+		case share >= underShare:
+			under = true
+			row.Findings = append(row.Findings, finding)
+		case share < overShare:
+			over = true
+			row.Findings = append(row.Findings, finding)
+		}
+	}
+	if alloc.MemoryLimit > 0 {
+		share := float64(peak.MemBytes) / float64(alloc.MemoryLimit)
+		finding := fmt.Sprintf("memory peaked at %.0f%% of its %s limit", share*100, units.BytesSize(float64(alloc.MemoryLimit)))
+		switch {
+		case share >= underShare:
+			under = true
+			row.Findings = append(row.Findings, finding)
+		case share < overShare:
+			over = true
+			row.Findings = append(row.Findings, finding)
+		}
+	}
+	if alloc.MemoryReservation > 0 {
+		share := float64(peak.MemBytes) / float64(alloc.MemoryReservation)
+		finding := fmt.Sprintf("memory peaked at %.0f%% of its %s reservation", share*100, units.BytesSize(float64(alloc.MemoryReservation)))
+		switch {
+		case share > 1:
+			under = true
+			row.Findings = append(row.Findings, finding)
+		case share < overShare:
+			over = true
+			row.Findings = append(row.Findings, finding)
+		}
+	}
+
+	switch {
+	case under:
+		row.Verdict = Under
+	case over:
+		row.Verdict = Over
+	case alloc.CPUs == 0 && alloc.MemoryLimit == 0:
+		row.Verdict = Unlimited
+		row.Findings = append(row.Findings, "no CPU or memory limit, it can take the whole host")
+	}
+	return row
+}
+
+// Sort orders rows worst verdict first, then by name
+func Sort(rows []Row) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].Verdict != rows[j].Verdict {
+			return rows[i].Verdict < rows[j].Verdict
+		}
+		return rows[i].Name < rows[j].Name
+	})
+}
+
+// csvHeader is the first row of an exported report. Sizes are in bytes
+// and CPU in CPUs, so the columns can be compared in a spreadsheet.
+var csvHeader = []string{"container", "verdict", "cpu_limit", "peak_cpus", "mem_limit_bytes", "mem_reservation_bytes", "peak_mem_bytes", "samples", "findings"}
+
+// WriteCSV writes the report as CSV, limits left empty where unset
+func WriteCSV(w io.Writer, rows []Row) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := []string{
+			row.Name,
+			row.Verdict.String(),
+			optional(row.Allocation.CPUs > 0, strconv.FormatFloat(row.Allocation.CPUs, 'f', -1, 64)),
+			strconv.FormatFloat(row.Peak.CPUPercent/100, 'f', 3, 64),
+			optional(row.Allocation.MemoryLimit > 0, strconv.FormatInt(row.Allocation.MemoryLimit, 10)),
+			optional(row.Allocation.MemoryReservation > 0, strconv.FormatInt(row.Allocation.MemoryReservation, 10)),
+			strconv.FormatUint(row.Peak.MemBytes, 10),
+			strconv.Itoa(row.Peak.Samples),
+			strings.Join(row.Findings, "; "),
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// optional returns value when set, "" otherwise
+func optional(set bool, value string) string {
+	if !set {
+		return ""
+	}
+	return value
+}
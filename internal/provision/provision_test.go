@@ -0,0 +1,101 @@
+package provision
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"tinyd/internal/types"
+)
+
+const mib = 1024 * 1024
+
+func TestTracker(t *testing.T) {
+	tr := NewTracker()
+	tr.Observe(map[string]types.ContainerStats{
+		"a": {CPU: "50.0", Mem: "10MiB", CPUPercent: 50, MemBytes: 10 * mib},
+		"b": {CPU: "--", Mem: "--"},
+	})
+	tr.Observe(map[string]types.ContainerStats{
+		"a": {CPU: "20.0", Mem: "30MiB", CPUPercent: 20, MemBytes: 30 * mib},
+	})
+
+	if got, want := tr.Peak("a"), (Peak{CPUPercent: 50, MemBytes: 30 * mib, Samples: 2}); got != want {
+		t.Errorf("Peak(a) = %+v, want %+v", got, want)
+	}
+	if got := tr.Peak("b"); got.Samples != 0 {
+		t.Errorf("Peak(b).Samples = %d, want 0 for a container without a sample", got.Samples)
+	}
+}
+
+func TestAssess(t *testing.T) {
+	tests := []struct {
+		name     string
+		alloc    types.ResourceAllocation
+		peak     Peak
+		want     Verdict
+		findings int
+	}{
+		{"too few samples", types.ResourceAllocation{CPUs: 1}, Peak{CPUPercent: 100, Samples: 2}, NoData, 1},
+		{"unlimited", types.ResourceAllocation{}, Peak{CPUPercent: 80, MemBytes: mib, Samples: 10}, Unlimited, 1},
+		{"ok", types.ResourceAllocation{CPUs: 2, MemoryLimit: 100 * mib}, Peak{CPUPercent: 100, MemBytes: 50 * mib, Samples: 10}, OK, 0},
+		{"cpu at the limit", types.ResourceAllocation{CPUs: 0.5}, Peak{CPUPercent: 49, Samples: 10}, Under, 1},
+		{"memory at the limit", types.ResourceAllocation{MemoryLimit: 100 * mib}, Peak{MemBytes: 95 * mib, Samples: 10}, Under, 1},
+		{"memory above the reservation", types.ResourceAllocation{MemoryReservation: 40 * mib}, Peak{MemBytes: 50 * mib, Samples: 10}, Under, 1},
+		{"idle cpu", types.ResourceAllocation{CPUs: 4, MemoryLimit: 100 * mib}, Peak{CPUPercent: 10, MemBytes: 50 * mib, Samples: 10}, Over, 1},
+		{"idle memory", types.ResourceAllocation{MemoryLimit: 1024 * mib, MemoryReservation: 512 * mib}, Peak{MemBytes: 20 * mib, Samples: 10}, Over, 2},
+		{"under beats over", types.ResourceAllocation{CPUs: 4, MemoryLimit: 100 * mib}, Peak{CPUPercent: 10, MemBytes: 99 * mib, Samples: 10}, Under, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := Assess("web", tt.alloc, tt.peak)
+			if row.Verdict != tt.want {
+				t.Errorf("Verdict = %v, want %v (findings %q)", row.Verdict, tt.want, row.Findings)
+			}
+			if len(row.Findings) != tt.findings {
+				t.Errorf("Findings = %q, want %d", row.Findings, tt.findings)
+			}
+		})
+	}
+}
+
+func TestSort(t *testing.T) {
+	rows := []Row{
+		{Name: "b", Verdict: OK},
+		{Name: "c", Verdict: Under},
+		{Name: "a", Verdict: OK},
+		{Name: "d", Verdict: Over},
+	}
+	Sort(rows)
+	var names []string
+	for _, row := range rows {
+		names = append(names, row.Name)
+	}
+	if got := strings.Join(names, ","); got != "c,d,a,b" {
+		t.Errorf("Sort() order = %s, want c,d,a,b", got)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	rows := []Row{
+		Assess("web", types.ResourceAllocation{CPUs: 0.5, MemoryLimit: 100 * mib}, Peak{CPUPercent: 49, MemBytes: 50 * mib, Samples: 10}),
+		Assess("db", types.ResourceAllocation{}, Peak{Samples: 1}),
+	}
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, rows); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want header and 2 rows:\n%s", len(lines), buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "container,verdict,cpu_limit") {
+		t.Errorf("header = %q", lines[0])
+	}
+	if want := "web,under-provisioned,0.5,0.490,104857600,,52428800,10,"; !strings.HasPrefix(lines[1], want) {
+		t.Errorf("row = %q, want prefix %q", lines[1], want)
+	}
+	if want := "db,no data,,0.000,,,0,1,"; !strings.HasPrefix(lines[2], want) {
+		t.Errorf("row = %q, want prefix %q", lines[2], want)
+	}
+}
@@ -0,0 +1,114 @@
+// Package recording appends container usage samples to a CSV file, for
+// capturing data during a load test without monitoring infrastructure.
+package recording
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"tinyd/internal/types"
+)
+
+// header is the first row of a new recording. Rows are one container per
+// sample, so containers can be filtered and pivoted in any spreadsheet.
+var header = []string{"timestamp", "container", "cpu_percent", "mem_bytes", "mem_limit_bytes", "mem_percent"}
+
+// Recorder writes samples of a fixed set of containers to a CSV file
+type Recorder struct {
+	path       string
+	file       *os.File
+	csv        *csv.Writer
+	containers map[string]string // ID to name
+	samples    int
+}
+
+// Start opens path for appending, writing the header when the file is new,
+// and records the given containers (ID to name) from then on
+func Start(path string, containers map[string]string) (*Recorder, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+
+	r := &Recorder{path: path, file: file, csv: csv.NewWriter(file), containers: containers}
+	if info.Size() == 0 {
+		if err := r.csv.Write(header); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return r, nil
+}
+
+// Path returns the file being written
+func (r *Recorder) Path() string {
+	return r.path
+}
+
+// Containers returns the IDs of the recorded containers
+func (r *Recorder) Containers() []string {
+	ids := make([]string, 0, len(r.containers))
+	for id := range r.containers {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Samples returns the number of rows written so far
+func (r *Recorder) Samples() int {
+	return r.samples
+}
+
+// Record writes a row for each recorded container that has stats in
+// stats, e.g. skipping containers that are stopped at the moment
+func (r *Recorder) Record(now time.Time, stats map[string]types.ContainerStats) error {
+	timestamp := now.Format(time.RFC3339)
+	for _, id := range r.Containers() {
+		s, ok := stats[id]
+		if !ok {
+			continue
+		}
+		memPercent := ""
+		if s.MemLimit > 0 {
+			memPercent = strconv.FormatFloat(float64(s.MemBytes)*100/float64(s.MemLimit), 'f', 2, 64)
+		}
+		row := []string{
+			timestamp,
+			r.containers[id],
+			strconv.FormatFloat(s.CPUPercent, 'f', 2, 64),
+			strconv.FormatUint(s.MemBytes, 10),
+			strconv.FormatUint(s.MemLimit, 10),
+			memPercent,
+		}
+		if err := r.csv.Write(row); err != nil {
+			return fmt.Errorf("failed to write %s: %w", r.path, err)
+		}
+		r.samples++
+	}
+	// Flush every tick so the file is usable while recording
+	r.csv.Flush()
+	if err := r.csv.Error(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", r.path, err)
+	}
+	return nil
+}
+
+// Close flushes and closes the file
+func (r *Recorder) Close() error {
+	r.csv.Flush()
+	err := r.csv.Error()
+	if closeErr := r.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
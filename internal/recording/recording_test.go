@@ -0,0 +1,58 @@
+package recording
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"tinyd/internal/types"
+)
+
+func TestRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.csv")
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	stats := map[string]types.ContainerStats{
+		"a": {CPUPercent: 12.345, MemBytes: 512, MemLimit: 1024},
+		"b": {CPUPercent: 1, MemBytes: 10},
+	}
+
+	r, err := Start(path, map[string]string{"a": "web", "b": "db", "c": "stopped"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Record(now, stats); err != nil {
+		t.Fatal(err)
+	}
+	if r.Samples() != 2 {
+		t.Errorf("Samples() = %d, want 2 (c has no stats)", r.Samples())
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A second recording to the same file appends without a second header
+	r, err = Start(path, map[string]string{"a": "web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Record(now.Add(time.Second), stats); err != nil {
+		t.Fatal(err)
+	}
+	r.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"timestamp,container,cpu_percent,mem_bytes,mem_limit_bytes,mem_percent",
+		"2024-05-01T12:00:00Z,web,12.35,512,1024,50.00",
+		"2024-05-01T12:00:00Z,db,1.00,10,0,",
+		"2024-05-01T12:00:01Z,web,12.35,512,1024,50.00",
+	}, "\n") + "\n"
+	if string(data) != want {
+		t.Errorf("file =\n%s\nwant\n%s", data, want)
+	}
+}
@@ -0,0 +1,160 @@
+// Package schedule keeps container start/stop/restart actions planned for a
+// later time, e.g. "stop in 2h" for a service that is only needed for a
+// while. Schedules live in memory, as long as tinyd runs.
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Action is what a schedule does to its container
+type Action int
+
+const (
+	Start Action = iota
+	Stop
+	Restart
+)
+
+// String returns the lowercase name of the action
+func (a Action) String() string {
+	switch a {
+	case Stop:
+		return "stop"
+	case Restart:
+		return "restart"
+	}
+	return "start"
+}
+
+// Past returns the action in the past tense, for results
+func (a Action) Past() string {
+	switch a {
+	case Stop:
+		return "stopped"
+	case Restart:
+		return "restarted"
+	}
+	return "started"
+}
+
+// Entry is a planned action on a container
+type Entry struct {
+	ID          int
+	ContainerID string
+	Container   string
+	Action      Action
+	At          time.Time
+}
+
+// String describes the entry, e.g. "stop web at 18:30"
+func (e Entry) String() string {
+	return fmt.Sprintf("%s %s at %s", e.Action, e.Container, e.At.Format("15:04"))
+}
+
+// Parse reads a schedule such as "stop in 2h", "restart in 45m", "start at
+// 18:30" or "stop at 2024-05-01 18:30"; "in" may be left out before a
+// duration ("stop 2h"). A time of day that has passed today means tomorrow.
+func Parse(s string, now time.Time) (Action, time.Time, error) {
+	fields := strings.Fields(strings.ToLower(s))
+	if len(fields) < 2 {
+		return 0, time.Time{}, fmt.Errorf("expected e.g. \"stop in 2h\" or \"start at 18:30\"")
+	}
+
+	var action Action
+	switch fields[0] {
+	case "start":
+		action = Start
+	case "stop":
+		action = Stop
+	case "restart":
+		action = Restart
+	default:
+		return 0, time.Time{}, fmt.Errorf("unknown action %q: use start, stop or restart", fields[0])
+	}
+
+	when := fields[1:]
+	switch when[0] {
+	case "in":
+		when = when[1:]
+	case "at":
+		at, err := parseTime(strings.Join(when[1:], " "), now)
+		return action, at, err
+	}
+	if len(when) != 1 {
+		return 0, time.Time{}, fmt.Errorf("expected a delay such as 2h or 30m")
+	}
+	delay, err := time.ParseDuration(when[0])
+	if err != nil || delay <= 0 {
+		return 0, time.Time{}, fmt.Errorf("invalid delay %q: use e.g. 2h or 30m", when[0])
+	}
+	return action, now.Add(delay), nil
+}
+
+// parseTime reads "15:04" (the next such time) or "2006-01-02 15:04"
+func parseTime(s string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02 15:04", s, now.Location()); err == nil {
+		if !t.After(now) {
+			return time.Time{}, fmt.Errorf("%s is in the past", s)
+		}
+		return t, nil
+	}
+	clock, err := time.ParseInLocation("15:04", s, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: use e.g. 18:30 or 2024-05-01 18:30", s)
+	}
+	t := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+	if !t.After(now) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// List holds the pending schedules
+type List struct {
+	entries []Entry
+	nextID  int
+}
+
+// Add plans an action and returns its entry
+func (l *List) Add(containerID, container string, action Action, at time.Time) Entry {
+	l.nextID++
+	entry := Entry{ID: l.nextID, ContainerID: containerID, Container: container, Action: action, At: at}
+	l.entries = append(l.entries, entry)
+	sort.SliceStable(l.entries, func(i, j int) bool { return l.entries[i].At.Before(l.entries[j].At) })
+	return entry
+}
+
+// Cancel drops a pending entry, reporting whether it was found
+func (l *List) Cancel(id int) bool {
+	for i, entry := range l.entries {
+		if entry.ID == id {
+			l.entries = append(l.entries[:i], l.entries[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Pending returns the entries not yet due, soonest first
+func (l *List) Pending() []Entry {
+	return append([]Entry(nil), l.entries...)
+}
+
+// Due removes and returns the entries due at now
+func (l *List) Due(now time.Time) []Entry {
+	var due []Entry
+	kept := l.entries[:0]
+	for _, entry := range l.entries {
+		if entry.At.After(now) {
+			kept = append(kept, entry)
+		} else {
+			due = append(due, entry)
+		}
+	}
+	l.entries = kept
+	return due
+}
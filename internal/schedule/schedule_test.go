@@ -0,0 +1,62 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	now := time.Date(2024, 5, 1, 14, 0, 0, 0, time.UTC)
+	tests := []struct {
+		input  string
+		action Action
+		at     time.Time
+	}{
+		{"stop in 2h", Stop, now.Add(2 * time.Hour)},
+		{"restart 45m", Restart, now.Add(45 * time.Minute)},
+		{"start at 18:30", Start, time.Date(2024, 5, 1, 18, 30, 0, 0, time.UTC)},
+		{"Stop at 09:00", Stop, time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)},
+		{"stop at 2024-05-03 08:15", Stop, time.Date(2024, 5, 3, 8, 15, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		action, at, err := Parse(tt.input, now)
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", tt.input, err)
+			continue
+		}
+		if action != tt.action || !at.Equal(tt.at) {
+			t.Errorf("Parse(%q) = %v %v, want %v %v", tt.input, action, at, tt.action, tt.at)
+		}
+	}
+
+	for _, input := range []string{"", "stop", "pause in 2h", "stop in soon", "stop in -5m", "stop at 25:00", "stop at 2024-04-01 10:00"} {
+		if _, _, err := Parse(input, now); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", input)
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	now := time.Date(2024, 5, 1, 14, 0, 0, 0, time.UTC)
+	var l List
+	later := l.Add("b", "db", Stop, now.Add(2*time.Hour))
+	soon := l.Add("a", "web", Restart, now.Add(time.Minute))
+	cancelled := l.Add("c", "cache", Start, now.Add(time.Hour))
+
+	if !l.Cancel(cancelled.ID) || l.Cancel(cancelled.ID) {
+		t.Error("Cancel() should succeed once")
+	}
+	if pending := l.Pending(); len(pending) != 2 || pending[0].ID != soon.ID {
+		t.Errorf("Pending() = %v, want soonest first", pending)
+	}
+
+	if due := l.Due(now); len(due) != 0 {
+		t.Errorf("Due() before any entry = %v", due)
+	}
+	if due := l.Due(now.Add(time.Hour)); len(due) != 1 || due[0].ID != soon.ID {
+		t.Errorf("Due() = %v, want the restart", due)
+	}
+	if pending := l.Pending(); len(pending) != 1 || pending[0].ID != later.ID {
+		t.Errorf("Pending() after Due() = %v", pending)
+	}
+}
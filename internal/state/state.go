@@ -0,0 +1,58 @@
+// Package state remembers small bits of the UI between runs, such as the
+// column each table is sorted by. It is saved to a file of the user config
+// directory; unlike workspaces it is written as the UI changes, without
+// being asked to.
+package state
+
+import "tinyd/internal/store"
+
+// Sort is how a table is sorted: the label of the column header, empty for
+// the default order, and the direction
+type Sort struct {
+	Column string `json:",omitempty"`
+	Desc   bool   `json:",omitempty"`
+}
+
+// State is the remembered UI state
+type State struct {
+	path  string          // "" when there is no config directory
+	Sorts map[string]Sort // By table, e.g. "containers"
+}
+
+// Open returns the state saved in the user config directory. A missing or
+// unreadable file starts empty.
+func Open() *State {
+	return openFile(store.Path("state.json"))
+}
+
+// openFile returns the state saved in path
+func openFile(path string) *State {
+	s := &State{path: path}
+	if store.Load(path, s) != nil {
+		*s = State{path: path}
+	}
+	return s
+}
+
+// Sort returns how the table is sorted, the default order when unknown
+func (s *State) Sort(table string) Sort {
+	return s.Sorts[table]
+}
+
+// SetSort remembers how the table is sorted
+func (s *State) SetSort(table string, sort Sort) error {
+	if s.Sorts == nil {
+		s.Sorts = make(map[string]Sort)
+	}
+	if sort == (Sort{}) {
+		delete(s.Sorts, table)
+	} else {
+		s.Sorts[table] = sort
+	}
+	return s.write()
+}
+
+// write saves the state to the file
+func (s *State) write() error {
+	return store.Save(s.path, s)
+}
@@ -0,0 +1,57 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSorts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tinyd", "state.json")
+
+	s := openFile(path)
+	if got := s.Sort("containers"); got != (Sort{}) {
+		t.Errorf("Sort() of a new state = %+v, want the default", got)
+	}
+
+	if err := s.SetSort("containers", Sort{Column: "MEM", Desc: true}); err != nil {
+		t.Fatalf("SetSort() error: %v", err)
+	}
+	if err := s.SetSort("images", Sort{Column: "SIZE"}); err != nil {
+		t.Fatalf("SetSort() error: %v", err)
+	}
+	// Back to the default order forgets the table
+	if err := s.SetSort("images", Sort{}); err != nil {
+		t.Fatalf("SetSort() error: %v", err)
+	}
+
+	// A later run reads what was saved
+	reopened := openFile(path)
+	if got := reopened.Sort("containers"); got != (Sort{Column: "MEM", Desc: true}) {
+		t.Errorf("Sort(containers) after reopening = %+v", got)
+	}
+	if _, ok := reopened.Sorts["images"]; ok {
+		t.Errorf("Sorts after reopening = %+v, want no images entry", reopened.Sorts)
+	}
+}
+
+func TestOpenFileInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := openFile(path).Sort("containers"); got != (Sort{}) {
+		t.Errorf("Sort() from an invalid file = %+v, want the default", got)
+	}
+}
+
+func TestSetSortWithoutConfigDir(t *testing.T) {
+	s := &State{}
+	if err := s.SetSort("containers", Sort{Column: "NAME"}); err == nil {
+		t.Error("SetSort() without a config directory should fail")
+	}
+	// The sort still applies for this run
+	if got := s.Sort("containers"); got.Column != "NAME" {
+		t.Errorf("Sort() = %+v, want NAME", got)
+	}
+}
@@ -0,0 +1,91 @@
+// Package statshistory keeps the last stats samples of a container, so that
+// the stats dashboard can graph how its usage moved rather than only show
+// the latest values.
+package statshistory
+
+import (
+	"time"
+
+	"tinyd/internal/types"
+)
+
+// Size is how many samples a ring keeps, five minutes at one a second
+const Size = 300
+
+// Sample is the stats of a container and when they were read
+type Sample struct {
+	Time  time.Time
+	Stats types.ContainerStats
+}
+
+// Ring is a ring buffer of the last Size samples
+type Ring struct {
+	samples []Sample
+	next    int // Slot the next sample goes into once the buffer is full
+}
+
+// Add records a sample read at the given time. Samples without any
+// reading, before the first one arrives from the stream, are skipped.
+func (r *Ring) Add(at time.Time, stats types.ContainerStats) {
+	if stats.CPU == "--" && stats.Mem == "--" {
+		return
+	}
+
+	sample := Sample{Time: at, Stats: stats}
+	if len(r.samples) < Size {
+		r.samples = append(r.samples, sample)
+		return
+	}
+	r.samples[r.next] = sample
+	r.next = (r.next + 1) % Size
+}
+
+// Len returns the number of samples kept
+func (r *Ring) Len() int {
+	return len(r.samples)
+}
+
+// Samples returns the samples kept, oldest first
+func (r *Ring) Samples() []Sample {
+	samples := make([]Sample, 0, len(r.samples))
+	for i := range r.samples {
+		samples = append(samples, r.samples[(r.next+i)%len(r.samples)])
+	}
+	return samples
+}
+
+// Clear drops every sample
+func (r *Ring) Clear() {
+	r.samples = nil
+	r.next = 0
+}
+
+// Values returns a gauge of each sample, e.g. its CPU percentage
+func Values(samples []Sample, gauge func(types.ContainerStats) float64) []float64 {
+	values := make([]float64, len(samples))
+	for i, s := range samples {
+		values[i] = gauge(s.Stats)
+	}
+	return values
+}
+
+// Rates returns how fast a counter, e.g. the bytes received, grew per
+// second between each sample and the one before, so one less value than
+// samples. A counter that went down, as it does when the container
+// restarts, counts as no growth.
+func Rates(samples []Sample, counter func(types.ContainerStats) uint64) []float64 {
+	if len(samples) < 2 {
+		return nil
+	}
+	rates := make([]float64, 0, len(samples)-1)
+	for i := 1; i < len(samples); i++ {
+		prev, cur := counter(samples[i-1].Stats), counter(samples[i].Stats)
+		elapsed := samples[i].Time.Sub(samples[i-1].Time).Seconds()
+		if cur < prev || elapsed <= 0 {
+			rates = append(rates, 0)
+			continue
+		}
+		rates = append(rates, float64(cur-prev)/elapsed)
+	}
+	return rates
+}
@@ -0,0 +1,62 @@
+package statshistory
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"tinyd/internal/types"
+)
+
+func TestRing(t *testing.T) {
+	var r Ring
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	r.Add(start, types.ContainerStats{CPU: "--", Mem: "--"})
+	if r.Len() != 0 {
+		t.Fatalf("Len() = %d after a sample without readings, want 0", r.Len())
+	}
+
+	for i := range Size + 2 {
+		r.Add(start.Add(time.Duration(i)*time.Second), types.ContainerStats{CPU: "1.0", CPUPercent: float64(i)})
+	}
+	if r.Len() != Size {
+		t.Fatalf("Len() = %d, want %d", r.Len(), Size)
+	}
+	samples := r.Samples()
+	if first, last := samples[0].Stats.CPUPercent, samples[Size-1].Stats.CPUPercent; first != 2 || last != Size+1 {
+		t.Errorf("Samples() runs from %v to %v, want 2 to %d, oldest first", first, last, Size+1)
+	}
+
+	r.Clear()
+	if r.Len() != 0 {
+		t.Errorf("Len() = %d after Clear, want 0", r.Len())
+	}
+}
+
+func TestValues(t *testing.T) {
+	samples := []Sample{
+		{Stats: types.ContainerStats{MemBytes: 10}},
+		{Stats: types.ContainerStats{MemBytes: 20}},
+	}
+	got := Values(samples, func(s types.ContainerStats) float64 { return float64(s.MemBytes) })
+	if want := []float64{10, 20}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Values() = %v, want %v", got, want)
+	}
+}
+
+func TestRates(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	samples := []Sample{
+		{Time: start, Stats: types.ContainerStats{NetRx: 1000}},
+		{Time: start.Add(time.Second), Stats: types.ContainerStats{NetRx: 3000}},
+		{Time: start.Add(3 * time.Second), Stats: types.ContainerStats{NetRx: 4000}},
+		{Time: start.Add(4 * time.Second), Stats: types.ContainerStats{NetRx: 100}}, // Restarted
+	}
+	got := Rates(samples, func(s types.ContainerStats) uint64 { return s.NetRx })
+	if want := []float64{2000, 500, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Rates() = %v, want %v", got, want)
+	}
+	if got := Rates(samples[:1], func(s types.ContainerStats) uint64 { return s.NetRx }); got != nil {
+		t.Errorf("Rates() of one sample = %v, want nil", got)
+	}
+}
@@ -0,0 +1,134 @@
+// Package store saves the small JSON files tinyd keeps in the user config
+// directory, such as the workspaces or the notes, and holds the named
+// lists several of them are made of.
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Path returns the path of the named file in tinyd's directory of the user
+// config directory, "" when there is no config directory
+func Path(name string) string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "tinyd", name)
+}
+
+// DaemonPath returns the path of a file kept per daemon, named after the
+// prefix and a digest of the daemon's host, e.g. "notes-3f2a9c1b7d4e.json"
+func DaemonPath(prefix, host string) string {
+	sum := sha256.Sum256([]byte(host))
+	return Path(prefix + "-" + hex.EncodeToString(sum[:6]) + ".json")
+}
+
+// Load decodes the JSON file at path into v. A missing file is an error
+// too; callers start empty on any error.
+func Load(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// Save encodes v as JSON into the file at path. The file is written then
+// renamed, so a crash mid-write keeps the previous one, and only the user
+// can read it, as some files hold secrets such as exec environments.
+func Save(path string, v any) error {
+	if path == "" {
+		return errors.New("no config directory to save in")
+	}
+	name := filepath.Base(path)
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to save %s: %w", name, err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to save %s: %w", name, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to save %s: %w", name, err)
+	}
+	return nil
+}
+
+// Named is a list of items saved to a file and kept sorted by name, names
+// being unique ignoring case
+type Named[T any] struct {
+	path  string // "" when there is no config directory
+	kind  string // What an item is called in errors, e.g. "workspace"
+	name  func(T) string
+	items []T
+}
+
+// OpenNamed returns the items saved in path. A missing or unreadable file
+// starts empty.
+func OpenNamed[T any](path, kind string, name func(T) string) *Named[T] {
+	n := &Named[T]{path: path, kind: kind, name: name}
+	if Load(path, &n.items) != nil {
+		n.items = nil
+	}
+	n.sort()
+	return n
+}
+
+// List returns the items, sorted by name
+func (n *Named[T]) List() []T {
+	return append([]T(nil), n.items...)
+}
+
+// Save adds an item, replacing the one of the same name
+func (n *Named[T]) Save(item T) error {
+	if strings.TrimSpace(n.name(item)) == "" {
+		return fmt.Errorf("a %s needs a name", n.kind)
+	}
+	if i := n.index(n.name(item)); i >= 0 {
+		n.items[i] = item
+	} else {
+		n.items = append(n.items, item)
+	}
+	n.sort()
+	return Save(n.path, n.items)
+}
+
+// Delete removes the item of that name
+func (n *Named[T]) Delete(name string) error {
+	i := n.index(name)
+	if i < 0 {
+		return fmt.Errorf("no %s named %q", n.kind, name)
+	}
+	n.items = append(n.items[:i], n.items[i+1:]...)
+	return Save(n.path, n.items)
+}
+
+// index returns the position of the named item, or -1
+func (n *Named[T]) index(name string) int {
+	for i, item := range n.items {
+		if strings.EqualFold(n.name(item), name) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (n *Named[T]) sort() {
+	sort.SliceStable(n.items, func(i, j int) bool {
+		return strings.ToLower(n.name(n.items[i])) < strings.ToLower(n.name(n.items[j]))
+	})
+}
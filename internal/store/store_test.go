@@ -0,0 +1,91 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tinyd", "state.json")
+
+	var missing map[string]int
+	if err := Load(path, &missing); err == nil {
+		t.Error("Load() of a missing file should fail")
+	}
+	if err := Save("", map[string]int{}); err == nil {
+		t.Error("Save() without a config directory should fail")
+	}
+
+	if err := Save(path, map[string]int{"a": 1}); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	var got map[string]int
+	if err := Load(path, &got); err != nil || got["a"] != 1 {
+		t.Errorf("Load() = %v, %v", got, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0o600 {
+		t.Errorf("file mode = %v, want 0600", info.Mode().Perm())
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("the temporary file is left behind: %v", err)
+	}
+}
+
+// item is a named thing for the tests
+type item struct {
+	Name  string
+	Value int
+}
+
+func openItems(path string) *Named[item] {
+	return OpenNamed(path, "item", func(i item) string { return i.Name })
+}
+
+func TestNamed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tinyd", "items.json")
+
+	n := openItems(path)
+	if len(n.List()) != 0 {
+		t.Fatal("List() of a new store should be empty")
+	}
+	if err := n.Save(item{Name: " "}); err == nil {
+		t.Error("Save() without a name should fail")
+	}
+
+	for _, i := range []item{{"b", 1}, {"A", 2}, {"B", 3}} {
+		if err := n.Save(i); err != nil {
+			t.Fatalf("Save(%+v) error: %v", i, err)
+		}
+	}
+
+	// A later run reads what was saved, sorted by name; the same name in
+	// another case replaced the first item
+	list := openItems(path).List()
+	if len(list) != 2 || list[0] != (item{"A", 2}) || list[1] != (item{"B", 3}) {
+		t.Errorf("List() after reopening = %+v", list)
+	}
+
+	if err := n.Delete("a"); err != nil {
+		t.Errorf("Delete() error: %v", err)
+	}
+	if err := n.Delete("a"); err == nil {
+		t.Error("Delete() of a missing item should fail")
+	}
+	if list := openItems(path).List(); len(list) != 1 {
+		t.Errorf("List() after Delete() = %+v", list)
+	}
+
+	// A corrupt file starts empty
+	if err := os.WriteFile(path, []byte("["), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if list := openItems(path).List(); len(list) != 0 {
+		t.Errorf("List() of a corrupt file = %+v", list)
+	}
+}
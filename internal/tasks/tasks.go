@@ -0,0 +1,226 @@
+// Package tasks runs long operations (pulls, copies, captures, batch actions)
+// in the background and tracks their state for the Tasks panel.
+package tasks
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// State is the lifecycle stage of a task
+type State int
+
+const (
+	Pending State = iota
+	Running
+	Done
+	Failed
+	Cancelled
+)
+
+// String returns the lowercase name of the state
+func (s State) String() string {
+	switch s {
+	case Pending:
+		return "pending"
+	case Running:
+		return "running"
+	case Done:
+		return "done"
+	case Failed:
+		return "failed"
+	case Cancelled:
+		return "cancelled"
+	}
+	return "unknown"
+}
+
+// Finished reports whether the task has stopped for good
+func (s State) Finished() bool {
+	return s == Done || s == Failed || s == Cancelled
+}
+
+// Func is the work of a task. It reports progress through progress, should
+// stop when ctx is cancelled, and returns a short result message.
+type Func func(ctx context.Context, progress func(string)) (string, error)
+
+// Task is a snapshot of a queued operation
+type Task struct {
+	ID       int
+	Name     string
+	State    State
+	Progress string // Latest progress report while running
+	Result   string // Result message once done
+	Err      error  // Failure once failed
+	Started  time.Time
+	Finished time.Time
+}
+
+// entry is a task with what the queue needs to run and cancel it
+type entry struct {
+	Task
+	fn     Func
+	cancel context.CancelFunc
+}
+
+// Queue runs tasks in the order they were added, a limited number at a time
+type Queue struct {
+	mu      sync.Mutex
+	entries []*entry
+	workers int
+	running int
+	nextID  int
+	changed chan struct{}
+}
+
+// NewQueue creates a queue that runs up to workers tasks at once
+func NewQueue(workers int) *Queue {
+	return &Queue{
+		workers: max(workers, 1),
+		changed: make(chan struct{}, 1),
+	}
+}
+
+// Changed delivers a signal after tasks were added or changed state or
+// progress. Signals are coalesced, so a receiver should read Tasks afresh.
+func (q *Queue) Changed() <-chan struct{} {
+	return q.changed
+}
+
+// Add queues a task and returns its ID
+func (q *Queue) Add(name string, fn Func) int {
+	q.mu.Lock()
+	q.nextID++
+	id := q.nextID
+	q.entries = append(q.entries, &entry{Task: Task{ID: id, Name: name, State: Pending}, fn: fn})
+	q.startPending()
+	q.mu.Unlock()
+
+	q.notify()
+	return id
+}
+
+// Cancel stops a pending or running task. It reports false when the task
+// doesn't exist or has already finished.
+func (q *Queue) Cancel(id int) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, e := range q.entries {
+		if e.ID != id {
+			continue
+		}
+		switch e.State {
+		case Pending:
+			e.State = Cancelled
+			e.Finished = time.Now()
+			q.notify()
+			return true
+		case Running:
+			// The task finishes as cancelled once its Func returns
+			e.cancel()
+			return true
+		}
+		return false
+	}
+	return false
+}
+
+// Tasks returns a snapshot of all tasks, oldest first
+func (q *Queue) Tasks() []Task {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	tasks := make([]Task, len(q.entries))
+	for i, e := range q.entries {
+		tasks[i] = e.Task
+	}
+	return tasks
+}
+
+// Active returns the number of pending and running tasks
+func (q *Queue) Active() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	active := 0
+	for _, e := range q.entries {
+		if !e.State.Finished() {
+			active++
+		}
+	}
+	return active
+}
+
+// ClearFinished forgets every finished task
+func (q *Queue) ClearFinished() {
+	q.mu.Lock()
+	kept := q.entries[:0]
+	for _, e := range q.entries {
+		if !e.State.Finished() {
+			kept = append(kept, e)
+		}
+	}
+	q.entries = kept
+	q.mu.Unlock()
+
+	q.notify()
+}
+
+// startPending starts pending tasks while workers are free; q.mu is held
+func (q *Queue) startPending() {
+	for _, e := range q.entries {
+		if q.running >= q.workers {
+			return
+		}
+		if e.State != Pending {
+			continue
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		e.State = Running
+		e.Started = time.Now()
+		e.cancel = cancel
+		q.running++
+		go q.run(ctx, e)
+	}
+}
+
+// run executes a task and records its outcome
+func (q *Queue) run(ctx context.Context, e *entry) {
+	result, err := e.fn(ctx, func(progress string) {
+		q.mu.Lock()
+		e.Progress = progress
+		q.mu.Unlock()
+		q.notify()
+	})
+
+	q.mu.Lock()
+	e.Finished = time.Now()
+	switch {
+	case ctx.Err() != nil || errors.Is(err, context.Canceled):
+		e.State = Cancelled
+	case err != nil:
+		e.State = Failed
+		e.Err = err
+	default:
+		e.State = Done
+		e.Result = result
+	}
+	e.cancel() // Release the context
+	q.running--
+	q.startPending()
+	q.mu.Unlock()
+
+	q.notify()
+}
+
+// notify signals a change without blocking; a pending signal covers it
+func (q *Queue) notify() {
+	select {
+	case q.changed <- struct{}{}:
+	default:
+	}
+}
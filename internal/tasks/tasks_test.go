@@ -0,0 +1,84 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// waitFor polls the queue until cond holds for the task snapshot
+func waitFor(t *testing.T, q *Queue, cond func([]Task) bool) []Task {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if tasks := q.Tasks(); cond(tasks) {
+			return tasks
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("condition not met, tasks: %+v", q.Tasks())
+	return nil
+}
+
+func TestQueueRunsInOrder(t *testing.T) {
+	q := NewQueue(1)
+	release := make(chan struct{})
+
+	q.Add("first", func(ctx context.Context, progress func(string)) (string, error) {
+		progress("halfway")
+		<-release
+		return "ok", nil
+	})
+	q.Add("second", func(ctx context.Context, progress func(string)) (string, error) {
+		return "", errors.New("boom")
+	})
+
+	tasks := waitFor(t, q, func(tasks []Task) bool { return tasks[0].Progress == "halfway" })
+	if tasks[0].State != Running || tasks[1].State != Pending {
+		t.Fatalf("with one worker: states = %v, %v, want running, pending", tasks[0].State, tasks[1].State)
+	}
+	if q.Active() != 2 {
+		t.Errorf("Active() = %d, want 2", q.Active())
+	}
+
+	close(release)
+	tasks = waitFor(t, q, func(tasks []Task) bool { return tasks[1].State.Finished() })
+	if tasks[0].State != Done || tasks[0].Result != "ok" {
+		t.Errorf("first = %+v, want done with result", tasks[0])
+	}
+	if tasks[1].State != Failed || tasks[1].Err == nil {
+		t.Errorf("second = %+v, want failed with error", tasks[1])
+	}
+
+	q.ClearFinished()
+	if n := len(q.Tasks()); n != 0 {
+		t.Errorf("after ClearFinished: %d tasks left", n)
+	}
+}
+
+func TestQueueCancel(t *testing.T) {
+	q := NewQueue(1)
+
+	running := q.Add("running", func(ctx context.Context, progress func(string)) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+	pending := q.Add("pending", func(ctx context.Context, progress func(string)) (string, error) {
+		t.Error("cancelled pending task ran")
+		return "", nil
+	})
+
+	waitFor(t, q, func(tasks []Task) bool { return tasks[0].State == Running })
+	if !q.Cancel(pending) || !q.Cancel(running) {
+		t.Fatal("Cancel() = false for an unfinished task")
+	}
+
+	tasks := waitFor(t, q, func(tasks []Task) bool { return tasks[0].State.Finished() })
+	if tasks[0].State != Cancelled || tasks[1].State != Cancelled {
+		t.Errorf("states = %v, %v, want both cancelled", tasks[0].State, tasks[1].State)
+	}
+	if q.Cancel(running) {
+		t.Error("Cancel() = true for a finished task")
+	}
+}
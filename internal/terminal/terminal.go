@@ -0,0 +1,99 @@
+// Package terminal opens commands in a new terminal window or tab, so that
+// shells can run next to tinyd instead of replacing it.
+package terminal
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// EnvVar selects the external terminal, e.g. "iterm", "gnome-terminal:Dark"
+// or a custom command containing {}. Empty keeps consoles inside tinyd.
+const EnvVar = "TINYD_TERMINAL"
+
+// Command returns the program and arguments that open argv in a new window or
+// tab of the terminal named by setting. setting is "<terminal>[:<profile>]"
+// where terminal is one of iterm, terminal (macOS Terminal.app), wt (Windows
+// Terminal) or gnome-terminal, or any command line in which {} is replaced
+// with argv, e.g. "kitty @ launch --type=tab {}".
+func Command(setting string, argv []string) (string, []string, error) {
+	name, profile, _ := strings.Cut(setting, ":")
+
+	switch name {
+	case "iterm":
+		if profile == "" {
+			profile = "default profile"
+		} else {
+			profile = "profile " + appleScriptString(profile)
+		}
+		script := fmt.Sprintf(`tell application "iTerm" to create window with %s command %s`, profile, appleScriptString(shellJoin(argv)))
+		return "osascript", []string{"-e", script}, nil
+
+	case "terminal":
+		script := fmt.Sprintf(`tell application "Terminal" to do script %s`, appleScriptString(shellJoin(argv)))
+		return "osascript", []string{"-e", script, "-e", `tell application "Terminal" to activate`}, nil
+
+	case "wt":
+		args := []string{"new-tab"}
+		if profile != "" {
+			args = append(args, "--profile", profile)
+		}
+		return "wt.exe", append(args, argv...), nil
+
+	case "gnome-terminal":
+		args := []string{"--tab"}
+		if profile != "" {
+			args = append(args, "--profile="+profile)
+		}
+		return "gnome-terminal", append(append(args, "--"), argv...), nil
+	}
+
+	// Custom command line; only spaces separate its words
+	if !strings.Contains(setting, "{}") {
+		return "", nil, fmt.Errorf("unknown terminal %q (use iterm, terminal, wt, gnome-terminal or a command with {})", setting)
+	}
+	var args []string
+	for _, word := range strings.Fields(setting) {
+		if word == "{}" {
+			args = append(args, argv...)
+		} else {
+			args = append(args, word)
+		}
+	}
+	return args[0], args[1:], nil
+}
+
+// Open starts argv in a new terminal window or tab without waiting for it
+func Open(setting string, argv []string) error {
+	name, args, err := Command(setting, argv)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(name, args...)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to open terminal: %w", err)
+	}
+	// The terminal outlives this call; reap it in the background
+	go cmd.Wait()
+	return nil
+}
+
+// shellJoin quotes argv for a POSIX shell
+func shellJoin(argv []string) string {
+	quoted := make([]string, len(argv))
+	for i, arg := range argv {
+		if arg != "" && strings.Trim(arg, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./=:@") == "" {
+			quoted[i] = arg
+		} else {
+			quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+	}
+	return strings.Join(quoted, " ")
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,51 @@
+package terminal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	argv := []string{"docker", "exec", "-it", "abc123", "sh", "-c", "exec bash || exec sh"}
+
+	tests := []struct {
+		setting  string
+		wantName string
+		wantArgs []string
+	}{
+		{
+			"gnome-terminal:Dark", "gnome-terminal",
+			append([]string{"--tab", "--profile=Dark", "--"}, argv...),
+		},
+		{
+			"wt", "wt.exe",
+			append([]string{"new-tab"}, argv...),
+		},
+		{
+			"iterm", "osascript",
+			[]string{"-e", `tell application "iTerm" to create window with default profile command "docker exec -it abc123 sh -c 'exec bash || exec sh'"`},
+		},
+		{
+			"kitty @ launch --type=tab {}", "kitty",
+			append([]string{"@", "launch", "--type=tab"}, argv...),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.setting, func(t *testing.T) {
+			name, args, err := Command(tt.setting, argv)
+			if err != nil {
+				t.Fatalf("Command() error = %v", err)
+			}
+			if name != tt.wantName || !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("Command() = %s %q, want %s %q", name, args, tt.wantName, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestCommandUnknown(t *testing.T) {
+	if _, _, err := Command("konsole", []string{"sh"}); err == nil {
+		t.Error("Command(unknown terminal) expected error")
+	}
+}
@@ -0,0 +1,150 @@
+// Package theme maps tinyd's truecolor palette to the colors the terminal
+// can show, so the UI stays legible on 256- and 16-color terminals.
+package theme
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// EnvVar forces a color depth: "truecolor", "256" or "16"
+const EnvVar = "TINYD_COLORS"
+
+// NoColorEnvVar turns colors off when set to any non-empty value, following
+// https://no-color.org; bold and underline are kept
+const NoColorEnvVar = "NO_COLOR"
+
+// noColor is read when the package loads, before the package-level styles of
+// the UI are built from Color
+var noColor = os.Getenv(NoColorEnvVar) != ""
+
+// plain is set by Init when all styling is off
+var plain bool
+
+// fallback holds hand-picked 256-color and 16-color equivalents of the
+// palette. Automatic nearest-color matching turns the dim grays into black
+// or white on 16-color terminals, which loses the dim/bright distinction.
+var fallback = map[string][2]string{
+	"#0A0A0A": {"233", "0"},  // Background
+	"#000000": {"16", "0"},   // Black text on highlights
+	"#FFFFFF": {"231", "15"}, // Primary text
+	"#CCCCCC": {"252", "7"},
+	"#ABB2BF": {"249", "7"},
+	"#999999": {"246", "7"}, // Secondary text
+	"#666666": {"241", "8"}, // Dim text
+	"#5C6370": {"241", "8"},
+	"#555555": {"240", "8"},
+	"#444444": {"238", "8"},
+	"#00FF00": {"46", "10"}, // Running, active input
+	"#98C379": {"114", "2"},
+	"#FF0000": {"196", "9"}, // Errors
+	"#00FFFF": {"51", "14"}, // Notices
+	"#87CEEB": {"117", "6"},
+	"#FFFF00": {"226", "11"}, // Warnings
+	"#FFAA00": {"214", "3"},
+	"#E5C07B": {"180", "3"},
+	"#D19A66": {"173", "3"},
+}
+
+// Init detects the terminal's color depth and applies it to lipgloss. Plain
+// output (--plain) drops all styling instead: no colors, no backgrounds and
+// no bold or underline, e.g. for recordings and documentation screenshots.
+func Init(plainOutput bool) {
+	plain = plainOutput
+	if plain {
+		lipgloss.SetColorProfile(termenv.Ascii)
+		return
+	}
+	lipgloss.SetColorProfile(Terminal())
+}
+
+// Terminal returns the color depth Init picks for the current terminal
+func Terminal() termenv.Profile {
+	return Detect(os.Getenv, terminfoColors)
+}
+
+// Plain reports whether all styling is off, so that anything normally shown
+// by underline or bold (e.g. shortcut keys) needs a textual marker
+func Plain() bool {
+	return plain || lipgloss.ColorProfile() == termenv.Ascii
+}
+
+// Monochrome reports whether the UI is drawn without colors, so that states
+// normally told apart by color (e.g. the selected row) need a textual marker
+func Monochrome() bool {
+	return noColor || Plain()
+}
+
+// Color returns a palette color ("#rrggbb") that renders as its hand-picked
+// equivalent on 256- and 16-color terminals. The choice is made at render
+// time, so package-level styles pick up the profile set by Init. Colors
+// outside the palette are left to lipgloss to approximate. With NO_COLOR
+// set, every color is left unset.
+func Color(hex string) lipgloss.TerminalColor {
+	if noColor {
+		return lipgloss.NoColor{}
+	}
+	alt, ok := fallback[strings.ToUpper(hex)]
+	if !ok {
+		return lipgloss.Color(hex)
+	}
+	return lipgloss.CompleteColor{TrueColor: hex, ANSI256: alt[0], ANSI: alt[1]}
+}
+
+// Detect works out the color depth from TINYD_COLORS, COLORTERM, TERM and,
+// when those are inconclusive, the number of colors terminfo reports
+func Detect(getenv func(string) string, terminfoColors func() int) termenv.Profile {
+	switch strings.ToLower(getenv(EnvVar)) {
+	case "truecolor", "24bit":
+		return termenv.TrueColor
+	case "256":
+		return termenv.ANSI256
+	case "16", "8":
+		return termenv.ANSI
+	}
+
+	switch strings.ToLower(getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return termenv.TrueColor
+	}
+
+	term := getenv("TERM")
+	switch {
+	case term == "":
+		// No TERM usually means Windows, whose terminals do truecolor
+		return termenv.TrueColor
+	case term == "dumb":
+		return termenv.Ascii
+	case strings.HasSuffix(term, "-direct") || strings.Contains(term, "truecolor"):
+		return termenv.TrueColor
+	case strings.Contains(term, "256color"):
+		return termenv.ANSI256
+	}
+
+	switch colors := terminfoColors(); {
+	case colors >= 1<<24:
+		return termenv.TrueColor
+	case colors >= 256:
+		return termenv.ANSI256
+	case colors > 0:
+		return termenv.ANSI
+	}
+	// Plain TERM values like "xterm" or "screen" without terminfo
+	return termenv.ANSI
+}
+
+// terminfoColors asks terminfo (through tput) how many colors the terminal
+// supports, 0 when unknown
+func terminfoColors() int {
+	out, err := exec.Command("tput", "colors").Output()
+	if err != nil {
+		return 0
+	}
+	colors, _ := strconv.Atoi(strings.TrimSpace(string(out)))
+	return colors
+}
@@ -0,0 +1,68 @@
+package theme
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		terminfo int
+		want     termenv.Profile
+	}{
+		{"override", map[string]string{EnvVar: "16", "COLORTERM": "truecolor"}, 0, termenv.ANSI},
+		{"colorterm", map[string]string{"COLORTERM": "truecolor", "TERM": "xterm"}, 8, termenv.TrueColor},
+		{"256color term", map[string]string{"TERM": "xterm-256color"}, 0, termenv.ANSI256},
+		{"dumb", map[string]string{"TERM": "dumb"}, 0, termenv.Ascii},
+		{"terminfo", map[string]string{"TERM": "screen"}, 256, termenv.ANSI256},
+		{"plain term", map[string]string{"TERM": "linux"}, 8, termenv.ANSI},
+		{"no terminfo", map[string]string{"TERM": "vt220"}, 0, termenv.ANSI},
+		{"windows", map[string]string{}, 0, termenv.TrueColor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			terminfo := func() int { return tt.terminfo }
+			if got := Detect(getenv, terminfo); got != tt.want {
+				t.Errorf("Detect() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColor(t *testing.T) {
+	defer func(saved bool) { noColor = saved }(noColor)
+	noColor = false
+
+	got := Color("#999999")
+	want := lipgloss.CompleteColor{TrueColor: "#999999", ANSI256: "246", ANSI: "7"}
+	if got != want {
+		t.Errorf("Color(#999999) = %v, want %v", got, want)
+	}
+
+	// Lowercase input matches the palette too
+	if _, ok := Color("#ffffff").(lipgloss.CompleteColor); !ok {
+		t.Errorf("Color(#ffffff) is not mapped to the palette")
+	}
+
+	if got := Color("#123456"); got != lipgloss.Color("#123456") {
+		t.Errorf("Color(#123456) = %v, want it passed through", got)
+	}
+}
+
+func TestColorNoColor(t *testing.T) {
+	defer func(saved bool) { noColor = saved }(noColor)
+	noColor = true
+
+	if got := Color("#999999"); got != (lipgloss.NoColor{}) {
+		t.Errorf("Color(#999999) with NO_COLOR = %v, want no color", got)
+	}
+	if !Monochrome() {
+		t.Errorf("Monochrome() = false with NO_COLOR")
+	}
+}
@@ -12,6 +12,47 @@ type Container struct {
 	Mem    string
 	Image  string
 	Ports  string
+
+	// Image reference the container was created from, not shortened
+	ImageRef string
+
+	// Raw usage behind CPU and Mem, for sorting (zero when unknown)
+	CPUPercent float64
+	MemBytes   uint64
+
+	// Network and block I/O rates as "in/out" per second, "" when unknown
+	NetIO   string
+	BlockIO string
+
+	// Compose service the container belongs to (empty when not from compose)
+	ComposeProject string
+	ComposeService string
+	ComposeNumber  int
+
+	// Other replicas of the service, when folded into this row
+	Replicas []Container
+
+	// Named volumes and bind-mounted host paths, and networks the
+	// container is attached to
+	Volumes  []string
+	Networks []string
+
+	// Risk badges, e.g. "PRIV" for privileged mode (see docker.Risk*)
+	Risks []string
+
+	// Healthcheck status: "healthy", "unhealthy", "starting", or empty
+	// without a healthcheck
+	Health string
+
+	// How long a running or paused container has been up, e.g. "5m"
+	Uptime string
+	// Exit code of an exited container, -1 otherwise
+	ExitCode int
+	// Times the daemon restarted the container under its restart policy
+	RestartCount int
+
+	// Labels set on the container, including compose's
+	Labels map[string]string
 }
 
 // Image represents a Docker image
@@ -21,8 +62,19 @@ type Image struct {
 	Tag        string
 	Size       string
 	Created    string
-	InUse      bool // Whether the image is used by any container
-	Dangling   bool // Whether the image has <none> tag/repo
+	CreatedAt  time.Time
+	InUse      bool   // Whether the image is used by any container
+	Dangling   bool   // Whether the image has <none> tag/repo
+	Digest     string // Registry digest, "sha256:..."; empty for images never pulled or pushed
+	SizeBytes  int64  // Raw size behind Size
+	RepoTag    string // First "repo:tag" in full; empty for untagged images
+
+	// Build provenance from the standard OCI labels (empty when unset)
+	Source       string // Source repository URL
+	Revision     string // Commit the image was built from
+	BuildCreated string // Build date, RFC 3339
+
+	Labels map[string]string
 }
 
 // Volume represents a Docker volume
@@ -34,6 +86,7 @@ type Volume struct {
 	Created    string
 	InUse      bool   // Whether the volume is mounted to any container
 	Containers string // Comma-separated list of container names using this volume
+	Labels     map[string]string
 }
 
 // Network represents a Docker network
@@ -45,6 +98,16 @@ type Network struct {
 	IPv4   string
 	IPv6   string
 	InUse  bool // Whether the network has any connected containers
+	Labels map[string]string
+}
+
+// NetworkSpec describes a network to create
+type NetworkSpec struct {
+	Name      string
+	Driver    string // "bridge" when empty
+	MTU       int    // 0 keeps the daemon default
+	Parent    string // Host interface of a macvlan or ipvlan network
+	Encrypted bool   // Encrypt overlay traffic between nodes
 }
 
 // PortMapping for run modal
@@ -59,6 +122,10 @@ type VolumeMapping struct {
 	Container  string
 	IsNamed    bool
 	VolumeName string
+	ReadOnly   bool
+	// Consistency is "cached" or "delegated" to relax file sharing on
+	// Docker Desktop for macOS, empty for the default; other hosts ignore it
+	Consistency string
 }
 
 // EnvVar for run modal
@@ -67,6 +134,179 @@ type EnvVar struct {
 	Value string
 }
 
+// Label for run modal
+type Label struct {
+	Key   string
+	Value string
+}
+
+// HostInfo describes the machine the daemon runs on
+type HostInfo struct {
+	Desktop  bool   // Docker Desktop, which runs the daemon in a VM
+	Rootless bool   // The daemon runs as an unprivileged user
+	NCPU     int    // CPUs available to the daemon
+	MemTotal int64  // Memory available to the daemon, in bytes
+	Platform string // Platform of the daemon's host, e.g. "linux/amd64"
+}
+
+// HostLoad is the current load of the daemon's host, known when the daemon
+// runs on this machine
+type HostLoad struct {
+	Load1, Load5, Load15 float64 // Load averages over 1, 5 and 15 minutes
+	MemUsed              int64   // Memory in use, page cache excluded, in bytes
+}
+
+// ClockInfo compares a container's clock and timezone to the host's
+type ClockInfo struct {
+	Drift  time.Duration // Container clock minus host clock
+	Zone   string        // Zone abbreviation inside the container, e.g. "UTC"
+	Offset string        // UTC offset inside the container, e.g. "+0000"
+	TZ     string        // $TZ inside the container, empty if unset
+}
+
+// ImageUse is an image repository containers were created from
+type ImageUse struct {
+	Repository string
+	Tags       []string // Tags in use, sorted
+	Containers int
+}
+
+// Relation is a container sharing volumes or networks with another one,
+// i.e. affected when that one is stopped or removed
+type Relation struct {
+	Container Container
+	Volumes   []string // Shared volumes and host paths
+	Networks  []string // Shared networks
+}
+
+// TagCleanup is what keeping only the newest tags of a repository deletes
+type TagCleanup struct {
+	Repository string
+	Keep       []Image // Newest first
+	Delete     []Image
+	InUse      []Image // Past the newest but used by containers, so kept
+}
+
+// DigestPin is the result of pinning an image tag to its digest
+type DigestPin struct {
+	Ref    string // Digest reference, e.g. "nginx@sha256:..."
+	PinTag string // Local tag keeping the image, e.g. "nginx:pin-0123456789ab"
+	Remote string // Digest the tag has in the registry now, empty if unknown
+}
+
+// DNSCheck is the outcome of a name lookup, and optionally a TCP connect,
+// run from inside a container
+type DNSCheck struct {
+	Host        string
+	Port        int           // 0 when no connect was tried
+	Tool        string        // Lookup tool used in the container, e.g. "getent"
+	Nameservers []string      // From the container's /etc/resolv.conf
+	Addresses   []string      // Empty when the name did not resolve
+	Resolve     time.Duration // Lookup time, without the exec overhead
+	Connected   bool
+	Connect     time.Duration // Connect time, without the exec overhead
+	ConnectErr  string        // Why the connect failed, if it did
+}
+
+// VolumeCopyPlan describes what copying one volume into another would do
+type VolumeCopyPlan struct {
+	Source       string
+	Target       string
+	Size         int64 // Bytes to copy
+	Files        int   // Entries in the source
+	TargetExists bool
+	TargetFiles  int // Entries already in the target
+}
+
+// ContainerStats holds the latest live resource usage of a container
+type ContainerStats struct {
+	CPU        string
+	Mem        string
+	CPUPercent float64
+	MemBytes   uint64
+	MemLimit   uint64 // Memory limit, or the host's memory when unlimited
+	NetRx      uint64 // Bytes received since the container started
+	NetTx      uint64 // Bytes sent since the container started
+	BlockRead  uint64 // Bytes read from block devices since the container started
+	BlockWrite uint64 // Bytes written to block devices since the container started
+
+	// Network receive/transmit and block read/write rates in bytes per
+	// second since the previous sample, and formatted as "1.2M/34k"; "--"
+	// until a previous sample is there to diff against
+	NetRxRate, NetTxRate          float64
+	BlockReadRate, BlockWriteRate float64
+	NetIO, BlockIO                string
+}
+
+// LogSearchResult holds the log lines of one container matching a search
+type LogSearchResult struct {
+	ContainerID   string
+	ContainerName string
+	Count         int      // Matching lines in the searched tail
+	Matches       []string // The most recent matching lines
+	Err           error
+}
+
+// BindMount is a host path bind-mounted into a container
+type BindMount struct {
+	Source      string
+	Destination string
+}
+
+// ImageLayer is one filesystem layer of an image and the files it touches
+type ImageLayer struct {
+	Digest    string
+	CreatedBy string // Dockerfile step that produced the layer, if known
+	Size      int64  // Total size of the files in the layer
+	Files     []LayerFile
+}
+
+// LayerFile is a file added, modified or deleted by a layer
+type LayerFile struct {
+	Path   string
+	Size   int64
+	Change string // "A" added, "M" modified, "D" deleted
+}
+
+// ImageWaste reports the space in an image that a rebuild could save
+type ImageWaste struct {
+	Total      int64   // Size of all layers
+	Wasted     int64   // Sum of the items below
+	Efficiency float64 // Share of Total that is not wasted, 0 to 1
+	Items      []WasteItem
+}
+
+// WasteItem is a file or directory taking space in the image for nothing
+type WasteItem struct {
+	Path   string
+	Size   int64
+	Files  int
+	Reason string // e.g. "overwritten in layer 4", "package cache"
+}
+
+// EnvPreview lists the env changes a bulk edit would make to one container
+type EnvPreview struct {
+	ContainerID   string
+	ContainerName string
+	Changes       []string // "+ KEY" added, "~ KEY" changed
+	Err           error
+}
+
+// StartFailure explains why the daemon refused to start a container
+type StartFailure struct {
+	Cause    string // What went wrong, e.g. "Port 8080 is already allocated"
+	Port     string // Host port in conflict, "" for other causes
+	Holder   string // Name of the container publishing Port, "" if none
+	HolderID string
+	Fixes    []string // Suggestions, most likely first
+}
+
+// LogsMsg carries fetched logs, with whether each line came from stderr
+type LogsMsg struct {
+	Logs   string
+	Stderr []bool // Per line of Logs; missing entries are stdout
+}
+
 // Message types for Bubble Tea
 type ContainerListMsg []Container
 type ImageListMsg []Image
@@ -74,11 +314,296 @@ type VolumeListMsg []Volume
 type NetworkListMsg []Network
 type ErrMsg error
 type TickMsg time.Time
+type StatsTickMsg time.Time
+type StatsMsg map[string]ContainerStats
 type AnimationTickMsg time.Time
-type ActionSuccessMsg string
 type ActionErrorMsg string
-type LogsMsg string
 type InspectMsg string
+type LastLogLinesMsg map[string]string
+type BulkEnvPreviewMsg []EnvPreview
+type WatchTickMsg time.Time
+type PullProgressMsg string
+type ImageTagTimesMsg map[string]time.Time
+type TasksChangedMsg struct{}
+type RecordTickMsg time.Time
+type ScheduleTickMsg time.Time
+type HostInfoMsg HostInfo
+type HostLoadMsg HostLoad
+
+// ForwardStartedMsg reports a port forward that started, or why it didn't
+type ForwardStartedMsg struct {
+	Text string // e.g. "127.0.0.1:8080 → web:80"
+	Err  error
+}
+
+// PruneReport is what pruning removed
+type PruneReport struct {
+	Deleted        int
+	SpaceReclaimed uint64
+}
+
+// HookOutputMsg reports a hook that ran around a container start or stop
+type HookOutputMsg struct {
+	Event     string // e.g. "pre-stop"
+	Container string
+	Output    string
+	Err       error
+}
+
+// ImagePlatformsMsg carries the platform of images by ID, e.g. "linux/arm64"
+type ImagePlatformsMsg map[string]string
+
+// ImageLayersMsg carries the layer contents of an inspected image
+type ImageLayersMsg struct {
+	Layers []ImageLayer
+	Err    error
+}
+
+// VolumeCopyPlanMsg carries the dry-run estimate of a volume copy
+type VolumeCopyPlanMsg struct {
+	Plan VolumeCopyPlan
+	Err  error
+}
+
+// LogSearchMsg carries the results of a search across container logs
+type LogSearchMsg struct {
+	Query   string
+	Results []LogSearchResult
+}
+
+// DebugCopyExitedMsg reports that the shell in a debug copy exited, so the
+// copy can be removed
+type DebugCopyExitedMsg struct {
+	CopyID    string
+	Container string // Name of the container the copy reproduces
+	Err       error
+}
+
+// ContainerPausedMsg reports a container paused or, without Paused,
+// unpaused
+type ContainerPausedMsg struct {
+	ContainerID string
+	Name        string
+	Paused      bool
+	Err         error
+}
+
+// StartFailedMsg reports a container that failed to start for a known
+// cause, with the daemon's error
+type StartFailedMsg struct {
+	Container string
+	Err       string
+	Failure   StartFailure
+}
+
+// ImageInUseMsg reports that an image couldn't be deleted because stopped
+// containers were created from it
+type ImageInUseMsg struct {
+	ImageID    string
+	ImageName  string
+	Containers []Container
+}
+
+// LogLineMsg carries a line of followed logs; Follow tells follow sessions
+// apart so lines of a stopped one are dropped
+type LogLineMsg struct {
+	Follow int
+	Line   string
+}
+
+// Process is a process running in a container, as ps reports it
+type Process struct {
+	PID     string
+	User    string
+	CPU     string // Percent, or ps's C column with Docker's default arguments
+	Mem     string // Percent; empty with Docker's default arguments
+	Command string
+}
+
+// Refresh is a set of lists an action changed, to be fetched again
+type Refresh int
+
+const (
+	RefreshContainers Refresh = 1 << iota
+	RefreshImages
+	RefreshVolumes
+	RefreshNetworks
+)
+
+// ActionSuccessMsg reports a finished action and the lists it changed
+type ActionSuccessMsg struct {
+	Text    string
+	Refresh Refresh
+}
+
+// RefreshDueMsg ends the debounce window of the refreshes asked for by
+// finished actions
+type RefreshDueMsg struct{}
+
+// HealthProbe is a run of a container's healthcheck
+type HealthProbe struct {
+	Start    time.Time
+	Duration time.Duration
+	ExitCode int // 0 healthy, 1 unhealthy, else the probe failed to run
+	Output   string
+}
+
+// HealthLog is a container's healthcheck and its last probes, newest first
+type HealthLog struct {
+	Status        string // As in Container.Health
+	FailingStreak int
+	Test          string // Command of the check
+	Interval      time.Duration
+	Probes        []HealthProbe
+}
+
+// BindMountsMsg carries the bind mounts of a container for the watch setup
+type BindMountsMsg struct {
+	ContainerID string
+	Mounts      []BindMount
+	Err         error
+}
+
+// ContainerEnvMsg carries the environment of a container for the env view
+type ContainerEnvMsg struct {
+	ContainerID string
+	Env         []EnvVar
+	Err         error
+}
+
+// AllocationsMsg carries the resource allocation of the running containers,
+// by ID, for the provisioning report
+type AllocationsMsg struct {
+	Allocations map[string]ResourceAllocation
+	Names       map[string]string
+	Err         error
+}
+
+// HealthLogMsg carries the healthcheck log of a container for the health view
+type HealthLogMsg struct {
+	ContainerID string
+	Log         HealthLog
+	Err         error
+}
+
+// ResourceLimits are the settings of a container `docker update` changes,
+// as text the way they are shown and entered. Empty means no limit.
+type ResourceLimits struct {
+	CPUShares string // Relative CPU weight, 1024 by default
+	CPUs      string // CPU quota in CPUs, e.g. 1.5
+	Memory    string // e.g. 512MiB
+	Restart   string // no, always, unless-stopped or on-failure[:N]
+}
+
+// ResourceAllocation is what a container is given, zero where unset
+type ResourceAllocation struct {
+	CPUs              float64 // CPU limit in CPUs
+	MemoryLimit       int64
+	MemoryReservation int64 // Soft limit the kernel reclaims down to under pressure
+}
+
+// ResourceLimitsMsg carries the current limits of a container for the
+// update prompt
+type ResourceLimitsMsg struct {
+	ContainerID string
+	Limits      ResourceLimits
+	Err         error
+}
+
+// ProcessesMsg carries the processes of a container for the processes view
+type ProcessesMsg struct {
+	ContainerID string
+	Processes   []Process
+	Err         error
+}
+
+// ProcessesTickMsg asks for a refresh of the processes view
+type ProcessesTickMsg struct {
+	ContainerID string
+}
+
+// StatsDashboardTickMsg samples the stats of the container on the stats
+// dashboard
+type StatsDashboardTickMsg struct {
+	ContainerID string
+	Time        time.Time
+}
+
+// AggregatedLogLine is a log line of one of several containers whose logs
+// are shown together; Source is the container's index among them
+type AggregatedLogLine struct {
+	Source int
+	Line   string
+}
+
+// ExecResultMsg carries the output of a command run in a container
+type ExecResultMsg struct {
+	ContainerID string
+	Command     string
+	Output      string
+	ExitCode    int
+	Err         error
+}
+
+// DockerEvent is an event of the daemon, as `docker events` prints it
+type DockerEvent struct {
+	Time   time.Time
+	Type   string // container, image, network, volume, ...
+	Action string // start, die, pull, ...
+	Actor  string // Name of the object, or its short ID
+	Attrs  string // Other attributes, "key=value" sorted by key
+}
+
+// EventMsg carries an event of the events view's stream
+type EventMsg struct {
+	Follow int
+	Event  DockerEvent
+}
+
+// EventsEndMsg reports that the events view's stream ended
+type EventsEndMsg struct {
+	Follow int
+	Err    error
+}
+
+// AggregatedLogsMsg carries the merged tail of the aggregated logs view
+type AggregatedLogsMsg struct {
+	Follow int
+	Lines  []AggregatedLogLine
+	Err    error
+}
+
+// AggregatedLogLineMsg carries a followed line of the aggregated logs view
+type AggregatedLogLineMsg struct {
+	Follow int
+	Line   AggregatedLogLine
+}
+
+// AggregatedLogsEndMsg reports that every follow of the aggregated logs
+// view ended
+type AggregatedLogsEndMsg struct {
+	Follow int
+	Err    error
+}
+
+// LogFollowEndMsg reports that a follow session ended
+type LogFollowEndMsg struct {
+	Follow int
+	Err    error
+}
+
+// UpdateAvailableMsg reports a newer tinyd release
+type UpdateAvailableMsg struct {
+	Version string
+	URL     string
+}
+
+// WatchResultMsg reports the current fingerprint of a watched container's paths
+type WatchResultMsg struct {
+	ContainerID string
+	Fingerprint uint64
+	Err         error
+}
 
 // ViewMode represents different UI views
 type ViewMode int
@@ -92,6 +617,34 @@ const (
 	ViewModeFilter
 	ViewModeRunImage
 	ViewModePullImage
+	ViewModeWatch
+	ViewModeLayers
+	ViewModeBulkEnv
+	ViewModeTasks
+	ViewModeLogSearch
+	ViewModeSchedules
+	ViewModeMessages
+	ViewModeRelated
+	ViewModeStartFailure
+	ViewModeTagCleanup
+	ViewModeAggregatedLogs
+	ViewModeProcesses
+	ViewModeWorkspaces
+	ViewModeEvents
+	ViewModeExecOutput
+	ViewModeHealth
+	ViewModeForwards
+	ViewModeEnv
+	ViewModeProvision
+	ViewModeStats
+	ViewModeLabels
+)
+
+// Container sort constants
+const (
+	ContainerSortStatus = iota // Default: RUNNING > PAUSED > ERROR > STOPPED
+	ContainerSortCPU           // CPU usage, descending
+	ContainerSortMem           // Memory usage, descending
 )
 
 // Container filter constants
@@ -106,6 +659,7 @@ const (
 	ImageFilterInUse
 	ImageFilterUnused
 	ImageFilterDangling
+	ImageFilterStale // First "unused for N days" preset; later presets follow
 )
 
 // Volume filter constants
@@ -1,21 +1,101 @@
 package ui
 
 import (
+	"context"
+	"fmt"
+	"net"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/docker/go-units"
+	"tinyd/internal/bell"
 	"tinyd/internal/docker"
+	"tinyd/internal/hooks"
+	"tinyd/internal/schedule"
+	"tinyd/internal/tasks"
+	"tinyd/internal/terminal"
 	"tinyd/internal/types"
+	"tinyd/internal/version"
+	"tinyd/internal/watcher"
 )
 
 // tickCmd creates a periodic tick for auto-refresh
-func tickCmd() tea.Cmd {
-	return tea.Tick(5*time.Second, func(t time.Time) tea.Msg {
+func tickCmd(interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(t time.Time) tea.Msg {
 		return types.TickMsg(t)
 	})
 }
 
+// statsTickCmd creates the tick for refreshing live container stats
+func statsTickCmd(interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(t time.Time) tea.Msg {
+		return types.StatsTickMsg(t)
+	})
+}
+
+// recordInterval is how often a stats recording samples its containers
+const recordInterval = time.Second
+
+// recordTickCmd creates the sampling tick of a stats recording
+func recordTickCmd() tea.Cmd {
+	return tea.Tick(recordInterval, func(t time.Time) tea.Msg {
+		return types.RecordTickMsg(t)
+	})
+}
+
+// processesTickCmd creates the tick for refreshing the processes view
+func processesTickCmd(interval time.Duration, containerID string) tea.Cmd {
+	return tea.Tick(interval, func(t time.Time) tea.Msg {
+		return types.ProcessesTickMsg{ContainerID: containerID}
+	})
+}
+
+// dashboardInterval is how often the stats dashboard samples its container
+const dashboardInterval = time.Second
+
+// dashboardTickCmd creates the sampling tick of the stats dashboard
+func dashboardTickCmd(containerID string) tea.Cmd {
+	return tea.Tick(dashboardInterval, func(t time.Time) tea.Msg {
+		return types.StatsDashboardTickMsg{ContainerID: containerID, Time: t}
+	})
+}
+
+// scheduleInterval is how often pending schedules are checked
+const scheduleInterval = time.Second
+
+// scheduleTickCmd creates the tick that runs schedules once due
+func scheduleTickCmd() tea.Cmd {
+	return tea.Tick(scheduleInterval, func(t time.Time) tea.Msg {
+		return types.ScheduleTickMsg(t)
+	})
+}
+
+// scheduledActionTask queues a schedule that has come due
+func (m *Model) scheduledActionTask(entry schedule.Entry) {
+	name := fmt.Sprintf("Scheduled %s of %s", entry.Action, entry.Container)
+	m.enqueueTask(name, func(ctx context.Context, progress func(string)) (string, error) {
+		err := m.withHooks(ctx, entry.Action.String(), entry.ContainerID, entry.Container, func() error {
+			switch entry.Action {
+			case schedule.Stop:
+				return m.docker.StopContainer(ctx, entry.ContainerID)
+			case schedule.Restart:
+				return m.docker.RestartContainer(ctx, entry.ContainerID)
+			}
+			return m.docker.StartContainer(ctx, entry.ContainerID)
+		})
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s %s", entry.Container, entry.Action.Past()), nil
+	})
+}
+
 // animationTickCmd creates a fast tick for status animations
 func animationTickCmd() tea.Cmd {
 	return tea.Tick(200*time.Millisecond, func(t time.Time) tea.Msg {
@@ -23,6 +103,87 @@ func animationTickCmd() tea.Cmd {
 	})
 }
 
+// watchTickCmd creates the poll tick for watched bind-mount paths
+func watchTickCmd() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return types.WatchTickMsg(t)
+	})
+}
+
+// syncStatsCmd points the stats streams at the given containers and returns
+// the latest samples
+func (m *Model) syncStatsCmd(containerIDs []string) tea.Cmd {
+	return func() tea.Msg {
+		m.stats.Sync(containerIDs)
+		return types.StatsMsg(m.stats.Snapshot())
+	}
+}
+
+// hostLoadCmd reads the load of the daemon's host, when it runs on this
+// machine
+func (m *Model) hostLoadCmd() tea.Cmd {
+	return func() tea.Msg {
+		load, ok := m.docker.HostLoad(m.hostInfo)
+		if !ok {
+			return nil
+		}
+		return types.HostLoadMsg(load)
+	}
+}
+
+// hostInfoCmd fetches the daemon's host resources; failures are silent since
+// they only feed the Docker Desktop warning
+func (m *Model) hostInfoCmd() tea.Cmd {
+	return func() tea.Msg {
+		info, err := m.docker.HostInfo(nil)
+		if err != nil {
+			return nil
+		}
+		return types.HostInfoMsg(info)
+	}
+}
+
+// refreshDebounce is how long refreshes asked for by finished actions are
+// held back, so that a burst of them (e.g. a bulk stop) fetches once
+const refreshDebounce = 300 * time.Millisecond
+
+// scheduleRefresh asks for the lists in r to be fetched at the end of the
+// current debounce window, starting one if none is running
+func (m *Model) scheduleRefresh(r types.Refresh) tea.Cmd {
+	if r == 0 {
+		return nil
+	}
+	m.pendingRefresh |= r
+	if m.refreshScheduled {
+		return nil
+	}
+	m.refreshScheduled = true
+	return tea.Tick(refreshDebounce, func(time.Time) tea.Msg {
+		return types.RefreshDueMsg{}
+	})
+}
+
+// fetchPendingCmd fetches the lists asked for during the debounce window
+func (m *Model) fetchPendingCmd() tea.Cmd {
+	pending := m.pendingRefresh
+	m.pendingRefresh, m.refreshScheduled = 0, false
+
+	var cmds []tea.Cmd
+	if pending&types.RefreshContainers != 0 {
+		cmds = append(cmds, m.fetchContainersCmd())
+	}
+	if pending&types.RefreshImages != 0 {
+		cmds = append(cmds, m.fetchImagesCmd())
+	}
+	if pending&types.RefreshVolumes != 0 {
+		cmds = append(cmds, m.fetchVolumesCmd())
+	}
+	if pending&types.RefreshNetworks != 0 {
+		cmds = append(cmds, m.fetchNetworksCmd())
+	}
+	return tea.Batch(cmds...)
+}
+
 // fetchContainersCmd fetches containers from Docker
 func (m *Model) fetchContainersCmd() tea.Cmd {
 	return func() tea.Msg {
@@ -33,6 +194,7 @@ func (m *Model) fetchContainersCmd() tea.Cmd {
 		if err != nil {
 			return types.ErrMsg(err)
 		}
+		m.cache.SaveContainers(containers)
 		return types.ContainerListMsg(containers)
 	}
 }
@@ -47,6 +209,7 @@ func (m *Model) fetchImagesCmd() tea.Cmd {
 		if err != nil {
 			return types.ErrMsg(err)
 		}
+		m.cache.SaveImages(images)
 		return types.ImageListMsg(images)
 	}
 }
@@ -61,6 +224,7 @@ func (m *Model) fetchVolumesCmd() tea.Cmd {
 		if err != nil {
 			return types.ErrMsg(err)
 		}
+		m.cache.SaveVolumes(volumes)
 		return types.VolumeListMsg(volumes)
 	}
 }
@@ -75,6 +239,7 @@ func (m *Model) fetchNetworksCmd() tea.Cmd {
 		if err != nil {
 			return types.ErrMsg(err)
 		}
+		m.cache.SaveNetworks(networks)
 		return types.NetworkListMsg(networks)
 	}
 }
@@ -82,42 +247,133 @@ func (m *Model) fetchNetworksCmd() tea.Cmd {
 // startContainerCmd starts a container
 func (m *Model) startContainerCmd(containerID, containerName string) tea.Cmd {
 	return func() tea.Msg {
-		ctx, cancel := m.docker.WithTimeout()
-		defer cancel()
-
-		if err := m.docker.StartContainer(ctx, containerID); err != nil {
-			return types.ActionErrorMsg(err.Error())
+		err := m.withHooks(context.Background(), "start", containerID, containerName, func() error {
+			ctx, cancel := m.docker.WithTimeout()
+			defer cancel()
+			return m.docker.StartContainer(ctx, containerID)
+		})
+		if err != nil {
+			return m.startErrorMsg(err, containerID, containerName)
 		}
-		return types.ActionSuccessMsg("Container " + containerName + " started")
+		return types.ActionSuccessMsg{Text: "Container " + containerName + " started", Refresh: types.RefreshContainers}
+	}
+}
+
+// startErrorMsg reports a failed start or restart, with a diagnosis when
+// the cause is a known one
+func (m *Model) startErrorMsg(err error, containerID, containerName string) tea.Msg {
+	if failure, ok := m.docker.DiagnoseStart(nil, err, containerID); ok {
+		return types.StartFailedMsg{Container: containerName, Err: err.Error(), Failure: failure}
 	}
+	return types.ActionErrorMsg(err.Error())
 }
 
 // stopContainerCmd stops a container
 func (m *Model) stopContainerCmd(containerID, containerName string) tea.Cmd {
+	return func() tea.Msg {
+		err := m.withHooks(context.Background(), "stop", containerID, containerName, func() error {
+			ctx, cancel := m.docker.WithTimeout()
+			defer cancel()
+			return m.docker.StopContainer(ctx, containerID)
+		})
+		if err != nil {
+			return types.ActionErrorMsg(err.Error())
+		}
+		return types.ActionSuccessMsg{Text: "Container " + containerName + " stopped", Refresh: types.RefreshContainers}
+	}
+}
+
+// pauseContainerCmd pauses a running container or unpauses a paused one
+func (m *Model) pauseContainerCmd(containerID, containerName string, pause bool) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := m.docker.WithTimeout()
 		defer cancel()
 
-		if err := m.docker.StopContainer(ctx, containerID); err != nil {
-			return types.ActionErrorMsg(err.Error())
+		var err error
+		if pause {
+			err = m.docker.PauseContainer(ctx, containerID)
+		} else {
+			err = m.docker.UnpauseContainer(ctx, containerID)
 		}
-		return types.ActionSuccessMsg("Container " + containerName + " stopped")
+		return types.ContainerPausedMsg{ContainerID: containerID, Name: containerName, Paused: pause, Err: err}
 	}
 }
 
 // restartContainerCmd restarts a container
 func (m *Model) restartContainerCmd(containerID, containerName string) tea.Cmd {
+	return func() tea.Msg {
+		err := m.withHooks(context.Background(), "restart", containerID, containerName, func() error {
+			ctx, cancel := m.docker.WithTimeout()
+			defer cancel()
+			return m.docker.RestartContainer(ctx, containerID)
+		})
+		if err != nil {
+			return m.startErrorMsg(err, containerID, containerName)
+		}
+		return types.ActionSuccessMsg{Text: "Container " + containerName + " restarted", Refresh: types.RefreshContainers}
+	}
+}
+
+// serviceActionCmd starts, stops or restarts every replica of a compose service
+func (m *Model) serviceActionCmd(action, service string, replicas []types.Container) tea.Cmd {
+	return func() tea.Msg {
+		done := map[string]string{"start": "started", "stop": "stopped", "restart": "restarted"}[action]
+		for _, r := range replicas {
+			err := m.withHooks(context.Background(), action, r.ID, r.Name, func() error {
+				switch action {
+				case "start":
+					return m.docker.StartContainer(nil, r.ID)
+				case "stop":
+					return m.docker.StopContainer(nil, r.ID)
+				}
+				return m.docker.RestartContainer(nil, r.ID)
+			})
+			if err != nil {
+				return types.ActionErrorMsg(r.Name + ": " + err.Error())
+			}
+		}
+		return types.ActionSuccessMsg{Text: fmt.Sprintf("Service %s: %d replica(s) %s", service, len(replicas), done), Refresh: types.RefreshContainers}
+	}
+}
+
+// addReplicaCmd scales a compose service up by one, cloning its
+// highest-numbered replica
+func (m *Model) addReplicaCmd(service string, replicas []types.Container) tea.Cmd {
+	return func() tea.Msg {
+		last := highestReplica(replicas)
+		if _, err := m.docker.AddReplica(nil, last.ID, last.ComposeNumber+1); err != nil {
+			return types.ActionErrorMsg(err.Error())
+		}
+		return types.ActionSuccessMsg{Text: fmt.Sprintf("Scaled %s to %d replicas", service, len(replicas)+1), Refresh: types.RefreshContainers}
+	}
+}
+
+// removeReplicaCmd scales a compose service down by one, removing its
+// highest-numbered replica
+func (m *Model) removeReplicaCmd(service string, replicas []types.Container) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := m.docker.WithTimeout()
 		defer cancel()
 
-		if err := m.docker.RestartContainer(ctx, containerID); err != nil {
+		last := highestReplica(replicas)
+		if err := m.docker.DeleteContainer(ctx, last.ID, true); err != nil {
 			return types.ActionErrorMsg(err.Error())
 		}
-		return types.ActionSuccessMsg("Container " + containerName + " restarted")
+		return types.ActionSuccessMsg{Text: fmt.Sprintf("Scaled %s to %d replicas (removed %s)", service, len(replicas)-1, last.Name), Refresh: types.RefreshContainers}
 	}
 }
 
+// highestReplica returns the replica with the highest compose number
+func highestReplica(replicas []types.Container) types.Container {
+	last := replicas[0]
+	for _, r := range replicas[1:] {
+		if r.ComposeNumber > last.ComposeNumber {
+			last = r
+		}
+	}
+	return last
+}
+
 // deleteContainerCmd deletes a container
 func (m *Model) deleteContainerCmd(containerID, containerName string) tea.Cmd {
 	return func() tea.Msg {
@@ -127,21 +383,390 @@ func (m *Model) deleteContainerCmd(containerID, containerName string) tea.Cmd {
 		if err := m.docker.DeleteContainer(ctx, containerID, true); err != nil {
 			return types.ActionErrorMsg(err.Error())
 		}
-		return types.ActionSuccessMsg("Container " + containerName + " deleted")
+		return types.ActionSuccessMsg{Text: "Container " + containerName + " deleted", Refresh: types.RefreshContainers}
+	}
+}
+
+// signalContainerCmd sends a signal to a container's main process
+func (m *Model) signalContainerCmd(containerID, containerName, signal string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := m.docker.WithTimeout()
+		defer cancel()
+
+		if err := m.docker.SignalContainer(ctx, containerID, signal); err != nil {
+			return types.ActionErrorMsg(err.Error())
+		}
+		return types.ActionSuccessMsg{Text: "Sent " + signal + " to " + containerName, Refresh: types.RefreshContainers}
+	}
+}
+
+// getBindMountsCmd retrieves the bind mounts of a container for the watch modal
+func (m *Model) getBindMountsCmd(containerID string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := m.docker.WithTimeout()
+		defer cancel()
+
+		mounts, err := m.docker.GetBindMounts(ctx, containerID)
+		return types.BindMountsMsg{ContainerID: containerID, Mounts: mounts, Err: err}
+	}
+}
+
+// pollWatchCmd fingerprints the watched paths of a container
+func pollWatchCmd(containerID string, paths []string) tea.Cmd {
+	return func() tea.Msg {
+		fingerprint, err := watcher.Fingerprint(paths)
+		return types.WatchResultMsg{
+			ContainerID: containerID,
+			Fingerprint: fingerprint,
+			Err:         err,
+		}
+	}
+}
+
+// fetchLastLogLinesCmd fetches the last log line of each given container
+func (m *Model) fetchLastLogLinesCmd(containerIDs []string) tea.Cmd {
+	if len(containerIDs) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := m.docker.WithTimeout()
+		defer cancel()
+
+		lines := make(types.LastLogLinesMsg, len(containerIDs))
+		for _, id := range containerIDs {
+			if line, err := m.docker.LastLogLine(ctx, id); err == nil {
+				lines[id] = line
+			}
+		}
+		return lines
+	}
+}
+
+// searchLogsCmd searches the recent logs of the given containers
+func (m *Model) searchLogsCmd(containers []types.Container, query string) tea.Cmd {
+	return func() tea.Msg {
+		return types.LogSearchMsg{Query: query, Results: m.docker.SearchLogs(nil, containers, query)}
+	}
+}
+
+// previewBulkEnvCmd works out which env changes each target would get
+func (m *Model) previewBulkEnvCmd(targets []types.Container, changes []types.EnvVar) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := m.docker.WithTimeout()
+		defer cancel()
+
+		previews := make(types.BulkEnvPreviewMsg, 0, len(targets))
+		for _, c := range targets {
+			preview := types.EnvPreview{ContainerID: c.ID, ContainerName: c.Name}
+			env, err := m.docker.ContainerEnv(ctx, c.ID)
+			if err != nil {
+				preview.Err = err
+			} else {
+				preview.Changes = docker.EnvChanges(env, changes)
+			}
+			previews = append(previews, preview)
+		}
+		return previews
+	}
+}
+
+// bulkEnvApplyCmd recreates every previewed container that has changes
+func (m *Model) bulkEnvApplyCmd(previews []types.EnvPreview, changes []types.EnvVar) tea.Cmd {
+	return func() tea.Msg {
+		recreated := 0
+		var failures []string
+		for _, p := range previews {
+			if p.Err != nil || len(p.Changes) == 0 {
+				continue
+			}
+			// Each recreate gets its own long timeout (nil context)
+			if _, err := m.docker.RecreateWithEnv(nil, p.ContainerID, changes); err != nil {
+				failures = append(failures, p.ContainerName+": "+err.Error())
+				continue
+			}
+			recreated++
+		}
+
+		if len(failures) > 0 {
+			return types.ActionErrorMsg(fmt.Sprintf("Recreated %d, failed %d: %s", recreated, len(failures), strings.Join(failures, "; ")))
+		}
+		return types.ActionSuccessMsg{Text: fmt.Sprintf("Recreated %d container(s) with updated env", recreated), Refresh: types.RefreshContainers}
+	}
+}
+
+// recreateWithLabelsCmd recreates a container with the labels edited in the
+// labels editor
+func (m *Model) recreateWithLabelsCmd(container types.Container, labels map[string]string) tea.Cmd {
+	return func() tea.Msg {
+		// A recreate gets its own long timeout (nil context)
+		if _, err := m.docker.RecreateWithLabels(nil, container.ID, labels); err != nil {
+			return types.ActionErrorMsg(err.Error())
+		}
+		return types.ActionSuccessMsg{Text: "Recreated " + container.Name + " with updated labels", Refresh: types.RefreshContainers}
 	}
 }
 
 // getContainerLogsCmd retrieves container logs
 func (m *Model) getContainerLogsCmd(containerID string) tea.Cmd {
+	// A time window returns every line inside it, otherwise the tail
+	opts := docker.LogsOptions{Tail: "all", Since: m.logsSince, Until: m.logsUntil, Timestamps: m.logsTimestamps}
+	if m.logsTail > 0 {
+		opts.Tail = strconv.Itoa(m.logsTail)
+	}
+	if !opts.Since.IsZero() || !opts.Until.IsZero() {
+		opts.Tail = "all"
+	}
+
 	return func() tea.Msg {
 		ctx, cancel := m.docker.WithTimeout()
 		defer cancel()
 
-		logs, err := m.docker.GetContainerLogs(ctx, containerID, "100")
+		logs, stderr, err := m.docker.GetContainerLogStreams(ctx, containerID, opts)
+		if err != nil {
+			return types.ActionErrorMsg(err.Error())
+		}
+		return types.LogsMsg{Logs: logs, Stderr: stderr}
+	}
+}
+
+// logsStreamName names the followed logs among the foreground streams
+const logsStreamName = "followed logs"
+
+// followLogsCmd starts a follow session streaming new log lines of a
+// container into the logs view, surviving restarts and compose recreates
+func (m *Model) followLogsCmd(containerID string) tea.Cmd {
+	m.logsFollowID++
+	m.logsFollow = true
+	m.logsSince, m.logsUntil = time.Time{}, time.Time{} // The view now runs up to now
+
+	follow, timestamps := m.logsFollowID, m.logsTimestamps
+	updates := make(chan tea.Msg, 64)
+	m.logsFollowUpdates = updates
+	ctx := m.openStream(logsStreamName)
+
+	go func() {
+		defer close(updates)
+		err := m.docker.FollowLogs(ctx, containerID, timestamps, func(line string) {
+			select {
+			case updates <- types.LogLineMsg{Follow: follow, Line: line}:
+			case <-ctx.Done():
+			}
+		})
+		if ctx.Err() != nil {
+			return // Stopped by the user
+		}
+		updates <- types.LogFollowEndMsg{Follow: follow, Err: err}
+	}()
+
+	return waitForUpdate(updates)
+}
+
+// Aggregated logs view limits: lines of history per container, and lines
+// kept in all
+const (
+	aggLogsTail = "50"
+	aggLinesMax = 5000
+)
+
+// aggStreamName names the aggregated logs among the foreground streams
+const aggStreamName = "aggregated logs"
+
+// aggregatedLogsCmd starts a session showing the recent logs of several
+// containers merged by time, then following their new lines together
+func (m *Model) aggregatedLogsCmd(containerIDs []string) tea.Cmd {
+	m.aggFollowID++
+	m.aggFollow = true
+
+	follow := m.aggFollowID
+	updates := make(chan tea.Msg, 64)
+	m.aggUpdates = updates
+	ctx := m.openStream(aggStreamName)
+
+	go func() {
+		defer close(updates)
+		lines, err := m.docker.AggregatedTail(ctx, containerIDs, aggLogsTail)
+		select {
+		case updates <- types.AggregatedLogsMsg{Follow: follow, Lines: lines, Err: err}:
+		case <-ctx.Done():
+			return
+		}
+
+		err = m.docker.FollowAggregatedLogs(ctx, containerIDs, func(line types.AggregatedLogLine) {
+			select {
+			case updates <- types.AggregatedLogLineMsg{Follow: follow, Line: line}:
+			case <-ctx.Done():
+			}
+		})
+		if ctx.Err() != nil {
+			return // Stopped by the user
+		}
+		updates <- types.AggregatedLogsEndMsg{Follow: follow, Err: err}
+	}()
+
+	return waitForUpdate(updates)
+}
+
+// execWithOptionsCmd runs a command interactively in the container as user
+// in workdir with extra env, like `docker exec -it`; without a command the
+// container's shell is opened
+func (m *Model) execWithOptionsCmd(containerID string, cmd []string, user, workdir string, env []string) tea.Cmd {
+	return func() tea.Msg {
+		if len(cmd) == 0 {
+			shell, err := m.docker.DetectShell(nil, containerID)
+			if err != nil {
+				return types.ActionErrorMsg("Failed to exec: " + err.Error())
+			}
+			cmd = []string{shell}
+		}
+		session := m.docker.NewExecSession(containerID, cmd)
+		session.SetUser(user)
+		session.SetWorkdir(workdir)
+		session.SetEnv(env)
+		return tea.Exec(session, func(err error) tea.Msg {
+			if err != nil {
+				return types.ActionErrorMsg("Failed to exec: " + err.Error())
+			}
+			return nil
+		})()
+	}
+}
+
+// execOutputCmd runs a command in a container through its shell, so pipes
+// and globs work, and returns its output
+func (m *Model) execOutputCmd(containerID, command string) tea.Cmd {
+	return func() tea.Msg {
+		out, code, err := m.docker.ExecOutput(nil, containerID, []string{"sh", "-c", command})
+		return types.ExecResultMsg{ContainerID: containerID, Command: command, Output: out, ExitCode: code, Err: err}
+	}
+}
+
+// eventsStreamName names the events view's stream among the foreground
+// streams
+const eventsStreamName = "events"
+
+// eventsCmd starts a session streaming the daemon's events matching filter
+// into the events view, replayed from since unless it is zero
+func (m *Model) eventsCmd(filter string, since time.Time) tea.Cmd {
+	m.eventsFollowID++
+	m.eventsFollow = true
+
+	follow := m.eventsFollowID
+	updates := make(chan tea.Msg, 64)
+	m.eventsUpdates = updates
+	ctx := m.openStream(eventsStreamName)
+
+	go func() {
+		defer close(updates)
+		err := m.docker.FollowEvents(ctx, filter, since, func(event types.DockerEvent) {
+			select {
+			case updates <- types.EventMsg{Follow: follow, Event: event}:
+			case <-ctx.Done():
+			}
+		})
+		if ctx.Err() != nil {
+			return // Stopped by the user
+		}
+		updates <- types.EventsEndMsg{Follow: follow, Err: err}
+	}()
+
+	return waitForUpdate(updates)
+}
+
+// containerHealthCmd fetches the healthcheck probes of a container
+func (m *Model) containerHealthCmd(containerID string) tea.Cmd {
+	return func() tea.Msg {
+		log, err := m.docker.ContainerHealth(nil, containerID)
+		return types.HealthLogMsg{ContainerID: containerID, Log: log, Err: err}
+	}
+}
+
+// containerEnvCmd reads the environment of a container for the env view
+func (m *Model) containerEnvCmd(containerID string) tea.Cmd {
+	return func() tea.Msg {
+		env, err := m.docker.ContainerEnv(nil, containerID)
+		return types.ContainerEnvMsg{ContainerID: containerID, Env: docker.ParseEnv(env), Err: err}
+	}
+}
+
+// allocationsCmd reads the resource allocation of each of containers, by
+// ID, for the provisioning report
+func (m *Model) allocationsCmd(containers map[string]string) tea.Cmd {
+	return func() tea.Msg {
+		allocations := make(map[string]types.ResourceAllocation, len(containers))
+		for id := range containers {
+			alloc, err := m.docker.ContainerAllocation(nil, id)
+			if err != nil {
+				return types.AllocationsMsg{Err: err}
+			}
+			allocations[id] = alloc
+		}
+		return types.AllocationsMsg{Allocations: allocations, Names: containers}
+	}
+}
+
+// containerLimitsCmd reads the resource limits of a container for the
+// update prompt
+func (m *Model) containerLimitsCmd(containerID string) tea.Cmd {
+	return func() tea.Msg {
+		limits, err := m.docker.ContainerLimits(nil, containerID)
+		return types.ResourceLimitsMsg{ContainerID: containerID, Limits: limits, Err: err}
+	}
+}
+
+// updateLimitsCmd changes the resource limits and restart policy of a
+// container in place
+func (m *Model) updateLimitsCmd(containerID, containerName string, limits types.ResourceLimits) tea.Cmd {
+	return func() tea.Msg {
+		warnings, err := m.docker.UpdateContainerLimits(nil, containerID, limits)
 		if err != nil {
 			return types.ActionErrorMsg(err.Error())
 		}
-		return types.LogsMsg(logs)
+		text := "Updated the limits of " + containerName
+		if len(warnings) > 0 {
+			text += " (" + strings.Join(warnings, "; ") + ")"
+		}
+		return types.ActionSuccessMsg{Text: text}
+	}
+}
+
+// startForwardCmd proxies a localhost port to a port of a running
+// container, at its address on the bridge network
+func (m *Model) startForwardCmd(containerID, containerName string, localPort, containerPort int) tea.Cmd {
+	return func() tea.Msg {
+		ip, err := m.docker.ContainerAddress(nil, containerID)
+		if err != nil {
+			return types.ForwardStartedMsg{Err: fmt.Errorf("%s: %w", containerName, err)}
+		}
+		f, err := m.forwards.Start(containerName, localPort, net.JoinHostPort(ip, strconv.Itoa(containerPort)))
+		if err != nil {
+			return types.ForwardStartedMsg{Err: err}
+		}
+		return types.ForwardStartedMsg{Text: f.String()}
+	}
+}
+
+// pruneContainersCmd removes the stopped containers matching filter
+func (m *Model) pruneContainersCmd(filter string) tea.Cmd {
+	return func() tea.Msg {
+		report, err := m.docker.PruneContainers(nil, filter)
+		if err != nil {
+			return types.ActionErrorMsg(err.Error())
+		}
+		if report.Deleted == 0 {
+			return types.ActionSuccessMsg{Text: "No stopped containers to prune"}
+		}
+		return types.ActionSuccessMsg{
+			Text:    fmt.Sprintf("Pruned %d container(s), reclaimed %s", report.Deleted, units.BytesSize(float64(report.SpaceReclaimed))),
+			Refresh: types.RefreshContainers,
+		}
+	}
+}
+
+// containerProcessesCmd lists the processes of a container
+func (m *Model) containerProcessesCmd(containerID string) tea.Cmd {
+	return func() tea.Msg {
+		processes, err := m.docker.ContainerProcesses(nil, containerID)
+		return types.ProcessesMsg{ContainerID: containerID, Processes: processes, Err: err}
 	}
 }
 
@@ -159,19 +784,298 @@ func (m *Model) inspectContainerCmd(containerID string) tea.Cmd {
 	}
 }
 
+// imageTagTimesCmd looks up the last tag time of unused images that aren't
+// cached yet, only while a stale filter needs them
+func (m *Model) imageTagTimesCmd() tea.Cmd {
+	if m.imageFilter < types.ImageFilterStale {
+		return nil
+	}
+	var missing []string
+	for _, img := range m.allImages {
+		if _, ok := m.imageTagTimes[img.ID]; !ok && !img.InUse {
+			missing = append(missing, img.ID)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return func() tea.Msg {
+		times, err := m.docker.ImageLastTagTimes(nil, missing)
+		if err != nil && len(times) == 0 {
+			return types.ActionErrorMsg(err.Error())
+		}
+		// Remember images without a tag time too, so they aren't inspected again
+		for _, id := range missing {
+			if _, ok := times[id]; !ok {
+				times[id] = time.Time{}
+			}
+		}
+		return types.ImageTagTimesMsg(times)
+	}
+}
+
+// imagePlatformsCmd looks up the platform of images that aren't cached yet,
+// for the ARCH column
+func (m *Model) imagePlatformsCmd() tea.Cmd {
+	var missing []string
+	for _, img := range m.allImages {
+		if _, ok := m.imagePlatforms[img.ID]; !ok {
+			missing = append(missing, img.ID)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return func() tea.Msg {
+		platforms, err := m.docker.ImagePlatforms(nil, missing)
+		if err != nil && len(platforms) == 0 {
+			return types.ActionErrorMsg(err.Error())
+		}
+		// Remember images without a platform too, so they aren't inspected again
+		for _, id := range missing {
+			if _, ok := platforms[id]; !ok {
+				platforms[id] = ""
+			}
+		}
+		return types.ImagePlatformsMsg(platforms)
+	}
+}
+
 // deleteImageCmd deletes an image
-func (m *Model) deleteImageCmd(imageID string) tea.Cmd {
+func (m *Model) deleteImageCmd(imageID, imageName string) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := m.docker.WithTimeout()
 		defer cancel()
 
 		if err := m.docker.DeleteImage(ctx, imageID, false); err != nil {
+			// Offer to delete the stopped containers holding the image
+			if docker.IsImageUsedByStoppedContainers(err) {
+				stopped, listErr := m.docker.StoppedContainersUsingImage(ctx, imageID)
+				if listErr == nil && len(stopped) > 0 {
+					return types.ImageInUseMsg{ImageID: imageID, ImageName: imageName, Containers: stopped}
+				}
+			}
+			return types.ActionErrorMsg(err.Error())
+		}
+		return types.ActionSuccessMsg{Text: "Image deleted successfully", Refresh: types.RefreshImages}
+	}
+}
+
+// deleteImageCascadeCmd deletes the stopped containers created from an
+// image, then the image itself
+func (m *Model) deleteImageCascadeCmd(imageID string, containers []types.Container) tea.Cmd {
+	return func() tea.Msg {
+		for _, c := range containers {
+			if err := m.docker.DeleteContainer(nil, c.ID, false); err != nil {
+				return types.ActionErrorMsg(fmt.Sprintf("%s: %v", c.Name, err))
+			}
+		}
+		if err := m.docker.DeleteImage(nil, imageID, false); err != nil {
 			return types.ActionErrorMsg(err.Error())
 		}
-		return types.ActionSuccessMsg("Image deleted successfully")
+		return types.ActionSuccessMsg{Text: fmt.Sprintf("Image and %d stopped container(s) deleted", len(containers)), Refresh: types.RefreshImages | types.RefreshContainers}
 	}
 }
 
+// pullImageTask queues a pull of an image reference. When keepAs is set
+// the local image of the reference is first tagged keepAs, so it stays
+// available for a rollback once the pull moves the tag.
+func (m *Model) pullImageTask(imageRef, keepAs string) {
+	m.enqueueTask("Pull "+imageRef, func(ctx context.Context, progress func(string)) (string, error) {
+		if keepAs != "" {
+			progress("Tagging the current image " + keepAs)
+			if err := m.docker.TagImage(ctx, imageRef, keepAs); err != nil {
+				return "", err
+			}
+		}
+		if err := m.docker.PullImageWithProgress(ctx, imageRef, progress); err != nil {
+			return "", err
+		}
+		if keepAs != "" {
+			return "Pulled " + imageRef + ", previous image kept as " + keepAs, nil
+		}
+		return "Pulled " + imageRef, nil
+	})
+}
+
+// saveImagesTask queues saving the images into one tar archive at path
+func (m *Model) saveImagesTask(images []types.Image, path string) {
+	name := "Save " + images[0].Repository + ":" + images[0].Tag
+	if len(images) > 1 {
+		name = fmt.Sprintf("Save %d images", len(images))
+	}
+	refs := docker.SaveRefs(images)
+	estimate := docker.SaveEstimate(images)
+	m.enqueueTask(name, func(ctx context.Context, progress func(string)) (string, error) {
+		if err := m.docker.SaveImages(ctx, refs, path, estimate, progress); err != nil {
+			return "", err
+		}
+		return "Images saved to " + path, nil
+	})
+}
+
+// commitTask queues committing a container to a new image; the images
+// are refetched once it is done
+func (m *Model) commitTask(container types.Container, ref, comment, author string) {
+	m.enqueueTask("Commit "+container.Name+" to "+ref, func(ctx context.Context, progress func(string)) (string, error) {
+		id, err := m.docker.CommitContainer(ctx, container.ID, ref, comment, author)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Committed %s to %s (%s)", container.Name, ref, id), nil
+	})
+}
+
+// bulkKinds names the rows of each tab in bulk action summaries
+var bulkKinds = [4]string{"container(s)", "image(s)", "volume(s)", "network(s)"}
+
+// bulkPastTense is the outcome of each bulk action
+var bulkPastTense = map[string]string{"start": "Started", "stop": "Stopped", "delete": "Deleted"}
+
+// bulkTask queues starting, stopping or deleting the targets of a tab one
+// after the other. A target that fails doesn't stop the rest; the summary
+// counts the successes and names the failures.
+func (m *Model) bulkTask(tab int, action string, targets []bulkTarget) {
+	kind := bulkKinds[tab]
+	name := fmt.Sprintf("%s %d %s", titleCase(action), len(targets), kind)
+	m.enqueueTask(name, func(ctx context.Context, progress func(string)) (string, error) {
+		var failures []string
+		for i, t := range targets {
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+			progress(fmt.Sprintf("%d/%d %s", i+1, len(targets), t.name))
+
+			var err error
+			switch {
+			case action == "start":
+				err = m.withHooks(ctx, action, t.id, t.name, func() error { return m.docker.StartContainer(ctx, t.id) })
+			case action == "stop":
+				err = m.withHooks(ctx, action, t.id, t.name, func() error { return m.docker.StopContainer(ctx, t.id) })
+			case tab == 0:
+				err = m.docker.DeleteContainer(ctx, t.id, true)
+			case tab == 1:
+				err = m.docker.DeleteImage(ctx, t.id, false)
+			case tab == 2:
+				err = m.docker.DeleteVolume(ctx, t.id, true)
+			case tab == 3:
+				err = m.docker.DeleteNetwork(ctx, t.id)
+			}
+			if err != nil {
+				failures = append(failures, t.name+": "+err.Error())
+			}
+		}
+
+		done := fmt.Sprintf("%s %d of %d %s", bulkPastTense[action], len(targets)-len(failures), len(targets), kind)
+		if len(failures) > 0 {
+			return "", fmt.Errorf("%s, %d failed: %s", done, len(failures), strings.Join(failures, "; "))
+		}
+		return done, nil
+	})
+}
+
+// drainTask queues stopping a container once its clients disconnected, or
+// after maxWait
+func (m *Model) drainTask(container types.Container, maxWait time.Duration) {
+	m.enqueueTask("Drain and stop "+container.Name, func(ctx context.Context, progress func(string)) (string, error) {
+		open, err := m.docker.DrainAndStop(ctx, container.ID, maxWait, progress)
+		if err != nil {
+			return "", err
+		}
+		if open > 0 {
+			return fmt.Sprintf("Stopped %s after %s with %d connection(s) still open", container.Name, maxWait, open), nil
+		}
+		return fmt.Sprintf("Drained and stopped %s", container.Name), nil
+	})
+}
+
+// fileCopyTask queues copying src to dest, from the container to the host
+// or, with toContainer, the other way round
+func (m *Model) fileCopyTask(container types.Container, src, dest string, toContainer bool) {
+	name := fmt.Sprintf("Copy %s:%s to %s", container.Name, src, dest)
+	if toContainer {
+		name = fmt.Sprintf("Copy %s to %s:%s", src, container.Name, dest)
+	}
+	m.enqueueTask(name, func(ctx context.Context, progress func(string)) (string, error) {
+		if toContainer {
+			if err := m.docker.CopyToContainer(ctx, container.ID, src, dest, progress); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Copied %s to %s:%s", src, container.Name, dest), nil
+		}
+		if err := m.docker.CopyFromContainer(ctx, container.ID, src, dest, progress); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Copied %s:%s to %s", container.Name, src, dest), nil
+	})
+}
+
+// tagCleanupTask queues deleting the tags of a cleanup plan
+func (m *Model) tagCleanupTask(plan types.TagCleanup) {
+	m.enqueueTask(fmt.Sprintf("Delete %d old tags of %s", len(plan.Delete), plan.Repository), func(ctx context.Context, progress func(string)) (string, error) {
+		if err := m.docker.DeleteTags(ctx, plan.Delete, progress); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Deleted %d tags of %s, kept the newest %d", len(plan.Delete), plan.Repository, len(plan.Keep)), nil
+	})
+}
+
+// enqueueTask adds a background operation to the task queue
+func (m *Model) enqueueTask(name string, fn tasks.Func) {
+	m.taskQueue.Add(name, fn)
+	m.statusMessage = name + " queued, [T] Tasks"
+}
+
+// waitForTasksCmd delivers a TasksChangedMsg once the task queue changes
+func (m *Model) waitForTasksCmd() tea.Cmd {
+	return func() tea.Msg {
+		<-m.taskQueue.Changed()
+		return types.TasksChangedMsg{}
+	}
+}
+
+// waitForHooksCmd delivers the next HookOutputMsg of a hook that ran
+func (m *Model) waitForHooksCmd() tea.Cmd {
+	return func() tea.Msg {
+		return <-m.hookOutput
+	}
+}
+
+// hookEvents maps a start, stop or restart to the hooks run before and
+// after it. A restart runs the stop hooks before and the start hooks after.
+var hookEvents = map[string][2]hooks.Event{
+	"start":   {hooks.PreStart, hooks.PostStart},
+	"stop":    {hooks.PreStop, hooks.PostStop},
+	"restart": {hooks.PreStop, hooks.PostStart},
+}
+
+// withHooks runs action, a start, stop or restart of a container, between
+// its pre and post hooks. A failing pre hook cancels the action; a failing
+// post hook is only reported. Each hook that ran is sent to the Messages
+// panel.
+func (m *Model) withHooks(ctx context.Context, action, containerID, containerName string, fn func() error) error {
+	events := hookEvents[action]
+	for _, hook := range m.hooks.For(events[0], containerName) {
+		out, err := hooks.Run(ctx, hook, containerID, containerName)
+		m.hookOutput <- types.HookOutputMsg{Event: string(hook.Event), Container: containerName, Output: out, Err: err}
+		if err != nil {
+			return fmt.Errorf("%s hook failed, %s cancelled: %w", hook.Event, action, err)
+		}
+	}
+
+	if err := fn(); err != nil {
+		return err
+	}
+
+	for _, hook := range m.hooks.For(events[1], containerName) {
+		out, err := hooks.Run(ctx, hook, containerID, containerName)
+		m.hookOutput <- types.HookOutputMsg{Event: string(hook.Event), Container: containerName, Output: out, Err: err}
+	}
+	return nil
+}
+
 // pullImageCmd pulls an image
 func (m *Model) pullImageCmd(imageName string) tea.Cmd {
 	return func() tea.Msg {
@@ -181,7 +1085,7 @@ func (m *Model) pullImageCmd(imageName string) tea.Cmd {
 		if err := m.docker.PullImage(ctx, imageName); err != nil {
 			return types.ActionErrorMsg(err.Error())
 		}
-		return types.ActionSuccessMsg("Image " + imageName + " pulled successfully")
+		return types.ActionSuccessMsg{Text: "Image " + imageName + " pulled successfully", Refresh: types.RefreshImages}
 	}
 }
 
@@ -199,24 +1103,98 @@ func (m *Model) inspectImageCmd(imageID string) tea.Cmd {
 	}
 }
 
-// runContainerCmd creates and runs a container from an image
-func (m *Model) runContainerCmd() tea.Cmd {
+// imageLayersCmd reads the layer contents of an image; this downloads the
+// whole image archive, so it uses the long timeout
+func (m *Model) imageLayersCmd(imageID string) tea.Cmd {
 	return func() tea.Msg {
-		if m.selectedImage == nil {
-			return types.ActionErrorMsg("No image selected")
+		ctx, cancel := m.docker.WithCustomTimeout(docker.TimeoutLong)
+		defer cancel()
+
+		layers, err := m.docker.ImageLayers(ctx, imageID)
+		return types.ImageLayersMsg{Layers: layers, Err: err}
+	}
+}
+
+// runContainerCmd creates and runs a container from the image reference
+// chosen in the run modal, pulling it first when it isn't present locally.
+// Pull progress and the final result arrive through m.runUpdates.
+func (m *Model) runContainerCmd() tea.Cmd {
+	if m.selectedImage == nil {
+		return func() tea.Msg { return types.ActionErrorMsg("No image selected") }
+	}
+
+	imageRef := m.runImageRef()
+	name, hostname := m.runContainerName, strings.TrimSpace(m.runHostname)
+	ports, volumes, envVars, labels := m.runPorts, m.runVolumes, m.runEnvVars, m.runLabels
+	updates := make(chan tea.Msg, 1)
+	m.runUpdates = updates
+
+	go func() {
+		defer close(updates)
+
+		exists, err := m.docker.ImageExists(nil, imageRef)
+		if err != nil {
+			updates <- types.ActionErrorMsg(err.Error())
+			return
+		}
+		if !exists {
+			updates <- types.PullProgressMsg("Pulling " + imageRef + "...")
+			err := m.docker.PullImageWithProgress(nil, imageRef, func(status string) {
+				// Drop intermediate updates the UI hasn't caught up with
+				select {
+				case updates <- types.PullProgressMsg("Pulling " + imageRef + ": " + status):
+				default:
+				}
+			})
+			if err != nil {
+				updates <- types.ActionErrorMsg(err.Error())
+				return
+			}
 		}
 
 		ctx, cancel := m.docker.WithTimeout()
 		defer cancel()
 
-		containerID, err := m.docker.RunContainer(ctx, m.selectedImage, m.runContainerName, m.runPorts, m.runVolumes, m.runEnvVars)
+		containerID, err := m.docker.RunContainer(ctx, imageRef, name, hostname, ports, volumes, envVars, labels)
 		if err != nil {
-			return types.ActionErrorMsg(err.Error())
+			updates <- types.ActionErrorMsg(err.Error())
+			return
+		}
+		updates <- types.ActionSuccessMsg{Text: "Container started: " + containerID, Refresh: types.RefreshContainers | types.RefreshImages}
+	}()
+
+	return waitForUpdate(updates)
+}
+
+// waitForUpdate delivers the next message of a pending background operation
+func waitForUpdate(updates <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-updates
+		if !ok {
+			return nil
 		}
-		return types.ActionSuccessMsg("Container started: " + containerID)
+		return msg
 	}
 }
 
+// estimateVolumeCopyCmd measures what copying source into target would do
+func (m *Model) estimateVolumeCopyCmd(source, target string) tea.Cmd {
+	return func() tea.Msg {
+		plan, err := m.docker.EstimateVolumeCopy(nil, source, target)
+		return types.VolumeCopyPlanMsg{Plan: plan, Err: err}
+	}
+}
+
+// copyVolumeTask queues a volume copy as planned
+func (m *Model) copyVolumeTask(plan types.VolumeCopyPlan) {
+	m.enqueueTask("Copy volume "+plan.Source+" → "+plan.Target, func(ctx context.Context, progress func(string)) (string, error) {
+		if err := m.docker.CopyVolume(ctx, plan.Source, plan.Target, plan.Size, progress); err != nil {
+			return "", err
+		}
+		return "Copied " + plan.Source + " to " + plan.Target, nil
+	})
+}
+
 // deleteVolumeCmd deletes a volume
 func (m *Model) deleteVolumeCmd(volumeName string) tea.Cmd {
 	return func() tea.Msg {
@@ -226,7 +1204,7 @@ func (m *Model) deleteVolumeCmd(volumeName string) tea.Cmd {
 		if err := m.docker.DeleteVolume(ctx, volumeName, true); err != nil {
 			return types.ActionErrorMsg(err.Error())
 		}
-		return types.ActionSuccessMsg("Volume " + volumeName + " deleted")
+		return types.ActionSuccessMsg{Text: "Volume " + volumeName + " deleted", Refresh: types.RefreshVolumes}
 	}
 }
 
@@ -253,7 +1231,36 @@ func (m *Model) deleteNetworkCmd(networkID string) tea.Cmd {
 		if err := m.docker.DeleteNetwork(ctx, networkID); err != nil {
 			return types.ActionErrorMsg(err.Error())
 		}
-		return types.ActionSuccessMsg("Network deleted successfully")
+		return types.ActionSuccessMsg{Text: "Network deleted successfully", Refresh: types.RefreshNetworks}
+	}
+}
+
+// pinImageCmd pins an image tag to its digest, reporting the digest
+// reference to use and whether the tag has moved in the registry since
+func (m *Model) pinImageCmd(imageID string) tea.Cmd {
+	return func() tea.Msg {
+		pin, err := m.docker.PinImageDigest(nil, imageID)
+		if err != nil {
+			return types.ActionErrorMsg(err.Error())
+		}
+		msg := "Pinned as " + pin.Ref + " (kept by tag " + pin.PinTag + ")"
+		if pin.Remote != "" && !strings.HasSuffix(pin.Ref, "@"+pin.Remote) {
+			msg += "; the registry tag has moved to " + docker.ShortDigest(pin.Remote)
+		}
+		return types.ActionSuccessMsg{Text: msg, Refresh: types.RefreshImages}
+	}
+}
+
+// createNetworkCmd creates a network
+func (m *Model) createNetworkCmd(spec types.NetworkSpec) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := m.docker.WithTimeout()
+		defer cancel()
+
+		if _, err := m.docker.CreateNetwork(ctx, spec); err != nil {
+			return types.ActionErrorMsg(err.Error())
+		}
+		return types.ActionSuccessMsg{Text: "Network " + spec.Name + " created", Refresh: types.RefreshNetworks}
 	}
 }
 
@@ -271,21 +1278,289 @@ func (m *Model) inspectNetworkCmd(networkID string) tea.Cmd {
 	}
 }
 
-// execContainerCmd opens an interactive shell in the container
+// execContainerCmd opens an interactive shell in the container through the
+// exec API, preferring bash; no docker CLI is needed
 func (m *Model) execContainerCmd(containerID string) tea.Cmd {
-	// Try /bin/bash first
-	c := exec.Command("docker", "exec", "-it", containerID, "/bin/bash")
+	return func() tea.Msg {
+		shell, err := m.docker.DetectShell(nil, containerID)
+		if err != nil {
+			return types.ActionErrorMsg("Failed to exec: " + err.Error())
+		}
+		session := m.docker.NewExecSession(containerID, []string{shell})
+		return tea.Exec(session, func(err error) tea.Msg {
+			if err != nil {
+				return types.ActionErrorMsg("Failed to exec: " + err.Error())
+			}
+			return nil
+		})()
+	}
+}
+
+// debugCopyCmd starts a debug copy of a stopped container and opens its
+// shell; the copy is removed once the shell exits
+func (m *Model) debugCopyCmd(containerID, containerName string) tea.Cmd {
+	return func() tea.Msg {
+		copyID, shell, err := m.docker.StartDebugCopy(nil, containerID)
+		if err != nil {
+			return types.ActionErrorMsg("Failed to start debug copy: " + err.Error())
+		}
+		session := m.docker.NewExecSession(copyID, []string{shell})
+		return tea.Exec(session, func(err error) tea.Msg {
+			return types.DebugCopyExitedMsg{CopyID: copyID, Container: containerName, Err: err}
+		})()
+	}
+}
+
+// removeDebugCopyCmd removes a debug copy after its shell exited
+func (m *Model) removeDebugCopyCmd(msg types.DebugCopyExitedMsg) tea.Cmd {
+	return func() tea.Msg {
+		removeErr := m.docker.RemoveDebugCopy(msg.CopyID)
+		switch {
+		case msg.Err != nil:
+			return types.ActionErrorMsg("Debug shell failed: " + msg.Err.Error())
+		case removeErr != nil:
+			return types.ActionErrorMsg(removeErr.Error())
+		}
+		return types.ActionSuccessMsg{Text: "Removed debug copy of " + msg.Container, Refresh: types.RefreshContainers}
+	}
+}
+
+// pagerCmd hands the logs buffer to $PAGER (less -R by default) and returns
+// to tinyd when the pager exits. The logs go through a temp file rather than
+// stdin so the pager keeps the terminal for its own input.
+func (m *Model) pagerCmd(content string) tea.Cmd {
+	f, err := os.CreateTemp("", "tinyd-logs-*.log")
+	if err != nil {
+		return func() tea.Msg { return types.ActionErrorMsg("Failed to write logs: " + err.Error()) }
+	}
+	_, err = f.WriteString(content)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return func() tea.Msg { return types.ActionErrorMsg("Failed to write logs: " + err.Error()) }
+	}
+
+	pager := strings.Fields(os.Getenv("PAGER"))
+	if len(pager) == 0 {
+		pager = []string{"less", "-R"}
+	}
+	c := exec.Command(pager[0], append(pager[1:], f.Name())...)
 	return tea.ExecProcess(c, func(err error) tea.Msg {
+		os.Remove(f.Name())
 		if err != nil {
-			// Fallback to /bin/sh if bash doesn't exist
-			c := exec.Command("docker", "exec", "-it", containerID, "/bin/sh")
-			return tea.ExecProcess(c, func(err error) tea.Msg {
-				if err != nil {
-					return types.ActionErrorMsg("Failed to exec: " + err.Error())
-				}
-				return nil
-			})()
+			return types.ActionErrorMsg("Pager failed: " + err.Error())
 		}
 		return nil
 	})
 }
+
+// openTerminalCmd opens a shell in a container in the configured external
+// terminal, preferring bash and falling back to sh. The daemon is named on
+// the command line, as the new terminal may not inherit DOCKER_HOST.
+func (m *Model) openTerminalCmd(containerID, containerName string) tea.Cmd {
+	flags := docker.CLIFlags(m.docker.Underlying().DaemonHost())
+	return func() tea.Msg {
+		argv := append(append([]string{"docker"}, flags...), "exec", "-it", containerID, "sh", "-c", "command -v bash >/dev/null && exec bash || exec sh")
+		if err := terminal.Open(m.externalTerminal, argv); err != nil {
+			return types.ActionErrorMsg(err.Error())
+		}
+		return types.ActionSuccessMsg{Text: "Opened console for " + containerName}
+	}
+}
+
+// sshHostCmd opens an interactive SSH session to the daemon host. The
+// destination is "user@host" with an optional ":port" suffix.
+func (m *Model) sshHostCmd(destination string) tea.Cmd {
+	args := []string{"-t"}
+	if host, port, err := net.SplitHostPort(destination); err == nil {
+		args = append(args, "-p", port)
+		destination = host
+	}
+	args = append(args, destination)
+
+	c := exec.Command("ssh", args...)
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		if err != nil {
+			return types.ActionErrorMsg("SSH session failed: " + err.Error())
+		}
+		return nil
+	})
+}
+
+// captureTask queues a capture of a container's traffic into a pcap file
+// in the temp directory
+func (m *Model) captureTask(container types.Container, port int, duration time.Duration) {
+	m.enqueueTask("Capture traffic of "+container.Name, func(ctx context.Context, progress func(string)) (string, error) {
+		ctx, cancel := context.WithTimeout(ctx, duration+docker.TimeoutLong)
+		defer cancel()
+
+		progress(fmt.Sprintf("capturing for %s", duration))
+		dest := filepath.Join(os.TempDir(), fmt.Sprintf("tinyd-%s-%s.pcap", container.Name, time.Now().Format("20060102-150405")))
+		if err := m.docker.CapturePackets(ctx, container.ID, port, duration, dest); err != nil {
+			return "", err
+		}
+		return "Capture saved to " + dest, nil
+	})
+}
+
+// containerClockCmd reports the clock drift and timezone of a container
+// relative to the host
+func (m *Model) containerClockCmd(containerID, containerName string) tea.Cmd {
+	return func() tea.Msg {
+		info, err := m.docker.ContainerClock(nil, containerID)
+		if err != nil {
+			return types.ActionErrorMsg(err.Error())
+		}
+		return types.ActionSuccessMsg{Text: containerName + ": " + describeClock(info, time.Now())}
+	}
+}
+
+// describeClock summarizes a container's clock against the host's, e.g.
+// "clock 3s ahead of host, zone UTC +0000 (host CET +0100)"
+func describeClock(info types.ClockInfo, now time.Time) string {
+	drift := info.Drift.Round(time.Second)
+	var clock string
+	switch {
+	case drift > time.Second:
+		clock = "clock " + drift.String() + " ahead of host"
+	case drift < -time.Second:
+		clock = "clock " + (-drift).String() + " behind host"
+	default:
+		// Drift is only measured to about a second
+		clock = "clock in sync with host"
+	}
+
+	hostZone, _ := now.Zone()
+	zone := fmt.Sprintf("zone %s %s", info.Zone, info.Offset)
+	if hostOffset := now.Format("-0700"); hostOffset != info.Offset {
+		zone += fmt.Sprintf(" (host %s %s)", hostZone, hostOffset)
+	} else {
+		zone += " (same as host)"
+	}
+	if info.TZ != "" {
+		zone += ", TZ=" + info.TZ
+	}
+	return clock + ", " + zone
+}
+
+// containerDNSCmd resolves a name, and connects to a port, from inside a
+// container
+func (m *Model) containerDNSCmd(containerID, containerName, host string, port int) tea.Cmd {
+	return func() tea.Msg {
+		check, err := m.docker.CheckDNS(nil, containerID, host, port)
+		if err != nil {
+			return types.ActionErrorMsg(err.Error())
+		}
+		summary := containerName + ": " + describeDNSCheck(check)
+		if len(check.Addresses) == 0 || (check.Port != 0 && !check.Connected) {
+			return types.ActionErrorMsg(summary)
+		}
+		return types.ActionSuccessMsg{Text: summary}
+	}
+}
+
+// describeDNSCheck summarizes a DNS check, e.g. "db is 172.18.0.3 (4ms via
+// 127.0.0.11), port 5432 open (1ms)"
+func describeDNSCheck(check types.DNSCheck) string {
+	via := check.Tool
+	if len(check.Nameservers) > 0 {
+		via = strings.Join(check.Nameservers, ", ")
+	}
+	if len(check.Addresses) == 0 {
+		return fmt.Sprintf("%s does not resolve (via %s)", check.Host, via)
+	}
+
+	summary := fmt.Sprintf("%s is %s (%s via %s)", check.Host, strings.Join(check.Addresses, ", "), check.Resolve.Round(time.Millisecond), via)
+	switch {
+	case check.Port == 0:
+	case check.Connected:
+		summary += fmt.Sprintf(", port %d open (%s)", check.Port, check.Connect.Round(time.Millisecond))
+	default:
+		summary += fmt.Sprintf(", port %d: %s", check.Port, check.ConnectErr)
+	}
+	return summary
+}
+
+// checkUpdateCmd looks up the latest tinyd release when update checks are
+// enabled; failures are silent since the check is best effort
+func (m *Model) checkUpdateCmd() tea.Cmd {
+	if !m.checkUpdates {
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		release, err := version.LatestRelease(ctx)
+		if err != nil || !version.IsNewer(release.Version, version.Version) {
+			return nil
+		}
+		return types.UpdateAvailableMsg{Version: release.Version, URL: release.URL}
+	}
+}
+
+// flashDuration is how long the screen stays in reverse video for a flash
+const flashDuration = 150 * time.Millisecond
+
+// ring signals an event as configured in TINYD_BELL, if at all
+func (m *Model) ring(event bell.Event) tea.Cmd {
+	switch m.bells.Signal(event) {
+	case bell.Ring:
+		return func() tea.Msg {
+			_, _ = os.Stdout.WriteString("\a")
+			return nil
+		}
+	case bell.Flash:
+		return func() tea.Msg {
+			_, _ = os.Stdout.WriteString(bell.FlashOn)
+			time.Sleep(flashDuration)
+			_, _ = os.Stdout.WriteString(bell.FlashOff)
+			return nil
+		}
+	}
+	return nil
+}
+
+// notifyCmd shows a desktop notification; failures are silent since the
+// status line reports the same message
+func notifyCmd(title, body string) tea.Cmd {
+	return func() tea.Msg {
+		var cmd *exec.Cmd
+		switch runtime.GOOS {
+		case "darwin":
+			script := fmt.Sprintf("display notification %q with title %q", body, title)
+			cmd = exec.Command("osascript", "-e", script)
+		case "linux":
+			cmd = exec.Command("notify-send", title, body)
+		default:
+			return nil
+		}
+		_ = cmd.Run()
+		return nil
+	}
+}
+
+// openURLCmd opens a URL in the default browser without blocking the TUI
+func openURLCmd(url string) tea.Cmd {
+	return func() tea.Msg {
+		var cmd *exec.Cmd
+		switch runtime.GOOS {
+		case "darwin":
+			cmd = exec.Command("open", url)
+		case "linux":
+			cmd = exec.Command("xdg-open", url)
+		case "windows":
+			cmd = exec.Command("cmd", "/c", "start", "", url)
+		default:
+			return types.ActionErrorMsg("Unsupported operating system")
+		}
+
+		if err := cmd.Start(); err != nil {
+			return types.ActionErrorMsg(fmt.Sprintf("Failed to open browser: %v", err))
+		}
+		go cmd.Wait()
+		return types.ActionSuccessMsg{Text: "Opening " + url}
+	}
+}
@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tinyd/internal/alerts"
+	"tinyd/internal/churn"
+	"tinyd/internal/state"
+	"tinyd/internal/types"
+)
+
+// press sends a key to the model as the terminal would
+func press(m *Model, key string) {
+	m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+}
+
+func TestMemorySortKey(t *testing.T) {
+	m := &Model{width: 120, height: 40, state: &state.State{}}
+	m.containers = []types.Container{
+		{ID: "a", Name: "small", Status: "RUNNING", MemBytes: 10},
+		{ID: "b", Name: "big", Status: "RUNNING", MemBytes: 30},
+		{ID: "c", Name: "medium", Status: "RUNNING", MemBytes: 20},
+	}
+
+	press(m, "M")
+	if want := (state.Sort{Column: "MEM", Desc: true}); m.sorts[0] != want {
+		t.Fatalf("sort after M = %+v, want %+v", m.sorts[0], want)
+	}
+	if m.containers[0].Name != "big" || m.containers[2].Name != "small" {
+		t.Errorf("containers after M = %s, %s, %s, want the biggest first", m.containers[0].Name, m.containers[1].Name, m.containers[2].Name)
+	}
+	if m.currentView != types.ViewModeList {
+		t.Errorf("M left the list for view %v", m.currentView)
+	}
+
+	press(m, "M")
+	if m.sorts[0] != (state.Sort{}) {
+		t.Errorf("sort after a second M = %+v, want the default", m.sorts[0])
+	}
+}
+
+func TestStatsDashboardKey(t *testing.T) {
+	m := &Model{width: 120, height: 40, state: &state.State{}, alerts: alerts.NewMonitor(nil)}
+	m.containers = []types.Container{{ID: "a", Name: "web", Status: "RUNNING"}}
+
+	press(m, "m")
+	if m.currentView != types.ViewModeStats || m.dashContainer.ID != "a" {
+		t.Errorf("m opened view %v for %q, want the stats dashboard of a", m.currentView, m.dashContainer.ID)
+	}
+	if m.sorts[0] != (state.Sort{}) {
+		t.Errorf("m changed the sort to %+v", m.sorts[0])
+	}
+}
+
+func TestProcessesKey(t *testing.T) {
+	m := &Model{width: 120, height: 40, state: &state.State{}}
+	m.containers = []types.Container{{ID: "a", Name: "web", Status: "RUNNING"}}
+
+	press(m, "t")
+	if m.currentView != types.ViewModeProcesses || m.topContainer.ID != "a" {
+		t.Errorf("t opened view %v for %q, want the processes of a", m.currentView, m.topContainer.ID)
+	}
+}
+
+func TestRangeMarkKey(t *testing.T) {
+	m := &Model{width: 120, height: 40, state: &state.State{}, visualAnchor: -1, marked: make(map[string]bool)}
+	m.containers = []types.Container{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+
+	press(m, "v")
+	m.handleKeyPress(tea.KeyMsg{Type: tea.KeyDown})
+	press(m, "v")
+	if marked := m.markedSet(); len(marked) != 2 || !marked["a"] || !marked["b"] {
+		t.Errorf("marked after v, down, v = %v, want a and b", marked)
+	}
+	if m.logPreview {
+		t.Error("v toggled the log preview")
+	}
+}
+
+func TestLabelFilterKey(t *testing.T) {
+	m := &Model{width: 120, height: 40, state: &state.State{}}
+	m.allContainers = []types.Container{
+		{ID: "a", Name: "api", Image: "api:1", Labels: map[string]string{"team": "core"}},
+		{ID: "b", Name: "web", Image: "web:1", Labels: map[string]string{"team": "site"}},
+	}
+	m.applyContainerFilter()
+
+	press(m, "f")
+	press(m, "label=team=")
+	m.handleKeyPress(tea.KeyMsg{Type: tea.KeyBackspace})
+	press(m, "=core")
+	m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.filterMode {
+		t.Fatalf("the filter is still open: %q", m.filterErr)
+	}
+	if len(m.containers) != 1 || m.containers[0].Name != "api" {
+		t.Errorf("containers after the label filter = %+v, want api", m.containers)
+	}
+
+	// A bad term keeps the filter open on the error
+	press(m, "f")
+	press(m, " label=")
+	m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.filterMode || m.filterErr == "" {
+		t.Error("a bad label term should keep the filter open with an error")
+	}
+	m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.filterMode || m.labelFilters[0] != "label=team=core" {
+		t.Errorf("esc left the filter open or changed it to %q", m.labelFilters[0])
+	}
+}
+
+func TestWatchOfFoldedReplica(t *testing.T) {
+	m := &Model{width: 120, height: 40, state: &state.State{}, groupReplicas: true}
+	m.containerChurn = churn.New(func(c types.Container) string { return c.ID })
+	m.watches = map[string]*watchState{"b": {containerName: "shop-web-2"}}
+	m.Update(types.ContainerListMsg{
+		{ID: "a", Name: "shop-web-1", ComposeProject: "shop", ComposeService: "web"},
+		{ID: "b", Name: "shop-web-2", ComposeProject: "shop", ComposeService: "web"},
+	})
+	if len(m.containers) != 1 {
+		t.Fatalf("grouping shows %d rows, want 1", len(m.containers))
+	}
+	if _, ok := m.watches["b"]; !ok {
+		t.Fatal("the watch of the folded replica was dropped")
+	}
+
+	press(m, "W")
+	if len(m.watches) != 0 || m.statusMessage != "Stopped watching shop-web-2" {
+		t.Errorf("W on the grouped row left %d watches, status %q", len(m.watches), m.statusMessage)
+	}
+}
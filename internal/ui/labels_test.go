@@ -0,0 +1,27 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tinyd/internal/types"
+)
+
+func TestLabelEditorTrapsGlobalKeys(t *testing.T) {
+	m := &Model{width: 120, height: 40}
+	m.selectedContainer = &types.Container{Name: "web", Labels: map[string]string{"team": "core"}}
+	m.openLabelEditor(m.selectedContainer.Labels)
+
+	m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	for _, r := range "Host=?" {
+		m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+
+	if m.labelEditInput != "Host=?" {
+		t.Errorf("labelEditInput = %q, want %q", m.labelEditInput, "Host=?")
+	}
+	if m.showHelp {
+		t.Error("H or ? toggled the help while typing a label")
+	}
+}
@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tinyd/internal/types"
+)
+
+func TestFollowedLogLines(t *testing.T) {
+	updates := make(chan tea.Msg, 4)
+	m := &Model{width: 80, height: 24, currentView: types.ViewModeLogs}
+	m.logsContent = strings.Repeat("line\n", m.logsVisibleLines()-1) + "5" // A screenful
+	m.logsFollow, m.logsFollowID, m.logsFollowUpdates = true, 2, updates
+
+	// At the bottom, new lines scroll the view and the next one is awaited
+	updates <- types.LogLineMsg{Follow: 2, Line: "7"}
+	_, cmd := m.Update(types.LogLineMsg{Follow: 2, Line: "6"})
+	if m.logsScrollOffset != 1 {
+		t.Errorf("scroll offset after a line = %d, want 1", m.logsScrollOffset)
+	}
+	if cmd == nil {
+		t.Fatal("no command awaiting the next line")
+	}
+	m.Update(cmd())
+	if !strings.HasSuffix(m.logsContent, "5\n6\n7") || m.logsScrollOffset != 2 {
+		t.Errorf("logs after two lines = %q at offset %d", m.logsContent, m.logsScrollOffset)
+	}
+
+	// Scrolled up, the view stays where it is
+	m.handleKeyPress(tea.KeyMsg{Type: tea.KeyUp})
+	m.Update(types.LogLineMsg{Follow: 2, Line: "8"})
+	if m.logsScrollOffset != 1 {
+		t.Errorf("scroll offset after scrolling up = %d, want 1", m.logsScrollOffset)
+	}
+
+	// Lines of an earlier session are dropped
+	m.Update(types.LogLineMsg{Follow: 1, Line: "old"})
+	if strings.Contains(m.logsContent, "old") {
+		t.Error("a line of an earlier follow session was shown")
+	}
+}
@@ -2,18 +2,42 @@
 package ui
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"tinyd/internal/alerts"
+	"tinyd/internal/bell"
+	"tinyd/internal/cache"
+	"tinyd/internal/churn"
 	"tinyd/internal/components"
 	"tinyd/internal/docker"
+	"tinyd/internal/eventwatch"
+	"tinyd/internal/exechistory"
+	"tinyd/internal/forward"
+	"tinyd/internal/history"
+	"tinyd/internal/hooks"
+	"tinyd/internal/notes"
+	"tinyd/internal/provision"
+	"tinyd/internal/recording"
+	"tinyd/internal/schedule"
+	"tinyd/internal/state"
+	"tinyd/internal/statshistory"
+	"tinyd/internal/tasks"
+	"tinyd/internal/terminal"
 	"tinyd/internal/types"
+	"tinyd/internal/version"
+	"tinyd/internal/workspace"
 )
 
 // Model represents the application state
 type Model struct {
 	// Docker client
 	docker *docker.Client
+	stats  *docker.StatsStreamer // Live stats for visible running containers
 
 	// Data
 	containers []types.Container
@@ -21,12 +45,34 @@ type Model struct {
 	volumes    []types.Volume
 	networks   []types.Network
 
+	// Lists of the last successful fetches, shown while the daemon is
+	// unreachable; staleSince is when they were fetched, zero while live
+	cache      *cache.Store
+	staleSince time.Time
+	staleErr   error
+
+	// Containers and images created or removed since start, marked NEW in
+	// the lists; showGone lists the removed ones below
+	containerChurn *churn.Tracker[types.Container]
+	imageChurn     *churn.Tracker[types.Image]
+	showGone       bool
+
+	// Latest live stats by container ID
+	containerStats map[string]types.ContainerStats
+
+	// Last log line preview for visible running containers
+	logPreview   bool
+	lastLogLines map[string]string // Keyed by container ID
+
 	// Navigation state
 	activeTab      int
 	selectedRow    int
 	scrollOffset   int
 	viewportHeight int
 
+	// Stats refresh interval, independent of the 5s list refresh
+	statsInterval time.Duration
+
 	// Display state
 	width  int
 	height int
@@ -46,12 +92,102 @@ type Model struct {
 
 	// Detail views
 	logsContent      string
+	logsStderr       []bool // Lines of logsContent written to stderr
 	logsScrollOffset int
 	logsSearchMode   bool
 	logsSearchQuery  string
 	inspectContent   string
 	inspectMode      int // 0=stats, 1=image, 2=mounts
 
+	// Search within the logs view: the prompt, then the active pattern
+	// whose matches are highlighted and visited with n/N
+	logsSearchInput string
+	logsSearchRegex bool
+	logsSearchErr   string
+	logsSearchRe    *regexp.Regexp
+	logsMatchLine   int    // Line of the current match
+	logsMatchInfo   string // Position among the matches, e.g. "3/17"
+
+	// Inspect export: the uncolored JSON and the prompted file path
+	inspectRaw         string
+	inspectExportMode  bool
+	inspectExportInput string
+	inspectExportMsg   string
+
+	// Security review of an inspected container, shown above its JSON
+	inspectSecurity []string
+
+	// Places in the lists to go back to, the latest last
+	navHistory []navEntry
+
+	// Logs time range (since/until window instead of the last 100 lines)
+	logsSince      time.Time
+	logsUntil      time.Time
+	logsRangeMode  bool
+	logsRangeField int // 0=since, 1=until
+	logsRangeInput [2]string
+	logsRangeErr   string
+
+	// Time range presets ("last 15m"), picked before the custom range
+	logsPresetMode   bool
+	logsPresetCursor int
+
+	// Docker's timestamps in front of each log line
+	logsTimestamps bool
+
+	// Long lines of the logs and inspect views: wrapped onto more rows, or
+	// cut to the width from the column hScroll on
+	wrapLines bool
+	hScroll   int
+
+	// Lines of history the logs view fetches (0 means all), opening with
+	// logsTailDefault (TINYD_LOG_TAIL). While more are loading, logsMoreAnchor
+	// holds the previous content, to find the old lines in the new ones.
+	logsTail        int
+	logsTailDefault int
+	logsMoreAnchor  string
+
+	// Logs follow mode: new lines stream in while the view is open
+	logsFollow        bool
+	logsFollowID      int          // Current follow session, see types.LogLineMsg
+	logsFollowUpdates chan tea.Msg // Lines and end of the current session
+
+	// Image layer browser
+	layers         []types.ImageLayer
+	layersErr      string
+	layerCursor    int
+	layerFilesOpen bool // Showing the files of the layer under the cursor
+	layerScroll    int
+	layerWasteOpen bool // Showing the waste report instead of the layers
+	layerWaste     types.ImageWaste
+
+	// Multi-select on the containers tab (Space), keyed by container ID
+	marked map[string]bool
+
+	// Multi-select on the images tab (Space), keyed by image ID
+	markedImages map[string]bool
+
+	// Multi-select on the volumes tab, keyed by name, and on the networks
+	// tab, keyed by network ID
+	markedVolumes  map[string]bool
+	markedNetworks map[string]bool
+
+	// Row of the active tab where v started marking a range, -1 when not
+	// marking one
+	visualAnchor int
+
+	// Confirmation of a start, stop or delete of the marked rows
+	bulkConfirm components.ConfirmModal
+	bulkAction  string
+	bulkTargets []bulkTarget
+
+	// Bulk env editing via recreate
+	bulkEnvTargets []types.Container
+	bulkEnvChanges []types.EnvVar
+	bulkEnvInput   string
+	bulkEnvPreview []types.EnvPreview // nil until the preview is loaded
+	bulkEnvLoading bool
+
 	// Selection state
 	selectedContainer *types.Container
 	selectedImage     *types.Image
@@ -62,16 +198,70 @@ type Model struct {
 	availablePorts  []string
 	selectedPortIdx int
 
+	// Column each tab is sorted by, remembered across restarts
+	state *state.State
+	sorts [4]state.Sort
+
+	// Clicking a table header sorts by its column (TINYD_MOUSE=1)
+	mouse          bool
+	tableHeaderRow int                      // Screen row of the list table header
+	tableHeaders   []components.TableHeader // Headers of the list table last rendered
+
+	// Fold replicas of a compose service into one row
+	groupReplicas bool
+
+	// Comfortable density: a blank line between list rows and padded modals,
+	// for small fonts and easier mouse targeting (TINYD_DENSITY)
+	comfortable bool
+
+	// Show the OCI source/revision columns on the images tab
+	showImageSource bool
+
+	// Show the registry digest column on the images tab
+	showImageDigest bool
+
+	// Optional containers table columns (TINYD_CONTAINER_COLUMNS) and
+	// whether they are shown (toggled with K)
+	containerColumns     []string
+	showContainerColumns bool
+
+	// Daemon host resources, for the Docker Desktop usage warning, the
+	// platform mismatch warning of the run modal and the host summary
+	hostInfo types.HostInfo
+	// Load of the daemon's host, nil unless it runs on this machine
+	hostLoad *types.HostLoad
+
 	// Filters
 	containerFilter int
+	allContainers   []types.Container // Unfiltered list; containers holds the shown one
 	imageFilter     int
+	allImages       []types.Image        // Unfiltered list; images holds the filtered one
+	staleDays       []int                // Presets of the "unused for N days" image filter
+	imageTagTimes   map[string]time.Time // Last tag time per image ID, for the stale filter
+	imagePlatforms  map[string]string    // Platform per image ID, "" while unknown
 	volumeFilter    int
 	networkFilter   int
 	filterOptions   []string
 	selectedFilter  int
 
+	// Containers tab filter by image repository ("" shows all), the label
+	// filter of each tab, e.g. "label=team=core label!=tmp", and the filter
+	// modal setting those of the active tab
+	containerImageFilter string
+	labelFilters         [4]string
+	filterMode           bool
+	filterChoice         int              // Containers: 0 is "all images", then filterImages; images: the preset
+	filterImages         []types.ImageUse // Images containers were created from
+	filterInput          string           // Label terms being typed
+	filterErr            string
+	allVolumes           []types.Volume  // Unfiltered list; volumes holds the shown one
+	allNetworks          []types.Network // Unfiltered list; networks holds the shown one
+
 	// Run image modal
+	runTag             string   // Tag to run; pulled first when not local
+	runTags            []string // Local tags of the same repository
 	runContainerName   string
+	runHostname        string
 	runPortHost        string
 	runPortContainer   string
 	runPorts           []types.PortMapping
@@ -80,9 +270,14 @@ type Model struct {
 	runSelectedVolume  string
 	runVolumeHost      string
 	runVolumeContainer string
+	runVolumeMode      int // Index into runVolumeModes()
 	runEnvKey          string
 	runEnvValue        string
+	runLabels          []types.Label
+	runLabelKey        string
+	runLabelValue      string
 	runModalField      int
+	runUpdates         chan tea.Msg // Pull progress and result of a pending run
 
 	// Pull image modal
 	pullImageName string
@@ -91,9 +286,294 @@ type Model struct {
 	listSearchMode  bool
 	listSearchQuery string
 
-	// Inline delete confirmation
-	deleteConfirmMode   bool
-	deleteConfirmOption int // 0=Yes, 1=No
+	// Inline delete confirmation, and the offer to delete the stopped
+	// containers of an image that couldn't be deleted
+	deleteConfirm       components.ConfirmModal
+	imageCascadeConfirm components.ConfirmModal
+	imageCascade        types.ImageInUseMsg
+
+	// Compose service whose highest-numbered replica the delete
+	// confirmation asks to remove when scaling down, nil replicas otherwise
+	scaleDownService  string
+	scaleDownReplicas []types.Container
+
+	// Bind-mount watcher (auto-restart on host file changes)
+	watches         map[string]*watchState // Keyed by container ID
+	watchTicking    bool                   // Whether the watch poll loop is running
+	watchMounts     []types.BindMount      // Mounts offered in the watch modal
+	watchSelected   []bool
+	watchCursor     int
+	watchSendSignal bool // false = restart container, true = send SIGHUP
+
+	// SSH jump to the daemon host (ssh:// contexts only)
+	sshEndpoint    string // Daemon endpoint, e.g. ssh://user@host
+	sshPromptMode  bool
+	sshPromptInput string // Editable ssh destination, prefilled from the endpoint
+
+	// Low-bandwidth mode for remote daemons: no per-container stats, a
+	// slower refresh and smaller chunks of logs
+	lowBandwidth bool
+
+	// Image reference prompt of a pull from the empty images tab
+	pullPromptMode  bool
+	pullPromptInput string
+
+	// Rollback prompt of a pull that would move a local tag: the tag to
+	// keep the current image under, empty to let the pull replace it
+	pullKeepMode  bool
+	pullKeepRef   string
+	pullKeepInput string
+
+	// Notes on containers and images, and the prompt editing the note on
+	// the object of noteKind, noteID and noteName
+	notes          *notes.Store
+	notePromptMode bool
+	noteInput      string
+	noteKind       string
+	noteID         string
+	noteName       string
+
+	// File copy prompt: source and destination paths of a copy between
+	// m.selectedContainer and the host, in the direction of fileCopyToContainer
+	fileCopyMode        bool
+	fileCopyToContainer bool
+	fileCopyField       int
+	fileCopyInput       [2]string
+
+	// Commit prompt: the image reference, comment and author of a commit of
+	// m.selectedContainer
+	commitMode  bool
+	commitField int
+	commitInput [3]string
+
+	// Update prompt: the CPU shares, CPUs, memory limit and restart policy
+	// to change m.selectedContainer to, prefilled with its current ones
+	limitsMode  bool
+	limitsField int
+	limitsInput [4]string
+
+	// Prune prompt: the filter of stopped containers to remove, e.g.
+	// until=24h
+	pruneMode  bool
+	pruneInput string
+	pruneErr   string
+
+	// Drain prompt: how long to wait for the clients of m.selectedContainer
+	// to disconnect before stopping it
+	drainMode  bool
+	drainInput string
+
+	// DNS check prompt: the target to resolve from m.selectedContainer
+	dnsPromptMode  bool
+	dnsPromptInput string
+
+	// Save prompt: the tar archive the images are written to
+	imageSaveMode    bool
+	imageSaveInput   string
+	imageSaveTargets []types.Image
+
+	// Tag cleanup: the prompt for how many tags to keep, then the preview
+	tagCleanupMode   bool
+	tagCleanupInput  string
+	tagCleanupRepo   string
+	tagCleanupPlan   types.TagCleanup
+	tagCleanupScroll int
+
+	// Create-network prompt
+	networkCreateMode  bool
+	networkCreateField int       // 0=name, 1=driver, 2=MTU, 3=parent or encryption
+	networkCreateInput [4]string // The extra field only shows for macvlan, ipvlan and overlay
+
+	// Packet capture prompt (tcpdump in a helper container)
+	captureMode  bool
+	captureField int       // 0=port, 1=seconds
+	captureInput [2]string // Port (empty = all traffic) and duration in seconds
+
+	// Volume copy prompt: target name, then the dry-run estimate to confirm
+	volumeCopyMode   bool
+	volumeCopySource string
+	volumeCopyInput  string
+	volumeCopyPlan   *types.VolumeCopyPlan
+
+	// Log search across running containers: query prompt, then the results
+	// grouped by container
+	logSearchPromptMode bool
+	logSearchInput      string
+	logSearchQuery      string
+	logSearchResults    []types.LogSearchResult
+	logSearchLoading    bool
+	logSearchCursor     int
+
+	// Background operations and the Tasks panel
+	taskQueue  *tasks.Queue
+	taskStates map[int]tasks.State // Last seen state per task, to report completions
+	taskCursor int
+
+	// Commands run around container starts and stops (hooks.json); their
+	// results reach the Messages panel through hookOutput
+	hooks      hooks.Config
+	hookOutput chan types.HookOutputMsg
+
+	// Foreground streams (followed logs, attach, events) and detach confirmation
+	streams       []foregroundStream
+	detachConfirm components.ConfirmModal
+
+	// External terminal for consoles (TINYD_TERMINAL), empty to exec in place
+	externalTerminal string
+
+	// Stats recording to CSV, nil when not recording
+	recorder      *recording.Recorder
+	recordTicking bool
+
+	// Scheduled start/stop/restart actions and the Schedules panel
+	schedules          schedule.List
+	scheduleTicking    bool
+	scheduleCursor     int
+	schedulePromptMode bool
+	scheduleInput      string
+	scheduleErr        string
+	scheduleTarget     types.Container
+
+	// Localhost ports proxied to containers and the Forwards panel
+	forwards          forward.Manager
+	forwardCursor     int
+	forwardPromptMode bool
+	forwardInput      string
+	forwardErr        string
+	forwardTarget     types.Container
+
+	// Status messages shown this session and the Messages panel
+	messages       history.Log
+	messagesScroll int
+
+	// Aggregated logs view: the names of the followed containers and their
+	// merged lines, oldest first
+	aggSources  []string
+	aggLines    []types.AggregatedLogLine
+	aggScroll   int
+	aggFollow   bool
+	aggFollowID int          // Current session, see types.AggregatedLogLineMsg
+	aggUpdates  chan tea.Msg // Tail, lines and end of the current session
+	aggSplit    bool         // Two containers side by side rather than interleaved
+
+	// Health view: the healthcheck probes of the inspected container
+	healthLog    types.HealthLog
+	healthLoaded bool
+	healthErr    string
+	healthScroll int
+
+	// Env view: the environment of the inspected container, with secret
+	// looking values masked until revealed
+	envVars      []types.EnvVar
+	envLoaded    bool
+	envErr       string
+	envCursor    int
+	envRevealed  map[string]bool // By key
+	envRevealAll bool
+	envMsg       string // Result of the last copy
+
+	// Provisioning report: peak usage seen in the stats this session,
+	// compared with what the running containers are given
+	peaks           *provision.Tracker
+	provisionRows   []provision.Row
+	provisionLoaded bool
+	provisionErr    string
+	provisionCursor int
+	provisionMsg    string // Result of the last export
+
+	// Labels editor: the labels of the inspected container as they were and
+	// as edited, applied by recreating the container after a confirmation
+	labelEditBefore  map[string]string
+	labelEditAfter   map[string]string
+	labelEditCursor  int
+	labelEditMode    bool // Typing KEY=VALUE
+	labelEditInput   string
+	labelEditKey     string // Key being edited, "" when adding one
+	labelEditErr     string
+	labelEditConfirm bool
+
+	// One-shot exec: the command prompt with its per-container history,
+	// browsed with up/down, and the output view of the last command
+	execHistory    *exechistory.Store
+	execPromptMode bool
+	execInput      string
+	execCommands   []string // History of execContainer, oldest first
+	execHistoryPos int      // Index into execCommands, len for the new line
+	execDraft      string   // New line typed before browsing the history
+	execContainer  types.Container
+	execRunning    bool
+	execResult     types.ExecResultMsg
+	execScroll     int
+
+	// Exec with options: the command, user, workdir and env of an interactive
+	// exec in execContainer, prefilled with the last ones used there
+	execOptsMode  bool
+	execOptsField int
+	execOptsInput [4]string
+
+	// Events view: the daemon's event stream through a filter, optionally
+	// replayed from a time, and the saved watches the filter is picked from
+	eventWatches   *eventwatch.Store
+	events         []types.DockerEvent
+	eventsScroll   int
+	eventsFollow   bool
+	eventsFollowID int          // Current session, see types.EventMsg
+	eventsUpdates  chan tea.Msg // Events and end of the current session
+	eventsFilter   string       // `docker events --filter` terms
+	eventsSince    time.Time    // Zero for new events only
+	eventsWatch    string       // Watch the filter was picked from
+	eventsPrompt   int          // eventsPrompt*
+	eventsInput    string
+	eventsErr      string
+
+	// Saved workspaces and their picker, which saves the list view it was
+	// opened from under the name typed in its prompt
+	workspaces          *workspace.Store
+	workspaceCursor     int
+	workspacePromptMode bool
+	workspaceInput      string
+	workspaceErr        string
+
+	// Processes view: the container whose processes are listed, refreshed
+	// every stats interval while open
+	topContainer types.Container
+	topProcesses []types.Process
+	topLoaded    bool
+	topErr       string
+	topScroll    int
+
+	// Stats dashboard: graphs of a container's usage, sampled every second
+	// from its stats stream; the history is kept when the same container's
+	// dashboard is opened again
+	dashContainer types.Container
+	dashHistory   statshistory.Ring
+
+	// Container whose related containers the Related panel lists
+	relatedTo     types.Container
+	relatedScroll int
+
+	// Diagnosis of the last container that failed to start
+	startFailure types.StartFailedMsg
+
+	// Usage alerts (TINYD_ALERTS), checked on every stats sample
+	alerts      *alerts.Monitor
+	alertNotify bool // Also send desktop notifications (TINYD_ALERT_NOTIFY=1)
+
+	// Column picked for widening on each tab, see adjustColumns
+	columnWidths [4]columnWidth
+
+	// Lists changed by finished actions, fetched together once the refresh
+	// debounce window ends
+	pendingRefresh   types.Refresh
+	refreshScheduled bool
+
+	// Events that ring the bell or flash the screen (TINYD_BELL)
+	bells bell.Config
+
+	// Update check (opt-in with TINYD_CHECK_UPDATES=1)
+	checkUpdates  bool
+	updateVersion string // Newer release version, empty if none
+	updateURL     string
 
 	// Components
 	header     components.HeaderComponent
@@ -102,6 +582,95 @@ type Model struct {
 	detailView components.DetailViewComponent
 }
 
+// Run image modal fields, in tab order
+const (
+	runFieldTag = iota
+	runFieldContainerName
+	runFieldHostname
+	runFieldPortHost
+	runFieldPortContainer
+	runFieldVolumeHost
+	runFieldVolumeContainer
+	runFieldVolumeMode
+	runFieldEnvKey
+	runFieldEnvValue
+	runFieldLabelKey
+	runFieldLabelValue
+)
+
+// logTailEnvVar sets how many lines of history the logs view opens with
+const logTailEnvVar = "TINYD_LOG_TAIL"
+
+// hScrollStep is how many columns ← and → scroll long lines
+const hScrollStep = 20
+
+// logsMoreStep is how many more lines of history `m` loads in the logs view
+const logsMoreStep = 1000
+
+// refreshInterval is how often the lists are refreshed
+const refreshInterval = 5 * time.Second
+
+// In low-bandwidth mode the lists are refreshed less often, and the logs
+// view opens with and loads fewer lines at a time, unless TINYD_LOG_TAIL
+// asks for more
+const (
+	lowBandwidthRefresh  = 30 * time.Second
+	lowBandwidthLogTail  = 50
+	lowBandwidthLogsMore = 200
+)
+
+// containerColumnsEnvVar picks the optional columns of the containers
+// table, e.g. "uptime,net"; when set they show from the start
+const containerColumnsEnvVar = "TINYD_CONTAINER_COLUMNS"
+
+// mouseEnvVar turns on mouse reporting when "1", for clicking the table
+// headers; it is off by default to keep the terminal's own text selection
+const mouseEnvVar = "TINYD_MOUSE"
+
+// densityEnvVar selects the display density, "comfortable" or the default
+// compact
+const densityEnvVar = "TINYD_DENSITY"
+
+// navHistoryMax is how many places back navigation remembers
+const navHistoryMax = 20
+
+// navEntry is a place in the lists that Esc from a detail view or
+// backspace returns to. The selected row is found again by ID, as the
+// lists may have been refreshed and reordered in between.
+type navEntry struct {
+	tab         int
+	id          string // ID (or name, for volumes) of the selected row
+	row         int    // Selected row, when the ID is gone
+	scroll      int
+	imageFilter string // Image the containers tab was filtered to
+}
+
+// bulkTarget is a row a bulk action applies to: a container, image or
+// network ID, or a volume name
+type bulkTarget struct {
+	id   string
+	name string
+}
+
+// foregroundStream is a long-lived stream the user is attached to (followed
+// logs, attach sessions, event streams). q/Ctrl+C detach from these before
+// tinyd is allowed to exit.
+type foregroundStream struct {
+	name   string
+	cancel context.CancelFunc
+}
+
+// watchState tracks a container whose bind-mounted paths are being watched
+type watchState struct {
+	containerName string
+	paths         []string
+	sendSignal    bool   // Send SIGHUP instead of restarting
+	fingerprint   uint64 // Last observed fingerprint of the paths
+	primed        bool   // Whether an initial fingerprint has been taken
+	dirty         bool   // Change seen, waiting for the paths to settle
+	polling       bool   // A fingerprint is currently being computed
+}
+
 // NewModel creates an initial model with default state
 func NewModel() (*Model, error) {
 	// Create Docker client
@@ -110,6 +679,34 @@ func NewModel() (*Model, error) {
 		return nil, err
 	}
 
+	alertRules, err := alerts.ParseRules(os.Getenv(alerts.EnvVar))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", alerts.EnvVar, err)
+	}
+	bells, err := bell.Parse(os.Getenv(bell.EnvVar))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", bell.EnvVar, err)
+	}
+	hookConfig, err := hooks.Load()
+	if err != nil {
+		return nil, fmt.Errorf("hooks: %w", err)
+	}
+
+	// Tables sort as they did in the last run
+	uiState := state.Open()
+	var sorts [4]state.Sort
+	for i, table := range sortTables {
+		sorts[i] = uiState.Sort(table)
+	}
+
+	// Remote daemons over ssh get the low-bandwidth mode unless turned off
+	lowBandwidth := docker.LowBandwidth(docker.Endpoint(), os.Getenv(docker.LowBandwidthEnvVar))
+	dockerClient.SetLowBandwidth(lowBandwidth)
+	logsTail := parseLogTail(os.Getenv(logTailEnvVar))
+	if lowBandwidth && os.Getenv(logTailEnvVar) == "" {
+		logsTail = lowBandwidthLogTail
+	}
+
 	// Initialize tab items
 	tabs := []components.TabItem{
 		{Name: "Containers", Shortcut: "^D"},
@@ -120,6 +717,15 @@ func NewModel() (*Model, error) {
 
 	return &Model{
 		docker:         dockerClient,
+		cache:          cache.Open(dockerClient.Underlying().DaemonHost()),
+		notes:          notes.Open(dockerClient.Underlying().DaemonHost()),
+		execHistory:    exechistory.Open(dockerClient.Underlying().DaemonHost()),
+		workspaces:     workspace.Open(),
+		eventWatches:   eventwatch.Open(),
+		containerChurn: churn.New(func(c types.Container) string { return c.ID }),
+		imageChurn:     churn.New(func(img types.Image) string { return img.ID + " " + img.Repository + ":" + img.Tag }),
+		stats:          dockerClient.NewStatsStreamer(),
+		statsInterval:  2 * time.Second,
 		activeTab:      0,
 		selectedRow:    0,
 		scrollOffset:   0,
@@ -130,22 +736,154 @@ func NewModel() (*Model, error) {
 		currentView:    types.ViewModeList,
 
 		// Initialize components
-		header:     components.NewHeaderComponent("tinyd v2.0.1", "[F1] Help [Q]uit"),
+		header:     components.NewHeaderComponent("tinyd v"+version.Version, "[F1] Help [Q]uit"),
 		tabs:       components.NewTabsComponent(tabs, 0),
 		actionBar:  components.NewActionBarComponent(),
 		detailView: components.NewDetailViewComponent("", 15),
 
 		// Initialize slices
-		containers: []types.Container{},
-		images:     []types.Image{},
-		volumes:    []types.Volume{},
-		networks:   []types.Network{},
-		runPorts:   []types.PortMapping{},
-		runVolumes: []types.VolumeMapping{},
-		runEnvVars: []types.EnvVar{},
+		containers:     []types.Container{},
+		images:         []types.Image{},
+		volumes:        []types.Volume{},
+		networks:       []types.Network{},
+		runPorts:       []types.PortMapping{},
+		runVolumes:     []types.VolumeMapping{},
+		runEnvVars:     []types.EnvVar{},
+		watches:        make(map[string]*watchState),
+		marked:         make(map[string]bool),
+		markedImages:   make(map[string]bool),
+		markedVolumes:  make(map[string]bool),
+		markedNetworks: make(map[string]bool),
+		visualAnchor:   -1,
+
+		sshEndpoint:   docker.Endpoint(),
+		groupReplicas: true,
+		comfortable:   os.Getenv(densityEnvVar) == "comfortable",
+		state:         uiState,
+		sorts:         sorts,
+		mouse:         os.Getenv(mouseEnvVar) == "1",
+
+		logsTailDefault: logsTail,
+		lowBandwidth:    lowBandwidth,
+
+		taskQueue:      tasks.NewQueue(2),
+		taskStates:     make(map[int]tasks.State),
+		staleDays:      parseStaleDays(os.Getenv("TINYD_STALE_DAYS")),
+		imageTagTimes:  make(map[string]time.Time),
+		imagePlatforms: make(map[string]string),
+
+		containerColumns:     parseContainerColumns(os.Getenv(containerColumnsEnvVar)),
+		showContainerColumns: os.Getenv(containerColumnsEnvVar) != "",
+
+		alerts:      alerts.NewMonitor(alertRules),
+		alertNotify: os.Getenv(alerts.NotifyEnvVar) == "1",
+		bells:       bells,
+
+		checkUpdates:     os.Getenv("TINYD_CHECK_UPDATES") == "1",
+		externalTerminal: os.Getenv(terminal.EnvVar),
+		hooks:            hookConfig,
+		hookOutput:       make(chan types.HookOutputMsg, 16),
+
+		containerStats: make(map[string]types.ContainerStats),
+		peaks:          provision.NewTracker(),
+		lastLogLines:   make(map[string]string),
 	}, nil
 }
 
+// columnWidth is a column widened at runtime: focus is its index in the
+// table headers (0, the status column, when none is picked), extra the
+// cells it gained and count the columns of the table last rendered
+type columnWidth struct {
+	focus, extra, count int
+}
+
+// columnWidthStep is how many cells ] and [ widen or narrow a column by
+const columnWidthStep = 4
+
+// pageSize returns how many list rows fit on screen; log preview sublines
+// take a second line per row on the containers tab, and comfortable density
+// a blank line between rows
+func (m *Model) pageSize() int {
+	height := m.viewportHeight
+	// The cached data or Docker Desktop warning banner takes a line
+	if m.listWarning() != "" {
+		height--
+	}
+	// So does the host summary above the containers
+	if m.activeTab == 0 && m.hostInfo.NCPU > 0 {
+		height--
+	}
+	// And the active label filter
+	if m.labelFilters[m.activeTab] != "" {
+		height--
+	}
+	height -= m.goneLines()
+	rowLines := 1 + m.rowSpacing()
+	if m.activeTab == 0 && m.logPreview && !m.logPreviewWide() {
+		rowLines++
+	}
+	// The last row has no spacing after it
+	return max((height+m.rowSpacing())/rowLines, 3)
+}
+
+// maxGoneShown bounds the removed items listed below the containers and
+// images tables
+const maxGoneShown = 5
+
+// goneLines returns the lines the removed items take below the table: a
+// title and up to maxGoneShown items
+func (m *Model) goneLines() int {
+	if !m.showGone {
+		return 0
+	}
+	var n int
+	switch m.activeTab {
+	case 0:
+		n = len(m.containerChurn.Removed())
+	case 1:
+		n = len(m.imageChurn.Removed())
+	}
+	if n == 0 {
+		return 0
+	}
+	return 1 + min(n, maxGoneShown)
+}
+
+// rowSpacing returns the blank lines between list rows for the density
+func (m *Model) rowSpacing() int {
+	if m.comfortable {
+		return 1
+	}
+	return 0
+}
+
+// modalPadding returns the blank lines above and below a modal's body and
+// the indent of its lines for the density
+func (m *Model) modalPadding() (int, string) {
+	if m.comfortable {
+		return 1, "  "
+	}
+	return 0, ""
+}
+
+// logPreviewWide reports whether the log preview fits as a table column
+// instead of a line under each row
+func (m *Model) logPreviewWide() bool {
+	return m.width >= 160
+}
+
+// Close releases background resources. Quitting calls it, so it only
+// needs calling when the program exits some other way.
+func (m *Model) Close() error {
+	m.stopRecording()
+	m.detachStreams()
+	m.stats.Close()
+	if m.docker == nil {
+		return nil
+	}
+	return m.docker.Close()
+}
+
 // Init initializes the model and fetches initial data
 func (m *Model) Init() tea.Cmd {
 	return tea.Batch(
@@ -153,7 +891,38 @@ func (m *Model) Init() tea.Cmd {
 		m.fetchImagesCmd(),
 		m.fetchVolumesCmd(),
 		m.fetchNetworksCmd(),
-		tickCmd(),
+		tickCmd(m.refreshEvery()),
+		statsTickCmd(m.statsInterval),
 		animationTickCmd(),
+		m.checkUpdateCmd(),
+		m.hostInfoCmd(),
+		m.waitForTasksCmd(),
+		m.waitForHooksCmd(),
+		m.mouseCmd(),
 	)
 }
+
+// refreshEvery returns how often the lists are refreshed
+func (m *Model) refreshEvery() time.Duration {
+	if m.lowBandwidth {
+		return lowBandwidthRefresh
+	}
+	return refreshInterval
+}
+
+// logsMoreLines returns how many more lines of history `m` loads in the
+// logs view
+func (m *Model) logsMoreLines() int {
+	if m.lowBandwidth {
+		return lowBandwidthLogsMore
+	}
+	return logsMoreStep
+}
+
+// mouseCmd turns on mouse reporting when enabled
+func (m *Model) mouseCmd() tea.Cmd {
+	if !m.mouse {
+		return nil
+	}
+	return tea.EnableMouseCellMotion
+}
@@ -0,0 +1,27 @@
+package ui
+
+import (
+	"testing"
+
+	"tinyd/internal/state"
+	"tinyd/internal/types"
+)
+
+func TestSortKeepsFullList(t *testing.T) {
+	m := &Model{activeTab: 2, state: &state.State{}}
+	m.allVolumes = []types.Volume{{Name: "b"}, {Name: "c"}, {Name: "a"}}
+	m.applyVolumeFilter()
+
+	m.setSort(state.Sort{Column: "NAME"})
+	if got := m.volumes[0].Name + m.volumes[1].Name + m.volumes[2].Name; got != "abc" {
+		t.Errorf("volumes sorted by name = %s, want abc", got)
+	}
+	if got := m.allVolumes[0].Name + m.allVolumes[1].Name + m.allVolumes[2].Name; got != "bca" {
+		t.Errorf("all volumes = %s after sorting, want them in listed order bca", got)
+	}
+
+	m.setSort(state.Sort{})
+	if got := m.volumes[0].Name + m.volumes[1].Name + m.volumes[2].Name; got != "bca" {
+		t.Errorf("volumes in the default order = %s, want bca", got)
+	}
+}
@@ -1,14 +1,53 @@
 package ui
 
 import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/termenv"
+	"tinyd/internal/alerts"
+	"tinyd/internal/bell"
+	"tinyd/internal/components"
+	"tinyd/internal/docker"
+	"tinyd/internal/eventwatch"
+	"tinyd/internal/exechistory"
+	"tinyd/internal/forward"
+	"tinyd/internal/notes"
+	"tinyd/internal/provision"
+	"tinyd/internal/recording"
+	"tinyd/internal/schedule"
+	"tinyd/internal/state"
+	"tinyd/internal/tasks"
 	"tinyd/internal/types"
+	"tinyd/internal/workspace"
 )
 
-// Update handles all state transitions
+// Update handles all state transitions, recording each new status message
+// for the Messages panel
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	before := m.statusMessage
+	model, cmd := m.update(msg)
+	if m.statusMessage != before {
+		m.messages.Add(time.Now(), m.statusMessage)
+		if strings.HasPrefix(m.statusMessage, "ERROR: ") {
+			cmd = tea.Batch(cmd, m.ring(bell.Error))
+		}
+	}
+	return model, cmd
+}
+
+// update handles a message
+func (m *Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		return m.handleKeyPress(msg)
@@ -16,26 +55,75 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		return m.handleResize(msg)
 
+	case tea.MouseMsg:
+		m.handleMouse(msg)
+		return m, nil
+
 	case types.ContainerListMsg:
-		m.containers = msg
+		// Drop watches and marks of containers that no longer exist
+		existing := make(map[string]bool, len(msg))
+		for _, c := range msg {
+			existing[c.ID] = true
+		}
+		m.allContainers = msg
+		m.containerChurn.Update(msg, time.Now())
+		m.applyContainerFilter()
 		m.loading = false
+		m.staleSince, m.staleErr = time.Time{}, nil
 		m.actionInProgress = false
-		// Keep selection in bounds
-		if m.activeTab == 0 && m.selectedRow >= len(m.containers) && len(m.containers) > 0 {
-			m.selectedRow = len(m.containers) - 1
+		m.applyContainerStats()
+		for id := range m.watches {
+			if !existing[id] {
+				delete(m.watches, id)
+			}
+		}
+		for id := range m.marked {
+			if !existing[id] {
+				delete(m.marked, id)
+			}
 		}
 		return m, nil
 
 	case types.ImageListMsg:
-		m.images = msg
-		// Keep selection in bounds
-		if m.activeTab == 1 && m.selectedRow >= len(m.images) && len(m.images) > 0 {
-			m.selectedRow = len(m.images) - 1
+		m.allImages = msg
+		m.imageChurn.Update(msg, time.Now())
+		existing := make(map[string]bool, len(msg))
+		for _, img := range msg {
+			existing[img.ID] = true
+		}
+		for id := range m.markedImages {
+			if !existing[id] {
+				delete(m.markedImages, id)
+			}
+		}
+		m.applyImageFilter()
+		return m, tea.Batch(m.imageTagTimesCmd(), m.imagePlatformsCmd())
+
+	case types.ImagePlatformsMsg:
+		for id, platform := range msg {
+			m.imagePlatforms[id] = platform
 		}
 		return m, nil
 
+	case types.ImageTagTimesMsg:
+		for id, tagged := range msg {
+			m.imageTagTimes[id] = tagged
+		}
+		m.applyImageFilter()
+		return m, nil
+
 	case types.VolumeListMsg:
-		m.volumes = msg
+		m.allVolumes = msg
+		m.applyVolumeFilter()
+		existing := make(map[string]bool, len(msg))
+		for _, vol := range msg {
+			existing[vol.Name] = true
+		}
+		for name := range m.markedVolumes {
+			if !existing[name] {
+				delete(m.markedVolumes, name)
+			}
+		}
 		// Keep selection in bounds
 		if m.activeTab == 2 && m.selectedRow >= len(m.volumes) && len(m.volumes) > 0 {
 			m.selectedRow = len(m.volumes) - 1
@@ -43,7 +131,17 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case types.NetworkListMsg:
-		m.networks = msg
+		m.allNetworks = msg
+		m.applyNetworkFilter()
+		existing := make(map[string]bool, len(msg))
+		for _, net := range msg {
+			existing[net.ID] = true
+		}
+		for id := range m.markedNetworks {
+			if !existing[id] {
+				delete(m.markedNetworks, id)
+			}
+		}
 		// Keep selection in bounds
 		if m.activeTab == 3 && m.selectedRow >= len(m.networks) && len(m.networks) > 0 {
 			m.selectedRow = len(m.networks) - 1
@@ -51,29 +149,302 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case types.ErrMsg:
-		m.err = error(msg)
 		m.loading = false
 		m.actionInProgress = false
+		// Keep showing the last lists, marked as cached, while the daemon is
+		// unreachable; the periodic refresh goes live again once it answers
+		if !m.showCached(error(msg)) {
+			m.err = error(msg)
+		}
 		return m, nil
 
+	case types.ContainerPausedMsg:
+		m.actionInProgress = false
+		if msg.Err != nil {
+			m.statusMessage = "ERROR: " + msg.Err.Error()
+			return m, nil
+		}
+		// Show the new state right away rather than at the next refresh
+		status, verb := "RUNNING", "unpaused"
+		if msg.Paused {
+			status, verb = "PAUSED", "paused"
+		}
+		for _, list := range [][]types.Container{m.containers, m.allContainers} {
+			for i := range list {
+				if list[i].ID == msg.ContainerID {
+					list[i].Status = status
+				}
+			}
+		}
+		m.statusMessage = "Container " + msg.Name + " " + verb
+		return m, m.scheduleRefresh(types.RefreshContainers)
+
 	case types.ActionSuccessMsg:
+		m.statusMessage = msg.Text
+		m.actionInProgress = false
+		return m, m.scheduleRefresh(msg.Refresh)
+
+	case types.RefreshDueMsg:
+		return m, m.fetchPendingCmd()
+
+	case types.PullProgressMsg:
 		m.statusMessage = string(msg)
+		return m, waitForUpdate(m.runUpdates)
+
+	case types.DebugCopyExitedMsg:
+		return m, m.removeDebugCopyCmd(msg)
+
+	case types.ImageInUseMsg:
+		m.actionInProgress = false
+		m.openImageCascadeConfirm(msg)
+		return m, nil
+
+	case types.TasksChangedMsg:
+		return m, tea.Batch(m.handleTasksChanged(), m.waitForTasksCmd())
+
+	case types.ForwardStartedMsg:
+		if msg.Err != nil {
+			m.forwardErr = msg.Err.Error()
+			m.statusMessage = "ERROR: " + m.forwardErr
+			return m, nil
+		}
+		m.forwardErr = ""
+		m.forwardCursor = len(m.forwards.List()) - 1
+		m.statusMessage = "Forwarding " + msg.Text
+		return m, nil
+
+	case types.HookOutputMsg:
+		m.messages.Add(time.Now(), hookMessage(msg))
+		return m, m.waitForHooksCmd()
+
+	case types.VolumeCopyPlanMsg:
 		m.actionInProgress = false
-		// Refresh data after successful action
-		return m, m.fetchContainersCmd()
+		if !m.volumeCopyMode {
+			return m, nil
+		}
+		if msg.Err != nil {
+			m.statusMessage = "ERROR: " + msg.Err.Error()
+			return m, nil
+		}
+		m.statusMessage = ""
+		m.volumeCopyPlan = &msg.Plan
+		return m, nil
 
 	case types.ActionErrorMsg:
 		m.statusMessage = "ERROR: " + string(msg)
 		m.actionInProgress = false
 		return m, nil
 
+	case types.StartFailedMsg:
+		m.statusMessage = "ERROR: " + msg.Container + ": " + msg.Failure.Cause
+		m.actionInProgress = false
+		m.startFailure = msg
+		m.currentView = types.ViewModeStartFailure
+		return m, nil
+
+	case types.LogLineMsg:
+		if !m.logsFollow || msg.Follow != m.logsFollowID {
+			return m, nil
+		}
+		m.appendLogLine(msg.Line)
+		return m, waitForUpdate(m.logsFollowUpdates)
+
+	case types.ProcessesMsg:
+		if m.currentView != types.ViewModeProcesses || msg.ContainerID != m.topContainer.ID {
+			return m, nil
+		}
+		m.topLoaded = true
+		m.topErr = ""
+		if msg.Err != nil {
+			// Keep the last list; the container may have just stopped
+			m.topErr = msg.Err.Error()
+		} else {
+			m.topProcesses = msg.Processes
+		}
+		return m, processesTickCmd(m.statsInterval, msg.ContainerID)
+
+	case types.ProcessesTickMsg:
+		if m.currentView != types.ViewModeProcesses || msg.ContainerID != m.topContainer.ID {
+			return m, nil
+		}
+		return m, m.containerProcessesCmd(msg.ContainerID)
+
+	case types.StatsDashboardTickMsg:
+		if m.currentView != types.ViewModeStats || msg.ContainerID != m.dashContainer.ID {
+			return m, nil
+		}
+		if stats, ok := m.stats.Latest(msg.ContainerID); ok {
+			m.dashHistory.Add(msg.Time, stats)
+		}
+		return m, dashboardTickCmd(msg.ContainerID)
+
+	case types.AggregatedLogsMsg:
+		if !m.aggFollow || msg.Follow != m.aggFollowID {
+			return m, nil
+		}
+		if msg.Err != nil {
+			m.statusMessage = "ERROR: " + msg.Err.Error()
+		}
+		m.aggLines = append(msg.Lines, m.aggLines...)
+		m.aggScroll = max(len(m.aggLines)-m.aggVisibleLines(), 0)
+		return m, waitForUpdate(m.aggUpdates)
+
+	case types.AggregatedLogLineMsg:
+		if !m.aggFollow || msg.Follow != m.aggFollowID {
+			return m, nil
+		}
+		m.appendAggLine(msg.Line)
+		return m, waitForUpdate(m.aggUpdates)
+
+	case types.AggregatedLogsEndMsg:
+		if msg.Follow == m.aggFollowID {
+			m.stopAggregatedLogs()
+			if msg.Err != nil {
+				m.appendAggLine(types.AggregatedLogLine{Source: -1, Line: "── follow stopped: " + msg.Err.Error() + " ──"})
+			}
+		}
+		return m, nil
+
+	case types.ResourceLimitsMsg:
+		if m.selectedContainer == nil || msg.ContainerID != m.selectedContainer.ID || m.modalOpen() {
+			return m, nil
+		}
+		if msg.Err != nil {
+			m.statusMessage = "ERROR: " + msg.Err.Error()
+			return m, nil
+		}
+		m.statusMessage = ""
+		m.limitsInput = [4]string{msg.Limits.CPUShares, msg.Limits.CPUs, msg.Limits.Memory, msg.Limits.Restart}
+		m.limitsField = 0
+		m.limitsMode = true
+		return m, nil
+
+	case types.ContainerEnvMsg:
+		if m.currentView != types.ViewModeEnv || m.selectedContainer == nil || msg.ContainerID != m.selectedContainer.ID {
+			return m, nil
+		}
+		m.envLoaded = true
+		m.envErr = ""
+		if msg.Err != nil {
+			m.envErr = msg.Err.Error()
+		} else {
+			m.envVars = msg.Env
+		}
+		return m, nil
+
+	case types.AllocationsMsg:
+		if m.currentView != types.ViewModeProvision {
+			return m, nil
+		}
+		m.provisionLoaded = true
+		m.provisionErr = ""
+		if msg.Err != nil {
+			m.provisionErr = msg.Err.Error()
+			return m, nil
+		}
+		m.provisionRows = m.provisionRows[:0]
+		for id, alloc := range msg.Allocations {
+			m.provisionRows = append(m.provisionRows, provision.Assess(msg.Names[id], alloc, m.peaks.Peak(id)))
+		}
+		provision.Sort(m.provisionRows)
+		return m, nil
+
+	case types.HealthLogMsg:
+		if m.currentView != types.ViewModeHealth || m.selectedContainer == nil || msg.ContainerID != m.selectedContainer.ID {
+			return m, nil
+		}
+		m.healthLoaded = true
+		m.healthErr = ""
+		if msg.Err != nil {
+			m.healthErr = msg.Err.Error()
+		} else {
+			m.healthLog = msg.Log
+		}
+		return m, nil
+
+	case types.ExecResultMsg:
+		if msg.ContainerID != m.execContainer.ID || msg.Command != m.execResult.Command {
+			return m, nil
+		}
+		m.execRunning = false
+		m.execResult = msg
+		m.execScroll = 0
+		return m, nil
+
+	case types.EventMsg:
+		if !m.eventsFollow || msg.Follow != m.eventsFollowID {
+			return m, nil
+		}
+		m.appendEvent(msg.Event)
+		return m, waitForUpdate(m.eventsUpdates)
+
+	case types.EventsEndMsg:
+		if msg.Follow == m.eventsFollowID {
+			m.stopEvents()
+			if msg.Err != nil {
+				m.eventsErr = msg.Err.Error()
+			}
+		}
+		return m, nil
+
+	case types.LogFollowEndMsg:
+		if msg.Follow == m.logsFollowID {
+			// Still following means the stream ended on its own, as it
+			// does when the container stops
+			var cmd tea.Cmd
+			if m.logsFollow {
+				cmd = m.ring(bell.Exit)
+			}
+			m.stopLogsFollow()
+			if msg.Err != nil {
+				m.appendLogLine("── follow stopped: " + msg.Err.Error() + " ──")
+			}
+			return m, cmd
+		}
+		return m, nil
+
 	case types.LogsMsg:
-		m.logsContent = string(msg)
+		m.logsContent = msg.Logs
+		m.logsStderr = msg.Stderr
+		if m.logsContent == "" {
+			// Keep an empty window distinguishable from "still loading"
+			m.logsContent = " No log lines"
+		}
+		if m.logsMoreAnchor != "" {
+			m.keepLogsPosition()
+			return m, nil
+		}
+		m.scrollToLastMatch()
+		return m, nil
+
+	case types.LogSearchMsg:
+		if msg.Query != m.logSearchQuery {
+			return m, nil
+		}
+		m.logSearchLoading = false
+		m.logSearchResults = msg.Results
+		m.logSearchCursor = 0
 		return m, nil
 
 	case types.InspectMsg:
 		// Show prettified JSON with jq-style color coding
+		m.inspectRaw = string(msg)
 		m.inspectContent = colorizeJSON(string(msg))
+		m.inspectSecurity = nil
+		if m.activeTab == 0 {
+			m.inspectSecurity = docker.SecuritySummary(m.inspectRaw)
+		}
+		return m, nil
+
+	case types.ImageLayersMsg:
+		if m.currentView != types.ViewModeLayers {
+			return m, nil
+		}
+		if msg.Err != nil {
+			m.layersErr = msg.Err.Error()
+			return m, nil
+		}
+		m.layers = msg.Layers
 		return m, nil
 
 	case types.TickMsg:
@@ -84,10 +455,123 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.fetchImagesCmd(),
 				m.fetchVolumesCmd(),
 				m.fetchNetworksCmd(),
-				tickCmd(),
+				m.refreshLogPreviewCmd(),
+				tickCmd(m.refreshEvery()),
 			)
 		}
-		return m, tickCmd()
+		return m, tickCmd(m.refreshEvery())
+
+	case types.StatsTickMsg:
+		// The host summary shows with the containers only
+		var hostLoad tea.Cmd
+		if m.activeTab == 0 && m.hostInfo.NCPU > 0 {
+			hostLoad = m.hostLoadCmd()
+		}
+		return m, tea.Batch(
+			m.syncStatsCmd(m.streamedContainerIDs()),
+			hostLoad,
+			statsTickCmd(m.statsInterval),
+		)
+
+	case types.HostInfoMsg:
+		m.hostInfo = types.HostInfo(msg)
+		return m, nil
+
+	case types.HostLoadMsg:
+		load := types.HostLoad(msg)
+		m.hostLoad = &load
+		return m, nil
+
+	case types.BulkEnvPreviewMsg:
+		if m.currentView != types.ViewModeBulkEnv || !m.bulkEnvLoading {
+			return m, nil
+		}
+		m.bulkEnvLoading = false
+		m.bulkEnvPreview = msg
+		return m, nil
+
+	case types.UpdateAvailableMsg:
+		m.updateVersion = msg.Version
+		m.updateURL = msg.URL
+		return m, nil
+
+	case types.LastLogLinesMsg:
+		if m.logPreview {
+			m.lastLogLines = msg
+		}
+		return m, nil
+
+	case types.ScheduleTickMsg:
+		for _, entry := range m.schedules.Due(time.Time(msg)) {
+			m.scheduledActionTask(entry)
+		}
+		// Stop the tick once nothing is left to run
+		if len(m.schedules.Pending()) == 0 {
+			m.scheduleTicking = false
+			return m, nil
+		}
+		return m, scheduleTickCmd()
+
+	case types.RecordTickMsg:
+		// Stop the tick once the recording has been stopped
+		if m.recorder == nil {
+			m.recordTicking = false
+			return m, nil
+		}
+		if err := m.recorder.Record(time.Time(msg), m.containerStats); err != nil {
+			m.stopRecording()
+			m.statusMessage = "ERROR: " + err.Error()
+			return m, nil
+		}
+		return m, recordTickCmd()
+
+	case types.StatsMsg:
+		m.containerStats = msg
+		m.peaks.Observe(msg)
+		m.applyContainerStats()
+		return m, m.checkAlerts()
+
+	case types.BindMountsMsg:
+		if m.currentView != types.ViewModeWatch || m.selectedContainer == nil || msg.ContainerID != m.selectedContainer.ID {
+			return m, nil
+		}
+		// Nothing to set up a watch with: back to the list with the reason
+		if msg.Err != nil {
+			m.currentView = types.ViewModeList
+			m.statusMessage = "ERROR: " + msg.Err.Error()
+			return m, nil
+		}
+		if len(msg.Mounts) == 0 {
+			m.currentView = types.ViewModeList
+			m.statusMessage = "Container has no bind mounts to watch"
+			return m, nil
+		}
+		m.watchMounts = msg.Mounts
+		m.watchSelected = make([]bool, len(msg.Mounts))
+		for i := range m.watchSelected {
+			m.watchSelected[i] = true
+		}
+		m.watchCursor = 0
+		return m, nil
+
+	case types.WatchTickMsg:
+		// Stop the poll loop once nothing is watched anymore
+		if len(m.watches) == 0 {
+			m.watchTicking = false
+			return m, nil
+		}
+		cmds := []tea.Cmd{watchTickCmd()}
+		for id, w := range m.watches {
+			if w.polling {
+				continue
+			}
+			w.polling = true
+			cmds = append(cmds, pollWatchCmd(id, w.paths))
+		}
+		return m, tea.Batch(cmds...)
+
+	case types.WatchResultMsg:
+		return m.handleWatchResult(msg)
 
 	case types.AnimationTickMsg:
 		// Update animation frame for status indicators
@@ -98,487 +582,5430 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// handleResize adjusts viewport when terminal size changes
-func (m *Model) handleResize(msg tea.WindowSizeMsg) (tea.Model, tea.Cmd) {
-	m.width = msg.Width
-	m.height = msg.Height
+// applyContainerStats copies the latest streamed stats onto the container list
+func (m *Model) applyContainerStats() {
+	for i := range m.containers {
+		if stats, ok := m.containerStats[m.containers[i].ID]; ok {
+			m.containers[i].CPU = stats.CPU
+			m.containers[i].Mem = stats.Mem
+			m.containers[i].CPUPercent = stats.CPUPercent
+			m.containers[i].MemBytes = stats.MemBytes
+			m.containers[i].NetIO = stats.NetIO
+			m.containers[i].BlockIO = stats.BlockIO
+		}
+	}
+	m.sortContainers()
+}
 
-	// Calculate viewport height for scrollable content
-	// Fixed UI elements:
-	// - Tabs: 4 lines
-	// - Table header: 2 lines
-	// - Action bar: 3 lines
-	// - Scroll indicator: 2 lines
-	// - Buffer: 1 line
-	fixedLines := 12
-	m.viewportHeight = msg.Height - fixedLines
-	if m.viewportHeight < 3 {
-		m.viewportHeight = 3 // Minimum 3 visible rows
+// checkAlerts runs the latest stats through the alert rules, reporting the
+// alerts that just started in the status line and, if enabled, as desktop
+// notifications
+func (m *Model) checkAlerts() tea.Cmd {
+	if !m.alerts.Enabled() {
+		return nil
 	}
 
-	// Update component dimensions
-	m.header = m.header.WithWidth(m.width)
-	m.tabs = m.tabs.WithWidth(m.width)
-	m.actionBar = m.actionBar.WithWidth(m.width)
-	m.detailView = m.detailView.WithWidth(m.width)
+	names := make(map[string]string, len(m.containers))
+	for _, c := range m.containers {
+		names[c.ID] = c.Name
+	}
+	samples := make(map[string]alerts.Sample, len(m.containerStats))
+	for id, stats := range m.containerStats {
+		name, ok := names[id]
+		if !ok {
+			continue
+		}
+		sample := alerts.Sample{Name: name, CPUPercent: stats.CPUPercent}
+		if stats.MemLimit > 0 {
+			sample.MemPercent = float64(stats.MemBytes) * 100 / float64(stats.MemLimit)
+		}
+		samples[id] = sample
+	}
 
-	// Keep scroll position valid after resize
-	maxRow := m.getMaxRow()
-	if m.selectedRow >= maxRow && maxRow > 0 {
-		m.selectedRow = maxRow - 1
+	started := m.alerts.Update(time.Now(), samples)
+	if len(started) == 0 {
+		return nil
 	}
-	if m.scrollOffset > maxRow-m.viewportHeight && maxRow > m.viewportHeight {
-		m.scrollOffset = maxRow - m.viewportHeight
+	descriptions := make([]string, len(started))
+	for i, alert := range started {
+		descriptions[i] = alert.String()
 	}
-	if m.scrollOffset < 0 {
-		m.scrollOffset = 0
+	m.statusMessage = "ALERT: " + strings.Join(descriptions, "; ")
+	if !m.alertNotify {
+		return m.ring(bell.Alert)
 	}
+	return tea.Batch(m.ring(bell.Alert), notifyCmd("tinyd alert", strings.Join(descriptions, "\n")))
+}
 
-	return m, nil
+// sortTables names the table of each tab in the state file
+var sortTables = [4]string{"containers", "images", "volumes", "networks"}
+
+// sortableColumns are the header labels each tab can be sorted by
+var sortableColumns = [4][]string{
+	{"NAME", "IMAGE", "CPU", "MEM"},
+	{"REPOSITORY:TAG", "SIZE", "CREATED"},
+	{"NAME", "CONTAINERS", "MOUNT POINT"},
+	{"NAME", "DRIVER", "SCOPE"},
 }
 
-// handleKeyPress routes keypresses based on current state
-// This is a simplified version - the full implementation would handle all keys
-func (m *Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	// Block input during actions
-	if m.actionInProgress {
-		return m, nil
+// descendingFirst are the columns that sort largest or newest first when
+// picked, usage, sizes and dates
+var descendingFirst = map[string]bool{"CPU": true, "MEM": true, "SIZE": true, "CREATED": true}
+
+// sortBy sorts items stably by less, reversed when descending
+func sortBy[T any](items []T, descending bool, less func(a, b T) bool) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if descending {
+			return less(items[j], items[i])
+		}
+		return less(items[i], items[j])
+	})
+}
+
+// lessFold compares strings ignoring case
+func lessFold(a, b string) bool {
+	return strings.ToLower(a) < strings.ToLower(b)
+}
+
+// sortContainers applies the selected sort order, keeping the cursor on the
+// same container
+func (m *Model) sortContainers() {
+	var selectedID string
+	if m.activeTab == 0 && m.selectedRow < len(m.containers) {
+		selectedID = m.containers[m.selectedRow].ID
 	}
 
-	key := msg.String()
+	desc := m.sorts[0].Desc
+	switch m.sorts[0].Column {
+	case "NAME":
+		sortBy(m.containers, desc, func(a, b types.Container) bool { return lessFold(a.Name, b.Name) })
+	case "IMAGE":
+		sortBy(m.containers, desc, func(a, b types.Container) bool { return lessFold(a.Image, b.Image) })
+	case "CPU":
+		sortBy(m.containers, desc, func(a, b types.Container) bool { return a.CPUPercent < b.CPUPercent })
+	case "MEM":
+		sortBy(m.containers, desc, func(a, b types.Container) bool { return a.MemBytes < b.MemBytes })
+	default:
+		docker.SortContainers(m.containers)
+	}
 
-	// Global keys (work in all modes)
-	switch key {
-	case "ctrl+c":
-		// Double Ctrl+C to exit
-		now := time.Now()
-		if now.Sub(m.lastCtrlC) < 500*time.Millisecond {
-			return m, tea.Quit
+	if selectedID == "" {
+		return
+	}
+	for i, c := range m.containers {
+		if c.ID == selectedID {
+			m.moveCursor(i)
+			break
 		}
-		m.lastCtrlC = now
-		m.statusMessage = "Press Ctrl+C again to exit"
-		return m, nil
-	case "H", "?":
-		m.showHelp = !m.showHelp
-		return m, nil
 	}
+}
 
-	// Route to appropriate handler based on view
-	switch m.currentView {
-	case types.ViewModeList:
-		return m.handleListViewKeys(msg)
-	case types.ViewModeLogs:
-		return m.handleLogsViewKeys(msg)
-	case types.ViewModeInspect:
-		return m.handleInspectViewKeys(msg)
-	default:
-		return m, nil
+// sortImages applies the selected sort order to the shown images. The
+// default order is the one of the full list.
+func (m *Model) sortImages() {
+	desc := m.sorts[1].Desc
+	switch m.sorts[1].Column {
+	case "REPOSITORY:TAG":
+		sortBy(m.images, desc, func(a, b types.Image) bool {
+			return lessFold(a.Repository+":"+a.Tag, b.Repository+":"+b.Tag)
+		})
+	case "SIZE":
+		sortBy(m.images, desc, func(a, b types.Image) bool { return a.SizeBytes < b.SizeBytes })
+	case "CREATED":
+		sortBy(m.images, desc, func(a, b types.Image) bool { return a.CreatedAt.Before(b.CreatedAt) })
 	}
 }
 
-// handleListViewKeys processes input in list view
-func (m *Model) handleListViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	key := msg.String()
+// sortVolumes applies the selected sort order to the volumes. The default
+// order is the one they were listed in.
+func (m *Model) sortVolumes() {
+	desc := m.sorts[2].Desc
+	switch m.sorts[2].Column {
+	case "NAME":
+		sortBy(m.volumes, desc, func(a, b types.Volume) bool { return lessFold(a.Name, b.Name) })
+	case "CONTAINERS":
+		sortBy(m.volumes, desc, func(a, b types.Volume) bool { return lessFold(a.Containers, b.Containers) })
+	case "MOUNT POINT":
+		sortBy(m.volumes, desc, func(a, b types.Volume) bool { return a.Mountpoint < b.Mountpoint })
+	}
+}
 
-	// Handle delete confirmation mode
-	if m.deleteConfirmMode {
-		switch key {
-		case "left", "h":
-			m.deleteConfirmOption = 0 // Yes
-			return m, nil
-		case "right", "l":
-			m.deleteConfirmOption = 1 // No
-			return m, nil
-		case "enter":
-			if m.deleteConfirmOption == 0 {
-				// User confirmed delete
-				m.deleteConfirmMode = false
-				m.actionInProgress = true
-
-				// Handle delete based on active tab
-				switch m.activeTab {
-				case 0: // Containers
-					if m.selectedRow < len(m.containers) {
-						container := m.containers[m.selectedRow]
-						return m, m.deleteContainerCmd(container.ID, container.Name)
-					}
-				case 1: // Images
-					if m.selectedRow < len(m.images) {
-						image := m.images[m.selectedRow]
-						return m, m.deleteImageCmd(image.ID)
-					}
-				case 2: // Volumes
-					if m.selectedRow < len(m.volumes) {
-						volume := m.volumes[m.selectedRow]
-						return m, m.deleteVolumeCmd(volume.Name)
-					}
-				case 3: // Networks
-					if m.selectedRow < len(m.networks) {
-						network := m.networks[m.selectedRow]
-						return m, m.deleteNetworkCmd(network.ID)
-					}
-				}
-			}
-			// User cancelled or selected No
-			m.deleteConfirmMode = false
-			return m, nil
-		case "esc":
-			m.deleteConfirmMode = false
-			return m, nil
-		}
-		return m, nil
+// sortNetworks applies the selected sort order to the networks. The
+// default order is the one they were listed in.
+func (m *Model) sortNetworks() {
+	desc := m.sorts[3].Desc
+	switch m.sorts[3].Column {
+	case "NAME":
+		sortBy(m.networks, desc, func(a, b types.Network) bool { return lessFold(a.Name, b.Name) })
+	case "DRIVER":
+		sortBy(m.networks, desc, func(a, b types.Network) bool { return a.Driver < b.Driver })
+	case "SCOPE":
+		sortBy(m.networks, desc, func(a, b types.Network) bool { return a.Scope < b.Scope })
 	}
+}
 
-	switch key {
-	case "up", "k":
-		if m.selectedRow > 0 {
-			m.selectedRow--
-			if m.selectedRow < m.scrollOffset {
+// moveCursor puts the cursor on row, scrolling it into view
+func (m *Model) moveCursor(row int) {
+	m.selectedRow = row
+	if m.selectedRow < m.scrollOffset {
+		m.scrollOffset = m.selectedRow
+	} else if m.selectedRow >= m.scrollOffset+m.pageSize() {
+		m.scrollOffset = m.selectedRow - m.pageSize() + 1
+	}
+}
+
+// setSort sorts the active tab, keeping the cursor on the same item, and
+// remembers the order for the next run
+func (m *Model) setSort(order state.Sort) {
+	tab := m.activeTab
+	m.sorts[tab] = order
+	if err := m.state.SetSort(sortTables[tab], order); err != nil {
+		m.statusMessage = "Sort not saved: " + err.Error()
+	}
+
+	selected, ok := m.rowKey(m.selectedRow)
+	switch tab {
+	case 0:
+		m.sortContainers()
+		return
+	case 1:
+		m.applyImageFilter()
+	case 2:
+		m.applyVolumeFilter()
+	case 3:
+		m.applyNetworkFilter()
+	}
+	if ok {
+		for i := 0; i < m.getMaxRow(); i++ {
+			if key, _ := m.rowKey(i); key == selected {
+				m.moveCursor(i)
+				break
+			}
+		}
+	}
+}
+
+// sortByColumn sorts the active tab by the column of that header label:
+// a new column sorts in its first direction, the same column again flips
+// it
+func (m *Model) sortByColumn(column string) {
+	current := m.sorts[m.activeTab]
+	if current.Column == column {
+		m.setSort(state.Sort{Column: column, Desc: !current.Desc})
+		return
+	}
+	m.setSort(state.Sort{Column: column, Desc: descendingFirst[column]})
+}
+
+// handleMouse sorts the list by the column of a clicked table header
+func (m *Model) handleMouse(msg tea.MouseMsg) {
+	if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+		return
+	}
+	if m.currentView != types.ViewModeList || m.modalOpen() || msg.Y != m.tableHeaderRow {
+		return
+	}
+	col := components.ColumnAt(m.tableHeaders, msg.X)
+	if col < 0 {
+		return
+	}
+	column := strings.TrimSuffix(m.tableHeaders[col].Label, "↔")
+	if !slices.Contains(sortableColumns[m.activeTab], column) {
+		return
+	}
+	m.sortByColumn(column)
+}
+
+// toggleContainerSort sorts the containers by the given column, largest
+// first, or back to the default status sort when it is already active
+func (m *Model) toggleContainerSort(column string) {
+	if m.sorts[0] == (state.Sort{Column: column, Desc: true}) {
+		m.setSort(state.Sort{})
+	} else {
+		m.setSort(state.Sort{Column: column, Desc: true})
+	}
+}
+
+// cycleSort steps the active tab through its sortable columns, each in
+// its first direction and then reversed, and back to the default order
+func (m *Model) cycleSort() {
+	columns := sortableColumns[m.activeTab]
+	current := m.sorts[m.activeTab]
+	if current.Column == "" {
+		m.sortByColumn(columns[0])
+		return
+	}
+	if current.Desc != descendingFirst[current.Column] {
+		for i, column := range columns {
+			if column == current.Column {
+				if i+1 < len(columns) {
+					m.sortByColumn(columns[i+1])
+					return
+				}
+				break
+			}
+		}
+		m.setSort(state.Sort{})
+		return
+	}
+	m.sortByColumn(current.Column)
+}
+
+// sortedColumn returns the index of the header the active tab is sorted
+// by, -1 for the default order
+func (m *Model) sortedColumn(headers []components.TableHeader) int {
+	column := m.sorts[m.activeTab].Column
+	if column == "" {
+		return -1
+	}
+	for i, header := range headers {
+		if strings.TrimSuffix(header.Label, "↔") == column {
+			return i
+		}
+	}
+	return -1
+}
+
+// containerSortOrder returns the containers sort as a workspace saves it
+func (m *Model) containerSortOrder() int {
+	switch m.sorts[0] {
+	case state.Sort{Column: "CPU", Desc: true}:
+		return types.ContainerSortCPU
+	case state.Sort{Column: "MEM", Desc: true}:
+		return types.ContainerSortMem
+	}
+	return types.ContainerSortStatus
+}
+
+// workspaceSort returns the containers sort saved in a workspace
+func workspaceSort(order int) state.Sort {
+	switch order {
+	case types.ContainerSortCPU:
+		return state.Sort{Column: "CPU", Desc: true}
+	case types.ContainerSortMem:
+		return state.Sort{Column: "MEM", Desc: true}
+	}
+	return state.Sort{}
+}
+
+// visibleRunningContainerIDs returns the IDs of running containers in the
+// visible rows of the containers tab
+func (m *Model) visibleRunningContainerIDs() []string {
+	if m.activeTab != 0 || m.currentView != types.ViewModeList {
+		return nil
+	}
+
+	var ids []string
+	end := m.scrollOffset + m.pageSize()
+	for i := m.scrollOffset; i < end && i < len(m.containers); i++ {
+		if m.containers[i].Status == "RUNNING" {
+			ids = append(ids, m.containers[i].ID)
+		}
+	}
+	return ids
+}
+
+// runningContainerIDs returns the IDs of all running containers, including
+// replicas folded into a service row
+func (m *Model) runningContainerIDs() []string {
+	var ids []string
+	for _, row := range m.containers {
+		for _, c := range docker.ServiceReplicas(row) {
+			if c.Status == "RUNNING" {
+				ids = append(ids, c.ID)
+			}
+		}
+	}
+	return ids
+}
+
+// streamedContainerIDs returns the containers to stream stats for: the
+// running ones on screen; on Docker Desktop all of them, to sum usage
+// against the VM limits, and with alerts configured all of them too, so
+// none goes unwatched
+func (m *Model) streamedContainerIDs() []string {
+	ids := m.visibleRunningContainerIDs()
+	if m.hostInfo.Desktop || m.alerts.Enabled() {
+		ids = m.runningContainerIDs()
+	}
+	// None in low-bandwidth mode, each stream is a request kept open
+	if m.lowBandwidth {
+		ids = nil
+	}
+	// Recorded containers are streamed wherever they are in the list, and
+	// so is the one on the stats dashboard
+	if m.recorder != nil {
+		ids = append(ids, m.recorder.Containers()...)
+	}
+	if m.currentView == types.ViewModeStats {
+		ids = append(ids, m.dashContainer.ID)
+	}
+	return ids
+}
+
+// showCached falls back to the cached lists after a failed fetch, reporting
+// whether there are any. Lists already on screen are kept: they are at
+// least as recent as the cache.
+func (m *Model) showCached(err error) bool {
+	snap, ok := m.cache.Snapshot()
+	if !ok {
+		return false
+	}
+	if m.staleSince.IsZero() {
+		m.staleSince = snap.Saved
+	}
+	m.staleErr = err
+	if len(m.allContainers) == 0 {
+		m.allContainers = snap.Containers
+		m.applyContainerFilter()
+	}
+	if len(m.allImages) == 0 {
+		m.allImages = snap.Images
+		m.applyImageFilter()
+	}
+	if len(m.allVolumes) == 0 {
+		m.allVolumes = snap.Volumes
+		m.applyVolumeFilter()
+	}
+	if len(m.allNetworks) == 0 {
+		m.allNetworks = snap.Networks
+		m.applyNetworkFilter()
+	}
+	return true
+}
+
+// listWarning returns the banner shown above the list: cached data first,
+// as nothing on screen is current then, or the Docker Desktop limits
+func (m *Model) listWarning() string {
+	if !m.staleSince.IsZero() {
+		return fmt.Sprintf("CACHED data from %s, daemon unreachable: %v", m.staleSince.Format("2006-01-02 15:04:05"), m.staleErr)
+	}
+	return m.desktopWarning()
+}
+
+// desktopWarning returns the Docker Desktop resource warning for the summed
+// usage of the running containers, or ""
+func (m *Model) desktopWarning() string {
+	if !m.hostInfo.Desktop {
+		return ""
+	}
+	var cpu float64
+	var mem uint64
+	for _, stats := range m.containerStats {
+		cpu += stats.CPUPercent
+		mem += stats.MemBytes
+	}
+	return docker.DesktopWarning(m.hostInfo, cpu, mem)
+}
+
+// refreshLogPreviewCmd fetches the last log lines of the visible running
+// containers, or nothing when the preview is off
+func (m *Model) refreshLogPreviewCmd() tea.Cmd {
+	if !m.logPreview {
+		return nil
+	}
+	return m.fetchLastLogLinesCmd(m.visibleRunningContainerIDs())
+}
+
+// handleResize adjusts viewport when terminal size changes
+func (m *Model) handleResize(msg tea.WindowSizeMsg) (tea.Model, tea.Cmd) {
+	m.width = msg.Width
+	m.height = msg.Height
+
+	// Update component dimensions
+	m.header = m.header.WithWidth(m.width)
+	m.tabs = m.tabs.WithWidth(m.width)
+	m.actionBar = m.actionBar.WithWidth(m.width)
+	m.detailView = m.detailView.WithWidth(m.width)
+
+	// The table gets whatever the tabs, the scroll indicator and the action
+	// bar leave, measured as renderListView draws them (minimum 3 rows)
+	m.viewportHeight = components.ViewportHeight(msg.Height, 3,
+		m.tabs.View(), m.getScrollIndicator(1), m.actionBar.View())
+
+	// Keep scroll position valid after resize
+	maxRow := m.getMaxRow()
+	if m.selectedRow >= maxRow && maxRow > 0 {
+		m.selectedRow = maxRow - 1
+	}
+	if m.scrollOffset > maxRow-m.pageSize() && maxRow > m.pageSize() {
+		m.scrollOffset = maxRow - m.pageSize()
+	}
+	if m.scrollOffset < 0 {
+		m.scrollOffset = 0
+	}
+
+	return m, nil
+}
+
+// handleKeyPress routes keypresses based on current state
+// This is a simplified version - the full implementation would handle all keys
+func (m *Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Block input during actions
+	if m.actionInProgress {
+		return m, nil
+	}
+
+	key := msg.String()
+
+	// Detach confirmation takes all input until answered
+	if m.detachConfirm.Active() {
+		return m.handleDetachConfirmKeys(key)
+	}
+
+	// Nothing is shown while the terminal is too small, so only quitting works
+	if m.width < components.MinWidth || m.height < components.MinHeight {
+		switch key {
+		case "q", "Q":
+			return m.handleQuit()
+		case "ctrl+c":
+		default:
+			return m, nil
+		}
+	}
+
+	// Open modals and prompts trap the focus: global shortcuts such as H
+	// would otherwise fire behind them, or eat the letters being typed
+	if m.modalOpen() && key != "ctrl+c" && key != "ctrl+d" {
+		return m.routeViewKeys(msg)
+	}
+
+	// Global keys (work in all modes)
+	switch key {
+	case "ctrl+c":
+		// Detach from open streams before allowing exit
+		if len(m.streams) > 0 {
+			m.openDetachConfirm()
+			return m, nil
+		}
+		// Double Ctrl+C to exit
+		now := time.Now()
+		if now.Sub(m.lastCtrlC) < 500*time.Millisecond {
+			return m.quit()
+		}
+		m.lastCtrlC = now
+		m.statusMessage = "Press Ctrl+C again to exit"
+		return m, nil
+	case "ctrl+d":
+		// Detach immediately, without confirmation
+		if len(m.streams) > 0 {
+			m.statusMessage = fmt.Sprintf("Detached from %d stream(s)", len(m.streams))
+			m.detachStreams()
+		}
+		return m, nil
+	case "H", "?":
+		m.showHelp = !m.showHelp
+		return m, nil
+	case "ctrl+o":
+		// Open the release page of an available update
+		if m.updateURL != "" {
+			return m, openURLCmd(m.updateURL)
+		}
+		return m, nil
+	}
+
+	return m.routeViewKeys(msg)
+}
+
+// modalOpen reports whether a modal or prompt has the focus
+func (m *Model) modalOpen() bool {
+	switch m.currentView {
+	case types.ViewModeRunImage, types.ViewModeBulkEnv:
+		return true
+	case types.ViewModeLogs:
+		return m.logsSearchMode || m.logsRangeMode || m.logsPresetMode
+	case types.ViewModeInspect:
+		return m.inspectExportMode
+	case types.ViewModeSchedules:
+		return m.schedulePromptMode
+	case types.ViewModeForwards:
+		return m.forwardPromptMode
+	case types.ViewModeWorkspaces:
+		return m.workspacePromptMode
+	case types.ViewModeEvents:
+		return m.eventsPrompt != eventsPromptNone
+	case types.ViewModeLabels:
+		return m.labelEditMode || m.labelEditConfirm
+	case types.ViewModeList:
+		return m.deleteConfirm.Active() || m.imageCascadeConfirm.Active() || m.bulkConfirm.Active() || m.listSearchMode || m.sshPromptMode || m.pullPromptMode || m.pullKeepMode ||
+			m.captureMode || m.volumeCopyMode || m.logSearchPromptMode || m.networkCreateMode || m.dnsPromptMode || m.notePromptMode || m.fileCopyMode || m.drainMode || m.commitMode || m.limitsMode || m.pruneMode || m.execPromptMode || m.execOptsMode ||
+			m.filterMode || m.imageSaveMode || m.tagCleanupMode
+	}
+	return false
+}
+
+// routeViewKeys passes a key press to the handler of the current view
+func (m *Model) routeViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.currentView {
+	case types.ViewModeList:
+		return m.handleListViewKeys(msg)
+	case types.ViewModeLogs:
+		return m.handleLogsViewKeys(msg)
+	case types.ViewModeInspect:
+		return m.handleInspectViewKeys(msg)
+	case types.ViewModeWatch:
+		return m.handleWatchViewKeys(msg)
+	case types.ViewModeLayers:
+		return m.handleLayersViewKeys(msg)
+	case types.ViewModeBulkEnv:
+		return m.handleBulkEnvKeys(msg)
+	case types.ViewModeRunImage:
+		return m.handleRunModalKeys(msg)
+	case types.ViewModeTasks:
+		return m.handleTasksViewKeys(msg)
+	case types.ViewModeSchedules:
+		return m.handleSchedulesViewKeys(msg)
+	case types.ViewModeMessages:
+		return m.handleMessagesViewKeys(msg)
+	case types.ViewModeRelated:
+		return m.handleRelatedViewKeys(msg)
+	case types.ViewModeStartFailure:
+		return m.handleStartFailureKeys(msg)
+	case types.ViewModeTagCleanup:
+		return m.handleTagCleanupViewKeys(msg)
+	case types.ViewModeLogSearch:
+		return m.handleLogSearchViewKeys(msg)
+	case types.ViewModeAggregatedLogs:
+		return m.handleAggregatedLogsKeys(msg)
+	case types.ViewModeProcesses:
+		return m.handleProcessesViewKeys(msg)
+	case types.ViewModeWorkspaces:
+		return m.handleWorkspacesViewKeys(msg)
+	case types.ViewModeEvents:
+		return m.handleEventsViewKeys(msg)
+	case types.ViewModeExecOutput:
+		return m.handleExecOutputKeys(msg)
+	case types.ViewModeHealth:
+		return m.handleHealthViewKeys(msg)
+	case types.ViewModeEnv:
+		return m.handleEnvViewKeys(msg)
+	case types.ViewModeForwards:
+		return m.handleForwardsViewKeys(msg)
+	case types.ViewModeProvision:
+		return m.handleProvisionViewKeys(msg)
+	case types.ViewModeStats:
+		return m.handleStatsDashboardKeys(msg)
+	case types.ViewModeLabels:
+		return m.handleLabelEditorKeys(msg)
+	default:
+		return m, nil
+	}
+}
+
+// handleQuit exits tinyd, or asks to detach first if streams are open
+func (m *Model) handleQuit() (tea.Model, tea.Cmd) {
+	if len(m.streams) > 0 {
+		m.openDetachConfirm()
+		return m, nil
+	}
+	return m.quit()
+}
+
+// quit stops all background work, releases the Docker client and exits
+// the program
+func (m *Model) quit() (tea.Model, tea.Cmd) {
+	_ = m.Close() // Exiting anyway, an error closing has nowhere to go
+	m.watches = make(map[string]*watchState)
+	return m, tea.Quit
+}
+
+// openDetachConfirm asks whether to detach from the open streams
+func (m *Model) openDetachConfirm() {
+	prompt := fmt.Sprintf("Detach from %d open stream(s)? ", len(m.streams))
+	if len(m.streams) == 1 {
+		prompt = "Detach from " + components.TruncateMiddle(m.streams[0].name, 30) + "? "
+	}
+	m.detachConfirm = components.NewConfirmModal(prompt, true)
+}
+
+// handleDetachConfirmKeys processes input while asking to detach from streams
+func (m *Model) handleDetachConfirmKeys(key string) (tea.Model, tea.Cmd) {
+	var result components.ModalResult
+	m.detachConfirm, result = m.detachConfirm.HandleKey(key)
+	if result == components.ModalConfirmed {
+		m.statusMessage = fmt.Sprintf("Detached from %d stream(s)", len(m.streams))
+		m.detachStreams()
+	}
+	return m, nil
+}
+
+// openDeleteConfirm asks whether to delete the selected row, focusing NO
+func (m *Model) openDeleteConfirm(name string) {
+	m.deleteConfirm = components.NewConfirmModal("Delete "+components.TruncateMiddle(name, 40)+"? ", false)
+}
+
+// handleDeleteConfirmKeys processes input while asking to delete the
+// selected row of the active tab
+func (m *Model) handleDeleteConfirmKeys(key string) (tea.Model, tea.Cmd) {
+	var result components.ModalResult
+	m.deleteConfirm, result = m.deleteConfirm.HandleKey(key)
+	if m.scaleDownReplicas != nil && result != components.ModalOpen {
+		service, replicas := m.scaleDownService, m.scaleDownReplicas
+		m.scaleDownService, m.scaleDownReplicas = "", nil
+		if result == components.ModalConfirmed {
+			m.actionInProgress = true
+			return m, m.removeReplicaCmd(service, replicas)
+		}
+		return m, nil
+	}
+	if result != components.ModalConfirmed {
+		return m, nil
+	}
+
+	switch m.activeTab {
+	case 0: // Containers
+		if m.selectedRow < len(m.containers) {
+			container := m.containers[m.selectedRow]
+			m.actionInProgress = true
+			return m, m.deleteContainerCmd(container.ID, container.Name)
+		}
+	case 1: // Images
+		if m.selectedRow < len(m.images) {
+			image := m.images[m.selectedRow]
+			m.actionInProgress = true
+			return m, m.deleteImageCmd(image.ID, image.Repository+":"+image.Tag)
+		}
+	case 2: // Volumes
+		if m.selectedRow < len(m.volumes) {
+			volume := m.volumes[m.selectedRow]
+			m.actionInProgress = true
+			return m, m.deleteVolumeCmd(volume.Name)
+		}
+	case 3: // Networks
+		if m.selectedRow < len(m.networks) {
+			network := m.networks[m.selectedRow]
+			m.actionInProgress = true
+			return m, m.deleteNetworkCmd(network.ID)
+		}
+	}
+	return m, nil
+}
+
+// openImageCascadeConfirm offers to delete the stopped containers that kept
+// an image from being deleted, and the image after them
+func (m *Model) openImageCascadeConfirm(msg types.ImageInUseMsg) {
+	names := make([]string, 0, len(msg.Containers))
+	for _, c := range msg.Containers {
+		names = append(names, components.TruncateMiddle(c.Name, 24))
+	}
+	if len(names) > 3 {
+		names = append(names[:3], fmt.Sprintf("+%d more", len(msg.Containers)-3))
+	}
+	prompt := fmt.Sprintf("%s is used by stopped container(s) %s. Delete them and the image? ",
+		components.TruncateMiddle(msg.ImageName, 40), strings.Join(names, ", "))
+	m.imageCascade = msg
+	m.imageCascadeConfirm = components.NewConfirmModal(prompt, false)
+}
+
+// handleImageCascadeKeys processes input while offering to delete an image
+// together with its stopped containers
+func (m *Model) handleImageCascadeKeys(key string) (tea.Model, tea.Cmd) {
+	var result components.ModalResult
+	m.imageCascadeConfirm, result = m.imageCascadeConfirm.HandleKey(key)
+	if result != components.ModalConfirmed {
+		return m, nil
+	}
+	m.actionInProgress = true
+	m.statusMessage = fmt.Sprintf("Deleting %d stopped container(s) and the image...", len(m.imageCascade.Containers))
+	return m, m.deleteImageCascadeCmd(m.imageCascade.ImageID, m.imageCascade.Containers)
+}
+
+// openStream registers a foreground stream and returns the context that
+// cancels it when the user detaches
+func (m *Model) openStream(name string) context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.streams = append(m.streams, foregroundStream{name: name, cancel: cancel})
+	return ctx
+}
+
+// closeStream cancels and forgets the foreground stream of that name
+func (m *Model) closeStream(name string) {
+	for i, stream := range m.streams {
+		if stream.name == name {
+			stream.cancel()
+			m.streams = append(m.streams[:i], m.streams[i+1:]...)
+			return
+		}
+	}
+}
+
+// detachStreams cancels every open foreground stream
+func (m *Model) detachStreams() {
+	for _, stream := range m.streams {
+		stream.cancel()
+	}
+	m.streams = nil
+	m.logsFollow = false
+	m.logsFollowUpdates = nil
+	m.aggFollow = false
+	m.aggUpdates = nil
+	m.eventsFollow = false
+	m.eventsUpdates = nil
+}
+
+// handleListViewKeys processes input in list view
+func (m *Model) handleListViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+
+	// SSH destination prompt takes all input until confirmed or cancelled
+	if m.sshPromptMode {
+		return m.handleSSHPromptKeys(msg)
+	}
+
+	// Pull prompt takes all input until pulled or cancelled
+	if m.pullKeepMode {
+		return m.handlePullKeepKeys(msg)
+	}
+	if m.pullPromptMode {
+		return m.handlePullPromptKeys(msg)
+	}
+
+	// Packet capture prompt takes all input until started or cancelled
+	if m.captureMode {
+		return m.handleCaptureKeys(msg)
+	}
+
+	// Filter modal takes all input until applied or cancelled
+	if m.filterMode {
+		return m.handleFilterKeys(msg)
+	}
+
+	// DNS check prompt takes all input until checked or cancelled
+	if m.dnsPromptMode {
+		return m.handleDNSPromptKeys(msg)
+	}
+
+	// Note prompt takes all input until saved or cancelled
+	if m.notePromptMode {
+		return m.handleNotePromptKeys(msg)
+	}
+
+	// File copy prompt takes all input until queued or cancelled
+	if m.fileCopyMode {
+		return m.handleFileCopyKeys(msg)
+	}
+
+	// Drain prompt takes all input until queued or cancelled
+	if m.drainMode {
+		return m.handleDrainKeys(msg)
+	}
+
+	// Commit prompt takes all input until queued or cancelled
+	if m.commitMode {
+		return m.handleCommitKeys(msg)
+	}
+
+	// Update prompt takes all input until applied or cancelled
+	if m.limitsMode {
+		return m.handleLimitsKeys(msg)
+	}
+
+	// Prune prompt takes all input until run or cancelled
+	if m.pruneMode {
+		return m.handlePruneKeys(msg)
+	}
+
+	// Exec prompt takes all input until run or cancelled
+	if m.execPromptMode {
+		return m.handleExecPromptKeys(msg)
+	}
+
+	// Exec options take all input until launched or cancelled
+	if m.execOptsMode {
+		return m.handleExecOptionsKeys(msg)
+	}
+
+	// Save prompt takes all input until saved or cancelled
+	if m.imageSaveMode {
+		return m.handleImageSaveKeys(msg)
+	}
+
+	// Tag cleanup prompt takes all input until previewed or cancelled
+	if m.tagCleanupMode {
+		return m.handleTagCleanupKeys(msg)
+	}
+
+	// Create-network prompt takes all input until created or cancelled
+	if m.networkCreateMode {
+		return m.handleNetworkCreateKeys(msg)
+	}
+
+	// Log search prompt takes all input until searched or cancelled
+	if m.logSearchPromptMode {
+		return m.handleLogSearchPromptKeys(msg)
+	}
+
+	// Volume copy prompt takes all input until started or cancelled
+	if m.volumeCopyMode {
+		return m.handleVolumeCopyKeys(msg)
+	}
+
+	// Delete confirmation takes all input until answered
+	if m.deleteConfirm.Active() {
+		return m.handleDeleteConfirmKeys(key)
+	}
+	if m.imageCascadeConfirm.Active() {
+		return m.handleImageCascadeKeys(key)
+	}
+	if m.bulkConfirm.Active() {
+		return m.handleBulkConfirmKeys(key)
+	}
+	// Esc drops a range being marked
+	if key == "esc" && m.visualAnchor >= 0 {
+		m.visualAnchor = -1
+		return m, nil
+	}
+
+	switch key {
+	case "q", "Q":
+		return m.handleQuit()
+
+	case "up", "k":
+		if m.selectedRow > 0 {
+			m.selectedRow--
+			if m.selectedRow < m.scrollOffset {
 				m.scrollOffset = m.selectedRow
 			}
 		}
 		return m, nil
 
-	case "down", "j":
-		maxRow := m.getMaxRow()
-		if m.selectedRow < maxRow-1 {
-			m.selectedRow++
-			if m.selectedRow >= m.scrollOffset+m.viewportHeight {
-				m.scrollOffset = m.selectedRow - m.viewportHeight + 1
-			}
+	case "down", "j":
+		maxRow := m.getMaxRow()
+		if m.selectedRow < maxRow-1 {
+			m.selectedRow++
+			if m.selectedRow >= m.scrollOffset+m.pageSize() {
+				m.scrollOffset = m.selectedRow - m.pageSize() + 1
+			}
+		}
+		return m, nil
+
+	case "left", "h", "right", "1", "2", "3", "4":
+		return m.handleTabSwitch(key)
+
+	case "enter":
+		// Refresh on enter
+		switch m.activeTab {
+		case 0:
+			return m, m.fetchContainersCmd()
+		case 1:
+			return m, m.fetchImagesCmd()
+		case 2:
+			return m, m.fetchVolumesCmd()
+		case 3:
+			return m, m.fetchNetworksCmd()
+		}
+		return m, nil
+
+	// Container actions (only on Containers tab)
+	case "s", "S":
+		if m.activeTab == 0 && len(m.marked) > 0 {
+			return m.openBulkStartStop()
+		}
+		if m.activeTab == 0 {
+			return m.handleContainerStartStop()
+		} else if m.activeTab == 1 {
+			return m.handleImageStart()
+		}
+		return m, nil
+	case "r", "R":
+		if m.activeTab == 0 && len(m.allContainers) == 0 {
+			// Empty state: containers are run from the images tab
+			m.statusMessage = "Select an image and press R to run it"
+			return m.handleTabSwitch("2")
+		}
+		if m.activeTab == 0 {
+			return m.handleContainerRestart()
+		} else if m.activeTab == 1 {
+			return m.handleImageStart()
+		}
+		return m, nil
+	case "l":
+		if m.activeTab == 0 {
+			// Marked containers are shown together
+			var marked []types.Container
+			for _, c := range m.markedContainers() {
+				// A folded service row stands for all its replicas
+				marked = append(append(marked, c), c.Replicas...)
+			}
+			if len(marked) > 0 {
+				m.aggSplit = false
+				return m.handleAggregatedLogs(marked)
+			}
+			return m.handleContainerLogs()
+		}
+		return m, nil
+	case "L":
+		// Uppercase only: the whole compose project of the selected container
+		if m.activeTab == 0 && m.selectedRow < len(m.containers) {
+			project := m.containers[m.selectedRow].ComposeProject
+			if project == "" {
+				return m.handleContainerLogs()
+			}
+			var services []types.Container
+			for _, c := range m.allContainers {
+				if c.ComposeProject == project {
+					services = append(services, c)
+				}
+			}
+			m.aggSplit = false
+			return m.handleAggregatedLogs(services)
+		}
+		return m, nil
+	case "i", "I":
+		switch m.activeTab {
+		case 0: // Containers
+			return m.handleContainerInspect()
+		case 1: // Images
+			return m.handleImageInspect()
+		case 2: // Volumes
+			return m.handleVolumeInspect()
+		case 3: // Networks
+			return m.handleNetworkInspect()
+		}
+		return m, nil
+	case "d", "D":
+		if m.markedCount() > 0 {
+			return m.openBulkDelete()
+		}
+		switch m.activeTab {
+		case 0: // Containers
+			return m.handleContainerDelete()
+		case 1: // Images
+			return m.handleImageDelete()
+		case 2: // Volumes
+			return m.handleVolumeDelete()
+		case 3: // Networks
+			return m.handleNetworkDelete()
+		}
+		return m, nil
+	case "e":
+		if m.activeTab == 0 {
+			return m.handleContainerExec()
+		}
+		return m, nil
+	case "E":
+		if m.activeTab == 0 && m.selectedRow < len(m.containers) {
+			return m.openExecOptions(m.containers[m.selectedRow])
+		}
+		return m, nil
+	case "!":
+		if m.activeTab == 0 && m.selectedRow < len(m.containers) {
+			return m.openExecPrompt(m.containers[m.selectedRow])
+		}
+		return m, nil
+	case "w", "W":
+		if m.activeTab == 0 {
+			return m.handleContainerWatch()
+		}
+		return m, nil
+	case " ":
+		// Mark/unmark the selected row for bulk actions, or the range
+		// being marked
+		if m.visualAnchor >= 0 {
+			m.markVisualRange()
+			return m, nil
+		}
+		if id, ok := m.rowKey(m.selectedRow); ok {
+			marked := m.markedSet()
+			if marked[id] {
+				delete(marked, id)
+			} else {
+				marked[id] = true
+			}
+		}
+		return m, nil
+	case "v", "V":
+		// Mark a range: from here to where v is pressed again
+		if m.visualAnchor >= 0 {
+			m.markVisualRange()
+		} else if m.selectedRow <= m.getMaxRow() {
+			m.visualAnchor = m.selectedRow
+		}
+		return m, nil
+	case "a", "A":
+		if m.activeTab == 0 {
+			return m.handleContainerBulkEnv()
+		}
+		return m, nil
+	case "c", "C":
+		switch m.activeTab {
+		case 0:
+			m.toggleContainerSort("CPU")
+		case 1:
+			return m.handleTagCleanup()
+		case 2:
+			return m.handleVolumeCopy()
+		}
+		return m, nil
+	case "m":
+		if m.activeTab == 0 {
+			return m.handleStatsDashboard()
+		}
+		return m, nil
+	case "M":
+		// Uppercase only: lowercase m opens the stats dashboard
+		if m.activeTab == 0 {
+			m.toggleContainerSort("MEM")
+		}
+		return m, nil
+	case "~":
+		m.messagesScroll = 0
+		m.currentView = types.ViewModeMessages
+		return m, nil
+	case "_":
+		// The preview is a line under each row
+		if m.activeTab == 0 {
+			m.logPreview = !m.logPreview
+			m.lastLogLines = make(map[string]string)
+			// Sublines halve the page; keep the selection on screen
+			if m.selectedRow >= m.scrollOffset+m.pageSize() {
+				m.scrollOffset = m.selectedRow - m.pageSize() + 1
+			}
+			return m, m.refreshLogPreviewCmd()
+		}
+		return m, nil
+	case "t":
+		if m.activeTab == 0 {
+			return m.handleContainerProcesses()
+		}
+		return m, nil
+	case "T":
+		// Uppercase only: lowercase t lists processes
+		m.taskCursor = 0
+		m.currentView = types.ViewModeTasks
+		return m, nil
+	case "@":
+		return m.handleSchedules()
+	case "ctrl+f":
+		return m.handleForwards()
+	case "ctrl+p":
+		if m.activeTab == 0 {
+			return m.handleContainerCapture()
+		}
+		return m, nil
+	case "ctrl+t":
+		m.cycleSort()
+		return m, nil
+	case "%":
+		if m.activeTab == 0 {
+			return m.handleProvisionReport()
+		}
+		return m, nil
+	case "b", "B":
+		if m.activeTab == 0 && m.selectedRow < len(m.containers) {
+			m.relatedTo = m.containers[m.selectedRow]
+			m.relatedScroll = 0
+			m.currentView = types.ViewModeRelated
+		}
+		return m, nil
+	case "n", "N":
+		if m.activeTab == 0 {
+			return m.handleContainerDNS()
+		}
+		if m.activeTab == 1 {
+			return m.handleImagePin()
+		}
+		if m.activeTab == 3 {
+			m.networkCreateMode = true
+			m.networkCreateField = 0
+			m.networkCreateInput = [4]string{"", "bridge", "", ""}
+		}
+		return m, nil
+	case "p", "P":
+		switch m.activeTab {
+		case 0:
+			return m.handleContainerPause()
+		case 1:
+			return m.handleImagePull()
+		}
+		return m, nil
+	case "o", "O":
+		if m.activeTab == 1 {
+			m.showImageSource = !m.showImageSource
+		}
+		return m, nil
+	case "#":
+		if m.activeTab == 1 {
+			m.showImageDigest = !m.showImageDigest
+		}
+		return m, nil
+	case "K":
+		if m.activeTab == 0 {
+			m.showContainerColumns = !m.showContainerColumns
+		}
+		return m, nil
+	case "f", "F":
+		return m.openFilter()
+	case "z", "Z":
+		if m.activeTab == 0 {
+			return m.handleContainerClock()
+		}
+		return m, nil
+	case "g", "G":
+		if m.activeTab == 0 {
+			m.groupReplicas = !m.groupReplicas
+			return m, m.fetchContainersCmd()
+		}
+		return m, nil
+	case "+", "-":
+		if m.activeTab == 0 {
+			return m.handleServiceScale(key)
+		}
+		return m, nil
+	case "J":
+		// Uppercase only: lowercase j moves down
+		return m.handleSSHJump()
+	case "x", "X":
+		if m.activeTab == 1 {
+			return m.handleImageSave()
+		}
+		return m, nil
+	case "ctrl+n":
+		return m.handleNoteEdit()
+	case "ctrl+s":
+		if m.activeTab == 0 {
+			return m.handleDrain()
+		}
+		return m, nil
+	case "|":
+		if m.activeTab == 0 {
+			return m.handleSplitLogs()
+		}
+		return m, nil
+	case "{", "}":
+		// Pick the column to widen, cycling through the columns after the
+		// status one and back to none
+		cw := &m.columnWidths[m.activeTab]
+		if cw.count > 1 {
+			step := 1
+			if msg.String() == "{" {
+				step = cw.count - 1
+			}
+			cw.focus = (cw.focus + step) % cw.count
+			cw.extra = 0
+		}
+		return m, nil
+	case "]", "[":
+		cw := &m.columnWidths[m.activeTab]
+		if cw.focus == 0 {
+			m.statusMessage = "Pick a column to widen with { or } first"
+			return m, nil
+		}
+		if msg.String() == "]" {
+			cw.extra += columnWidthStep
+		} else {
+			cw.extra = max(cw.extra-columnWidthStep, 0)
+		}
+		return m, nil
+	case "ctrl+k":
+		if m.activeTab == 0 {
+			return m.handleContainerCommit()
+		}
+		return m, nil
+	case "u", "U":
+		if m.activeTab == 0 && m.selectedRow < len(m.containers) {
+			container := m.containers[m.selectedRow]
+			m.selectedContainer = &container
+			m.statusMessage = "Reading the limits of " + container.Name + "..."
+			return m, m.containerLimitsCmd(container.ID)
+		}
+		return m, nil
+	case "ctrl+x":
+		if m.activeTab == 0 {
+			m.pruneMode = true
+			m.pruneInput = ""
+			m.pruneErr = ""
+		}
+		return m, nil
+	case "ctrl+g":
+		if m.activeTab <= 1 {
+			m.showGone = !m.showGone
+			if m.selectedRow >= m.scrollOffset+m.pageSize() {
+				m.scrollOffset = m.selectedRow - m.pageSize() + 1
+			}
+		}
+		return m, nil
+	case "y", "Y":
+		if m.activeTab == 0 {
+			// Lowercase copies out of the container, uppercase into it
+			return m.handleFileCopy(key == "Y")
+		}
+		return m, nil
+	case "ctrl+e":
+		m.currentView = types.ViewModeEvents
+		m.eventsPrompt = eventsPromptNone
+		if m.eventsFollow {
+			return m, nil
+		}
+		return m, m.startEvents()
+	case "ctrl+w":
+		m.workspaceCursor = 0
+		m.workspacePromptMode = false
+		m.currentView = types.ViewModeWorkspaces
+		return m, nil
+	case "ctrl+r":
+		if m.activeTab == 0 {
+			return m.handleStatsRecording()
+		}
+		return m, nil
+	case ">":
+		return m.handleGoToRelated()
+	case "backspace":
+		if !m.popNav() {
+			m.statusMessage = "Nothing to go back to"
+		}
+		return m, nil
+	case "/":
+		if m.activeTab == 0 {
+			m.logSearchPromptMode = true
+			m.logSearchInput = m.logSearchQuery
+		}
+		return m, nil
+	case "=":
+		m.comfortable = !m.comfortable
+		if m.selectedRow >= m.scrollOffset+m.pageSize() {
+			m.scrollOffset = m.selectedRow - m.pageSize() + 1
+		}
+		m.statusMessage = "Density: compact"
+		if m.comfortable {
+			m.statusMessage = "Density: comfortable"
+		}
+		return m, nil
+
+	default:
+		return m, nil
+	}
+}
+
+// handleTabSwitch switches between tabs
+func (m *Model) handleTabSwitch(key string) (tea.Model, tea.Cmd) {
+	oldTab := m.activeTab
+
+	switch key {
+	case "left", "h":
+		m.activeTab--
+		if m.activeTab < 0 {
+			m.activeTab = 3
+		}
+	case "right", "l":
+		m.activeTab++
+		if m.activeTab > 3 {
+			m.activeTab = 0
+		}
+	case "1":
+		m.activeTab = 0
+	case "2":
+		m.activeTab = 1
+	case "3":
+		m.activeTab = 2
+	case "4":
+		m.activeTab = 3
+	}
+
+	if m.activeTab != oldTab {
+		m.selectedRow = 0
+		m.scrollOffset = 0
+		m.visualAnchor = -1
+		m.tabs = m.tabs.SetActiveTab(m.activeTab)
+	}
+
+	return m, nil
+}
+
+// handleLogsViewKeys processes input in logs view
+func (m *Model) handleLogsViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+
+	if m.logsRangeMode {
+		return m.handleLogsRangeKeys(msg)
+	}
+	if m.logsPresetMode {
+		return m.handleLogsPresetKeys(msg)
+	}
+	if m.logsSearchMode {
+		return m.handleLogsSearchKeys(msg)
+	}
+
+	switch key {
+	case "q", "Q":
+		return m.handleQuit()
+
+	case "esc":
+		m.stopLogsFollow()
+		m.currentView = types.ViewModeList
+		if m.logsSearchQuery != "" {
+			// Opened from a log search: go back to its results
+			m.currentView = types.ViewModeLogSearch
+			m.logsSearchQuery = ""
+		}
+		m.logsSearchRe = nil
+		m.logsMatchInfo = ""
+		m.logsContent = ""
+		m.logsStderr = nil
+		m.logsMoreAnchor = ""
+		m.hScroll = 0
+		return m, nil
+
+	case "f", "F":
+		if m.logsFollow {
+			m.stopLogsFollow()
+			return m, nil
+		}
+		if m.selectedContainer == nil || m.logsContent == "" {
+			return m, nil
+		}
+		return m, m.followLogsCmd(m.selectedContainer.ID)
+
+	case "up", "k":
+		if m.logsScrollOffset > 0 {
+			m.logsScrollOffset--
+		}
+		return m, nil
+
+	case "down", "j":
+		m.logsScrollOffset++
+		return m, nil
+
+	case "t", "T":
+		m.logsPresetMode = true
+		return m, nil
+
+	case "w", "W", "left", "right", "home":
+		m.handleLongLineKey(key)
+		return m, nil
+	case "m":
+		return m, m.loadMoreLogs(m.logsTail + m.logsMoreLines())
+	case "M":
+		return m, m.loadMoreLogs(0)
+	case "s", "S":
+		m.logsTimestamps = !m.logsTimestamps
+		return m, m.reloadLogs()
+
+	case "/":
+		m.logsSearchMode = true
+		m.logsSearchErr = ""
+		return m, nil
+
+	case "n":
+		m.jumpToLogsMatch(1)
+		return m, nil
+
+	case "N":
+		m.jumpToLogsMatch(-1)
+		return m, nil
+
+	case "p", "P":
+		// Open the whole buffer in the pager for its search and navigation
+		if m.logsContent == "" {
+			return m, nil
+		}
+		return m, m.pagerCmd(m.logsContent)
+
+	default:
+		return m, nil
+	}
+}
+
+// logsRangePresets are the time ranges offered before a custom one, as
+// durations back from now; zero shows the last lines without a range
+var logsRangePresets = []struct {
+	label string
+	since time.Duration
+}{
+	{"last 5m", 5 * time.Minute},
+	{"last 15m", 15 * time.Minute},
+	{"last 1h", time.Hour},
+	{"last 6h", 6 * time.Hour},
+	{"last 24h", 24 * time.Hour},
+	{"tail", 0},
+}
+
+// logsPresetCustom is the cursor position past the presets: the custom
+// since/until range
+var logsPresetCustom = len(logsRangePresets)
+
+// handleLogsPresetKeys picks a time range preset, or opens the custom
+// since/until range
+func (m *Model) handleLogsPresetKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.logsPresetMode = false
+	case "left", "h", "shift+tab":
+		m.logsPresetCursor = (m.logsPresetCursor + logsPresetCustom) % (logsPresetCustom + 1)
+	case "right", "l", "tab":
+		m.logsPresetCursor = (m.logsPresetCursor + 1) % (logsPresetCustom + 1)
+	case "enter":
+		m.logsPresetMode = false
+		if m.logsPresetCursor == logsPresetCustom {
+			m.logsRangeMode = true
+			m.logsRangeField = 0
+			m.logsRangeErr = ""
+			return m, nil
+		}
+		preset := logsRangePresets[m.logsPresetCursor]
+		m.logsSince, m.logsUntil = time.Time{}, time.Time{}
+		m.logsRangeInput = [2]string{}
+		if preset.since > 0 {
+			m.logsSince = time.Now().Add(-preset.since)
+			// Prefill the custom range, so it can be adjusted from there
+			m.logsRangeInput[0] = strings.TrimSuffix(strings.TrimSuffix(preset.since.String(), "0s"), "0m")
+		}
+		return m, m.reloadLogs()
+	}
+	return m, nil
+}
+
+// reloadLogs fetches the logs again for the current range and timestamp
+// setting, ending a follow session
+func (m *Model) reloadLogs() tea.Cmd {
+	m.stopLogsFollow()
+	if m.selectedContainer == nil {
+		return nil
+	}
+	m.logsContent = ""
+	m.logsStderr = nil
+	m.logsScrollOffset = 0
+	m.logsMoreAnchor = ""
+	return m.getContainerLogsCmd(m.selectedContainer.ID)
+}
+
+// handleLongLineKey toggles wrapping of long lines in the logs and inspect
+// views, or scrolls them sideways when not wrapped
+func (m *Model) handleLongLineKey(key string) {
+	switch key {
+	case "w", "W":
+		m.wrapLines = !m.wrapLines
+		m.hScroll = 0
+	case "left":
+		m.hScroll = max(m.hScroll-hScrollStep, 0)
+	case "right":
+		if !m.wrapLines {
+			m.hScroll += hScrollStep
+		}
+	case "home":
+		m.hScroll = 0
+	}
+}
+
+// loadMoreLogs fetches the logs again with tail lines of history (0 for
+// all), keeping the lines on screen in place as the older ones come in
+// above them. Following stops, as the fetch replaces the content.
+func (m *Model) loadMoreLogs(tail int) tea.Cmd {
+	if m.selectedContainer == nil || m.logsContent == "" || m.logsMoreAnchor != "" {
+		return nil
+	}
+	if !m.logsSince.IsZero() || !m.logsUntil.IsZero() || m.logsTail == 0 {
+		m.statusMessage = "Already showing the whole log"
+		if label := m.logsRangeLabel(); label != "" {
+			m.statusMessage = "Already showing the whole time range " + label
+		}
+		return nil
+	}
+	if lines := strings.Count(m.logsContent, "\n") + 1; lines < m.logsTail {
+		m.statusMessage = fmt.Sprintf("Already showing the whole log (%d lines)", lines)
+		return nil
+	}
+
+	m.stopLogsFollow()
+	m.logsTail = tail
+	m.logsMoreAnchor = m.logsContent
+	return m.getContainerLogsCmd(m.selectedContainer.ID)
+}
+
+// keepLogsPosition shifts the logs view by the lines a load-more added
+// above the previous content, so the same lines stay on screen
+func (m *Model) keepLogsPosition() {
+	anchor := m.logsMoreAnchor
+	m.logsMoreAnchor = ""
+	if anchor == " No log lines" {
+		return
+	}
+
+	// The old content follows the older lines, unless the log rotated
+	added := 0
+	if i := strings.Index(m.logsContent, anchor); i >= 0 {
+		added = strings.Count(m.logsContent[:i], "\n")
+	} else {
+		added = max(strings.Count(m.logsContent, "\n")-strings.Count(anchor, "\n"), 0)
+	}
+	m.logsScrollOffset += added
+	m.logsMatchLine += added
+	m.statusMessage = fmt.Sprintf("Loaded %d older lines", added)
+}
+
+// appendLogLine adds a followed line to the logs view, keeping the view at
+// the bottom unless the user has scrolled up
+func (m *Model) appendLogLine(line string) {
+	if m.logsContent == " No log lines" {
+		m.logsContent = ""
+		m.logsStderr = nil
+	}
+	lines := strings.Count(m.logsContent, "\n") + 1
+	atBottom := m.logsScrollOffset >= lines-m.logsVisibleLines()
+
+	if m.logsContent == "" {
+		m.logsContent = line
+	} else {
+		m.logsContent += "\n" + line
+	}
+	if atBottom {
+		m.logsScrollOffset = max(lines+1-m.logsVisibleLines(), 0)
+	}
+}
+
+// stopLogsFollow ends the current follow session, if any
+func (m *Model) stopLogsFollow() {
+	if !m.logsFollow {
+		return
+	}
+	m.logsFollow = false
+	m.logsFollowUpdates = nil
+	m.closeStream(logsStreamName)
+}
+
+// handleAggregatedLogs opens the logs of several containers together,
+// interleaved and followed like `docker compose logs -f`
+func (m *Model) handleAggregatedLogs(targets []types.Container) (tea.Model, tea.Cmd) {
+	ids := make([]string, len(targets))
+	m.aggSources = make([]string, len(targets))
+	for i, c := range targets {
+		ids[i] = c.ID
+		m.aggSources[i] = c.Name
+	}
+	m.aggLines = nil
+	m.aggScroll = 0
+	m.currentView = types.ViewModeAggregatedLogs
+	return m, m.aggregatedLogsCmd(ids)
+}
+
+// handleSplitLogs opens the logs of two containers side by side: the two
+// marked ones, or the one marked and the selected one
+func (m *Model) handleSplitLogs() (tea.Model, tea.Cmd) {
+	targets := m.markedContainers()
+	if len(targets) == 1 && m.selectedRow < len(m.containers) && m.containers[m.selectedRow].ID != targets[0].ID {
+		targets = append(targets, m.containers[m.selectedRow])
+	}
+	if len(targets) != 2 {
+		m.statusMessage = "Mark one or two containers with Space to compare their logs side by side"
+		return m, nil
+	}
+	m.aggSplit = true
+	return m.handleAggregatedLogs(targets)
+}
+
+// handleAggregatedLogsKeys scrolls the aggregated logs; Esc stops following
+func (m *Model) handleAggregatedLogsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	bottom := max(len(m.aggLines)-m.aggVisibleLines(), 0)
+	switch msg.String() {
+	case "q", "Q":
+		return m.handleQuit()
+	case "esc":
+		m.stopAggregatedLogs()
+		m.aggLines = nil
+		m.currentView = types.ViewModeList
+	case "up", "k":
+		if m.aggScroll > 0 {
+			m.aggScroll--
+		}
+	case "down", "j":
+		if m.aggScroll < bottom {
+			m.aggScroll++
+		}
+	case "pgup":
+		m.aggScroll = max(m.aggScroll-m.aggVisibleLines(), 0)
+	case "pgdown":
+		m.aggScroll = min(m.aggScroll+m.aggVisibleLines(), bottom)
+	case "g":
+		m.aggScroll = 0
+	case "G":
+		m.aggScroll = bottom
+	case "|":
+		// Two containers can be shown side by side or interleaved
+		if len(m.aggSources) == 2 {
+			atBottom := m.aggScroll >= bottom
+			m.aggSplit = !m.aggSplit
+			if atBottom {
+				m.aggScroll = max(len(m.aggLines)-m.aggVisibleLines(), 0)
+			}
+		}
+	}
+	return m, nil
+}
+
+// appendAggLine adds a followed line to the aggregated logs, dropping the
+// oldest past aggLinesMax and staying at the bottom if already there
+func (m *Model) appendAggLine(line types.AggregatedLogLine) {
+	atBottom := m.aggScroll >= len(m.aggLines)-m.aggVisibleLines()
+	m.aggLines = append(m.aggLines, line)
+	if dropped := len(m.aggLines) - aggLinesMax; dropped > 0 {
+		m.aggLines = m.aggLines[dropped:]
+		m.aggScroll = max(m.aggScroll-dropped, 0)
+	}
+	if atBottom {
+		m.aggScroll = max(len(m.aggLines)-m.aggVisibleLines(), 0)
+	}
+}
+
+// aggVisibleLines returns how many lines the aggregated logs view shows:
+// the height minus the header, the divider, the pane titles when split and
+// a margin
+func (m *Model) aggVisibleLines() int {
+	if m.aggSplit {
+		return max(m.height-5, 5)
+	}
+	return max(m.height-4, 5)
+}
+
+// stopAggregatedLogs ends the current aggregated logs session, if any
+func (m *Model) stopAggregatedLogs() {
+	if !m.aggFollow {
+		return
+	}
+	m.aggFollow = false
+	m.aggUpdates = nil
+	m.closeStream(aggStreamName)
+}
+
+// Prompts of the events view
+const (
+	eventsPromptNone = iota
+	eventsPromptFilter
+	eventsPromptSince
+	eventsPromptSave
+)
+
+// eventsMax bounds the events kept in the events view
+const eventsMax = 5000
+
+// handleEventsViewKeys scrolls the events, edits their filter and since
+// time, and picks, saves and deletes watches
+func (m *Model) handleEventsViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.eventsPrompt != eventsPromptNone {
+		return m.handleEventsPromptKeys(msg)
+	}
+
+	watches := m.eventWatches.List()
+	bottom := max(len(m.events)-m.eventsVisibleLines(), 0)
+	key := msg.String()
+	switch key {
+	case "q", "Q":
+		return m.handleQuit()
+	case "esc":
+		m.stopEvents()
+		m.events = nil
+		m.currentView = types.ViewModeList
+	case "up", "k":
+		if m.eventsScroll > 0 {
+			m.eventsScroll--
+		}
+	case "down", "j":
+		if m.eventsScroll < bottom {
+			m.eventsScroll++
+		}
+	case "pgup":
+		m.eventsScroll = max(m.eventsScroll-m.eventsVisibleLines(), 0)
+	case "pgdown":
+		m.eventsScroll = min(m.eventsScroll+m.eventsVisibleLines(), bottom)
+	case "g":
+		m.eventsScroll = 0
+	case "G":
+		m.eventsScroll = bottom
+	case "c", "C":
+		m.events = nil
+		m.eventsScroll = 0
+	case "f", "F":
+		m.eventsPrompt, m.eventsInput = eventsPromptFilter, m.eventsFilter
+	case "s", "S":
+		m.eventsPrompt, m.eventsInput = eventsPromptSince, ""
+		if !m.eventsSince.IsZero() {
+			m.eventsInput = m.eventsSince.Format("2006-01-02 15:04:05")
+		}
+	case "n", "N":
+		m.eventsPrompt, m.eventsInput = eventsPromptSave, m.eventsWatch
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		if i := int(key[0] - '1'); i < len(watches) {
+			m.eventsFilter, m.eventsWatch = watches[i].Filter, watches[i].Name
+			return m, m.startEvents()
+		}
+	case "x", "X":
+		if m.eventsWatch == "" {
+			return m, nil
+		}
+		if err := m.eventWatches.Delete(m.eventsWatch); err != nil {
+			m.statusMessage = "ERROR: " + err.Error()
+			return m, nil
+		}
+		m.statusMessage = "Deleted watch " + m.eventsWatch
+		m.eventsWatch = ""
+	}
+	return m, nil
+}
+
+// handleEventsPromptKeys edits the filter, since time or watch name of the
+// events view; a new filter or since time restarts the stream
+func (m *Model) handleEventsPromptKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.eventsPrompt = eventsPromptNone
+		m.eventsErr = ""
+	case tea.KeyBackspace:
+		if len(m.eventsInput) > 0 {
+			runes := []rune(m.eventsInput)
+			m.eventsInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.eventsInput += " "
+	case tea.KeyRunes:
+		m.eventsInput += string(msg.Runes)
+	case tea.KeyEnter:
+		input := strings.TrimSpace(m.eventsInput)
+		switch m.eventsPrompt {
+		case eventsPromptFilter:
+			if _, err := docker.ParseEventFilter(input); err != nil {
+				m.eventsErr = err.Error()
+				return m, nil
+			}
+			if input != m.eventsFilter {
+				m.eventsWatch = ""
+			}
+			m.eventsFilter = input
+		case eventsPromptSince:
+			since, err := docker.ParseSince(input, time.Now())
+			if err != nil {
+				m.eventsErr = err.Error()
+				return m, nil
+			}
+			m.eventsSince = since
+		case eventsPromptSave:
+			if err := m.eventWatches.Save(eventwatch.Watch{Name: input, Filter: m.eventsFilter}); err != nil {
+				m.eventsErr = err.Error()
+				return m, nil
+			}
+			m.eventsPrompt = eventsPromptNone
+			m.eventsErr = ""
+			m.eventsWatch = input
+			m.statusMessage = "Saved watch " + input
+			return m, nil
+		}
+		m.eventsPrompt = eventsPromptNone
+		return m, m.startEvents()
+	}
+	return m, nil
+}
+
+// startEvents restarts the events stream with the current filter and since
+// time, replacing the events shown
+func (m *Model) startEvents() tea.Cmd {
+	m.stopEvents()
+	m.events = nil
+	m.eventsScroll = 0
+	m.eventsErr = ""
+	return m.eventsCmd(m.eventsFilter, m.eventsSince)
+}
+
+// appendEvent adds a streamed event, dropping the oldest past eventsMax and
+// staying at the bottom if already there
+func (m *Model) appendEvent(event types.DockerEvent) {
+	atBottom := m.eventsScroll >= len(m.events)-m.eventsVisibleLines()
+	m.events = append(m.events, event)
+	if dropped := len(m.events) - eventsMax; dropped > 0 {
+		m.events = m.events[dropped:]
+		m.eventsScroll = max(m.eventsScroll-dropped, 0)
+	}
+	if atBottom {
+		m.eventsScroll = max(len(m.events)-m.eventsVisibleLines(), 0)
+	}
+}
+
+// eventsVisibleLines returns how many events the events view shows: the
+// height minus the header, the filter line, the divider and a margin
+func (m *Model) eventsVisibleLines() int {
+	return max(m.height-5, 5)
+}
+
+// stopEvents ends the current events stream, if any
+func (m *Model) stopEvents() {
+	if !m.eventsFollow {
+		return
+	}
+	m.eventsFollow = false
+	m.eventsUpdates = nil
+	m.closeStream(eventsStreamName)
+}
+
+// handleLogsRangeKeys edits the since/until fields and refetches the logs
+// for that window on enter; clearing both fields returns to the tail view
+func (m *Model) handleLogsRangeKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.logsRangeMode = false
+	case tea.KeyTab, tea.KeyShiftTab, tea.KeyUp, tea.KeyDown:
+		m.logsRangeField = 1 - m.logsRangeField
+	case tea.KeyBackspace:
+		input := []rune(m.logsRangeInput[m.logsRangeField])
+		if len(input) > 0 {
+			m.logsRangeInput[m.logsRangeField] = string(input[:len(input)-1])
+		}
+	case tea.KeySpace:
+		m.logsRangeInput[m.logsRangeField] += " "
+	case tea.KeyRunes:
+		m.logsRangeInput[m.logsRangeField] += string(msg.Runes)
+	case tea.KeyEnter:
+		now := time.Now()
+		since, err := docker.ParseLogTime(m.logsRangeInput[0], now)
+		if err != nil {
+			m.logsRangeErr = "Since: " + err.Error()
+			return m, nil
+		}
+		until, err := docker.ParseLogTime(m.logsRangeInput[1], now)
+		if err != nil {
+			m.logsRangeErr = "Until: " + err.Error()
+			return m, nil
+		}
+		if !since.IsZero() && !until.IsZero() && !until.After(since) {
+			m.logsRangeErr = "Until must be after Since"
+			return m, nil
+		}
+
+		m.logsRangeMode = false
+		m.logsSince = since
+		m.logsUntil = until
+		return m, m.reloadLogs()
+	}
+	return m, nil
+}
+
+// handleInspectViewKeys processes input in inspect view
+func (m *Model) handleInspectViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+
+	if m.inspectExportMode {
+		return m.handleInspectExportKeys(msg)
+	}
+
+	switch key {
+	case "q", "Q":
+		return m.handleQuit()
+
+	case "esc", "backspace":
+		m.closeInspect()
+		return m, nil
+
+	case "e", "E":
+		// Export the JSON to a file, defaulting to the current directory
+		if m.inspectRaw == "" {
+			return m, nil
+		}
+		m.inspectExportMode = true
+		m.inspectExportInput = m.inspectSubject() + "-inspect.json"
+		m.inspectExportMsg = ""
+		return m, nil
+
+	case "up", "k":
+		if m.logsScrollOffset > 0 {
+			m.logsScrollOffset--
+		}
+		return m, nil
+
+	case "down", "j":
+		m.logsScrollOffset++
+		return m, nil
+
+	case "w", "W", "left", "right", "home":
+		m.handleLongLineKey(key)
+		return m, nil
+
+	case "h", "H":
+		// Healthcheck probes (containers only)
+		if m.activeTab == 0 && m.selectedContainer != nil {
+			m.currentView = types.ViewModeHealth
+			m.healthLog = types.HealthLog{}
+			m.healthLoaded = false
+			m.healthErr = ""
+			m.healthScroll = 0
+			return m, m.containerHealthCmd(m.selectedContainer.ID)
+		}
+		return m, nil
+
+	case "v", "V":
+		// Environment variables (containers only)
+		if m.activeTab == 0 && m.selectedContainer != nil {
+			m.currentView = types.ViewModeEnv
+			m.envVars = nil
+			m.envLoaded = false
+			m.envErr = ""
+			m.envCursor = 0
+			m.envRevealed = make(map[string]bool)
+			m.envRevealAll = false
+			m.envMsg = ""
+			return m, m.containerEnvCmd(m.selectedContainer.ID)
+		}
+		return m, nil
+
+	case "l", "L":
+		// Edit the labels of a container, browse the layers of an image
+		if m.activeTab == 0 && m.selectedContainer != nil {
+			m.openLabelEditor(m.inspectLabels())
+			return m, nil
+		}
+		if m.activeTab == 1 && m.selectedImage != nil {
+			m.currentView = types.ViewModeLayers
+			m.layers = nil
+			m.layersErr = ""
+			m.layerCursor = 0
+			m.layerFilesOpen = false
+			m.layerWasteOpen = false
+			m.layerScroll = 0
+			return m, m.imageLayersCmd(m.selectedImage.ID)
+		}
+		return m, nil
+
+	default:
+		return m, nil
+	}
+}
+
+// handleHealthViewKeys scrolls the healthcheck probes and refreshes them
+func (m *Model) handleHealthViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "Q":
+		return m.handleQuit()
+	case "esc", "backspace":
+		m.currentView = types.ViewModeInspect
+	case "up", "k":
+		if m.healthScroll > 0 {
+			m.healthScroll--
+		}
+	case "down", "j":
+		m.healthScroll++
+	case "r", "R":
+		if m.selectedContainer != nil {
+			return m, m.containerHealthCmd(m.selectedContainer.ID)
+		}
+	}
+	return m, nil
+}
+
+// handleEnvViewKeys moves through the variables of the env view, reveals
+// masked values and copies them
+func (m *Model) handleEnvViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "Q":
+		return m.handleQuit()
+	case "esc", "backspace":
+		m.currentView = types.ViewModeInspect
+	case "up", "k":
+		if m.envCursor > 0 {
+			m.envCursor--
+		}
+	case "down", "j":
+		if m.envCursor < len(m.envVars)-1 {
+			m.envCursor++
+		}
+	case "r":
+		// Reveal or mask the selected value
+		if m.envCursor < len(m.envVars) {
+			key := m.envVars[m.envCursor].Key
+			m.envRevealed[key] = !m.envRevealed[key]
+		}
+	case "R":
+		m.envRevealAll = !m.envRevealAll
+		m.envRevealed = make(map[string]bool)
+	case "c", "C":
+		// Copy the value through the terminal (OSC 52), which also works
+		// over SSH; masking only hides it on screen
+		if m.envCursor < len(m.envVars) {
+			v := m.envVars[m.envCursor]
+			termenv.Copy(v.Value)
+			m.envMsg = "Copied the value of " + v.Key + " to the clipboard"
+		}
+	}
+	return m, nil
+}
+
+// handleProvisionReport opens the provisioning report of the running
+// containers, comparing their limits with the peak usage seen so far
+func (m *Model) handleProvisionReport() (tea.Model, tea.Cmd) {
+	containers := make(map[string]string)
+	for _, row := range m.allContainers {
+		for _, c := range docker.ServiceReplicas(row) {
+			if c.Status == "RUNNING" {
+				containers[c.ID] = c.Name
+			}
+		}
+	}
+	if len(containers) == 0 {
+		m.statusMessage = "No running containers to report on"
+		return m, nil
+	}
+	m.currentView = types.ViewModeProvision
+	m.provisionRows = nil
+	m.provisionLoaded = false
+	m.provisionErr = ""
+	m.provisionCursor = 0
+	m.provisionMsg = ""
+	return m, m.allocationsCmd(containers)
+}
+
+// handleProvisionViewKeys moves through the provisioning report, refreshes
+// it and exports it to CSV
+func (m *Model) handleProvisionViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "Q":
+		return m.handleQuit()
+	case "esc", "backspace":
+		m.currentView = types.ViewModeList
+	case "up", "k":
+		if m.provisionCursor > 0 {
+			m.provisionCursor--
+		}
+	case "down", "j":
+		if m.provisionCursor < len(m.provisionRows)-1 {
+			m.provisionCursor++
+		}
+	case "r", "R":
+		return m.handleProvisionReport()
+	case "e", "E":
+		if len(m.provisionRows) == 0 {
+			return m, nil
+		}
+		path := filepath.Join(os.TempDir(), "tinyd-provisioning-"+time.Now().Format("20060102-150405")+".csv")
+		if err := writeProvisionCSV(path, m.provisionRows); err != nil {
+			m.provisionMsg = "ERROR: " + err.Error()
+			return m, nil
+		}
+		m.provisionMsg = "Exported the report to " + path
+	}
+	return m, nil
+}
+
+// writeProvisionCSV writes the provisioning report to a CSV file at path
+func writeProvisionCSV(path string, rows []provision.Row) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := provision.WriteCSV(f, rows); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// envMasked reports whether a variable's value is hidden in the env view
+func (m *Model) envMasked(v types.EnvVar) bool {
+	return docker.SecretEnvKey(v.Key) && !m.envRevealAll && !m.envRevealed[v.Key]
+}
+
+// closeInspect leaves the inspect view for the list it was opened from
+func (m *Model) closeInspect() {
+	m.currentView = types.ViewModeList
+	m.inspectContent = ""
+	m.inspectRaw = ""
+	m.inspectExportMsg = ""
+	m.hScroll = 0
+	m.popNav()
+}
+
+// openLabelEditor opens the labels editor of the inspected container,
+// prefilled with its labels
+func (m *Model) openLabelEditor(labels map[string]string) {
+	m.currentView = types.ViewModeLabels
+	m.labelEditBefore = labels
+	m.labelEditAfter = maps.Clone(labels)
+	if m.labelEditAfter == nil {
+		m.labelEditAfter = make(map[string]string)
+	}
+	m.labelEditCursor = 0
+	m.labelEditMode = false
+	m.labelEditErr = ""
+	m.labelEditConfirm = false
+}
+
+// labelEditKeys returns the keys of the labels editor rows, sorted: the
+// container's labels, removed ones included, and the added ones
+func (m *Model) labelEditKeys() []string {
+	keys := slices.Collect(maps.Keys(m.labelEditAfter))
+	for key := range m.labelEditBefore {
+		if _, kept := m.labelEditAfter[key]; !kept {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// handleLabelEditorKeys edits the labels of the inspected container: a
+// adds one, e changes the selected one, d removes or restores it, and s
+// recreates the container with the result after a confirmation
+func (m *Model) handleLabelEditorKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.labelEditMode {
+		return m.handleLabelInputKeys(msg)
+	}
+	keys := m.labelEditKeys()
+	if m.labelEditConfirm {
+		switch msg.Type {
+		case tea.KeyEsc:
+			m.labelEditConfirm = false
+		case tea.KeyEnter:
+			container := *m.selectedContainer
+			m.labelEditConfirm = false
+			m.closeInspect()
+			m.actionInProgress = true
+			m.statusMessage = "Recreating " + container.Name + " with updated labels..."
+			return m, m.recreateWithLabelsCmd(container, m.labelEditAfter)
+		}
+		return m, nil
+	}
+
+	m.labelEditErr = ""
+	switch msg.String() {
+	case "q", "Q":
+		return m.handleQuit()
+	case "esc", "backspace":
+		m.currentView = types.ViewModeInspect
+	case "up", "k":
+		if m.labelEditCursor > 0 {
+			m.labelEditCursor--
+		}
+	case "down", "j":
+		if m.labelEditCursor < len(keys)-1 {
+			m.labelEditCursor++
+		}
+	case "a", "A":
+		m.labelEditMode = true
+		m.labelEditKey = ""
+		m.labelEditInput = ""
+	case "e", "E", "enter":
+		if m.labelEditCursor < len(keys) {
+			key := keys[m.labelEditCursor]
+			value, kept := m.labelEditAfter[key]
+			if !kept {
+				value = m.labelEditBefore[key]
+			}
+			m.labelEditMode = true
+			m.labelEditKey = key
+			m.labelEditInput = key + "=" + value
+		}
+	case "d", "D", "x", "X":
+		// Remove the selected label, or restore it when already removed
+		if m.labelEditCursor < len(keys) {
+			key := keys[m.labelEditCursor]
+			if _, kept := m.labelEditAfter[key]; kept {
+				delete(m.labelEditAfter, key)
+			} else {
+				m.labelEditAfter[key] = m.labelEditBefore[key]
+			}
+			m.labelEditCursor = min(m.labelEditCursor, max(len(m.labelEditKeys())-1, 0))
+		}
+	case "s", "S":
+		if len(docker.LabelChanges(m.labelEditBefore, m.labelEditAfter)) == 0 {
+			m.labelEditErr = "No changes to apply"
+			return m, nil
+		}
+		m.labelEditConfirm = true
+	}
+	return m, nil
+}
+
+// handleLabelInputKeys edits the KEY=VALUE of a label being added or
+// changed; an empty value is allowed, Docker keeps labels without one
+func (m *Model) handleLabelInputKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.labelEditMode = false
+		m.labelEditErr = ""
+	case tea.KeyBackspace:
+		if runes := []rune(m.labelEditInput); len(runes) > 0 {
+			m.labelEditInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.labelEditInput += " "
+	case tea.KeyRunes:
+		m.labelEditInput += string(msg.Runes)
+	case tea.KeyEnter:
+		key, value, _ := strings.Cut(m.labelEditInput, "=")
+		key = strings.TrimSpace(key)
+		if key == "" || strings.ContainsAny(key, " \t") {
+			m.labelEditErr = "Enter KEY=VALUE, with a key without spaces"
+			return m, nil
+		}
+		// Renaming a label replaces the old one
+		if m.labelEditKey != "" && m.labelEditKey != key {
+			delete(m.labelEditAfter, m.labelEditKey)
+		}
+		m.labelEditAfter[key] = value
+		m.labelEditMode = false
+		m.labelEditErr = ""
+		m.labelEditCursor = max(slices.Index(m.labelEditKeys(), key), 0)
+	}
+	return m, nil
+}
+
+// handleInspectExportKeys edits the export path and writes the inspect
+// JSON there on enter
+func (m *Model) handleInspectExportKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.inspectExportMode = false
+	case tea.KeyBackspace:
+		input := []rune(m.inspectExportInput)
+		if len(input) > 0 {
+			m.inspectExportInput = string(input[:len(input)-1])
+		}
+	case tea.KeySpace:
+		m.inspectExportInput += " "
+	case tea.KeyRunes:
+		m.inspectExportInput += string(msg.Runes)
+	case tea.KeyEnter:
+		path := strings.TrimSpace(m.inspectExportInput)
+		if path == "" {
+			return m, nil
+		}
+		if rest, ok := strings.CutPrefix(path, "~/"); ok {
+			if home, err := os.UserHomeDir(); err == nil {
+				path = filepath.Join(home, rest)
+			}
+		}
+
+		m.inspectExportMode = false
+		if err := os.WriteFile(path, []byte(m.inspectRaw+"\n"), 0o644); err != nil {
+			m.inspectExportMsg = "Export failed: " + err.Error()
+		} else {
+			m.inspectExportMsg = "Saved to " + path
+		}
+	}
+	return m, nil
+}
+
+// inspectSubject names what the inspect view shows, for file names
+func (m *Model) inspectSubject() string {
+	name := "inspect"
+	switch {
+	case m.activeTab == 0 && m.selectedContainer != nil:
+		name = m.selectedContainer.Name
+	case m.activeTab == 1 && m.selectedImage != nil:
+		name = m.selectedImage.Repository + "_" + m.selectedImage.Tag
+		if m.selectedImage.Repository == "<none>" {
+			name = m.selectedImage.ID
+		}
+	case m.activeTab == 2 && m.selectedVolume != nil:
+		name = m.selectedVolume.Name
+	case m.activeTab == 3 && m.selectedNetwork != nil:
+		name = m.selectedNetwork.Name
+	}
+	return strings.NewReplacer("/", "_", ":", "_").Replace(name)
+}
+
+// handleBulkEnvKeys processes input in the bulk env editor: first collect
+// KEY=VALUE changes, then preview them per container and confirm
+func (m *Model) handleBulkEnvKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Preview stage: Enter recreates, Esc goes back to editing
+	if m.bulkEnvPreview != nil {
+		switch msg.Type {
+		case tea.KeyEsc:
+			m.bulkEnvPreview = nil
+		case tea.KeyEnter:
+			previews := m.bulkEnvPreview
+			m.currentView = types.ViewModeList
+			m.bulkEnvPreview = nil
+			m.marked = make(map[string]bool)
+			m.actionInProgress = true
+			m.statusMessage = "Recreating containers..."
+			return m, m.bulkEnvApplyCmd(previews, m.bulkEnvChanges)
+		}
+		return m, nil
+	}
+	if m.bulkEnvLoading {
+		if msg.Type == tea.KeyEsc {
+			m.bulkEnvLoading = false
+			m.currentView = types.ViewModeList
+		}
+		return m, nil
+	}
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.currentView = types.ViewModeList
+	case tea.KeyEnter:
+		input := strings.TrimSpace(m.bulkEnvInput)
+		if input == "" {
+			// Empty input: done adding, preview the changes
+			if len(m.bulkEnvChanges) == 0 {
+				return m, nil
+			}
+			m.bulkEnvLoading = true
+			return m, m.previewBulkEnvCmd(m.bulkEnvTargets, m.bulkEnvChanges)
+		}
+		key, value, ok := strings.Cut(input, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return m, nil
+		}
+		m.bulkEnvChanges = append(m.bulkEnvChanges, types.EnvVar{Key: strings.TrimSpace(key), Value: value})
+		m.bulkEnvInput = ""
+	case tea.KeyBackspace:
+		if m.bulkEnvInput == "" && len(m.bulkEnvChanges) > 0 {
+			// Backspace on an empty input removes the last change
+			m.bulkEnvChanges = m.bulkEnvChanges[:len(m.bulkEnvChanges)-1]
+		} else if runes := []rune(m.bulkEnvInput); len(runes) > 0 {
+			m.bulkEnvInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.bulkEnvInput += " "
+	case tea.KeyRunes:
+		m.bulkEnvInput += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// handleLayersViewKeys processes input in the image layer browser
+func (m *Model) handleLayersViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+
+	switch key {
+	case "q", "Q":
+		return m.handleQuit()
+
+	case "esc":
+		if m.layerFilesOpen || m.layerWasteOpen {
+			m.layerFilesOpen = false
+			m.layerWasteOpen = false
+			m.layerScroll = 0
+			return m, nil
+		}
+		m.currentView = types.ViewModeInspect
+		m.layers = nil
+		return m, nil
+
+	case "up", "k":
+		if m.layerFilesOpen || m.layerWasteOpen {
+			if m.layerScroll > 0 {
+				m.layerScroll--
+			}
+		} else if m.layerCursor > 0 {
+			m.layerCursor--
+		}
+		return m, nil
+
+	case "down", "j":
+		if m.layerFilesOpen {
+			if m.layerScroll < len(m.layers[m.layerCursor].Files)-1 {
+				m.layerScroll++
+			}
+		} else if m.layerWasteOpen {
+			if m.layerScroll < len(m.layerWaste.Items)-1 {
+				m.layerScroll++
+			}
+		} else if m.layerCursor < len(m.layers)-1 {
+			m.layerCursor++
+		}
+		return m, nil
+
+	case "enter":
+		if !m.layerFilesOpen && !m.layerWasteOpen && m.layerCursor < len(m.layers) {
+			m.layerFilesOpen = true
+			m.layerScroll = 0
+		}
+		return m, nil
+
+	case "w", "W":
+		// Waste report over all layers
+		if !m.layerFilesOpen && m.layers != nil {
+			m.layerWasteOpen = !m.layerWasteOpen
+			m.layerWaste = docker.AnalyzeWaste(m.layers)
+			m.layerScroll = 0
+		}
+		return m, nil
+
+	default:
+		return m, nil
+	}
+}
+
+// handleWatchViewKeys processes input in the watch modal
+func (m *Model) handleWatchViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+
+	switch key {
+	case "esc":
+		m.currentView = types.ViewModeList
+		m.watchMounts = nil
+		return m, nil
+
+	case "up", "k":
+		if m.watchCursor > 0 {
+			m.watchCursor--
+		}
+		return m, nil
+
+	case "down", "j":
+		if m.watchCursor < len(m.watchMounts)-1 {
+			m.watchCursor++
+		}
+		return m, nil
+
+	case " ":
+		if m.watchCursor < len(m.watchSelected) {
+			m.watchSelected[m.watchCursor] = !m.watchSelected[m.watchCursor]
+		}
+		return m, nil
+
+	case "tab":
+		m.watchSendSignal = !m.watchSendSignal
+		return m, nil
+
+	case "enter":
+		if m.selectedContainer == nil || len(m.watchMounts) == 0 {
+			return m, nil
+		}
+		var paths []string
+		for i, mount := range m.watchMounts {
+			if m.watchSelected[i] {
+				paths = append(paths, mount.Source)
+			}
+		}
+		if len(paths) == 0 {
+			return m, nil
+		}
+
+		m.watches[m.selectedContainer.ID] = &watchState{
+			containerName: m.selectedContainer.Name,
+			paths:         paths,
+			sendSignal:    m.watchSendSignal,
+		}
+		m.currentView = types.ViewModeList
+		m.watchMounts = nil
+		m.statusMessage = fmt.Sprintf("Watching %d path(s) of %s", len(paths), m.selectedContainer.Name)
+
+		if !m.watchTicking {
+			m.watchTicking = true
+			return m, watchTickCmd()
+		}
+		return m, nil
+
+	default:
+		return m, nil
+	}
+}
+
+// handleWatchResult compares a fresh fingerprint against the last one and
+// reloads the container once a change has settled
+func (m *Model) handleWatchResult(msg types.WatchResultMsg) (tea.Model, tea.Cmd) {
+	w, ok := m.watches[msg.ContainerID]
+	if !ok {
+		return m, nil
+	}
+	w.polling = false
+
+	if msg.Err != nil {
+		delete(m.watches, msg.ContainerID)
+		m.statusMessage = "ERROR: Stopped watching " + w.containerName + ": " + msg.Err.Error()
+		return m, nil
+	}
+
+	if !w.primed {
+		w.fingerprint = msg.Fingerprint
+		w.primed = true
+		return m, nil
+	}
+
+	if msg.Fingerprint != w.fingerprint {
+		// Wait one more poll so a burst of saves triggers a single reload
+		w.fingerprint = msg.Fingerprint
+		w.dirty = true
+		return m, nil
+	}
+
+	if !w.dirty {
+		return m, nil
+	}
+	w.dirty = false
+
+	if w.sendSignal {
+		m.statusMessage = "Change detected, sending SIGHUP to " + w.containerName
+		return m, m.signalContainerCmd(msg.ContainerID, w.containerName, "SIGHUP")
+	}
+	m.statusMessage = "Change detected, restarting " + w.containerName
+	return m, m.restartContainerCmd(msg.ContainerID, w.containerName)
+}
+
+// getMaxRow returns the number of items in the current tab
+func (m *Model) getMaxRow() int {
+	switch m.activeTab {
+	case 0:
+		return len(m.containers)
+	case 1:
+		return len(m.images)
+	case 2:
+		return len(m.volumes)
+	case 3:
+		return len(m.networks)
+	}
+	return 0
+}
+
+// Container action handlers
+
+func (m *Model) handleContainerStartStop() (tea.Model, tea.Cmd) {
+	if m.selectedRow >= len(m.containers) {
+		return m, nil
+	}
+	container := m.containers[m.selectedRow]
+
+	// Grouped compose services start and stop as a whole
+	if len(container.Replicas) > 0 {
+		action := "start"
+		if container.Status == "RUNNING" {
+			action = "stop"
+		}
+		m.actionInProgress = true
+		return m, m.serviceActionCmd(action, container.ComposeService, docker.ServiceReplicas(container))
+	}
+
+	// Toggle start/stop based on current status
+	if container.Status == "RUNNING" {
+		m.actionInProgress = true
+		return m, m.stopContainerCmd(container.ID, container.Name)
+	} else {
+		m.actionInProgress = true
+		return m, m.startContainerCmd(container.ID, container.Name)
+	}
+}
+
+// handleContainerPause pauses the selected running container, or unpauses
+// it when paused
+func (m *Model) handleContainerPause() (tea.Model, tea.Cmd) {
+	if m.selectedRow >= len(m.containers) {
+		return m, nil
+	}
+	container := m.containers[m.selectedRow]
+
+	switch container.Status {
+	case "RUNNING":
+		m.actionInProgress = true
+		return m, m.pauseContainerCmd(container.ID, container.Name, true)
+	case "PAUSED":
+		m.actionInProgress = true
+		return m, m.pauseContainerCmd(container.ID, container.Name, false)
+	}
+	m.statusMessage = "Container must be running to pause"
+	return m, nil
+}
+
+func (m *Model) handleContainerRestart() (tea.Model, tea.Cmd) {
+	if m.selectedRow >= len(m.containers) {
+		return m, nil
+	}
+	container := m.containers[m.selectedRow]
+
+	// Only restart if running
+	if container.Status != "RUNNING" {
+		m.statusMessage = "Container must be running to restart"
+		return m, nil
+	}
+
+	m.actionInProgress = true
+	if len(container.Replicas) > 0 {
+		return m, m.serviceActionCmd("restart", container.ComposeService, docker.ServiceReplicas(container))
+	}
+	return m, m.restartContainerCmd(container.ID, container.Name)
+}
+
+// handleContainerCapture opens the packet capture prompt for the selected
+// running container
+func (m *Model) handleContainerCapture() (tea.Model, tea.Cmd) {
+	if m.selectedRow >= len(m.containers) {
+		return m, nil
+	}
+	container := m.containers[m.selectedRow]
+	if container.Status != "RUNNING" {
+		m.statusMessage = "Container must be running to capture traffic"
+		return m, nil
+	}
+
+	m.selectedContainer = &container
+	m.captureMode = true
+	m.captureField = 0
+	m.captureInput = [2]string{"", "10"}
+	return m, nil
+}
+
+// handleCaptureKeys edits the capture port and duration and starts the
+// capture on enter
+func (m *Model) handleCaptureKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.captureMode = false
+	case tea.KeyTab, tea.KeyShiftTab, tea.KeyUp, tea.KeyDown:
+		m.captureField = 1 - m.captureField
+	case tea.KeyBackspace:
+		if field := m.captureInput[m.captureField]; len(field) > 0 {
+			m.captureInput[m.captureField] = field[:len(field)-1]
+		}
+	case tea.KeyRunes:
+		// Both fields are numbers
+		for _, r := range msg.Runes {
+			if r >= '0' && r <= '9' {
+				m.captureInput[m.captureField] += string(r)
+			}
+		}
+	case tea.KeyEnter:
+		port := 0
+		if m.captureInput[0] != "" {
+			port, _ = strconv.Atoi(m.captureInput[0])
+			if port < 1 || port > 65535 {
+				m.statusMessage = "Port must be between 1 and 65535"
+				return m, nil
+			}
+		}
+		seconds, _ := strconv.Atoi(m.captureInput[1])
+		if seconds < 1 {
+			m.statusMessage = "Capture needs a duration of at least 1 second"
+			return m, nil
+		}
+
+		m.captureMode = false
+		target := "all traffic"
+		if port > 0 {
+			target = "port " + m.captureInput[0]
+		}
+		m.captureTask(*m.selectedContainer, port, time.Duration(seconds)*time.Second)
+		m.statusMessage = fmt.Sprintf("Capturing %s on %s for %ds, [T] Tasks", target, m.selectedContainer.Name, seconds)
+		return m, nil
+	}
+	return m, nil
+}
+
+// handleContainerDNS asks for a hostname (and optional port) to check from
+// inside the selected running container
+func (m *Model) handleContainerDNS() (tea.Model, tea.Cmd) {
+	if m.selectedRow >= len(m.containers) {
+		return m, nil
+	}
+	container := m.containers[m.selectedRow]
+	if container.Status != "RUNNING" {
+		m.statusMessage = "Container must be running to check DNS"
+		return m, nil
+	}
+
+	m.selectedContainer = &container
+	m.dnsPromptMode = true
+	return m, nil
+}
+
+// handleDNSPromptKeys edits the DNS check target and runs the check on enter
+func (m *Model) handleDNSPromptKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.dnsPromptMode = false
+	case tea.KeyEnter:
+		host, port, err := docker.ParseHostPort(m.dnsPromptInput)
+		if err != nil {
+			m.statusMessage = err.Error()
+			return m, nil
+		}
+		m.dnsPromptMode = false
+		m.actionInProgress = true
+		m.statusMessage = fmt.Sprintf("Resolving %s from %s...", host, m.selectedContainer.Name)
+		return m, m.containerDNSCmd(m.selectedContainer.ID, m.selectedContainer.Name, host, port)
+	case tea.KeyBackspace:
+		if len(m.dnsPromptInput) > 0 {
+			runes := []rune(m.dnsPromptInput)
+			m.dnsPromptInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes:
+		m.dnsPromptInput += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// handleNoteEdit opens the prompt editing the note on the selected
+// container or image
+func (m *Model) handleNoteEdit() (tea.Model, tea.Cmd) {
+	switch {
+	case m.activeTab == 0 && m.selectedRow < len(m.containers):
+		c := m.containers[m.selectedRow]
+		m.noteKind, m.noteID, m.noteName = notes.Container, c.ID, c.Name
+	case m.activeTab == 1 && m.selectedRow < len(m.images):
+		img := m.images[m.selectedRow]
+		m.noteKind, m.noteID, m.noteName = notes.Image, img.ID, imageNoteName(img)
+	default:
+		return m, nil
+	}
+
+	note, _ := m.notes.Get(m.noteKind, m.noteID, m.noteName)
+	m.noteInput = note.Text
+	m.notePromptMode = true
+	return m, nil
+}
+
+// handleNotePromptKeys edits the note and saves it on enter; saving an empty
+// note removes it
+func (m *Model) handleNotePromptKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.notePromptMode = false
+	case tea.KeyEnter:
+		m.notePromptMode = false
+		if err := m.notes.Set(m.noteKind, m.noteID, m.noteName, m.noteInput); err != nil {
+			m.statusMessage = "ERROR: " + err.Error()
+			return m, nil
+		}
+		if strings.TrimSpace(m.noteInput) == "" {
+			m.statusMessage = "Removed the note on " + m.noteLabel()
+		} else {
+			m.statusMessage = "Saved the note on " + m.noteLabel()
+		}
+	case tea.KeyBackspace:
+		if len(m.noteInput) > 0 {
+			runes := []rune(m.noteInput)
+			m.noteInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.noteInput += " "
+	case tea.KeyRunes:
+		m.noteInput += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// handleContainerCommit asks for the image reference, comment and author
+// to commit the selected container to
+func (m *Model) handleContainerCommit() (tea.Model, tea.Cmd) {
+	if m.selectedRow >= len(m.containers) {
+		return m, nil
+	}
+	container := m.containers[m.selectedRow]
+	m.selectedContainer = &container
+
+	// Image names are lowercase, container names need not be
+	m.commitInput = [3]string{strings.ToLower(container.Name) + ":snapshot", "", ""}
+	m.commitField = 0
+	m.commitMode = true
+	return m, nil
+}
+
+// handleCommitKeys edits the commit fields and queues the commit on enter
+func (m *Model) handleCommitKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.commitMode = false
+	case tea.KeyTab, tea.KeyDown:
+		m.commitField = (m.commitField + 1) % len(m.commitInput)
+	case tea.KeyShiftTab, tea.KeyUp:
+		m.commitField = (m.commitField + len(m.commitInput) - 1) % len(m.commitInput)
+	case tea.KeyBackspace:
+		if field := m.commitInput[m.commitField]; len(field) > 0 {
+			runes := []rune(field)
+			m.commitInput[m.commitField] = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		// Spaces only make sense in the comment and author
+		if m.commitField > 0 {
+			m.commitInput[m.commitField] += " "
+		}
+	case tea.KeyRunes:
+		m.commitInput[m.commitField] += string(msg.Runes)
+	case tea.KeyEnter:
+		ref := strings.TrimSpace(m.commitInput[0])
+		if ref == "" {
+			m.statusMessage = "Enter the repository:tag of the new image"
+			return m, nil
+		}
+		m.commitMode = false
+		m.commitTask(*m.selectedContainer, ref, strings.TrimSpace(m.commitInput[1]), strings.TrimSpace(m.commitInput[2]))
+	}
+	return m, nil
+}
+
+// handleLimitsKeys edits the limits of m.selectedContainer and applies
+// them on enter
+func (m *Model) handleLimitsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	field := &m.limitsInput[m.limitsField]
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.limitsMode = false
+	case tea.KeyTab, tea.KeyDown:
+		m.limitsField = (m.limitsField + 1) % len(m.limitsInput)
+	case tea.KeyShiftTab, tea.KeyUp:
+		m.limitsField = (m.limitsField + len(m.limitsInput) - 1) % len(m.limitsInput)
+	case tea.KeyBackspace:
+		if len(*field) > 0 {
+			runes := []rune(*field)
+			*field = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes:
+		*field += string(msg.Runes)
+	case tea.KeyEnter:
+		m.limitsMode = false
+		limits := types.ResourceLimits{
+			CPUShares: m.limitsInput[0],
+			CPUs:      m.limitsInput[1],
+			Memory:    m.limitsInput[2],
+			Restart:   m.limitsInput[3],
+		}
+		return m, m.updateLimitsCmd(m.selectedContainer.ID, m.selectedContainer.Name, limits)
+	}
+	return m, nil
+}
+
+// handlePruneKeys edits the filter of a prune of stopped containers and
+// runs it on enter
+func (m *Model) handlePruneKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.pruneMode = false
+	case tea.KeyBackspace:
+		if runes := []rune(m.pruneInput); len(runes) > 0 {
+			m.pruneInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.pruneInput += " "
+	case tea.KeyRunes:
+		m.pruneInput += string(msg.Runes)
+	case tea.KeyEnter:
+		if _, err := docker.ParsePruneFilter(m.pruneInput); err != nil {
+			m.pruneErr = err.Error()
+			return m, nil
+		}
+		m.pruneMode = false
+		m.statusMessage = "Pruning stopped containers..."
+		return m, m.pruneContainersCmd(m.pruneInput)
+	}
+	return m, nil
+}
+
+// handleDrain asks how long to wait for the clients of the selected
+// running container to disconnect before stopping it
+func (m *Model) handleDrain() (tea.Model, tea.Cmd) {
+	if m.selectedRow >= len(m.containers) {
+		return m, nil
+	}
+	container := m.containers[m.selectedRow]
+	if container.Status != "RUNNING" {
+		m.statusMessage = "Container must be running to drain it"
+		return m, nil
+	}
+
+	m.selectedContainer = &container
+	m.drainMode = true
+	m.drainInput = "5m"
+	return m, nil
+}
+
+// handleDrainKeys edits the longest wait of a drain and queues it on enter;
+// a bare number is seconds
+func (m *Model) handleDrainKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.drainMode = false
+	case tea.KeyEnter:
+		input := strings.TrimSpace(m.drainInput)
+		if _, err := strconv.Atoi(input); err == nil {
+			input += "s"
+		}
+		maxWait, err := time.ParseDuration(input)
+		if err != nil || maxWait <= 0 {
+			m.statusMessage = "Enter the longest wait as a duration, e.g. 90s or 5m"
+			return m, nil
+		}
+		m.drainMode = false
+		m.drainTask(*m.selectedContainer, maxWait)
+	case tea.KeyBackspace:
+		if len(m.drainInput) > 0 {
+			runes := []rune(m.drainInput)
+			m.drainInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes:
+		m.drainInput += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// handleFileCopy asks for the paths of a copy between the selected
+// container and the host, like `docker cp`
+func (m *Model) handleFileCopy(toContainer bool) (tea.Model, tea.Cmd) {
+	if m.selectedRow >= len(m.containers) {
+		return m, nil
+	}
+	container := m.containers[m.selectedRow]
+	m.selectedContainer = &container
+
+	m.fileCopyMode = true
+	m.fileCopyToContainer = toContainer
+	m.fileCopyField = 0
+	m.fileCopyInput = [2]string{"", "."}
+	if toContainer {
+		m.fileCopyInput = [2]string{"", "/tmp"}
+	}
+	return m, nil
+}
+
+// handleFileCopyKeys edits the source and destination of a file copy and
+// queues it as a background task on enter
+func (m *Model) handleFileCopyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.fileCopyMode = false
+	case tea.KeyTab, tea.KeyShiftTab, tea.KeyDown, tea.KeyUp:
+		m.fileCopyField = 1 - m.fileCopyField
+	case tea.KeyBackspace:
+		if field := m.fileCopyInput[m.fileCopyField]; len(field) > 0 {
+			runes := []rune(field)
+			m.fileCopyInput[m.fileCopyField] = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.fileCopyInput[m.fileCopyField] += " "
+	case tea.KeyRunes:
+		m.fileCopyInput[m.fileCopyField] += string(msg.Runes)
+	case tea.KeyEnter:
+		src, dest := strings.TrimSpace(m.fileCopyInput[0]), strings.TrimSpace(m.fileCopyInput[1])
+		if src == "" || dest == "" {
+			m.statusMessage = "Enter both a source and a destination path"
+			return m, nil
+		}
+		m.fileCopyMode = false
+		m.fileCopyTask(*m.selectedContainer, src, dest, m.fileCopyToContainer)
+	}
+	return m, nil
+}
+
+// imageNoteName is the name an image note falls back to once the image ID
+// is gone, e.g. after pulling the tag again; dangling images have none
+func imageNoteName(img types.Image) string {
+	if img.Dangling {
+		return ""
+	}
+	return img.Repository + ":" + img.Tag
+}
+
+// noteLabel names the object of the note being edited
+func (m *Model) noteLabel() string {
+	if m.noteName == "" {
+		return m.noteID
+	}
+	return m.noteName
+}
+
+// handleContainerClock compares the clock and timezone of the selected
+// running container to the host's
+func (m *Model) handleContainerClock() (tea.Model, tea.Cmd) {
+	if m.selectedRow >= len(m.containers) {
+		return m, nil
+	}
+	container := m.containers[m.selectedRow]
+	if container.Status != "RUNNING" {
+		m.statusMessage = "Container must be running to check its clock"
+		return m, nil
+	}
+
+	m.actionInProgress = true
+	return m, m.containerClockCmd(container.ID, container.Name)
+}
+
+// handleServiceScale adds ("+") or removes ("-") a replica of the compose
+// service of the selected container
+func (m *Model) handleServiceScale(key string) (tea.Model, tea.Cmd) {
+	if m.selectedRow >= len(m.containers) {
+		return m, nil
+	}
+	container := m.containers[m.selectedRow]
+	if container.ComposeService == "" {
+		m.statusMessage = "Not part of a compose service"
+		return m, nil
+	}
+
+	replicas := m.serviceReplicas(container)
+	if key == "-" && len(replicas) == 1 {
+		m.statusMessage = container.ComposeService + " has a single replica; delete it instead"
+		return m, nil
+	}
+
+	if key == "+" {
+		m.actionInProgress = true
+		return m, m.addReplicaCmd(container.ComposeService, replicas)
+	}
+
+	// Scaling down deletes a replica, running or not: ask first
+	m.scaleDownService = container.ComposeService
+	m.scaleDownReplicas = replicas
+	last := highestReplica(replicas)
+	m.deleteConfirm = components.NewConfirmModal("Scale "+container.ComposeService+" down, deleting "+components.TruncateMiddle(last.Name, 40)+"? ", false)
+	return m, nil
+}
+
+// serviceReplicas returns every replica of the compose service of c, whether
+// or not replicas are grouped
+func (m *Model) serviceReplicas(c types.Container) []types.Container {
+	var replicas []types.Container
+	for _, row := range m.containers {
+		if row.ComposeProject == c.ComposeProject && row.ComposeService == c.ComposeService {
+			replicas = append(replicas, docker.ServiceReplicas(row)...)
+		}
+	}
+	return replicas
+}
+
+func (m *Model) handleContainerLogs() (tea.Model, tea.Cmd) {
+	if m.selectedRow >= len(m.containers) {
+		return m, nil
+	}
+	container := m.containers[m.selectedRow]
+	m.selectedContainer = &container
+	m.currentView = types.ViewModeLogs
+	m.logsContent = ""
+	m.logsStderr = nil
+	m.logsScrollOffset = 0
+	m.logsSince = time.Time{}
+	m.logsUntil = time.Time{}
+	m.logsRangeInput = [2]string{}
+	m.logsTail = m.logsTailDefault
+	m.logsMoreAnchor = ""
+	m.logsSearchQuery = ""
+	m.logsSearchRe = nil
+	m.logsMatchInfo = ""
+	return m, m.getContainerLogsCmd(container.ID)
+}
+
+// handleContainerProcesses opens the processes view of the selected
+// container, like `docker top`
+func (m *Model) handleContainerProcesses() (tea.Model, tea.Cmd) {
+	if m.selectedRow >= len(m.containers) {
+		return m, nil
+	}
+	container := m.containers[m.selectedRow]
+	if container.Status != "RUNNING" {
+		m.statusMessage = container.Name + " is not running: it has no processes to list"
+		return m, nil
+	}
+	m.topContainer = container
+	m.topProcesses = nil
+	m.topLoaded = false
+	m.topErr = ""
+	m.topScroll = 0
+	m.currentView = types.ViewModeProcesses
+	return m, m.containerProcessesCmd(container.ID)
+}
+
+// handleStatsDashboard opens the stats dashboard of the selected
+// container, streaming its stats even where the list streams none
+func (m *Model) handleStatsDashboard() (tea.Model, tea.Cmd) {
+	if m.selectedRow >= len(m.containers) {
+		return m, nil
+	}
+	container := m.containers[m.selectedRow]
+	if container.Status != "RUNNING" {
+		m.statusMessage = container.Name + " is not running: it has no stats to graph"
+		return m, nil
+	}
+	if container.ID != m.dashContainer.ID {
+		m.dashHistory.Clear()
+	}
+	m.dashContainer = container
+	m.currentView = types.ViewModeStats
+	return m, tea.Batch(
+		m.syncStatsCmd(m.streamedContainerIDs()),
+		dashboardTickCmd(container.ID),
+	)
+}
+
+// handleStatsDashboardKeys leaves the stats dashboard
+func (m *Model) handleStatsDashboardKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "Q":
+		return m.handleQuit()
+	case "esc", "backspace":
+		m.currentView = types.ViewModeList
+	}
+	return m, nil
+}
+
+// handleProcessesViewKeys scrolls the processes view
+func (m *Model) handleProcessesViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "Q":
+		return m.handleQuit()
+	case "esc":
+		m.currentView = types.ViewModeList
+		m.topProcesses = nil
+	case "up", "k":
+		if m.topScroll > 0 {
+			m.topScroll--
+		}
+	case "down", "j":
+		if m.topScroll < len(m.topProcesses)-1 {
+			m.topScroll++
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) handleContainerInspect() (tea.Model, tea.Cmd) {
+	if m.selectedRow >= len(m.containers) {
+		return m, nil
+	}
+	container := m.containers[m.selectedRow]
+	m.selectedContainer = &container
+	m.pushNav()
+	m.currentView = types.ViewModeInspect
+	m.inspectContent = ""
+	return m, m.inspectContainerCmd(container.ID)
+}
+
+func (m *Model) handleContainerDelete() (tea.Model, tea.Cmd) {
+	if m.selectedRow >= len(m.containers) {
+		return m, nil
+	}
+	m.openDeleteConfirm(m.containers[m.selectedRow].Name)
+	return m, nil
+}
+
+// watchedReplica returns the ID of the watched container behind a row, which
+// may be a replica grouping folded into it
+func (m *Model) watchedReplica(c types.Container) (string, bool) {
+	for _, replica := range docker.ServiceReplicas(c) {
+		if _, ok := m.watches[replica.ID]; ok {
+			return replica.ID, true
+		}
+	}
+	return "", false
+}
+
+func (m *Model) handleContainerWatch() (tea.Model, tea.Cmd) {
+	if m.selectedRow >= len(m.containers) {
+		return m, nil
+	}
+	container := m.containers[m.selectedRow]
+
+	// Pressing W on a watched container stops the watch
+	if id, ok := m.watchedReplica(container); ok {
+		m.statusMessage = "Stopped watching " + m.watches[id].containerName
+		delete(m.watches, id)
+		return m, nil
+	}
+
+	// The sources are paths on the daemon's host, only found here when the
+	// daemon is reached through a local socket
+	if !docker.LocalHost(m.docker.Underlying().DaemonHost()) {
+		m.statusMessage = "ERROR: Bind mounts can only be watched on a local daemon"
+		return m, nil
+	}
+
+	m.selectedContainer = &container
+	m.currentView = types.ViewModeWatch
+	m.watchMounts = nil
+	m.watchSendSignal = false
+	return m, m.getBindMountsCmd(container.ID)
+}
+
+// handleContainerBulkEnv opens the env editor for the marked containers, or
+// the selected one when none are marked
+func (m *Model) handleContainerBulkEnv() (tea.Model, tea.Cmd) {
+	m.bulkEnvTargets = m.markedContainers()
+	if len(m.bulkEnvTargets) == 0 {
+		if m.selectedRow >= len(m.containers) {
+			return m, nil
+		}
+		m.bulkEnvTargets = []types.Container{m.containers[m.selectedRow]}
+	}
+
+	m.currentView = types.ViewModeBulkEnv
+	m.bulkEnvChanges = nil
+	m.bulkEnvInput = ""
+	m.bulkEnvPreview = nil
+	m.bulkEnvLoading = false
+	return m, nil
+}
+
+// markedContainers returns the marked containers in list order
+func (m *Model) markedContainers() []types.Container {
+	var marked []types.Container
+	for _, c := range m.containers {
+		if m.marked[c.ID] {
+			marked = append(marked, c)
+		}
+	}
+	return marked
+}
+
+// markedSet returns the marks of the active tab
+func (m *Model) markedSet() map[string]bool {
+	switch m.activeTab {
+	case 1:
+		return m.markedImages
+	case 2:
+		return m.markedVolumes
+	case 3:
+		return m.markedNetworks
+	}
+	return m.marked
+}
+
+// markedCount returns how many rows of the active tab are marked
+func (m *Model) markedCount() int {
+	return len(m.markedSet())
+}
+
+// rowKey returns the key the row of the active tab is marked by
+func (m *Model) rowKey(row int) (string, bool) {
+	switch m.activeTab {
+	case 0:
+		if row < len(m.containers) {
+			return m.containers[row].ID, true
+		}
+	case 1:
+		if row < len(m.images) {
+			return m.images[row].ID, true
+		}
+	case 2:
+		if row < len(m.volumes) {
+			return m.volumes[row].Name, true
+		}
+	case 3:
+		if row < len(m.networks) {
+			return m.networks[row].ID, true
+		}
+	}
+	return "", false
+}
+
+// isMarked reports whether a row of the active tab is marked, or inside
+// the range being marked
+func (m *Model) isMarked(row int) bool {
+	if m.visualAnchor >= 0 && row >= min(m.visualAnchor, m.selectedRow) && row <= max(m.visualAnchor, m.selectedRow) {
+		return true
+	}
+	id, ok := m.rowKey(row)
+	return ok && m.markedSet()[id]
+}
+
+// markVisualRange marks the rows between where v was pressed and the
+// selected row, and stops marking a range
+func (m *Model) markVisualRange() {
+	marked := m.markedSet()
+	for row := min(m.visualAnchor, m.selectedRow); row <= max(m.visualAnchor, m.selectedRow); row++ {
+		if id, ok := m.rowKey(row); ok {
+			marked[id] = true
+		}
+	}
+	m.visualAnchor = -1
+}
+
+// openBulkStartStop asks whether to stop the marked running containers,
+// or to start the marked ones when none runs. Folded service rows stand
+// for all their replicas.
+func (m *Model) openBulkStartStop() (tea.Model, tea.Cmd) {
+	var running, stopped []bulkTarget
+	for _, row := range m.markedContainers() {
+		for _, c := range docker.ServiceReplicas(row) {
+			if c.Status == "RUNNING" {
+				running = append(running, bulkTarget{c.ID, c.Name})
+			} else if c.Status != "PAUSED" {
+				stopped = append(stopped, bulkTarget{c.ID, c.Name})
+			}
+		}
+	}
+
+	m.bulkAction, m.bulkTargets = "stop", running
+	if len(running) == 0 {
+		m.bulkAction, m.bulkTargets = "start", stopped
+	}
+	if len(m.bulkTargets) == 0 {
+		m.statusMessage = "None of the marked containers can be started or stopped"
+		return m, nil
+	}
+	prompt := fmt.Sprintf("%s %d marked container(s)? ", titleCase(m.bulkAction), len(m.bulkTargets))
+	m.bulkConfirm = components.NewConfirmModal(prompt, false)
+	return m, nil
+}
+
+// openBulkDelete asks whether to delete the marked rows of the active tab
+func (m *Model) openBulkDelete() (tea.Model, tea.Cmd) {
+	m.bulkAction, m.bulkTargets = "delete", nil
+	switch m.activeTab {
+	case 0:
+		for _, row := range m.markedContainers() {
+			for _, c := range docker.ServiceReplicas(row) {
+				m.bulkTargets = append(m.bulkTargets, bulkTarget{c.ID, c.Name})
+			}
+		}
+	case 1:
+		for _, img := range m.images {
+			if m.markedImages[img.ID] {
+				m.bulkTargets = append(m.bulkTargets, bulkTarget{img.ID, img.Repository + ":" + img.Tag})
+			}
+		}
+	case 2:
+		for _, vol := range m.volumes {
+			if m.markedVolumes[vol.Name] {
+				m.bulkTargets = append(m.bulkTargets, bulkTarget{vol.Name, vol.Name})
+			}
+		}
+	case 3:
+		for _, net := range m.networks {
+			if m.markedNetworks[net.ID] {
+				m.bulkTargets = append(m.bulkTargets, bulkTarget{net.ID, net.Name})
+			}
+		}
+	}
+	if len(m.bulkTargets) == 0 {
+		return m, nil
+	}
+	prompt := fmt.Sprintf("Delete %d marked %s? ", len(m.bulkTargets), bulkKinds[m.activeTab])
+	m.bulkConfirm = components.NewConfirmModal(prompt, false)
+	return m, nil
+}
+
+// handleBulkConfirmKeys processes input while asking to start, stop or
+// delete the marked rows, queueing the action as one task and clearing
+// the marks once confirmed
+func (m *Model) handleBulkConfirmKeys(key string) (tea.Model, tea.Cmd) {
+	var result components.ModalResult
+	m.bulkConfirm, result = m.bulkConfirm.HandleKey(key)
+	if result != components.ModalConfirmed {
+		return m, nil
+	}
+	m.bulkTask(m.activeTab, m.bulkAction, m.bulkTargets)
+	clear(m.markedSet())
+	return m, nil
+}
+
+// titleCase capitalizes the first letter of an action
+func titleCase(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func (m *Model) handleContainerExec() (tea.Model, tea.Cmd) {
+	if m.selectedRow >= len(m.containers) {
+		return m, nil
+	}
+	container := m.containers[m.selectedRow]
+
+	// A stopped or crashed container can't be exec'd into, so reproduce its
+	// environment in a throwaway copy and open the shell there instead
+	if container.Status != "RUNNING" {
+		m.statusMessage = "Starting a debug copy of " + container.Name + "..."
+		return m, m.debugCopyCmd(container.ID, container.Name)
+	}
+
+	// Open the shell next to tinyd when an external terminal is configured
+	if m.externalTerminal != "" {
+		return m, m.openTerminalCmd(container.ID, container.Name)
+	}
+
+	// Create exec command for interactive shell
+	// The command will suspend the TUI and run in the foreground
+	return m, m.execContainerCmd(container.ID)
+}
+
+// openExecPrompt asks for a command to run in a running container, with
+// its history ready to browse
+func (m *Model) openExecPrompt(container types.Container) (tea.Model, tea.Cmd) {
+	if container.Status != "RUNNING" {
+		m.statusMessage = "ERROR: " + container.Name + " is not running"
+		return m, nil
+	}
+	m.execContainer = container
+	m.execCommands = m.execHistory.Commands(container.Name)
+	m.execHistoryPos = len(m.execCommands)
+	m.execInput, m.execDraft = "", ""
+	m.execPromptMode = true
+	return m, nil
+}
+
+// handleExecPromptKeys edits the command, browses the container's history
+// with up/down like a shell, and runs the command on enter
+func (m *Model) handleExecPromptKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.execPromptMode = false
+	case tea.KeyUp:
+		if m.execHistoryPos > 0 {
+			if m.execHistoryPos == len(m.execCommands) {
+				m.execDraft = m.execInput
+			}
+			m.execHistoryPos--
+			m.execInput = m.execCommands[m.execHistoryPos]
+		}
+	case tea.KeyDown:
+		if m.execHistoryPos < len(m.execCommands) {
+			m.execHistoryPos++
+			m.execInput = m.execDraft
+			if m.execHistoryPos < len(m.execCommands) {
+				m.execInput = m.execCommands[m.execHistoryPos]
+			}
+		}
+	case tea.KeyBackspace:
+		if len(m.execInput) > 0 {
+			runes := []rune(m.execInput)
+			m.execInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.execInput += " "
+	case tea.KeyRunes:
+		m.execInput += string(msg.Runes)
+	case tea.KeyEnter:
+		command := strings.TrimSpace(m.execInput)
+		if command == "" {
+			return m, nil
+		}
+		m.execPromptMode = false
+		return m, m.runExec(command)
+	}
+	return m, nil
+}
+
+// runExec records a command in the history of m.execContainer and runs it,
+// showing the output view until it is done
+func (m *Model) runExec(command string) tea.Cmd {
+	if err := m.execHistory.Add(m.execContainer.Name, command); err != nil {
+		m.statusMessage = "ERROR: " + err.Error()
+	}
+	m.execRunning = true
+	m.execResult = types.ExecResultMsg{ContainerID: m.execContainer.ID, Command: command}
+	m.execScroll = 0
+	m.currentView = types.ViewModeExecOutput
+	return m.execOutputCmd(m.execContainer.ID, command)
+}
+
+// openExecOptions asks for the command, user, workdir and env of an
+// interactive exec, prefilled with the last ones used in the container
+func (m *Model) openExecOptions(container types.Container) (tea.Model, tea.Cmd) {
+	if container.Status != "RUNNING" {
+		m.statusMessage = "ERROR: " + container.Name + " is not running"
+		return m, nil
+	}
+	m.execContainer = container
+	o, _ := m.execHistory.LastOptions(container.Name)
+	m.execOptsInput = [4]string{o.Command, o.User, o.Workdir, o.Env}
+	m.execOptsField = 0
+	m.execOptsMode = true
+	return m, nil
+}
+
+// handleExecOptionsKeys edits the exec options and launches the command
+// interactively on enter; an empty command opens the container's shell
+func (m *Model) handleExecOptionsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	field := &m.execOptsInput[m.execOptsField]
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.execOptsMode = false
+	case tea.KeyTab, tea.KeyDown:
+		m.execOptsField = (m.execOptsField + 1) % len(m.execOptsInput)
+	case tea.KeyShiftTab, tea.KeyUp:
+		m.execOptsField = (m.execOptsField + len(m.execOptsInput) - 1) % len(m.execOptsInput)
+	case tea.KeyBackspace:
+		if len(*field) > 0 {
+			runes := []rune(*field)
+			*field = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		*field += " "
+	case tea.KeyRunes:
+		*field += string(msg.Runes)
+	case tea.KeyEnter:
+		o := exechistory.Options{
+			Command: strings.TrimSpace(m.execOptsInput[0]),
+			User:    strings.TrimSpace(m.execOptsInput[1]),
+			Workdir: strings.TrimSpace(m.execOptsInput[2]),
+			Env:     strings.TrimSpace(m.execOptsInput[3]),
+		}
+		cmd, err := docker.SplitCommand(o.Command)
+		if err != nil {
+			m.statusMessage = "ERROR: command: " + err.Error()
+			return m, nil
+		}
+		env, err := docker.SplitCommand(o.Env)
+		if err != nil {
+			m.statusMessage = "ERROR: env: " + err.Error()
+			return m, nil
+		}
+		for _, e := range env {
+			if key, _, ok := strings.Cut(e, "="); !ok || key == "" {
+				m.statusMessage = "ERROR: env: expected KEY=value, got " + e
+				return m, nil
+			}
+		}
+		m.execOptsMode = false
+		if err := m.execHistory.SetLastOptions(m.execContainer.Name, o); err != nil {
+			m.statusMessage = "ERROR: " + err.Error()
+		}
+		return m, m.execWithOptionsCmd(m.execContainer.ID, cmd, o.User, o.Workdir, env)
+	}
+	return m, nil
+}
+
+// handleExecOutputKeys scrolls the output of the last command, runs it
+// again with r, or asks for another with !
+func (m *Model) handleExecOutputKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	lines := len(strings.Split(strings.TrimSuffix(m.execResult.Output, "\n"), "\n"))
+	bottom := max(lines-m.execVisibleLines(), 0)
+	switch msg.String() {
+	case "q", "Q":
+		return m.handleQuit()
+	case "esc":
+		m.currentView = types.ViewModeList
+	case "up", "k":
+		if m.execScroll > 0 {
+			m.execScroll--
+		}
+	case "down", "j":
+		if m.execScroll < bottom {
+			m.execScroll++
+		}
+	case "pgup":
+		m.execScroll = max(m.execScroll-m.execVisibleLines(), 0)
+	case "pgdown":
+		m.execScroll = min(m.execScroll+m.execVisibleLines(), bottom)
+	case "g":
+		m.execScroll = 0
+	case "G":
+		m.execScroll = bottom
+	case "r", "R":
+		if !m.execRunning {
+			return m, m.runExec(m.execResult.Command)
+		}
+	case "!":
+		m.currentView = types.ViewModeList
+		return m.openExecPrompt(m.execContainer)
+	}
+	return m, nil
+}
+
+// execVisibleLines returns how many output lines the exec view shows: the
+// height minus the header, the divider, the exit code line and a margin
+func (m *Model) execVisibleLines() int {
+	return max(m.height-5, 5)
+}
+
+// handleSSHJump opens the SSH destination prompt, prefilled from the
+// ssh:// endpoint of the current docker context
+func (m *Model) handleSSHJump() (tea.Model, tea.Cmd) {
+	destination, port, ok := docker.SSHDestination(m.sshEndpoint)
+	if !ok {
+		m.statusMessage = "SSH jump needs an ssh:// docker context"
+		return m, nil
+	}
+
+	if port != "" {
+		destination += ":" + port
+	}
+	m.sshPromptMode = true
+	m.sshPromptInput = destination
+	return m, nil
+}
+
+// handlePullPromptKeys edits the image reference and pulls it on enter
+func (m *Model) handlePullPromptKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.pullPromptMode = false
+	case tea.KeyEnter:
+		ref := strings.TrimSpace(m.pullPromptInput)
+		if ref == "" {
+			return m, nil
+		}
+		m.pullPromptMode = false
+		return m.startPull(ref)
+	case tea.KeyBackspace:
+		if len(m.pullPromptInput) > 0 {
+			runes := []rune(m.pullPromptInput)
+			m.pullPromptInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes:
+		m.pullPromptInput += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// handleSSHPromptKeys edits the SSH destination and connects on enter
+func (m *Model) handleSSHPromptKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.sshPromptMode = false
+	case tea.KeyEnter:
+		m.sshPromptMode = false
+		if strings.TrimSpace(m.sshPromptInput) == "" {
+			return m, nil
+		}
+		return m, m.sshHostCmd(strings.TrimSpace(m.sshPromptInput))
+	case tea.KeyBackspace:
+		if len(m.sshPromptInput) > 0 {
+			runes := []rune(m.sshPromptInput)
+			m.sshPromptInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes:
+		m.sshPromptInput += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// handleStatsRecording starts recording the CPU and memory of the marked
+// containers (or the selected one) to a CSV file, or stops the recording
+func (m *Model) handleStatsRecording() (tea.Model, tea.Cmd) {
+	if m.recorder != nil {
+		path, samples := m.recorder.Path(), m.recorder.Samples()
+		m.stopRecording()
+		m.statusMessage = fmt.Sprintf("Recorded %d samples to %s", samples, path)
+		return m, nil
+	}
+
+	targets := m.markedContainers()
+	if len(targets) == 0 && m.selectedRow < len(m.containers) {
+		targets = []types.Container{m.containers[m.selectedRow]}
+	}
+	// Grouped service rows record every replica
+	containers := make(map[string]string)
+	for _, row := range targets {
+		for _, c := range docker.ServiceReplicas(row) {
+			if c.Status == "RUNNING" {
+				containers[c.ID] = c.Name
+			}
+		}
+	}
+	if len(containers) == 0 {
+		m.statusMessage = "Select or mark running containers to record"
+		return m, nil
+	}
+
+	path := filepath.Join(os.TempDir(), "tinyd-stats-"+time.Now().Format("20060102-150405")+".csv")
+	recorder, err := recording.Start(path, containers)
+	if err != nil {
+		m.statusMessage = "ERROR: " + err.Error()
+		return m, nil
+	}
+	m.recorder = recorder
+	m.statusMessage = fmt.Sprintf("Recording %d container(s) to %s, Ctrl+R to stop", len(containers), path)
+	// A tick left over from a previous recording keeps sampling this one
+	if m.recordTicking {
+		return m, nil
+	}
+	m.recordTicking = true
+	return m, recordTickCmd()
+}
+
+// stopRecording closes the stats recording, if any
+func (m *Model) stopRecording() {
+	if m.recorder == nil {
+		return
+	}
+	m.recorder.Close()
+	m.recorder = nil
+}
+
+// handleLogSearchPromptKeys edits the log search query and searches the
+// logs of every running container on enter
+func (m *Model) handleLogSearchPromptKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.logSearchPromptMode = false
+	case tea.KeyEnter:
+		query := strings.TrimSpace(m.logSearchInput)
+		if query == "" {
+			return m, nil
+		}
+		var running []types.Container
+		for _, c := range m.containers {
+			if c.Status == "RUNNING" {
+				running = append(running, c)
+			}
+		}
+		m.logSearchPromptMode = false
+		if len(running) == 0 {
+			m.statusMessage = "No running containers to search"
+			return m, nil
+		}
+		m.logSearchQuery = query
+		m.logSearchResults = nil
+		m.logSearchLoading = true
+		m.logSearchCursor = 0
+		m.currentView = types.ViewModeLogSearch
+		return m, m.searchLogsCmd(running, query)
+	case tea.KeyBackspace:
+		if runes := []rune(m.logSearchInput); len(runes) > 0 {
+			m.logSearchInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.logSearchInput += " "
+	case tea.KeyRunes:
+		m.logSearchInput += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// handleLogSearchViewKeys processes input in the log search results
+func (m *Model) handleLogSearchViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "Q":
+		return m.handleQuit()
+	case "esc":
+		m.currentView = types.ViewModeList
+	case "/":
+		// Refine the query from the list's search prompt
+		m.currentView = types.ViewModeList
+		m.logSearchPromptMode = true
+		m.logSearchInput = m.logSearchQuery
+	case "up", "k":
+		if m.logSearchCursor > 0 {
+			m.logSearchCursor--
+		}
+	case "down", "j":
+		if m.logSearchCursor < len(m.logSearchResults)-1 {
+			m.logSearchCursor++
+		}
+	case "enter":
+		// Jump to the logs of the selected container, at its last match
+		if m.logSearchCursor >= len(m.logSearchResults) {
+			return m, nil
+		}
+		result := m.logSearchResults[m.logSearchCursor]
+		container := types.Container{ID: result.ContainerID, Name: result.ContainerName}
+		for _, c := range m.containers {
+			if c.ID == result.ContainerID {
+				container = c
+			}
+		}
+		m.selectedContainer = &container
+		m.currentView = types.ViewModeLogs
+		m.logsContent = ""
+		m.logsStderr = nil
+		m.logsScrollOffset = 0
+		m.logsSince = time.Time{}
+		m.logsUntil = time.Time{}
+		m.logsRangeInput = [2]string{}
+		// As many lines as the search looked at, so the match is among them
+		m.logsTail = max(docker.LogSearchTail, m.logsTailDefault)
+		m.logsMoreAnchor = ""
+		m.logsSearchQuery = m.logSearchQuery
+		m.logsSearchInput, m.logsSearchRegex = m.logSearchQuery, false
+		m.logsSearchRe, _ = docker.CompileLogQuery(m.logSearchQuery, false)
+		m.logsMatchInfo = ""
+		return m, m.getContainerLogsCmd(container.ID)
+	}
+	return m, nil
+}
+
+// handleLogsSearchKeys edits the search of the logs view: Tab switches
+// between text and regex, Enter highlights the matches and jumps to the
+// first one below the top of the view, and an empty search clears it
+func (m *Model) handleLogsSearchKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.logsSearchMode = false
+	case tea.KeyTab:
+		m.logsSearchRegex = !m.logsSearchRegex
+		m.logsSearchErr = ""
+	case tea.KeyBackspace:
+		if runes := []rune(m.logsSearchInput); len(runes) > 0 {
+			m.logsSearchInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.logsSearchInput += " "
+	case tea.KeyRunes:
+		m.logsSearchInput += string(msg.Runes)
+	case tea.KeyEnter:
+		if m.logsSearchInput == "" {
+			m.logsSearchMode = false
+			m.logsSearchRe = nil
+			m.logsMatchInfo = ""
+			return m, nil
+		}
+		re, err := docker.CompileLogQuery(m.logsSearchInput, m.logsSearchRegex)
+		if err != nil {
+			m.logsSearchErr = err.Error()
+			return m, nil
+		}
+		m.logsSearchMode = false
+		m.logsSearchRe = re
+		m.logsMatchLine = m.logsScrollOffset - 1 // A match on the top line comes first
+		m.jumpToLogsMatch(1)
+	}
+	return m, nil
+}
+
+// jumpToLogsMatch scrolls the logs view to the next (dir 1) or previous
+// (dir -1) line matching the search, wrapping around at the ends
+func (m *Model) jumpToLogsMatch(dir int) {
+	if m.logsSearchRe == nil {
+		return
+	}
+	matches := docker.MatchingLines(strings.Split(m.logsContent, "\n"), m.logsSearchRe)
+	if len(matches) == 0 {
+		m.logsMatchInfo = "no matches"
+		return
+	}
+	idx := -1
+	if dir > 0 {
+		for i, line := range matches {
+			if line > m.logsMatchLine {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			idx = 0
+		}
+	} else {
+		for i := len(matches) - 1; i >= 0; i-- {
+			if matches[i] < m.logsMatchLine {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			idx = len(matches) - 1
+		}
+	}
+	// Keep a few lines of context above the match
+	m.logsMatchLine = matches[idx]
+	m.logsScrollOffset = max(m.logsMatchLine-m.logsVisibleLines()/3, 0)
+	m.logsMatchInfo = fmt.Sprintf("%d/%d", idx+1, len(matches))
+}
+
+// scrollToLastMatch scrolls the logs view to the last line matching the log
+// search it was opened from, if any
+func (m *Model) scrollToLastMatch() {
+	if m.logsSearchQuery == "" {
+		return
+	}
+	lines := strings.Split(m.logsContent, "\n")
+	query := strings.ToLower(m.logsSearchQuery)
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.Contains(strings.ToLower(lines[i]), query) {
+			// Keep some context above the match
+			m.logsScrollOffset = max(min(i-m.logsVisibleLines()/2, len(lines)-m.logsVisibleLines()), 0)
+			return
+		}
+	}
+}
+
+// Image action handlers
+
+func (m *Model) handleImageStart() (tea.Model, tea.Cmd) {
+	if m.selectedRow >= len(m.images) {
+		return m, nil
+	}
+	image := m.images[m.selectedRow]
+	m.selectedImage = &image
+
+	// Open the run modal with a clean form
+	m.runTag = image.Tag
+	m.runTags = m.localTags(image.Repository)
+	m.runContainerName = ""
+	m.runHostname = ""
+	m.runPortHost, m.runPortContainer = "", ""
+	m.runVolumeHost, m.runVolumeContainer = "", ""
+	m.runVolumeMode = 0
+	m.runEnvKey, m.runEnvValue = "", ""
+	m.runPorts = []types.PortMapping{}
+	m.runVolumes = []types.VolumeMapping{}
+	m.runEnvVars = []types.EnvVar{}
+	m.runLabelKey, m.runLabelValue = "", ""
+	m.runLabels = nil
+	m.runModalField = runFieldContainerName
+	if image.Repository != "<none>" {
+		m.runModalField = runFieldTag
+	}
+	m.currentView = types.ViewModeRunImage
+	return m, nil
+}
+
+// handleImagePull queues a pull of the selected image's tag, to fetch a
+// newer version from the registry
+func (m *Model) handleImagePull() (tea.Model, tea.Cmd) {
+	if m.selectedRow >= len(m.images) {
+		// Nothing to pull again: ask which image to pull
+		m.pullPromptMode = true
+		m.pullPromptInput = ""
+		return m, nil
+	}
+	image := m.images[m.selectedRow]
+	if image.Repository == "<none>" || image.Tag == "<none>" {
+		m.statusMessage = "Untagged images can't be pulled"
+		return m, nil
+	}
+	return m.startPull(image.Repository + ":" + image.Tag)
+}
+
+// startPull queues a pull of ref. When the pull would move a tag off a
+// local image, it first asks for a tag to keep that image under, so the
+// previous version remains available for a rollback.
+func (m *Model) startPull(ref string) (tea.Model, tea.Cmd) {
+	repo, tag, ok := docker.LocalRef(ref)
+	if !ok || !m.hasImageTag(repo, tag) {
+		m.pullImageTask(ref, "")
+		return m, nil
+	}
+
+	// Default to the first of tag-old, tag-old2, ... not taken yet
+	keep := tag + "-old"
+	for n := 2; m.hasImageTag(repo, keep); n++ {
+		keep = fmt.Sprintf("%s-old%d", tag, n)
+	}
+	m.pullKeepMode = true
+	m.pullKeepRef = ref
+	m.pullKeepInput = keep
+	return m, nil
+}
+
+// hasImageTag reports whether an image listed is tagged repo:tag
+func (m *Model) hasImageTag(repo, tag string) bool {
+	for _, image := range m.images {
+		if image.Repository == repo && image.Tag == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// handlePullKeepKeys edits the tag the current image of a pulled
+// reference is kept under and queues the pull on enter; an empty tag lets
+// the pull replace the image
+func (m *Model) handlePullKeepKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.pullKeepMode = false
+		m.statusMessage = "Pull of " + m.pullKeepRef + " cancelled"
+	case tea.KeyEnter:
+		m.pullKeepMode = false
+		keepAs := ""
+		if keep := strings.TrimSpace(m.pullKeepInput); keep != "" {
+			repo, _, _ := docker.LocalRef(m.pullKeepRef)
+			keepAs = repo + ":" + keep
+		}
+		m.pullImageTask(m.pullKeepRef, keepAs)
+	case tea.KeyBackspace:
+		if runes := []rune(m.pullKeepInput); len(runes) > 0 {
+			m.pullKeepInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes:
+		m.pullKeepInput += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// handleImagePin pins the selected image's tag to its registry digest
+func (m *Model) handleImagePin() (tea.Model, tea.Cmd) {
+	if m.selectedRow >= len(m.images) {
+		return m, nil
+	}
+	image := m.images[m.selectedRow]
+	if image.Tag == "<none>" {
+		m.statusMessage = "Untagged images can't be pinned"
+		return m, nil
+	}
+	m.actionInProgress = true
+	m.statusMessage = "Pinning " + image.Repository + ":" + image.Tag + "..."
+	return m, m.pinImageCmd(image.ID)
+}
+
+// handleImageSave asks where to save the marked images, or the selected
+// one when none are marked, as a single tar archive
+func (m *Model) handleImageSave() (tea.Model, tea.Cmd) {
+	var targets []types.Image
+	for _, img := range m.images {
+		if m.markedImages[img.ID] {
+			targets = append(targets, img)
+		}
+	}
+	if len(targets) == 0 {
+		if m.selectedRow >= len(m.images) {
+			return m, nil
+		}
+		targets = []types.Image{m.images[m.selectedRow]}
+	}
+
+	m.imageSaveTargets = targets
+	m.imageSaveInput = filepath.Join(os.TempDir(), "tinyd-images-"+time.Now().Format("20060102-150405")+".tar")
+	m.imageSaveMode = true
+	return m, nil
+}
+
+// handleImageSaveKeys edits the archive path and queues the save on enter
+func (m *Model) handleImageSaveKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.imageSaveMode = false
+	case tea.KeyEnter:
+		path := strings.TrimSpace(m.imageSaveInput)
+		if path == "" {
+			return m, nil
+		}
+		m.imageSaveMode = false
+		m.markedImages = make(map[string]bool)
+		m.saveImagesTask(m.imageSaveTargets, path)
+	case tea.KeyBackspace:
+		if len(m.imageSaveInput) > 0 {
+			runes := []rune(m.imageSaveInput)
+			m.imageSaveInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes:
+		m.imageSaveInput += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// handleTagCleanup asks how many of the newest tags of the selected
+// image's repository to keep
+func (m *Model) handleTagCleanup() (tea.Model, tea.Cmd) {
+	if m.selectedRow >= len(m.images) {
+		return m, nil
+	}
+	image := m.images[m.selectedRow]
+	if image.RepoTag == "" {
+		m.statusMessage = "Untagged images have no repository to clean up"
+		return m, nil
+	}
+	m.tagCleanupRepo = docker.ImageRepository(image.RepoTag)
+	m.tagCleanupInput = "5"
+	m.tagCleanupMode = true
+	return m, nil
+}
+
+// handleTagCleanupKeys edits the number of tags to keep and opens the
+// preview of what would be deleted on enter
+func (m *Model) handleTagCleanupKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.tagCleanupMode = false
+	case tea.KeyEnter:
+		keep, err := strconv.Atoi(m.tagCleanupInput)
+		if err != nil || keep < 1 {
+			m.statusMessage = "Keep at least 1 tag"
+			return m, nil
+		}
+		m.tagCleanupMode = false
+		plan := docker.PlanTagCleanup(m.allImages, m.tagCleanupRepo, keep)
+		if len(plan.Delete) == 0 {
+			m.statusMessage = fmt.Sprintf("Nothing to delete: %s has %d tag(s) to keep", plan.Repository, len(plan.Keep)+len(plan.InUse))
+			return m, nil
+		}
+		m.tagCleanupPlan = plan
+		m.tagCleanupScroll = 0
+		m.currentView = types.ViewModeTagCleanup
+	case tea.KeyBackspace:
+		if len(m.tagCleanupInput) > 0 {
+			m.tagCleanupInput = m.tagCleanupInput[:len(m.tagCleanupInput)-1]
+		}
+	case tea.KeyRunes:
+		for _, r := range msg.Runes {
+			if r >= '0' && r <= '9' && len(m.tagCleanupInput) < 3 {
+				m.tagCleanupInput += string(r)
+			}
+		}
+	}
+	return m, nil
+}
+
+// handleTagCleanupViewKeys deletes the previewed tags on enter
+func (m *Model) handleTagCleanupViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "Q":
+		return m.handleQuit()
+	case "esc":
+		m.currentView = types.ViewModeList
+	case "enter":
+		m.currentView = types.ViewModeList
+		m.tagCleanupTask(m.tagCleanupPlan)
+	case "up", "k":
+		if m.tagCleanupScroll > 0 {
+			m.tagCleanupScroll--
+		}
+	case "down", "j":
+		plan := m.tagCleanupPlan
+		if m.tagCleanupScroll < len(plan.Keep)+len(plan.InUse)+len(plan.Delete)-1 {
+			m.tagCleanupScroll++
+		}
+	}
+	return m, nil
+}
+
+// localTags returns the tags of a repository present locally, sorted
+func (m *Model) localTags(repository string) []string {
+	var tags []string
+	if repository == "<none>" {
+		return tags
+	}
+	for _, img := range m.images {
+		if img.Repository == repository && img.Tag != "<none>" {
+			tags = append(tags, img.Tag)
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// runImageRef is the image reference the run modal will start: the
+// repository with the chosen tag, or the image ID for untagged images
+func (m *Model) runImageRef() string {
+	if m.selectedImage.Repository == "<none>" {
+		return m.selectedImage.ID
+	}
+	tag := strings.TrimSpace(m.runTag)
+	if tag == "" {
+		tag = "latest"
+	}
+	return m.selectedImage.Repository + ":" + tag
+}
+
+// runImagePlatform returns the platform of the image the run modal starts,
+// or "" when unknown, e.g. for a tag that is pulled first
+func (m *Model) runImagePlatform() string {
+	if m.selectedImage == nil {
+		return ""
+	}
+	if m.selectedImage.Repository == "<none>" {
+		return m.imagePlatforms[m.selectedImage.ID]
+	}
+	tag := strings.TrimSpace(m.runTag)
+	for _, img := range m.allImages {
+		if img.Repository == m.selectedImage.Repository && img.Tag == tag {
+			return m.imagePlatforms[img.ID]
+		}
+	}
+	return ""
+}
+
+// runTagIsLocal reports whether the tag chosen in the run modal is present
+func (m *Model) runTagIsLocal() bool {
+	for _, tag := range m.runTags {
+		if tag == strings.TrimSpace(m.runTag) {
+			return true
+		}
+	}
+	return false
+}
+
+// handleRunModalKeys processes input in the run image modal
+func (m *Model) handleRunModalKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Untagged images are run by ID, so the tag field is skipped
+	firstField := runFieldTag
+	if m.selectedImage == nil || m.selectedImage.Repository == "<none>" {
+		firstField = runFieldContainerName
+	}
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.currentView = types.ViewModeList
+		m.selectedImage = nil
+		return m, nil
+
+	case tea.KeyTab, tea.KeyDown:
+		m.runModalField++
+		if m.runModalField > runFieldLabelValue {
+			m.runModalField = firstField
+		}
+		return m, nil
+
+	case tea.KeyShiftTab, tea.KeyUp:
+		m.runModalField--
+		if m.runModalField < firstField {
+			m.runModalField = runFieldLabelValue
+		}
+		return m, nil
+
+	case tea.KeyLeft, tea.KeyRight:
+		// Cycle through the access modes of the volume
+		if m.runModalField == runFieldVolumeMode {
+			modes := len(runVolumeModes())
+			if msg.Type == tea.KeyRight {
+				m.runVolumeMode = (m.runVolumeMode + 1) % modes
+			} else {
+				m.runVolumeMode = (m.runVolumeMode + modes - 1) % modes
+			}
+			return m, nil
+		}
+		// Cycle through the local tags of the repository
+		if m.runModalField == runFieldTag && len(m.runTags) > 0 {
+			idx := -1
+			for i, tag := range m.runTags {
+				if tag == m.runTag {
+					idx = i
+				}
+			}
+			if msg.Type == tea.KeyRight {
+				idx = (idx + 1) % len(m.runTags)
+			} else if idx <= 0 {
+				idx = len(m.runTags) - 1
+			} else {
+				idx--
+			}
+			m.runTag = m.runTags[idx]
+		}
+		return m, nil
+
+	case tea.KeyEnter:
+		// Enter on a complete port, volume, env or label pair adds it;
+		// anywhere else it runs the container, keeping pairs that are
+		// filled in
+		if m.addRunModalPair(m.runModalField) {
+			return m, nil
+		}
+		for _, field := range []int{runFieldPortHost, runFieldVolumeHost, runFieldEnvKey, runFieldLabelKey} {
+			m.addRunModalPair(field)
+		}
+		m.currentView = types.ViewModeList
+		m.actionInProgress = true
+		m.statusMessage = "Starting container..."
+		return m, m.runContainerCmd()
+
+	case tea.KeyBackspace:
+		if field := m.runModalInput(); field != nil && len(*field) > 0 {
+			runes := []rune(*field)
+			*field = string(runes[:len(runes)-1])
+		}
+		return m, nil
+
+	case tea.KeyRunes, tea.KeySpace:
+		if field := m.runModalInput(); field != nil {
+			*field += string(msg.Runes)
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// addRunModalPair adds the port, volume or env pair that field belongs to
+// when both of its halves are filled in, and focuses the first half again
+func (m *Model) addRunModalPair(field int) bool {
+	switch field {
+	case runFieldPortHost, runFieldPortContainer:
+		if m.runPortHost == "" || m.runPortContainer == "" {
+			return false
+		}
+		m.runPorts = append(m.runPorts, types.PortMapping{Host: m.runPortHost, Container: m.runPortContainer})
+		m.runPortHost, m.runPortContainer = "", ""
+		m.runModalField = runFieldPortHost
+	case runFieldVolumeHost, runFieldVolumeContainer, runFieldVolumeMode:
+		if m.runVolumeHost == "" || m.runVolumeContainer == "" {
+			return false
+		}
+		access, consistency, _ := strings.Cut(runVolumeModes()[m.runVolumeMode], ",")
+		m.runVolumes = append(m.runVolumes, types.VolumeMapping{
+			Host:        m.runVolumeHost,
+			Container:   m.runVolumeContainer,
+			ReadOnly:    access == "ro",
+			Consistency: consistency,
+		})
+		m.runVolumeHost, m.runVolumeContainer = "", ""
+		m.runVolumeMode = 0
+		m.runModalField = runFieldVolumeHost
+	case runFieldEnvKey, runFieldEnvValue:
+		if m.runEnvKey == "" || m.runEnvValue == "" {
+			return false
+		}
+		m.runEnvVars = append(m.runEnvVars, types.EnvVar{Key: m.runEnvKey, Value: m.runEnvValue})
+		m.runEnvKey, m.runEnvValue = "", ""
+		m.runModalField = runFieldEnvKey
+	case runFieldLabelKey, runFieldLabelValue:
+		// "key=value" typed in the key field is a whole label, which
+		// also allows labels with an empty value
+		key, value, split := strings.Cut(m.runLabelKey, "=")
+		if !split {
+			value = m.runLabelValue
+		}
+		if key = strings.TrimSpace(key); key == "" || (!split && value == "") {
+			return false
+		}
+		m.runLabels = append(m.runLabels, types.Label{Key: key, Value: value})
+		m.runLabelKey, m.runLabelValue = "", ""
+		m.runModalField = runFieldLabelKey
+	default:
+		return false
+	}
+	return true
+}
+
+// runVolumeModes lists the access modes offered for a run modal volume, as
+// bind options. Consistency options only affect Docker Desktop for macOS.
+func runVolumeModes() []string {
+	modes := []string{"rw", "ro"}
+	if runtime.GOOS == "darwin" {
+		modes = append(modes, "rw,cached", "rw,delegated", "ro,cached")
+	}
+	return modes
+}
+
+// runModalInput returns the text of the focused run modal field
+func (m *Model) runModalInput() *string {
+	switch m.runModalField {
+	case runFieldTag:
+		return &m.runTag
+	case runFieldContainerName:
+		return &m.runContainerName
+	case runFieldHostname:
+		return &m.runHostname
+	case runFieldPortHost:
+		return &m.runPortHost
+	case runFieldPortContainer:
+		return &m.runPortContainer
+	case runFieldVolumeHost:
+		return &m.runVolumeHost
+	case runFieldVolumeContainer:
+		return &m.runVolumeContainer
+	case runFieldEnvKey:
+		return &m.runEnvKey
+	case runFieldEnvValue:
+		return &m.runEnvValue
+	case runFieldLabelKey:
+		return &m.runLabelKey
+	case runFieldLabelValue:
+		return &m.runLabelValue
+	}
+	return nil
+}
+
+func (m *Model) handleImageInspect() (tea.Model, tea.Cmd) {
+	if m.selectedRow >= len(m.images) {
+		return m, nil
+	}
+	image := m.images[m.selectedRow]
+	m.selectedImage = &image
+	m.pushNav()
+	m.currentView = types.ViewModeInspect
+	m.inspectContent = ""
+	return m, m.inspectImageCmd(image.ID)
+}
+
+func (m *Model) handleImageDelete() (tea.Model, tea.Cmd) {
+	if m.selectedRow >= len(m.images) {
+		return m, nil
+	}
+	m.openDeleteConfirm(m.images[m.selectedRow].Repository + ":" + m.images[m.selectedRow].Tag)
+	return m, nil
+}
+
+// Volume action handlers
+
+func (m *Model) handleVolumeInspect() (tea.Model, tea.Cmd) {
+	if m.selectedRow >= len(m.volumes) {
+		return m, nil
+	}
+	volume := m.volumes[m.selectedRow]
+	m.selectedVolume = &volume
+	m.pushNav()
+	m.currentView = types.ViewModeInspect
+	m.inspectContent = ""
+	return m, m.inspectVolumeCmd(volume.Name)
+}
+
+func (m *Model) handleVolumeDelete() (tea.Model, tea.Cmd) {
+	if m.selectedRow >= len(m.volumes) {
+		return m, nil
+	}
+	m.openDeleteConfirm(m.volumes[m.selectedRow].Name)
+	return m, nil
+}
+
+// handleVolumeCopy opens the prompt for copying the selected volume
+func (m *Model) handleVolumeCopy() (tea.Model, tea.Cmd) {
+	if m.selectedRow >= len(m.volumes) {
+		return m, nil
+	}
+	volume := m.volumes[m.selectedRow]
+	m.volumeCopyMode = true
+	m.volumeCopySource = volume.Name
+	m.volumeCopyInput = volume.Name + "-copy"
+	m.volumeCopyPlan = nil
+	return m, nil
+}
+
+// handleVolumeCopyKeys edits the target volume name, runs the dry-run
+// estimate on enter and starts the copy once the estimate is confirmed
+func (m *Model) handleVolumeCopyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Confirmation stage: Enter copies, Esc goes back to editing the target
+	if m.volumeCopyPlan != nil {
+		switch msg.Type {
+		case tea.KeyEsc:
+			m.volumeCopyPlan = nil
+		case tea.KeyEnter:
+			plan := *m.volumeCopyPlan
+			m.volumeCopyMode = false
+			m.volumeCopyPlan = nil
+			m.copyVolumeTask(plan)
+			return m, nil
 		}
 		return m, nil
+	}
 
-	case "left", "h", "right", "1", "2", "3", "4":
-		return m.handleTabSwitch(key)
+	m.statusMessage = "" // Show the prompt again after a failed estimate
 
-	case "enter":
-		// Refresh on enter
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.volumeCopyMode = false
+	case tea.KeyBackspace:
+		input := []rune(m.volumeCopyInput)
+		if len(input) > 0 {
+			m.volumeCopyInput = string(input[:len(input)-1])
+		}
+	case tea.KeyRunes:
+		m.volumeCopyInput += string(msg.Runes)
+	case tea.KeyEnter:
+		target := strings.TrimSpace(m.volumeCopyInput)
+		if target == "" {
+			return m, nil
+		}
+		m.actionInProgress = true
+		m.statusMessage = "Measuring " + m.volumeCopySource + "..."
+		return m, m.estimateVolumeCopyCmd(m.volumeCopySource, target)
+	}
+	return m, nil
+}
+
+// applyContainerFilter rebuilds the shown containers from the full list:
+// those of the filtered image and labels, with replicas folded when
+// grouping
+func (m *Model) applyContainerFilter() {
+	containers := m.allContainers
+	if m.containerImageFilter != "" {
+		containers = docker.ContainersOfImage(containers, m.containerImageFilter)
+	}
+	if filter := m.labelFilter(0); len(filter) > 0 {
+		var labelled []types.Container
+		for _, c := range containers {
+			if docker.MatchLabels(c.Labels, filter) {
+				labelled = append(labelled, c)
+			}
+		}
+		containers = labelled
+	}
+	if m.groupReplicas {
+		containers = docker.GroupReplicas(containers)
+	}
+	m.containers = containers
+
+	// Keep selection in bounds
+	if m.activeTab == 0 && m.selectedRow >= len(m.containers) && len(m.containers) > 0 {
+		m.selectedRow = len(m.containers) - 1
+	}
+}
+
+// openFilter opens the filter modal of the active tab on its current
+// filter
+func (m *Model) openFilter() (tea.Model, tea.Cmd) {
+	m.filterChoice = 0
+	switch m.activeTab {
+	case 0:
+		m.filterImages = docker.ImagesInUse(m.allContainers)
+		for i, use := range m.filterImages {
+			if use.Repository == m.containerImageFilter {
+				m.filterChoice = i + 1
+			}
+		}
+	case 1:
+		m.filterChoice = m.imageFilter
+	}
+	m.filterInput = m.labelFilters[m.activeTab]
+	m.filterErr = ""
+	m.filterMode = true
+	return m, nil
+}
+
+// filterChoices counts what the filter modal cycles through on the active
+// tab: the images containers were created from, or the image presets
+func (m *Model) filterChoices() int {
+	switch m.activeTab {
+	case 0:
+		return len(m.filterImages) + 1
+	case 1:
+		return types.ImageFilterStale + len(m.staleDays)
+	}
+	return 1
+}
+
+// handleFilterKeys edits the filter of the active tab: arrows pick the
+// image or preset, typing edits the label terms, and enter applies both;
+// an empty filter shows everything again
+func (m *Model) handleFilterKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.filterErr = ""
+	choices := m.filterChoices()
+	switch msg.Type {
+	case tea.KeyBackspace:
+		if runes := []rune(m.filterInput); len(runes) > 0 {
+			m.filterInput = string(runes[:len(runes)-1])
+		}
+		return m, nil
+	case tea.KeySpace:
+		m.filterInput += " "
+		return m, nil
+	case tea.KeyRunes:
+		m.filterInput += string(msg.Runes)
+		return m, nil
+	case tea.KeyLeft:
+		m.filterChoice = (m.filterChoice + choices - 1) % choices
+		return m, nil
+	case tea.KeyRight:
+		m.filterChoice = (m.filterChoice + 1) % choices
+		return m, nil
+	}
+
+	switch components.ClassifyModalKey(msg.String()) {
+	case components.ModalKeyPrev:
+		m.filterChoice = (m.filterChoice + choices - 1) % choices
+	case components.ModalKeyNext:
+		m.filterChoice = (m.filterChoice + 1) % choices
+	case components.ModalKeyCancel:
+		m.filterMode = false
+	case components.ModalKeyConfirm:
+		if _, err := docker.ParseLabelFilter(m.filterInput); err != nil {
+			m.filterErr = err.Error()
+			return m, nil
+		}
+		m.filterMode = false
+		m.labelFilters[m.activeTab] = strings.Join(strings.Fields(m.filterInput), " ")
+		m.selectedRow = 0
+		m.scrollOffset = 0
 		switch m.activeTab {
 		case 0:
-			return m, m.fetchContainersCmd()
+			m.containerImageFilter = ""
+			if m.filterChoice > 0 {
+				m.containerImageFilter = m.filterImages[m.filterChoice-1].Repository
+			}
+			m.applyContainerFilter()
+			m.applyContainerStats()
 		case 1:
-			return m, m.fetchImagesCmd()
+			m.imageFilter = m.filterChoice
+			m.applyImageFilter()
+			return m, m.imageTagTimesCmd()
 		case 2:
-			return m, m.fetchVolumesCmd()
+			m.applyVolumeFilter()
 		case 3:
-			return m, m.fetchNetworksCmd()
+			m.applyNetworkFilter()
 		}
-		return m, nil
+	}
+	return m, nil
+}
 
-	// Container actions (only on Containers tab)
-	case "s", "S":
-		if m.activeTab == 0 {
-			return m.handleContainerStartStop()
-		} else if m.activeTab == 1 {
-			return m.handleImageStart()
+// labelFilter returns the label terms of the tab's filter, checked when
+// they were entered
+func (m *Model) labelFilter(tab int) []docker.LabelMatch {
+	filter, _ := docker.ParseLabelFilter(m.labelFilters[tab])
+	return filter
+}
+
+// applyImageFilter rebuilds the shown images from the full list
+func (m *Model) applyImageFilter() {
+	var filtered []types.Image
+	switch {
+	case m.imageFilter >= types.ImageFilterStale:
+		days := m.staleDays[m.imageFilter-types.ImageFilterStale]
+		filtered = docker.StaleImages(m.allImages, m.imageTagTimes, time.Duration(days)*24*time.Hour, time.Now())
+	case m.imageFilter == types.ImageFilterAll:
+		filtered = m.allImages
+	default:
+		for _, img := range m.allImages {
+			if (m.imageFilter == types.ImageFilterInUse && img.InUse) ||
+				(m.imageFilter == types.ImageFilterUnused && !img.InUse) ||
+				(m.imageFilter == types.ImageFilterDangling && img.Dangling) {
+				filtered = append(filtered, img)
+			}
 		}
-		return m, nil
-	case "r", "R":
-		if m.activeTab == 0 {
-			return m.handleContainerRestart()
+	}
+	if filter := m.labelFilter(1); len(filter) > 0 {
+		var labelled []types.Image
+		for _, img := range filtered {
+			if docker.MatchLabels(img.Labels, filter) {
+				labelled = append(labelled, img)
+			}
 		}
-		return m, nil
-	case "l", "L":
-		if m.activeTab == 0 {
-			return m.handleContainerLogs()
+		filtered = labelled
+	}
+	m.images = filtered
+	if m.sorts[1].Column != "" {
+		// Sort a copy, the full list keeps the default order
+		m.images = append([]types.Image(nil), filtered...)
+		m.sortImages()
+	}
+
+	// Keep selection in bounds
+	if m.activeTab == 1 && m.selectedRow >= len(m.images) && len(m.images) > 0 {
+		m.selectedRow = len(m.images) - 1
+	}
+}
+
+// applyVolumeFilter rebuilds the shown volumes from the full list: those
+// matching the label filter, sorted
+func (m *Model) applyVolumeFilter() {
+	m.volumes = m.allVolumes
+	if filter := m.labelFilter(2); len(filter) > 0 {
+		m.volumes = nil
+		for _, vol := range m.allVolumes {
+			if docker.MatchLabels(vol.Labels, filter) {
+				m.volumes = append(m.volumes, vol)
+			}
 		}
-		return m, nil
-	case "i", "I":
-		switch m.activeTab {
-		case 0: // Containers
-			return m.handleContainerInspect()
-		case 1: // Images
-			return m.handleImageInspect()
-		case 2: // Volumes
-			return m.handleVolumeInspect()
-		case 3: // Networks
-			return m.handleNetworkInspect()
+	}
+	if m.sorts[2].Column != "" {
+		// Sort a copy, the full list keeps the default order
+		m.volumes = append([]types.Volume(nil), m.volumes...)
+		m.sortVolumes()
+	}
+}
+
+// applyNetworkFilter rebuilds the shown networks from the full list: those
+// matching the label filter, sorted
+func (m *Model) applyNetworkFilter() {
+	m.networks = m.allNetworks
+	if filter := m.labelFilter(3); len(filter) > 0 {
+		m.networks = nil
+		for _, net := range m.allNetworks {
+			if docker.MatchLabels(net.Labels, filter) {
+				m.networks = append(m.networks, net)
+			}
 		}
-		return m, nil
-	case "d", "D":
-		switch m.activeTab {
-		case 0: // Containers
-			return m.handleContainerDelete()
-		case 1: // Images
-			return m.handleImageDelete()
-		case 2: // Volumes
-			return m.handleVolumeDelete()
-		case 3: // Networks
-			return m.handleNetworkDelete()
+	}
+	if m.sorts[3].Column != "" {
+		// Sort a copy, the full list keeps the default order
+		m.networks = append([]types.Network(nil), m.networks...)
+		m.sortNetworks()
+	}
+}
+
+// imageFilterLabel names an image filter preset
+func (m *Model) imageFilterLabel(filter int) string {
+	switch filter {
+	case types.ImageFilterInUse:
+		return "In use images"
+	case types.ImageFilterUnused:
+		return "Unused images"
+	case types.ImageFilterDangling:
+		return "Dangling images"
+	case types.ImageFilterAll:
+		return "All images"
+	}
+	return fmt.Sprintf("Unused for %d+ days", m.staleDays[filter-types.ImageFilterStale])
+}
+
+// parseLogTail reads TINYD_LOG_TAIL, a positive line count or "all"; the
+// default is 100
+func parseLogTail(value string) int {
+	if strings.TrimSpace(value) == "all" {
+		return 0
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && n > 0 {
+		return n
+	}
+	return 100
+}
+
+// parseStaleDays reads the stale image filter presets from a comma-separated
+// list of days, falling back to 30 and 90
+func parseStaleDays(value string) []int {
+	var days []int
+	for _, field := range strings.Split(value, ",") {
+		if n, err := strconv.Atoi(strings.TrimSpace(field)); err == nil && n > 0 {
+			days = append(days, n)
 		}
-		return m, nil
-	case "e", "E":
-		if m.activeTab == 0 {
-			return m.handleContainerExec()
+	}
+	if len(days) == 0 {
+		return []int{30, 90}
+	}
+	return days
+}
+
+// containerColumnNames are the optional columns of the containers table
+var containerColumnNames = []string{"uptime", "exit", "restarts", "net", "block"}
+
+// parseContainerColumns reads the optional containers table columns from a
+// comma-separated list, falling back to all of them
+func parseContainerColumns(value string) []string {
+	var columns []string
+	for _, field := range strings.Split(value, ",") {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if slices.Contains(containerColumnNames, field) && !slices.Contains(columns, field) {
+			columns = append(columns, field)
 		}
-		return m, nil
+	}
+	if len(columns) == 0 {
+		return containerColumnNames
+	}
+	return columns
+}
 
-	default:
+// Network action handlers
+
+func (m *Model) handleNetworkInspect() (tea.Model, tea.Cmd) {
+	if m.selectedRow >= len(m.networks) {
 		return m, nil
 	}
+	network := m.networks[m.selectedRow]
+	m.selectedNetwork = &network
+	m.pushNav()
+	m.currentView = types.ViewModeInspect
+	m.inspectContent = ""
+	return m, m.inspectNetworkCmd(network.ID)
 }
 
-// handleTabSwitch switches between tabs
-func (m *Model) handleTabSwitch(key string) (tea.Model, tea.Cmd) {
-	oldTab := m.activeTab
+// networkExtraField names the driver-specific field of the create-network
+// prompt, "" when the driver has none
+func networkExtraField(driver string) string {
+	switch driver {
+	case "macvlan", "ipvlan":
+		return "parent"
+	case "overlay":
+		return "encrypt"
+	}
+	return ""
+}
 
-	switch key {
-	case "left", "h":
-		m.activeTab--
-		if m.activeTab < 0 {
-			m.activeTab = 3
+// handleNetworkCreateKeys edits the create-network prompt and creates the
+// network on enter
+func (m *Model) handleNetworkCreateKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	fields := 3
+	if networkExtraField(m.networkCreateInput[1]) != "" {
+		fields = 4
+	}
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.networkCreateMode = false
+	case tea.KeyTab, tea.KeyDown:
+		m.networkCreateField = (m.networkCreateField + 1) % fields
+	case tea.KeyShiftTab, tea.KeyUp:
+		m.networkCreateField = (m.networkCreateField + fields - 1) % fields
+	case tea.KeyBackspace:
+		if field := m.networkCreateInput[m.networkCreateField]; len(field) > 0 {
+			runes := []rune(field)
+			m.networkCreateInput[m.networkCreateField] = string(runes[:len(runes)-1])
 		}
-	case "right", "l":
-		m.activeTab++
-		if m.activeTab > 3 {
-			m.activeTab = 0
+	case tea.KeyRunes:
+		for _, r := range msg.Runes {
+			switch {
+			case m.networkCreateField == 2 && (r < '0' || r > '9'):
+				// MTU is a number
+			case m.networkCreateField == 3 && networkExtraField(m.networkCreateInput[1]) == "encrypt":
+				if r == 'y' || r == 'Y' {
+					m.networkCreateInput[3] = "yes"
+				} else if r == 'n' || r == 'N' {
+					m.networkCreateInput[3] = ""
+				}
+			default:
+				m.networkCreateInput[m.networkCreateField] += string(r)
+			}
 		}
-	case "1":
-		m.activeTab = 0
-	case "2":
-		m.activeTab = 1
-	case "3":
-		m.activeTab = 2
-	case "4":
-		m.activeTab = 3
+	case tea.KeyEnter:
+		spec := types.NetworkSpec{
+			Name:   strings.TrimSpace(m.networkCreateInput[0]),
+			Driver: strings.TrimSpace(m.networkCreateInput[1]),
+		}
+		if m.networkCreateInput[2] != "" {
+			spec.MTU, _ = strconv.Atoi(m.networkCreateInput[2])
+		}
+		switch networkExtraField(spec.Driver) {
+		case "parent":
+			spec.Parent = strings.TrimSpace(m.networkCreateInput[3])
+		case "encrypt":
+			spec.Encrypted = m.networkCreateInput[3] == "yes"
+		}
+		if spec.Name == "" {
+			m.networkCreateField = 0
+			return m, nil
+		}
+		m.networkCreateMode = false
+		m.actionInProgress = true
+		return m, m.createNetworkCmd(spec)
+	}
+
+	// Leaving the extra field out when the driver no longer has one
+	if m.networkCreateField >= 3 && networkExtraField(m.networkCreateInput[1]) == "" {
+		m.networkCreateField = 0
+	}
+	return m, nil
+}
+
+func (m *Model) handleNetworkDelete() (tea.Model, tea.Cmd) {
+	if m.selectedRow >= len(m.networks) {
+		return m, nil
 	}
+	m.openDeleteConfirm(m.networks[m.selectedRow].Name)
+	return m, nil
+}
 
-	if m.activeTab != oldTab {
-		m.selectedRow = 0
-		m.scrollOffset = 0
-		m.tabs = m.tabs.SetActiveTab(m.activeTab)
+// handleTasksChanged reports tasks that finished since the last change and
+// refreshes the lists they may have affected
+func (m *Model) handleTasksChanged() tea.Cmd {
+	tasksNow := m.taskQueue.Tasks()
+	finished := false
+	states := make(map[int]tasks.State, len(tasksNow))
+	for _, task := range tasksNow {
+		states[task.ID] = task.State
+		if !task.State.Finished() || m.taskStates[task.ID].Finished() {
+			continue
+		}
+		finished = true
+		switch task.State {
+		case tasks.Done:
+			m.statusMessage = "✓ " + task.Result
+		case tasks.Failed:
+			m.statusMessage = "ERROR: " + task.Name + ": " + task.Err.Error()
+		case tasks.Cancelled:
+			m.statusMessage = task.Name + " cancelled"
+		}
 	}
+	m.taskStates = states
+	m.taskCursor = min(m.taskCursor, max(len(tasksNow)-1, 0))
 
-	return m, nil
+	if !finished {
+		return nil
+	}
+	return m.scheduleRefresh(types.RefreshContainers | types.RefreshImages | types.RefreshVolumes | types.RefreshNetworks)
 }
 
-// handleLogsViewKeys processes input in logs view
-func (m *Model) handleLogsViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	key := msg.String()
+// hookMessage describes a hook that ran for the Messages panel, with its
+// output below
+func hookMessage(msg types.HookOutputMsg) string {
+	text := fmt.Sprintf("Hook %s for %s ran", msg.Event, msg.Container)
+	if msg.Err != nil {
+		text = fmt.Sprintf("ERROR: Hook %s for %s failed: %v", msg.Event, msg.Container, msg.Err)
+	}
+	if msg.Output != "" {
+		text += "\n" + msg.Output
+	}
+	return text
+}
 
-	switch key {
+// handleMessagesViewKeys processes input in the Messages panel
+func (m *Model) handleMessagesViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "Q":
+		return m.handleQuit()
 	case "esc":
 		m.currentView = types.ViewModeList
-		m.logsContent = ""
-		return m, nil
-
 	case "up", "k":
-		if m.logsScrollOffset > 0 {
-			m.logsScrollOffset--
+		if m.messagesScroll > 0 {
+			m.messagesScroll--
 		}
-		return m, nil
-
 	case "down", "j":
-		m.logsScrollOffset++
-		return m, nil
-
-	default:
-		return m, nil
+		if m.messagesScroll < m.messages.Len()-1 {
+			m.messagesScroll++
+		}
+	case "c", "C":
+		m.messages.Clear()
+		m.messagesScroll = 0
 	}
+	return m, nil
 }
 
-// handleInspectViewKeys processes input in inspect view
-func (m *Model) handleInspectViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	key := msg.String()
-
-	switch key {
+// handleRelatedViewKeys scrolls the containers sharing volumes or networks
+// with the selected one
+func (m *Model) handleRelatedViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "Q":
+		return m.handleQuit()
 	case "esc":
 		m.currentView = types.ViewModeList
-		m.inspectContent = ""
-		return m, nil
-
 	case "up", "k":
-		if m.logsScrollOffset > 0 {
-			m.logsScrollOffset--
+		if m.relatedScroll > 0 {
+			m.relatedScroll--
 		}
-		return m, nil
-
 	case "down", "j":
-		m.logsScrollOffset++
-		return m, nil
+		if m.relatedScroll < len(docker.RelatedContainers(m.allContainers, m.relatedTo.ID))-1 {
+			m.relatedScroll++
+		}
+	}
+	return m, nil
+}
 
-	default:
-		return m, nil
+// handleStartFailureKeys closes the start failure diagnosis, or jumps to the
+// container holding the port the failed one needs
+func (m *Model) handleStartFailureKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "Q":
+		return m.handleQuit()
+	case "esc":
+		m.currentView = types.ViewModeList
+	case "g", "G":
+		if m.startFailure.Failure.HolderID != "" {
+			m.currentView = types.ViewModeList
+			m.jumpToContainer(m.startFailure.Failure.HolderID)
+		}
 	}
+	return m, nil
 }
 
-// getMaxRow returns the number of items in the current tab
-func (m *Model) getMaxRow() int {
+// jumpToContainer switches to the containers tab and selects a container,
+// clearing the image filter when it hides it; backspace returns
+func (m *Model) jumpToContainer(id string) {
+	m.pushNav()
+	m.activeTab = 0
+	m.tabs = m.tabs.SetActiveTab(0)
+	row := containerRow(m.containers, id)
+	if row < 0 && m.containerImageFilter != "" {
+		m.containerImageFilter = ""
+		m.applyContainerFilter()
+		row = containerRow(m.containers, id)
+	}
+	if row < 0 {
+		m.statusMessage = "Container not found, press Enter to refresh"
+		return
+	}
+	m.selectRow(row)
+}
+
+// handleGoToRelated hops from a container to the image it was created
+// from, and from an image to the containers using it; backspace returns
+func (m *Model) handleGoToRelated() (tea.Model, tea.Cmd) {
 	switch m.activeTab {
 	case 0:
-		return len(m.containers)
+		if m.selectedRow >= len(m.containers) {
+			return m, nil
+		}
+		ref := m.containers[m.selectedRow].ImageRef
+		idx := docker.ImageOf(m.images, ref)
+		if idx < 0 && docker.ImageOf(m.allImages, ref) >= 0 {
+			// Hidden by the image filter
+			m.imageFilter = types.ImageFilterAll
+			m.applyImageFilter()
+			idx = docker.ImageOf(m.images, ref)
+		}
+		if idx < 0 {
+			m.statusMessage = "Image " + ref + " not found, press Enter on the Images tab to refresh"
+			return m, nil
+		}
+		m.pushNav()
+		m.activeTab = 1
+		m.tabs = m.tabs.SetActiveTab(1)
+		m.selectRow(idx)
 	case 1:
-		return len(m.images)
-	case 2:
-		return len(m.volumes)
-	case 3:
-		return len(m.networks)
+		if m.selectedRow >= len(m.images) {
+			return m, nil
+		}
+		image := m.images[m.selectedRow]
+		if image.RepoTag == "" {
+			m.statusMessage = "Untagged images have no repository to find containers by"
+			return m, nil
+		}
+		m.pushNav()
+		m.containerImageFilter = docker.ImageRepository(image.RepoTag)
+		m.applyContainerFilter()
+		m.activeTab = 0
+		m.tabs = m.tabs.SetActiveTab(0)
+		m.selectRow(0)
 	}
-	return 0
+	return m, nil
 }
 
-// Container action handlers
+// currentNav describes where the lists are now
+func (m *Model) currentNav() navEntry {
+	entry := navEntry{tab: m.activeTab, row: m.selectedRow, scroll: m.scrollOffset, imageFilter: m.containerImageFilter}
+	entry.id = m.rowID(m.selectedRow)
+	return entry
+}
 
-func (m *Model) handleContainerStartStop() (tea.Model, tea.Cmd) {
-	if m.selectedRow >= len(m.containers) {
-		return m, nil
+// rowID returns the ID of a row of the active tab (the name, for volumes),
+// "" past the end
+func (m *Model) rowID(row int) string {
+	switch {
+	case m.activeTab == 0 && row < len(m.containers):
+		return m.containers[row].ID
+	case m.activeTab == 1 && row < len(m.images):
+		return m.images[row].ID
+	case m.activeTab == 2 && row < len(m.volumes):
+		return m.volumes[row].Name
+	case m.activeTab == 3 && row < len(m.networks):
+		return m.networks[row].ID
 	}
-	container := m.containers[m.selectedRow]
+	return ""
+}
 
-	// Toggle start/stop based on current status
-	if container.Status == "RUNNING" {
-		m.actionInProgress = true
-		return m, m.stopContainerCmd(container.ID, container.Name)
-	} else {
-		m.actionInProgress = true
-		return m, m.startContainerCmd(container.ID, container.Name)
+// pushNav remembers the current place for Esc or backspace to return to
+func (m *Model) pushNav() {
+	m.navHistory = append(m.navHistory, m.currentNav())
+	if len(m.navHistory) > navHistoryMax {
+		m.navHistory = m.navHistory[1:]
 	}
 }
 
-func (m *Model) handleContainerRestart() (tea.Model, tea.Cmd) {
-	if m.selectedRow >= len(m.containers) {
-		return m, nil
+// popNav returns to the last remembered place: its tab, container filter,
+// selection and scroll position. It reports false when there is none.
+func (m *Model) popNav() bool {
+	if len(m.navHistory) == 0 {
+		return false
 	}
-	container := m.containers[m.selectedRow]
+	entry := m.navHistory[len(m.navHistory)-1]
+	m.navHistory = m.navHistory[:len(m.navHistory)-1]
 
-	// Only restart if running
-	if container.Status != "RUNNING" {
-		m.statusMessage = "Container must be running to restart"
-		return m, nil
+	m.activeTab = entry.tab
+	m.tabs = m.tabs.SetActiveTab(entry.tab)
+	if m.containerImageFilter != entry.imageFilter {
+		m.containerImageFilter = entry.imageFilter
+		m.applyContainerFilter()
 	}
 
-	m.actionInProgress = true
-	return m, m.restartContainerCmd(container.ID, container.Name)
+	// The row may have moved since, e.g. after a refresh
+	row := entry.row
+	if entry.id != "" && m.rowID(row) != entry.id {
+		for i := 0; m.rowID(i) != ""; i++ {
+			if m.rowID(i) == entry.id {
+				row = i
+				break
+			}
+		}
+	}
+	m.scrollOffset = entry.scroll
+	m.selectRow(row)
+	return true
 }
 
-func (m *Model) handleContainerLogs() (tea.Model, tea.Cmd) {
-	if m.selectedRow >= len(m.containers) {
-		return m, nil
+// selectRow selects a row of the active tab, within bounds, scrolling only
+// when it is out of view
+func (m *Model) selectRow(row int) {
+	if rows := m.getMaxRow(); row >= rows {
+		row = max(rows-1, 0)
+	}
+	m.selectedRow = row
+	if m.selectedRow < m.scrollOffset || m.selectedRow >= m.scrollOffset+m.pageSize() {
+		m.scrollOffset = max(m.selectedRow-m.pageSize()/2, 0)
 	}
-	container := m.containers[m.selectedRow]
-	m.selectedContainer = &container
-	m.currentView = types.ViewModeLogs
-	m.logsContent = ""
-	m.logsScrollOffset = 0
-	return m, m.getContainerLogsCmd(container.ID)
 }
 
-func (m *Model) handleContainerInspect() (tea.Model, tea.Cmd) {
-	if m.selectedRow >= len(m.containers) {
-		return m, nil
+// containerRow returns the row showing the container id, including rows of
+// grouped replicas, or -1
+func containerRow(containers []types.Container, id string) int {
+	for i, c := range containers {
+		if c.ID == id {
+			return i
+		}
+		for _, r := range c.Replicas {
+			if r.ID == id {
+				return i
+			}
+		}
 	}
-	container := m.containers[m.selectedRow]
-	m.selectedContainer = &container
-	m.currentView = types.ViewModeInspect
-	m.inspectContent = ""
-	return m, m.inspectContainerCmd(container.ID)
+	return -1
 }
 
-func (m *Model) handleContainerDelete() (tea.Model, tea.Cmd) {
-	if m.selectedRow >= len(m.containers) {
-		return m, nil
+// handleSchedules opens the Schedules panel, remembering the selected
+// container as the target of new schedules
+func (m *Model) handleSchedules() (tea.Model, tea.Cmd) {
+	m.scheduleTarget = types.Container{}
+	if m.activeTab == 0 && m.selectedRow < len(m.containers) {
+		m.scheduleTarget = m.containers[m.selectedRow]
 	}
-	// Toggle delete confirmation mode
-	m.deleteConfirmMode = !m.deleteConfirmMode
-	m.deleteConfirmOption = 1 // Default to "No"
+	m.scheduleCursor = 0
+	m.schedulePromptMode = false
+	m.currentView = types.ViewModeSchedules
 	return m, nil
 }
 
-func (m *Model) handleContainerExec() (tea.Model, tea.Cmd) {
-	if m.selectedRow >= len(m.containers) {
-		return m, nil
+// handleForwards opens the Forwards panel, remembering the selected
+// container as the target of new forwards
+func (m *Model) handleForwards() (tea.Model, tea.Cmd) {
+	m.forwardTarget = types.Container{}
+	if m.activeTab == 0 && m.selectedRow < len(m.containers) {
+		m.forwardTarget = m.containers[m.selectedRow]
 	}
-	container := m.containers[m.selectedRow]
+	m.forwardCursor = 0
+	m.forwardPromptMode = false
+	m.forwardErr = ""
+	m.currentView = types.ViewModeForwards
+	return m, nil
+}
 
-	// Only allow exec on running containers
-	if container.Status != "RUNNING" {
-		m.statusMessage = "Container must be running to exec"
-		return m, nil
+// handleForwardsViewKeys starts and stops port forwards
+func (m *Model) handleForwardsViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.forwardPromptMode {
+		return m.handleForwardPromptKeys(msg)
 	}
 
-	// Create exec command for interactive shell
-	// The command will suspend the TUI and run in the foreground
-	return m, m.execContainerCmd(container.ID)
+	list := m.forwards.List()
+	switch msg.String() {
+	case "q", "Q":
+		return m.handleQuit()
+	case "esc":
+		m.currentView = types.ViewModeList
+	case "up", "k":
+		if m.forwardCursor > 0 {
+			m.forwardCursor--
+		}
+	case "down", "j":
+		if m.forwardCursor < len(list)-1 {
+			m.forwardCursor++
+		}
+	case "n", "N":
+		if m.forwardTarget.ID == "" {
+			m.statusMessage = "Select a container on the Containers tab first"
+			return m, nil
+		}
+		if m.forwardTarget.Status != "RUNNING" {
+			m.forwardErr = m.forwardTarget.Name + " is not running"
+			return m, nil
+		}
+		m.forwardPromptMode = true
+		m.forwardInput = ""
+		m.forwardErr = ""
+	case "x", "X":
+		if m.forwardCursor < len(list) {
+			f := list[m.forwardCursor]
+			if err := m.forwards.Stop(f.ID); err != nil {
+				m.statusMessage = "ERROR: " + err.Error()
+				return m, nil
+			}
+			m.statusMessage = "Stopped forwarding " + f.String()
+			if m.forwardCursor > 0 && m.forwardCursor >= len(list)-1 {
+				m.forwardCursor--
+			}
+		}
+	}
+	return m, nil
 }
 
-// Image action handlers
+// handleForwardPromptKeys edits the ports of a new forward and starts it
+// on enter
+func (m *Model) handleForwardPromptKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.forwardPromptMode = false
+	case tea.KeyBackspace:
+		if runes := []rune(m.forwardInput); len(runes) > 0 {
+			m.forwardInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes:
+		m.forwardInput += string(msg.Runes)
+	case tea.KeyEnter:
+		localPort, containerPort, err := forward.ParseSpec(m.forwardInput)
+		if err != nil {
+			m.forwardErr = err.Error()
+			return m, nil
+		}
+		m.forwardPromptMode = false
+		m.forwardErr = ""
+		return m, m.startForwardCmd(m.forwardTarget.ID, m.forwardTarget.Name, localPort, containerPort)
+	}
+	return m, nil
+}
 
-func (m *Model) handleImageStart() (tea.Model, tea.Cmd) {
-	if m.selectedRow >= len(m.images) {
-		return m, nil
+// handleWorkspacesViewKeys picks, saves and deletes workspaces; a digit
+// switches to that workspace directly
+func (m *Model) handleWorkspacesViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.workspacePromptMode {
+		return m.handleWorkspacePromptKeys(msg)
 	}
-	image := m.images[m.selectedRow]
-	m.selectedImage = &image
 
-	// Start the image (create and run a container from it)
-	// For now, use simple defaults - can be expanded to a modal later
-	return m, m.runContainerCmd()
+	list := m.workspaces.List()
+	key := msg.String()
+	switch key {
+	case "q", "Q":
+		return m.handleQuit()
+	case "esc":
+		m.currentView = types.ViewModeList
+	case "up", "k":
+		if m.workspaceCursor > 0 {
+			m.workspaceCursor--
+		}
+	case "down", "j":
+		if m.workspaceCursor < len(list)-1 {
+			m.workspaceCursor++
+		}
+	case "enter":
+		if m.workspaceCursor < len(list) {
+			return m, m.applyWorkspace(list[m.workspaceCursor])
+		}
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		if i := int(key[0] - '1'); i < len(list) {
+			return m, m.applyWorkspace(list[i])
+		}
+	case "n", "N":
+		m.workspacePromptMode = true
+		m.workspaceInput = ""
+		if m.workspaceCursor < len(list) {
+			m.workspaceInput = list[m.workspaceCursor].Name
+		}
+		m.workspaceErr = ""
+	case "x", "X":
+		if m.workspaceCursor < len(list) {
+			if err := m.workspaces.Delete(list[m.workspaceCursor].Name); err != nil {
+				m.statusMessage = "ERROR: " + err.Error()
+				return m, nil
+			}
+			m.statusMessage = "Deleted workspace " + list[m.workspaceCursor].Name
+			if m.workspaceCursor > 0 && m.workspaceCursor >= len(list)-1 {
+				m.workspaceCursor--
+			}
+		}
+	}
+	return m, nil
 }
 
-func (m *Model) handleImageInspect() (tea.Model, tea.Cmd) {
-	if m.selectedRow >= len(m.images) {
-		return m, nil
+// handleWorkspacePromptKeys edits the name the current list view is saved
+// under, replacing a workspace of the same name
+func (m *Model) handleWorkspacePromptKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.workspacePromptMode = false
+	case tea.KeyBackspace:
+		if len(m.workspaceInput) > 0 {
+			runes := []rune(m.workspaceInput)
+			m.workspaceInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.workspaceInput += " "
+	case tea.KeyRunes:
+		m.workspaceInput += string(msg.Runes)
+	case tea.KeyEnter:
+		w := m.currentWorkspace()
+		w.Name = m.workspaceInput
+		if err := m.workspaces.Save(w); err != nil {
+			m.workspaceErr = err.Error()
+			return m, nil
+		}
+		m.workspacePromptMode = false
+		m.statusMessage = "Saved workspace " + strings.TrimSpace(w.Name)
 	}
-	image := m.images[m.selectedRow]
-	m.selectedImage = &image
-	m.currentView = types.ViewModeInspect
-	m.inspectContent = ""
-	return m, m.inspectImageCmd(image.ID)
+	return m, nil
 }
 
-func (m *Model) handleImageDelete() (tea.Model, tea.Cmd) {
-	if m.selectedRow >= len(m.images) {
-		return m, nil
+// currentWorkspace returns the list view as a workspace, without a name
+func (m *Model) currentWorkspace() workspace.Workspace {
+	return workspace.Workspace{
+		Tab:            m.activeTab,
+		ContainerImage: m.containerImageFilter,
+		ContainerSort:  m.containerSortOrder(),
+		GroupReplicas:  m.groupReplicas,
+		LogPreview:     m.logPreview,
+		StateColumns:   m.showContainerColumns,
+		ImageFilter:    m.imageFilter,
+		ImageSource:    m.showImageSource,
+		ImageDigest:    m.showImageDigest,
 	}
-	// Toggle delete confirmation mode
-	m.deleteConfirmMode = !m.deleteConfirmMode
-	m.deleteConfirmOption = 1 // Default to "No"
-	return m, nil
 }
 
-// Volume action handlers
+// applyWorkspace switches the list view to a workspace
+func (m *Model) applyWorkspace(w workspace.Workspace) tea.Cmd {
+	m.currentView = types.ViewModeList
+	m.activeTab = min(max(w.Tab, 0), 3)
+	m.tabs = m.tabs.SetActiveTab(m.activeTab)
+	m.selectedRow = 0
+	m.scrollOffset = 0
+	m.visualAnchor = -1
 
-func (m *Model) handleVolumeInspect() (tea.Model, tea.Cmd) {
-	if m.selectedRow >= len(m.volumes) {
-		return m, nil
+	m.containerImageFilter = w.ContainerImage
+	m.groupReplicas = w.GroupReplicas
+	m.applyContainerFilter()
+	m.sorts[0] = workspaceSort(w.ContainerSort)
+	_ = m.state.SetSort(sortTables[0], m.sorts[0])
+	m.sortContainers()
+	m.showContainerColumns = w.StateColumns
+	if m.logPreview != w.LogPreview {
+		m.logPreview = w.LogPreview
+		m.lastLogLines = make(map[string]string)
 	}
-	volume := m.volumes[m.selectedRow]
-	m.selectedVolume = &volume
-	m.currentView = types.ViewModeInspect
-	m.inspectContent = ""
-	return m, m.inspectVolumeCmd(volume.Name)
+
+	// Stale filters count from the TINYD_STALE_DAYS presets, which may have changed
+	if w.ImageFilter < types.ImageFilterStale+len(m.staleDays) {
+		m.imageFilter = w.ImageFilter
+	} else {
+		m.imageFilter = types.ImageFilterAll
+	}
+	m.showImageSource = w.ImageSource
+	m.showImageDigest = w.ImageDigest
+	m.applyImageFilter()
+
+	m.statusMessage = "Workspace: " + w.Name
+	return tea.Batch(m.refreshLogPreviewCmd(), m.imageTagTimesCmd())
 }
 
-func (m *Model) handleVolumeDelete() (tea.Model, tea.Cmd) {
-	if m.selectedRow >= len(m.volumes) {
-		return m, nil
+// handleSchedulesViewKeys processes input in the Schedules panel
+func (m *Model) handleSchedulesViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.schedulePromptMode {
+		return m.handleSchedulePromptKeys(msg)
+	}
+
+	pending := m.schedules.Pending()
+	switch msg.String() {
+	case "q", "Q":
+		return m.handleQuit()
+	case "esc":
+		m.currentView = types.ViewModeList
+	case "up", "k":
+		if m.scheduleCursor > 0 {
+			m.scheduleCursor--
+		}
+	case "down", "j":
+		if m.scheduleCursor < len(pending)-1 {
+			m.scheduleCursor++
+		}
+	case "n", "N":
+		if m.scheduleTarget.ID == "" {
+			m.statusMessage = "Select a container on the Containers tab first"
+			return m, nil
+		}
+		m.schedulePromptMode = true
+		m.scheduleInput = ""
+		m.scheduleErr = ""
+	case "x", "X":
+		if m.scheduleCursor < len(pending) {
+			m.schedules.Cancel(pending[m.scheduleCursor].ID)
+			if m.scheduleCursor > 0 && m.scheduleCursor >= len(pending)-1 {
+				m.scheduleCursor--
+			}
+		}
 	}
-	// Toggle delete confirmation mode
-	m.deleteConfirmMode = !m.deleteConfirmMode
-	m.deleteConfirmOption = 1 // Default to "No"
 	return m, nil
 }
 
-// Network action handlers
-
-func (m *Model) handleNetworkInspect() (tea.Model, tea.Cmd) {
-	if m.selectedRow >= len(m.networks) {
-		return m, nil
+// handleSchedulePromptKeys edits a new schedule and adds it on enter
+func (m *Model) handleSchedulePromptKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.schedulePromptMode = false
+	case tea.KeyBackspace:
+		if len(m.scheduleInput) > 0 {
+			runes := []rune(m.scheduleInput)
+			m.scheduleInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.scheduleInput += " "
+	case tea.KeyRunes:
+		m.scheduleInput += string(msg.Runes)
+	case tea.KeyEnter:
+		action, at, err := schedule.Parse(m.scheduleInput, time.Now())
+		if err != nil {
+			m.scheduleErr = err.Error()
+			return m, nil
+		}
+		entry := m.schedules.Add(m.scheduleTarget.ID, m.scheduleTarget.Name, action, at)
+		m.schedulePromptMode = false
+		m.statusMessage = "Scheduled: " + entry.String()
+		if !m.scheduleTicking {
+			m.scheduleTicking = true
+			return m, scheduleTickCmd()
+		}
 	}
-	network := m.networks[m.selectedRow]
-	m.selectedNetwork = &network
-	m.currentView = types.ViewModeInspect
-	m.inspectContent = ""
-	return m, m.inspectNetworkCmd(network.ID)
+	return m, nil
 }
 
-func (m *Model) handleNetworkDelete() (tea.Model, tea.Cmd) {
-	if m.selectedRow >= len(m.networks) {
-		return m, nil
+// handleTasksViewKeys processes input in the tasks view
+func (m *Model) handleTasksViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	tasksNow := m.taskQueue.Tasks()
+
+	switch msg.String() {
+	case "q", "Q":
+		return m.handleQuit()
+	case "esc":
+		m.currentView = types.ViewModeList
+	case "up", "k":
+		if m.taskCursor > 0 {
+			m.taskCursor--
+		}
+	case "down", "j":
+		if m.taskCursor < len(tasksNow)-1 {
+			m.taskCursor++
+		}
+	case "x", "X":
+		if m.taskCursor < len(tasksNow) {
+			task := tasksNow[m.taskCursor]
+			if !m.taskQueue.Cancel(task.ID) {
+				m.statusMessage = task.Name + " has already finished"
+			}
+		}
+	case "c", "C":
+		m.taskQueue.ClearFinished()
+		m.taskCursor = 0
 	}
-	// Toggle delete confirmation mode
-	m.deleteConfirmMode = !m.deleteConfirmMode
-	m.deleteConfirmOption = 1 // Default to "No"
 	return m, nil
 }
@@ -3,40 +3,163 @@ package ui
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/docker/go-units"
 	"tinyd/internal/components"
+	"tinyd/internal/docker"
+	"tinyd/internal/notes"
+	"tinyd/internal/provision"
+	"tinyd/internal/statshistory"
+	"tinyd/internal/tasks"
+	"tinyd/internal/theme"
 	"tinyd/internal/types"
+	"tinyd/internal/workspace"
 )
 
 // Color styles for status indicators
 var (
-	greenStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#00FF00"))
-	yellowStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFF00"))
-	redStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF0000"))
-	grayStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#999999"))
+	greenStyle  = lipgloss.NewStyle().Foreground(theme.Color("#00FF00"))
+	yellowStyle = lipgloss.NewStyle().Foreground(theme.Color("#FFFF00"))
+	redStyle    = lipgloss.NewStyle().Foreground(theme.Color("#FF0000"))
+	grayStyle   = lipgloss.NewStyle().Foreground(theme.Color("#999999"))
+)
+
+// Column breakpoints in terminal cells: optional columns are dropped on
+// narrower terminals instead of squeezing the remaining ones
+const (
+	portsColumnMinWidth  = 90  // Containers: PORTS
+	scopeColumnMinWidth  = 90  // Networks: SCOPE
+	sourceColumnMinWidth = 100 // Images: SOURCE (shown with o)
 )
 
 // View renders the UI
 func (m *Model) View() string {
 	if m.err != nil {
-		return fmt.Sprintf("Error: %v\n\nPress q to quit", m.err)
+		return m.renderError()
+	}
+	if m.width < components.MinWidth || m.height < components.MinHeight {
+		return m.renderTooSmall()
 	}
 
 	// Render based on current view mode
+	var view string
 	switch m.currentView {
 	case types.ViewModeList:
 		return m.renderListView()
 	case types.ViewModeLogs:
-		return m.renderLogsView()
+		view = m.renderLogsView()
 	case types.ViewModeInspect:
-		return m.renderInspectView()
+		view = m.renderInspectView()
+	case types.ViewModeWatch:
+		view = m.renderWatchView()
+	case types.ViewModeLayers:
+		view = m.renderLayersView()
+	case types.ViewModeBulkEnv:
+		view = m.renderBulkEnvView()
+	case types.ViewModeRunImage:
+		view = m.renderRunImageView()
+	case types.ViewModeTasks:
+		view = m.renderTasksView()
+	case types.ViewModeLogSearch:
+		view = m.renderLogSearchView()
+	case types.ViewModeSchedules:
+		view = m.renderSchedulesView()
+	case types.ViewModeMessages:
+		view = m.renderMessagesView()
+	case types.ViewModeRelated:
+		view = m.renderRelatedView()
+	case types.ViewModeStartFailure:
+		view = m.renderStartFailureView()
+	case types.ViewModeTagCleanup:
+		view = m.renderTagCleanupView()
+	case types.ViewModeAggregatedLogs:
+		view = m.renderAggregatedLogsView()
+	case types.ViewModeProcesses:
+		view = m.renderProcessesView()
+	case types.ViewModeWorkspaces:
+		view = m.renderWorkspacesView()
+	case types.ViewModeEvents:
+		view = m.renderEventsView()
+	case types.ViewModeExecOutput:
+		view = m.renderExecOutputView()
+	case types.ViewModeHealth:
+		view = m.renderHealthView()
+	case types.ViewModeEnv:
+		view = m.renderEnvView()
+	case types.ViewModeForwards:
+		view = m.renderForwardsView()
+	case types.ViewModeProvision:
+		view = m.renderProvisionView()
+	case types.ViewModeStats:
+		view = m.renderStatsDashboard()
+	case types.ViewModeLabels:
+		view = m.renderLabelEditor()
 	default:
 		return "Unknown view mode\n\nPress q to quit"
 	}
+
+	// Detail views have no action bar; show the detach prompt below them
+	if m.detachConfirm.Active() {
+		view += "\n" + m.detachConfirm.View()
+	}
+	return view
+}
+
+// renderError shows why the daemon couldn't be reached. Socket permission
+// errors come with fixes for the socket that was tried.
+func (m *Model) renderError() string {
+	errorStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FF0000"))
+
+	textStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999"))
+
+	var b strings.Builder
+	b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+	b.WriteString("\n\n")
+
+	host := m.docker.Underlying().DaemonHost()
+	if diagnosis, ok := docker.DiagnoseConnection(m.err, host); ok {
+		kind := "rootful"
+		if diagnosis.Rootless {
+			kind = "rootless"
+		}
+		b.WriteString(textStyle.Render(fmt.Sprintf("Tried %s (%s daemon socket)", diagnosis.Host, kind)))
+		b.WriteString("\n\n")
+		b.WriteString(textStyle.Render("To fix:"))
+		b.WriteString("\n")
+		for _, fix := range diagnosis.Fixes {
+			b.WriteString(textStyle.Render("  - " + fix))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("Press q to quit")
+	return b.String()
+}
+
+// renderTooSmall asks for a bigger terminal, centered in the current one
+func (m *Model) renderTooSmall() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFAA00")).
+		Bold(true)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999"))
+
+	notice := lipgloss.JoinVertical(lipgloss.Center,
+		titleStyle.Render("Terminal too small"),
+		helpStyle.Render(fmt.Sprintf("need %dx%d, have %dx%d", components.MinWidth, components.MinHeight, m.width, m.height)),
+		helpStyle.Render("[Q]uit"),
+	)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, notice)
 }
 
 // renderListView renders the main list view
@@ -44,55 +167,228 @@ func (m *Model) renderListView() string {
 	var b strings.Builder
 
 	// Update and render tabs
-	m.tabs = m.tabs.SetActiveTab(m.activeTab).WithWidth(m.width)
+	m.tabs = m.tabs.SetActiveTab(m.activeTab).SetBadges(m.tabBadges()).WithWidth(m.width)
+	if m.updateVersion != "" {
+		m.tabs = m.tabs.SetNotice("update available v" + m.updateVersion + " [^O] ")
+	} else if m.hostInfo.Rootless {
+		m.tabs = m.tabs.SetNotice("rootless ")
+	} else if m.lowBandwidth {
+		m.tabs = m.tabs.SetNotice("low bandwidth ")
+	}
 	tabsContent := m.tabs.View()
 	b.WriteString(tabsContent)
 
 	// Render content based on active tab
 	var contentStr string
+	m.tableHeaders = nil
+	if warning := m.listWarning(); warning != "" {
+		warningStyle := lipgloss.NewStyle().
+			Foreground(theme.Color("#FFAA00")).
+			Background(theme.Color("#0a0a0a")).
+			Bold(true)
+		contentStr = warningStyle.Render(truncateWithEllipsis("⚠ "+warning, m.width-2)) + "\n"
+	}
+	if m.activeTab == 0 {
+		contentStr += m.renderHostSummary()
+	}
+	if filter := m.labelFilters[m.activeTab]; filter != "" {
+		filterStyle := lipgloss.NewStyle().
+			Foreground(theme.Color("#00FFFF")).
+			Background(theme.Color("#0a0a0a"))
+		contentStr += filterStyle.Render(truncateWithEllipsis("Filtered by "+filter+"  [F] change", m.width-2)) + "\n"
+	}
+	// The table header is the first line after the tabs, warning and summary
+	m.tableHeaderRow = strings.Count(tabsContent+contentStr, "\n")
 	switch m.activeTab {
 	case 0:
-		contentStr = m.renderContainersTab()
+		contentStr += m.renderContainersTab() + m.renderGone()
 	case 1:
-		contentStr = m.renderImagesTab()
+		contentStr += m.renderImagesTab() + m.renderGone()
 	case 2:
-		contentStr = m.renderVolumesTab()
+		contentStr += m.renderVolumesTab()
 	case 3:
-		contentStr = m.renderNetworksTab()
+		contentStr += m.renderNetworksTab()
 	}
 	b.WriteString(contentStr)
-
-	// Calculate padding to push action bar to bottom
-	// Count actual lines used (tabs + visible content rows + action bar)
-	tabsHeight := strings.Count(tabsContent, "\n")
-	contentHeight := strings.Count(contentStr, "\n")
-	actionBarHeight := 3
-
-	// Total used height including current content
-	usedHeight := tabsHeight + contentHeight + actionBarHeight + 2 // +2 for newlines
-
-	// Add padding to push action bar to bottom
-	remainingHeight := m.height - usedHeight
-	if remainingHeight > 0 {
-		b.WriteString(strings.Repeat("\n", remainingHeight))
-	} else if remainingHeight < 0 {
-		// If content is too tall, don't add padding
+	if !strings.HasSuffix(contentStr, "\n") {
 		b.WriteString("\n")
 	}
 
 	// Render action bar at bottom
-	b.WriteString("\n")
 	m.actionBar = m.actionBar.WithWidth(m.width)
-	if m.statusMessage != "" {
+	if m.detachConfirm.Active() {
+		m.actionBar = m.actionBar.SetStatusMessage("").SetActions(m.detachConfirm.View())
+	} else if m.imageCascadeConfirm.Active() {
+		m.actionBar = m.actionBar.SetStatusMessage("").SetActions(m.imageCascadeConfirm.View())
+	} else if m.bulkConfirm.Active() {
+		m.actionBar = m.actionBar.SetStatusMessage("").SetActions(m.bulkConfirm.View())
+	} else if m.sshPromptMode {
+		m.actionBar = m.actionBar.SetStatusMessage("").SetActions(m.renderSSHPrompt())
+	} else if m.pullPromptMode {
+		m.actionBar = m.actionBar.SetStatusMessage("").SetActions(m.renderPullPrompt())
+	} else if m.pullKeepMode {
+		m.actionBar = m.actionBar.SetStatusMessage("").SetActions(m.renderPullKeepPrompt())
+	} else if m.captureMode {
+		m.actionBar = m.actionBar.SetStatusMessage("").SetActions(m.renderCapturePrompt())
+	} else if m.filterMode {
+		m.actionBar = m.actionBar.SetStatusMessage("").SetActions(m.renderFilterPrompt())
+	} else if m.dnsPromptMode {
+		m.actionBar = m.actionBar.SetStatusMessage("").SetActions(m.renderDNSPrompt())
+	} else if m.notePromptMode {
+		m.actionBar = m.actionBar.SetStatusMessage("").SetActions(m.renderNotePrompt())
+	} else if m.fileCopyMode {
+		m.actionBar = m.actionBar.SetStatusMessage("").SetActions(m.renderFileCopyPrompt())
+	} else if m.drainMode {
+		m.actionBar = m.actionBar.SetStatusMessage("").SetActions(m.renderDrainPrompt())
+	} else if m.commitMode {
+		m.actionBar = m.actionBar.SetStatusMessage("").SetActions(m.renderCommitPrompt())
+	} else if m.limitsMode {
+		m.actionBar = m.actionBar.SetStatusMessage("").SetActions(m.renderLimitsPrompt())
+	} else if m.pruneMode {
+		m.actionBar = m.actionBar.SetStatusMessage("").SetActions(m.renderPrunePrompt())
+	} else if m.execPromptMode {
+		m.actionBar = m.actionBar.SetStatusMessage("").SetActions(m.renderExecPrompt())
+	} else if m.execOptsMode {
+		m.actionBar = m.actionBar.SetStatusMessage("").SetActions(m.renderExecOptionsPrompt())
+	} else if m.imageSaveMode {
+		m.actionBar = m.actionBar.SetStatusMessage("").SetActions(m.renderImageSavePrompt())
+	} else if m.tagCleanupMode {
+		m.actionBar = m.actionBar.SetStatusMessage("").SetActions(m.renderTagCleanupPrompt())
+	} else if m.networkCreateMode {
+		m.actionBar = m.actionBar.SetStatusMessage("").SetActions(m.renderNetworkCreatePrompt())
+	} else if m.logSearchPromptMode {
+		m.actionBar = m.actionBar.SetStatusMessage("").SetActions(m.renderLogSearchPrompt())
+	} else if m.volumeCopyMode && m.statusMessage == "" {
+		m.actionBar = m.actionBar.SetStatusMessage("").SetActions(m.renderVolumeCopyPrompt())
+	} else if m.statusMessage != "" {
 		m.actionBar = m.actionBar.SetStatusMessage(m.statusMessage)
 	} else {
 		m.actionBar = m.actionBar.SetActions(m.getActionShortcuts())
 	}
-	b.WriteString(m.actionBar.View())
 
+	// Pad so the action bar takes the last lines of the terminal; without a
+	// trailing newline the view is exactly m.height lines when the rows fill
+	// the viewport
+	actionBar := strings.TrimSuffix(m.actionBar.View(), "\n")
+	if padding := m.height - components.LineCount(b.String()) - components.LineCount(actionBar); padding > 0 {
+		b.WriteString(strings.Repeat("\n", padding))
+	}
+	b.WriteString(actionBar)
+
+	return b.String()
+}
+
+// hostPressure is the share of the host's CPUs or memory in use above which
+// the host summary is highlighted
+const hostPressure = 0.9
+
+// renderHostSummary renders the CPUs, memory and, when the daemon runs on
+// this machine, the load of the daemon's host above the containers, with
+// the usage of the containers whose stats are streamed for context
+func (m *Model) renderHostSummary() string {
+	info := m.hostInfo
+	if info.NCPU == 0 {
+		return ""
+	}
+
+	var cpu float64
+	var mem uint64
+	for _, stats := range m.containerStats {
+		cpu += stats.CPUPercent
+		mem += stats.MemBytes
+	}
+	hostCPU := cpu / float64(info.NCPU)
+	var hostMem float64
+	if info.MemTotal > 0 {
+		hostMem = float64(mem) * 100 / float64(info.MemTotal)
+	}
+	busy := hostCPU >= hostPressure*100 || hostMem >= hostPressure*100
+
+	summary := fmt.Sprintf("Host: %d CPUs", info.NCPU)
+	if load := m.hostLoad; load != nil {
+		loadShare := load.Load1 * 100 / float64(info.NCPU)
+		summary += fmt.Sprintf(", load %.2f %.2f %.2f (%d%%)", load.Load1, load.Load5, load.Load15, int(loadShare))
+		busy = busy || loadShare >= hostPressure*100
+		if info.MemTotal > 0 {
+			used := float64(load.MemUsed) * 100 / float64(info.MemTotal)
+			summary += fmt.Sprintf(" | memory %s of %s used (%d%%)",
+				units.BytesSize(float64(load.MemUsed)), units.BytesSize(float64(info.MemTotal)), int(used))
+			busy = busy || used >= hostPressure*100
+		}
+	} else if info.MemTotal > 0 {
+		summary += ", " + units.BytesSize(float64(info.MemTotal)) + " memory"
+	}
+	if len(m.containerStats) > 0 {
+		summary += fmt.Sprintf(" | containers CPU %.0f%% (%d%% of host), %s (%d%%)",
+			cpu, int(hostCPU), units.BytesSize(float64(mem)), int(hostMem))
+	}
+
+	style := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+	if busy {
+		style = style.Foreground(theme.Color("#FFAA00")).Bold(true)
+	}
+	return style.Render(truncateWithEllipsis(summary, m.width-2)) + "\n"
+}
+
+// renderGone lists the containers or images removed since start, greyed
+// out below the table, when shown
+func (m *Model) renderGone() string {
+	if m.goneLines() == 0 {
+		return ""
+	}
+
+	type gone struct {
+		name string
+		at   time.Time
+	}
+	var items []gone
+	if m.activeTab == 0 {
+		for _, r := range m.containerChurn.Removed() {
+			items = append(items, gone{r.Item.Name + " (" + r.Item.Image + ")", r.At})
+		}
+	} else {
+		for _, r := range m.imageChurn.Removed() {
+			items = append(items, gone{r.Item.Repository + ":" + r.Item.Tag + " (" + r.Item.ID + ")", r.At})
+		}
+	}
+
+	goneStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#666666")).
+		Background(theme.Color("#0a0a0a"))
+
+	var b strings.Builder
+	b.WriteString("\n")
+	b.WriteString(goneStyle.Render(fmt.Sprintf(" Removed since start (%d)", len(items))))
+	for _, item := range items[:min(len(items), maxGoneShown)] {
+		b.WriteString("\n")
+		b.WriteString(goneStyle.Render(truncateWithEllipsis("   GONE "+item.at.Format("15:04:05")+" "+item.name, m.width-2)))
+	}
 	return b.String()
 }
 
+// tabBadges returns the live item counts shown in the tab labels
+func (m *Model) tabBadges() []string {
+	if m.loading {
+		return nil
+	}
+
+	running := 0
+	for _, c := range m.containers {
+		if c.Status == "RUNNING" {
+			running++
+		}
+	}
+
+	return []string{
+		fmt.Sprintf("%d/%d▶", len(m.containers), running),
+		fmt.Sprintf("%d", len(m.images)),
+		fmt.Sprintf("%d", len(m.volumes)),
+		fmt.Sprintf("%d", len(m.networks)),
+	}
+}
+
 // renderContainersTab renders the containers tab with proper table formatting
 func (m *Model) renderContainersTab() string {
 	if len(m.containers) == 0 {
@@ -104,19 +400,34 @@ func (m *Model) renderContainersTab() string {
 
 	// Calculate responsive column widths using full terminal width
 	totalWidth := m.width - 4 // Account for padding
-	if totalWidth < 60 {
-		totalWidth = 60 // Minimum width for reasonable display
-	}
 
 	// Fixed columns: Status(2) + CPU(8) + MEM(8) + Ports(15)
 	// Spacing: 5 gaps * 2 spaces = 10
 	fixedWidth := 2 + 8 + 8 + 15
 	spacing := 5 * 2 // (6 columns - 1) * 2 spaces per gap
+	showPorts := m.width >= portsColumnMinWidth
+	if !showPorts {
+		fixedWidth -= 15
+		spacing -= 2
+	}
+	// Optional uptime, exit code, restart and I/O columns
+	var stateHeaders []components.TableHeader
+	if m.showContainerColumns {
+		for _, column := range m.containerColumns {
+			header := containerColumnHeaders[column]
+			stateHeaders = append(stateHeaders, header)
+			fixedWidth += header.Width
+			spacing += 2
+		}
+	}
 	fillWidth := totalWidth - fixedWidth - spacing
 
-	// Ensure minimum width for fill columns
-	if fillWidth < 40 {
-		fillWidth = 40
+	// Wide log preview takes a third fill column
+	showLogColumn := m.logPreview && m.logPreviewWide()
+	logFill := 0
+	if showLogColumn {
+		logFill = fillWidth / 3
+		fillWidth -= logFill + 2
 	}
 
 	// Two fill columns: Name and Image (distribute equally)
@@ -137,13 +448,25 @@ func (m *Model) renderContainersTab() string {
 		{Label: "IMAGE", Width: imageFill, AlignRight: false},
 		{Label: "CPU", Width: 8, AlignRight: true},
 		{Label: "MEM", Width: 8, AlignRight: true},
-		{Label: "PORTS", Width: 15, AlignRight: false},
+	}
+	headers = append(headers, stateHeaders...)
+	portsColumn := len(headers)
+	if showPorts {
+		headers = append(headers, components.TableHeader{Label: "PORTS", Width: 15, AlignRight: false})
+	}
+	if showLogColumn {
+		headers = append(headers, components.TableHeader{Label: "LAST LOG", Width: logFill, AlignRight: false})
+	}
+	if showLogColumn {
+		m.adjustColumns(headers, 1, 2, len(headers)-1)
+	} else {
+		m.adjustColumns(headers, 1, 2)
 	}
 
 	// Build table rows (only visible ones based on scroll position)
 	var rows []components.TableRow
 	start := m.scrollOffset
-	end := m.scrollOffset + m.viewportHeight
+	end := m.scrollOffset + m.pageSize()
 	if end > len(m.containers) {
 		end = len(m.containers)
 	}
@@ -155,8 +478,8 @@ func (m *Model) renderContainersTab() string {
 		c := m.containers[i]
 
 		// Handle delete confirmation overlay
-		if m.deleteConfirmMode && i == m.selectedRow {
-			confirmText := renderDeleteConfirmation(c.Name, m.deleteConfirmOption)
+		if m.deleteConfirm.Active() && i == m.selectedRow {
+			confirmText := m.deleteConfirm.View()
 			emptyCells := make([]string, len(headers)-1)
 			rows = append(rows, components.TableRow{
 				Cells:      append([]string{confirmText}, emptyCells...),
@@ -165,25 +488,77 @@ func (m *Model) renderContainersTab() string {
 			continue
 		}
 
+		// Grouped compose services show the service and replica count
+		name := c.Name
+		if len(c.Replicas) > 0 {
+			name = fmt.Sprintf("%s-%s ×%d", c.ComposeProject, c.ComposeService, len(c.Replicas)+1)
+		}
+		// Risky settings show as badges before the name
+		if len(c.Risks) > 0 {
+			name = "[" + strings.Join(c.Risks, " ") + "] " + name
+		}
+		// Mark containers created since start
+		if m.containerChurn.IsNew(c) {
+			name = "NEW " + name
+		}
+		// Mark containers with a note
+		if m.notes.Has(notes.Container, c.ID, c.Name) {
+			name = "✎ " + name
+		}
+		// Mark containers with an active bind-mount watch
+		if _, watched := m.watchedReplica(c); watched {
+			name = "⟳ " + name
+		}
+		if m.isMarked(i) {
+			name = "✓ " + name
+		}
+
 		cells := []string{
-			m.getStatusDot(c.Status),
-			truncateWithEllipsis(c.Name, headers[1].Width),   // Fill column - truncate
+			m.getStatusDot(c.Status) + healthMark(c.Health),
+			truncateWithEllipsis(name, headers[1].Width),     // Fill column - truncate
 			truncateWithEllipsis(c.Image, headers[2].Width),  // Fill column - truncate
 			c.CPU,                                             // Fixed column - short values
 			c.Mem,                                             // Fixed column - short values
-			truncateWithEllipsis(c.Ports, 15),                // Can be long
+		}
+		if m.showContainerColumns {
+			for _, column := range m.containerColumns {
+				cells = append(cells, containerColumnCell(c, column))
+			}
+		}
+		if showPorts {
+			cells = append(cells, truncateWithEllipsis(c.Ports, headers[portsColumn].Width)) // Can be long
 		}
 
-		rows = append(rows, components.TableRow{
+		// Last log line: extra column in wide mode, dim line under the row otherwise
+		var subline string
+		if m.logPreview && c.Status == "RUNNING" {
+			if showLogColumn {
+				cells = append(cells, truncateWithEllipsis(m.lastLogLines[c.ID], headers[len(headers)-1].Width))
+			} else if line := m.lastLogLines[c.ID]; line != "" {
+				subline = truncateWithEllipsis("└ "+line, totalWidth-4)
+			}
+		} else if showLogColumn {
+			cells = append(cells, "")
+		}
+
+		row := components.TableRow{
 			Cells:      cells,
 			IsSelected: i == m.selectedRow,
-		})
+			Subline:    subline,
+		}
+		// Containers breaking an alert rule are shown in red
+		if m.alerts.Firing(c.ID) {
+			row.Style = redStyle
+		}
+		rows = append(rows, row)
 	}
 
 	// Create and render table
-	table := components.NewTableComponent(headers).
+	table := m.newListTable(headers).
 		WithWidth(m.width).
+		WithRowSpacing(m.rowSpacing()).
 		SetRows(rows).
+		SetEmptyMessage(m.emptyMessage()).
 		SetVisibleRange(0, len(rows))
 
 	// Add scroll indicator
@@ -191,6 +566,43 @@ func (m *Model) renderContainersTab() string {
 	return table.View() + scrollInfo
 }
 
+// containerColumnHeaders are the headers of the optional containers table
+// columns, by their name in TINYD_CONTAINER_COLUMNS
+var containerColumnHeaders = map[string]components.TableHeader{
+	"uptime":   {Label: "UPTIME", Width: 6, AlignRight: true},
+	"exit":     {Label: "EXIT", Width: 4, AlignRight: true},
+	"restarts": {Label: "RESTARTS", Width: 8, AlignRight: true},
+	"net":      {Label: "NET RX/TX", Width: 9, AlignRight: true},
+	"block":    {Label: "BLOCK R/W", Width: 9, AlignRight: true},
+}
+
+// containerColumnCell returns a container's cell in an optional column:
+// the uptime of running containers, the exit code of exited ones, the
+// restart count of all and the I/O rates of those whose stats are streamed
+func containerColumnCell(c types.Container, column string) string {
+	switch column {
+	case "net":
+		if c.NetIO != "" {
+			return c.NetIO
+		}
+	case "block":
+		if c.BlockIO != "" {
+			return c.BlockIO
+		}
+	case "uptime":
+		if c.Uptime != "" {
+			return c.Uptime
+		}
+	case "exit":
+		if c.ExitCode >= 0 && (c.Status == "STOPPED" || c.Status == "ERROR") {
+			return strconv.Itoa(c.ExitCode)
+		}
+	case "restarts":
+		return strconv.Itoa(c.RestartCount)
+	}
+	return "--"
+}
+
 // renderImagesTab renders the images tab with proper table formatting
 func (m *Model) renderImagesTab() string {
 	if len(m.images) == 0 {
@@ -202,31 +614,59 @@ func (m *Model) renderImagesTab() string {
 
 	// Calculate responsive column widths using full terminal width
 	totalWidth := m.width - 4
-	if totalWidth < 50 {
-		totalWidth = 50
-	}
 
-	// Fixed columns: Status(2) + Size(10) + Created(8)
-	// Spacing: 3 gaps * 2 spaces = 6
-	fixedWidth := 2 + 10 + 8
-	spacing := 3 * 2 // (4 columns - 1) * 2 spaces per gap
+	// Fixed columns: Status(2) + Size(10) + Created(8) + Arch(8)
+	// Spacing: 4 gaps * 2 spaces = 8
+	fixedWidth := 2 + 10 + 8 + 8
+	spacing := 4 * 2 // (5 columns - 1) * 2 spaces per gap
 	fillWidth := totalWidth - fixedWidth - spacing
-	if fillWidth < 20 {
-		fillWidth = 20
+
+	// OCI provenance columns share the fill with Repository:Tag; on narrow
+	// terminals only the revision is shown
+	repoFill := fillWidth
+	sourceFill := 0
+	showSource := m.showImageSource && m.width >= sourceColumnMinWidth
+	if showSource {
+		sourceFill = (fillWidth - 9 - 4) * 2 / 5
+		repoFill = fillWidth - sourceFill - 9 - 4
+	} else if m.showImageSource {
+		repoFill = fillWidth - 9 - 2
+	}
+	// The digest column ("sha256:" and 12 hex digits) takes from the fill too
+	if m.showImageDigest {
+		repoFill -= 19 + 2
 	}
 
 	// One fill column: Repository:Tag
 	headers := []components.TableHeader{
 		{Label: "", Width: 2, AlignRight: false},              // Status
-		{Label: "REPOSITORY:TAG", Width: fillWidth, AlignRight: false},
+		{Label: "REPOSITORY:TAG", Width: repoFill, AlignRight: false},
 		{Label: "SIZE", Width: 10, AlignRight: true},
 		{Label: "CREATED", Width: 8, AlignRight: false},
+		{Label: "ARCH", Width: 8, AlignRight: false},
+	}
+	sourceCol, revisionCol := 0, 0
+	if showSource {
+		sourceCol = len(headers)
+		headers = append(headers, components.TableHeader{Label: "SOURCE", Width: sourceFill, AlignRight: false})
+	}
+	if m.showImageSource {
+		revisionCol = len(headers)
+		headers = append(headers, components.TableHeader{Label: "REVISION", Width: 9, AlignRight: false})
+	}
+	if m.showImageDigest {
+		headers = append(headers, components.TableHeader{Label: "DIGEST", Width: 19, AlignRight: false})
+	}
+	if showSource {
+		m.adjustColumns(headers, 1, sourceCol)
+	} else {
+		m.adjustColumns(headers, 1)
 	}
 
 	// Build table rows (only visible ones based on scroll position)
 	var rows []components.TableRow
 	start := m.scrollOffset
-	end := m.scrollOffset + m.viewportHeight
+	end := m.scrollOffset + m.pageSize()
 	if end > len(m.images) {
 		end = len(m.images)
 	}
@@ -238,8 +678,8 @@ func (m *Model) renderImagesTab() string {
 		img := m.images[i]
 
 		// Handle delete confirmation overlay
-		if m.deleteConfirmMode && i == m.selectedRow {
-			confirmText := renderDeleteConfirmation(img.Repository+":"+img.Tag, m.deleteConfirmOption)
+		if m.deleteConfirm.Active() && i == m.selectedRow {
+			confirmText := m.deleteConfirm.View()
 			emptyCells := make([]string, len(headers)-1)
 			rows = append(rows, components.TableRow{
 				Cells:      append([]string{confirmText}, emptyCells...),
@@ -250,6 +690,15 @@ func (m *Model) renderImagesTab() string {
 
 		// Combine repository:tag
 		repoTag := img.Repository + ":" + img.Tag
+		if m.imageChurn.IsNew(img) {
+			repoTag = "NEW " + repoTag
+		}
+		if m.notes.Has(notes.Image, img.ID, imageNoteName(img)) {
+			repoTag = "✎ " + repoTag
+		}
+		if m.isMarked(i) {
+			repoTag = "✓ " + repoTag
+		}
 
 		// Only truncate if actually needed
 		repoTagCell := repoTag
@@ -262,6 +711,20 @@ func (m *Model) renderImagesTab() string {
 			repoTagCell,
 			img.Size,                    // Fixed column - short values
 			shortenTimeAgo(img.Created), // Fixed column - already short
+			m.imageArch(img),
+		}
+		if showSource {
+			cells = append(cells, truncateWithEllipsis(docker.ShortSource(img.Source), headers[sourceCol].Width))
+		}
+		if m.showImageSource {
+			cells = append(cells, truncateWithEllipsis(docker.ShortRevision(img.Revision), headers[revisionCol].Width))
+		}
+		if m.showImageDigest {
+			digest := "--"
+			if img.Digest != "" {
+				digest = docker.ShortDigest(img.Digest)
+			}
+			cells = append(cells, digest)
 		}
 
 		rows = append(rows, components.TableRow{
@@ -271,9 +734,11 @@ func (m *Model) renderImagesTab() string {
 	}
 
 	// Create and render table
-	table := components.NewTableComponent(headers).
+	table := m.newListTable(headers).
 		WithWidth(m.width).
+		WithRowSpacing(m.rowSpacing()).
 		SetRows(rows).
+		SetEmptyMessage(m.emptyMessage()).
 		SetVisibleRange(0, len(rows))
 
 	// Add scroll indicator
@@ -281,6 +746,46 @@ func (m *Model) renderImagesTab() string {
 	return table.View() + scrollInfo
 }
 
+// newListTable returns the table of the active tab with its sorted column
+// marked, remembering the headers for mouse clicks
+func (m *Model) newListTable(headers []components.TableHeader) components.TableComponent {
+	m.tableHeaders = headers
+	return components.NewTableComponent(headers).WithSort(m.sortedColumn(headers), m.sorts[m.activeTab].Desc)
+}
+
+// minFillWidth is how narrow widening another column makes a fill column
+const minFillWidth = 10
+
+// adjustColumns widens the column picked with { and } on this tab by the
+// cells added with ], taking them from the fill columns, and marks its
+// label. The extra width is capped at what the fill columns can give.
+func (m *Model) adjustColumns(headers []components.TableHeader, fill ...int) {
+	cw := &m.columnWidths[m.activeTab]
+	cw.count = len(headers)
+	if cw.focus <= 0 || cw.focus >= len(headers) {
+		return
+	}
+	headers[cw.focus].Label += "↔"
+	cw.extra = components.WidenColumn(headers, cw.focus, cw.extra, fill, minFillWidth)
+}
+
+// imageArch returns the ARCH cell of an image: its architecture, with a "!"
+// when the daemon can't run it natively, or "…" while it is looked up
+func (m *Model) imageArch(img types.Image) string {
+	platform, ok := m.imagePlatforms[img.ID]
+	if !ok {
+		return "…"
+	}
+	if platform == "" {
+		return "--"
+	}
+	arch := docker.ShortPlatform(platform)
+	if docker.PlatformMismatch(platform, m.hostInfo.Platform) {
+		arch = "!" + arch
+	}
+	return truncateWithEllipsis(arch, 8)
+}
+
 // renderVolumesTab renders the volumes tab with proper table formatting
 func (m *Model) renderVolumesTab() string {
 	if len(m.volumes) == 0 {
@@ -292,18 +797,12 @@ func (m *Model) renderVolumesTab() string {
 
 	// Calculate responsive column widths using full terminal width
 	totalWidth := m.width - 4
-	if totalWidth < 50 {
-		totalWidth = 50
-	}
 
 	// Fixed columns: Status(2)
 	// Spacing: 3 gaps * 2 spaces = 6
 	fixedWidth := 2
 	spacing := 3 * 2 // (4 columns - 1) * 2 spaces per gap
 	fillWidth := totalWidth - fixedWidth - spacing
-	if fillWidth < 30 {
-		fillWidth = 30
-	}
 
 	// Three fill columns: Name, Containers, Mount Point (distribute equally)
 	nameFill := fillWidth / 3
@@ -316,11 +815,12 @@ func (m *Model) renderVolumesTab() string {
 		{Label: "CONTAINERS", Width: containersFill, AlignRight: false},
 		{Label: "MOUNT POINT", Width: mountFill, AlignRight: false},
 	}
+	m.adjustColumns(headers, 1, 2, 3)
 
 	// Build table rows (only visible ones based on scroll position)
 	var rows []components.TableRow
 	start := m.scrollOffset
-	end := m.scrollOffset + m.viewportHeight
+	end := m.scrollOffset + m.pageSize()
 	if end > len(m.volumes) {
 		end = len(m.volumes)
 	}
@@ -332,8 +832,8 @@ func (m *Model) renderVolumesTab() string {
 		vol := m.volumes[i]
 
 		// Handle delete confirmation overlay
-		if m.deleteConfirmMode && i == m.selectedRow {
-			confirmText := renderDeleteConfirmation(vol.Name, m.deleteConfirmOption)
+		if m.deleteConfirm.Active() && i == m.selectedRow {
+			confirmText := m.deleteConfirm.View()
 			emptyCells := make([]string, len(headers)-1)
 			rows = append(rows, components.TableRow{
 				Cells:      append([]string{confirmText}, emptyCells...),
@@ -353,9 +853,14 @@ func (m *Model) renderVolumesTab() string {
 			containers = "-"
 		}
 
+		name := vol.Name
+		if m.isMarked(i) {
+			name = "✓ " + name
+		}
+
 		cells := []string{
 			statusDot,
-			truncateWithEllipsis(vol.Name, headers[1].Width),       // Fill column - truncate
+			truncateWithEllipsis(name, headers[1].Width),           // Fill column - truncate
 			truncateWithEllipsis(containers, headers[2].Width),     // Fill column - truncate
 			truncateWithEllipsis(vol.Mountpoint, headers[3].Width), // Fill column - truncate
 		}
@@ -367,9 +872,11 @@ func (m *Model) renderVolumesTab() string {
 	}
 
 	// Create and render table
-	table := components.NewTableComponent(headers).
+	table := m.newListTable(headers).
 		WithWidth(m.width).
+		WithRowSpacing(m.rowSpacing()).
 		SetRows(rows).
+		SetEmptyMessage(m.emptyMessage()).
 		SetVisibleRange(0, len(rows))
 
 	// Add scroll indicator
@@ -388,18 +895,17 @@ func (m *Model) renderNetworksTab() string {
 
 	// Calculate responsive column widths using full terminal width
 	totalWidth := m.width - 4
-	if totalWidth < 50 {
-		totalWidth = 50
-	}
 
 	// Fixed columns: Status(2) + Driver(10) + Scope(8) + IPv4(18)
 	// Spacing: 5 gaps * 2 spaces = 10
 	fixedWidth := 2 + 10 + 8 + 18
 	spacing := 5 * 2 // (6 columns - 1) * 2 spaces per gap
-	fillWidth := totalWidth - fixedWidth - spacing
-	if fillWidth < 20 {
-		fillWidth = 20
+	showScope := m.width >= scopeColumnMinWidth
+	if !showScope {
+		fixedWidth -= 8
+		spacing -= 2
 	}
+	fillWidth := totalWidth - fixedWidth - spacing
 
 	// Two fill columns: Name and Containers (distribute equally)
 	nameFill := fillWidth / 2
@@ -410,14 +916,17 @@ func (m *Model) renderNetworksTab() string {
 		{Label: "NAME", Width: nameFill, AlignRight: false},
 		{Label: "CONTAINERS", Width: containersFill, AlignRight: false},
 		{Label: "DRIVER", Width: 10, AlignRight: false},
-		{Label: "SCOPE", Width: 8, AlignRight: false},
-		{Label: "IPv4", Width: 18, AlignRight: false},
 	}
+	if showScope {
+		headers = append(headers, components.TableHeader{Label: "SCOPE", Width: 8, AlignRight: false})
+	}
+	headers = append(headers, components.TableHeader{Label: "IPv4", Width: 18, AlignRight: false})
+	m.adjustColumns(headers, 1, 2)
 
 	// Build table rows (only visible ones based on scroll position)
 	var rows []components.TableRow
 	start := m.scrollOffset
-	end := m.scrollOffset + m.viewportHeight
+	end := m.scrollOffset + m.pageSize()
 	if end > len(m.networks) {
 		end = len(m.networks)
 	}
@@ -429,8 +938,8 @@ func (m *Model) renderNetworksTab() string {
 		net := m.networks[i]
 
 		// Handle delete confirmation overlay
-		if m.deleteConfirmMode && i == m.selectedRow {
-			confirmText := renderDeleteConfirmation(net.Name, m.deleteConfirmOption)
+		if m.deleteConfirm.Active() && i == m.selectedRow {
+			confirmText := m.deleteConfirm.View()
 			emptyCells := make([]string, len(headers)-1)
 			rows = append(rows, components.TableRow{
 				Cells:      append([]string{confirmText}, emptyCells...),
@@ -447,14 +956,21 @@ func (m *Model) renderNetworksTab() string {
 		// TODO: Add Containers field to Network type to show connected container names
 		containers := "-"
 
+		name := net.Name
+		if m.isMarked(i) {
+			name = "✓ " + name
+		}
+
 		cells := []string{
 			statusDot,
-			truncateWithEllipsis(net.Name, headers[1].Width),       // Fill column - truncate
+			truncateWithEllipsis(name, headers[1].Width),           // Fill column - truncate
 			truncateWithEllipsis(containers, headers[2].Width),     // Fill column - truncate
 			net.Driver,                                              // Fixed column - short values
-			net.Scope,                                               // Fixed column - short values
-			truncateWithEllipsis(net.IPv4, 18),                     // Can be long
 		}
+		if showScope {
+			cells = append(cells, net.Scope) // Fixed column - short values
+		}
+		cells = append(cells, truncateWithEllipsis(net.IPv4, headers[len(headers)-1].Width)) // Can be long
 
 		rows = append(rows, components.TableRow{
 			Cells:      cells,
@@ -463,9 +979,11 @@ func (m *Model) renderNetworksTab() string {
 	}
 
 	// Create and render table
-	table := components.NewTableComponent(headers).
+	table := m.newListTable(headers).
 		WithWidth(m.width).
+		WithRowSpacing(m.rowSpacing()).
 		SetRows(rows).
+		SetEmptyMessage(m.emptyMessage()).
 		SetVisibleRange(0, len(rows))
 
 	// Add scroll indicator
@@ -478,29 +996,40 @@ func (m *Model) renderLogsView() string {
 	var b strings.Builder
 
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Background(lipgloss.Color("#0a0a0a")).
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
 		Bold(true)
 
 	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#999999")).
-		Background(lipgloss.Color("#0a0a0a"))
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
 
 	lineStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#999999")).
-		Background(lipgloss.Color("#0a0a0a"))
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
 
 	contentStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#999999")).
-		Background(lipgloss.Color("#0a0a0a"))
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
 
 	// Header
 	headerText := "Logs"
 	if m.selectedContainer != nil {
 		headerText = "Logs: " + m.selectedContainer.Name
 	}
-	headerRight := "[ESC] Back"
-	headerSpacing := strings.Repeat(" ", m.width-len(headerText)-len(headerRight)-4)
+	if label := m.logsRangeLabel(); label != "" {
+		headerText += " (" + label + ")"
+	}
+	if m.logsFollow {
+		headerText += " (following)"
+	}
+	headerText += m.lineViewStatus()
+	headerRight := "[/] Search  [F]ollow  [T]ime range  [M]ore  [S]tamps  [W]rap  [P]ager  [ESC] Back"
+	if m.logsSearchRe != nil {
+		headerText += " (" + m.logsMatchInfo + ")"
+		headerRight = "[N]ext/prev match  " + headerRight
+	}
+	headerSpacing := strings.Repeat(" ", max(m.width-lipgloss.Width(headerText)-len(headerRight)-4, 1))
 	b.WriteString(titleStyle.Render(headerText))
 	b.WriteString(headerSpacing)
 	b.WriteString(helpStyle.Render(headerRight))
@@ -510,11 +1039,16 @@ func (m *Model) renderLogsView() string {
 	b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
 	b.WriteString("\n")
 
-	// Calculate available lines for content
-	// Height - tabs(4) - header(1) - divider(1) - action bar(3) - scroll indicator(2)
-	availableLines := m.height - 11
-	if availableLines < 5 {
-		availableLines = 5
+	availableLines := m.logsVisibleLines()
+	if m.logsRangeMode {
+		b.WriteString(m.renderLogsRangePrompt())
+		b.WriteString("\n")
+	} else if m.logsPresetMode {
+		b.WriteString(m.renderLogsPresetPrompt())
+		b.WriteString("\n")
+	} else if m.logsSearchMode {
+		b.WriteString(m.renderLogsSearchPrompt())
+		b.WriteString("\n")
 	}
 
 	// Render content with scrolling
@@ -525,55 +1059,163 @@ func (m *Model) renderLogsView() string {
 		lines := strings.Split(m.logsContent, "\n")
 		totalLines := len(lines)
 
-		end := m.logsScrollOffset + availableLines
-		if end > totalLines {
-			end = totalLines
-		}
-
-		for i := m.logsScrollOffset; i < end; i++ {
-			if i < len(lines) {
-				b.WriteString(lines[i])
-				b.WriteString("\n")
+		m.writeScrolled(&b, lines, m.logsScrollOffset, availableLines, func(i int, line string) string {
+			// stderr in red, so errors stand out from regular output
+			stderr := i < len(m.logsStderr) && m.logsStderr[i]
+			if line == docker.LogRestartMarker {
+				return yellowStyle.Render(line)
+			} else if m.logsSearchRe != nil && m.logsSearchRe.MatchString(line) {
+				return highlightMatches(line, m.logsSearchRe, stderr)
+			} else if stderr {
+				return redStyle.Render(line)
 			}
-		}
+			return line
+		})
+
+		// Add scroll indicator
+		b.WriteString(m.getInspectScrollIndicator(totalLines, availableLines))
+	}
+
+	return b.String()
+}
 
-		// Fill remaining lines
-		for i := end - m.logsScrollOffset; i < availableLines; i++ {
+// writeScrolled writes the lines from offset on that fit in rows screen
+// rows, each rendered by render and then wrapped or cut at the horizontal
+// scroll, and fills the rest of the rows
+func (m *Model) writeScrolled(b *strings.Builder, lines []string, offset, rows int, render func(i int, line string) string) {
+	width := max(m.width-2, 10)
+	used := 0
+	for i := offset; i < len(lines) && used < rows; i++ {
+		rendered := render(i, lines[i])
+		var parts []string
+		if m.wrapLines {
+			parts = strings.Split(ansi.Hardwrap(rendered, width, true), "\n")
+		} else {
+			parts = []string{ansi.Cut(rendered, m.hScroll, m.hScroll+width)}
+		}
+		for _, part := range parts[:min(len(parts), rows-used)] {
+			b.WriteString(part)
 			b.WriteString("\n")
+			used++
 		}
+	}
+	b.WriteString(strings.Repeat("\n", rows-used))
+}
 
-		// Add scroll indicator
-		b.WriteString(m.getInspectScrollIndicator(totalLines, availableLines))
+// lineViewStatus describes how long lines are shown in the logs and
+// inspect views, for their headers: "" when cut at the first column
+func (m *Model) lineViewStatus() string {
+	if m.wrapLines {
+		return " (wrapped)"
+	}
+	if m.hScroll > 0 {
+		return fmt.Sprintf(" (from col %d)", m.hScroll+1)
+	}
+	return ""
+}
+
+// logsVisibleLines is the number of log lines the logs view shows at once
+func (m *Model) logsVisibleLines() int {
+	// Height - tabs(4) - header(1) - divider(1) - action bar(3) - scroll indicator(2)
+	lines := m.height - 11
+	if m.logsRangeMode || m.logsPresetMode || m.logsSearchMode {
+		lines -= 2
+	}
+	return max(lines, 5)
+}
+
+// logsRangeLabel describes the active logs time window, "" when unset
+func (m *Model) logsRangeLabel() string {
+	if m.logsSince.IsZero() && m.logsUntil.IsZero() {
+		return ""
+	}
+
+	today := time.Now().Format("2006-01-02")
+	format := func(t time.Time) string {
+		if t.IsZero() {
+			return "now"
+		}
+		if t.Format("2006-01-02") == today {
+			return t.Format("15:04:05")
+		}
+		return t.Format("2006-01-02 15:04:05")
+	}
+	if m.logsSince.IsZero() {
+		return "until " + format(m.logsUntil)
+	}
+	return format(m.logsSince) + " → " + format(m.logsUntil)
+}
+
+// renderLogsRangePrompt renders the since/until inputs of the time range picker
+func (m *Model) renderLogsRangePrompt() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	inputStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#00FF00")).
+		Background(theme.Color("#0a0a0a"))
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#666666")).
+		Background(theme.Color("#0a0a0a"))
+
+	errorStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FF0000")).
+		Background(theme.Color("#0a0a0a"))
+
+	var b strings.Builder
+	for i, label := range []string{" Since: ", "  Until: "} {
+		value := m.logsRangeInput[i]
+		if i == m.logsRangeField {
+			value += "█"
+		}
+		b.WriteString(labelStyle.Render(label))
+		b.WriteString(inputStyle.Render("[" + value + "]"))
 	}
+	b.WriteString(hintStyle.Render("  [Tab] Next field  [Enter] Apply  [Esc] Cancel"))
+	b.WriteString("\n")
 
+	if m.logsRangeErr != "" {
+		b.WriteString(errorStyle.Render(" " + m.logsRangeErr))
+	} else {
+		b.WriteString(hintStyle.Render(" e.g. 14:00, yesterday 14:00, 2024-05-01 14:10, 30m (ago); empty = open-ended"))
+	}
 	return b.String()
 }
 
-// renderInspectView renders the inspect detail view
-func (m *Model) renderInspectView() string {
+// aggSourceColors tell the containers of the aggregated logs view apart
+var aggSourceColors = []string{"#00FFFF", "#FF00FF", "#FFFF00", "#00FF00", "#FF8800", "#8888FF", "#FF5555", "#55FFAA"}
+
+// renderAggregatedLogsView renders the merged logs of several containers,
+// each line prefixed with its container's name in that container's color
+func (m *Model) renderAggregatedLogsView() string {
 	var b strings.Builder
 
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Background(lipgloss.Color("#0a0a0a")).
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
 		Bold(true)
 
 	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#999999")).
-		Background(lipgloss.Color("#0a0a0a"))
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
 
 	lineStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#999999")).
-		Background(lipgloss.Color("#0a0a0a"))
-
-	contentStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#999999")).
-		Background(lipgloss.Color("#0a0a0a"))
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
 
 	// Header
-	headerText := "Inspect"
-	headerRight := "[ESC] Back"
-	headerSpacing := strings.Repeat(" ", m.width-len(headerText)-len(headerRight)-4)
+	headerText := fmt.Sprintf("Logs: %d containers", len(m.aggSources))
+	if m.aggFollow {
+		headerText += " (following)"
+	}
+	headerRight := "[G] Bottom  [ESC] Back"
+	if len(m.aggSources) == 2 {
+		headerRight = "[|] Split  " + headerRight
+	}
+	headerSpacing := strings.Repeat(" ", max(m.width-len(headerText)-len(headerRight)-4, 1))
 	b.WriteString(titleStyle.Render(headerText))
 	b.WriteString(headerSpacing)
 	b.WriteString(helpStyle.Render(headerRight))
@@ -583,70 +1225,2496 @@ func (m *Model) renderInspectView() string {
 	b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
 	b.WriteString("\n")
 
-	// Calculate available lines for content
-	// Height - tabs(4) - header(1) - divider(1) - action bar(3) - scroll indicator(2)
-	availableLines := m.height - 11
-	if availableLines < 5 {
-		availableLines = 5
-	}
-
-	// Render content with scrolling
-	if m.inspectContent == "" {
-		b.WriteString(contentStyle.Render(" Loading..."))
-		b.WriteString("\n")
-	} else {
-		lines := strings.Split(m.inspectContent, "\n")
-		totalLines := len(lines)
-
-		end := m.logsScrollOffset + availableLines
-		if end > totalLines {
-			end = totalLines
+	if len(m.aggLines) == 0 {
+		status := " Loading logs..."
+		if !m.aggFollow {
+			status = " No log lines"
 		}
+		b.WriteString(helpStyle.Render(status))
+		b.WriteString("\n")
+		return b.String()
+	}
 
-		for i := m.logsScrollOffset; i < end; i++ {
-			if i < len(lines) {
-				b.WriteString(lines[i])
-				b.WriteString("\n")
-			}
-		}
+	if m.aggSplit && len(m.aggSources) == 2 {
+		m.writeSplitLogs(&b)
+		return b.String()
+	}
 
-		// Fill remaining lines
-		for i := end - m.logsScrollOffset; i < availableLines; i++ {
+	// Names are padded to the longest, so the lines start aligned
+	nameWidth := 0
+	for _, name := range m.aggSources {
+		nameWidth = max(nameWidth, len(name))
+	}
+	end := min(m.aggScroll+m.aggVisibleLines(), len(m.aggLines))
+	for _, line := range m.aggLines[min(m.aggScroll, end):end] {
+		if line.Source < 0 || line.Source >= len(m.aggSources) {
+			b.WriteString(yellowStyle.Render(truncateWithEllipsis(line.Line, m.width-2)))
 			b.WriteString("\n")
+			continue
 		}
-
-		// Add scroll indicator
-		b.WriteString(m.getInspectScrollIndicator(totalLines, availableLines))
+		nameStyle := lipgloss.NewStyle().
+			Foreground(theme.Color(aggSourceColors[line.Source%len(aggSourceColors)]))
+		prefix := fmt.Sprintf("%-*s | ", nameWidth, m.aggSources[line.Source])
+		b.WriteString(nameStyle.Render(prefix))
+		if line.Line == docker.LogRestartMarker {
+			b.WriteString(yellowStyle.Render(line.Line))
+		} else {
+			b.WriteString(truncateWithEllipsis(line.Line, max(m.width-2-len(prefix), 10)))
+		}
+		b.WriteString("\n")
 	}
 
 	return b.String()
 }
 
-// Helper functions
+// writeSplitLogs writes the logs of two containers side by side. Each line
+// keeps its own row in time order, so the panes scroll together and a line
+// sits below the other container's lines that came before it.
+func (m *Model) writeSplitLogs(b *strings.Builder) {
+	separator := grayStyle.Render(" │ ")
+	paneWidth := max((m.width-2-3)/2, 10)
+	pane := func(text string) string {
+		// Restart markers stand out as in the interleaved view
+		marker := text == docker.LogRestartMarker
+		text = truncateWithEllipsis(text, paneWidth)
+		padded := text + strings.Repeat(" ", max(paneWidth-ansi.StringWidth(text), 0))
+		if marker {
+			return yellowStyle.Render(padded)
+		}
+		return padded
+	}
 
-// getScrollIndicator returns a scroll indicator showing current position and scroll availability
-func (m *Model) getScrollIndicator(totalItems int) string {
-	if totalItems == 0 {
-		return ""
+	// Pane titles in the colors of the interleaved view
+	for source, name := range m.aggSources {
+		if source > 0 {
+			b.WriteString(separator)
+		}
+		nameStyle := lipgloss.NewStyle().
+			Foreground(theme.Color(aggSourceColors[source%len(aggSourceColors)])).
+			Bold(true)
+		b.WriteString(nameStyle.Render(fmt.Sprintf("%-*s", paneWidth, truncateWithEllipsis(name, paneWidth))))
 	}
+	b.WriteString("\n")
 
-	var b strings.Builder
+	end := min(m.aggScroll+m.aggVisibleLines(), len(m.aggLines))
+	for _, line := range m.aggLines[min(m.aggScroll, end):end] {
+		switch line.Source {
+		case 0:
+			b.WriteString(pane(line.Line))
+			b.WriteString(separator)
+		case 1:
+			b.WriteString(strings.Repeat(" ", paneWidth))
+			b.WriteString(separator)
+			b.WriteString(pane(line.Line))
+		default:
+			b.WriteString(yellowStyle.Render(truncateWithEllipsis(line.Line, m.width-2)))
+		}
+		b.WriteString("\n")
+	}
+}
+
+// renderLogsSearchPrompt renders the search input of the logs view, with
+// its mode and any error in the pattern
+func (m *Model) renderLogsSearchPrompt() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	inputStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#00FF00")).
+		Background(theme.Color("#0a0a0a"))
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#666666")).
+		Background(theme.Color("#0a0a0a"))
+
+	errorStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FF0000")).
+		Background(theme.Color("#0a0a0a"))
+
+	mode := "text"
+	if m.logsSearchRegex {
+		mode = "regex"
+	}
+
+	var b strings.Builder
+	b.WriteString(labelStyle.Render(" Search (" + mode + "): "))
+	b.WriteString(inputStyle.Render(m.logsSearchInput + "█"))
+	b.WriteString(hintStyle.Render("  [Tab] Text/regex  [Enter] Find  [Esc] Cancel"))
+	b.WriteString("\n")
+	if m.logsSearchErr != "" {
+		b.WriteString(errorStyle.Render(truncateWithEllipsis(" "+m.logsSearchErr, m.width-2)))
+	} else {
+		b.WriteString(hintStyle.Render(" Case-insensitive; matches are highlighted, n/N jump between them; empty clears"))
+	}
+	return b.String()
+}
+
+// highlightMatches renders a log line with the parts re matches
+// highlighted, the rest in red for stderr lines
+func highlightMatches(line string, re *regexp.Regexp, stderr bool) string {
+	matchStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#000000")).
+		Background(theme.Color("#FFFF00"))
+
+	render := func(s string) string { return s }
+	if stderr {
+		render = func(s string) string { return redStyle.Render(s) }
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, loc := range re.FindAllStringIndex(line, -1) {
+		if loc[0] == loc[1] {
+			continue // Empty matches have nothing to highlight
+		}
+		b.WriteString(render(line[last:loc[0]]))
+		b.WriteString(matchStyle.Render(line[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+	b.WriteString(render(line[last:]))
+	return b.String()
+}
+
+// renderLogsPresetPrompt renders the time range presets, the focused one
+// highlighted, followed by the custom range
+func (m *Model) renderLogsPresetPrompt() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	inputStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#00FF00")).
+		Background(theme.Color("#0a0a0a"))
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#666666")).
+		Background(theme.Color("#0a0a0a"))
+
+	var b strings.Builder
+	b.WriteString(labelStyle.Render(" Show: "))
+	for i := 0; i <= logsPresetCustom; i++ {
+		label := "custom…"
+		if i < logsPresetCustom {
+			label = logsRangePresets[i].label
+		}
+		if i == m.logsPresetCursor {
+			b.WriteString(inputStyle.Render("[" + label + "]"))
+		} else {
+			b.WriteString(hintStyle.Render(" " + label + " "))
+		}
+		b.WriteString(" ")
+	}
+	b.WriteString("\n")
+	b.WriteString(hintStyle.Render(" [←/→] Choose  [Enter] Apply  [Esc] Cancel"))
+	return b.String()
+}
+
+// renderInspectExportPrompt renders the file path prompt of the inspect export
+func (m *Model) renderInspectExportPrompt() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	inputStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#00FF00")).
+		Background(theme.Color("#0a0a0a"))
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#666666")).
+		Background(theme.Color("#0a0a0a"))
+
+	return labelStyle.Render(" Save JSON to: ") +
+		inputStyle.Render("["+m.inspectExportInput+"█]") +
+		hintStyle.Render("  [Enter] Save  [Esc] Cancel")
+}
+
+// renderInspectView renders the inspect detail view
+func (m *Model) renderInspectView() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	lineStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	contentStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	// Header
+	headerText := "Inspect" + m.lineViewStatus()
+	headerRight := "[←→] Scroll  [W]rap  [E]xport  [ESC] Back"
+	if m.activeTab == 0 && m.selectedContainer != nil {
+		headerRight = "[V] Env  [L]abels  [←→] Scroll  [W]rap  [E]xport  [ESC] Back"
+	} else if m.activeTab == 1 && m.selectedImage != nil {
+		headerRight = "[L] Layers  [←→] Scroll  [W]rap  [E]xport  [ESC] Back"
+	}
+	headerSpacing := strings.Repeat(" ", max(m.width-len(headerText)-len(headerRight)-4, 1))
+	b.WriteString(titleStyle.Render(headerText))
+	b.WriteString(headerSpacing)
+	b.WriteString(helpStyle.Render(headerRight))
+	b.WriteString("\n")
+
+	// Content divider
+	b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
+	b.WriteString("\n")
+
+	// Calculate available lines for content
+	// Height - tabs(4) - header(1) - divider(1) - action bar(3) - scroll indicator(2)
+	availableLines := m.height - 11
+
+	// Notes left on the container or image come first
+	if note := m.inspectNote(); note != "" {
+		b.WriteString(yellowStyle.Render(truncateWithEllipsis(" ✎ Note: "+note, m.width-2)))
+		b.WriteString("\n")
+		availableLines--
+	}
+	// Images built by CI carry their source and commit in OCI labels
+	if m.activeTab == 1 && m.selectedImage != nil {
+		if provenance := imageProvenance(*m.selectedImage); provenance != "" {
+			b.WriteString(titleStyle.Render(truncateWithEllipsis(" Built from "+provenance, m.width-2)))
+			b.WriteString("\n")
+			availableLines--
+		}
+	}
+	// Labels tell the project, team or environment a resource belongs to
+	if labels := docker.FormatLabels(m.inspectLabels()); len(labels) > 0 {
+		b.WriteString(titleStyle.Render(fmt.Sprintf(" Labels (%d)", len(labels))))
+		b.WriteString("\n")
+		availableLines--
+		shown := labels
+		if len(labels) > maxInspectLabels {
+			shown = labels[:maxInspectLabels-1]
+		}
+		for _, label := range shown {
+			b.WriteString(helpStyle.Render(truncateWithEllipsis("   "+label, m.width-2)))
+			b.WriteString("\n")
+			availableLines--
+		}
+		if len(shown) < len(labels) {
+			b.WriteString(helpStyle.Render(fmt.Sprintf("   … %d more in the JSON below", len(labels)-len(shown))))
+			b.WriteString("\n")
+			availableLines--
+		}
+	}
+	// Containers get a short security review, so risky settings stand out
+	if m.activeTab == 0 && m.inspectContent != "" {
+		if len(m.inspectSecurity) == 0 {
+			b.WriteString(greenStyle.Render(" Security: not privileged, no host namespaces or Docker socket, not root"))
+			b.WriteString("\n")
+			availableLines--
+		} else {
+			b.WriteString(titleStyle.Render(" Security"))
+			b.WriteString("\n")
+			availableLines--
+			for _, finding := range m.inspectSecurity {
+				b.WriteString(redStyle.Render(truncateWithEllipsis("   ⚠ "+finding, m.width-2)))
+				b.WriteString("\n")
+				availableLines--
+			}
+		}
+	}
+	if m.inspectExportMode {
+		b.WriteString(m.renderInspectExportPrompt())
+		b.WriteString("\n")
+		availableLines--
+	} else if m.inspectExportMsg != "" {
+		b.WriteString(helpStyle.Render(truncateWithEllipsis(" "+m.inspectExportMsg, m.width-2)))
+		b.WriteString("\n")
+		availableLines--
+	}
+	if availableLines < 5 {
+		availableLines = 5
+	}
+
+	// Render content with scrolling
+	if m.inspectContent == "" {
+		b.WriteString(contentStyle.Render(" Loading..."))
+		b.WriteString("\n")
+	} else {
+		lines := strings.Split(m.inspectContent, "\n")
+		totalLines := len(lines)
+
+		m.writeScrolled(&b, lines, m.logsScrollOffset, availableLines, func(_ int, line string) string {
+			return line
+		})
+
+		// Add scroll indicator
+		b.WriteString(m.getInspectScrollIndicator(totalLines, availableLines))
+	}
+
+	return b.String()
+}
+
+// maxInspectLabels bounds the label lines above the inspect JSON, which
+// has them all
+const maxInspectLabels = 8
+
+// inspectLabels returns the labels of the inspected resource
+func (m *Model) inspectLabels() map[string]string {
+	switch {
+	case m.activeTab == 0 && m.selectedContainer != nil:
+		return m.selectedContainer.Labels
+	case m.activeTab == 1 && m.selectedImage != nil:
+		return m.selectedImage.Labels
+	case m.activeTab == 2 && m.selectedVolume != nil:
+		return m.selectedVolume.Labels
+	case m.activeTab == 3 && m.selectedNetwork != nil:
+		return m.selectedNetwork.Labels
+	}
+	return nil
+}
+
+// inspectNote returns the note on the inspected container or image, or ""
+func (m *Model) inspectNote() string {
+	var note notes.Note
+	switch {
+	case m.activeTab == 0 && m.selectedContainer != nil:
+		note, _ = m.notes.Get(notes.Container, m.selectedContainer.ID, m.selectedContainer.Name)
+	case m.activeTab == 1 && m.selectedImage != nil:
+		note, _ = m.notes.Get(notes.Image, m.selectedImage.ID, imageNoteName(*m.selectedImage))
+	}
+	return note.Text
+}
+
+// imageProvenance describes where an image was built from using its OCI
+// labels, e.g. "github.com/org/repo @ 3f2a9c1 on 2024-05-01", or ""
+func imageProvenance(img types.Image) string {
+	if img.Source == "" && img.Revision == "" {
+		return ""
+	}
+
+	var parts []string
+	if img.Source != "" {
+		parts = append(parts, docker.ShortSource(img.Source))
+	}
+	if img.Revision != "" {
+		parts = append(parts, "@ "+docker.ShortRevision(img.Revision))
+	}
+	if img.BuildCreated != "" {
+		created := img.BuildCreated
+		if t, err := time.Parse(time.RFC3339, created); err == nil {
+			created = t.Format("2006-01-02 15:04")
+		}
+		parts = append(parts, "on "+created)
+	}
+	return strings.Join(parts, " ")
+}
+
+// renderWatchView renders the bind-mount watch modal
+func (m *Model) renderWatchView() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	lineStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	contentStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a"))
+
+	// Header
+	headerText := "Watch"
+	if m.selectedContainer != nil {
+		headerText = "Watch: " + m.selectedContainer.Name
+	}
+	headerRight := "[ESC] Back"
+	headerSpacing := strings.Repeat(" ", max(m.width-len(headerText)-len(headerRight)-4, 1))
+	b.WriteString(titleStyle.Render(headerText))
+	b.WriteString(headerSpacing)
+	b.WriteString(helpStyle.Render(headerRight))
+	b.WriteString("\n")
+
+	// Content divider
+	b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
+	b.WriteString("\n")
+
+	if len(m.watchMounts) == 0 {
+		b.WriteString(contentStyle.Render(" Loading..."))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	b.WriteString(contentStyle.Render(" Host paths to watch:"))
+	b.WriteString("\n\n")
+
+	for i, mount := range m.watchMounts {
+		check := "[ ]"
+		if m.watchSelected[i] {
+			check = "[x]"
+		}
+		line := fmt.Sprintf(" %s %s → %s", check, mount.Source, mount.Destination)
+		line = truncateWithEllipsis(line, m.width-2)
+		if i == m.watchCursor {
+			b.WriteString(selectedStyle.Render(line))
+		} else {
+			b.WriteString(contentStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	// On-change action
+	action := "Restart container"
+	if m.watchSendSignal {
+		action = "Send SIGHUP"
+	}
+	b.WriteString("\n")
+	b.WriteString(contentStyle.Render(" On change: "))
+	b.WriteString(selectedStyle.Render(action))
+	b.WriteString("\n\n")
+
+	b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render(" [Space] Toggle path  [Tab] Restart/SIGHUP  [Enter] Start watching"))
+
+	return b.String()
+}
+
+// renderBulkEnvView renders the bulk env editor and its per-container preview
+func (m *Model) renderBulkEnvView() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	lineStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	contentStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a"))
+
+	inputStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#00FF00")).
+		Background(theme.Color("#0a0a0a"))
+
+	errorStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FF0000")).
+		Background(theme.Color("#0a0a0a"))
+
+	// Header
+	headerText := fmt.Sprintf("Set env on %d container(s)", len(m.bulkEnvTargets))
+	headerRight := "[ESC] Back"
+	headerSpacing := strings.Repeat(" ", max(m.width-len(headerText)-len(headerRight)-4, 1))
+	b.WriteString(titleStyle.Render(headerText))
+	b.WriteString(headerSpacing)
+	b.WriteString(helpStyle.Render(headerRight))
+	b.WriteString("\n")
+
+	// Content divider
+	b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
+	b.WriteString("\n")
+
+	// Changes collected so far (values hidden, they are often secrets)
+	b.WriteString(contentStyle.Render(" Variables to set:"))
+	b.WriteString("\n")
+	if len(m.bulkEnvChanges) == 0 {
+		b.WriteString(contentStyle.Render("   (none yet)"))
+		b.WriteString("\n")
+	}
+	for _, change := range m.bulkEnvChanges {
+		b.WriteString(selectedStyle.Render(fmt.Sprintf("   %s=%s", change.Key, strings.Repeat("•", min(len(change.Value), 8)))))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	switch {
+	case m.bulkEnvPreview != nil:
+		b.WriteString(contentStyle.Render(" Preview (containers are stopped, removed and recreated):"))
+		b.WriteString("\n")
+		for _, p := range m.bulkEnvPreview {
+			switch {
+			case p.Err != nil:
+				b.WriteString(errorStyle.Render(truncateWithEllipsis(fmt.Sprintf("   %s: %s", p.ContainerName, p.Err), m.width-2)))
+			case len(p.Changes) == 0:
+				b.WriteString(contentStyle.Render(fmt.Sprintf("   %s: no changes, skipped", p.ContainerName)))
+			default:
+				b.WriteString(selectedStyle.Render(truncateWithEllipsis(fmt.Sprintf("   %s: %s", p.ContainerName, strings.Join(p.Changes, ", ")), m.width-2)))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+		b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render(" [Enter] Recreate  [Esc] Edit changes   + added  ~ changed"))
+
+	case m.bulkEnvLoading:
+		b.WriteString(contentStyle.Render(" Loading preview..."))
+		b.WriteString("\n")
+
+	default:
+		b.WriteString(contentStyle.Render(" KEY=VALUE: "))
+		b.WriteString(inputStyle.Render(m.bulkEnvInput + "█"))
+		b.WriteString("\n\n")
+		b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render(" [Enter] Add variable, or preview when empty  [Backspace] Remove last"))
+	}
+
+	return b.String()
+}
+
+// renderLabelEditor renders the labels of the inspected container with
+// their pending changes, and below them the KEY=VALUE input or the
+// confirmation to recreate the container
+func (m *Model) renderLabelEditor() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	lineStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	inputStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#00FF00")).
+		Background(theme.Color("#0a0a0a"))
+
+	// Header
+	headerText := "Labels: " + m.selectedContainer.Name
+	headerRight := "[A]dd  [E]dit  [D]elete  [S]ave  [ESC] Back"
+	headerSpacing := strings.Repeat(" ", max(m.width-len(headerText)-len(headerRight)-4, 1))
+	b.WriteString(titleStyle.Render(headerText))
+	b.WriteString(headerSpacing)
+	b.WriteString(helpStyle.Render(headerRight))
+	b.WriteString("\n")
+
+	// Content divider
+	b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
+	b.WriteString("\n")
+
+	// Keys take what they need up to half of the width, values the rest
+	keys := m.labelEditKeys()
+	keyWidth := 3
+	for _, key := range keys {
+		keyWidth = max(keyWidth, len(key))
+	}
+	keyWidth = min(keyWidth, (m.width-4)/2)
+	headers := []components.TableHeader{
+		{Label: "", Width: 1}, // Change: + added, ~ changed, - removed
+		{Label: "KEY", Width: keyWidth},
+		{Label: "VALUE", Width: max(m.width-4-1-keyWidth-4, 10)},
+	}
+
+	visible := max(m.height-10, 5)
+	m.labelEditCursor = min(m.labelEditCursor, max(len(keys)-1, 0))
+	start := max(m.labelEditCursor-visible+1, 0)
+	end := min(start+visible, len(keys))
+	var rows []components.TableRow
+	for i := start; i < end; i++ {
+		key := keys[i]
+		before, existed := m.labelEditBefore[key]
+		value, kept := m.labelEditAfter[key]
+		row := components.TableRow{IsSelected: i == m.labelEditCursor}
+		change := ""
+		switch {
+		case !kept:
+			change, value = "-", before
+			row.Style = redStyle
+		case !existed:
+			change = "+"
+			row.Style = greenStyle
+		case value != before:
+			change = "~"
+			row.Style = yellowStyle
+		}
+		row.Cells = []string{
+			change,
+			truncateWithEllipsis(key, headers[1].Width),
+			truncateWithEllipsis(value, headers[2].Width),
+		}
+		rows = append(rows, row)
+	}
+
+	table := components.NewTableComponent(headers).
+		WithWidth(m.width).
+		SetRows(rows).
+		SetEmptyMessage("The container has no labels, [A] adds one").
+		SetVisibleRange(0, len(rows))
+	b.WriteString(table.View())
+	b.WriteString("\n")
+
+	b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
+	b.WriteString("\n")
+	switch {
+	case m.labelEditMode:
+		b.WriteString(helpStyle.Render(" KEY=VALUE: "))
+		b.WriteString(inputStyle.Render(m.labelEditInput + "█"))
+		b.WriteString("\n")
+		if m.labelEditErr != "" {
+			b.WriteString(redStyle.Render(" " + m.labelEditErr))
+			b.WriteString("\n")
+		}
+		b.WriteString(helpStyle.Render(" [Enter] Set label  [Esc] Cancel"))
+	case m.labelEditConfirm:
+		changes := docker.LabelChanges(m.labelEditBefore, m.labelEditAfter)
+		b.WriteString(titleStyle.Render(truncateWithEllipsis(fmt.Sprintf(" Recreate %s with %d label change(s)? It is replaced by a new one with the same configuration.", m.selectedContainer.Name, len(changes)), m.width-2)))
+		b.WriteString("\n")
+		if docker.TouchesComposeLabels(m.labelEditBefore, m.labelEditAfter) {
+			b.WriteString(yellowStyle.Render(" Compose labels change: Compose will no longer see it as part of its project"))
+			b.WriteString("\n")
+		}
+		b.WriteString(helpStyle.Render(" [Enter] Recreate  [Esc] Keep editing"))
+	case m.labelEditErr != "":
+		b.WriteString(redStyle.Render(" " + m.labelEditErr))
+	default:
+		b.WriteString(helpStyle.Render(" Labels the image sets come back on the new container even when deleted"))
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// renderTasksView renders the background task queue, oldest first, with
+// each task's state, latest progress or outcome, and how long it ran
+func (m *Model) renderTasksView() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	lineStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	contentStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a"))
+
+	tasksNow := m.taskQueue.Tasks()
+
+	// Header
+	headerText := fmt.Sprintf("Tasks (%d active)", m.taskQueue.Active())
+	headerRight := "[X] Cancel  [C]lear finished  [ESC] Back"
+	headerSpacing := strings.Repeat(" ", max(m.width-len(headerText)-len(headerRight)-4, 1))
+	b.WriteString(titleStyle.Render(headerText))
+	b.WriteString(headerSpacing)
+	b.WriteString(helpStyle.Render(headerRight))
+	b.WriteString("\n")
+
+	// Content divider
+	b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
+	b.WriteString("\n")
+
+	if len(tasksNow) == 0 {
+		b.WriteString(contentStyle.Render(" No tasks. Pulls, volume copies and captures run here."))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	now := time.Now()
+	for i, task := range tasksNow {
+		icon, detail := "○", "waiting"
+		switch task.State {
+		case tasks.Running:
+			icon, detail = yellowStyle.Render("●"), task.Progress
+		case tasks.Done:
+			icon, detail = greenStyle.Render("✓"), task.Result
+		case tasks.Failed:
+			icon, detail = redStyle.Render("✗"), task.Err.Error()
+		case tasks.Cancelled:
+			icon, detail = "–", "cancelled"
+		}
+
+		elapsed := ""
+		if !task.Started.IsZero() {
+			end := task.Finished
+			if end.IsZero() {
+				end = now
+			}
+			elapsed = end.Sub(task.Started).Round(time.Second).String()
+		}
+
+		style, cursor := contentStyle, "  "
+		if i == m.taskCursor {
+			style, cursor = selectedStyle, "> "
+		}
+		line := truncateWithEllipsis(fmt.Sprintf("%-32s %-8s %s", truncateWithEllipsis(task.Name, 32), elapsed, detail), m.width-6)
+		b.WriteString(style.Render(cursor) + icon + style.Render(" "+line))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// renderSchedulesView renders the pending schedules, soonest first, and the
+// prompt for a new one
+func (m *Model) renderSchedulesView() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	lineStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	contentStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a"))
+
+	pending := m.schedules.Pending()
+
+	// Header
+	headerText := fmt.Sprintf("Schedules (%d pending)", len(pending))
+	headerRight := "[X] Cancel  [ESC] Back"
+	if m.scheduleTarget.ID != "" {
+		headerRight = "[N]ew for " + m.scheduleTarget.Name + "  " + headerRight
+	}
+	headerSpacing := strings.Repeat(" ", max(m.width-len(headerText)-len(headerRight)-4, 1))
+	b.WriteString(titleStyle.Render(headerText))
+	b.WriteString(headerSpacing)
+	b.WriteString(helpStyle.Render(headerRight))
+	b.WriteString("\n")
+
+	// Content divider
+	b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
+	b.WriteString("\n")
+
+	if len(pending) == 0 {
+		b.WriteString(contentStyle.Render(" No schedules. Select a container and press @ then N, e.g. \"stop in 2h\"."))
+		b.WriteString("\n")
+	}
+
+	now := time.Now()
+	for i, entry := range pending {
+		style, cursor := contentStyle, "  "
+		if i == m.scheduleCursor {
+			style, cursor = selectedStyle, "> "
+		}
+		left := entry.At.Sub(now).Round(time.Second)
+		line := truncateWithEllipsis(fmt.Sprintf("%-40s in %s", entry.String(), left), m.width-6)
+		b.WriteString(style.Render(cursor + line))
+		b.WriteString("\n")
+	}
+
+	if m.schedulePromptMode {
+		b.WriteString("\n")
+		b.WriteString(titleStyle.Render(" " + m.scheduleTarget.Name + ": "))
+		b.WriteString(selectedStyle.Render(m.scheduleInput + "█"))
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render(" e.g. stop in 2h, restart 45m, start at 18:30  [ENTER] Add  [ESC] Cancel"))
+		b.WriteString("\n")
+		if m.scheduleErr != "" {
+			b.WriteString(redStyle.Render(" " + m.scheduleErr))
+			b.WriteString("\n")
+		}
+	}
+
+	return b.String()
+}
+
+// renderForwardsView renders the Forwards panel: the localhost ports
+// proxied to containers with their connections, and the prompt of a new one
+func (m *Model) renderForwardsView() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	lineStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	contentStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a"))
+
+	list := m.forwards.List()
+
+	// Header
+	headerText := fmt.Sprintf("Port forwards (%d)", len(list))
+	headerRight := "[X] Stop  [ESC] Back"
+	if m.forwardTarget.ID != "" {
+		headerRight = "[N]ew for " + m.forwardTarget.Name + "  " + headerRight
+	}
+	headerSpacing := strings.Repeat(" ", max(m.width-len(headerText)-len(headerRight)-4, 1))
+	b.WriteString(titleStyle.Render(headerText))
+	b.WriteString(headerSpacing)
+	b.WriteString(helpStyle.Render(headerRight))
+	b.WriteString("\n")
+
+	// Content divider
+	b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
+	b.WriteString("\n")
+
+	if len(list) == 0 {
+		b.WriteString(contentStyle.Render(" No forwards. Select a running container and press Ctrl+F then N, e.g. \"8080:80\"."))
+		b.WriteString("\n")
+	}
+
+	now := time.Now()
+	for i, f := range list {
+		style, cursor := contentStyle, "  "
+		if i == m.forwardCursor {
+			style, cursor = selectedStyle, "> "
+		}
+		line := fmt.Sprintf("%-40s via %-21s %d open, %d total  up %s",
+			f.String(), f.Target, f.Open, f.Total, now.Sub(f.Started).Round(time.Second))
+		b.WriteString(style.Render(cursor + truncateWithEllipsis(line, m.width-6)))
+		b.WriteString("\n")
+	}
+
+	if m.forwardPromptMode {
+		b.WriteString("\n")
+		b.WriteString(titleStyle.Render(" Forward to " + m.forwardTarget.Name + ": "))
+		b.WriteString(selectedStyle.Render(m.forwardInput + "█"))
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render(" local:container port, e.g. 8080:80, or just 80 for a free local port  [ENTER] Start  [ESC] Cancel"))
+		b.WriteString("\n")
+	}
+	if m.forwardErr != "" {
+		b.WriteString(redStyle.Render(" " + m.forwardErr))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// renderHealthView renders the healthcheck of the inspected container and
+// its last probes, newest first, with their exit codes and output
+func (m *Model) renderHealthView() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	lineStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	log := m.healthLog
+
+	// Header
+	headerText := "Health: " + m.selectedContainer.Name
+	if log.Status != "" {
+		headerText += " " + log.Status
+		if log.FailingStreak > 0 {
+			headerText += fmt.Sprintf(" (%d failing in a row)", log.FailingStreak)
+		}
+	}
+	headerRight := "[R]efresh  [ESC] Back"
+	headerSpacing := strings.Repeat(" ", max(m.width-len(headerText)-len(headerRight)-4, 1))
+	b.WriteString(titleStyle.Render(headerText))
+	b.WriteString(headerSpacing)
+	b.WriteString(helpStyle.Render(headerRight))
+	b.WriteString("\n")
+
+	// Content divider
+	b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
+	b.WriteString("\n")
+
+	switch {
+	case !m.healthLoaded:
+		b.WriteString(helpStyle.Render(" Loading healthcheck..."))
+		b.WriteString("\n")
+		return b.String()
+	case m.healthErr != "":
+		b.WriteString(redStyle.Render(" " + m.healthErr))
+		b.WriteString("\n")
+		return b.String()
+	case log.Test == "" && log.Status == "":
+		b.WriteString(helpStyle.Render(" No healthcheck. Add one with HEALTHCHECK in the Dockerfile or healthcheck: in compose."))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	// The check, then each probe's result line and indented output
+	lines := []string{helpStyle.Render(truncateWithEllipsis(fmt.Sprintf(" Check: %s  every %s", log.Test, formatInterval(log.Interval)), m.width-2)), ""}
+	if len(log.Probes) == 0 {
+		lines = append(lines, helpStyle.Render(" No probe has run yet"))
+	}
+	for _, probe := range log.Probes {
+		result := fmt.Sprintf(" %s  exit %d  %s", probe.Start.Format("15:04:05"), probe.ExitCode, probe.Duration.Round(time.Millisecond))
+		if probe.ExitCode == 0 {
+			lines = append(lines, greenStyle.Render(result))
+		} else {
+			lines = append(lines, redStyle.Render(result))
+		}
+		for _, line := range strings.Split(probe.Output, "\n") {
+			if line != "" {
+				lines = append(lines, truncateWithEllipsis("   "+line, m.width-2))
+			}
+		}
+	}
+
+	visible := max(m.height-4, 5)
+	m.healthScroll = min(m.healthScroll, max(len(lines)-visible, 0))
+	end := min(m.healthScroll+visible, len(lines))
+	for _, line := range lines[m.healthScroll:end] {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// envMask stands for a masked value; it has a fixed length so that it
+// doesn't tell how long the secret is
+const envMask = "••••••••"
+
+// renderEnvView renders the environment of the inspected container as a
+// table, values of secret looking variables masked
+func (m *Model) renderEnvView() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	lineStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	// Header
+	headerText := "Env: " + m.selectedContainer.Name
+	if m.envLoaded && m.envErr == "" {
+		headerText += fmt.Sprintf(" (%d variables)", len(m.envVars))
+	}
+	headerRight := "[R]eveal  Shift+[R] All  [C]opy  [ESC] Back"
+	headerSpacing := strings.Repeat(" ", max(m.width-len(headerText)-len(headerRight)-4, 1))
+	b.WriteString(titleStyle.Render(headerText))
+	b.WriteString(headerSpacing)
+	b.WriteString(helpStyle.Render(headerRight))
+	b.WriteString("\n")
+
+	// Content divider
+	b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
+	b.WriteString("\n")
+
+	switch {
+	case !m.envLoaded:
+		b.WriteString(helpStyle.Render(" Loading environment..."))
+		b.WriteString("\n")
+		return b.String()
+	case m.envErr != "":
+		b.WriteString(redStyle.Render(" " + m.envErr))
+		b.WriteString("\n")
+		return b.String()
+	}
+	if m.envMsg != "" {
+		b.WriteString(greenStyle.Render(truncateWithEllipsis(" "+m.envMsg, m.width-2)))
+		b.WriteString("\n")
+	}
+
+	// Keys take what they need up to a third of the width, values the rest
+	keyWidth := 3
+	for _, v := range m.envVars {
+		keyWidth = max(keyWidth, len(v.Key))
+	}
+	keyWidth = min(keyWidth, (m.width-4)/3)
+	headers := []components.TableHeader{
+		{Label: "", Width: 1}, // Cursor
+		{Label: "KEY", Width: keyWidth},
+		{Label: "VALUE", Width: max(m.width-4-1-keyWidth-4, 10)},
+	}
+
+	visible := max(m.height-6, 5)
+	m.envCursor = min(m.envCursor, max(len(m.envVars)-1, 0))
+	start := max(m.envCursor-visible+1, 0)
+	end := min(start+visible, len(m.envVars))
+	var rows []components.TableRow
+	for i := start; i < end; i++ {
+		v := m.envVars[i]
+		value := v.Value
+		if m.envMasked(v) {
+			value = envMask
+		}
+		cursor := ""
+		if i == m.envCursor {
+			cursor = ">"
+		}
+		rows = append(rows, components.TableRow{
+			Cells: []string{
+				cursor,
+				truncateWithEllipsis(v.Key, headers[1].Width),
+				truncateWithEllipsis(value, headers[2].Width),
+			},
+			IsSelected: i == m.envCursor,
+		})
+	}
+
+	table := components.NewTableComponent(headers).
+		WithWidth(m.width).
+		SetRows(rows).
+		SetEmptyMessage("The container sets no environment variables").
+		SetVisibleRange(0, len(rows))
+	b.WriteString(table.View())
+
+	return b.String()
+}
+
+// renderProvisionView renders the provisioning report: each running
+// container's limits against its peak usage, worst verdict first, with the
+// findings of the selected container below
+func (m *Model) renderProvisionView() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	lineStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	// Header
+	headerText := "Provisioning"
+	if m.provisionLoaded && m.provisionErr == "" {
+		headerText += fmt.Sprintf(" (%d running containers)", len(m.provisionRows))
+	}
+	headerRight := "[E]xport CSV  [R]efresh  [ESC] Back"
+	headerSpacing := strings.Repeat(" ", max(m.width-len(headerText)-len(headerRight)-4, 1))
+	b.WriteString(titleStyle.Render(headerText))
+	b.WriteString(headerSpacing)
+	b.WriteString(helpStyle.Render(headerRight))
+	b.WriteString("\n")
+
+	// Content divider
+	b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
+	b.WriteString("\n")
+
+	switch {
+	case !m.provisionLoaded:
+		b.WriteString(helpStyle.Render(" Reading container limits..."))
+		b.WriteString("\n")
+		return b.String()
+	case m.provisionErr != "":
+		b.WriteString(redStyle.Render(" " + m.provisionErr))
+		b.WriteString("\n")
+		return b.String()
+	}
+	if m.provisionMsg != "" {
+		style := greenStyle
+		if strings.HasPrefix(m.provisionMsg, "ERROR: ") {
+			style = redStyle
+		}
+		b.WriteString(style.Render(truncateWithEllipsis(" "+m.provisionMsg, m.width-2)))
+		b.WriteString("\n")
+	}
+
+	headers := []components.TableHeader{
+		{Label: "", Width: 1}, // Cursor
+		{Label: "CONTAINER", Width: max(m.width-4-1-69-7*2, 12)}, // What the fixed columns and gaps leave
+		{Label: "CPU LIMIT", Width: 10},
+		{Label: "PEAK CPU", Width: 10},
+		{Label: "MEM LIMIT", Width: 10},
+		{Label: "RESERVED", Width: 12},
+		{Label: "PEAK MEM", Width: 10},
+		{Label: "VERDICT", Width: 17},
+	}
+
+	// The findings of the selected container take up to three lines below
+	visible := max(m.height-10, 5)
+	m.provisionCursor = min(m.provisionCursor, max(len(m.provisionRows)-1, 0))
+	start := max(m.provisionCursor-visible+1, 0)
+	end := min(start+visible, len(m.provisionRows))
+	var rows []components.TableRow
+	for i := start; i < end; i++ {
+		row := m.provisionRows[i]
+		cursor := ""
+		if i == m.provisionCursor {
+			cursor = ">"
+		}
+		tableRow := components.TableRow{
+			Cells: []string{
+				cursor,
+				truncateWithEllipsis(row.Name, headers[1].Width),
+				formatCPUs(row.Allocation.CPUs),
+				formatCPUs(row.Peak.CPUPercent / 100),
+				formatAllocBytes(row.Allocation.MemoryLimit),
+				formatAllocBytes(row.Allocation.MemoryReservation),
+				formatAllocBytes(int64(row.Peak.MemBytes)),
+				row.Verdict.String(),
+			},
+			IsSelected: i == m.provisionCursor,
+		}
+		switch row.Verdict {
+		case provision.Under:
+			tableRow.Style = redStyle
+		case provision.Over:
+			tableRow.Style = yellowStyle
+		}
+		rows = append(rows, tableRow)
+	}
+
+	table := components.NewTableComponent(headers).
+		WithWidth(m.width).
+		SetRows(rows).
+		SetEmptyMessage("No running containers").
+		SetVisibleRange(0, len(rows))
+	b.WriteString(table.View())
+
+	if m.provisionCursor < len(m.provisionRows) {
+		b.WriteString("\n")
+		for _, finding := range m.provisionRows[m.provisionCursor].Findings {
+			b.WriteString(helpStyle.Render(truncateWithEllipsis(" • "+finding, m.width-2)))
+			b.WriteString("\n")
+		}
+	}
+
+	return b.String()
+}
+
+// formatCPUs formats a number of CPUs for the provisioning report, "-" for
+// none
+func formatCPUs(cpus float64) string {
+	if cpus == 0 {
+		return "-"
+	}
+	return strconv.FormatFloat(cpus, 'f', 2, 64)
+}
+
+// formatAllocBytes formats a memory size for the provisioning report, "-"
+// for none
+func formatAllocBytes(n int64) string {
+	if n == 0 {
+		return "-"
+	}
+	return units.BytesSize(float64(n))
+}
+
+// formatInterval formats a healthcheck interval, 30s when unset as Docker
+// defaults to
+func formatInterval(d time.Duration) string {
+	if d == 0 {
+		d = 30 * time.Second
+	}
+	return d.String()
+}
+
+// renderExecOutputView renders the output and exit code of the last
+// one-shot exec
+func (m *Model) renderExecOutputView() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	lineStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	// Header
+	headerText := truncateWithEllipsis(m.execContainer.Name+" $ "+m.execResult.Command, max(m.width/2, 20))
+	headerRight := "[R]erun  [!] New command  [ESC] Back"
+	headerSpacing := strings.Repeat(" ", max(m.width-len(headerText)-len(headerRight)-4, 1))
+	b.WriteString(titleStyle.Render(headerText))
+	b.WriteString(headerSpacing)
+	b.WriteString(helpStyle.Render(headerRight))
+	b.WriteString("\n")
+
+	// Content divider
+	b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
+	b.WriteString("\n")
+
+	switch {
+	case m.execRunning:
+		b.WriteString(helpStyle.Render(" Running..."))
+		b.WriteString("\n")
+		return b.String()
+	case m.execResult.Err != nil:
+		b.WriteString(redStyle.Render(" " + m.execResult.Err.Error()))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	lines := strings.Split(strings.TrimSuffix(m.execResult.Output, "\n"), "\n")
+	end := min(m.execScroll+m.execVisibleLines(), len(lines))
+	for _, line := range lines[min(m.execScroll, end):end] {
+		b.WriteString(truncateWithEllipsis(line, m.width-2))
+		b.WriteString("\n")
+	}
+
+	exit := fmt.Sprintf("exit %d", m.execResult.ExitCode)
+	if m.execResult.ExitCode == 0 {
+		b.WriteString(greenStyle.Render(exit))
+	} else {
+		b.WriteString(redStyle.Render(exit))
+	}
+	if len(lines) > m.execVisibleLines() {
+		b.WriteString(helpStyle.Render(fmt.Sprintf("  lines %d-%d of %d", m.execScroll+1, end, len(lines))))
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// renderEventsView renders the daemon's events as they stream in, under
+// the filter, the since time and the saved watches
+func (m *Model) renderEventsView() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	lineStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	inputStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#00FF00")).
+		Background(theme.Color("#0a0a0a"))
+
+	// Header
+	headerText := fmt.Sprintf("Events (%d)", len(m.events))
+	if m.eventsWatch != "" {
+		headerText = fmt.Sprintf("Events: %s (%d)", m.eventsWatch, len(m.events))
+	}
+	if m.eventsFollow {
+		headerText += " (following)"
+	}
+	headerRight := "[F]ilter  [S]ince  [N] Save  [C]lear  [ESC] Back"
+	headerSpacing := strings.Repeat(" ", max(m.width-len(headerText)-len(headerRight)-4, 1))
+	b.WriteString(titleStyle.Render(headerText))
+	b.WriteString(headerSpacing)
+	b.WriteString(helpStyle.Render(headerRight))
+	b.WriteString("\n")
+
+	// Filter and since time, the prompt editing one of them, or an error
+	switch m.eventsPrompt {
+	case eventsPromptFilter:
+		b.WriteString(titleStyle.Render(" Filter: "))
+		b.WriteString(inputStyle.Render(m.eventsInput + "█"))
+		b.WriteString(helpStyle.Render("  e.g. type=container event=die label=com.docker.compose.project=shop"))
+	case eventsPromptSince:
+		b.WriteString(titleStyle.Render(" Replay since: "))
+		b.WriteString(inputStyle.Render(m.eventsInput + "█"))
+		b.WriteString(helpStyle.Render("  e.g. 10m, 14:30, 2024-05-01 14:30; empty for new events only"))
+	case eventsPromptSave:
+		b.WriteString(titleStyle.Render(" Save filter as: "))
+		b.WriteString(inputStyle.Render(m.eventsInput + "█"))
+	default:
+		filter, since := m.eventsFilter, "now"
+		if filter == "" {
+			filter = "all events"
+		}
+		if !m.eventsSince.IsZero() {
+			since = m.eventsSince.Format("2006-01-02 15:04:05")
+		}
+		line := " " + filter + "  since " + since
+		var watches []string
+		for i, w := range m.eventWatches.List() {
+			if i == 9 {
+				break
+			}
+			watches = append(watches, fmt.Sprintf("%d %s", i+1, w.Name))
+		}
+		if len(watches) > 0 {
+			line += "  │  Watches: " + strings.Join(watches, "  ")
+			if m.eventsWatch != "" {
+				line += "  [X] Delete " + m.eventsWatch
+			}
+		}
+		b.WriteString(helpStyle.Render(truncateWithEllipsis(line, m.width-2)))
+	}
+	if m.eventsErr != "" {
+		b.WriteString(redStyle.Render("  " + m.eventsErr))
+	}
+	b.WriteString("\n")
+
+	// Content divider
+	b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
+	b.WriteString("\n")
+
+	if len(m.events) == 0 {
+		status := " Waiting for events..."
+		if !m.eventsFollow {
+			status = " No events"
+		}
+		b.WriteString(helpStyle.Render(status))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	today := time.Now().Format("2006-01-02")
+	end := min(m.eventsScroll+m.eventsVisibleLines(), len(m.events))
+	for _, event := range m.events[min(m.eventsScroll, end):end] {
+		stamp := event.Time.Format("15:04:05")
+		if event.Time.Format("2006-01-02") != today {
+			stamp = event.Time.Format("Jan 02 15:04:05")
+		}
+		line := fmt.Sprintf("%-15s %-9s %-12s %s  %s", stamp, event.Type, event.Action, event.Actor, event.Attrs)
+		line = truncateWithEllipsis(line, m.width-2)
+		switch {
+		case strings.HasPrefix(event.Action, "die"), strings.HasPrefix(event.Action, "kill"),
+			strings.HasPrefix(event.Action, "oom"), strings.HasPrefix(event.Action, "destroy"):
+			b.WriteString(redStyle.Render(line))
+		case event.Action == "start", event.Action == "create", event.Action == "pull":
+			b.WriteString(greenStyle.Render(line))
+		default:
+			b.WriteString(line)
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// renderWorkspacesView renders the saved workspaces, numbered for direct
+// switching, and the prompt naming the list view to save
+func (m *Model) renderWorkspacesView() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	lineStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	contentStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a"))
+
+	list := m.workspaces.List()
+
+	// Header
+	headerText := fmt.Sprintf("Workspaces (%d)", len(list))
+	headerRight := "[ENTER] Switch  [N]ew  [X] Delete  [ESC] Back"
+	headerSpacing := strings.Repeat(" ", max(m.width-len(headerText)-len(headerRight)-4, 1))
+	b.WriteString(titleStyle.Render(headerText))
+	b.WriteString(headerSpacing)
+	b.WriteString(helpStyle.Render(headerRight))
+	b.WriteString("\n")
+
+	// Content divider
+	b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
+	b.WriteString("\n")
+
+	if len(list) == 0 {
+		b.WriteString(contentStyle.Render(" No workspaces. Set up a tab's filter and sort, then press N to save it, e.g. as \"databases\"."))
+		b.WriteString("\n")
+	}
+
+	for i, w := range list {
+		style, cursor := contentStyle, "  "
+		if i == m.workspaceCursor {
+			style, cursor = selectedStyle, "> "
+		}
+		number := " "
+		if i < 9 {
+			number = strconv.Itoa(i + 1)
+		}
+		line := truncateWithEllipsis(fmt.Sprintf("%s  %-20s %s", number, w.Name, m.workspaceSummary(w)), m.width-6)
+		b.WriteString(style.Render(cursor + line))
+		b.WriteString("\n")
+	}
+
+	if m.workspacePromptMode {
+		b.WriteString("\n")
+		b.WriteString(titleStyle.Render(" Save as: "))
+		b.WriteString(selectedStyle.Render(m.workspaceInput + "█"))
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render(" " + m.workspaceSummary(m.currentWorkspace()) + "  [ENTER] Save  [ESC] Cancel"))
+		b.WriteString("\n")
+		if m.workspaceErr != "" {
+			b.WriteString(redStyle.Render(" " + m.workspaceErr))
+			b.WriteString("\n")
+		}
+	}
+
+	return b.String()
+}
+
+// workspaceSummary describes the tab, filter and sort of a workspace
+func (m *Model) workspaceSummary(w workspace.Workspace) string {
+	parts := []string{[]string{"Containers", "Images", "Volumes", "Networks"}[min(max(w.Tab, 0), 3)]}
+	switch w.Tab {
+	case 0:
+		if w.ContainerImage != "" {
+			parts = append(parts, "image "+w.ContainerImage)
+		}
+		switch w.ContainerSort {
+		case types.ContainerSortCPU:
+			parts = append(parts, "by CPU")
+		case types.ContainerSortMem:
+			parts = append(parts, "by memory")
+		}
+		if w.GroupReplicas {
+			parts = append(parts, "replicas grouped")
+		}
+		if w.LogPreview {
+			parts = append(parts, "log preview")
+		}
+	case 1:
+		switch {
+		case w.ImageFilter == types.ImageFilterInUse:
+			parts = append(parts, "in use")
+		case w.ImageFilter == types.ImageFilterUnused:
+			parts = append(parts, "unused")
+		case w.ImageFilter == types.ImageFilterDangling:
+			parts = append(parts, "dangling")
+		case w.ImageFilter >= types.ImageFilterStale && w.ImageFilter < types.ImageFilterStale+len(m.staleDays):
+			parts = append(parts, fmt.Sprintf("unused for %d+ days", m.staleDays[w.ImageFilter-types.ImageFilterStale]))
+		}
+		if w.ImageSource {
+			parts = append(parts, "source")
+		}
+		if w.ImageDigest {
+			parts = append(parts, "digest")
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// renderMessagesView renders the status messages of the session, newest
+// first, wrapped so that long errors can be read in full
+func (m *Model) renderMessagesView() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	lineStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	contentStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	// Header
+	headerText := fmt.Sprintf("Messages (%d)", m.messages.Len())
+	headerRight := "[C]lear  [ESC] Back"
+	headerSpacing := strings.Repeat(" ", max(m.width-len(headerText)-len(headerRight)-4, 1))
+	b.WriteString(titleStyle.Render(headerText))
+	b.WriteString(headerSpacing)
+	b.WriteString(helpStyle.Render(headerRight))
+	b.WriteString("\n")
+
+	// Content divider
+	b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
+	b.WriteString("\n")
+
+	entries := m.messages.Entries()
+	if len(entries) == 0 {
+		b.WriteString(contentStyle.Render(" No messages yet. Action results and errors are kept here."))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	// Height - header(1) - divider(1) - margin(2)
+	availableLines := max(m.height-4, 5)
+	wrapStyle := lipgloss.NewStyle().Width(max(m.width-13, 20))
+	for _, entry := range entries[min(m.messagesScroll, len(entries)-1):] {
+		style := contentStyle
+		if entry.Error {
+			style = redStyle
+		}
+		for i, line := range strings.Split(wrapStyle.Render(entry.Text), "\n") {
+			if availableLines == 0 {
+				return b.String()
+			}
+			stamp := "        "
+			if i == 0 {
+				stamp = entry.Time.Format("15:04:05")
+			}
+			b.WriteString(helpStyle.Render(" "+stamp+"  ") + style.Render(strings.TrimRight(line, " ")))
+			b.WriteString("\n")
+			availableLines--
+		}
+	}
+
+	return b.String()
+}
+
+// renderRelatedView lists the containers sharing a volume or a network with
+// the selected one, i.e. what stopping or removing it may break
+func (m *Model) renderRelatedView() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	lineStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	contentStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	nameStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a"))
+
+	related := docker.RelatedContainers(m.allContainers, m.relatedTo.ID)
+
+	// Header
+	headerText := fmt.Sprintf("Related to %s (%d)", m.relatedTo.Name, len(related))
+	headerRight := "[ESC] Back"
+	headerSpacing := strings.Repeat(" ", max(m.width-len(headerText)-len(headerRight)-4, 1))
+	b.WriteString(titleStyle.Render(headerText))
+	b.WriteString(headerSpacing)
+	b.WriteString(helpStyle.Render(headerRight))
+	b.WriteString("\n")
+
+	// Content divider
+	b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
+	b.WriteString("\n")
+
+	// What the container itself uses
+	volumes, networks := "none", "none"
+	if len(m.relatedTo.Volumes) > 0 {
+		volumes = strings.Join(m.relatedTo.Volumes, ", ")
+	}
+	if len(m.relatedTo.Networks) > 0 {
+		networks = strings.Join(m.relatedTo.Networks, ", ")
+	}
+	b.WriteString(contentStyle.Render(truncateWithEllipsis(" Volumes:  "+volumes, m.width-2)))
+	b.WriteString("\n")
+	b.WriteString(contentStyle.Render(truncateWithEllipsis(" Networks: "+networks, m.width-2)))
+	b.WriteString("\n\n")
+
+	if len(related) == 0 {
+		b.WriteString(contentStyle.Render(" No other container shares its volumes or networks (default networks aside)."))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	// Height - header(1) - divider(1) - summary(3) - margin(2); two lines each
+	visible := max((m.height-7)/2, 2)
+	for _, rel := range related[min(m.relatedScroll, len(related)-1):] {
+		if visible == 0 {
+			break
+		}
+		b.WriteString(" " + m.getStatusDot(rel.Container.Status) + " " + nameStyle.Render(rel.Container.Name))
+		b.WriteString(helpStyle.Render("  " + strings.ToLower(rel.Container.Status)))
+		b.WriteString("\n")
+
+		var shared []string
+		if len(rel.Volumes) > 0 {
+			shared = append(shared, "volumes: "+strings.Join(rel.Volumes, ", "))
+		}
+		if len(rel.Networks) > 0 {
+			shared = append(shared, "networks: "+strings.Join(rel.Networks, ", "))
+		}
+		b.WriteString(contentStyle.Render(truncateWithEllipsis("     shares "+strings.Join(shared, "; "), m.width-2)))
+		b.WriteString("\n")
+		visible--
+	}
+
+	return b.String()
+}
+
+// renderTagCleanupView previews a tag cleanup: the tags kept, newest first,
+// then the ones Enter deletes
+func (m *Model) renderTagCleanupView() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	lineStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	plan := m.tagCleanupPlan
+	var size int64
+	for _, img := range plan.Delete {
+		size += img.SizeBytes
+	}
+
+	// Header
+	headerText := fmt.Sprintf("Clean up %s: delete %d of %d tags (up to %s)",
+		plan.Repository, len(plan.Delete), len(plan.Keep)+len(plan.InUse)+len(plan.Delete), units.HumanSize(float64(size)))
+	headerRight := "[Enter] Delete  [ESC] Cancel"
+	headerSpacing := strings.Repeat(" ", max(m.width-len(headerText)-len(headerRight)-4, 1))
+	b.WriteString(titleStyle.Render(headerText))
+	b.WriteString(headerSpacing)
+	b.WriteString(helpStyle.Render(headerRight))
+	b.WriteString("\n")
+
+	// Content divider
+	b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
+	b.WriteString("\n")
+
+	// One line per tag: kept ones first, the deleted ones last
+	var lines []string
+	row := func(mark, note string, img types.Image, style lipgloss.Style) string {
+		text := fmt.Sprintf(" %s %-40s %10s  %s", mark, truncateWithEllipsis(img.Tag, 40), img.Size, img.Created)
+		if note != "" {
+			text += "  " + note
+		}
+		return style.Render(truncateWithEllipsis(text, m.width-2))
+	}
+	for _, img := range plan.Keep {
+		lines = append(lines, row("keep", "", img, greenStyle))
+	}
+	for _, img := range plan.InUse {
+		lines = append(lines, row("keep", "in use", img, yellowStyle))
+	}
+	for _, img := range plan.Delete {
+		lines = append(lines, row("del ", "", img, redStyle))
+	}
+
+	// Height - header(1) - divider(1) - margin(2)
+	visible := max(m.height-4, 1)
+	for _, line := range lines[min(m.tagCleanupScroll, len(lines)-1):] {
+		if visible == 0 {
+			break
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+		visible--
+	}
+
+	return b.String()
+}
+
+// renderProcessesView lists the processes of a container, busiest first,
+// refreshed every stats interval
+func (m *Model) renderProcessesView() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	lineStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	contentStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	// Header
+	headerText := fmt.Sprintf("Processes: %s (%d)", m.topContainer.Name, len(m.topProcesses))
+	headerRight := fmt.Sprintf("every %s  [ESC] Back", m.statsInterval)
+	headerSpacing := strings.Repeat(" ", max(m.width-len(headerText)-len(headerRight)-4, 1))
+	b.WriteString(titleStyle.Render(headerText))
+	b.WriteString(headerSpacing)
+	b.WriteString(helpStyle.Render(headerRight))
+	b.WriteString("\n")
+
+	// Content divider
+	b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
+	b.WriteString("\n")
+
+	visible := max(m.height-5, 1) // Height - header(1) - divider(1) - titles(1) - margin(2)
+	if m.topErr != "" {
+		b.WriteString(redStyle.Render(truncateWithEllipsis(" "+m.topErr, m.width-2)))
+		b.WriteString("\n")
+		visible--
+	}
+	if !m.topLoaded {
+		b.WriteString(contentStyle.Render(" Loading..."))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	row := func(p types.Process) string {
+		return truncateWithEllipsis(fmt.Sprintf(" %7s  %-10s %6s %6s  %s", p.PID, truncateWithEllipsis(p.User, 10), p.CPU, p.Mem, p.Command), m.width-2)
+	}
+	b.WriteString(titleStyle.Render(row(types.Process{PID: "PID", User: "USER", CPU: "%CPU", Mem: "%MEM", Command: "COMMAND"})))
+	b.WriteString("\n")
+	if len(m.topProcesses) == 0 {
+		return b.String()
+	}
+	for _, p := range m.topProcesses[min(m.topScroll, len(m.topProcesses)-1):] {
+		if visible <= 0 {
+			break
+		}
+		b.WriteString(contentStyle.Render(row(p)))
+		b.WriteString("\n")
+		visible--
+	}
+
+	return b.String()
+}
+
+// dashboardMetric is one graph of the stats dashboard: its values oldest
+// first, the reading shown next to its name and the scale of the graph
+type dashboardMetric struct {
+	label   string
+	reading string
+	values  []float64
+	ceiling float64 // 0 scales the graph to its largest value
+}
+
+// renderStatsDashboard graphs the CPU, memory, network and block I/O of the
+// container on the stats dashboard over the samples kept, newest on the
+// right
+func (m *Model) renderStatsDashboard() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	lineStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	graphStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#00FFFF")).
+		Background(theme.Color("#0a0a0a"))
+
+	// Header
+	headerText := "Stats: " + m.dashContainer.Name
+	headerRight := fmt.Sprintf("every %s  [ESC] Back", dashboardInterval)
+	headerSpacing := strings.Repeat(" ", max(m.width-len(headerText)-len(headerRight)-4, 1))
+	b.WriteString(titleStyle.Render(headerText))
+	b.WriteString(headerSpacing)
+	b.WriteString(helpStyle.Render(headerRight))
+	b.WriteString("\n")
+
+	// Content divider
+	b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
+	b.WriteString("\n")
+
+	samples := m.dashHistory.Samples()
+	if len(samples) == 0 {
+		b.WriteString(helpStyle.Render(" Waiting for stats..."))
+		b.WriteString("\n")
+		return b.String()
+	}
+	latest := samples[len(samples)-1].Stats
+
+	cpu := statshistory.Values(samples, func(s types.ContainerStats) float64 { return s.CPUPercent })
+	mem := statshistory.Values(samples, func(s types.ContainerStats) float64 { return float64(s.MemBytes) })
+	netRx := statshistory.Rates(samples, func(s types.ContainerStats) uint64 { return s.NetRx })
+	netTx := statshistory.Rates(samples, func(s types.ContainerStats) uint64 { return s.NetTx })
+	blockRead := statshistory.Rates(samples, func(s types.ContainerStats) uint64 { return s.BlockRead })
+	blockWrite := statshistory.Rates(samples, func(s types.ContainerStats) uint64 { return s.BlockWrite })
+
+	memReading := units.BytesSize(float64(latest.MemBytes))
+	if latest.MemLimit > 0 {
+		memReading += " / " + units.BytesSize(float64(latest.MemLimit))
+	}
+	metrics := []dashboardMetric{
+		// CPU is drawn against one full CPU at least, so idle noise stays low
+		{"CPU", fmt.Sprintf("%.1f%%  peak %.1f%%", latest.CPUPercent, maxValue(cpu)), cpu, max(maxValue(cpu), 100)},
+		{"MEM", fmt.Sprintf("%s  peak %s", memReading, units.BytesSize(maxValue(mem))), mem, 0},
+		{"NET RX", rateReading(netRx, latest.NetRx), netRx, 0},
+		{"NET TX", rateReading(netTx, latest.NetTx), netTx, 0},
+		{"BLOCK READ", rateReading(blockRead, latest.BlockRead), blockRead, 0},
+		{"BLOCK WRITE", rateReading(blockWrite, latest.BlockWrite), blockWrite, 0},
+	}
+
+	// Each metric takes its title line and an equal share of the height
+	graphHeight := min(max((m.height-3)/len(metrics)-1, 1), 6)
+	graphWidth := max(m.width-4, 10)
+	for _, metric := range metrics {
+		b.WriteString(titleStyle.Render(fmt.Sprintf(" %-12s", metric.label)))
+		b.WriteString(helpStyle.Render(truncateWithEllipsis(metric.reading, max(m.width-16, 1))))
+		b.WriteString("\n")
+		for _, line := range components.Sparkline(metric.values, graphWidth, graphHeight, metric.ceiling) {
+			b.WriteString(" ")
+			b.WriteString(graphStyle.Render(line))
+			b.WriteString("\n")
+		}
+	}
+
+	return b.String()
+}
+
+// rateReading shows the latest rate of a counter on the stats dashboard
+// with its total since the container started
+func rateReading(rates []float64, total uint64) string {
+	rate := 0.0
+	if len(rates) > 0 {
+		rate = rates[len(rates)-1]
+	}
+	return fmt.Sprintf("%s/s  total %s", units.BytesSize(rate), units.BytesSize(float64(total)))
+}
+
+// maxValue returns the largest of values, 0 for none
+func maxValue(values []float64) float64 {
+	largest := 0.0
+	for _, v := range values {
+		largest = max(largest, v)
+	}
+	return largest
+}
+
+// renderStartFailureView explains why a container failed to start, with
+// suggested fixes and the daemon's own error
+func (m *Model) renderStartFailureView() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	lineStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	contentStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	failure := m.startFailure.Failure
+
+	// Header
+	headerText := "Failed to start " + m.startFailure.Container
+	headerRight := "[ESC] Back"
+	if failure.Holder != "" {
+		headerRight = "[G]o to " + failure.Holder + "  " + headerRight
+	}
+	headerSpacing := strings.Repeat(" ", max(m.width-len(headerText)-len(headerRight)-4, 1))
+	b.WriteString(titleStyle.Render(headerText))
+	b.WriteString(headerSpacing)
+	b.WriteString(helpStyle.Render(headerRight))
+	b.WriteString("\n")
+
+	// Content divider
+	b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
+	b.WriteString("\n")
+
+	wrapStyle := lipgloss.NewStyle().Width(max(m.width-8, 20))
+	writeWrapped := func(prefix, text string, style lipgloss.Style) {
+		for i, line := range strings.Split(wrapStyle.Render(text), "\n") {
+			if i > 0 {
+				prefix = strings.Repeat(" ", len(prefix))
+			}
+			b.WriteString(style.Render(prefix + strings.TrimRight(line, " ")))
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString(labelStyle.Render(" Cause"))
+	b.WriteString("\n")
+	writeWrapped("   ", failure.Cause, redStyle)
+	b.WriteString("\n")
+
+	b.WriteString(labelStyle.Render(" Suggested fixes"))
+	b.WriteString("\n")
+	for i, fix := range failure.Fixes {
+		writeWrapped(fmt.Sprintf("   %d. ", i+1), fix, contentStyle)
+	}
+	if failure.Holder != "" {
+		writeWrapped("      ", "Press G to go to "+failure.Holder, helpStyle)
+	}
+	b.WriteString("\n")
+
+	b.WriteString(labelStyle.Render(" Daemon error"))
+	b.WriteString("\n")
+	writeWrapped("   ", m.startFailure.Err, helpStyle)
+
+	return b.String()
+}
+
+// renderLogSearchView renders the results of a log search: one entry per
+// container with its match count and most recent matching lines
+func (m *Model) renderLogSearchView() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	lineStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	contentStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	matchStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#666666")).
+		Background(theme.Color("#0a0a0a"))
+
+	errorStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FF0000")).
+		Background(theme.Color("#0a0a0a"))
+
+	// Header
+	headerText := fmt.Sprintf("Log search: %q", m.logSearchQuery)
+	headerRight := "[Enter] Logs  [/] New search  [ESC] Back"
+	headerSpacing := strings.Repeat(" ", max(m.width-lipgloss.Width(headerText)-len(headerRight)-4, 1))
+	b.WriteString(titleStyle.Render(headerText))
+	b.WriteString(headerSpacing)
+	b.WriteString(helpStyle.Render(headerRight))
+	b.WriteString("\n")
+
+	// Content divider
+	b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
+	b.WriteString("\n")
+
+	switch {
+	case m.logSearchLoading:
+		b.WriteString(contentStyle.Render(fmt.Sprintf(" Searching the last %d lines of each running container...", docker.LogSearchTail)))
+		b.WriteString("\n")
+		return b.String()
+	case len(m.logSearchResults) == 0:
+		b.WriteString(contentStyle.Render(fmt.Sprintf(" No matches in the last %d lines of any running container", docker.LogSearchTail)))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	// Each entry takes its header plus its match lines; keep the cursor's
+	// entry on screen by starting from it when the list doesn't fit
+	available := m.height - 4
+	start := 0
+	for used, i := 0, m.logSearchCursor; i >= 0; i-- {
+		used += 1 + len(m.logSearchResults[i].Matches)
+		if used > available {
+			break
+		}
+		start = i
+	}
+
+	used := 0
+	for i := start; i < len(m.logSearchResults); i++ {
+		result := m.logSearchResults[i]
+		if used+1 > available {
+			break
+		}
+
+		header := fmt.Sprintf("  %s (%d matches)", result.ContainerName, result.Count)
+		if result.Count == 1 {
+			header = fmt.Sprintf("  %s (1 match)", result.ContainerName)
+		}
+		style := contentStyle
+		if i == m.logSearchCursor {
+			header = ">" + header[1:]
+			style = selectedStyle
+		}
+		b.WriteString(style.Render(truncateWithEllipsis(header, m.width-2)))
+		b.WriteString("\n")
+		used++
+
+		if result.Err != nil {
+			b.WriteString(errorStyle.Render(truncateWithEllipsis("      "+result.Err.Error(), m.width-2)))
+			b.WriteString("\n")
+			used++
+			continue
+		}
+		for _, line := range result.Matches {
+			if used >= available {
+				break
+			}
+			b.WriteString(matchStyle.Render(truncateWithEllipsis("      "+line, m.width-2)))
+			b.WriteString("\n")
+			used++
+		}
+	}
+
+	return b.String()
+}
+
+// renderRunImageView renders the run modal: tag selector, container name,
+// and the port, volume and environment pairs collected so far
+func (m *Model) renderRunImageView() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	lineStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a"))
+
+	activeStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#00FF00")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	pullStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFAA00")).
+		Background(theme.Color("#0a0a0a"))
+
+	// Body lines; the focused field is remembered so it can be kept in view
+	var lines []string
+	activeLine := 0
+	field := func(label, value string, id int) string {
+		line := label + value
+		if m.runModalField == id {
+			activeLine = len(lines)
+			return activeStyle.Render(truncateWithEllipsis(line+"█", m.width-2))
+		}
+		return labelStyle.Render(truncateWithEllipsis(line, m.width-2))
+	}
+
+	// Header
+	headerText := "Run container"
+	if m.selectedImage != nil {
+		headerText += ": " + components.TruncateMiddle(m.runImageRef(), max(m.width/2, 20))
+	}
+	headerRight := "[ESC] Back"
+	headerSpacing := strings.Repeat(" ", max(m.width-lipgloss.Width(headerText)-len(headerRight)-4, 1))
+	b.WriteString(titleStyle.Render(headerText))
+	b.WriteString(headerSpacing)
+	b.WriteString(helpStyle.Render(headerRight))
+	b.WriteString("\n")
+
+	// Content divider
+	b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
+	b.WriteString("\n")
+
+	// Tag selector (untagged images run by ID)
+	if m.selectedImage != nil && m.selectedImage.Repository != "<none>" {
+		line := field(" Tag: ", m.runTag, runFieldTag)
+		if m.runTagIsLocal() {
+			line += helpStyle.Render(fmt.Sprintf("   ◀ ▶ %d local tag(s)", len(m.runTags)))
+		} else {
+			line += pullStyle.Render("   not local, pulled before starting")
+		}
+		lines = append(lines, line)
+	}
+	// Images of another architecture run under emulation, if at all
+	if platform := m.runImagePlatform(); docker.PlatformMismatch(platform, m.hostInfo.Platform) {
+		lines = append(lines, pullStyle.Render(truncateWithEllipsis(fmt.Sprintf(
+			" ⚠ %s image on a %s daemon: runs slowly under emulation, or fails with \"exec format error\" without it",
+			platform, m.hostInfo.Platform), m.width-2)))
+	}
+	lines = append(lines, field(" Container name: ", m.runContainerName, runFieldContainerName))
+	lines = append(lines, field(" Hostname: ", m.runHostname, runFieldHostname), "")
+
+	// Ports
+	lines = append(lines, labelStyle.Render(" Ports:"))
+	for _, port := range m.runPorts {
+		lines = append(lines, valueStyle.Render("   "+port.Host+":"+port.Container))
+	}
+	lines = append(lines, field("   Host: ", m.runPortHost, runFieldPortHost))
+	lines = append(lines, field("   Container: ", m.runPortContainer, runFieldPortContainer), "")
+
+	// Volumes
+	lines = append(lines, labelStyle.Render(" Volumes:"))
+	for _, vol := range m.runVolumes {
+		mount := vol.Host + ":" + vol.Container
+		if vol.ReadOnly {
+			mount += " (read-only)"
+		}
+		if vol.Consistency != "" {
+			mount += " " + vol.Consistency
+		}
+		lines = append(lines, valueStyle.Render(truncateWithEllipsis("   "+mount, m.width-2)))
+	}
+	lines = append(lines, field("   Host path: ", m.runVolumeHost, runFieldVolumeHost))
+	lines = append(lines, field("   Container path: ", m.runVolumeContainer, runFieldVolumeContainer))
+	mode := "   Mode: " + runVolumeModes()[m.runVolumeMode]
+	if m.runModalField == runFieldVolumeMode {
+		activeLine = len(lines)
+		lines = append(lines, activeStyle.Render(mode)+helpStyle.Render("   ◀ ▶ change"), "")
+	} else {
+		lines = append(lines, labelStyle.Render(mode), "")
+	}
+
+	// Environment variables
+	lines = append(lines, labelStyle.Render(" Environment variables:"))
+	for _, env := range m.runEnvVars {
+		lines = append(lines, valueStyle.Render(truncateWithEllipsis("   "+env.Key+"="+env.Value, m.width-2)))
+	}
+	lines = append(lines, field("   Key: ", m.runEnvKey, runFieldEnvKey))
+	lines = append(lines, field("   Value: ", m.runEnvValue, runFieldEnvValue), "")
+
+	// Labels, e.g. for traefik routing
+	lines = append(lines, labelStyle.Render(" Labels:"))
+	for _, label := range m.runLabels {
+		lines = append(lines, valueStyle.Render(truncateWithEllipsis("   "+label.Key+"="+label.Value, m.width-2)))
+	}
+	lines = append(lines, field("   Key (or key=value): ", m.runLabelKey, runFieldLabelKey))
+	lines = append(lines, field("   Value: ", m.runLabelValue, runFieldLabelValue))
+
+	// Comfortable density pads the body with blank lines and an indent
+	padLines, indent := m.modalPadding()
+	b.WriteString(strings.Repeat("\n", padLines))
+
+	// Scroll the body on short terminals, keeping the focused field and the
+	// line after it in view. Header and footer take 4 lines, the scroll
+	// markers 2.
+	visible := max(m.height-6-2*padLines, 3)
+	total := len(lines)
+	offset := 0
+	if total > visible {
+		offset = min(max(activeLine-visible+2, 0), total-visible)
+		if offset > 0 {
+			b.WriteString(helpStyle.Render(fmt.Sprintf(" ↑ %d more", offset)))
+		}
+		b.WriteString("\n")
+		lines = lines[offset : offset+visible]
+	}
+	for _, line := range lines {
+		if line != "" {
+			b.WriteString(indent)
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	if below := total - offset - len(lines); below > 0 {
+		b.WriteString(helpStyle.Render(fmt.Sprintf(" ↓ %d more", below)))
+	}
+	b.WriteString("\n")
+	b.WriteString(strings.Repeat("\n", padLines))
+
+	b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render(" [Tab/↑↓] Next field  [←/→] Change tag/mode  [Enter] Add pair or run  [Esc] Cancel"))
+
+	return b.String()
+}
+
+// renderLayersView renders the image layer browser: the list of layers, or
+// the files touched by the layer under the cursor
+func (m *Model) renderLayersView() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	lineStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	contentStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a"))
+
+	// File change markers: added green, modified yellow, deleted red
+	changeStyles := map[string]lipgloss.Style{
+		"A": lipgloss.NewStyle().Foreground(theme.Color("#00FF00")).Background(theme.Color("#0a0a0a")),
+		"M": lipgloss.NewStyle().Foreground(theme.Color("#FFFF00")).Background(theme.Color("#0a0a0a")),
+		"D": lipgloss.NewStyle().Foreground(theme.Color("#FF0000")).Background(theme.Color("#0a0a0a")),
+	}
+
+	// Header
+	headerText := "Layers"
+	if m.selectedImage != nil {
+		headerText = "Layers: " + m.selectedImage.Repository + ":" + m.selectedImage.Tag
+	}
+	if m.layerFilesOpen {
+		headerText = fmt.Sprintf("Layer %d of %d", m.layerCursor+1, len(m.layers))
+	}
+	if m.layerWasteOpen {
+		headerText = fmt.Sprintf("Waste report: %.1f%% efficient, %s wasted", m.layerWaste.Efficiency*100, units.BytesSize(float64(m.layerWaste.Wasted)))
+	}
+	headerRight := "[ESC] Back"
+	headerSpacing := strings.Repeat(" ", max(m.width-len(headerText)-len(headerRight)-4, 1))
+	b.WriteString(titleStyle.Render(headerText))
+	b.WriteString(headerSpacing)
+	b.WriteString(helpStyle.Render(headerRight))
+	b.WriteString("\n")
+
+	// Content divider
+	b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
+	b.WriteString("\n")
+
+	if m.layersErr != "" {
+		b.WriteString(contentStyle.Render(" ERROR: " + m.layersErr))
+		b.WriteString("\n")
+		return b.String()
+	}
+	if m.layers == nil {
+		b.WriteString(contentStyle.Render(" Reading image archive..."))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	// Height - header(1) - divider(2) - step line(2) - help(1)
+	availableLines := max(m.height-8, 5)
+
+	if m.layerWasteOpen {
+		return b.String() + m.renderLayerWaste(availableLines, contentStyle, lineStyle, helpStyle)
+	}
+
+	if !m.layerFilesOpen {
+		start := 0
+		if m.layerCursor >= availableLines {
+			start = m.layerCursor - availableLines + 1
+		}
+		end := min(start+availableLines, len(m.layers))
+
+		for i := start; i < end; i++ {
+			layer := m.layers[i]
+			step := layer.CreatedBy
+			if step == "" {
+				step = layer.Digest
+			}
+			line := fmt.Sprintf(" #%-3d %9s %6d files  %s", i+1, units.BytesSize(float64(layer.Size)), len(layer.Files), step)
+			line = truncateWithEllipsis(line, m.width-2)
+			if i == m.layerCursor {
+				b.WriteString(selectedStyle.Render(line))
+			} else {
+				b.WriteString(contentStyle.Render(line))
+			}
+			b.WriteString("\n")
+		}
+
+		b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render(" [↑↓] Select layer  [Enter] Show files  [W]aste report"))
+		return b.String()
+	}
+
+	// Files of the selected layer
+	layer := m.layers[m.layerCursor]
+	step := layer.CreatedBy
+	if step == "" {
+		step = layer.Digest
+	}
+	b.WriteString(selectedStyle.Render(truncateWithEllipsis(" "+step, m.width-2)))
+	b.WriteString("\n\n")
+
+	if len(layer.Files) == 0 {
+		b.WriteString(contentStyle.Render(" Layer has no files"))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	end := min(m.layerScroll+availableLines, len(layer.Files))
+	for _, f := range layer.Files[m.layerScroll:end] {
+		size := ""
+		if f.Change != "D" {
+			size = units.BytesSize(float64(f.Size))
+		}
+		b.WriteString(changeStyles[f.Change].Render(" " + f.Change))
+		b.WriteString(contentStyle.Render(fmt.Sprintf(" %9s  %s", size, truncateWithEllipsis(f.Path, m.width-16))))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render(fmt.Sprintf(" Files %d-%d of %d  [A]dded [M]odified [D]eleted", m.layerScroll+1, end, len(layer.Files))))
+
+	return b.String()
+}
+
+// renderLayerWaste renders the waste report of the layer browser, biggest
+// items first
+func (m *Model) renderLayerWaste(availableLines int, contentStyle, lineStyle, helpStyle lipgloss.Style) string {
+	var b strings.Builder
+	report := m.layerWaste
+
+	b.WriteString(contentStyle.Render(fmt.Sprintf(" %s in %d layers", units.BytesSize(float64(report.Total)), len(m.layers))))
+	b.WriteString("\n\n")
+
+	if len(report.Items) == 0 {
+		b.WriteString(greenStyle.Render(" No waste found: nothing overwritten, deleted later or left in package caches"))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	end := min(m.layerScroll+availableLines, len(report.Items))
+	for _, item := range report.Items[m.layerScroll:end] {
+		path := item.Path
+		if item.Files > 1 {
+			path += fmt.Sprintf(" (%d files)", item.Files)
+		}
+		line := fmt.Sprintf(" %9s  %-24s %s", units.BytesSize(float64(item.Size)), item.Reason, path)
+		b.WriteString(contentStyle.Render(truncateWithEllipsis(line, m.width-2)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render(fmt.Sprintf(" Items %d-%d of %d  Clean caches and delete files in the step that creates them", m.layerScroll+1, end, len(report.Items))))
+	return b.String()
+}
+
+// Helper functions
+
+// getScrollIndicator returns a scroll indicator showing current position and scroll availability
+func (m *Model) getScrollIndicator(totalItems int) string {
+	if totalItems == 0 {
+		return ""
+	}
+
+	var b strings.Builder
 	indicatorStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#666666")).
-		Background(lipgloss.Color("#0a0a0a"))
+		Foreground(theme.Color("#666666")).
+		Background(theme.Color("#0a0a0a"))
 
 	highlightStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#00FFFF")).
-		Background(lipgloss.Color("#0a0a0a"))
+		Foreground(theme.Color("#00FFFF")).
+		Background(theme.Color("#0a0a0a"))
 
 	b.WriteString("\n")
 
 	// Show scroll indicators and position info
 	canScrollUp := m.scrollOffset > 0
-	canScrollDown := m.scrollOffset+m.viewportHeight < totalItems
+	canScrollDown := m.scrollOffset+m.pageSize() < totalItems
 
 	start := m.scrollOffset + 1
-	end := m.scrollOffset + m.viewportHeight
+	end := m.scrollOffset + m.pageSize()
 	if end > totalItems {
 		end = totalItems
 	}
@@ -677,16 +3745,16 @@ func (m *Model) getInspectScrollIndicator(totalLines, visibleLines int) string {
 
 	var b strings.Builder
 	indicatorStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#666666")).
-		Background(lipgloss.Color("#0a0a0a"))
+		Foreground(theme.Color("#666666")).
+		Background(theme.Color("#0a0a0a"))
 
 	highlightStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#00FFFF")).
-		Background(lipgloss.Color("#0a0a0a"))
+		Foreground(theme.Color("#00FFFF")).
+		Background(theme.Color("#0a0a0a"))
 
 	lineStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#999999")).
-		Background(lipgloss.Color("#0a0a0a"))
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
 
 	// Separator line
 	b.WriteString(lineStyle.Render(strings.Repeat("─", m.width-2)))
@@ -723,12 +3791,12 @@ func (m *Model) getInspectScrollIndicator(totalLines, visibleLines int) string {
 // colorizeJSON adds jq-style syntax highlighting to JSON output
 func colorizeJSON(jsonStr string) string {
 	// Color styles for JSON syntax highlighting
-	keyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#87CEEB"))      // Light blue for keys
-	stringStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#98C379"))   // Green for strings
-	numberStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#D19A66"))   // Orange for numbers
-	boolStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#E5C07B"))     // Yellow for booleans
-	nullStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#5C6370"))     // Gray for null
-	punctStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#ABB2BF"))    // Light gray for punctuation
+	keyStyle := lipgloss.NewStyle().Foreground(theme.Color("#87CEEB"))      // Light blue for keys
+	stringStyle := lipgloss.NewStyle().Foreground(theme.Color("#98C379"))   // Green for strings
+	numberStyle := lipgloss.NewStyle().Foreground(theme.Color("#D19A66"))   // Orange for numbers
+	boolStyle := lipgloss.NewStyle().Foreground(theme.Color("#E5C07B"))     // Yellow for booleans
+	nullStyle := lipgloss.NewStyle().Foreground(theme.Color("#5C6370"))     // Gray for null
+	punctStyle := lipgloss.NewStyle().Foreground(theme.Color("#ABB2BF"))    // Light gray for punctuation
 
 	var result strings.Builder
 	var inString bool
@@ -822,6 +3890,20 @@ func (m *Model) getStatusDot(status string) string {
 	}
 }
 
+// healthMark returns the badge shown after a container's status dot for its
+// healthcheck status, or "" without a healthcheck
+func healthMark(health string) string {
+	switch health {
+	case "healthy":
+		return greenStyle.Render("✓")
+	case "unhealthy":
+		return redStyle.Render("✗")
+	case "starting":
+		return yellowStyle.Render("…")
+	}
+	return ""
+}
+
 // getImageStatusDot returns a colored status indicator based on image status
 func (m *Model) getImageStatusDot(img types.Image) string {
 	if img.InUse {
@@ -843,43 +3925,564 @@ func truncateWithEllipsis(s string, max int) string {
 	return s[:max-3] + "..."
 }
 
-// renderDeleteConfirmation renders an inline delete confirmation message
-func renderDeleteConfirmation(name string, selectedOption int) string {
-	// Delete message in white
-	confirmStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Background(lipgloss.Color("#0a0a0a")).
+// emptyMessage points an empty tab at the key that fills it
+func (m *Model) emptyMessage() string {
+	if filter := m.labelFilters[m.activeTab]; filter != "" {
+		return "Nothing matches " + filter + " — press F to change the filter"
+	}
+	switch m.activeTab {
+	case 0:
+		if m.containerImageFilter != "" && len(m.allContainers) > 0 {
+			return "No containers of " + m.containerImageFilter + " — press F to change the filter"
+		}
+		return "No containers — press R to pick an image to run"
+	case 1:
+		if len(m.allImages) > 0 {
+			return "No images match the " + m.imageFilterLabel(m.imageFilter) + " filter — press F to change it"
+		}
+		return "No images — press P to pull one"
+	case 2:
+		return "No volumes — they appear here once containers create them"
+	}
+	return "No networks"
+}
+
+// renderPullPrompt renders the image reference input of a pull
+func (m *Model) renderPullPrompt() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	inputStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#00FF00")).
+		Background(theme.Color("#0a0a0a"))
+
+	return labelStyle.Render("Pull image: ") +
+		inputStyle.Render(m.pullPromptInput+"█") + " " +
+		renderShortcut("Enter", " Pull") + " " +
+		renderShortcut("Esc", " Cancel")
+}
+
+// renderPullKeepPrompt warns that a pull replaces a local image and asks
+// for the tag to keep it under
+func (m *Model) renderPullKeepPrompt() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	inputStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#00FF00")).
+		Background(theme.Color("#0a0a0a"))
+
+	fieldStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	repo, _, _ := docker.LocalRef(m.pullKeepRef)
+	prompt := labelStyle.Render(components.TruncateMiddle(m.pullKeepRef, 40)+" exists locally, keep it as "+components.TruncateMiddle(repo, 30)+":") +
+		inputStyle.Render(m.pullKeepInput+"█") + " "
+	if m.pullKeepInput == "" {
+		prompt += fieldStyle.Render("empty replaces it") + " "
+	}
+	return prompt + renderShortcut("Enter", " Pull") + " " + renderShortcut("Esc", " Cancel")
+}
+
+// renderSSHPrompt renders the editable SSH destination for the host jump
+func (m *Model) renderSSHPrompt() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	inputStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#00FF00")).
+		Background(theme.Color("#0a0a0a"))
+
+	return labelStyle.Render("SSH to host: ") +
+		inputStyle.Render(m.sshPromptInput+"█") + " " +
+		renderShortcut("Enter", " Connect") + " " +
+		renderShortcut("Esc", " Cancel")
+}
+
+// renderLogSearchPrompt renders the query input of the log search
+func (m *Model) renderLogSearchPrompt() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	inputStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#00FF00")).
+		Background(theme.Color("#0a0a0a"))
+
+	return labelStyle.Render("Search logs of running containers: ") +
+		inputStyle.Render(m.logSearchInput+"█") + " " +
+		renderShortcut("Enter", " Search") + " " +
+		renderShortcut("Esc", " Cancel")
+}
+
+// renderCapturePrompt renders the packet capture port and duration inputs
+func (m *Model) renderCapturePrompt() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	inputStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#00FF00")).
+		Background(theme.Color("#0a0a0a"))
+
+	fieldStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	var rendered [2]string
+	for i, value := range m.captureInput {
+		switch {
+		case i == m.captureField:
+			rendered[i] = inputStyle.Render(value + "█")
+		case i == 0 && value == "":
+			rendered[i] = fieldStyle.Render("any")
+		default:
+			rendered[i] = fieldStyle.Render(value)
+		}
+	}
+
+	return labelStyle.Render("Capture port: ") + rendered[0] +
+		labelStyle.Render(" for ") + rendered[1] + labelStyle.Render("s ") +
+		renderShortcut("Tab", " Field") + " " +
+		renderShortcut("Enter", " Start") + " " +
+		renderShortcut("Esc", " Cancel")
+}
+
+// renderFilterPrompt renders the filter modal of the active tab: the image
+// or preset choice where the tab has one, then the label terms
+func (m *Model) renderFilterPrompt() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	inputStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#00FF00")).
+		Background(theme.Color("#0a0a0a"))
+
+	fieldStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	tab := []string{"containers", "images", "volumes", "networks"}[m.activeTab]
+	prompt := labelStyle.Render("Filter " + tab + ": ")
+
+	choice := ""
+	switch m.activeTab {
+	case 0:
+		choice = fmt.Sprintf("all images (%d)", len(m.allContainers))
+		if m.filterChoice > 0 {
+			use := m.filterImages[m.filterChoice-1]
+			choice = use.Repository
+			if len(use.Tags) > 0 {
+				choice += ":" + strings.Join(use.Tags, ",")
+			}
+			choice += fmt.Sprintf(" (%d)", use.Containers)
+		}
+	case 1:
+		choice = m.imageFilterLabel(m.filterChoice)
+	}
+	if choice != "" {
+		prompt += inputStyle.Render("◀ "+choice+" ▶") +
+			fieldStyle.Render(fmt.Sprintf(" %d/%d ", m.filterChoice+1, m.filterChoices()))
+	}
+
+	prompt += labelStyle.Render("labels: ") + inputStyle.Render(m.filterInput+"█") + " "
+	if m.filterErr != "" {
+		prompt += redStyle.Render(m.filterErr) + " "
+	} else if m.filterInput == "" {
+		prompt += fieldStyle.Render("e.g. label=team=core label!=env=dev") + " "
+	}
+	return prompt + renderShortcut("Enter", " Apply") + " " + renderShortcut("Esc", " Cancel")
+}
+
+// renderDNSPrompt renders the hostname input of a DNS check
+func (m *Model) renderDNSPrompt() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	inputStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#00FF00")).
+		Background(theme.Color("#0a0a0a"))
+
+	return labelStyle.Render("Resolve from "+m.selectedContainer.Name+" (host[:port]): ") +
+		inputStyle.Render(m.dnsPromptInput+"█") + " " +
+		renderShortcut("Enter", " Check") + " " +
+		renderShortcut("Esc", " Cancel")
+}
+
+// renderCommitPrompt renders the image reference, comment and author
+// inputs of a container commit
+func (m *Model) renderCommitPrompt() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
 		Bold(true)
 
-	// Active YES button: black text on green background
-	yesActiveStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#000000")).
-		Background(lipgloss.Color("#00FF00"))
+	inputStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#00FF00")).
+		Background(theme.Color("#0a0a0a"))
 
-	// Active NO button: black text on red background
-	noActiveStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#000000")).
-		Background(lipgloss.Color("#FF0000"))
+	fieldStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
 
-	// Inactive button: gray text, no background
-	inactiveStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#666666")).
-		Background(lipgloss.Color("#0a0a0a"))
+	labels := []string{"Commit " + m.selectedContainer.Name + " to: ", " comment ", " author "}
 
 	var b strings.Builder
-	b.WriteString(confirmStyle.Render("Delete " + truncateWithEllipsis(name, 30) + "? "))
+	for i, value := range m.commitInput {
+		b.WriteString(labelStyle.Render(labels[i]))
+		switch {
+		case i == m.commitField:
+			b.WriteString(inputStyle.Render(value + "█"))
+		case value == "":
+			b.WriteString(fieldStyle.Render("none"))
+		default:
+			b.WriteString(fieldStyle.Render(value))
+		}
+	}
 
-	if selectedOption == 0 {
-		// YES is active
-		b.WriteString(yesActiveStyle.Render(" YES "))
-		b.WriteString(inactiveStyle.Render(" NO "))
-	} else {
-		// NO is active
-		b.WriteString(inactiveStyle.Render(" YES "))
-		b.WriteString(noActiveStyle.Render(" NO "))
+	return b.String() + " " +
+		renderShortcut("Tab", " Field") + " " +
+		renderShortcut("Enter", " Commit") + " " +
+		renderShortcut("Esc", " Cancel")
+}
+
+// renderLimitsPrompt renders the resource limits of an update
+func (m *Model) renderLimitsPrompt() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	inputStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#00FF00")).
+		Background(theme.Color("#0a0a0a"))
+
+	fieldStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	labels := []string{"Update " + components.TruncateMiddle(m.selectedContainer.Name, 24) + ": shares ", " CPUs ", " memory ", " restart "}
+	// What an empty field means
+	defaults := []string{"1024", "unlimited", "unlimited", "no"}
+
+	var b strings.Builder
+	for i, value := range m.limitsInput {
+		b.WriteString(labelStyle.Render(labels[i]))
+		switch {
+		case i == m.limitsField:
+			b.WriteString(inputStyle.Render(value + "█"))
+		case value == "":
+			b.WriteString(fieldStyle.Render(defaults[i]))
+		default:
+			b.WriteString(fieldStyle.Render(value))
+		}
 	}
 
-	return b.String()
+	return b.String() + " " +
+		renderShortcut("Tab", " Field") + " " +
+		renderShortcut("Enter", " Apply") + " " +
+		renderShortcut("Esc", " Cancel")
+}
+
+// renderDrainPrompt renders the longest wait input of a drain
+func (m *Model) renderDrainPrompt() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	inputStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#00FF00")).
+		Background(theme.Color("#0a0a0a"))
+
+	return labelStyle.Render("Stop "+m.selectedContainer.Name+" when its clients disconnect, waiting at most: ") +
+		inputStyle.Render(m.drainInput+"█") + " " +
+		renderShortcut("Enter", " Drain") + " " +
+		renderShortcut("Esc", " Cancel")
+}
+
+// renderPrunePrompt renders the filter input of a prune of stopped
+// containers, with how many are stopped
+func (m *Model) renderPrunePrompt() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	inputStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#00FF00")).
+		Background(theme.Color("#0a0a0a"))
+
+	fieldStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	stopped := 0
+	for _, c := range m.containers {
+		if c.Status == "STOPPED" || c.Status == "ERROR" {
+			stopped++
+		}
+	}
+
+	prompt := labelStyle.Render(fmt.Sprintf("Prune stopped containers (%d), filter: ", stopped)) +
+		inputStyle.Render(m.pruneInput+"█") + " "
+	if m.pruneErr != "" {
+		prompt += redStyle.Render(m.pruneErr) + " "
+	} else if m.pruneInput == "" {
+		prompt += fieldStyle.Render("all, or e.g. 24h, label=env=dev") + " "
+	}
+	return prompt + renderShortcut("Enter", " Prune") + " " + renderShortcut("Esc", " Cancel")
+}
+
+// renderFileCopyPrompt renders the source and destination inputs of a copy
+// between the container and the host
+func (m *Model) renderFileCopyPrompt() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	inputStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#00FF00")).
+		Background(theme.Color("#0a0a0a"))
+
+	fieldStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	labels := []string{"Copy from " + m.selectedContainer.Name + ": ", " to host: "}
+	if m.fileCopyToContainer {
+		labels = []string{"Copy from host: ", " to " + m.selectedContainer.Name + ": "}
+	}
+
+	var b strings.Builder
+	for i, value := range m.fileCopyInput {
+		b.WriteString(labelStyle.Render(labels[i]))
+		if i == m.fileCopyField {
+			b.WriteString(inputStyle.Render(value + "█"))
+		} else {
+			b.WriteString(fieldStyle.Render(value))
+		}
+	}
+
+	return b.String() + " " +
+		renderShortcut("Tab", " Field") + " " +
+		renderShortcut("Enter", " Copy") + " " +
+		renderShortcut("Esc", " Cancel")
+}
+
+// renderExecPrompt renders the command input of a one-shot exec, with the
+// position in the container's history while browsing it
+func (m *Model) renderExecPrompt() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	inputStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#00FF00")).
+		Background(theme.Color("#0a0a0a"))
+
+	history := renderShortcut("↑↓", " History")
+	if m.execHistoryPos < len(m.execCommands) {
+		history = renderShortcut("↑↓", fmt.Sprintf(" History %d/%d", m.execHistoryPos+1, len(m.execCommands)))
+	}
+
+	return labelStyle.Render(m.execContainer.Name+" $ ") +
+		inputStyle.Render(m.execInput+"█") + " " +
+		history + " " +
+		renderShortcut("Enter", " Run") + " " +
+		renderShortcut("Esc", " Cancel")
+}
+
+// renderExecOptionsPrompt renders the command, user, workdir and env inputs
+// of an interactive exec
+func (m *Model) renderExecOptionsPrompt() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	inputStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#00FF00")).
+		Background(theme.Color("#0a0a0a"))
+
+	fieldStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	labels := []string{"Exec in " + m.execContainer.Name + ": ", " user ", " workdir ", " env "}
+	placeholders := []string{"shell", "default", "default", "KEY=value ..."}
+
+	var b strings.Builder
+	for i, value := range m.execOptsInput {
+		b.WriteString(labelStyle.Render(labels[i]))
+		switch {
+		case i == m.execOptsField:
+			b.WriteString(inputStyle.Render(value + "█"))
+		case value == "":
+			b.WriteString(fieldStyle.Render(placeholders[i]))
+		default:
+			b.WriteString(fieldStyle.Render(value))
+		}
+	}
+
+	return b.String() + " " +
+		renderShortcut("Tab", " Field") + " " +
+		renderShortcut("Enter", " Run") + " " +
+		renderShortcut("Esc", " Cancel")
+}
+
+// renderNotePrompt renders the text input of a container or image note
+func (m *Model) renderNotePrompt() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	inputStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#00FF00")).
+		Background(theme.Color("#0a0a0a"))
+
+	return labelStyle.Render("Note on "+m.noteLabel()+": ") +
+		inputStyle.Render(m.noteInput+"█") + " " +
+		renderShortcut("Enter", " Save (empty removes)") + " " +
+		renderShortcut("Esc", " Cancel")
+}
+
+// renderImageSavePrompt asks where to write the images' tar archive, with
+// the size it may take
+func (m *Model) renderImageSavePrompt() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	inputStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#00FF00")).
+		Background(theme.Color("#0a0a0a"))
+
+	label := "Save " + m.imageSaveTargets[0].Repository + ":" + m.imageSaveTargets[0].Tag
+	if len(m.imageSaveTargets) > 1 {
+		label = fmt.Sprintf("Save %d images", len(m.imageSaveTargets))
+	}
+	label += " (up to " + units.HumanSize(float64(docker.SaveEstimate(m.imageSaveTargets))) + ") to: "
+
+	return labelStyle.Render(label) +
+		inputStyle.Render(m.imageSaveInput+"█") + " " +
+		renderShortcut("Enter", " Save") + " " +
+		renderShortcut("Esc", " Cancel")
+}
+
+// renderTagCleanupPrompt asks how many of the newest tags of a repository
+// to keep
+func (m *Model) renderTagCleanupPrompt() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	inputStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#00FF00")).
+		Background(theme.Color("#0a0a0a"))
+
+	return labelStyle.Render("Keep the newest N tags of "+m.tagCleanupRepo+": ") +
+		inputStyle.Render(m.tagCleanupInput+"█") + " " +
+		renderShortcut("Enter", " Preview") + " " +
+		renderShortcut("Esc", " Cancel")
+}
+
+// renderNetworkCreatePrompt renders the fields of a new network: name,
+// driver, MTU and, for drivers that have one, the parent interface or
+// overlay encryption
+func (m *Model) renderNetworkCreatePrompt() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	inputStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#00FF00")).
+		Background(theme.Color("#0a0a0a"))
+
+	fieldStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#999999")).
+		Background(theme.Color("#0a0a0a"))
+
+	labels := []string{"New network: ", " driver ", " MTU ", ""}
+	extra := networkExtraField(m.networkCreateInput[1])
+	switch extra {
+	case "parent":
+		labels[3] = " parent "
+	case "encrypt":
+		labels[3] = " encrypt (y/n) "
+	}
+
+	var b strings.Builder
+	for i, value := range m.networkCreateInput {
+		if i == 3 && extra == "" {
+			break
+		}
+		if i == 3 && extra == "encrypt" && value == "" {
+			value = "no"
+		}
+		b.WriteString(labelStyle.Render(labels[i]))
+		switch {
+		case i == m.networkCreateField:
+			b.WriteString(inputStyle.Render(value + "█"))
+		case i == 2 && value == "":
+			b.WriteString(fieldStyle.Render("default"))
+		default:
+			b.WriteString(fieldStyle.Render(value))
+		}
+	}
+
+	return b.String() + " " +
+		renderShortcut("Tab", " Field") + " " +
+		renderShortcut("Enter", " Create") + " " +
+		renderShortcut("Esc", " Cancel")
+}
+
+// renderVolumeCopyPrompt renders the target input of a volume copy, or the
+// dry-run estimate waiting for confirmation
+func (m *Model) renderVolumeCopyPrompt() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
+		Bold(true)
+
+	inputStyle := lipgloss.NewStyle().
+		Foreground(theme.Color("#00FF00")).
+		Background(theme.Color("#0a0a0a"))
+
+	if plan := m.volumeCopyPlan; plan != nil {
+		into := "new volume " + plan.Target
+		if plan.TargetExists {
+			into = fmt.Sprintf("existing volume %s (%d entries, same paths overwritten)", plan.Target, plan.TargetFiles)
+		}
+		summary := fmt.Sprintf("Copy %s (%d entries) into %s? ", units.HumanSize(float64(plan.Size)), plan.Files, into)
+		return labelStyle.Render(summary) +
+			renderShortcut("Enter", " Copy") + " " +
+			renderShortcut("Esc", " Back")
+	}
+
+	return labelStyle.Render("Copy "+m.volumeCopySource+" to volume: ") +
+		inputStyle.Render(m.volumeCopyInput+"█") + " " +
+		renderShortcut("Enter", " Estimate") + " " +
+		renderShortcut("Esc", " Cancel")
 }
 
 // getActionShortcuts returns the keyboard shortcuts for the current tab
@@ -897,7 +4500,26 @@ func (m *Model) getActionShortcuts() string {
 					renderShortcut("S", "top"),
 					renderShortcut("R", "estart"),
 					renderShortcut("L", "ogs"),
-					renderShortcut("E", "xec"),
+					renderShortcut("m", "etrics"),
+					renderShortcut("t", "op"),
+					renderShortcut("P", "ause"),
+					renderShortcut("e", "xec"),
+					renderShortcut("E", "xec options"),
+					renderShortcut("W", "atch"),
+					renderShortcut("^P", " tcpdump"),
+					renderShortcut("Z", "one/clock"),
+					renderShortcut("N", "et check"),
+					renderShortcut("B", "last radius"),
+					renderShortcut(">", " Image"),
+					renderShortcut("A", "pply env"),
+					renderShortcut("U", "pdate limits"),
+					renderShortcut("I", "nspect"),
+					renderShortcut("D", "elete"),
+				}
+			} else if container.Status == "PAUSED" {
+				shortcuts = []string{
+					renderShortcut("P", " Unpause"),
+					renderShortcut("L", "ogs"),
 					renderShortcut("I", "nspect"),
 					renderShortcut("D", "elete"),
 				}
@@ -906,6 +4528,10 @@ func (m *Model) getActionShortcuts() string {
 				shortcuts = []string{
 					renderShortcut("S", "tart"),
 					renderShortcut("L", "ogs"),
+					renderShortcut("e", "xec in debug copy"),
+					renderShortcut("A", "pply env"),
+					renderShortcut("B", "last radius"),
+					renderShortcut(">", " Image"),
 					renderShortcut("I", "nspect"),
 					renderShortcut("D", "elete"),
 				}
@@ -916,7 +4542,7 @@ func (m *Model) getActionShortcuts() string {
 				renderShortcut("S", "tart/Stop"),
 				renderShortcut("R", "estart"),
 				renderShortcut("L", "ogs"),
-				renderShortcut("E", "xec"),
+				renderShortcut("e", "xec"),
 				renderShortcut("I", "nspect"),
 				renderShortcut("D", "elete"),
 			}
@@ -924,21 +4550,67 @@ func (m *Model) getActionShortcuts() string {
 	case 1: // Images
 		shortcuts = []string{
 			renderShortcut("S", "tart"),
+			renderShortcut("P", "ull"),
 			renderShortcut("I", "nspect"),
 			renderShortcut("D", "elete"),
+			renderShortcut("O", "CI source"),
+			renderShortcut("#", " Digest"),
+			renderShortcut("N", " Pin digest"),
+			renderShortcut("X", " Save tar"),
+			renderShortcut("C", "lean up tags"),
+			renderShortcut(">", " Containers"),
+			renderShortcut("F", "ilter"),
 		}
 	case 2: // Volumes
 		shortcuts = []string{
 			renderShortcut("I", "nspect"),
+			renderShortcut("C", "opy"),
 			renderShortcut("D", "elete"),
 		}
 	case 3: // Networks
 		shortcuts = []string{
+			renderShortcut("N", "ew"),
 			renderShortcut("I", "nspect"),
 			renderShortcut("D", "elete"),
 		}
 	}
 
+	// The image filter stays visible while it hides containers
+	if m.activeTab == 0 && m.containerImageFilter != "" {
+		shortcuts = append([]string{renderShortcut("F", "ilter: "+m.containerImageFilter)}, shortcuts...)
+	}
+
+	// Scaling and replica grouping are offered for compose services
+	if m.activeTab == 0 && m.selectedRow < len(m.containers) && m.containers[m.selectedRow].ComposeService != "" {
+		shortcuts = append(shortcuts, renderShortcut("+/-", " scale"), renderShortcut("G", "roup"))
+	}
+
+	// Bulk actions apply to the marked containers
+	if m.visualAnchor >= 0 {
+		rows := max(m.visualAnchor, m.selectedRow) - min(m.visualAnchor, m.selectedRow) + 1
+		shortcuts = append([]string{renderShortcut("v", fmt.Sprintf(" Mark %d rows", rows)), renderShortcut("Esc", " Cancel")}, shortcuts...)
+	} else if n := m.markedCount(); n > 0 {
+		shortcuts = append([]string{renderShortcut("Space", fmt.Sprintf(" %d marked", n))}, shortcuts...)
+	}
+
+	// A running stats recording is shown on every tab
+	if m.recorder != nil {
+		shortcuts = append([]string{redStyle.Render("●") + " " + renderShortcut("^R", fmt.Sprintf(" Stop recording (%d)", m.recorder.Samples()))}, shortcuts...)
+	}
+
+	// Background tasks stay reachable from every tab while any are listed
+	if n := len(m.taskQueue.Tasks()); n > 0 {
+		shortcuts = append(shortcuts, renderShortcut("T", fmt.Sprintf("asks (%d)", n)))
+	}
+	if n := len(m.schedules.Pending()); n > 0 {
+		shortcuts = append(shortcuts, renderShortcut("@", fmt.Sprintf(" Schedules (%d)", n)))
+	}
+
+	// SSH jump is only offered when connected through an ssh:// context
+	if _, _, ok := docker.SSHDestination(m.sshEndpoint); ok {
+		shortcuts = append(shortcuts, renderShortcut("J", "ump to host"))
+	}
+
 	// Add common shortcuts
 	shortcuts = append(shortcuts,
 		renderShortcut("H", "elp"),
@@ -951,14 +4623,19 @@ func (m *Model) getActionShortcuts() string {
 func renderShortcut(key string, rest ...string) string {
 	// First letter: white with underline
 	keyStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Background(lipgloss.Color("#0a0a0a")).
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
 		Underline(true)
 
 	// Rest of word: dimmed gray
 	textStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#666666")).
-		Background(lipgloss.Color("#0a0a0a"))
+		Foreground(theme.Color("#666666")).
+		Background(theme.Color("#0a0a0a"))
+
+	// Without underline the key is bracketed instead
+	if theme.Plain() {
+		key = "[" + key + "]"
+	}
 
 	var b strings.Builder
 	b.WriteString(keyStyle.Render(key))
@@ -0,0 +1,136 @@
+// Package version holds tinyd build information and checks GitHub for newer
+// releases.
+package version
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+// Build information, overridden at build time with
+// -ldflags "-X tinyd/internal/version.Version=2.1.0 -X tinyd/internal/version.Commit=..."
+var (
+	Version = "2.0.1"
+	Commit  = ""
+	Date    = ""
+)
+
+// ReleasesURL is the GitHub releases page of tinyd
+const ReleasesURL = "https://github.com/jalonsogo/tinyd/releases"
+
+// latestReleaseAPI returns the newest published (non-prerelease) release
+const latestReleaseAPI = "https://api.github.com/repos/jalonsogo/tinyd/releases/latest"
+
+// Info returns a one-line description of the build, for --version
+func Info() string {
+	commit, date := Commit, Date
+	if info, ok := debug.ReadBuildInfo(); ok {
+		// Fall back to the VCS stamp embedded by go build
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if commit == "" {
+					commit = setting.Value
+				}
+			case "vcs.time":
+				if date == "" {
+					date = setting.Value
+				}
+			}
+		}
+	}
+
+	var details []string
+	if commit != "" {
+		details = append(details, "commit "+commit[:min(len(commit), 7)])
+	}
+	if date != "" {
+		details = append(details, "built "+date)
+	}
+	details = append(details, runtime.Version(), runtime.GOOS+"/"+runtime.GOARCH)
+
+	return fmt.Sprintf("tinyd v%s (%s)", Version, strings.Join(details, ", "))
+}
+
+// Release is a published tinyd release
+type Release struct {
+	Version string // Without the leading "v"
+	URL     string
+}
+
+// LatestRelease fetches the latest tinyd release from GitHub
+func LatestRelease(ctx context.Context) (Release, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, latestReleaseAPI, nil)
+	if err != nil {
+		return Release{}, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return Release{}, fmt.Errorf("failed to check for updates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Release{}, fmt.Errorf("failed to check for updates: %s", resp.Status)
+	}
+
+	var body struct {
+		TagName string `json:"tag_name"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return Release{}, fmt.Errorf("failed to parse release: %w", err)
+	}
+
+	url := body.HTMLURL
+	if url == "" {
+		url = ReleasesURL
+	}
+	return Release{Version: strings.TrimPrefix(body.TagName, "v"), URL: url}, nil
+}
+
+// IsNewer reports whether version latest is newer than current. Both are
+// dotted numeric versions with an optional "v" prefix; pre-release and build
+// suffixes are ignored.
+func IsNewer(latest, current string) bool {
+	l, c := parseVersion(latest), parseVersion(current)
+	for i := 0; i < max(len(l), len(c)); i++ {
+		var lp, cp int
+		if i < len(l) {
+			lp = l[i]
+		}
+		if i < len(c) {
+			cp = c[i]
+		}
+		if lp != cp {
+			return lp > cp
+		}
+	}
+	return false
+}
+
+// parseVersion splits "v1.2.3-rc1" into [1 2 3]
+func parseVersion(v string) []int {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+
+	var parts []int
+	for _, field := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			break
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
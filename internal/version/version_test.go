@@ -0,0 +1,35 @@
+package version
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsNewer(t *testing.T) {
+	tests := []struct {
+		latest, current string
+		want            bool
+	}{
+		{"2.1.0", "2.0.1", true},
+		{"v2.0.10", "2.0.9", true},
+		{"2.0.1", "2.0.1", false},
+		{"2.0.0", "2.0.1", false},
+		{"2.1", "2.0.5", true},
+		{"2.0.1-rc1", "2.0.0", true},
+		{"3.0.0", "3.0.0+dirty", false},
+		{"", "2.0.1", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsNewer(tt.latest, tt.current); got != tt.want {
+			t.Errorf("IsNewer(%q, %q) = %t, want %t", tt.latest, tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestInfo(t *testing.T) {
+	info := Info()
+	if !strings.HasPrefix(info, "tinyd v"+Version+" (") {
+		t.Errorf("Info() = %q", info)
+	}
+}
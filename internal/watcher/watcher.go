@@ -0,0 +1,64 @@
+// Package watcher detects changes below host paths by polling file metadata.
+package watcher
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"path/filepath"
+)
+
+// MaxFiles caps how many files a single fingerprint may visit, so that
+// accidentally watching a huge tree doesn't stall the UI
+const MaxFiles = 20000
+
+// Fingerprint walks the given paths and returns a hash that changes whenever a
+// file below any of them is added, removed, resized or modified
+func Fingerprint(paths []string) (uint64, error) {
+	h := fnv.New64a()
+	buf := make([]byte, 8)
+	count := 0
+
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				// Files removed mid-walk are part of the change, not an error
+				if path != root && errors.Is(err, fs.ErrNotExist) {
+					return nil
+				}
+				return err
+			}
+			// VCS metadata churns on every git operation; ignore it
+			if d.IsDir() && d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					return nil
+				}
+				return err
+			}
+
+			count++
+			if count > MaxFiles {
+				return fmt.Errorf("more than %d files under watched paths", MaxFiles)
+			}
+
+			h.Write([]byte(path))
+			binary.LittleEndian.PutUint64(buf, uint64(info.Size()))
+			h.Write(buf)
+			binary.LittleEndian.PutUint64(buf, uint64(info.ModTime().UnixNano()))
+			h.Write(buf)
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return h.Sum64(), nil
+}
@@ -0,0 +1,67 @@
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFingerprintDetectsChanges(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "app.conf")
+	if err := os.WriteFile(file, []byte("a=1"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := Fingerprint([]string{dir})
+	if err != nil {
+		t.Fatalf("Fingerprint() failed: %v", err)
+	}
+
+	again, _ := Fingerprint([]string{dir})
+	if first != again {
+		t.Error("Fingerprint changed without any file changes")
+	}
+
+	// Modify content and bump mtime
+	if err := os.WriteFile(file, []byte("a=22"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Minute)
+	os.Chtimes(file, future, future)
+
+	modified, _ := Fingerprint([]string{dir})
+	if modified == first {
+		t.Error("Fingerprint did not change after modifying a file")
+	}
+
+	// Add a new file
+	os.WriteFile(filepath.Join(dir, "new.conf"), nil, 0o644)
+	added, _ := Fingerprint([]string{dir})
+	if added == modified {
+		t.Error("Fingerprint did not change after adding a file")
+	}
+}
+
+func TestFingerprintIgnoresGitDir(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0o644)
+	os.MkdirAll(filepath.Join(dir, ".git"), 0o755)
+	os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref"), 0o644)
+
+	before, _ := Fingerprint([]string{dir})
+
+	os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref: refs/heads/main"), 0o644)
+
+	after, _ := Fingerprint([]string{dir})
+	if before != after {
+		t.Error("Fingerprint changed after modifying a file inside .git")
+	}
+}
+
+func TestFingerprintMissingPath(t *testing.T) {
+	if _, err := Fingerprint([]string{filepath.Join(t.TempDir(), "missing")}); err == nil {
+		t.Error("Expected error for missing path")
+	}
+}
@@ -0,0 +1,52 @@
+// Package workspace keeps named views of the lists, e.g. "databases" for
+// the containers of the postgres image sorted by memory, so that recurring
+// slices of a big environment are one pick away. Workspaces are saved to a
+// file of the user config directory and survive restarts.
+package workspace
+
+import (
+	"strings"
+
+	"tinyd/internal/store"
+)
+
+// Workspace is a saved tab with its filter, sort and columns
+type Workspace struct {
+	Name string
+
+	Tab int // 0 containers, 1 images, 2 volumes, 3 networks
+
+	// Containers tab
+	ContainerImage string `json:",omitempty"` // Image the containers are filtered to
+	ContainerSort  int    `json:",omitempty"` // types.ContainerSort*
+	GroupReplicas  bool
+	LogPreview     bool `json:",omitempty"`
+	StateColumns   bool `json:",omitempty"` // Uptime, exit code and restart columns
+
+	// Images tab
+	ImageFilter int  `json:",omitempty"` // types.ImageFilter*
+	ImageSource bool `json:",omitempty"`
+	ImageDigest bool `json:",omitempty"`
+}
+
+// Store holds the saved workspaces, sorted by name
+type Store struct {
+	*store.Named[Workspace]
+}
+
+// Open returns the workspaces saved in the user config directory. A missing
+// or unreadable file starts empty.
+func Open() *Store {
+	return openFile(store.Path("workspaces.json"))
+}
+
+// openFile returns the workspaces saved in path
+func openFile(path string) *Store {
+	return &Store{store.OpenNamed(path, "workspace", func(w Workspace) string { return w.Name })}
+}
+
+// Save adds a workspace, replacing the one of the same name (ignoring case)
+func (s *Store) Save(w Workspace) error {
+	w.Name = strings.TrimSpace(w.Name)
+	return s.Named.Save(w)
+}
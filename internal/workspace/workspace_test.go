@@ -0,0 +1,21 @@
+package workspace
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tinyd", "workspaces.json")
+
+	w := Workspace{Name: "databases", ContainerImage: "postgres", ContainerSort: 2, GroupReplicas: true, StateColumns: true}
+	typed := w
+	typed.Name = " databases "
+	if err := openFile(path).Save(typed); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	// A later run gets the whole view back, under the trimmed name
+	if list := openFile(path).List(); len(list) != 1 || list[0] != w {
+		t.Errorf("List() after reopening = %+v, want %+v", list, w)
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
 	"os"
@@ -17,6 +18,12 @@ import (
 	"github.com/docker/go-units"
 	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/client"
+	"tinyd/internal/components"
+	"tinyd/internal/crash"
+	"tinyd/internal/docker"
+	"tinyd/internal/doctor"
+	"tinyd/internal/theme"
+	"tinyd/internal/version"
 )
 
 // Container represents a Docker container with display data
@@ -75,6 +82,19 @@ type actionErrorMsg string
 type logsMsg string
 type inspectMsg string
 
+// logLineMsg carries a line of followed logs; follow tells follow sessions
+// apart so lines of a stopped one are dropped
+type logLineMsg struct {
+	follow int
+	line   string
+}
+
+// logFollowEndMsg reports that a follow session ended
+type logFollowEndMsg struct {
+	follow int
+	err    error
+}
+
 // View modes
 type viewMode int
 
@@ -154,6 +174,10 @@ type model struct {
 	logsScrollOffset  int
 	logsSearchMode    bool
 	logsSearchQuery   string
+	logsFollow        bool
+	logsFollowID      int                // Current follow session, see logLineMsg
+	logsFollowUpdates chan tea.Msg       // Lines and end of the current session
+	logsFollowCancel  context.CancelFunc // Stops the current session
 	inspectContent    string
 	inspectMode       int // 0=stats, 1=image, 2=mounts
 	selectedContainer *Container
@@ -229,22 +253,22 @@ type EnvVar struct {
 // Minimalistic color palette
 var (
 	// Minimalistic color palette
-	bgColor     = lipgloss.Color("#0a0a0a")
-	borderColor = lipgloss.Color("#303030")
-	lineColor   = lipgloss.Color("#1a1a1a")
+	bgColor     = theme.Color("#0a0a0a")
+	borderColor = theme.Color("#303030")
+	lineColor   = theme.Color("#1a1a1a")
 
 	// Status colors (for dots)
-	green  = lipgloss.Color("#00FF00")
-	yellow = lipgloss.Color("#FFFF00")
-	red    = lipgloss.Color("#FF0000")
-	cyan   = lipgloss.Color("#00FFFF")
+	green  = theme.Color("#00FF00")
+	yellow = theme.Color("#FFFF00")
+	red    = theme.Color("#FF0000")
+	cyan   = theme.Color("#00FFFF")
 
 	// Text colors
-	white      = lipgloss.Color("#FFFFFF")
-	grayText   = lipgloss.Color("#666666")
-	darkGray   = lipgloss.Color("#444444")
-	dimOverlay = lipgloss.Color("#333333")
-	lightGray  = lipgloss.Color("#999999")
+	white      = theme.Color("#FFFFFF")
+	grayText   = theme.Color("#666666")
+	darkGray   = theme.Color("#444444")
+	dimOverlay = theme.Color("#333333")
+	lightGray  = theme.Color("#999999")
 )
 
 // Styles - Minimalistic theme
@@ -325,7 +349,7 @@ func initialModel() model {
 		loading:        true,
 
 		// Initialize components
-		header:     NewHeaderComponent("tinyd v2.0.1", "[F1] Help [Q]uit"),
+		header:     NewHeaderComponent("tinyd v"+version.Version, "[F1] Help [Q]uit"),
 		tabs:       NewTabsComponent(tabs, 0),
 		actionBar:  NewActionBarComponent(),
 		detailView: NewDetailViewComponent("", 15),
@@ -953,50 +977,77 @@ func openBrowserPort(port string) tea.Cmd {
 	}
 }
 
-// Open console in container
-func openConsole(containerID, containerName string, useDebug bool) tea.Cmd {
-	var cmd *exec.Cmd
+// Open console in container. Shell detection and the exec itself go through
+// the Docker API, so only docker debug needs the docker CLI.
+func openConsole(cli *client.Client, containerID, containerName string, useDebug bool) tea.Cmd {
+	done := func(err error) tea.Msg {
+		if err != nil {
+			return actionErrorMsg(fmt.Sprintf("Console error: %v", err))
+		}
+		return actionSuccessMsg(fmt.Sprintf("Exited console for %s", containerName))
+	}
 
 	if useDebug {
-		// Use docker debug directly
-		cmd = exec.Command("docker", "debug", containerID)
-	} else {
-		// Try different shells with docker exec
-		shells := []string{"/bin/bash", "/bin/sh", "/bin/ash"}
-		var selectedShell string
-
-		for _, shell := range shells {
-			testCmd := exec.Command("docker", "exec", containerID, "test", "-f", shell)
-			if testCmd.Run() == nil {
-				selectedShell = shell
-				break
-			}
-		}
+		// docker debug is a CLI plugin with no API equivalent
+		return tea.ExecProcess(exec.Command("docker", "debug", containerID), done)
+	}
 
-		// Fallback to /bin/sh if no shell found
-		if selectedShell == "" {
-			selectedShell = "/bin/sh"
+	return func() tea.Msg {
+		dockerClient := docker.Wrap(cli)
+		shell, err := dockerClient.DetectShell(nil, containerID)
+		if err != nil {
+			return actionErrorMsg(fmt.Sprintf("Console error: %v", err))
 		}
 
-		// Write init script to display toolbar
-		mode := "docker exec"
-		if useDebug {
-			mode = "docker debug"
+		// tea.Exec releases the terminal (altscreen included) while the shell runs
+		plain := dockerClient.NewExecSession(containerID, []string{shell})
+		if os.Getenv(consoleToolbarEnvVar) == "0" {
+			return tea.Exec(plain, done)()
 		}
 
 		// Create script that shows toolbar and starts shell
-		initScript := createToolbarScript(containerName, mode, containerID, selectedShell)
+		initScript := createToolbarScript(containerName, "docker exec", containerID, shell)
+		toolbar := dockerClient.NewExecSession(containerID, []string{shell, "-c", initScript})
+		return tea.Exec(&fallbackSession{first: toolbar, fallback: plain}, done)()
+	}
+}
 
-		cmd = exec.Command("docker", "exec", "-it", containerID, selectedShell, "-c", initScript)
+// consoleToolbarEnvVar turns the console toolbar off when set to 0, for
+// containers whose shell doesn't cope with the injected script
+const consoleToolbarEnvVar = "TINYD_CONSOLE_TOOLBAR"
+
+// fallbackWindow is how soon a failed session counts as failing to start;
+// a shell used for longer may exit with 127 after a mistyped command
+const fallbackWindow = 2 * time.Second
+
+// fallbackSession runs the fallback session when the first one fails to
+// start, e.g. a plain shell when the toolbar script can't start it
+type fallbackSession struct {
+	first, fallback *docker.ExecSession
+}
+
+func (s *fallbackSession) Run() error {
+	start := time.Now()
+	err := s.first.Run()
+	if err == nil || time.Since(start) > fallbackWindow {
+		return err
 	}
+	return s.fallback.Run()
+}
 
-	// Use tea.ExecProcess for altscreen support
-	return tea.ExecProcess(cmd, func(err error) tea.Msg {
-		if err != nil {
-			return actionErrorMsg(fmt.Sprintf("Console error: %v", err))
-		}
-		return actionSuccessMsg(fmt.Sprintf("Exited console for %s", containerName))
-	})
+func (s *fallbackSession) SetStdin(r io.Reader) {
+	s.first.SetStdin(r)
+	s.fallback.SetStdin(r)
+}
+
+func (s *fallbackSession) SetStdout(w io.Writer) {
+	s.first.SetStdout(w)
+	s.fallback.SetStdout(w)
+}
+
+func (s *fallbackSession) SetStderr(w io.Writer) {
+	s.first.SetStderr(w)
+	s.fallback.SetStderr(w)
 }
 
 func createToolbarScript(containerName, mode, containerID, shell string) string {
@@ -1085,6 +1136,36 @@ func getContainerLogs(cli *client.Client, containerID string) tea.Cmd {
 	}
 }
 
+// followContainerLogs streams the lines a container writes from now on into
+// updates, until ctx is cancelled or the stream ends
+func followContainerLogs(ctx context.Context, cli *client.Client, containerID string, follow int, updates chan tea.Msg) tea.Cmd {
+	go func() {
+		defer close(updates)
+		err := docker.Wrap(cli).FollowLogs(ctx, containerID, false, func(line string) {
+			select {
+			case updates <- logLineMsg{follow: follow, line: line}:
+			case <-ctx.Done():
+			}
+		})
+		if ctx.Err() != nil {
+			return // Stopped by the user
+		}
+		updates <- logFollowEndMsg{follow: follow, err: err}
+	}()
+	return waitForLogLine(updates)
+}
+
+// waitForLogLine delivers the next message of a follow session
+func waitForLogLine(updates <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-updates
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
 // Get container inspect info
 func inspectContainer(cli *client.Client, containerID string) tea.Cmd {
 	return func() tea.Msg {
@@ -1792,7 +1873,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if len(filteredContainers) > 0 && m.selectedRow < len(filteredContainers) {
 					selectedContainer := filteredContainers[m.selectedRow]
 					if selectedContainer.Status == "RUNNING" {
-						return m, openConsole(selectedContainer.ID, selectedContainer.Name, false)
+						return m, openConsole(m.dockerClient, selectedContainer.ID, selectedContainer.Name, false)
 					} else {
 						m.statusMessage = "ERROR: Container must be running"
 					}
@@ -1854,6 +1935,22 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 		case "f", "F":
+			// Follow logs: stream new lines into the logs view
+			if m.currentView == viewModeLogs && !m.logsSearchMode {
+				if m.logsFollow {
+					m.stopLogsFollow()
+					return m, nil
+				}
+				if m.selectedContainer == nil || m.dockerClient == nil {
+					return m, nil
+				}
+				m.logsFollowID++
+				m.logsFollow = true
+				m.logsFollowUpdates = make(chan tea.Msg, 64)
+				ctx, cancel := context.WithCancel(context.Background())
+				m.logsFollowCancel = cancel
+				return m, followContainerLogs(ctx, m.dockerClient, m.selectedContainer.ID, m.logsFollowID, m.logsFollowUpdates)
+			}
 			// Open filter modal
 			if m.currentView == viewModeList {
 				m.currentView = viewModeFilter
@@ -1910,6 +2007,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.logsScrollOffset = 0
 			} else if m.currentView != viewModeList {
 				// Return to list view
+				m.stopLogsFollow()
 				m.currentView = viewModeList
 				m.logsContent = ""
 				m.inspectContent = ""
@@ -2102,21 +2200,17 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 
-		// Calculate viewport height based on terminal height
-		// Fixed UI elements: tabs (3 lines) + table header (2 lines) + action bar (2 lines) = 7 lines
-		// Reserve 1 line for safety margin
-		fixedLines := 8
-		m.viewportHeight = msg.Height - fixedLines
-		if m.viewportHeight < 5 {
-			m.viewportHeight = 5 // Minimum height to show something
-		}
-
 		// Update components with new width
 		m.header = m.header.WithWidth(m.width)
 		m.tabs = m.tabs.WithWidth(m.width)
 		m.actionBar = m.actionBar.WithWidth(m.width)
 		m.detailView = m.detailView.WithWidth(m.width)
 
+		// The table gets whatever the header, tabs, status line and action bar
+		// leave, measured from their rendering (minimum 5 rows)
+		m.viewportHeight = components.ViewportHeight(msg.Height, 5,
+			m.header.View(), m.tabs.View(), NewStatusLineComponent("", 0).View(), m.actionBar.View())
+
 	case containerListMsg:
 		m.containers = msg
 		m.loading = false
@@ -2172,6 +2266,22 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.logsContent = string(msg)
 		return m, nil
 
+	case logLineMsg:
+		if !m.logsFollow || msg.follow != m.logsFollowID {
+			return m, nil
+		}
+		m.appendLogLine(msg.line)
+		return m, waitForLogLine(m.logsFollowUpdates)
+
+	case logFollowEndMsg:
+		if msg.follow == m.logsFollowID {
+			m.stopLogsFollow()
+			if msg.err != nil {
+				m.appendLogLine("── follow stopped: " + msg.err.Error() + " ──")
+			}
+		}
+		return m, nil
+
 	case inspectMsg:
 		m.inspectContent = string(msg)
 		return m, nil
@@ -2508,10 +2618,10 @@ func (m model) addFilterIndicator(tabsView string, filterName string, width int)
 	if m.listSearchMode {
 		// Show search input
 		searchStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#CCCCCC"))
+			Foreground(theme.Color("#CCCCCC"))
 
 		cursorStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFFFFF"))
+			Foreground(theme.Color("#FFFFFF"))
 
 		// Build search text: / query█
 		rightContent = searchStyle.Render("/") + " " + searchStyle.Render(m.listSearchQuery) + cursorStyle.Render("█")
@@ -2519,10 +2629,10 @@ func (m model) addFilterIndicator(tabsView string, filterName string, width int)
 	} else {
 		// Show filter indicator
 		filterStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#CCCCCC"))
+			Foreground(theme.Color("#CCCCCC"))
 
 		fStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFFFFF")).
+			Foreground(theme.Color("#FFFFFF")).
 			Underline(true)
 
 		// Build filter text: ≡ Filter: {selection}
@@ -2609,11 +2719,11 @@ func renderShortcut(key string) string {
 	}
 
 	firstLetterStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFFFF")).
+		Foreground(theme.Color("#FFFFFF")).
 		Underline(true)
 
 	restStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#CCCCCC"))
+		Foreground(theme.Color("#CCCCCC"))
 
 	firstLetter := string(key[0])
 	rest := ""
@@ -2832,9 +2942,9 @@ func (m model) renderContainers() string {
 			// Check if this row should show delete confirmation
 			if m.deleteConfirmMode && i == m.selectedRow {
 				// Build inline delete confirmation
-				deleteBg := lipgloss.Color("#610202")
-				nameColor := lipgloss.Color("#FFFFFF")
-				questionColor := lipgloss.Color("#EA3323")
+				deleteBg := theme.Color("#610202")
+				nameColor := theme.Color("#FFFFFF")
+				questionColor := theme.Color("#EA3323")
 
 				nameStyle := lipgloss.NewStyle().Foreground(nameColor).Background(deleteBg)
 				questionStyle := lipgloss.NewStyle().Foreground(questionColor).Background(deleteBg)
@@ -2924,7 +3034,8 @@ func (m model) renderContainers() string {
 		m.actionBar = m.actionBar.SetActions("")
 	}
 	actionBar := m.actionBar.WithWidth(width)
-	b.WriteString(actionBar.View())
+	// No trailing newline, so a full table ends on the terminal's last line
+	b.WriteString(strings.TrimSuffix(actionBar.View(), "\n"))
 
 	return containerStyle.Render(b.String())
 }
@@ -3057,9 +3168,9 @@ func (m model) renderImages() string {
 			// Check if this row should show delete confirmation
 			if m.deleteConfirmMode && isSelected {
 				// Build inline delete confirmation
-				deleteBg := lipgloss.Color("#610202")
-				nameColor := lipgloss.Color("#FFFFFF")
-				questionColor := lipgloss.Color("#EA3323")
+				deleteBg := theme.Color("#610202")
+				nameColor := theme.Color("#FFFFFF")
+				questionColor := theme.Color("#EA3323")
 
 				nameStyle := lipgloss.NewStyle().Foreground(nameColor).Background(deleteBg)
 				questionStyle := lipgloss.NewStyle().Foreground(questionColor).Background(deleteBg)
@@ -3141,7 +3252,8 @@ func (m model) renderImages() string {
 		m.actionBar = m.actionBar.SetActions("")
 	}
 	actionBar := m.actionBar.WithWidth(width)
-	b.WriteString(actionBar.View())
+	// No trailing newline, so a full table ends on the terminal's last line
+	b.WriteString(strings.TrimSuffix(actionBar.View(), "\n"))
 
 	return containerStyle.Render(b.String())
 }
@@ -3272,9 +3384,9 @@ func (m model) renderVolumes() string {
 			// Check if this row should show delete confirmation
 			if m.deleteConfirmMode && isSelected {
 				// Build inline delete confirmation
-				deleteBg := lipgloss.Color("#610202")
-				nameColor := lipgloss.Color("#FFFFFF")
-				questionColor := lipgloss.Color("#EA3323")
+				deleteBg := theme.Color("#610202")
+				nameColor := theme.Color("#FFFFFF")
+				questionColor := theme.Color("#EA3323")
 
 				nameStyle := lipgloss.NewStyle().Foreground(nameColor).Background(deleteBg)
 				questionStyle := lipgloss.NewStyle().Foreground(questionColor).Background(deleteBg)
@@ -3356,7 +3468,8 @@ func (m model) renderVolumes() string {
 		m.actionBar = m.actionBar.SetActions("")
 	}
 	actionBar := m.actionBar.WithWidth(width)
-	b.WriteString(actionBar.View())
+	// No trailing newline, so a full table ends on the terminal's last line
+	b.WriteString(strings.TrimSuffix(actionBar.View(), "\n"))
 
 	return containerStyle.Render(b.String())
 }
@@ -3492,9 +3605,9 @@ func (m model) renderNetworks() string {
 			// Check if this row should show delete confirmation
 			if m.deleteConfirmMode && isSelected {
 				// Build inline delete confirmation
-				deleteBg := lipgloss.Color("#610202")
-				nameColor := lipgloss.Color("#FFFFFF")
-				questionColor := lipgloss.Color("#EA3323")
+				deleteBg := theme.Color("#610202")
+				nameColor := theme.Color("#FFFFFF")
+				questionColor := theme.Color("#EA3323")
 
 				nameStyle := lipgloss.NewStyle().Foreground(nameColor).Background(deleteBg)
 				questionStyle := lipgloss.NewStyle().Foreground(questionColor).Background(deleteBg)
@@ -3576,11 +3689,41 @@ func (m model) renderNetworks() string {
 		m.actionBar = m.actionBar.SetActions("")
 	}
 	actionBar := m.actionBar.WithWidth(width)
-	b.WriteString(actionBar.View())
+	// No trailing newline, so a full table ends on the terminal's last line
+	b.WriteString(strings.TrimSuffix(actionBar.View(), "\n"))
 
 	return containerStyle.Render(b.String())
 }
 
+// appendLogLine adds a followed line to the logs view, keeping the view at
+// the bottom unless the user has scrolled up
+func (m *model) appendLogLine(line string) {
+	// Same height as renderLogs
+	availableLines := max(m.height-5, 5)
+	lines := strings.Count(m.logsContent, "\n") + 1
+	atBottom := m.logsScrollOffset >= lines-availableLines
+
+	if m.logsContent == "" {
+		m.logsContent = line
+	} else {
+		m.logsContent += "\n" + line
+	}
+	if atBottom && !m.logsSearchMode {
+		m.logsScrollOffset = max(lines+1-availableLines, 0)
+	}
+}
+
+// stopLogsFollow ends the current follow session, if any
+func (m *model) stopLogsFollow() {
+	if !m.logsFollow {
+		return
+	}
+	m.logsFollow = false
+	m.logsFollowUpdates = nil
+	m.logsFollowCancel()
+	m.logsFollowCancel = nil
+}
+
 func (m model) renderLogs() string {
 	var b strings.Builder
 
@@ -3605,18 +3748,18 @@ func (m model) renderLogs() string {
 	// Styles
 	// Blue background for header bar (same as console: #1D85E1 to #0F4FA9)
 	headerBarStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Background(lipgloss.Color("#1668B8")). // Mid-point between #1D85E1 and #0F4FA9
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#1668B8")). // Mid-point between #1D85E1 and #0F4FA9
 		Bold(true)
 
 	lineStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#303030"))
+		Foreground(theme.Color("#303030"))
 
 	contentStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#666666"))
+		Foreground(theme.Color("#666666"))
 
 	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#666666"))
+		Foreground(theme.Color("#666666"))
 
 	// Pre-calculate scroll info for the header
 	logLines := strings.Split(m.logsContent, "\n")
@@ -3646,6 +3789,9 @@ func (m model) renderLogs() string {
 
 	// Build header bar with all information
 	titleText := fmt.Sprintf("  Logs: %s  ", containerName)
+	if m.logsFollow {
+		titleText = fmt.Sprintf("  Logs: %s (following)  ", containerName)
+	}
 	var searchText string
 	if m.logsSearchMode {
 		searchInput := "Search: " + m.logsSearchQuery + "█"
@@ -3657,9 +3803,9 @@ func (m model) renderLogs() string {
 	// Add shortcuts and scroll info on the right
 	var headerRight string
 	if len(filteredLines) > availableLines {
-		headerRight = fmt.Sprintf("[S]earch | [ESC] Back | %d-%d of %d lines  ", m.logsScrollOffset+1, end, len(filteredLines))
+		headerRight = fmt.Sprintf("[F]ollow | [S]earch | [ESC] Back | %d-%d of %d lines  ", m.logsScrollOffset+1, end, len(filteredLines))
 	} else {
-		headerRight = "[S]earch | [ESC] Back  "
+		headerRight = "[F]ollow | [S]earch | [ESC] Back  "
 	}
 
 	// Build full-width header with blue background
@@ -3767,10 +3913,10 @@ func (m model) renderPortSelector() string {
 	// Build modal with box-drawing characters
 	var modalContent strings.Builder
 
-	borderColor := lipgloss.Color("#666666")
-	modalBg := lipgloss.Color("#0a0a0a")
-	textColor := lipgloss.Color("#CCCCCC")
-	selectedColor := lipgloss.Color("#FFFFFF")
+	borderColor := theme.Color("#666666")
+	modalBg := theme.Color("#0a0a0a")
+	textColor := theme.Color("#CCCCCC")
+	selectedColor := theme.Color("#FFFFFF")
 
 	borderStyle := lipgloss.NewStyle().Foreground(borderColor).Background(modalBg)
 	textStyle := lipgloss.NewStyle().Foreground(textColor).Background(modalBg)
@@ -3865,11 +4011,11 @@ func (m model) renderFilterModal() string {
 	// Build modal with box-drawing characters
 	var modalContent strings.Builder
 
-	borderColor := lipgloss.Color("#666666")
-	modalBg := lipgloss.Color("#0a0a0a")
-	textColor := lipgloss.Color("#CCCCCC")
-	selectedColor := lipgloss.Color("#FFFFFF")
-	checkColor := lipgloss.Color("#00FF00") // Green checkmark
+	borderColor := theme.Color("#666666")
+	modalBg := theme.Color("#0a0a0a")
+	textColor := theme.Color("#CCCCCC")
+	selectedColor := theme.Color("#FFFFFF")
+	checkColor := theme.Color("#00FF00") // Green checkmark
 
 	borderStyle := lipgloss.NewStyle().Foreground(borderColor).Background(modalBg)
 	textStyle := lipgloss.NewStyle().Foreground(textColor).Background(modalBg)
@@ -3957,11 +4103,11 @@ func (m model) renderStopConfirm() string {
 	// Build modal with box-drawing characters
 	var modalContent strings.Builder
 
-	borderColor := lipgloss.Color("#666666")
-	modalBg := lipgloss.Color("#0a0a0a")
-	textColor := lipgloss.Color("#CCCCCC")
-	warningColor := lipgloss.Color("#FFFFFF")
-	subTextColor := lipgloss.Color("#999999")
+	borderColor := theme.Color("#666666")
+	modalBg := theme.Color("#0a0a0a")
+	textColor := theme.Color("#CCCCCC")
+	warningColor := theme.Color("#FFFFFF")
+	subTextColor := theme.Color("#999999")
 
 	borderStyle := lipgloss.NewStyle().Foreground(borderColor).Background(modalBg)
 	textStyle := lipgloss.NewStyle().Foreground(textColor).Background(modalBg)
@@ -4037,11 +4183,11 @@ func (m model) renderPullImageModal() string {
 	// Build modal with box-drawing characters
 	var modalContent strings.Builder
 
-	borderColor := lipgloss.Color("#666666")
-	modalBg := lipgloss.Color("#0a0a0a")
-	textColor := lipgloss.Color("#CCCCCC")
-	labelColor := lipgloss.Color("#999999")
-	inputColor := lipgloss.Color("#FFFFFF")
+	borderColor := theme.Color("#666666")
+	modalBg := theme.Color("#0a0a0a")
+	textColor := theme.Color("#CCCCCC")
+	labelColor := theme.Color("#999999")
+	inputColor := theme.Color("#FFFFFF")
 
 	borderStyle := lipgloss.NewStyle().Foreground(borderColor).Background(modalBg)
 	textStyle := lipgloss.NewStyle().Foreground(textColor).Background(modalBg)
@@ -4138,12 +4284,12 @@ func (m model) renderRunImageModal() string {
 	// Build modal with box-drawing characters
 	var modalContent strings.Builder
 
-	borderColor := lipgloss.Color("#666666")
-	modalBg := lipgloss.Color("#0a0a0a")
-	textColor := lipgloss.Color("#CCCCCC")
-	labelColor := lipgloss.Color("#999999")
-	inputColor := lipgloss.Color("#FFFFFF")
-	activeColor := lipgloss.Color("#00FF00") // Green for active field
+	borderColor := theme.Color("#666666")
+	modalBg := theme.Color("#0a0a0a")
+	textColor := theme.Color("#CCCCCC")
+	labelColor := theme.Color("#999999")
+	inputColor := theme.Color("#FFFFFF")
+	activeColor := theme.Color("#00FF00") // Green for active field
 
 	borderStyle := lipgloss.NewStyle().Foreground(borderColor).Background(modalBg)
 	textStyle := lipgloss.NewStyle().Foreground(textColor).Background(modalBg)
@@ -4352,25 +4498,25 @@ func (m model) renderError() string {
 	var b strings.Builder
 
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FF0000")).
-		Background(lipgloss.Color("#0a0a0a")).
+		Foreground(theme.Color("#FF0000")).
+		Background(theme.Color("#0a0a0a")).
 		Bold(true)
 
 	errorStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FF0000")).
-		Background(lipgloss.Color("#0a0a0a"))
+		Foreground(theme.Color("#FF0000")).
+		Background(theme.Color("#0a0a0a"))
 
 	textStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#666666")).
-		Background(lipgloss.Color("#0a0a0a"))
+		Foreground(theme.Color("#666666")).
+		Background(theme.Color("#0a0a0a"))
 
 	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#00FFFF")).
-		Background(lipgloss.Color("#0a0a0a"))
+		Foreground(theme.Color("#00FFFF")).
+		Background(theme.Color("#0a0a0a"))
 
 	lineStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#303030")).
-		Background(lipgloss.Color("#0a0a0a"))
+		Foreground(theme.Color("#303030")).
+		Background(theme.Color("#0a0a0a"))
 
 	title := "tinyd - Error"
 	b.WriteString(titleStyle.Render(title))
@@ -4387,21 +4533,42 @@ func (m model) renderError() string {
 	b.WriteString(errorStyle.Render(errorLine))
 	b.WriteString("\n\n")
 
-	troubleLine := "Troubleshooting:"
-	b.WriteString(textStyle.Render(troubleLine))
-	b.WriteString("\n")
+	host := client.DefaultDockerHost
+	if m.dockerClient != nil {
+		host = m.dockerClient.DaemonHost()
+	}
+	if diagnosis, ok := docker.DiagnoseConnection(m.err, host); ok {
+		// Permission errors get fixes for the socket that was tried
+		kind := "rootful"
+		if diagnosis.Rootless {
+			kind = "rootless"
+		}
+		b.WriteString(textStyle.Render(fmt.Sprintf("Tried %s (%s daemon socket)", diagnosis.Host, kind)))
+		b.WriteString("\n\n")
+		b.WriteString(textStyle.Render("To fix:"))
+		b.WriteString("\n")
+		for _, fix := range diagnosis.Fixes {
+			b.WriteString(textStyle.Render("  - " + fix))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	} else {
+		troubleLine := "Troubleshooting:"
+		b.WriteString(textStyle.Render(troubleLine))
+		b.WriteString("\n")
 
-	tip1 := "  - Make sure Docker is running"
-	b.WriteString(textStyle.Render(tip1))
-	b.WriteString("\n")
+		tip1 := "  - Make sure Docker is running"
+		b.WriteString(textStyle.Render(tip1))
+		b.WriteString("\n")
 
-	tip2 := "  - Check Docker socket permissions"
-	b.WriteString(textStyle.Render(tip2))
-	b.WriteString("\n")
+		tip2 := "  - Check Docker socket permissions"
+		b.WriteString(textStyle.Render(tip2))
+		b.WriteString("\n")
 
-	tip3 := "  - Verify DOCKER_HOST environment variable"
-	b.WriteString(textStyle.Render(tip3))
-	b.WriteString("\n\n")
+		tip3 := "  - Verify DOCKER_HOST environment variable"
+		b.WriteString(textStyle.Render(tip3))
+		b.WriteString("\n\n")
+	}
 
 	quitLine := "Press 'q' to quit"
 	b.WriteString(helpStyle.Render(quitLine))
@@ -4419,25 +4586,25 @@ func (m model) renderHelp() string {
 	var b strings.Builder
 
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Background(lipgloss.Color("#0a0a0a")).
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a")).
 		Bold(true)
 
 	headerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Background(lipgloss.Color("#0a0a0a"))
+		Foreground(theme.Color("#FFFFFF")).
+		Background(theme.Color("#0a0a0a"))
 
 	textStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#666666")).
-		Background(lipgloss.Color("#0a0a0a"))
+		Foreground(theme.Color("#666666")).
+		Background(theme.Color("#0a0a0a"))
 
 	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#00FFFF")).
-		Background(lipgloss.Color("#0a0a0a"))
+		Foreground(theme.Color("#00FFFF")).
+		Background(theme.Color("#0a0a0a"))
 
 	lineStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#303030")).
-		Background(lipgloss.Color("#0a0a0a"))
+		Foreground(theme.Color("#303030")).
+		Background(theme.Color("#0a0a0a"))
 
 	// Helper function to render a line
 	renderLine := func(text string, style lipgloss.Style) {
@@ -4630,16 +4797,43 @@ func padCenter(s string, width int) string {
 }
 
 func main() {
+	showVersion := flag.Bool("version", false, "print version information and exit")
+	plain := flag.Bool("plain", false, "disable colors, backgrounds and text decorations")
+	flag.Parse()
+
+	if *showVersion {
+		fmt.Println(version.Info())
+		return
+	}
+
+	if flag.Arg(0) == "doctor" {
+		os.Exit(doctor.Run(os.Stdout))
+	}
+
+	var reporter *crash.Reporter
 	defer func() {
 		if r := recover(); r != nil {
 			fmt.Printf("Program panicked: %v\n", r)
+			if reporter != nil {
+				if path := reporter.Recover(r); path != "" {
+					fmt.Printf("Crash report written to %s\n", path)
+				}
+			}
 			os.Exit(1)
 		}
 	}()
 
-	p := tea.NewProgram(initialModel(), tea.WithAltScreen())
+	theme.Init(*plain)
+
+	// Panics in the UI are written to a crash report; Bubble Tea restores
+	// the terminal before Run returns
+	reporter = crash.Wrap(initialModel())
+	p := tea.NewProgram(reporter, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Error running program: %v\n", err)
+		if path := reporter.Path(); path != "" {
+			fmt.Printf("Crash report written to %s, please attach it when reporting the issue\n", path)
+		}
 		os.Exit(1)
 	}
 }